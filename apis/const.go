@@ -3,10 +3,12 @@ package apis
 import "time"
 
 const (
-	CategoriesExtensionKey     = "x-kubernetes-categories"
-	GVKExtensionKey            = "x-kubernetes-group-version-kind"
-	ScopeExtensionKey          = "x-kubernetes-scope"
-	PrinterColumnsExtensionKey = "x-kubernetes-print-columns"
+	// MaturityAnnotation tags a CustomResourceDefinition with the maturity
+	// of its kind: MaturityAlpha, MaturityBeta or MaturityStable.
+	MaturityAnnotation = "gateway.platform-mesh.io/maturity"
+	MaturityAlpha      = "alpha"
+	MaturityBeta       = "beta"
+	MaturityStable     = "stable"
 
 	// Timeout constants for different test scenarios
 	ShortTimeout = 100 * time.Millisecond // Short timeout for quick operations
@@ -57,7 +57,7 @@ type CAConfig struct {
 }
 
 // AuthConfig defines authentication configuration options
-// +kubebuilder:validation:XValidation:rule="(has(self.tokenSecretRef) ? 1 : 0) + (has(self.kubeconfigSecretRef) ? 1 : 0) + (has(self.clientCertificateRef) ? 1 : 0) + (has(self.serviceAccountRef) ? 1 : 0) <= 1",message="only one of tokenSecretRef, kubeconfigSecretRef, clientCertificateRef, or serviceAccountRef can be set"
+// +kubebuilder:validation:XValidation:rule="(has(self.tokenSecretRef) ? 1 : 0) + (has(self.kubeconfigSecretRef) ? 1 : 0) + (has(self.clientCertificateRef) ? 1 : 0) + (has(self.serviceAccountRef) ? 1 : 0) + (has(self.workloadIdentity) ? 1 : 0) <= 1",message="only one of tokenSecretRef, kubeconfigSecretRef, clientCertificateRef, serviceAccountRef, or workloadIdentity can be set"
 type AuthConfig struct {
 	// SecretRef points to a secret containing auth token
 	// +optional
@@ -72,6 +72,36 @@ type AuthConfig struct {
 	// ServiceAccountRef points to a service account for token generation
 	// +optional
 	ServiceAccountRef *ServiceAccountRef `json:"serviceAccountRef,omitempty"`
+	// WorkloadIdentity obtains tokens from the cloud workload identity of the
+	// listener and gateway pods, so no secret is needed for cloud-managed clusters.
+	// +optional
+	WorkloadIdentity *WorkloadIdentityConfig `json:"workloadIdentity,omitempty"`
+}
+
+// WorkloadIdentityProvider is a cloud workload identity mechanism.
+// +kubebuilder:validation:Enum=GCP;AWS;Azure
+type WorkloadIdentityProvider string
+
+const (
+	// WorkloadIdentityProviderGCP uses GKE Workload Identity Federation.
+	WorkloadIdentityProviderGCP WorkloadIdentityProvider = "GCP"
+	// WorkloadIdentityProviderAWS uses EKS IAM roles for service accounts.
+	WorkloadIdentityProviderAWS WorkloadIdentityProvider = "AWS"
+	// WorkloadIdentityProviderAzure uses Azure Workload Identity.
+	WorkloadIdentityProviderAzure WorkloadIdentityProvider = "Azure"
+)
+
+// WorkloadIdentityConfig defines workload identity authentication
+// +kubebuilder:validation:XValidation:rule="self.provider != 'AWS' || has(self.clusterName)",message="clusterName is required for the AWS provider"
+type WorkloadIdentityConfig struct {
+	// Provider is the cloud whose workload identity is used
+	Provider WorkloadIdentityProvider `json:"provider"`
+	// ClusterName is the name of the EKS cluster tokens are issued for. Required for AWS.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+	// Region is the AWS STS region. Defaults to the region of the pods.
+	// +optional
+	Region string `json:"region,omitempty"`
 }
 
 // SecretKeyRef defines a reference to a secret with a specific key.
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/platform-mesh/kubernetes-graphql-gateway/workloadidentity"
+
 	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/rest"
@@ -19,6 +21,9 @@ import (
 type Schema struct {
 	Components      *spec3.Components `json:"components,omitempty"`
 	ClusterMetadata *ClusterMetadata  `json:"x-cluster-metadata,omitempty"`
+	// Version is the version of the schema document contract, empty in
+	// documents written before it was versioned.
+	Version string `json:"x-schema-version,omitempty"`
 }
 
 // ClusterMetadataFunc is a function type that returns ClusterMetadata for a given cluster name
@@ -50,10 +55,11 @@ type ClusterMetadata struct {
 type AuthenticationType string
 
 const (
-	AuthTypeToken          AuthenticationType = "token"
-	AuthTypeKubeconfig     AuthenticationType = "kubeconfig"
-	AuthTypeClientCert     AuthenticationType = "clientCert"
-	AuthTypeServiceAccount AuthenticationType = "serviceAccount"
+	AuthTypeToken            AuthenticationType = "token"
+	AuthTypeKubeconfig       AuthenticationType = "kubeconfig"
+	AuthTypeClientCert       AuthenticationType = "clientCert"
+	AuthTypeServiceAccount   AuthenticationType = "serviceAccount"
+	AuthTypeWorkloadIdentity AuthenticationType = "workloadIdentity"
 )
 
 // AuthMetadata represents authentication information
@@ -67,6 +73,8 @@ type AuthMetadata struct {
 	SAName      string   `json:"saName,omitempty"`
 	SANamespace string   `json:"saNamespace,omitempty"`
 	SAAudience  []string `json:"saAudience,omitempty"`
+	// WorkloadIdentity is resolved by the gateway from its own pod identity
+	WorkloadIdentity *WorkloadIdentityConfig `json:"workloadIdentity,omitempty"`
 }
 
 // CAMetadata represents CA certificate information
@@ -200,6 +208,13 @@ func buildClusterMetadataFromClusterAccess(ctx context.Context, ca ClusterAccess
 			SANamespace: auth.ServiceAccountRef.Namespace,
 			SAAudience:  auth.ServiceAccountRef.Audience,
 		}
+
+	case auth.WorkloadIdentity != nil:
+		// Tokens are obtained from the pod identity where the config is built
+		metadata.Auth = &AuthMetadata{
+			Type:             AuthTypeWorkloadIdentity,
+			WorkloadIdentity: auth.WorkloadIdentity.DeepCopy(),
+		}
 	}
 
 	return metadata, nil
@@ -316,6 +331,19 @@ func buildConfigFromMetadata(metadata ClusterMetadata) (*rest.Config, error) {
 			}
 			config.BearerToken = string(tokenData)
 		}
+	case AuthTypeWorkloadIdentity:
+		if metadata.Auth.WorkloadIdentity == nil {
+			return nil, errors.New("workload identity auth requires workload identity configuration")
+		}
+		ts, err := workloadidentity.NewTokenSource(workloadidentity.Config{
+			Provider:    workloadidentity.Provider(metadata.Auth.WorkloadIdentity.Provider),
+			ClusterName: metadata.Auth.WorkloadIdentity.ClusterName,
+			Region:      metadata.Auth.WorkloadIdentity.Region,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure workload identity: %w", err)
+		}
+		config.Wrap(workloadidentity.WrapTransport(ts))
 	}
 
 	if metadata.Host != "" {
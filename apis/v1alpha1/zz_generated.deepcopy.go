@@ -33,6 +33,11 @@ func (in *AuthConfig) DeepCopyInto(out *AuthConfig) {
 		*out = new(ServiceAccountRef)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.WorkloadIdentity != nil {
+		in, out := &in.WorkloadIdentity, &out.WorkloadIdentity
+		*out = new(WorkloadIdentityConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfig.
@@ -53,6 +58,11 @@ func (in *AuthMetadata) DeepCopyInto(out *AuthMetadata) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.WorkloadIdentity != nil {
+		in, out := &in.WorkloadIdentity, &out.WorkloadIdentity
+		*out = new(WorkloadIdentityConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthMetadata.
@@ -271,3 +281,18 @@ func (in *ServiceAccountRef) DeepCopy() *ServiceAccountRef {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadIdentityConfig) DeepCopyInto(out *WorkloadIdentityConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadIdentityConfig.
+func (in *WorkloadIdentityConfig) DeepCopy() *WorkloadIdentityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadIdentityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
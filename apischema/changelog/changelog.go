@@ -0,0 +1,195 @@
+// Package changelog implements the changelog of a schema file: JSON Patches
+// appended by the listener for each change of a schema, so the gateway can
+// apply them to its copy instead of reading the whole schema again.
+//
+// Every line of a changelog is an Entry. Entries are chained by the hashes of
+// the schemas they apply to and produce, so entries left over from before the
+// schema file was rewritten are skipped.
+package changelog
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// Dir is the directory next to the schema files holding their changelogs,
+// named like the schema files.
+const Dir = ".changelog"
+
+// diffDepth is how deep objects are compared: a change of a definition
+// replaces the whole definition.
+const diffDepth = 2
+
+// Entry is a change of a schema.
+type Entry struct {
+	// From is the Hash of the schema the entry applies to.
+	From string `json:"from"`
+	// To is the Hash of the schema after the entry is applied.
+	To string `json:"to"`
+	// Patch is the JSON Patch changing the schema.
+	Patch json.RawMessage `json:"patch"`
+}
+
+// operation is an operation of a JSON Patch.
+type operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Hash returns a hash of the content of a JSON document, independent of its
+// formatting and the order of its keys.
+func Hash(doc []byte) (string, error) {
+	var v any
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return "", err
+	}
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Diff returns the entry changing the schema from to the schema to. Changed
+// top-level fields and definitions are replaced as a whole.
+func Diff(from, to []byte) (Entry, error) {
+	var fromDoc, toDoc map[string]any
+	if err := json.Unmarshal(from, &fromDoc); err != nil {
+		return Entry{}, fmt.Errorf("failed to parse previous schema: %w", err)
+	}
+	if err := json.Unmarshal(to, &toDoc); err != nil {
+		return Entry{}, fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	ops := []operation{}
+	if err := diff("", fromDoc, toDoc, diffDepth, &ops); err != nil {
+		return Entry{}, err
+	}
+	patch, err := json.Marshal(ops)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry := Entry{Patch: patch}
+	if entry.From, err = Hash(from); err != nil {
+		return Entry{}, err
+	}
+	if entry.To, err = Hash(to); err != nil {
+		return Entry{}, err
+	}
+	return entry, nil
+}
+
+// diff appends the operations changing the object from to the object to at
+// path to ops, comparing nested objects up to depth.
+func diff(path string, from, to map[string]any, depth int, ops *[]operation) error {
+	for _, key := range slices.Sorted(maps.Keys(from)) {
+		if _, ok := to[key]; !ok {
+			*ops = append(*ops, operation{Op: "remove", Path: path + "/" + escape(key)})
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(to)) {
+		keyPath := path + "/" + escape(key)
+		fromValue, exists := from[key]
+		fromObj, fromIsObj := fromValue.(map[string]any)
+		toObj, toIsObj := to[key].(map[string]any)
+
+		var op string
+		switch {
+		case !exists:
+			op = "add"
+		case depth > 1 && fromIsObj && toIsObj:
+			if err := diff(keyPath, fromObj, toObj, depth-1, ops); err != nil {
+				return err
+			}
+			continue
+		case !reflect.DeepEqual(fromValue, to[key]):
+			op = "replace"
+		default:
+			continue
+		}
+
+		value, err := json.Marshal(to[key])
+		if err != nil {
+			return err
+		}
+		*ops = append(*ops, operation{Op: op, Path: keyPath, Value: value})
+	}
+	return nil
+}
+
+// escape escapes a key for a JSON Pointer.
+func escape(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// Empty reports whether the entry changes nothing.
+func (e Entry) Empty() bool {
+	return e.From == e.To
+}
+
+// Apply applies the entry to schema, whose Hash is e.From.
+func (e Entry) Apply(schema []byte) ([]byte, error) {
+	patch, err := jsonpatch.DecodePatch(e.Patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode patch: %w", err)
+	}
+	patched, err := patch.Apply(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply patch: %w", err)
+	}
+	if hash, err := Hash(patched); err != nil || hash != e.To {
+		return nil, fmt.Errorf("patched schema does not match hash %s", e.To)
+	}
+	return patched, nil
+}
+
+// Replay applies the entries of log following the schema with the given
+// Hash. Entries not continuing the chain are skipped, and replaying stops at
+// an incomplete last line. It returns the schema and its hash after the last
+// applied entry.
+func Replay(schema []byte, hash string, log []byte) ([]byte, string, error) {
+	for line := range bytes.Lines(log) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			// The listener may still be appending the line.
+			break
+		}
+		if entry.From != hash || entry.Empty() {
+			continue
+		}
+
+		patched, err := entry.Apply(schema)
+		if err != nil {
+			return nil, "", err
+		}
+		schema, hash = patched, entry.To
+	}
+	return schema, hash, nil
+}
+
+// Marshal returns the changelog line of the entry.
+func (e Entry) Marshal() ([]byte, error) {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
@@ -0,0 +1,71 @@
+package changelog_test
+
+import (
+	"testing"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/changelog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	v1 = `{"definitions":{"io.k8s.api.core.v1.Pod":{"type":"object"},"com.example.v1.Widget":{"type":"object"}},"x-cluster-metadata":{"host":"a"}}`
+	v2 = `{"definitions":{"io.k8s.api.core.v1.Pod":{"type":"object"},"com.example.v1.Gadget":{"type":"object"}},"x-cluster-metadata":{"host":"b"}}`
+	v3 = `{"definitions":{"io.k8s.api.core.v1.Pod":{"type":"object","description":"Pod"}},"x-cluster-metadata":{"host":"b"}}`
+)
+
+func TestDiff(t *testing.T) {
+	entry, err := changelog.Diff([]byte(v1), []byte(v2))
+	require.NoError(t, err)
+
+	// Definitions are patched one by one.
+	assert.JSONEq(t, `[
+		{"op":"remove","path":"/definitions/com.example.v1.Widget"},
+		{"op":"add","path":"/definitions/com.example.v1.Gadget","value":{"type":"object"}},
+		{"op":"replace","path":"/x-cluster-metadata/host","value":"b"}
+	]`, string(entry.Patch))
+
+	patched, err := entry.Apply([]byte(v1))
+	require.NoError(t, err)
+	assert.JSONEq(t, v2, string(patched))
+
+	// Formatting and key order are no changes.
+	entry, err = changelog.Diff([]byte(v1), []byte(`{"x-cluster-metadata":{"host":"a"},  "definitions":{"com.example.v1.Widget":{"type":"object"},"io.k8s.api.core.v1.Pod":{"type":"object"}}}`))
+	require.NoError(t, err)
+	assert.True(t, entry.Empty())
+}
+
+func TestReplay(t *testing.T) {
+	first, err := changelog.Diff([]byte(v1), []byte(v2))
+	require.NoError(t, err)
+	second, err := changelog.Diff([]byte(v2), []byte(v3))
+	require.NoError(t, err)
+	stale, err := changelog.Diff([]byte(v3), []byte(v1))
+	require.NoError(t, err)
+	stale.From = "rewritten"
+
+	var log []byte
+	for _, entry := range []changelog.Entry{first, second, stale} {
+		line, err := entry.Marshal()
+		require.NoError(t, err)
+		log = append(log, line...)
+	}
+
+	hash, err := changelog.Hash([]byte(v1))
+	require.NoError(t, err)
+	schema, got, err := changelog.Replay([]byte(v1), hash, log)
+	require.NoError(t, err)
+	assert.JSONEq(t, v3, string(schema))
+	assert.Equal(t, second.To, got)
+
+	// Replaying from the second schema only applies the second entry.
+	schema, _, err = changelog.Replay([]byte(v2), first.To, log)
+	require.NoError(t, err)
+	assert.JSONEq(t, v3, string(schema))
+
+	// An incomplete last line is left for the next replay.
+	schema, got, err = changelog.Replay([]byte(v1), hash, log[:len(log)/2])
+	require.NoError(t, err)
+	assert.JSONEq(t, v2, string(schema))
+	assert.Equal(t, first.To, got)
+}
@@ -0,0 +1,130 @@
+// Package contract defines the schema document contract between the listener,
+// which writes schema documents, and the gateway, which reads them.
+//
+// The listener tests generate a document from OpenAPIV3 and Discovery and
+// compare it with Golden; the gateway tests build a GraphQL schema from
+// Golden. Both run AssertDocument, so a change to an extension (GVK, scope,
+// categories, subresources, cluster metadata or version) fails on whichever side does
+// not follow it. Regenerate Golden with
+//
+//	go test ./listener/controllers/reconciler/ -run TestContract -update
+package contract
+
+import (
+	_ "embed"
+	"encoding/json"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// OpenAPIV3Path is the path OpenAPIV3 is served at.
+const OpenAPIV3Path = "apis/example.com/v1"
+
+// GoldenFile is the location of Golden relative to this package.
+const GoldenFile = "testdata/schema.golden.json"
+
+// OpenAPIV3 is the example.com/v1 OpenAPI v3 document as served by the API
+// server.
+//
+//go:embed testdata/openapi.json
+var OpenAPIV3 []byte
+
+// Golden is the schema document the listener writes for OpenAPIV3,
+// Discovery and Metadata.
+//
+//go:embed testdata/schema.golden.json
+var Golden []byte
+
+// Discovery is the API server's discovery of preferred resources.
+// aggregated.example.com has no OpenAPI document, so Thing is stubbed.
+var Discovery = []*metav1.APIResourceList{
+	{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "widgets", Kind: "Widget", Namespaced: true, Categories: []string{"all", "example"}, Verbs: []string{"get", "list", "watch"}},
+			{Name: "gadgets", Kind: "Gadget", Namespaced: false, Verbs: []string{"get", "list", "watch"}},
+		},
+	},
+	{
+		GroupVersion: "aggregated.example.com/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "things", Kind: "Thing", Namespaced: true, Verbs: []string{"get", "list"}},
+		},
+	},
+}
+
+// Metadata is the cluster connection metadata injected into the document.
+var Metadata = v1alpha1.ClusterMetadata{
+	Host: "https://api.example.com:6443",
+	Path: "clusters/root",
+	Auth: &v1alpha1.AuthMetadata{
+		Type:  v1alpha1.AuthTypeToken,
+		Token: "dG9rZW4=",
+	},
+	CA: &v1alpha1.CAMetadata{
+		Data: "Y2E=",
+	},
+}
+
+// Resource describes a resource the document must expose to the gateway.
+type Resource struct {
+	Key          string
+	GVK          schema.GroupVersionKind
+	Scope        apiextensionsv1.ResourceScope
+	Categories   []string
+	Subresources []string
+}
+
+// Resources are the resources Golden describes.
+var Resources = []Resource{
+	{
+		Key:          "com.example.v1.Widget",
+		GVK:          schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"},
+		Scope:        apiextensionsv1.NamespaceScoped,
+		Categories:   []string{"all", "example"},
+		Subresources: []string{"scale", "status"},
+	},
+	{
+		Key:   "com.example.v1.Gadget",
+		GVK:   schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Gadget"},
+		Scope: apiextensionsv1.ClusterScoped,
+	},
+	{
+		Key:   "aggregated.example.com.v1.Thing",
+		GVK:   schema.GroupVersionKind{Group: "aggregated.example.com", Version: "v1", Kind: "Thing"},
+		Scope: apiextensionsv1.NamespaceScoped,
+	},
+}
+
+// AssertDocument verifies that a schema document, decoded the way the gateway
+// decodes it, carries Metadata and the extensions of all Resources.
+func AssertDocument(t require.TestingT, document []byte) {
+	var doc v1alpha1.Schema
+	require.NoError(t, json.Unmarshal(document, &doc), "document must decode as v1alpha1.Schema")
+	require.NotNil(t, doc.Components, "document must have components")
+	require.Equal(t, &Metadata, doc.ClusterMetadata, extensions.ClusterMetadataKey)
+	require.Equal(t, extensions.Version, doc.Version, extensions.VersionKey)
+	require.NoError(t, extensions.ValidateDocument(&doc))
+
+	for _, r := range Resources {
+		s, ok := doc.Components.Schemas[r.Key]
+		require.True(t, ok, "schema %s must be present", r.Key)
+
+		gvk, err := extensions.GVK(s)
+		require.NoError(t, err, "%s: %s", r.Key, extensions.GVKKey)
+		require.Equal(t, &r.GVK, gvk, "%s: %s", r.Key, extensions.GVKKey)
+
+		scope, err := extensions.Scope(s)
+		require.NoError(t, err, "%s: %s", r.Key, extensions.ScopeKey)
+		require.Equal(t, r.Scope, scope, "%s: %s", r.Key, extensions.ScopeKey)
+
+		require.Equal(t, r.Categories, extensions.Categories(s), "%s: %s", r.Key, extensions.CategoriesKey)
+		require.Equal(t, r.Subresources, extensions.Subresources(s), "%s: %s", r.Key, extensions.SubresourcesKey)
+	}
+}
@@ -0,0 +1,62 @@
+package extensions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
+)
+
+// ErrUnsupportedVersion indicates a document follows a version of the
+// contract the gateway does not know.
+var ErrUnsupportedVersion = errors.New("unsupported schema document version")
+
+// WithClusterMetadata returns document with the cluster connection metadata
+// and the Version of the contract set.
+func WithClusterMetadata(document []byte, metadata *v1alpha1.ClusterMetadata) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse schema JSON: %w", err)
+	}
+	doc[ClusterMetadataKey] = metadata
+	doc[VersionKey] = Version
+	return json.Marshal(doc)
+}
+
+// CheckVersion returns ErrUnsupportedVersion unless version is Version or
+// empty, as in documents written before the contract was versioned.
+func CheckVersion(version string) error {
+	if version != "" && version != Version {
+		return fmt.Errorf("%w %q, expected %q", ErrUnsupportedVersion, version, Version)
+	}
+	return nil
+}
+
+// ValidateDocument checks that a decoded schema document follows the
+// contract: a supported version, cluster metadata with a host and
+// well-formed extensions on all definitions.
+func ValidateDocument(doc *v1alpha1.Schema) error {
+	if err := CheckVersion(doc.Version); err != nil {
+		return err
+	}
+	if doc.ClusterMetadata == nil {
+		return fmt.Errorf("%s is missing", ClusterMetadataKey)
+	}
+	if doc.ClusterMetadata.Host == "" {
+		return fmt.Errorf("%s: host is missing", ClusterMetadataKey)
+	}
+	if doc.Components == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, key := range slices.Sorted(maps.Keys(doc.Components.Schemas)) {
+		if err := Validate(doc.Components.Schemas[key]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
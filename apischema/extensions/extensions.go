@@ -0,0 +1,387 @@
+// Package extensions defines the OpenAPI extensions of the schema documents
+// the listener writes and the gateway reads. Tools generating schema documents
+// for the gateway can use it to write documents the gateway serves.
+//
+// A schema document is an OpenAPI v3 document with the cluster connection
+// metadata under ClusterMetadataKey and the Version of this contract under
+// VersionKey. The definitions of its resources carry the extensions below,
+// which are read and written with the functions of this package. Documents
+// without a version were written before the contract was versioned and follow
+// Version "v1".
+package extensions
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apis"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const (
+	// Version is the version of the contract. It changes when an extension is
+	// changed in a way older gateways cannot read.
+	Version = "v1"
+
+	// VersionKey holds the Version a document follows.
+	VersionKey = "x-schema-version"
+	// ClusterMetadataKey holds the v1alpha1.ClusterMetadata of the cluster a
+	// document describes.
+	ClusterMetadataKey = "x-cluster-metadata"
+
+	// GVKKey holds the group, version and kind of a resource as a list with
+	// one object with the properties group, version and kind.
+	GVKKey = "x-kubernetes-group-version-kind"
+	// ScopeKey holds the scope of a resource, Namespaced or Cluster.
+	ScopeKey = "x-kubernetes-scope"
+	// CategoriesKey holds the categories of a resource, e.g. "all".
+	CategoriesKey = "x-kubernetes-categories"
+	// PrinterColumnsKey holds the additional printer columns of a custom
+	// resource.
+	PrinterColumnsKey = "x-kubernetes-print-columns"
+	// IntOrStringKey marks a property holding an integer or a string.
+	IntOrStringKey = "x-kubernetes-int-or-string"
+	// DeniedVerbsKey holds the verbs the listener's credentials were denied
+	// on a resource.
+	DeniedVerbsKey = "x-kubernetes-denied-verbs"
+	// SubresourcesKey holds the subresources served for a resource, e.g.
+	// "status" or "scale".
+	SubresourcesKey = "x-kubernetes-subresources"
+	// MaturityKey holds the maturity of a custom resource: apis.MaturityAlpha,
+	// apis.MaturityBeta or apis.MaturityStable.
+	MaturityKey = "x-kubernetes-maturity"
+)
+
+var (
+	// ErrInvalidGVKFormat indicates the GVKKey extension has an unexpected format.
+	ErrInvalidGVKFormat = errors.New("invalid GVK extension format")
+
+	// ErrScopeNotFound indicates the ScopeKey extension is missing.
+	ErrScopeNotFound = errors.New("scope extension not found")
+
+	// ErrInvalidScopeFormat indicates the scope extension has an unexpected format.
+	ErrInvalidScopeFormat = errors.New("invalid scope extension format")
+)
+
+// GVK returns the group, version and kind of a resource. It returns nil if
+// the schema has no or several GVKs, e.g. for sub-resources.
+func GVK(s *spec.Schema) (*schema.GroupVersionKind, error) {
+	if s == nil || s.Extensions == nil {
+		return nil, nil
+	}
+
+	gvksVal, ok := s.Extensions[GVKKey]
+	if !ok {
+		return nil, nil
+	}
+
+	// The GVKs are maps after a JSON round trip and typed when set in-process.
+	switch v := gvksVal.(type) {
+	case []any:
+		if len(v) != 1 {
+			return nil, nil
+		}
+		gvkMap, ok := v[0].(map[string]any)
+		if !ok {
+			return nil, ErrInvalidGVKFormat
+		}
+		return gvkFromMap(gvkMap), nil
+	case []map[string]any:
+		if len(v) != 1 {
+			return nil, nil
+		}
+		return gvkFromMap(v[0]), nil
+	default:
+		return nil, ErrInvalidGVKFormat
+	}
+}
+
+// SetGVK sets the group, version and kind of a resource.
+func SetGVK(s *spec.Schema, gvk schema.GroupVersionKind) {
+	s.AddExtension(GVKKey, []map[string]any{
+		{"group": gvk.Group, "version": gvk.Version, "kind": gvk.Kind},
+	})
+}
+
+// gvkFromMap extracts a GroupVersionKind from a map with group/version/kind keys.
+func gvkFromMap(m map[string]any) *schema.GroupVersionKind {
+	return &schema.GroupVersionKind{
+		Group:   mapValue[string](m, "group"),
+		Version: mapValue[string](m, "version"),
+		Kind:    mapValue[string](m, "kind"),
+	}
+}
+
+// mapValue extracts a typed value from a map, returning the zero value if not found or wrong type.
+func mapValue[T any](m map[string]any, key string) T {
+	var zero T
+	if v, ok := m[key]; ok {
+		if typed, ok := v.(T); ok {
+			return typed
+		}
+	}
+	return zero
+}
+
+// Scope returns the scope of a resource, Namespaced or Cluster.
+func Scope(s *spec.Schema) (apiextensionsv1.ResourceScope, error) {
+	if s == nil || s.Extensions == nil {
+		return "", ErrScopeNotFound
+	}
+
+	scopeRaw, ok := s.Extensions[ScopeKey]
+	if !ok {
+		return "", ErrScopeNotFound
+	}
+
+	// Handle both string and ResourceScope types
+	switch v := scopeRaw.(type) {
+	case string:
+		return apiextensionsv1.ResourceScope(v), nil
+	case apiextensionsv1.ResourceScope:
+		return v, nil
+	default:
+		return "", ErrInvalidScopeFormat
+	}
+}
+
+// SetScope sets the scope of a resource.
+func SetScope(s *spec.Schema, scope apiextensionsv1.ResourceScope) {
+	s.AddExtension(ScopeKey, scope)
+}
+
+// Categories returns the categories of a resource, or nil if it has none.
+func Categories(s *spec.Schema) []string {
+	return stringsValue(s, CategoriesKey)
+}
+
+// SetCategories sets the categories of a resource.
+func SetCategories(s *spec.Schema, categories []string) {
+	s.AddExtension(CategoriesKey, categories)
+}
+
+// DeniedVerbs returns the verbs the listener's credentials were denied on a
+// resource, or nil if none were recorded.
+func DeniedVerbs(s *spec.Schema) []string {
+	return stringsValue(s, DeniedVerbsKey)
+}
+
+// SetDeniedVerbs sets the verbs the listener's credentials were denied on a
+// resource.
+func SetDeniedVerbs(s *spec.Schema, verbs []string) {
+	s.AddExtension(DeniedVerbsKey, verbs)
+}
+
+// Subresources returns the subresources the API server serves for a
+// resource, or nil if none were recorded.
+func Subresources(s *spec.Schema) []string {
+	return stringsValue(s, SubresourcesKey)
+}
+
+// SetSubresources sets the subresources the API server serves for a resource.
+func SetSubresources(s *spec.Schema, subresources []string) {
+	s.AddExtension(SubresourcesKey, subresources)
+}
+
+// Maturity returns the maturity a custom resource is tagged with, or "" if it
+// is not tagged.
+func Maturity(s *spec.Schema) string {
+	if s == nil || s.Extensions == nil {
+		return ""
+	}
+	maturity, _ := s.Extensions[MaturityKey].(string)
+	return maturity
+}
+
+// SetMaturity sets the maturity of a custom resource.
+func SetMaturity(s *spec.Schema, maturity string) {
+	s.AddExtension(MaturityKey, maturity)
+}
+
+// IntOrString reports whether a property holds an integer or a string.
+func IntOrString(s *spec.Schema) bool {
+	if s == nil {
+		return false
+	}
+	intOrString, ok := s.Extensions.GetBool(IntOrStringKey)
+	return ok && intOrString
+}
+
+// PrinterColumns returns the additional printer columns of a custom resource,
+// or nil if none were recorded.
+func PrinterColumns(s *spec.Schema) []apiextensionsv1.CustomResourceColumnDefinition {
+	if s == nil || s.Extensions == nil {
+		return nil
+	}
+
+	// The columns are typed when set in-process and maps after a JSON round trip.
+	switch v := s.Extensions[PrinterColumnsKey].(type) {
+	case []apiextensionsv1.CustomResourceColumnDefinition:
+		return v
+	case []any:
+		columns := make([]apiextensionsv1.CustomResourceColumnDefinition, 0, len(v))
+		for _, value := range v {
+			m, ok := value.(map[string]any)
+			if !ok {
+				continue
+			}
+			columns = append(columns, apiextensionsv1.CustomResourceColumnDefinition{
+				Name:        mapValue[string](m, "name"),
+				Type:        mapValue[string](m, "type"),
+				Format:      mapValue[string](m, "format"),
+				Description: mapValue[string](m, "description"),
+				Priority:    int32(mapValue[float64](m, "priority")),
+				JSONPath:    mapValue[string](m, "jsonPath"),
+			})
+		}
+		return columns
+	default:
+		return nil
+	}
+}
+
+// SetPrinterColumns sets the additional printer columns of a custom resource.
+func SetPrinterColumns(s *spec.Schema, columns []apiextensionsv1.CustomResourceColumnDefinition) {
+	s.AddExtension(PrinterColumnsKey, columns)
+}
+
+// stringsValue returns a string list extension, which is []string when set
+// in-process and []any after a JSON round trip.
+func stringsValue(s *spec.Schema, key string) []string {
+	if s == nil || s.Extensions == nil {
+		return nil
+	}
+
+	switch v := s.Extensions[key].(type) {
+	case []string:
+		return v
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, value := range v {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// Validate checks that the extensions of a definition are well-formed.
+// Extensions the definition does not have are not checked.
+func Validate(s *spec.Schema) error {
+	if s == nil || s.Extensions == nil {
+		return nil
+	}
+
+	var errs []error
+	if gvks, ok := s.Extensions[GVKKey]; ok {
+		if err := validateGVKs(gvks); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if _, ok := s.Extensions[ScopeKey]; ok {
+		scope, err := Scope(s)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", ScopeKey, err))
+		case scope != apiextensionsv1.NamespaceScoped && scope != apiextensionsv1.ClusterScoped:
+			errs = append(errs, fmt.Errorf("%s: unknown scope %q", ScopeKey, scope))
+		}
+	}
+	for _, key := range []string{CategoriesKey, DeniedVerbsKey, SubresourcesKey} {
+		if value, ok := s.Extensions[key]; ok && !isStrings(value) {
+			errs = append(errs, fmt.Errorf("%s: must be a list of strings", key))
+		}
+	}
+	if value, ok := s.Extensions[MaturityKey]; ok {
+		switch value {
+		case apis.MaturityAlpha, apis.MaturityBeta, apis.MaturityStable:
+		default:
+			errs = append(errs, fmt.Errorf("%s: unknown maturity %v", MaturityKey, value))
+		}
+	}
+	if value, ok := s.Extensions[IntOrStringKey]; ok {
+		if _, ok := value.(bool); !ok {
+			errs = append(errs, fmt.Errorf("%s: must be a boolean", IntOrStringKey))
+		}
+	}
+	if value, ok := s.Extensions[PrinterColumnsKey]; ok {
+		if err := validatePrinterColumns(value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateGVKs checks that value is a list of objects with a string kind and
+// version and an optional string group.
+func validateGVKs(value any) error {
+	var gvks []map[string]any
+	switch v := value.(type) {
+	case []map[string]any:
+		gvks = v
+	case []any:
+		for _, item := range v {
+			m, ok := item.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%s: %w", GVKKey, ErrInvalidGVKFormat)
+			}
+			gvks = append(gvks, m)
+		}
+	default:
+		return fmt.Errorf("%s: %w", GVKKey, ErrInvalidGVKFormat)
+	}
+
+	for _, gvk := range gvks {
+		for _, key := range []string{"group", "version", "kind"} {
+			value, ok := gvk[key]
+			if !ok && key == "group" {
+				continue
+			}
+			if s, isString := value.(string); !isString || (s == "" && key != "group") {
+				return fmt.Errorf("%s: %s must be a non-empty string", GVKKey, key)
+			}
+		}
+	}
+	return nil
+}
+
+// validatePrinterColumns checks that value is a list of columns with a name
+// and a JSON path.
+func validatePrinterColumns(value any) error {
+	switch v := value.(type) {
+	case []apiextensionsv1.CustomResourceColumnDefinition:
+		return nil
+	case []any:
+		for _, item := range v {
+			m, ok := item.(map[string]any)
+			if !ok || mapValue[string](m, "name") == "" || mapValue[string](m, "jsonPath") == "" {
+				return fmt.Errorf("%s: columns must have a name and a jsonPath", PrinterColumnsKey)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%s: must be a list of columns", PrinterColumnsKey)
+	}
+}
+
+func isStrings(value any) bool {
+	switch v := value.(type) {
+	case []string:
+		return true
+	case []any:
+		for _, item := range v {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
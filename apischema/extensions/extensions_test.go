@@ -0,0 +1,146 @@
+package extensions
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// TestRoundTrip verifies that extensions set in-process read the same after a
+// JSON round trip, as the gateway reads them from schema files.
+func TestRoundTrip(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	columns := []apiextensionsv1.CustomResourceColumnDefinition{{Name: "Ready", Type: "string", JSONPath: ".status.ready", Priority: 1}}
+
+	s := &spec.Schema{}
+	SetGVK(s, gvk)
+	SetScope(s, apiextensionsv1.NamespaceScoped)
+	SetCategories(s, []string{"all"})
+	SetDeniedVerbs(s, []string{"delete"})
+	SetSubresources(s, []string{"status"})
+	SetMaturity(s, "beta")
+	SetPrinterColumns(s, columns)
+
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	var decoded spec.Schema
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	for name, s := range map[string]*spec.Schema{"in-process": s, "decoded": &decoded} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, Validate(s))
+
+			got, err := GVK(s)
+			require.NoError(t, err)
+			assert.Equal(t, &gvk, got)
+			scope, err := Scope(s)
+			require.NoError(t, err)
+			assert.Equal(t, apiextensionsv1.NamespaceScoped, scope)
+			assert.Equal(t, []string{"all"}, Categories(s))
+			assert.Equal(t, []string{"delete"}, DeniedVerbs(s))
+			assert.Equal(t, []string{"status"}, Subresources(s))
+			assert.Equal(t, "beta", Maturity(s))
+			assert.Equal(t, columns, PrinterColumns(s))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		extensions spec.Extensions
+		wantErr    string
+	}{
+		{name: "none"},
+		{
+			name:       "core group",
+			extensions: spec.Extensions{GVKKey: []any{map[string]any{"group": "", "version": "v1", "kind": "Pod"}}},
+		},
+		{
+			name:       "several GVKs",
+			extensions: spec.Extensions{GVKKey: []any{map[string]any{"version": "v1", "kind": "DeleteOptions"}, map[string]any{"group": "apps", "version": "v1", "kind": "DeleteOptions"}}},
+		},
+		{
+			name:       "GVK without kind",
+			extensions: spec.Extensions{GVKKey: []any{map[string]any{"group": "apps", "version": "v1"}}},
+			wantErr:    GVKKey + ": kind must be a non-empty string",
+		},
+		{
+			name:       "GVK object",
+			extensions: spec.Extensions{GVKKey: map[string]any{"version": "v1", "kind": "Pod"}},
+			wantErr:    ErrInvalidGVKFormat.Error(),
+		},
+		{
+			name:       "unknown scope",
+			extensions: spec.Extensions{ScopeKey: "Namespace"},
+			wantErr:    `unknown scope "Namespace"`,
+		},
+		{
+			name:       "categories",
+			extensions: spec.Extensions{CategoriesKey: []any{"all", 1.0}},
+			wantErr:    CategoriesKey + ": must be a list of strings",
+		},
+		{
+			name:       "maturity",
+			extensions: spec.Extensions{MaturityKey: "gamma"},
+			wantErr:    "unknown maturity gamma",
+		},
+		{
+			name:       "int or string",
+			extensions: spec.Extensions{IntOrStringKey: "true"},
+			wantErr:    IntOrStringKey + ": must be a boolean",
+		},
+		{
+			name:       "printer columns",
+			extensions: spec.Extensions{PrinterColumnsKey: []any{map[string]any{"name": "Ready"}}},
+			wantErr:    "columns must have a name and a jsonPath",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&spec.Schema{VendorExtensible: spec.VendorExtensible{Extensions: tt.extensions}})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDocument(t *testing.T) {
+	metadata := &v1alpha1.ClusterMetadata{Host: "https://api.example.com:6443"}
+	document, err := WithClusterMetadata([]byte(`{"components":{"schemas":{"a":{"x-kubernetes-scope":"Cluster"}}}}`), metadata)
+	require.NoError(t, err)
+
+	var doc v1alpha1.Schema
+	require.NoError(t, json.Unmarshal(document, &doc))
+	assert.Equal(t, Version, doc.Version)
+	assert.Equal(t, metadata, doc.ClusterMetadata)
+	require.NoError(t, ValidateDocument(&doc))
+
+	// Documents written before the contract was versioned are supported.
+	doc.Version = ""
+	require.NoError(t, ValidateDocument(&doc))
+
+	doc.Version = "v2"
+	assert.ErrorIs(t, ValidateDocument(&doc), ErrUnsupportedVersion)
+
+	doc.Version = Version
+	doc.Components = &spec3.Components{Schemas: map[string]*spec.Schema{
+		"a": {VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{ScopeKey: 1.0}}},
+	}}
+	assert.ErrorContains(t, ValidateDocument(&doc), "a: "+ScopeKey)
+
+	doc.ClusterMetadata = nil
+	assert.ErrorContains(t, ValidateDocument(&doc), ClusterMetadataKey+" is missing")
+}
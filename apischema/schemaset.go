@@ -1,6 +1,8 @@
 package apischema
 
 import (
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+
 	"encoding/json"
 	"maps"
 	"strings"
@@ -18,7 +20,7 @@ type SchemaEntry struct {
 	GVK    *schema.GroupVersionKind
 }
 
-// SchemaSet is an immutable collection of schemas
+// SchemaSet is a collection of schemas indexed by key, kind and GVK.
 type SchemaSet struct {
 	entries map[string]*SchemaEntry
 	byKind  map[string][]*SchemaEntry
@@ -34,19 +36,35 @@ func NewSchemaSet(entries map[string]*SchemaEntry) *SchemaSet {
 	}
 
 	for _, entry := range entries {
-		if entry.GVK == nil {
-			continue
-		}
+		s.index(entry)
+	}
 
-		// Index by lowercase kind for O(1) lookup
-		kindKey := strings.ToLower(entry.GVK.Kind)
-		s.byKind[kindKey] = append(s.byKind[kindKey], entry)
+	return s
+}
 
-		// Index by exact GVK
-		s.byGVK[*entry.GVK] = entry
+// Add inserts an entry into the set. Existing entries with the same key are
+// left unchanged; Add reports whether the entry was added.
+func (s *SchemaSet) Add(entry *SchemaEntry) bool {
+	if _, exists := s.entries[entry.Key]; exists {
+		return false
 	}
 
-	return s
+	s.entries[entry.Key] = entry
+	s.index(entry)
+	return true
+}
+
+func (s *SchemaSet) index(entry *SchemaEntry) {
+	if entry.GVK == nil {
+		return
+	}
+
+	// Index by lowercase kind for O(1) lookup
+	kindKey := strings.ToLower(entry.GVK.Kind)
+	s.byKind[kindKey] = append(s.byKind[kindKey], entry)
+
+	// Index by exact GVK
+	s.byGVK[*entry.GVK] = entry
 }
 
 // Get returns a schema entry by its key.
@@ -95,7 +113,7 @@ func (s *SchemaSet) Marshal() ([]byte, error) {
 func NewSchemaSetFromMap(schemas map[string]*spec.Schema) *SchemaSet {
 	entries := make(map[string]*SchemaEntry, len(schemas))
 	for k, v := range schemas {
-		gvk, _ := ExtractGVK(v)
+		gvk, _ := extensions.GVK(v)
 		entries[k] = &SchemaEntry{
 			Key:    k,
 			Schema: v,
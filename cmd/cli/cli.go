@@ -0,0 +1,125 @@
+// Package cli assembles the kubernetes-graphql-gateway command line.
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/cmd/gateway"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/cmd/listener"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/cmd/loadgen"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/cmd/metrics"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/cmd/schema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/cmd/version"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/yaml"
+)
+
+// EnvPrefix prefixes the environment variables setting flags, e.g.
+// KGW_GATEWAY_PORT for --gateway-port.
+const EnvPrefix = "KGW_"
+
+// ConfigFlag is the flag naming a YAML file with flag values.
+const ConfigFlag = "config"
+
+// NewRootCommand returns the root command with all subcommands.
+//
+// Flags of every command can also be set by environment variables and by a
+// config file mapping flag names to values. Flags given on the command line
+// take precedence over environment variables, which take precedence over the
+// config file.
+func NewRootCommand() *cobra.Command {
+	var configFile string
+
+	cmd := &cobra.Command{
+		Use:     "kubernetes-graphql-gateway",
+		Short:   "Kubernetes GraphQL Gateway",
+		Version: version.String(),
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			return applyFlagSources(cmd.Flags(), os.LookupEnv, configFile)
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&configFile, ConfigFlag, "", "YAML file mapping flag names to values")
+
+	cmd.AddCommand(
+		gateway.NewCommand(),
+		listener.NewCommand(),
+		loadgen.NewCommand(),
+		metrics.NewCommand(),
+		schema.NewCommand(),
+		version.NewCommand(),
+	)
+	return cmd
+}
+
+// applyFlagSources sets the flags not given on the command line from the
+// environment and then from the config file.
+func applyFlagSources(fs *pflag.FlagSet, lookupEnv func(string) (string, bool), configFile string) error {
+	config := map[string]any{}
+	if configFile != "" {
+		data, err := os.ReadFile(configFile)
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("failed to parse config file %s: %w", configFile, err)
+		}
+	}
+
+	var errs []error
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Changed || f.Name == ConfigFlag {
+			return
+		}
+
+		if value, ok := lookupEnv(EnvVar(f.Name)); ok {
+			if err := fs.Set(f.Name, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", EnvVar(f.Name), err))
+			}
+			return
+		}
+
+		if value, ok := config[f.Name]; ok {
+			if err := setFromConfig(fs, f, value); err != nil {
+				errs = append(errs, fmt.Errorf("config file %s: %w", configFile, err))
+			}
+		}
+	})
+	if len(errs) > 0 {
+		return errs[0]
+	}
+
+	for name := range config {
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("unknown flag %q in config file", name)
+		}
+	}
+	return nil
+}
+
+// setFromConfig sets a flag from a config file value. Lists set slice flags
+// element by element.
+func setFromConfig(fs *pflag.FlagSet, f *pflag.Flag, value any) error {
+	values, isList := value.([]any)
+	if !isList {
+		return fs.Set(f.Name, fmt.Sprint(value))
+	}
+
+	items := make([]string, 0, len(values))
+	for _, item := range values {
+		items = append(items, fmt.Sprint(item))
+	}
+	if slice, ok := f.Value.(pflag.SliceValue); ok {
+		return slice.Replace(items)
+	}
+	return fs.Set(f.Name, strings.Join(items, ","))
+}
+
+// EnvVar returns the environment variable setting a flag.
+func EnvVar(flag string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(flag))
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyFlagSources(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+gateway-port: 9090
+gateway-address: 127.0.0.1
+cors-allowed-origins: [a.example.com, b.example.com]
+enable-playground: true
+`), 0o600))
+
+	tests := []struct {
+		name        string
+		args        []string
+		env         map[string]string
+		configFile  string
+		wantPort    int
+		wantAddress string
+		wantOrigins []string
+		wantErr     string
+	}{
+		{
+			name:        "defaults",
+			wantPort:    8080,
+			wantAddress: "0.0.0.0",
+		},
+		{
+			name:        "config file",
+			configFile:  configFile,
+			wantPort:    9090,
+			wantAddress: "127.0.0.1",
+			wantOrigins: []string{"a.example.com", "b.example.com"},
+		},
+		{
+			name:        "environment overrides config file",
+			env:         map[string]string{"KGW_GATEWAY_PORT": "7070"},
+			configFile:  configFile,
+			wantPort:    7070,
+			wantAddress: "127.0.0.1",
+			wantOrigins: []string{"a.example.com", "b.example.com"},
+		},
+		{
+			name:        "command line overrides environment",
+			args:        []string{"--gateway-port=6060"},
+			env:         map[string]string{"KGW_GATEWAY_PORT": "7070", "KGW_CORS_ALLOWED_ORIGINS": "c.example.com"},
+			wantPort:    6060,
+			wantAddress: "0.0.0.0",
+			wantOrigins: []string{"c.example.com"},
+		},
+		{
+			name:    "invalid environment value",
+			env:     map[string]string{"KGW_GATEWAY_PORT": "eighty"},
+			wantErr: `KGW_GATEWAY_PORT: invalid argument "eighty" for "--gateway-port" flag: strconv.ParseInt: parsing "eighty": invalid syntax`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				port       int
+				address    string
+				origins    []string
+				playground bool
+			)
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			fs.IntVar(&port, "gateway-port", 8080, "")
+			fs.StringVar(&address, "gateway-address", "0.0.0.0", "")
+			fs.StringSliceVar(&origins, "cors-allowed-origins", nil, "")
+			fs.BoolVar(&playground, "enable-playground", false, "")
+			require.NoError(t, fs.Parse(tt.args))
+
+			lookupEnv := func(key string) (string, bool) {
+				value, ok := tt.env[key]
+				return value, ok
+			}
+
+			err := applyFlagSources(fs, lookupEnv, tt.configFile)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantPort, port)
+			assert.Equal(t, tt.wantAddress, address)
+			assert.Equal(t, tt.wantOrigins, origins)
+			assert.Equal(t, tt.configFile != "", playground)
+		})
+	}
+}
+
+func TestApplyFlagSources_UnknownConfigKey(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("gateway-prot: 9090\n"), 0o600))
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.Int("gateway-port", 8080, "")
+
+	err := applyFlagSources(fs, func(string) (string, bool) { return "", false }, configFile)
+	assert.EqualError(t, err, `unknown flag "gateway-prot" in config file`)
+}
+
+func TestEnvVar(t *testing.T) {
+	assert.Equal(t, "KGW_GATEWAY_PORT", EnvVar("gateway-port"))
+	assert.Equal(t, "KGW_LOG_FLUSH_FREQUENCY", EnvVar("log-flush-frequency"))
+}
@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"errors"
+	"time"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/doctor"
+	"github.com/spf13/cobra"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type doctorCommand struct {
+	config doctor.Config
+}
+
+func newDoctorCommand() *cobra.Command {
+	c := &doctorCommand{
+		config: doctor.Config{
+			SchemasDir:    "_output/schemas",
+			ProbeResource: "namespaces",
+			Timeout:       10 * time.Second,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check a gateway deployment for common misconfigurations",
+		Long: `Runs end-to-end diagnostics against the gateway's environment: the schema
+directory is readable, every schema parses and generates a GraphQL schema,
+each target cluster is reachable with its stored credentials, an optional
+probe identity is allowed to list and watch, and watches can be established.`,
+		RunE: c.run,
+	}
+
+	fs := cmd.Flags()
+	fs.StringVar(&c.config.SchemasDir, "schemas-dir", c.config.SchemasDir, "directory containing the schema files to check")
+	fs.StringVar(&c.config.ProbeUser, "probe-user", c.config.ProbeUser, "user to check list/watch permissions for via SubjectAccessReview (empty to skip)")
+	fs.StringSliceVar(&c.config.ProbeGroups, "probe-groups", c.config.ProbeGroups, "groups of the probe user")
+	fs.StringVar(&c.config.ProbeResource, "probe-resource", c.config.ProbeResource, "resource the probe user must be able to list and watch")
+	fs.DurationVar(&c.config.Timeout, "timeout", c.config.Timeout, "timeout for each network check")
+
+	return cmd
+}
+
+func (c *doctorCommand) run(cmd *cobra.Command, _ []string) error {
+	log.SetLogger(klog.NewKlogr())
+
+	findings := doctor.Run(cmd.Context(), c.config)
+	doctor.Print(cmd.OutOrStdout(), findings)
+
+	if doctor.HasFailures(findings) {
+		cmd.SilenceUsage = true
+		return errors.New("doctor found problems")
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/memory"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/options"
 	"github.com/spf13/cobra"
 
@@ -20,13 +21,26 @@ type command struct {
 }
 
 func NewCommand() *cobra.Command {
+	// Running the gateway command itself serves, too, for deployments that
+	// predate the serve subcommand.
+	cmd := newServeCommand()
+	cmd.Use = "gateway"
+	cmd.Short = "Run and check the gateway server"
+	cmd.Long = "Runs the gateway server when called without a subcommand, like \"gateway serve\"."
+
+	cmd.AddCommand(newServeCommand(), newDoctorCommand())
+	return cmd
+}
+
+func newServeCommand() *cobra.Command {
 	c := &command{
 		options: options.NewOptions(),
 	}
 
 	cmd := &cobra.Command{
-		Use:   "gateway",
+		Use:   "serve",
 		Short: "Run the gateway server",
+		Args:  cobra.NoArgs,
 		RunE:  c.run,
 	}
 
@@ -49,6 +63,12 @@ func (c *command) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	memory.Tune(memory.Tuning{
+		GCPercent:   completed.GCPercent,
+		MemoryLimit: completed.MemoryLimitBytes,
+		Ballast:     completed.MemoryBallastBytes,
+	})
+
 	config, err := gateway.NewConfig(completed)
 	if err != nil {
 		return err
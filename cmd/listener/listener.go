@@ -23,13 +23,26 @@ type command struct {
 }
 
 func NewCommand() *cobra.Command {
+	// Running the listener command itself runs the listener, too, for
+	// deployments that predate the run subcommand.
+	cmd := newRunCommand()
+	cmd.Use = "listener"
+	cmd.Short = "Run the listener server"
+	cmd.Long = "Runs the listener server when called without a subcommand, like \"listener run\"."
+
+	cmd.AddCommand(newRunCommand())
+	return cmd
+}
+
+func newRunCommand() *cobra.Command {
 	c := &command{
 		options: options.NewOptions(),
 	}
 
 	cmd := &cobra.Command{
-		Use:   "listener",
+		Use:   "run",
 		Short: "Run the listener server",
+		Args:  cobra.NoArgs,
 		RunE:  c.run,
 	}
 
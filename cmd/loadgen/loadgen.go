@@ -0,0 +1,66 @@
+// Package loadgen replays a mix of GraphQL operations against a gateway
+// endpoint at a fixed rate and reports latencies, errors and the requests the
+// gateway sent to API servers per request.
+package loadgen
+
+import (
+	"errors"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the loadgen command.
+func NewCommand() *cobra.Command {
+	cfg := Config{
+		RPS:         10,
+		Duration:    30 * time.Second,
+		Concurrency: 50,
+		Timeout:     30 * time.Second,
+	}
+	var mixFile string
+
+	cmd := &cobra.Command{
+		Use:   "loadgen",
+		Short: "Replay a mix of GraphQL operations against a gateway endpoint",
+		Long: `Sends the operations of a mix file to the GraphQL endpoint of a cluster at
+a fixed rate, picking each operation in proportion to its weight, and
+reports latency percentiles and failed requests by operation and error
+class. With --metrics-url, the gateway's metrics are scraped before and
+after the run to report how many API server requests each gateway request
+caused. Other traffic on the gateway during the run is included in that
+count.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if cfg.URL == "" {
+				return errors.New("--url is required")
+			}
+			if mixFile == "" {
+				return errors.New("--mix is required")
+			}
+			mix, err := LoadMix(mixFile)
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+			report, err := Run(cmd.Context(), cfg, mix)
+			if err != nil {
+				return err
+			}
+			report.Print(cmd.OutOrStdout())
+			return nil
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVar(&cfg.URL, "url", cfg.URL, "GraphQL endpoint of a cluster, e.g. http://localhost:8080/api/clusters/single/graphql")
+	fs.StringVar(&cfg.Token, "token", cfg.Token, "bearer token sent with each request")
+	fs.StringVar(&mixFile, "mix", mixFile, "YAML file with the operations to replay and their weights")
+	fs.Float64Var(&cfg.RPS, "rps", cfg.RPS, "requests started per second")
+	fs.DurationVar(&cfg.Duration, "duration", cfg.Duration, "how long requests are started for")
+	fs.IntVar(&cfg.Concurrency, "concurrency", cfg.Concurrency, "maximum number of requests in flight; requests due above it are dropped")
+	fs.DurationVar(&cfg.Timeout, "timeout", cfg.Timeout, "timeout of each request")
+	fs.StringVar(&cfg.MetricsURL, "metrics-url", cfg.MetricsURL, "metrics endpoint of the gateway to measure API server requests, e.g. http://localhost:8080/metrics (empty to skip)")
+	return cmd
+}
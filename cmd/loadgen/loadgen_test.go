@@ -0,0 +1,133 @@
+package loadgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMix(t *testing.T) {
+	mix, err := LoadMix(filepath.Join("..", "..", "config", "examples", "loadgen.yaml"))
+	require.NoError(t, err)
+	assert.Len(t, mix.operations, 3)
+	assert.Equal(t, 8, mix.total)
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "no operations", content: "operations: []", wantErr: "no operations"},
+		{name: "missing name", content: "operations: [{query: '{ a }'}]", wantErr: "without name"},
+		{name: "duplicate name", content: "operations: [{name: a, query: '{ a }'}, {name: a, query: '{ b }'}]", wantErr: "duplicate"},
+		{name: "missing query", content: "operations: [{name: a}]", wantErr: "no query"},
+		{name: "negative weight", content: "operations: [{name: a, query: '{ a }', weight: -1}]", wantErr: "negative weight"},
+		{name: "unknown field", content: "operations: [{name: a, query: '{ a }', rate: 1}]", wantErr: "failed to parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "mix.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			_, err := LoadMix(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestMixPick(t *testing.T) {
+	mix, err := NewMix([]Operation{
+		{Name: "rare", Query: "{ a }"},
+		{Name: "common", Query: "{ b }", Weight: 9},
+	})
+	require.NoError(t, err)
+
+	counts := map[string]int{}
+	for range 1000 {
+		counts[mix.pick().Name]++
+	}
+	assert.Greater(t, counts["common"], counts["rare"]*4)
+	assert.NotZero(t, counts["rare"])
+}
+
+func TestRun(t *testing.T) {
+	var upstream atomic.Int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var req struct {
+			Query string `json:"query"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		switch req.Query {
+		case "{ ok }":
+			upstream.Add(2)
+			fmt.Fprint(w, `{"data":{"ok":true}}`)
+		case "{ invalid }":
+			fmt.Fprint(w, `{"errors":[{"message":"Cannot query field"}]}`)
+		case "{ limited }":
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	})
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "# TYPE rest_client_requests_total counter")
+		fmt.Fprintf(w, "rest_client_requests_total{code=\"200\",host=\"api server\",method=\"GET\"} %d\n", upstream.Load())
+		fmt.Fprintln(w, `rest_client_requests_total{code="403",host="api server",method="GET"} 5`)
+		fmt.Fprintln(w, `rest_client_request_duration_seconds_count 100`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mix, err := NewMix([]Operation{
+		{Name: "ok", Query: "{ ok }", Weight: 2},
+		{Name: "invalid", Query: "{ invalid }"},
+		{Name: "limited", Query: "{ limited }"},
+	})
+	require.NoError(t, err)
+
+	report, err := Run(t.Context(), Config{
+		URL:         server.URL + "/graphql",
+		Token:       "token",
+		RPS:         200,
+		Duration:    200 * time.Millisecond,
+		Concurrency: 10,
+		Timeout:     time.Second,
+		MetricsURL:  server.URL + "/metrics",
+	}, mix)
+	require.NoError(t, err)
+
+	require.NotZero(t, report.Total.Requests)
+	ok, invalid, limited := report.Operations["ok"], report.Operations["invalid"], report.Operations["limited"]
+	assert.Equal(t, report.Total.Requests, ok.Requests+invalid.Requests+limited.Requests)
+	assert.Zero(t, ok.Errors)
+	assert.Equal(t, invalid.Requests, report.ErrorClasses[ErrorGraphQL])
+	assert.Equal(t, limited.Requests, report.ErrorClasses[ErrorRateLimited])
+	assert.Equal(t, float64(2*ok.Requests), report.UpstreamRequests)
+	assert.LessOrEqual(t, report.Total.P50, report.Total.P99)
+
+	var out bytes.Buffer
+	report.Print(&out)
+	assert.Contains(t, out.String(), "OPERATION")
+	assert.Contains(t, out.String(), "per gateway request")
+}
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 50*time.Millisecond, percentile(latencies, 50))
+	assert.Equal(t, 99*time.Millisecond, percentile(latencies, 99))
+	assert.Equal(t, time.Millisecond, percentile(latencies[:1], 99))
+}
@@ -0,0 +1,92 @@
+package loadgen
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Operation is a GraphQL request replayed by the load generator.
+type Operation struct {
+	// Name identifies the operation in the report.
+	Name string `json:"name"`
+	// Weight is the share of requests sending the operation relative to the
+	// other operations. Defaults to 1.
+	Weight int `json:"weight,omitempty"`
+	// Query is the GraphQL document.
+	Query string `json:"query"`
+	// Variables are the operation's variables.
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// file is the format of the mix file.
+type file struct {
+	Operations []Operation `json:"operations"`
+}
+
+// Mix picks operations at random in proportion to their weights.
+type Mix struct {
+	operations []Operation
+	total      int
+}
+
+// LoadMix reads the operations to replay from a YAML file.
+func LoadMix(path string) (*Mix, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mix: %w", err)
+	}
+
+	var f file
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse mix %s: %w", path, err)
+	}
+	return NewMix(f.Operations)
+}
+
+// NewMix validates the operations and returns their mix.
+func NewMix(operations []Operation) (*Mix, error) {
+	if len(operations) == 0 {
+		return nil, errors.New("mix has no operations")
+	}
+
+	m := &Mix{operations: make([]Operation, 0, len(operations))}
+	names := map[string]bool{}
+	for _, op := range operations {
+		if op.Name == "" {
+			return nil, errors.New("operation without name")
+		}
+		if names[op.Name] {
+			return nil, fmt.Errorf("duplicate operation %q", op.Name)
+		}
+		names[op.Name] = true
+
+		if op.Query == "" {
+			return nil, fmt.Errorf("operation %q has no query", op.Name)
+		}
+		if op.Weight < 0 {
+			return nil, fmt.Errorf("operation %q has a negative weight", op.Name)
+		}
+		if op.Weight == 0 {
+			op.Weight = 1
+		}
+		m.operations = append(m.operations, op)
+		m.total += op.Weight
+	}
+	return m, nil
+}
+
+// pick returns a random operation.
+func (m *Mix) pick() Operation {
+	n := rand.IntN(m.total)
+	for _, op := range m.operations {
+		if n < op.Weight {
+			return op
+		}
+		n -= op.Weight
+	}
+	return m.operations[len(m.operations)-1]
+}
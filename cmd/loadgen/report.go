@@ -0,0 +1,154 @@
+package loadgen
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"text/tabwriter"
+	"time"
+)
+
+// Error classes of failed requests.
+const (
+	ErrorTimeout       = "timeout"
+	ErrorTransport     = "transport"
+	ErrorRateLimited   = "http 429"
+	ErrorClient        = "http 4xx"
+	ErrorServer        = "http 5xx"
+	ErrorGraphQL       = "graphql errors"
+	ErrorInvalidResult = "invalid response"
+)
+
+// result is the outcome of a single request.
+type result struct {
+	operation string
+	latency   time.Duration
+	// errorClass is empty for successful requests.
+	errorClass string
+}
+
+// OperationReport summarizes the requests of one operation, or of all of
+// them.
+type OperationReport struct {
+	Requests int
+	Errors   int
+	P50      time.Duration
+	P90      time.Duration
+	P99      time.Duration
+	Max      time.Duration
+}
+
+// Report summarizes a load test.
+type Report struct {
+	Duration time.Duration
+	// Dropped counts requests not sent because --concurrency requests were
+	// still in flight, so the target RPS was not reached.
+	Dropped int
+	Total   OperationReport
+	// Operations are the reports by operation name.
+	Operations map[string]OperationReport
+	// ErrorClasses counts failed requests by class.
+	ErrorClasses map[string]int
+	// UpstreamRequests is the number of requests the gateway sent to API
+	// servers during the test, or -1 if it was not measured.
+	UpstreamRequests float64
+}
+
+// Amplification returns the number of API server requests per gateway
+// request, or -1 if it was not measured.
+func (r *Report) Amplification() float64 {
+	if r.UpstreamRequests < 0 || r.Total.Requests == 0 {
+		return -1
+	}
+	return r.UpstreamRequests / float64(r.Total.Requests)
+}
+
+// newReport aggregates the results of a load test.
+func newReport(results []result, duration time.Duration, dropped int) *Report {
+	r := &Report{
+		Duration:         duration,
+		Dropped:          dropped,
+		Operations:       map[string]OperationReport{},
+		ErrorClasses:     map[string]int{},
+		UpstreamRequests: -1,
+	}
+
+	byOperation := map[string][]result{}
+	for _, res := range results {
+		byOperation[res.operation] = append(byOperation[res.operation], res)
+		if res.errorClass != "" {
+			r.ErrorClasses[res.errorClass]++
+		}
+	}
+	for name, opResults := range byOperation {
+		r.Operations[name] = summarize(opResults)
+	}
+	r.Total = summarize(results)
+	return r
+}
+
+// summarize computes the request count, errors and latency percentiles of
+// results.
+func summarize(results []result) OperationReport {
+	s := OperationReport{Requests: len(results)}
+	if len(results) == 0 {
+		return s
+	}
+
+	latencies := make([]time.Duration, len(results))
+	for i, res := range results {
+		latencies[i] = res.latency
+		if res.errorClass != "" {
+			s.Errors++
+		}
+	}
+	slices.Sort(latencies)
+
+	s.P50 = percentile(latencies, 50)
+	s.P90 = percentile(latencies, 90)
+	s.P99 = percentile(latencies, 99)
+	s.Max = latencies[len(latencies)-1]
+	return s
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank-1, 0)]
+}
+
+// Print writes the report as tables.
+func (r *Report) Print(w io.Writer) {
+	rps := 0.0
+	if r.Duration > 0 {
+		rps = float64(r.Total.Requests) / r.Duration.Seconds()
+	}
+	fmt.Fprintf(w, "%d requests in %s (%.1f/s), %d errors, %d dropped\n\n", r.Total.Requests, r.Duration.Round(time.Millisecond), rps, r.Total.Errors, r.Dropped) //nolint:errcheck
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "OPERATION\tREQUESTS\tERRORS\tP50\tP90\tP99\tMAX") //nolint:errcheck
+	printRow := func(name string, s OperationReport) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\n", name, s.Requests, s.Errors, //nolint:errcheck
+			s.P50.Round(time.Microsecond), s.P90.Round(time.Microsecond), s.P99.Round(time.Microsecond), s.Max.Round(time.Microsecond))
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.Operations)) {
+		printRow(name, r.Operations[name])
+	}
+	printRow("total", r.Total)
+	tw.Flush() //nolint:errcheck
+
+	if len(r.ErrorClasses) > 0 {
+		fmt.Fprintln(w) //nolint:errcheck
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ERROR CLASS\tREQUESTS") //nolint:errcheck
+		for _, class := range slices.Sorted(maps.Keys(r.ErrorClasses)) {
+			fmt.Fprintf(tw, "%s\t%d\n", class, r.ErrorClasses[class]) //nolint:errcheck
+		}
+		tw.Flush() //nolint:errcheck
+	}
+
+	if amplification := r.Amplification(); amplification >= 0 {
+		fmt.Fprintf(w, "\n%.0f API server requests, %.2f per gateway request\n", r.UpstreamRequests, amplification) //nolint:errcheck
+	}
+}
@@ -0,0 +1,214 @@
+package loadgen
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// upstreamMetric counts the requests of the gateway's Kubernetes clients.
+const upstreamMetric = "rest_client_requests_total"
+
+// Config configures a load test.
+type Config struct {
+	// URL is the GraphQL endpoint of a cluster.
+	URL string
+	// Token is sent as bearer token if set.
+	Token string
+	// RPS is the rate requests are started at.
+	RPS float64
+	// Duration is how long requests are started for.
+	Duration time.Duration
+	// Concurrency limits the requests in flight. Requests due while the
+	// limit is reached are dropped and counted.
+	Concurrency int
+	// Timeout is the timeout of each request.
+	Timeout time.Duration
+	// MetricsURL is the gateway's metrics endpoint, scraped before and after
+	// the test to measure API server requests. Empty skips the measurement.
+	MetricsURL string
+}
+
+// Run sends requests picked from the mix at the configured rate until the
+// duration has passed and all requests have completed.
+func Run(ctx context.Context, cfg Config, mix *Mix) (*Report, error) {
+	if cfg.RPS <= 0 {
+		return nil, errors.New("rps must be positive")
+	}
+	if cfg.Concurrency <= 0 {
+		return nil, errors.New("concurrency must be positive")
+	}
+
+	httpClient := &http.Client{Timeout: cfg.Timeout}
+
+	upstreamBefore := -1.0
+	if cfg.MetricsURL != "" {
+		var err error
+		if upstreamBefore, err = scrapeCounter(ctx, httpClient, cfg.MetricsURL, upstreamMetric); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		results []result
+		dropped int
+		wg      sync.WaitGroup
+	)
+	inFlight := make(chan struct{}, cfg.Concurrency)
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.RPS))
+	defer ticker.Stop()
+	deadline := time.After(cfg.Duration)
+	start := time.Now()
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-deadline:
+			break loop
+		case <-ticker.C:
+		}
+
+		select {
+		case inFlight <- struct{}{}:
+		default:
+			dropped++
+			continue
+		}
+
+		op := mix.pick()
+		wg.Go(func() {
+			defer func() { <-inFlight }()
+			res := send(ctx, httpClient, cfg, op)
+			mu.Lock()
+			results = append(results, res)
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	report := newReport(results, time.Since(start), dropped)
+	if cfg.MetricsURL != "" {
+		upstreamAfter, err := scrapeCounter(context.WithoutCancel(ctx), httpClient, cfg.MetricsURL, upstreamMetric)
+		if err != nil {
+			return nil, err
+		}
+		report.UpstreamRequests = upstreamAfter - upstreamBefore
+	}
+	return report, nil
+}
+
+// send executes an operation and classifies its outcome.
+func send(ctx context.Context, httpClient *http.Client, cfg Config, op Operation) result {
+	res := result{operation: op.Name}
+
+	body, err := json.Marshal(map[string]any{"query": op.Query, "variables": op.Variables})
+	if err != nil {
+		res.errorClass = ErrorTransport
+		return res
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		res.errorClass = ErrorTransport
+		return res
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.Token)
+	}
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		res.latency = time.Since(start)
+		res.errorClass = ErrorTransport
+		if errors.Is(err, context.DeadlineExceeded) || isTimeout(err) {
+			res.errorClass = ErrorTimeout
+		}
+		return res
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	var payload struct {
+		Errors []json.RawMessage `json:"errors"`
+	}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&payload)
+	res.latency = time.Since(start)
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		res.errorClass = ErrorRateLimited
+	case resp.StatusCode == http.StatusGatewayTimeout:
+		res.errorClass = ErrorTimeout
+	case resp.StatusCode >= 500:
+		res.errorClass = ErrorServer
+	case resp.StatusCode >= 400:
+		res.errorClass = ErrorClient
+	case decodeErr != nil:
+		res.errorClass = ErrorInvalidResult
+	case len(payload.Errors) > 0:
+		res.errorClass = ErrorGraphQL
+	}
+	return res
+}
+
+// isTimeout reports whether err is a network timeout.
+func isTimeout(err error) bool {
+	var timeout interface{ Timeout() bool }
+	return errors.As(err, &timeout) && timeout.Timeout()
+}
+
+// scrapeCounter returns the sum of all series of a counter in the Prometheus
+// text format served at url.
+func scrapeCounter(ctx context.Context, httpClient *http.Client, url, name string) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to scrape metrics: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to scrape metrics: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to scrape metrics: %s returned %s", url, resp.Status)
+	}
+
+	sum := 0.0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, name+"{") && !strings.HasPrefix(line, name+" ") {
+			continue
+		}
+		// The value follows the labels, which may contain spaces.
+		rest := line[len(name):]
+		if i := strings.LastIndex(rest, "}"); i >= 0 {
+			rest = rest[i+1:]
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		sum += value
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to scrape metrics: %w", err)
+	}
+	return sum, nil
+}
@@ -0,0 +1,301 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Components are the components dashboards are generated for.
+var Components = []string{"gateway", "listener"}
+
+// rateInterval is the range of rate queries, chosen by Grafana from the
+// scrape interval of the data source.
+const rateInterval = "[$__rate_interval]"
+
+// panelWidth and panelHeight size the panels on Grafana's 24 column grid,
+// two per row.
+const (
+	panelWidth  = 12
+	panelHeight = 8
+)
+
+// row groups panels under a collapsible title.
+type row struct {
+	title  string
+	panels []panel
+}
+
+// panel is a time series panel with one query per series.
+type panel struct {
+	title       string
+	description string
+	unit        string
+	queries     []query
+}
+
+type query struct {
+	expr   string
+	legend string
+}
+
+// selector restricts a metric to the instances picked in the job variable.
+func selector(metric string, matchers ...string) string {
+	return metric + "{" + strings.Join(append([]string{`job=~"$job"`}, matchers...), ",") + "}"
+}
+
+// quantiles returns the queries for the 50th, 95th and 99th percentile of a
+// histogram, grouped by the given labels.
+func quantiles(histogram string, by ...string) []query {
+	groupBy := strings.Join(append(slices.Clone(by), "le"), ", ")
+	prefix := ""
+	if len(by) > 0 {
+		prefix = "{{" + by[0] + "}} "
+	}
+
+	var queries []query
+	for _, q := range []struct{ quantile, legend string }{{"0.5", "p50"}, {"0.95", "p95"}, {"0.99", "p99"}} {
+		queries = append(queries, query{
+			expr:   fmt.Sprintf("histogram_quantile(%s, sum by (%s) (rate(%s%s)))", q.quantile, groupBy, selector(histogram+"_bucket"), rateInterval),
+			legend: prefix + q.legend,
+		})
+	}
+	return queries
+}
+
+// runtimeRow returns the panels for the Go runtime and process metrics both
+// components expose.
+func runtimeRow() row {
+	return row{title: "Runtime", panels: []panel{
+		{
+			title:   "Memory",
+			unit:    "bytes",
+			queries: []query{{expr: selector("go_memstats_heap_inuse_bytes"), legend: "{{instance}} heap"}, {expr: selector("process_resident_memory_bytes"), legend: "{{instance}} resident"}},
+		},
+		{
+			title:   "CPU",
+			unit:    "short",
+			queries: []query{{expr: "rate(" + selector("process_cpu_seconds_total") + rateInterval + ")", legend: "{{instance}}"}},
+		},
+		{
+			title:   "Goroutines",
+			unit:    "short",
+			queries: []query{{expr: selector("go_goroutines"), legend: "{{instance}}"}},
+		},
+	}}
+}
+
+// gatewayRows are the panels of the gateway dashboard.
+func gatewayRows() []row {
+	return []row{
+		{title: "Requests", panels: []panel{
+			{
+				title:       "Request rate",
+				description: "GraphQL query and mutation requests by HTTP status code. Subscriptions are counted separately.",
+				unit:        "reqps",
+				queries:     []query{{expr: "sum by (code) (rate(" + selector("graphql_requests_total") + rateInterval + "))", legend: "{{code}}"}},
+			},
+			{
+				title:       "Error ratio",
+				description: "Share of requests answered with a 5xx status code, including timeouts. GraphQL errors returned with status 200 are not included.",
+				unit:        "percentunit",
+				queries: []query{{
+					expr:   "sum(rate(" + selector("graphql_requests_total", `code=~"5.."`) + rateInterval + ")) / sum(rate(" + selector("graphql_requests_total") + rateInterval + "))",
+					legend: "5xx",
+				}},
+			},
+			{
+				title:       "Latency",
+				description: "Latency of query and mutation requests.",
+				unit:        "s",
+				queries:     quantiles("graphql_request_duration_seconds"),
+			},
+			{
+				title:       "Rejected requests",
+				description: "Requests rejected with 429 because the in-flight limit was reached.",
+				unit:        "reqps",
+				queries:     []query{{expr: "sum(rate(" + selector("graphql_requests_total", `code="429"`) + rateInterval + "))", legend: "rejected"}},
+			},
+		}},
+		{title: "Subscriptions", panels: []panel{
+			{
+				title:   "Active subscriptions",
+				unit:    "short",
+				queries: []query{{expr: "sum(" + selector("graphql_subscriptions_active") + ")", legend: "active"}},
+			},
+			{
+				title: "Subscription rate",
+				unit:  "short",
+				queries: []query{
+					{expr: "sum(rate(" + selector("graphql_subscriptions_total") + rateInterval + "))", legend: "opened"},
+					{expr: "sum(rate(" + selector("graphql_subscriptions_rejected_total") + rateInterval + "))", legend: "rejected"},
+				},
+			},
+			{
+				title:       "Dropped events",
+				description: "Subscription events merged into later events of the same object by --subscription-event-limits, by kind.",
+				unit:        "short",
+				queries:     []query{{expr: "sum by (kind) (rate(" + selector("graphql_subscription_events_dropped_total") + rateInterval + "))", legend: "{{kind}}"}},
+			},
+		}},
+		{title: "Schemas", panels: []panel{
+			{
+				title:       "Failed smoke tests",
+				description: "Smoke tests failed after the last schema load by cluster. Non-zero marks the cluster degraded.",
+				unit:        "short",
+				queries:     []query{{expr: "max by (cluster) (" + selector("graphql_smoke_tests_failed") + ")", legend: "{{cluster}}"}},
+			},
+			{
+				title:   "Smoke test runs",
+				unit:    "short",
+				queries: []query{{expr: "sum by (result) (rate(" + selector("graphql_smoke_test_runs_total") + rateInterval + "))", legend: "{{result}}"}},
+			},
+			{
+				title:       "Schema age",
+				description: "Time since the listener last wrote the schema file of a cluster. Ages beyond the staleness threshold mean the listener is down or stuck.",
+				unit:        "s",
+				queries:     []query{{expr: "max by (cluster) (" + selector("graphql_schema_age_seconds") + ")", legend: "{{cluster}}"}},
+			},
+		}},
+		{title: "API server", panels: []panel{
+			{
+				title:       "API requests",
+				description: "Requests to the API servers of the clusters by HTTP status code, including token reviews. Compare with the request rate to see how many API requests a GraphQL request causes.",
+				unit:        "reqps",
+				queries:     []query{{expr: "sum by (code) (rate(" + selector("rest_client_requests_total") + rateInterval + "))", legend: "{{code}}"}},
+			},
+		}},
+		runtimeRow(),
+	}
+}
+
+// listenerRows are the panels of the listener dashboard, built on the
+// metrics of controller-runtime.
+func listenerRows() []row {
+	return []row{
+		{title: "Reconciliation", panels: []panel{
+			{
+				title:       "Reconciles",
+				description: "Reconciles by controller and result. Each successful reconcile regenerates the schemas of a cluster.",
+				unit:        "short",
+				queries:     []query{{expr: "sum by (controller, result) (rate(" + selector("controller_runtime_reconcile_total") + rateInterval + "))", legend: "{{controller}} {{result}}"}},
+			},
+			{
+				title:   "Reconcile errors",
+				unit:    "short",
+				queries: []query{{expr: "sum by (controller) (rate(" + selector("controller_runtime_reconcile_errors_total") + rateInterval + "))", legend: "{{controller}}"}},
+			},
+			{
+				title:   "Reconcile latency",
+				unit:    "s",
+				queries: quantiles("controller_runtime_reconcile_time_seconds", "controller"),
+			},
+			{
+				title:   "Work queue depth",
+				unit:    "short",
+				queries: []query{{expr: "sum by (name) (" + selector("workqueue_depth") + ")", legend: "{{name}}"}},
+			},
+		}},
+		{title: "API server", panels: []panel{
+			{
+				title:       "API requests",
+				description: "Requests to the API servers of the watched clusters by HTTP status code.",
+				unit:        "reqps",
+				queries:     []query{{expr: "sum by (code) (rate(" + selector("rest_client_requests_total") + rateInterval + "))", legend: "{{code}}"}},
+			},
+		}},
+		runtimeRow(),
+	}
+}
+
+// Dashboard returns the Grafana dashboard of a component as JSON. The
+// dashboard asks for a Prometheus data source and the jobs to show when it
+// is imported.
+func Dashboard(component string) ([]byte, error) {
+	var (
+		rows      []row
+		jobMetric string
+	)
+	switch component {
+	case "gateway":
+		rows, jobMetric = gatewayRows(), "graphql_subscriptions_total"
+	case "listener":
+		rows, jobMetric = listenerRows(), "controller_runtime_reconcile_total"
+	default:
+		return nil, fmt.Errorf("unknown component %q, must be one of %s", component, strings.Join(Components, ", "))
+	}
+
+	datasource := map[string]any{"type": "prometheus", "uid": "${datasource}"}
+
+	var panels []map[string]any
+	id, y := 1, 0
+	for _, r := range rows {
+		panels = append(panels, map[string]any{
+			"id":        id,
+			"type":      "row",
+			"title":     r.title,
+			"collapsed": false,
+			"gridPos":   map[string]any{"x": 0, "y": y, "w": 2 * panelWidth, "h": 1},
+			"panels":    []any{},
+		})
+		id, y = id+1, y+1
+
+		for i, p := range r.panels {
+			targets := make([]map[string]any, 0, len(p.queries))
+			for j, q := range p.queries {
+				targets = append(targets, map[string]any{
+					"datasource":   datasource,
+					"expr":         q.expr,
+					"legendFormat": q.legend,
+					"refId":        string(rune('A' + j)),
+				})
+			}
+			panels = append(panels, map[string]any{
+				"id":          id,
+				"type":        "timeseries",
+				"title":       p.title,
+				"description": p.description,
+				"datasource":  datasource,
+				"gridPos":     map[string]any{"x": (i % 2) * panelWidth, "y": y + (i/2)*panelHeight, "w": panelWidth, "h": panelHeight},
+				"fieldConfig": map[string]any{"defaults": map[string]any{"unit": p.unit}, "overrides": []any{}},
+				"targets":     targets,
+			})
+			id++
+		}
+		y += (len(r.panels) + 1) / 2 * panelHeight
+	}
+
+	dashboard := map[string]any{
+		"uid":           "kubernetes-graphql-" + component,
+		"title":         "Kubernetes GraphQL Gateway / " + strings.ToUpper(component[:1]) + component[1:],
+		"tags":          []string{"kubernetes-graphql-gateway"},
+		"schemaVersion": 39,
+		"editable":      true,
+		"refresh":       "30s",
+		"time":          map[string]any{"from": "now-6h", "to": "now"},
+		"templating": map[string]any{"list": []map[string]any{
+			{
+				"name":  "datasource",
+				"label": "Data source",
+				"type":  "datasource",
+				"query": "prometheus",
+			},
+			{
+				"name":       "job",
+				"label":      "Job",
+				"type":       "query",
+				"datasource": datasource,
+				"query":      map[string]any{"query": "label_values(" + jobMetric + ", job)", "refId": "job"},
+				"refresh":    2,
+				"multi":      true,
+				"includeAll": true,
+				"allValue":   ".*",
+				"current":    map[string]any{"text": "All", "value": "$__all"},
+			},
+		}},
+		"panels": panels,
+	}
+
+	return json.MarshalIndent(dashboard, "", "  ")
+}
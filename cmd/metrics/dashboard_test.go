@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/freshness"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/metrics"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/smoketest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboard(t *testing.T) {
+	for _, component := range Components {
+		t.Run(component, func(t *testing.T) {
+			data, err := Dashboard(component)
+			require.NoError(t, err)
+
+			var dashboard struct {
+				UID    string `json:"uid"`
+				Panels []struct {
+					ID      int    `json:"id"`
+					Type    string `json:"type"`
+					Targets []struct {
+						Expr  string `json:"expr"`
+						RefID string `json:"refId"`
+					} `json:"targets"`
+				} `json:"panels"`
+			}
+			require.NoError(t, json.Unmarshal(data, &dashboard))
+			assert.Equal(t, "kubernetes-graphql-"+component, dashboard.UID)
+
+			ids := map[int]bool{}
+			for _, p := range dashboard.Panels {
+				assert.False(t, ids[p.ID], "duplicate panel id %d", p.ID)
+				ids[p.ID] = true
+				if p.Type == "row" {
+					continue
+				}
+				require.NotEmpty(t, p.Targets)
+				for _, target := range p.Targets {
+					assert.Contains(t, target.Expr, `job=~"$job"`)
+				}
+			}
+		})
+	}
+
+	_, err := Dashboard("unknown")
+	assert.Error(t, err)
+}
+
+// recordingRegisterer records the names of the metrics registered with it.
+type recordingRegisterer struct {
+	names map[string]bool
+}
+
+var fqNameRegex = regexp.MustCompile(`fqName: "([^"]+)"`)
+
+func (r *recordingRegisterer) Register(c prometheus.Collector) error {
+	descs := make(chan *prometheus.Desc)
+	go func() {
+		c.Describe(descs)
+		close(descs)
+	}()
+	for desc := range descs {
+		if match := fqNameRegex.FindStringSubmatch(desc.String()); match != nil {
+			r.names[match[1]] = true
+		}
+	}
+	return nil
+}
+
+func (r *recordingRegisterer) MustRegister(cs ...prometheus.Collector) {
+	for _, c := range cs {
+		_ = r.Register(c)
+	}
+}
+
+func (r *recordingRegisterer) Unregister(prometheus.Collector) bool { return true }
+
+func TestGatewayDashboardUsesRegisteredMetrics(t *testing.T) {
+	reg := &recordingRegisterer{names: map[string]bool{}}
+	metrics.NewSubscriptionMetrics(reg)
+	metrics.NewRequestMetrics(reg)
+	metrics.NewEventMetrics(reg)
+	smoketest.NewRunner(nil, nil, 0, reg)
+	freshness.NewWatchdog("", 0, "", reg)
+
+	metricRegex := regexp.MustCompile(`(graphql_[a-z_]+?)(_bucket)?\{`)
+	checked := 0
+	for _, r := range gatewayRows() {
+		for _, p := range r.panels {
+			for _, q := range p.queries {
+				for _, match := range metricRegex.FindAllStringSubmatch(q.expr, -1) {
+					assert.True(t, reg.names[match[1]], "panel %q uses unregistered metric %s", p.title, match[1])
+					checked++
+				}
+			}
+		}
+	}
+	assert.NotZero(t, checked)
+}
@@ -0,0 +1,46 @@
+// Package metrics provides commands for monitoring the gateway and listener.
+package metrics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the metrics command, grouping commands for monitoring
+// the gateway and listener.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Monitor the gateway and listener",
+	}
+
+	cmd.AddCommand(newDashboardCommand())
+	return cmd
+}
+
+func newDashboardCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "dashboard COMPONENT",
+		Short: "Print a Grafana dashboard for the metrics of a component",
+		Long: `Prints a Grafana dashboard for the metrics of the gateway or the listener
+as JSON, to be imported into Grafana or provisioned from a file. The
+dashboard shows request rates, latencies and errors, subscriptions, smoke
+test results and runtime metrics of the gateway, or reconciles, work queues
+and API server requests of the listener. It asks for a Prometheus data
+source and the scrape jobs to show.
+
+Components: ` + strings.Join(Components, ", "),
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: Components,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dashboard, err := Dashboard(args[0])
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), string(dashboard))
+			return err
+		},
+	}
+}
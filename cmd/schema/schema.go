@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the schema command, grouping commands working on schema
+// files written by the listener.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Work with schema files written by the listener",
+	}
+
+	cmd.AddCommand(newPreviewCommand())
+	return cmd
+}
+
+func newPreviewCommand() *cobra.Command {
+	var exposeManagedFields, federation bool
+
+	cmd := &cobra.Command{
+		Use:   "preview FILE",
+		Short: "Print the GraphQL schema the gateway generates for a schema file",
+		Long: `Generates the GraphQL schema the gateway would serve for a schema file
+written by the listener and prints it in the GraphQL schema definition
+language. No cluster is contacted.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			var schemaData v1alpha1.Schema
+			if err := json.Unmarshal(data, &schemaData); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", args[0], err)
+			}
+			if schemaData.Components == nil {
+				return fmt.Errorf("%s has no component definitions", args[0])
+			}
+
+			definitions := schemaData.Components.Schemas
+			if !exposeManagedFields {
+				definitions = schema.WithoutManagedFields(definitions)
+			}
+
+			provider, err := schema.New(cmd.Context(), definitions, resolver.New(nil).WithFederation(federation), nil)
+			if err != nil {
+				return fmt.Errorf("failed to generate GraphQL schema: %w", err)
+			}
+
+			printSDL := schema.PrintSDL
+			if federation {
+				printSDL = schema.PrintSubgraphSDL
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), printSDL(provider.GetSchema()))
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&exposeManagedFields, "expose-managed-fields", false, "include metadata.managedFields as with the gateway flag of the same name")
+	cmd.Flags().BoolVar(&federation, "federation", false, "print the SDL of the Apollo Federation subgraph as with the gateway flag of the same name")
+	return cmd
+}
@@ -0,0 +1,52 @@
+// Package version holds the build's version, shared by all subcommands.
+// Release builds set the variables with
+//
+//	-ldflags "-X github.com/platform-mesh/kubernetes-graphql-gateway/cmd/version.Version=v1.2.3"
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Version is the release version of the build.
+	Version = "dev"
+	// Commit is the git commit the build was made from.
+	Commit = ""
+)
+
+// String returns the version, commit and Go version of the build. The commit
+// falls back to the VCS information Go embeds in the binary.
+func String() string {
+	commit := Commit
+	if commit == "" {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range info.Settings {
+				if setting.Key == "vcs.revision" {
+					commit = setting.Value
+				}
+			}
+		}
+	}
+	if commit == "" {
+		commit = "unknown"
+	}
+	return fmt.Sprintf("%s (commit %s, %s)", Version, commit, runtime.Version())
+}
+
+// NewCommand returns the version command.
+func NewCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print the version",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			_, err := fmt.Fprintln(cmd.OutOrStdout(), String())
+			return err
+		},
+	}
+}
@@ -0,0 +1,229 @@
+package changefeed
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Operation names recorded in the change feed.
+const (
+	OperationCreate = "CREATE"
+	OperationUpdate = "UPDATE"
+	OperationDelete = "DELETE"
+	// OperationApply is a server-side apply, which creates or updates.
+	OperationApply = "APPLY"
+)
+
+// Change is a mutation executed through this gateway instance.
+type Change struct {
+	Time            time.Time `json:"time"`
+	Cluster         string    `json:"cluster"`
+	Operation       string    `json:"operation"`
+	Group           string    `json:"group"`
+	Version         string    `json:"version"`
+	Kind            string    `json:"kind"`
+	Namespace       string    `json:"namespace,omitempty"`
+	Name            string    `json:"name"`
+	ResourceVersion string    `json:"resourceVersion,omitempty"`
+}
+
+// Filter selects changes returned by Recent.
+type Filter struct {
+	// Cluster matches the change's cluster exactly.
+	Cluster string
+	// Kinds matches any of the kinds, case-insensitively. Empty matches all.
+	Kinds []string
+}
+
+func (f Filter) matches(c Change) bool {
+	if c.Cluster != f.Cluster {
+		return false
+	}
+	if len(f.Kinds) == 0 {
+		return true
+	}
+	for _, kind := range f.Kinds {
+		if strings.EqualFold(kind, c.Kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// Feed is a bounded ring buffer of recent changes. When created with a file,
+// changes are appended to it as JSON lines and reloaded on start, so the feed
+// survives restarts.
+type Feed struct {
+	mu      sync.Mutex
+	changes []Change
+	next    int
+	full    bool
+	file    *os.File
+}
+
+// New creates a feed holding up to size changes. If path is not empty,
+// changes are persisted to that file and the most recent ones are loaded
+// from it.
+func New(size int, path string) (*Feed, error) {
+	if size <= 0 {
+		return nil, errors.New("change feed size must be positive")
+	}
+
+	f := &Feed{changes: make([]Change, size)}
+	if path == "" {
+		return f, nil
+	}
+
+	if err := f.load(path); err != nil {
+		return nil, err
+	}
+
+	// Rewrite the file with the retained changes so it does not grow
+	// without bound across restarts.
+	if err := f.compact(path); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open change feed file: %w", err)
+	}
+	f.file = file
+
+	return f, nil
+}
+
+// Record adds a change to the feed, evicting the oldest if it is full.
+func (f *Feed) Record(ctx context.Context, c Change) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.add(c)
+
+	if f.file == nil {
+		return
+	}
+	line, err := json.Marshal(c)
+	if err == nil {
+		_, err = f.file.Write(append(line, '\n'))
+	}
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to persist change")
+	}
+}
+
+// Recent returns up to limit changes matching filter, newest first.
+func (f *Feed) Recent(filter Filter, limit int) []Change {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := []Change{}
+	for _, c := range f.newestFirst() {
+		if len(result) == limit {
+			break
+		}
+		if filter.matches(c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// Size returns the maximum number of changes the feed holds.
+func (f *Feed) Size() int {
+	return len(f.changes)
+}
+
+// Close closes the persistence file, if any.
+func (f *Feed) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *Feed) add(c Change) {
+	f.changes[f.next] = c
+	f.next = (f.next + 1) % len(f.changes)
+	if f.next == 0 {
+		f.full = true
+	}
+}
+
+// newestFirst returns all buffered changes, newest first.
+func (f *Feed) newestFirst() []Change {
+	count := f.next
+	if f.full {
+		count = len(f.changes)
+	}
+
+	changes := make([]Change, 0, count)
+	for i := 1; i <= count; i++ {
+		changes = append(changes, f.changes[(f.next-i+len(f.changes))%len(f.changes)])
+	}
+	return changes
+}
+
+func (f *Feed) load(path string) error {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open change feed file: %w", err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var c Change
+		// Skip lines that cannot be decoded, e.g. one cut off by a crash.
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			continue
+		}
+		f.add(c)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read change feed file: %w", err)
+	}
+	return nil
+}
+
+func (f *Feed) compact(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to compact change feed file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+
+	encoder := json.NewEncoder(tmp)
+	changes := f.newestFirst()
+	for i := len(changes) - 1; i >= 0; i-- {
+		if err := encoder.Encode(changes[i]); err != nil {
+			_ = tmp.Close()
+			return fmt.Errorf("failed to compact change feed file: %w", err)
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to compact change feed file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to compact change feed file: %w", err)
+	}
+	return nil
+}
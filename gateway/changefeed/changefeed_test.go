@@ -0,0 +1,94 @@
+package changefeed_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/changefeed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func names(changes []changefeed.Change) []string {
+	result := make([]string, 0, len(changes))
+	for _, c := range changes {
+		result = append(result, c.Name)
+	}
+	return result
+}
+
+func TestNew_InvalidSize(t *testing.T) {
+	_, err := changefeed.New(0, "")
+	assert.Error(t, err)
+}
+
+func TestFeed_EvictsOldest(t *testing.T) {
+	feed, err := changefeed.New(3, "")
+	require.NoError(t, err)
+
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		feed.Record(t.Context(), changefeed.Change{Cluster: "c1", Kind: "Pod", Name: name})
+	}
+
+	assert.Equal(t, []string{"e", "d", "c"}, names(feed.Recent(changefeed.Filter{Cluster: "c1"}, 10)))
+	assert.Equal(t, []string{"e", "d"}, names(feed.Recent(changefeed.Filter{Cluster: "c1"}, 2)))
+}
+
+func TestFeed_Filter(t *testing.T) {
+	feed, err := changefeed.New(10, "")
+	require.NoError(t, err)
+
+	feed.Record(t.Context(), changefeed.Change{Cluster: "c1", Kind: "Pod", Name: "pod"})
+	feed.Record(t.Context(), changefeed.Change{Cluster: "c1", Kind: "ConfigMap", Name: "cm"})
+	feed.Record(t.Context(), changefeed.Change{Cluster: "c2", Kind: "Pod", Name: "other"})
+	feed.Record(t.Context(), changefeed.Change{Cluster: "c1", Kind: "Secret", Name: "secret"})
+
+	tests := []struct {
+		name   string
+		filter changefeed.Filter
+		want   []string
+	}{
+		{name: "cluster only", filter: changefeed.Filter{Cluster: "c1"}, want: []string{"secret", "cm", "pod"}},
+		{name: "other cluster", filter: changefeed.Filter{Cluster: "c2"}, want: []string{"other"}},
+		{name: "kinds case-insensitive", filter: changefeed.Filter{Cluster: "c1", Kinds: []string{"pod", "SECRET"}}, want: []string{"secret", "pod"}},
+		{name: "no match", filter: changefeed.Filter{Cluster: "c3"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, names(feed.Recent(tt.filter, 10)))
+		})
+	}
+}
+
+func TestFeed_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.jsonl")
+
+	feed, err := changefeed.New(2, path)
+	require.NoError(t, err)
+	for _, name := range []string{"a", "b", "c"} {
+		feed.Record(t.Context(), changefeed.Change{Cluster: "c1", Operation: changefeed.OperationCreate, Kind: "Pod", Name: name})
+	}
+	require.NoError(t, feed.Close())
+
+	// A line cut off by a crash must not prevent loading the rest.
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"cluster":"c1","na`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	reloaded, err := changefeed.New(2, path)
+	require.NoError(t, err)
+	defer reloaded.Close() //nolint:errcheck
+
+	changes := reloaded.Recent(changefeed.Filter{Cluster: "c1"}, 10)
+	assert.Equal(t, []string{"c", "b"}, names(changes))
+	assert.Equal(t, changefeed.OperationCreate, changes[0].Operation)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), 2, "file should be compacted to the feed size")
+}
@@ -2,14 +2,28 @@ package gateway
 
 import (
 	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"strings"
 
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/changefeed"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway"
 	gatewayconfig "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/config"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/freshness"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/memory"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/metrics"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/middleware"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/queryvalidation"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/http"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/kindalias"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/options"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/savedqueries"
+	schematypes "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/smoketest"
 	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 type Config struct {
@@ -17,6 +31,8 @@ type Config struct {
 
 	Gateway    *gateway.Service
 	HTTPServer *http.Server
+	// Freshness watches the age of the schema files, nil if disabled.
+	Freshness *freshness.Watchdog
 }
 
 func NewConfig(opts *options.CompletedOptions) (*Config, error) {
@@ -24,6 +40,61 @@ func NewConfig(opts *options.CompletedOptions) (*Config, error) {
 		Options: opts,
 	}
 
+	var changes *changefeed.Feed
+	if cfg.Options.ChangeFeedSize > 0 {
+		feed, err := changefeed.New(cfg.Options.ChangeFeedSize, cfg.Options.ChangeFeedFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create change feed: %w", err)
+		}
+		changes = feed
+	}
+
+	var savedQueries *savedqueries.Store
+	if cfg.Options.SavedQueriesFile != "" {
+		store, err := savedqueries.New(cfg.Options.SavedQueriesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load saved queries: %w", err)
+		}
+		savedQueries = store
+	}
+
+	var smokeTests *smoketest.Runner
+	if cfg.Options.SmokeTestsFile != "" {
+		operations, err := smoketest.Load(cfg.Options.SmokeTestsFile)
+		if err != nil {
+			return nil, err
+		}
+		namespace, name, _ := strings.Cut(cfg.Options.SmokeTestServiceAccount, "/")
+		smokeTests = smoketest.NewRunner(operations, smoketest.ServiceAccountToken(namespace, name), cfg.Options.SmokeTestTimeout, prometheus.DefaultRegisterer)
+	}
+
+	var kindAliases []kindalias.Alias
+	if cfg.Options.KindAliasesFile != "" {
+		aliases, err := kindalias.Load(cfg.Options.KindAliasesFile)
+		if err != nil {
+			return nil, err
+		}
+		kindAliases = aliases
+	}
+
+	eventLimits, err := resolver.ParseEventLimits(cfg.Options.SubscriptionEventLimits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --subscription-event-limits: %w", err)
+	}
+	eventMetrics := metrics.NewEventMetrics(prometheus.DefaultRegisterer)
+	eventsDropped := func(gk schema.GroupKind) {
+		eventMetrics.Dropped.WithLabelValues(gk.Group, gk.Kind).Inc()
+	}
+
+	var allowlist *queryvalidation.Allowlist
+	if cfg.Options.OperationAllowlistDir != "" {
+		loaded, err := queryvalidation.LoadAllowlist(cfg.Options.OperationAllowlistDir)
+		if err != nil {
+			return nil, err
+		}
+		allowlist = loaded
+	}
+
 	gatewayServer, err := gateway.New(gatewayconfig.Gateway{
 		SchemaHandler:      cfg.Options.SchemaHandler,
 		SchemaDirectory:    cfg.Options.SchemasDir,
@@ -33,13 +104,47 @@ func NewConfig(opts *options.CompletedOptions) (*Config, error) {
 			Pretty:            true,
 			PlaygroundEnabled: cfg.Options.PlaygroundEnabled,
 			GraphiQL:          cfg.Options.PlaygroundEnabled,
+
+			SubscriptionMaxLifetime:        cfg.Options.SubscriptionMaxLifetime,
+			SubscriptionExpiryWarning:      cfg.Options.SubscriptionExpiryWarning,
+			SSEKeepaliveInterval:           cfg.Options.SSEKeepaliveInterval,
+			SSEWriteTimeout:                cfg.Options.SSEWriteTimeout,
+			LiveQueryInterval:              cfg.Options.LiveQueryInterval,
+			EventLimits:                    eventLimits,
+			EventsDropped:                  eventsDropped,
+			BatchConcurrency:               cfg.Options.QueryBatchConcurrency,
+			ExposeManagedFields:            cfg.Options.ExposeManagedFields,
+			EmptyValues:                    resolver.EmptyValues(cfg.Options.EmptyValues),
+			FieldCasing:                    schematypes.FieldCasing(cfg.Options.FieldCasing),
+			Federation:                     cfg.Options.Federation,
+			KindAliases:                    kindAliases,
+			Introspection:                  gatewayconfig.Introspection(cfg.Options.Introspection),
+			OperationAllowlist:             allowlist,
+			RBACSchemaPruning:              cfg.Options.RBACSchemaPruning,
+			RBACSchemaPruningNamespace:     cfg.Options.RBACSchemaPruningNamespace,
+			HiddenMaturities:               cfg.Options.HiddenMaturities,
+			HiddenMaturitiesGroups:         cfg.Options.HiddenMaturitiesGroups,
+			ClusterRegistrationTTL:         cfg.Options.ClusterRegistrationTTL,
+			ClusterRegistrationGroups:      cfg.Options.ClusterRegistrationGroups,
+			ClusterRegistrationHomeCluster: cfg.Options.ClusterRegistrationHomeCluster,
 		},
 		Limits: gatewayconfig.Limits{
 			MaxQueryDepth:      cfg.Options.MaxQueryDepth,
 			MaxQueryComplexity: cfg.Options.MaxQueryComplexity,
 			MaxQueryBatchSize:  cfg.Options.MaxQueryBatchSize,
+			MaxLogLines:        cfg.Options.MaxLogLines,
+			MaxLogBytes:        cfg.Options.MaxLogBytes,
+			MaxEvents:          cfg.Options.MaxEvents,
 		},
 		TokenReviewCacheTTL: cfg.Options.TokenReviewCacheTTL,
+		ChangeFeed:          changes,
+		SavedQueries:        savedQueries,
+		SmokeTests:          smokeTests,
+		Canary: gatewayconfig.Canary{
+			Duration:         cfg.Options.CanaryDuration,
+			Percentage:       cfg.Options.CanaryPercentage,
+			MaxErrorIncrease: cfg.Options.CanaryMaxErrorIncrease,
+		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gateway server: %w", err)
@@ -47,6 +152,32 @@ func NewConfig(opts *options.CompletedOptions) (*Config, error) {
 	cfg.Gateway = gatewayServer
 
 	subMetrics := metrics.NewSubscriptionMetrics(prometheus.DefaultRegisterer)
+	requestMetrics := metrics.NewRequestMetrics(prometheus.DefaultRegisterer)
+
+	var statusz nethttp.Handler
+	if cfg.Options.SchemaHandler == "file" && cfg.Options.SchemaStalenessThreshold > 0 {
+		cfg.Freshness = freshness.NewWatchdog(cfg.Options.SchemasDir, cfg.Options.SchemaStalenessThreshold, cfg.Options.ListenerHealthURL, prometheus.DefaultRegisterer)
+		statusz = cfg.Freshness
+	}
+
+	var memStats nethttp.Handler
+	if cfg.Options.DebugMemStats {
+		memStats = memory.NewHandler(gatewayServer.Registry().SchemaSizes)
+	}
+
+	var publicURL *url.URL
+	if cfg.Options.PublicURL != "" {
+		parsed, err := url.Parse(cfg.Options.PublicURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public URL: %w", err)
+		}
+		publicURL = parsed
+	}
+
+	var persistedQueries *middleware.PersistedQueryCache
+	if cfg.Options.PersistedQueriesCacheSize > 0 {
+		persistedQueries = middleware.NewPersistedQueryCache(cfg.Options.PersistedQueriesCacheSize)
+	}
 
 	httpServer, err := http.NewServer(http.ServerConfig{
 		Gateway:                  gatewayServer,
@@ -61,11 +192,25 @@ func NewConfig(opts *options.CompletedOptions) (*Config, error) {
 		ReadHeaderTimeout:        cfg.Options.ReadHeaderTimeout,
 		IdleTimeout:              cfg.Options.IdleTimeout,
 		EndpointSuffix:           cfg.Options.EndpointSuffix,
+		PublicURL:                publicURL,
+		PathPrefix:               cfg.Options.PathPrefix,
+		Mirror: middleware.MirrorConfig{
+			TargetURL:  cfg.Options.MirrorURL,
+			Percentage: cfg.Options.MirrorPercentage,
+			Timeout:    cfg.Options.RequestTimeout,
+		},
 		SubscriptionMetrics: &middleware.InFlightMetrics{
 			Active:   subMetrics.Active,
 			Total:    subMetrics.Total,
 			Rejected: subMetrics.Rejected,
 		},
+		RequestMetrics: &middleware.RequestMetrics{
+			Total:    requestMetrics.Total,
+			Duration: requestMetrics.Duration,
+		},
+		PersistedQueries: persistedQueries,
+		Statusz:          statusz,
+		MemStats:         memStats,
 		CORSConfig: http.CORSConfig{
 			AllowedOrigins:   cfg.Options.CORSAllowedOrigins,
 			AllowedHeaders:   cfg.Options.CORSAllowedHeaders,
@@ -0,0 +1,331 @@
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/changelog"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/cluster"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Severity classifies the outcome of a single check.
+type Severity string
+
+const (
+	SeverityOK   Severity = "OK"
+	SeverityWarn Severity = "WARN"
+	SeverityFail Severity = "FAIL"
+)
+
+// Check names reported in findings.
+const (
+	CheckSchemasDir    = "schemas-dir"
+	CheckSchemaParse   = "schema-parse"
+	CheckClusterReach  = "cluster-reachable"
+	CheckProbeAccess   = "probe-access"
+	CheckSubscriptions = "subscriptions"
+)
+
+// Finding is the result of a single diagnostic check.
+type Finding struct {
+	Check    string
+	Cluster  string
+	Severity Severity
+	Message  string
+	// Hint suggests how to fix the problem. Empty for successful checks.
+	Hint string
+}
+
+// Config holds the inputs for a diagnostic run.
+type Config struct {
+	// SchemasDir is the directory the gateway reads schema files from.
+	SchemasDir string
+
+	// ProbeUser is the identity used for SubjectAccessReview checks.
+	// Empty skips the probe-access check.
+	ProbeUser string
+
+	// ProbeGroups are the groups of ProbeUser.
+	ProbeGroups []string
+
+	// ProbeResource is the resource ProbeUser must be able to list and watch.
+	ProbeResource string
+
+	// Timeout bounds every network check against a single cluster.
+	Timeout time.Duration
+}
+
+// Run executes all checks and returns the findings in the order they were produced.
+func Run(ctx context.Context, cfg Config) []Finding {
+	var findings []Finding
+
+	files, finding := listSchemaFiles(cfg.SchemasDir)
+	findings = append(findings, finding)
+	if finding.Severity == SeverityFail {
+		return findings
+	}
+
+	for _, file := range files {
+		findings = append(findings, checkCluster(ctx, cfg, file)...)
+	}
+
+	return findings
+}
+
+// HasFailures reports whether any finding failed.
+func HasFailures(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Print writes findings in a human-readable form followed by a summary line.
+func Print(w io.Writer, findings []Finding) {
+	counts := map[Severity]int{}
+	for _, f := range findings {
+		counts[f.Severity]++
+
+		scope := ""
+		if f.Cluster != "" {
+			scope = fmt.Sprintf(" [%s]", f.Cluster)
+		}
+		fmt.Fprintf(w, "%-4s %s%s: %s\n", f.Severity, f.Check, scope, f.Message) //nolint:errcheck
+		if f.Hint != "" {
+			fmt.Fprintf(w, "     hint: %s\n", f.Hint) //nolint:errcheck
+		}
+	}
+	fmt.Fprintf(w, "\n%d ok, %d warnings, %d failures\n", counts[SeverityOK], counts[SeverityWarn], counts[SeverityFail]) //nolint:errcheck
+}
+
+func listSchemaFiles(dir string) ([]string, Finding) {
+	finding := Finding{Check: CheckSchemasDir}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		finding.Severity = SeverityFail
+		finding.Message = fmt.Sprintf("cannot access %s: %v", dir, err)
+		finding.Hint = "check --schemas-dir and that the volume shared with the listener is mounted"
+		return nil, finding
+	}
+	if !info.IsDir() {
+		finding.Severity = SeverityFail
+		finding.Message = fmt.Sprintf("%s is not a directory", dir)
+		finding.Hint = "--schemas-dir must point to the directory the listener writes schemas into"
+		return nil, finding
+	}
+
+	var files []string
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path == filepath.Join(dir, changelog.Dir) {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		finding.Severity = SeverityFail
+		finding.Message = fmt.Sprintf("cannot read %s: %v", dir, err)
+		finding.Hint = "the gateway needs read permission on the schema directory and all files in it"
+		return nil, finding
+	}
+
+	if len(files) == 0 {
+		finding.Severity = SeverityWarn
+		finding.Message = fmt.Sprintf("%s contains no schema files", dir)
+		finding.Hint = "check that the listener is running and writes to the same directory"
+		return nil, finding
+	}
+
+	finding.Severity = SeverityOK
+	finding.Message = fmt.Sprintf("found %d schema file(s) in %s", len(files), dir)
+	return files, finding
+}
+
+func checkCluster(ctx context.Context, cfg Config, path string) []Finding {
+	name := filepath.Base(path)
+
+	schemaData, finding := parseSchemaFile(ctx, name, path)
+	findings := []Finding{finding}
+	if finding.Severity == SeverityFail {
+		return findings
+	}
+
+	cl, err := cluster.New(ctx, name, schemaData.ClusterMetadata)
+	if err != nil {
+		return append(findings, Finding{
+			Check:    CheckClusterReach,
+			Cluster:  name,
+			Severity: SeverityFail,
+			Message:  fmt.Sprintf("cannot build client from stored credentials: %v", err),
+			Hint:     "the x-cluster-metadata auth or CA data is invalid; check the ClusterAccess secrets",
+		})
+	}
+	defer cl.Close()
+
+	adminCfg := cl.AdminConfig()
+	if cfg.Timeout > 0 {
+		adminCfg.Timeout = cfg.Timeout
+	}
+	cs, err := kubernetes.NewForConfig(adminCfg)
+	if err != nil {
+		return append(findings, Finding{
+			Check:    CheckClusterReach,
+			Cluster:  name,
+			Severity: SeverityFail,
+			Message:  fmt.Sprintf("cannot build clientset: %v", err),
+		})
+	}
+
+	version, err := cs.Discovery().ServerVersion()
+	if err != nil {
+		return append(findings, Finding{
+			Check:    CheckClusterReach,
+			Cluster:  name,
+			Severity: SeverityFail,
+			Message:  fmt.Sprintf("%s is not reachable: %v", schemaData.ClusterMetadata.Host, err),
+			Hint:     "check network policies, DNS and TLS settings, and that the stored credentials have not expired",
+		})
+	}
+	findings = append(findings, Finding{
+		Check:    CheckClusterReach,
+		Cluster:  name,
+		Severity: SeverityOK,
+		Message:  fmt.Sprintf("%s reachable (Kubernetes %s)", schemaData.ClusterMetadata.Host, version.GitVersion),
+	})
+
+	if cfg.ProbeUser != "" {
+		findings = append(findings, checkProbeAccess(ctx, cfg, name, cs))
+	}
+
+	return append(findings, checkSubscriptions(ctx, cfg, name, cs))
+}
+
+func parseSchemaFile(ctx context.Context, name, path string) (*v1alpha1.Schema, Finding) {
+	finding := Finding{Check: CheckSchemaParse, Cluster: name, Severity: SeverityFail}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		finding.Message = fmt.Sprintf("cannot read %s: %v", path, err)
+		finding.Hint = "the gateway needs read permission on schema files"
+		return nil, finding
+	}
+
+	var schemaData v1alpha1.Schema
+	if err := json.Unmarshal(data, &schemaData); err != nil {
+		finding.Message = fmt.Sprintf("invalid JSON: %v", err)
+		finding.Hint = "the file may be truncated or not written by the listener; delete it and let the listener regenerate it"
+		return nil, finding
+	}
+
+	if schemaData.ClusterMetadata == nil {
+		finding.Message = "schema has no x-cluster-metadata"
+		finding.Hint = "upgrade the listener; schemas without cluster metadata cannot be served"
+		return nil, finding
+	}
+
+	if schemaData.Components == nil || len(schemaData.Components.Schemas) == 0 {
+		finding.Message = "schema has no component definitions"
+		finding.Hint = "the listener could not read the cluster's OpenAPI document; check its logs and RBAC"
+		return nil, finding
+	}
+
+	if err := extensions.ValidateDocument(&schemaData); err != nil {
+		finding.Message = fmt.Sprintf("schema does not follow the schema document contract: %v", err)
+		finding.Hint = "upgrade the gateway if the listener is newer; otherwise fix the tool that wrote the schema file"
+		return nil, finding
+	}
+
+	if _, err := schema.New(ctx, schemaData.Components.Schemas, resolver.New(nil), nil); err != nil {
+		finding.Message = fmt.Sprintf("GraphQL schema generation failed: %v", err)
+		finding.Hint = "report this as a bug together with the schema file"
+		return nil, finding
+	}
+
+	finding.Severity = SeverityOK
+	finding.Message = fmt.Sprintf("%d definitions, GraphQL schema generated", len(schemaData.Components.Schemas))
+	return &schemaData, finding
+}
+
+func checkProbeAccess(ctx context.Context, cfg Config, name string, cs kubernetes.Interface) Finding {
+	finding := Finding{Check: CheckProbeAccess, Cluster: name}
+
+	var denied []string
+	for _, verb := range []string{"list", "watch"} {
+		sar := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:   cfg.ProbeUser,
+				Groups: cfg.ProbeGroups,
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Verb:     verb,
+					Resource: cfg.ProbeResource,
+				},
+			},
+		}
+
+		result, err := cs.AuthorizationV1().SubjectAccessReviews().Create(ctx, sar, metav1.CreateOptions{})
+		if err != nil {
+			finding.Severity = SeverityFail
+			finding.Message = fmt.Sprintf("SubjectAccessReview failed: %v", err)
+			finding.Hint = "the stored credentials need permission to create subjectaccessreviews"
+			return finding
+		}
+		if !result.Status.Allowed {
+			denied = append(denied, verb)
+		}
+	}
+
+	if len(denied) > 0 {
+		finding.Severity = SeverityFail
+		finding.Message = fmt.Sprintf("user %q may not %v %s", cfg.ProbeUser, denied, cfg.ProbeResource)
+		finding.Hint = "grant the probe identity a (Cluster)Role with list and watch on the resource"
+		return finding
+	}
+
+	finding.Severity = SeverityOK
+	finding.Message = fmt.Sprintf("user %q may list and watch %s", cfg.ProbeUser, cfg.ProbeResource)
+	return finding
+}
+
+func checkSubscriptions(ctx context.Context, cfg Config, name string, cs kubernetes.Interface) Finding {
+	finding := Finding{Check: CheckSubscriptions, Cluster: name}
+
+	timeoutSeconds := int64(1)
+	if cfg.Timeout > time.Second {
+		timeoutSeconds = int64(cfg.Timeout.Seconds())
+	}
+
+	w, err := cs.CoreV1().Namespaces().Watch(ctx, metav1.ListOptions{Limit: 1, TimeoutSeconds: &timeoutSeconds})
+	if err != nil {
+		finding.Severity = SeverityFail
+		finding.Message = fmt.Sprintf("cannot open a watch: %v", err)
+		finding.Hint = "proxies in front of the API server must allow long-lived streaming responses"
+		return finding
+	}
+	w.Stop()
+
+	finding.Severity = SeverityOK
+	finding.Message = "watch established"
+	return finding
+}
@@ -0,0 +1,177 @@
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+func writeSchema(t *testing.T, dir, name, host string) {
+	t.Helper()
+	schema := map[string]any{
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"io.k8s.api.core.v1.ConfigMap": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"data": map[string]any{"type": "string"},
+					},
+					extensions.GVKKey:   []any{map[string]any{"group": "", "version": "v1", "kind": "ConfigMap"}},
+					extensions.ScopeKey: "Namespaced",
+				},
+			},
+		},
+		"x-cluster-metadata": map[string]any{
+			"host": host,
+			"auth": map[string]any{"type": "token", "token": "dG9rZW4="},
+		},
+	}
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+}
+
+func fakeAPIServer(t *testing.T, allowed bool) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/version", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"gitVersion":"v1.33.0"}`)) //nolint:errcheck
+	})
+	mux.HandleFunc("/apis/authorization.k8s.io/v1/subjectaccessreviews", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(authorizationv1.SubjectAccessReview{ //nolint:errcheck
+			Status: authorizationv1.SubjectAccessReviewStatus{Allowed: allowed},
+		})
+	})
+	mux.HandleFunc("/api/v1/namespaces", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func findingFor(findings []Finding, check string) (Finding, bool) {
+	for _, f := range findings {
+		if f.Check == check {
+			return f, true
+		}
+	}
+	return Finding{}, false
+}
+
+func TestRun_SchemasDir(t *testing.T) {
+	t.Run("missing directory fails", func(t *testing.T) {
+		findings := Run(t.Context(), Config{SchemasDir: filepath.Join(t.TempDir(), "missing")})
+		require.Len(t, findings, 1)
+		assert.Equal(t, SeverityFail, findings[0].Severity)
+		assert.NotEmpty(t, findings[0].Hint)
+		assert.True(t, HasFailures(findings))
+	})
+
+	t.Run("empty directory warns", func(t *testing.T) {
+		findings := Run(t.Context(), Config{SchemasDir: t.TempDir()})
+		require.Len(t, findings, 1)
+		assert.Equal(t, SeverityWarn, findings[0].Severity)
+		assert.False(t, HasFailures(findings))
+	})
+}
+
+func TestRun_SchemaParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantMsg string
+	}{
+		{name: "invalid json", content: `{`, wantMsg: "invalid JSON"},
+		{name: "missing metadata", content: `{"components":{"schemas":{"a":{}}}}`, wantMsg: "no x-cluster-metadata"},
+		{name: "missing components", content: `{"x-cluster-metadata":{"host":"https://example"}}`, wantMsg: "no component definitions"},
+		{name: "unsupported version", content: `{"components":{"schemas":{"a":{}}},"x-cluster-metadata":{"host":"https://example"},"x-schema-version":"v2"}`, wantMsg: "does not follow the schema document contract"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "cluster"), []byte(tt.content), 0o600))
+
+			findings := Run(t.Context(), Config{SchemasDir: dir})
+
+			f, ok := findingFor(findings, CheckSchemaParse)
+			require.True(t, ok)
+			assert.Equal(t, SeverityFail, f.Severity)
+			assert.Equal(t, "cluster", f.Cluster)
+			assert.Contains(t, f.Message, tt.wantMsg)
+		})
+	}
+}
+
+func TestRun_Cluster(t *testing.T) {
+	t.Run("reachable cluster with permitted probe", func(t *testing.T) {
+		server := fakeAPIServer(t, true)
+		dir := t.TempDir()
+		writeSchema(t, dir, "root", server.URL)
+
+		findings := Run(t.Context(), Config{SchemasDir: dir, ProbeUser: "alice", ProbeResource: "namespaces", Timeout: time.Second})
+
+		for _, check := range []string{CheckSchemasDir, CheckSchemaParse, CheckClusterReach, CheckProbeAccess, CheckSubscriptions} {
+			f, ok := findingFor(findings, check)
+			require.True(t, ok, check)
+			assert.Equal(t, SeverityOK, f.Severity, "%s: %s", check, f.Message)
+		}
+	})
+
+	t.Run("denied probe fails", func(t *testing.T) {
+		server := fakeAPIServer(t, false)
+		dir := t.TempDir()
+		writeSchema(t, dir, "root", server.URL)
+
+		findings := Run(t.Context(), Config{SchemasDir: dir, ProbeUser: "alice", ProbeResource: "namespaces", Timeout: time.Second})
+
+		f, ok := findingFor(findings, CheckProbeAccess)
+		require.True(t, ok)
+		assert.Equal(t, SeverityFail, f.Severity)
+		assert.Contains(t, f.Message, "alice")
+	})
+
+	t.Run("unreachable cluster fails", func(t *testing.T) {
+		server := fakeAPIServer(t, true)
+		server.Close()
+		dir := t.TempDir()
+		writeSchema(t, dir, "root", server.URL)
+
+		findings := Run(t.Context(), Config{SchemasDir: dir, Timeout: time.Second})
+
+		f, ok := findingFor(findings, CheckClusterReach)
+		require.True(t, ok)
+		assert.Equal(t, SeverityFail, f.Severity)
+		_, ok = findingFor(findings, CheckSubscriptions)
+		assert.False(t, ok)
+	})
+}
+
+func TestPrint(t *testing.T) {
+	var buf bytes.Buffer
+	Print(&buf, []Finding{
+		{Check: CheckSchemasDir, Severity: SeverityOK, Message: "found 1 schema file(s)"},
+		{Check: CheckClusterReach, Cluster: "root", Severity: SeverityFail, Message: "not reachable", Hint: "check DNS"},
+	})
+
+	out := buf.String()
+	assert.Contains(t, out, "OK   schemas-dir: found 1 schema file(s)")
+	assert.Contains(t, out, "FAIL cluster-reachable [root]: not reachable")
+	assert.Contains(t, out, "hint: check DNS")
+	assert.Contains(t, out, "1 ok, 0 warnings, 1 failures")
+}
@@ -6,12 +6,16 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/kubectl"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/roundtripper"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/roundtripper/union"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/warnings"
 
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
@@ -23,6 +27,7 @@ type Cluster struct {
 	client   client.WithWatch
 	restCfg  *rest.Config
 	adminCfg *rest.Config
+	health   *healthTracker
 }
 
 // New creates a new Cluster connection from cluster metadata.
@@ -54,6 +59,15 @@ func New(
 		return roundtripper.NewPathTemplateHandler(rt, tpl, basePath)
 	})
 
+	// Track the health of the cluster from the calls made with the admin
+	// credentials, e.g. token reviews and discovery.
+	cluster.health = &healthTracker{now: time.Now}
+	cluster.adminCfg.Wrap(cluster.health.wrap)
+	cluster.health.client, err = discovery.NewDiscoveryClientForConfig(cluster.adminCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
 	tlsConfig := cluster.restCfg.TLSClientConfig
 	baseRT, err := roundtripper.NewBaseRoundTripper(tlsConfig)
 	if err != nil {
@@ -68,6 +82,14 @@ func New(
 		)
 	})
 
+	// Record the requests of operations asking for their kubectl equivalents.
+	cluster.restCfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return kubectl.NewTransport(rt)
+	})
+
+	// Collect API server warnings per GraphQL operation instead of only logging them.
+	cluster.restCfg.WarningHandlerWithContext = warnings.Handler{}
+
 	var mapper meta.RESTMapper
 	if metadata.IntrospectionPath != "" {
 		mapper, err = restMapperFromConfig(cluster.adminCfg, metadata.IntrospectionPath)
@@ -103,6 +125,12 @@ func (c *Cluster) AdminConfig() *rest.Config {
 	return rest.CopyConfig(c.adminCfg)
 }
 
+// Health returns the health of the cluster's API server, probing it if no
+// call was made with the admin credentials recently.
+func (c *Cluster) Health(ctx context.Context) Health {
+	return c.health.get(ctx)
+}
+
 func (c *Cluster) Close() {
 	c.client = nil
 	c.adminCfg = nil
@@ -0,0 +1,134 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+)
+
+const (
+	// probeInterval is how long the result of a probe is reused.
+	probeInterval = 30 * time.Second
+	// probeTimeout bounds a probe of an unresponsive API server.
+	probeTimeout = 5 * time.Second
+)
+
+// Health is the state of a cluster's API server as seen with the gateway's
+// admin credentials.
+type Health struct {
+	// Reachable reports whether the last call got a response.
+	Reachable bool
+	// CredentialsValid reports whether the last response did not reject the
+	// credentials.
+	CredentialsValid bool
+	// LastSuccess is when a call last succeeded, zero if none did.
+	LastSuccess time.Time
+	// ServerVersion is the version of the API server, empty until a probe
+	// succeeded.
+	ServerVersion string
+	// Error is the error of the last call, empty if it succeeded.
+	Error string
+}
+
+// healthTracker records the outcome of the calls made with the admin
+// credentials and probes the API server version when asked for the health of
+// a cluster without recent calls.
+type healthTracker struct {
+	client discovery.DiscoveryInterface
+	now    func() time.Time
+
+	mu       sync.Mutex
+	health   Health
+	lastCall time.Time
+	probed   time.Time
+}
+
+// wrap returns a roundtripper recording the outcome of the requests of rt.
+func (t *healthTracker) wrap(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := rt.RoundTrip(req)
+		switch {
+		case err != nil:
+			if !errors.Is(err, context.Canceled) {
+				t.record(false, false, err.Error())
+			}
+		case resp.StatusCode == http.StatusUnauthorized:
+			t.record(true, false, "credentials were rejected: "+http.StatusText(resp.StatusCode))
+		case resp.StatusCode < http.StatusInternalServerError:
+			t.record(true, true, "")
+		default:
+			t.record(true, true, http.StatusText(resp.StatusCode))
+		}
+		return resp, err
+	})
+}
+
+func (t *healthTracker) record(reachable, credentialsValid bool, errMsg string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastCall = t.now()
+	t.health.Reachable = reachable
+	t.health.Error = errMsg
+	// Unreachable API servers tell nothing about the credentials.
+	if reachable {
+		t.health.CredentialsValid = credentialsValid
+	}
+	if reachable && credentialsValid && errMsg == "" {
+		t.health.LastSuccess = t.lastCall
+	}
+}
+
+// get returns the health of the cluster, probing the API server version
+// first if it was not probed and no call was made within the probe interval.
+func (t *healthTracker) get(ctx context.Context) Health {
+	t.mu.Lock()
+	now := t.now()
+	fresh := now.Sub(t.probed) < probeInterval || (t.health.ServerVersion != "" && now.Sub(t.lastCall) < probeInterval)
+	t.mu.Unlock()
+
+	if !fresh {
+		t.probe(ctx)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.health
+}
+
+// probe requests the version of the API server. Its outcome is recorded by
+// the roundtripper wrapping the admin config.
+func (t *healthTracker) probe(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	var info version.Info
+	body, err := t.client.RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	if err == nil {
+		err = json.Unmarshal(body, &info)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.probed = t.now()
+	if info.GitVersion != "" {
+		t.health.ServerVersion = info.GitVersion
+	}
+	// Errors the roundtripper does not see, e.g. of a malformed response.
+	if err != nil && !apierrors.IsUnauthorized(err) && t.health.Error == "" {
+		t.health.Error = err.Error()
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,62 @@
+package cluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+func TestHealth(t *testing.T) {
+	var unauthorized atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if unauthorized.Load() {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"gitVersion":"v1.33.1"}`)) //nolint:errcheck
+	}))
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := &healthTracker{now: func() time.Time { return now }}
+	cfg := &rest.Config{Host: server.URL}
+	cfg.Wrap(tracker.wrap)
+	var err error
+	tracker.client, err = discovery.NewDiscoveryClientForConfig(cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, Health{
+		Reachable:        true,
+		CredentialsValid: true,
+		LastSuccess:      now,
+		ServerVersion:    "v1.33.1",
+	}, tracker.get(t.Context()))
+
+	// Recent probes are reused.
+	unauthorized.Store(true)
+	now = now.Add(time.Second)
+	assert.True(t, tracker.get(t.Context()).CredentialsValid)
+
+	now = now.Add(probeInterval)
+	health := tracker.get(t.Context())
+	assert.True(t, health.Reachable)
+	assert.False(t, health.CredentialsValid)
+	assert.Equal(t, now.Add(-probeInterval-time.Second), health.LastSuccess)
+	assert.Equal(t, "v1.33.1", health.ServerVersion)
+	assert.NotEmpty(t, health.Error)
+
+	server.Close()
+	now = now.Add(probeInterval)
+	health = tracker.get(t.Context())
+	assert.False(t, health.Reachable)
+	assert.False(t, health.CredentialsValid, "unreachable servers keep the credential state")
+	assert.NotEmpty(t, health.Error)
+}
@@ -3,7 +3,14 @@ package config
 import (
 	"time"
 
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/changefeed"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/authn"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/queryvalidation"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/kindalias"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/savedqueries"
+	schematypes "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/smoketest"
 )
 
 // Gateway holds the complete gateway service configuration.
@@ -44,6 +51,44 @@ type Gateway struct {
 	// Start on TokenReviewValidator). The same Validator instance is shared
 	// across all endpoints.
 	Validator authn.Validator
+
+	// ChangeFeed records mutations executed through the gateway and backs the
+	// recentChanges query. When nil (the default), the query is not exposed.
+	// The same Feed is shared across all endpoints; changes are kept apart
+	// by cluster.
+	ChangeFeed *changefeed.Feed
+
+	// SavedQueries stores the named queries users save through the
+	// saveQuery mutation. When nil (the default), the saved query operations
+	// are not exposed. Queries are kept apart by cluster.
+	SavedQueries *savedqueries.Store
+
+	// SmokeTests runs configured operations against each endpoint after its
+	// schema was loaded and marks the cluster degraded when they fail. When
+	// nil (the default), no smoke tests run.
+	SmokeTests *smoketest.Runner
+
+	// Canary configures gradual rollouts of updated schemas.
+	Canary Canary
+}
+
+// Canary holds the configuration of canary schema rollouts. While a canary
+// runs, the previous schema keeps serving most requests, and the new one is
+// promoted once its share of failed requests stayed close to that of the
+// previous one.
+type Canary struct {
+	// Duration is how long a new schema is served as a canary before it is
+	// promoted or rolled back. 0 disables canaries; new schemas replace the
+	// current one immediately.
+	Duration time.Duration
+
+	// Percentage is the percentage (0-100) of requests routed to the canary.
+	// Requests can pick a schema with the canary header regardless.
+	Percentage float64
+
+	// MaxErrorIncrease is how much higher (0-1) the canary's share of failed
+	// requests may be than the current schema's for it to be promoted.
+	MaxErrorIncrease float64
 }
 
 // GraphQL holds GraphQL handler configuration.
@@ -51,8 +96,102 @@ type GraphQL struct {
 	Pretty            bool
 	PlaygroundEnabled bool
 	GraphiQL          bool
+
+	// SubscriptionMaxLifetime is how long an SSE subscription may run before
+	// the gateway ends it, unless the client renews it. 0 disables the limit.
+	SubscriptionMaxLifetime time.Duration
+
+	// SubscriptionExpiryWarning is how long before the end of its lifetime a
+	// subscription receives an "expiring" event.
+	SubscriptionExpiryWarning time.Duration
+
+	// EventLimits merge the subscription events of an object of a kind
+	// arriving within its interval, reporting merged events to
+	// EventsDropped.
+	EventLimits   resolver.EventLimits
+	EventsDropped resolver.DroppedEventsFunc
+
+	// SSEKeepaliveInterval is how often a comment is sent to SSE clients
+	// so proxies do not close quiet subscriptions as idle. 0 disables it.
+	SSEKeepaliveInterval time.Duration
+
+	// SSEWriteTimeout bounds writing an SSE event to a client. Clients not
+	// reading their events are disconnected when it is exceeded. 0
+	// disables it.
+	SSEWriteTimeout time.Duration
+
+	// BatchConcurrency is how many operations of a batched request are
+	// executed at the same time.
+	BatchConcurrency int
+
+	// LiveQueryInterval is how often a query marked with @live is re-executed
+	// to detect changes.
+	LiveQueryInterval time.Duration
+
+	// ExposeManagedFields keeps metadata.managedFields in the schema. It is
+	// left out by default as it is large and only useful to apply tooling.
+	ExposeManagedFields bool
+
+	// EmptyValues selects how empty and absent list and map fields of
+	// objects are returned.
+	EmptyValues resolver.EmptyValues
+
+	// FieldCasing selects how the fields of objects are named. Input
+	// objects accept the same names.
+	FieldCasing schematypes.FieldCasing
+
+	// Federation adds the queries and types of an Apollo Federation
+	// subgraph to the schemas.
+	Federation bool
+
+	// KindAliases serve renamed kinds under their former names on the
+	// clusters they apply to.
+	KindAliases []kindalias.Alias
+
+	// Introspection selects who may query __schema and __type.
+	Introspection Introspection
+
+	// RBACSchemaPruning serves each user a schema without the kinds they
+	// may not list, according to a SelfSubjectRulesReview in
+	// RBACSchemaPruningNamespace.
+	RBACSchemaPruning          bool
+	RBACSchemaPruningNamespace string
+
+	// HiddenMaturities leaves the kinds tagged with these maturities, e.g.
+	// alpha, out of the schema. Members of HiddenMaturitiesGroups are
+	// served them anyway.
+	HiddenMaturities       []string
+	HiddenMaturitiesGroups []string
+
+	// ClusterRegistrationTTL enables the registerCluster mutation, serving
+	// clusters registered by members of ClusterRegistrationGroups for at
+	// most this long. 0 disables it. The groups are reviewed on
+	// ClusterRegistrationHomeCluster, a cluster served from the listener's
+	// schemas.
+	ClusterRegistrationTTL         time.Duration
+	ClusterRegistrationGroups      []string
+	ClusterRegistrationHomeCluster string
+
+	// OperationAllowlist restricts the executed queries to registered
+	// documents. nil allows all queries.
+	OperationAllowlist *queryvalidation.Allowlist
 }
 
+// Introspection selects who may run introspection queries.
+type Introspection string
+
+const (
+	// IntrospectionEnabled allows introspection to everyone reaching the
+	// endpoint, including unauthenticated GraphiQL requests.
+	IntrospectionEnabled Introspection = "enabled"
+	// IntrospectionAuthenticated allows introspection to requests with a
+	// valid token only.
+	IntrospectionAuthenticated Introspection = "authenticated"
+	// IntrospectionDisabled rejects introspection queries and schema
+	// downloads for everyone.
+	IntrospectionDisabled Introspection = "disabled"
+)
+
 // Limits holds query validation limits enforced at the GraphQL layer.
 // HTTP-level limits (body size, in-flight concurrency) live in http.ServerConfig.
 type Limits struct {
@@ -68,4 +207,16 @@ type Limits struct {
 	// MaxQueryBatchSize is the maximum number of queries allowed in a single batched request.
 	// 0 disables the limit.
 	MaxQueryBatchSize int
+
+	// MaxLogLines is the maximum number of lines a podLogsPage query returns.
+	// Larger requested limits are capped. 0 disables the limit.
+	MaxLogLines int
+
+	// MaxLogBytes is the maximum number of bytes of the lines of a page a
+	// podLogsPage query returns. 0 disables the limit.
+	MaxLogBytes int64
+
+	// MaxEvents is the maximum number of events an events field returns.
+	// Larger requested limits are capped. 0 disables the limit.
+	MaxEvents int
 }
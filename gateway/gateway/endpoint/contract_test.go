@@ -0,0 +1,89 @@
+package endpoint
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/contract"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// TestContract verifies that the gateway serves the schema document the
+// listener is tested to write in apischema/contract.
+func TestContract(t *testing.T) {
+	contract.AssertDocument(t, contract.Golden)
+
+	schemaData, err := parseSchema(contract.Golden)
+	require.NoError(t, err)
+
+	cfg, err := v1alpha1.BuildRestConfigFromMetadata(*schemaData.ClusterMetadata)
+	require.NoError(t, err)
+	assert.Equal(t, contract.Metadata.Host, cfg.Host)
+	assert.Equal(t, "token", cfg.BearerToken)
+	assert.Equal(t, []byte("ca"), cfg.CAData)
+
+	provider, err := schema.New(t.Context(), schemaData.Components.Schemas, resolver.New(nil), nil)
+	require.NoError(t, err)
+	gqlSchema := provider.GetSchema()
+
+	for _, r := range contract.Resources {
+		t.Run(r.GVK.Kind, func(t *testing.T) {
+			query := versionType(t, gqlSchema.QueryType(), r.GVK.Group, r.GVK.Version)
+			mutation := versionType(t, gqlSchema.MutationType(), r.GVK.Group, r.GVK.Version)
+
+			item, ok := query.Fields()[r.GVK.Kind]
+			require.True(t, ok, "%s query", r.GVK.Kind)
+			hasNamespace := false
+			for _, arg := range item.Args {
+				if arg.Name() == resolver.NamespaceArg {
+					hasNamespace = true
+				}
+			}
+			assert.Equal(t, r.Scope == apiextensionsv1.NamespaceScoped, hasNamespace, "namespace argument follows the scope")
+
+			for _, name := range []string{"apply" + r.GVK.Kind, "deleteAll" + r.GVK.Kind} {
+				_, ok = mutation.Fields()[name]
+				assert.True(t, ok, "%s mutation", name)
+			}
+			_, ok = mutation.Fields()["update"+r.GVK.Kind+"Status"]
+			assert.Equal(t, slices.Contains(r.Subresources, "status"), ok, "status mutation follows the subresources")
+			_, ok = mutation.Fields()["scale"+r.GVK.Kind]
+			assert.Equal(t, slices.Contains(r.Subresources, "scale"), ok, "scale mutation follows the subresources")
+		})
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        *gqlSchema,
+		RequestString: `{ typeByCategory(name: "example") { group version kind scope } }`,
+		Context:       t.Context(),
+	})
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string]any{"typeByCategory": []any{
+		map[string]any{"group": "example.com", "version": "v1", "kind": "Widget", "scope": "Namespaced"},
+	}}, result.Data)
+}
+
+// versionType returns the type holding the fields of a group version below
+// a root type, e.g. Query.example_com.v1.
+func versionType(t *testing.T, root *graphql.Object, group, version string) *graphql.Object {
+	t.Helper()
+
+	groupField, ok := root.Fields()[types.SanitizeGroupName(group)]
+	require.True(t, ok, "%s.%s", root.Name(), group)
+	groupType, ok := groupField.Type.(*graphql.Object)
+	require.True(t, ok)
+
+	versionField, ok := groupType.Fields()[version]
+	require.True(t, ok, "%s.%s.%s", root.Name(), group, version)
+	versionType, ok := versionField.Type.(*graphql.Object)
+	require.True(t, ok)
+	return versionType
+}
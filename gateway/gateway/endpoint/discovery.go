@@ -0,0 +1,49 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+)
+
+// DiscoveryFile is the schema discovery document served below the endpoint
+// path, listing the externally reachable URLs of the endpoint.
+const DiscoveryFile = "discovery.json"
+
+// Discovery is the schema discovery document of an endpoint.
+type Discovery struct {
+	// Endpoint is the URL of queries and mutations.
+	Endpoint string `json:"endpoint"`
+	// SubscriptionEndpoint is the URL of subscriptions over Server-Sent
+	// Events, requested with "Accept: text/event-stream".
+	SubscriptionEndpoint string `json:"subscriptionEndpoint"`
+	// Schema holds the URLs of the schema files, omitted if introspection
+	// is disabled.
+	Schema *DiscoverySchema `json:"schema,omitempty"`
+}
+
+// DiscoverySchema holds the URLs of the schema files of an endpoint.
+type DiscoverySchema struct {
+	SDL           string `json:"sdl"`
+	Introspection string `json:"introspection"`
+}
+
+// serveDiscovery writes the discovery document of the requested endpoint.
+func serveDiscovery(w http.ResponseWriter, r *http.Request, introspection bool) {
+	endpointURL, ok := utilscontext.GetEndpointURLFromCtx(r.Context())
+	if !ok {
+		http.Error(w, "endpoint URL is unknown", http.StatusInternalServerError)
+		return
+	}
+
+	discovery := Discovery{Endpoint: endpointURL, SubscriptionEndpoint: endpointURL}
+	if introspection {
+		discovery.Schema = &DiscoverySchema{
+			SDL:           endpointURL + "/" + SDLFile,
+			Introspection: endpointURL + "/" + IntrospectionFile,
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(discovery)
+}
@@ -0,0 +1,41 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeDiscovery(t *testing.T) {
+	const endpointURL = "https://api.example.com/k8s/api/clusters/test/graphql"
+
+	get := func(introspection bool) (*httptest.ResponseRecorder, Discovery) {
+		r := httptest.NewRequest(http.MethodGet, "/api/clusters/test/graphql/"+DiscoveryFile, nil)
+		r = r.WithContext(utilscontext.SetEndpointURL(r.Context(), endpointURL))
+		w := httptest.NewRecorder()
+		serveDiscovery(w, r, introspection)
+
+		var discovery Discovery
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &discovery))
+		return w, discovery
+	}
+
+	w, discovery := get(true)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, Discovery{
+		Endpoint:             endpointURL,
+		SubscriptionEndpoint: endpointURL,
+		Schema: &DiscoverySchema{
+			SDL:           endpointURL + "/schema.graphql",
+			Introspection: endpointURL + "/schema.json",
+		},
+	}, discovery)
+
+	_, discovery = get(false)
+	assert.Nil(t, discovery.Schema)
+}
@@ -3,22 +3,38 @@ package endpoint
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
+	graphqlgo "github.com/graphql-go/graphql"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
+	openapiext "github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/changefeed"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/authn"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/cluster"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/config"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/kubectl"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/maturity"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/queryvalidation"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/rbacschema"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/requestparser"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/routing"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/warnings"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/kindalias"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/savedqueries"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/extensions"
+	schematypes "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
 	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/recovery"
 
+	"k8s.io/client-go/rest"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -29,6 +45,17 @@ type Endpoint struct {
 	graphqlServer *graphql.GraphQLServer
 	handler       http.Handler
 	cancelFunc    context.CancelFunc
+	schemaSize    SchemaSize
+	categories    map[string][]resolver.TypeByCategory
+}
+
+// SchemaSize is the size of the schema of an endpoint, which the memory it
+// takes grows with.
+type SchemaSize struct {
+	// Bytes is the size of the schema document.
+	Bytes int `json:"schemaBytes"`
+	// Types is the number of types of the GraphQL schema served by default.
+	Types int `json:"types"`
 }
 
 func New(
@@ -39,6 +66,11 @@ func New(
 	limits config.Limits,
 	tokenReviewCacheTTL time.Duration,
 	injectedValidator authn.Validator,
+	changes *changefeed.Feed,
+	savedQueries *savedqueries.Store,
+	categories *resolver.CategoryFeed,
+	clusterStatus resolver.ClusterStatusFunc,
+	registration *resolver.ClusterRegistration,
 ) (*Endpoint, error) {
 	schemaData, err := parseSchema(schemaJSON)
 	if err != nil {
@@ -66,36 +98,157 @@ func New(
 		validatorCancel = trCancel
 	}
 
-	resolverProvider := resolver.New(cl.Client())
+	resolverProvider := resolver.New(cl.Client()).
+		WithChangeFeed(changes).
+		WithSavedQueries(savedQueries).
+		WithCategoryFeed(categories).
+		WithClusterStatus(clusterStatus).
+		WithEmptyValues(graphqlCfg.EmptyValues).
+		WithFieldNames(schematypes.NewFieldNames(graphqlCfg.FieldCasing)).
+		WithFederation(graphqlCfg.Federation).
+		WithKindAliases(kindalias.ForCluster(graphqlCfg.KindAliases, name)).
+		WithEventLimits(graphqlCfg.EventLimits, graphqlCfg.EventsDropped).
+		WithMaxEvents(limits.MaxEvents)
+
+	if registration != nil {
+		resolverProvider.WithClusterRegistration(registration)
+	}
 
-	customSubGen, err := extensions.NewCustomSubscriptionGenerator(cl.RestConfig())
+	tables, err := resolver.NewTableReader(cl.RestConfig())
+	if err != nil {
+		validatorCancel()
+		return nil, fmt.Errorf("failed to create table reader: %w", err)
+	}
+	resolverProvider.WithTables(tables)
+
+	customSubGen, err := extensions.NewCustomSubscriptionGenerator(cl.RestConfig(), extensions.LogLimits{
+		MaxLines: limits.MaxLogLines,
+		MaxBytes: limits.MaxLogBytes,
+	})
 	if err != nil {
 		validatorCancel()
 		return nil, fmt.Errorf("failed to create custom subscription generator: %w", err)
 	}
 
-	schemaProvider, err := schema.New(ctx, schemaData.Components.Schemas, resolverProvider, customSubGen)
+	definitions := schemaData.Components.Schemas
+	if !graphqlCfg.ExposeManagedFields {
+		definitions = schema.WithoutManagedFields(definitions)
+	}
+
+	graphqlServer := graphql.NewGraphQLServer(graphqlCfg)
+	newServedSchema := func(definitions map[string]*spec.Schema) (*servedSchema, error) {
+		schemaProvider, err := schema.New(ctx, definitions, resolverProvider, customSubGen)
+		if err != nil {
+			return nil, err
+		}
+		gqlSchema := schemaProvider.GetSchema()
+		gqlSchema.AddExtensions(warnings.Extension{}, resolver.PrunedFieldsExtension{}, resolver.ErrorsExtension{}, kubectl.Extension{})
+		recovery.WrapResolvers(gqlSchema)
+
+		gqlHandler := graphqlServer.CreateHandler(gqlSchema)
+		graphqlHandler := kubectl.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Accept") == "text/event-stream" {
+				graphqlServer.HandleSubscription(w, r, gqlHandler.Schema)
+				return
+			}
+			gqlHandler.Handler.ServeHTTP(w, r)
+		}))
+		validation := queryvalidation.Config{
+			MaxDepth:              limits.MaxQueryDepth,
+			MaxComplexity:         limits.MaxQueryComplexity,
+			MaxBatchSize:          limits.MaxQueryBatchSize,
+			DisallowIntrospection: graphqlCfg.Introspection == config.IntrospectionDisabled,
+			Allowlist:             graphqlCfg.OperationAllowlist,
+		}
+		served := &servedSchema{
+			query:      queryvalidation.Middleware(graphqlHandler, validation),
+			schema:     gqlSchema,
+			validation: validation,
+			categories: schemaProvider.Categories(),
+		}
+
+		// Unauthenticated playground requests may only introspect if it is
+		// enabled for everyone.
+		validation.DisallowIntrospection = graphqlCfg.Introspection == config.IntrospectionAuthenticated || graphqlCfg.Introspection == config.IntrospectionDisabled
+		served.playground = queryvalidation.Middleware(graphqlHandler, validation)
+
+		served.download = &schemaFiles{schema: gqlSchema}
+		return served, nil
+	}
+
+	// Kinds of hidden maturities are left out of the schema served by
+	// default, including to unauthenticated playground requests.
+	exposed := maturity.Without(definitions, graphqlCfg.HiddenMaturities)
+	full, err := newServedSchema(exposed)
 	if err != nil {
 		validatorCancel()
 		return nil, fmt.Errorf("failed to create GraphQL schema: %w", err)
 	}
 
-	hasPathTemplate := schemaData.ClusterMetadata != nil && schemaData.ClusterMetadata.RequestPathTemplate != ""
+	// With RBAC schema pruning, users get a schema without the kinds they
+	// may not list. Unauthenticated playground requests get the full one.
+	schemaFor := func(definitions map[string]*spec.Schema, full *servedSchema) func(context.Context, string) *servedSchema {
+		if graphqlCfg.RBACSchemaPruning {
+			return rbacschema.New(cl.Client(), graphqlCfg.RBACSchemaPruningNamespace, definitions, full, newServedSchema, tokenReviewCacheTTL).For
+		}
+		return func(context.Context, string) *servedSchema { return full }
+	}
+	servedFor := schemaFor(exposed, full)
 
-	graphqlServer := graphql.NewGraphQLServer(graphqlCfg)
-	gqlHandler := graphqlServer.CreateHandler(schemaProvider.GetSchema())
+	// Members of the exempt groups are served the hidden kinds as well. If
+	// their groups cannot be reviewed, the kinds stay hidden.
+	if len(exposed) < len(definitions) && len(graphqlCfg.HiddenMaturitiesGroups) > 0 {
+		all, err := newServedSchema(definitions)
+		if err != nil {
+			validatorCancel()
+			return nil, fmt.Errorf("failed to create GraphQL schema with hidden maturities: %w", err)
+		}
 
-	gqlHTTPHandler := queryvalidation.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Header.Get("Accept") == "text/event-stream" {
-			graphqlServer.HandleSubscription(w, r, gqlHandler.Schema)
-			return
+		exempt := maturity.NewGroups(cl.Client(), graphqlCfg.HiddenMaturitiesGroups, tokenReviewCacheTTL)
+		exposedFor, allFor := servedFor, schemaFor(definitions, all)
+		servedFor = func(ctx context.Context, token string) *servedSchema {
+			member, err := exempt.Member(ctx, token)
+			if err != nil {
+				log.FromContext(ctx).Error(err, "Failed to review groups, hiding kinds of hidden maturities")
+			}
+			if member {
+				return allFor(ctx, token)
+			}
+			return exposedFor(ctx, token)
+		}
+	}
+
+	hasPathTemplate := schemaData.ClusterMetadata != nil && schemaData.ClusterMetadata.RequestPathTemplate != ""
+
+	authenticateWebSocket := func(ctx context.Context, token string) (*graphql.WebSocketSession, error) {
+		if token == "" {
+			return nil, errors.New("missing bearer token")
+		}
+		authenticated, err := validator.Validate(ctx, token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate token: %w", err)
+		}
+		if !authenticated {
+			return nil, errors.New("token was not accepted by the cluster")
 		}
-		gqlHandler.Handler.ServeHTTP(w, r)
-	}), queryvalidation.Config{
-		MaxDepth:      limits.MaxQueryDepth,
-		MaxComplexity: limits.MaxQueryComplexity,
-		MaxBatchSize:  limits.MaxQueryBatchSize,
-	})
+
+		served := servedFor(ctx, token)
+		return &graphql.WebSocketSession{
+			Context: utilscontext.SetToken(ctx, token),
+			Schema:  served.schema,
+			Prepare: func(ctx context.Context, query string, extensions map[string]any) (context.Context, error) {
+				if err := queryvalidation.Validate(query, served.validation); err != nil {
+					return nil, err
+				}
+				if hasPathTemplate {
+					if target := utilscontext.FindClusterTarget([]utilscontext.GraphQLRequest{{Query: query, Extensions: extensions}}); target != "" {
+						ctx = utilscontext.SetClusterTarget(ctx, target)
+					}
+				}
+				return ctx, nil
+			},
+		}, nil
+	}
 
 	// Middleware chain (outermost runs first):
 	//   requestparser → clusterTarget extraction → auth → queryvalidation → graphql handler
@@ -108,29 +261,59 @@ func New(
 			}
 		}
 
+		// WebSocket connections authenticate with their connection_init
+		// message once upgraded.
+		if graphql.IsWebSocketRequest(r) {
+			graphqlServer.HandleWebSocket(w, r, authenticateWebSocket)
+			return
+		}
+
+		isSchemaDownload := r.PathValue(SchemaFileParam) != ""
+
 		// Allow unauthenticated GET requests through when playground is enabled.
-		if graphqlCfg.PlaygroundEnabled && r.Method == http.MethodGet {
-			gqlHTTPHandler.ServeHTTP(w, r)
+		if graphqlCfg.PlaygroundEnabled && r.Method == http.MethodGet && !isSchemaDownload {
+			full.playground.ServeHTTP(w, r)
 			return
 		}
 
 		token, ok := utilscontext.GetTokenFromCtx(r.Context())
 		if !ok || token == "" {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			routing.Write(w, routing.Unauthenticated("missing bearer token", routing.ClusterReady))
 			return
 		}
 
 		authenticated, err := validator.Validate(r.Context(), token)
 		if err != nil {
-			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+			routing.Write(w, routing.AuthenticationUnavailable())
 			return
 		}
 		if !authenticated {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			routing.Write(w, routing.Unauthenticated("token was not accepted by the cluster", routing.ClusterReady))
+			return
+		}
+
+		if r.PathValue(SchemaFileParam) == DiscoveryFile {
+			serveDiscovery(w, r, graphqlCfg.Introspection != config.IntrospectionDisabled)
+			return
+		}
+
+		served := servedFor(r.Context(), token)
+
+		if isSchemaDownload {
+			if graphqlCfg.Introspection == config.IntrospectionDisabled {
+				http.Error(w, "introspection is disabled", http.StatusForbidden)
+				return
+			}
+			served.download.ServeHTTP(w, r)
 			return
 		}
 
-		gqlHTTPHandler.ServeHTTP(w, r)
+		if id := r.Header.Get(graphql.SubscriptionRenewHeader); id != "" {
+			graphqlServer.HandleRenewal(w, r, id)
+			return
+		}
+
+		served.query.ServeHTTP(w, r)
 	}))
 	log.FromContext(ctx).Info("Registered endpoint", "cluster", name)
 
@@ -140,9 +323,27 @@ func New(
 		graphqlServer: graphqlServer,
 		handler:       handler,
 		cancelFunc:    validatorCancel,
+		schemaSize:    SchemaSize{Bytes: len(schemaJSON), Types: len(full.schema.TypeMap())},
+		categories:    full.categories,
 	}, nil
 }
 
+// servedSchema holds the handlers serving a schema.
+type servedSchema struct {
+	// query serves authenticated queries, mutations and subscriptions.
+	query http.Handler
+	// playground serves unauthenticated GET requests.
+	playground http.Handler
+	download   *schemaFiles
+
+	// schema and validation serve operations sent over WebSocket connections.
+	schema     *graphqlgo.Schema
+	validation queryvalidation.Config
+
+	// categories are the types of each category of the schema.
+	categories map[string][]resolver.TypeByCategory
+}
+
 func (e *Endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if e.handler == nil {
 		http.Error(w, "Endpoint not ready", http.StatusServiceUnavailable)
@@ -155,6 +356,53 @@ func (e *Endpoint) Name() string {
 	return e.name
 }
 
+// Client returns the client of the endpoint's cluster, sending requests with
+// the token of the request.
+func (e *Endpoint) Client() client.Client {
+	if e.cluster == nil {
+		return nil
+	}
+	return e.cluster.Client()
+}
+
+// AdminConfig returns the cluster's admin config, nil once the endpoint is
+// closed.
+func (e *Endpoint) AdminConfig() *rest.Config {
+	if e.cluster == nil {
+		return nil
+	}
+	return e.cluster.AdminConfig()
+}
+
+// Results returns the number of query and mutation results the endpoint
+// served and how many of them had errors.
+func (e *Endpoint) Results() (served, failed uint64) {
+	if e.graphqlServer == nil {
+		return 0, 0
+	}
+	return e.graphqlServer.Results()
+}
+
+// SchemaSize returns the size of the endpoint's schema.
+func (e *Endpoint) SchemaSize() SchemaSize {
+	return e.schemaSize
+}
+
+// Health returns the health of the cluster's API server, the zero Health once
+// the endpoint is closed.
+func (e *Endpoint) Health(ctx context.Context) cluster.Health {
+	if e.cluster == nil {
+		return cluster.Health{}
+	}
+	return e.cluster.Health(ctx)
+}
+
+// Categories returns the types of each category of the schema served by
+// default, i.e. without RBAC schema pruning and hidden maturities.
+func (e *Endpoint) Categories() map[string][]resolver.TypeByCategory {
+	return e.categories
+}
+
 func (e *Endpoint) Close() {
 	if e.cancelFunc != nil {
 		e.cancelFunc()
@@ -171,5 +419,8 @@ func parseSchema(schemaJSON []byte) (*v1alpha1.Schema, error) {
 	if err := json.Unmarshal(schemaJSON, &schemaData); err != nil {
 		return nil, fmt.Errorf("failed to parse JSON: %w", err)
 	}
+	if err := openapiext.CheckVersion(schemaData.Version); err != nil {
+		return nil, err
+	}
 	return &schemaData, nil
 }
@@ -0,0 +1,59 @@
+package endpoint
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	graphqlgo "github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// SchemaFileParam is the path parameter naming the schema file to download
+// below the endpoint path.
+const SchemaFileParam = "schemaFile"
+
+// Schema files served below the endpoint path.
+const (
+	SDLFile           = "schema.graphql"
+	IntrospectionFile = "schema.json"
+)
+
+// schemaFiles renders the schema of an endpoint for download, once on the
+// first request as the schema does not change during the endpoint's life.
+type schemaFiles struct {
+	schema *graphqlgo.Schema
+
+	once          sync.Once
+	sdl           []byte
+	introspection []byte
+	err           error
+}
+
+func (f *schemaFiles) render(ctx context.Context) {
+	f.sdl = []byte(schema.PrintSDL(f.schema) + "\n")
+	f.introspection, f.err = schema.IntrospectionJSON(ctx, f.schema)
+}
+
+// ServeHTTP writes the schema file named by the path parameter.
+func (f *schemaFiles) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.once.Do(func() { f.render(r.Context()) })
+
+	switch r.PathValue(SchemaFileParam) {
+	case SDLFile:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write(f.sdl)
+	case IntrospectionFile:
+		if f.err != nil {
+			log.FromContext(r.Context()).Error(f.err, "Failed to render introspection result")
+			http.Error(w, "failed to render introspection result", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(f.introspection)
+	default:
+		http.NotFound(w, r)
+	}
+}
@@ -0,0 +1,51 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	graphqlgo "github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaFiles(t *testing.T) {
+	s, err := graphqlgo.NewSchema(graphqlgo.SchemaConfig{
+		Query: graphqlgo.NewObject(graphqlgo.ObjectConfig{
+			Name:   "Query",
+			Fields: graphqlgo.Fields{"hello": &graphqlgo.Field{Type: graphqlgo.String}},
+		}),
+	})
+	require.NoError(t, err)
+	files := &schemaFiles{schema: &s}
+
+	get := func(file string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/api/clusters/test/graphql/"+file, nil)
+		r.SetPathValue(SchemaFileParam, file)
+		w := httptest.NewRecorder()
+		files.ServeHTTP(w, r)
+		return w
+	}
+
+	sdl := get(SDLFile)
+	assert.Equal(t, http.StatusOK, sdl.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", sdl.Header().Get("Content-Type"))
+	assert.Equal(t, "type Query {\n  hello: String\n}\n", sdl.Body.String())
+
+	introspection := get(IntrospectionFile)
+	assert.Equal(t, http.StatusOK, introspection.Code)
+	assert.Equal(t, "application/json", introspection.Header().Get("Content-Type"))
+	var result struct {
+		Data struct {
+			Schema struct {
+				QueryType struct{ Name string } `json:"queryType"`
+			} `json:"__schema"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(introspection.Body.Bytes(), &result))
+	assert.Equal(t, "Query", result.Data.Schema.QueryType.Name)
+
+	assert.Equal(t, http.StatusNotFound, get("schema.yaml").Code)
+}
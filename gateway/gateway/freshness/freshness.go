@@ -0,0 +1,246 @@
+// Package freshness detects stale schema files. The listener rewrites the
+// schema file of a cluster on every reconcile, at least once per resync
+// period, so files older than that mean the listener is down or stuck and
+// the gateway serves schemas that no longer follow the clusters.
+package freshness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/changelog"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// checkInterval is how often the schema files are checked.
+const checkInterval = time.Minute
+
+// States of the watchdog.
+const (
+	StateOK      = "ok"
+	StateWarning = "warning"
+)
+
+// Status is the result of a check, served on /statusz.
+type Status struct {
+	State     string          `json:"state"`
+	Message   string          `json:"message,omitempty"`
+	Threshold string          `json:"threshold"`
+	CheckedAt time.Time       `json:"checkedAt"`
+	Schemas   []SchemaStatus  `json:"schemas"`
+	Listener  *ListenerStatus `json:"listener,omitempty"`
+}
+
+// SchemaStatus is the age of the schema file of a cluster.
+type SchemaStatus struct {
+	Cluster   string    `json:"cluster"`
+	WrittenAt time.Time `json:"writtenAt"`
+	Age       string    `json:"age"`
+	Stale     bool      `json:"stale"`
+}
+
+// ListenerStatus is the result of probing the listener's health endpoint.
+type ListenerStatus struct {
+	URL     string `json:"url"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Watchdog checks the age of the schema files in a directory and, if
+// configured, the health of the listener.
+type Watchdog struct {
+	dir         string
+	threshold   time.Duration
+	listenerURL string
+	client      *http.Client
+	now         func() time.Time
+
+	age        *prometheus.GaugeVec
+	stale      prometheus.Gauge
+	listenerUp prometheus.Gauge
+
+	mu     sync.RWMutex
+	status Status
+}
+
+// NewWatchdog returns a watchdog warning about schema files in dir older than
+// threshold. listenerURL is the listener's health endpoint, probed to tell a
+// listener that is down from one that is stuck; empty disables the probe.
+func NewWatchdog(dir string, threshold time.Duration, listenerURL string, reg prometheus.Registerer) *Watchdog {
+	w := &Watchdog{
+		dir:         dir,
+		threshold:   threshold,
+		listenerURL: listenerURL,
+		client:      &http.Client{Timeout: 5 * time.Second},
+		now:         time.Now,
+		age: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "graphql_schema_age_seconds",
+			Help: "Time since the schema file of a cluster was last written by the listener.",
+		}, []string{"cluster"}),
+		stale: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "graphql_schemas_stale",
+			Help: "Number of schema files older than the freshness threshold.",
+		}),
+		status: Status{State: StateOK, Threshold: threshold.String()},
+	}
+	reg.MustRegister(w.age, w.stale)
+	if listenerURL != "" {
+		w.listenerUp = prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "graphql_listener_up",
+			Help: "Whether the last probe of the listener's health endpoint succeeded.",
+		})
+		reg.MustRegister(w.listenerUp)
+	}
+	return w
+}
+
+// Run checks the schema files every minute until ctx is done.
+func (w *Watchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		w.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check checks the schema files and the listener, updates the metrics and
+// returns the new status.
+func (w *Watchdog) Check(ctx context.Context) Status {
+	logger := log.FromContext(ctx)
+	now := w.now()
+
+	status := Status{State: StateOK, Threshold: w.threshold.String(), CheckedAt: now, Schemas: []SchemaStatus{}}
+	err := filepath.WalkDir(w.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// Changelogs count as writes of their schema files.
+			if path == filepath.Join(w.dir, changelog.Dir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		writtenAt := info.ModTime()
+		if rel, err := filepath.Rel(w.dir, path); err == nil {
+			if changes, err := os.Stat(filepath.Join(w.dir, changelog.Dir, rel)); err == nil && changes.ModTime().After(writtenAt) {
+				writtenAt = changes.ModTime()
+			}
+		}
+		age := now.Sub(writtenAt)
+		status.Schemas = append(status.Schemas, SchemaStatus{
+			Cluster:   filepath.Base(path),
+			WrittenAt: writtenAt,
+			Age:       age.Truncate(time.Second).String(),
+			Stale:     age > w.threshold,
+		})
+		return nil
+	})
+	if err != nil {
+		logger.Error(err, "Failed to check schema files", "dir", w.dir)
+	}
+	slices.SortFunc(status.Schemas, func(a, b SchemaStatus) int { return strings.Compare(a.Cluster, b.Cluster) })
+
+	w.age.Reset()
+	var stale []string
+	for _, s := range status.Schemas {
+		w.age.WithLabelValues(s.Cluster).Set(now.Sub(s.WrittenAt).Seconds())
+		if s.Stale {
+			stale = append(stale, s.Cluster)
+		}
+	}
+	w.stale.Set(float64(len(stale)))
+
+	if w.listenerURL != "" {
+		status.Listener = w.probeListener(ctx)
+		if status.Listener.Healthy {
+			w.listenerUp.Set(1)
+		} else {
+			w.listenerUp.Set(0)
+		}
+	}
+
+	switch {
+	case len(stale) > 0 && status.Listener == nil:
+		status.State = StateWarning
+		status.Message = fmt.Sprintf("schemas of %s are older than %s, check that the listener is running", strings.Join(stale, ", "), w.threshold)
+	case len(stale) > 0 && status.Listener.Healthy:
+		status.State = StateWarning
+		status.Message = fmt.Sprintf("schemas of %s are older than %s although the listener is healthy, it may be stuck", strings.Join(stale, ", "), w.threshold)
+	case len(stale) > 0:
+		status.State = StateWarning
+		status.Message = fmt.Sprintf("schemas of %s are older than %s and the listener is not healthy", strings.Join(stale, ", "), w.threshold)
+	case status.Listener != nil && !status.Listener.Healthy:
+		status.State = StateWarning
+		status.Message = "the listener is not healthy, schemas will become stale"
+	}
+
+	w.mu.Lock()
+	previous := w.status.State
+	w.status = status
+	w.mu.Unlock()
+
+	if status.State != previous {
+		if status.State == StateWarning {
+			logger.Info("Schema freshness warning", "message", status.Message)
+		} else {
+			logger.Info("Schemas are fresh again")
+		}
+	}
+	return status
+}
+
+func (w *Watchdog) probeListener(ctx context.Context) *ListenerStatus {
+	status := &ListenerStatus{URL: w.listenerURL}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.listenerURL, nil)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		status.Error = fmt.Sprintf("health endpoint returned %s", resp.Status)
+		return status
+	}
+	status.Healthy = true
+	return status
+}
+
+// Status returns the result of the last check.
+func (w *Watchdog) Status() Status {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.status
+}
+
+// ServeHTTP writes the result of the last check as JSON. The status code is
+// always 200, as stale schemas are still served.
+func (w *Watchdog) ServeHTTP(rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(w.Status()) //nolint:errcheck
+}
@@ -0,0 +1,136 @@
+package freshness
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSchema writes a schema file for cluster written age ago.
+func writeSchema(t *testing.T, dir, cluster string, age time.Duration) {
+	t.Helper()
+	path := filepath.Join(dir, cluster)
+	require.NoError(t, os.WriteFile(path, []byte("{}"), 0o644))
+	written := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(path, written, written))
+}
+
+func TestWatchdog_Check(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer healthy.Close()
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer unhealthy.Close()
+
+	tests := []struct {
+		name         string
+		ages         map[string]time.Duration
+		listenerURL  string
+		wantState    string
+		wantMessage  string
+		wantStale    float64
+		wantListener float64
+	}{
+		{
+			name:      "fresh schemas",
+			ages:      map[string]time.Duration{"a": time.Minute, "b": time.Hour},
+			wantState: StateOK,
+		},
+		{
+			name:        "stale schema without listener probe",
+			ages:        map[string]time.Duration{"a": time.Minute, "b": 3 * time.Hour},
+			wantState:   StateWarning,
+			wantMessage: "schemas of b are older than 2h0m0s, check that the listener is running",
+			wantStale:   1,
+		},
+		{
+			name:         "stale schema with healthy listener",
+			ages:         map[string]time.Duration{"a": 3 * time.Hour},
+			listenerURL:  healthy.URL,
+			wantState:    StateWarning,
+			wantMessage:  "schemas of a are older than 2h0m0s although the listener is healthy, it may be stuck",
+			wantStale:    1,
+			wantListener: 1,
+		},
+		{
+			name:        "stale schema with unhealthy listener",
+			ages:        map[string]time.Duration{"a": 3 * time.Hour},
+			listenerURL: unhealthy.URL,
+			wantState:   StateWarning,
+			wantMessage: "schemas of a are older than 2h0m0s and the listener is not healthy",
+			wantStale:   1,
+		},
+		{
+			name:        "fresh schemas with unhealthy listener",
+			ages:        map[string]time.Duration{"a": time.Minute},
+			listenerURL: unhealthy.URL,
+			wantState:   StateWarning,
+			wantMessage: "the listener is not healthy, schemas will become stale",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for cluster, age := range tt.ages {
+				writeSchema(t, dir, cluster, age)
+			}
+			require.NoError(t, os.Mkdir(filepath.Join(dir, "subdir"), 0o755))
+
+			w := NewWatchdog(dir, 2*time.Hour, tt.listenerURL, prometheus.NewRegistry())
+			status := w.Check(t.Context())
+
+			assert.Equal(t, tt.wantState, status.State)
+			assert.Equal(t, tt.wantMessage, status.Message)
+			assert.Len(t, status.Schemas, len(tt.ages))
+			assert.Equal(t, tt.wantStale, testutil.ToFloat64(w.stale))
+			assert.Equal(t, len(tt.ages), testutil.CollectAndCount(w.age))
+			if tt.listenerURL != "" {
+				assert.Equal(t, tt.wantListener, testutil.ToFloat64(w.listenerUp))
+			} else {
+				assert.Nil(t, status.Listener)
+			}
+		})
+	}
+}
+
+func TestWatchdog_RemovedSchema(t *testing.T) {
+	dir := t.TempDir()
+	writeSchema(t, dir, "a", 3*time.Hour)
+	w := NewWatchdog(dir, 2*time.Hour, "", prometheus.NewRegistry())
+	assert.Equal(t, StateWarning, w.Check(t.Context()).State)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "a")))
+	assert.Equal(t, StateOK, w.Check(t.Context()).State)
+	assert.Zero(t, testutil.CollectAndCount(w.age))
+}
+
+func TestWatchdog_ServeHTTP(t *testing.T) {
+	dir := t.TempDir()
+	writeSchema(t, dir, "a", 3*time.Hour)
+	w := NewWatchdog(dir, 2*time.Hour, "", prometheus.NewRegistry())
+	w.Check(t.Context())
+
+	rec := httptest.NewRecorder()
+	w.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/statusz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var status Status
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, StateWarning, status.State)
+	assert.Equal(t, "2h0m0s", status.Threshold)
+	require.Len(t, status.Schemas, 1)
+	assert.Equal(t, "a", status.Schemas[0].Cluster)
+	assert.True(t, status.Schemas[0].Stale)
+}
@@ -0,0 +1,96 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/recovery"
+	"golang.org/x/sync/errgroup"
+)
+
+// batchedOperation is an operation of a batched request.
+type batchedOperation struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// withBatching executes POST requests with a JSON array of operations, as
+// sent by batching clients, and answers with an array of their results in
+// the same order. The operations run concurrently, at most
+// config.BatchConcurrency at a time, so they must not depend on each other.
+// Other requests are passed to next.
+func (s *GraphQLServer) withBatching(schema *graphql.Schema, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Body == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Error reading request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if trimmed := bytes.TrimSpace(body); len(trimmed) == 0 || trimmed[0] != '[' {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var operations []batchedOperation
+		if err := json.Unmarshal(body, &operations); err != nil {
+			http.Error(w, "Error parsing batched request body", http.StatusBadRequest)
+			return
+		}
+		if len(operations) == 0 {
+			http.Error(w, "Batched request contains no operations", http.StatusBadRequest)
+			return
+		}
+
+		results := make([]*graphql.Result, len(operations))
+		g := errgroup.Group{}
+		g.SetLimit(max(s.config.BatchConcurrency, 1))
+		for i, op := range operations {
+			g.Go(func() error {
+				results[i] = s.executeBatched(r, schema, op)
+				return nil
+			})
+		}
+		_ = g.Wait()
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		encoder := json.NewEncoder(w)
+		if s.config.Pretty {
+			encoder.SetIndent("", "\t")
+		}
+		if err := encoder.Encode(results); err != nil {
+			http.Error(w, "Error encoding batched response", http.StatusInternalServerError)
+		}
+	})
+}
+
+// executeBatched executes an operation of a batched request. The workers
+// have goroutines of their own, so panics are recovered here.
+func (s *GraphQLServer) executeBatched(r *http.Request, schema *graphql.Schema, op batchedOperation) (result *graphql.Result) {
+	defer func() {
+		if err := recovery.Recovered(r.Context(), "batchedOperation", recover()); err != nil {
+			result = &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.FormatError(err)}}
+		}
+		s.served.Add(1)
+		if result.HasErrors() {
+			s.failed.Add(1)
+		}
+	}()
+
+	return graphql.Do(graphql.Params{
+		Schema:         *schema,
+		RequestString:  op.Query,
+		VariableValues: op.Variables,
+		OperationName:  op.OperationName,
+		Context:        r.Context(),
+	})
+}
@@ -0,0 +1,95 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithBatching(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+			"echo": &graphql.Field{
+				Type: graphql.String,
+				Args: graphql.FieldConfigArgument{"value": &graphql.ArgumentConfig{Type: graphql.String}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					n := running.Add(1)
+					defer running.Add(-1)
+					for {
+						m := maxRunning.Load()
+						if n <= m || maxRunning.CompareAndSwap(m, n) {
+							break
+						}
+					}
+					time.Sleep(10 * time.Millisecond)
+					return p.Args["value"], nil
+				},
+			},
+			"boom": &graphql.Field{
+				Type:    graphql.String,
+				Resolve: func(graphql.ResolveParams) (any, error) { panic("boom") },
+			},
+		}}),
+	})
+	require.NoError(t, err)
+
+	s := NewGraphQLServer(config.GraphQL{BatchConcurrency: 2})
+	h := s.CreateHandler(&schema)
+
+	t.Run("results in order", func(t *testing.T) {
+		body := `[
+			{"query": "{ echo(value: \"a\") }"},
+			{"query": "query Q($v: String) { echo(value: $v) }", "variables": {"v": "b"}},
+			{"query": "query A { echo(value: \"c\") } query B { echo(value: \"d\") }", "operationName": "B"},
+			{"query": "{ missing }"},
+			{"query": "{ echo(value: \"e\") }"}
+		]`
+		rec := httptest.NewRecorder()
+		h.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var results []struct {
+			Data   map[string]any   `json:"data"`
+			Errors []map[string]any `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &results))
+		require.Len(t, results, 5)
+		assert.Equal(t, "a", results[0].Data["echo"])
+		assert.Equal(t, "b", results[1].Data["echo"])
+		assert.Equal(t, "d", results[2].Data["echo"])
+		assert.NotEmpty(t, results[3].Errors)
+		assert.Equal(t, "e", results[4].Data["echo"])
+		assert.LessOrEqual(t, maxRunning.Load(), int32(2))
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[{"query": "{ boom }"}]`)))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"errors"`)
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[]`)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("single request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"query": "{ echo(value: \"a\") }"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		h.Handler.ServeHTTP(rec, req)
+		assert.JSONEq(t, `{"data": {"echo": "a"}}`, rec.Body.String())
+	})
+}
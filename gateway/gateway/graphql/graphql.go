@@ -1,20 +1,32 @@
 package graphql
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync/atomic"
+	"time"
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/handler"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/config"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // GraphQLServer provides utility methods for creating GraphQL handlers.
 type GraphQLServer struct {
-	config config.GraphQL
+	config    config.GraphQL
+	lifetimes lifetimeRegistry
+
+	// served and failed count the query and mutation results returned by
+	// the handlers of this server, failed those with errors.
+	served atomic.Uint64
+	failed atomic.Uint64
 }
 
 // NewGraphQLServer creates a new GraphQL server.
@@ -37,14 +49,29 @@ func (s *GraphQLServer) CreateHandler(schema *graphql.Schema) *GraphQLHandler {
 		Pretty:     s.config.Pretty,
 		Playground: s.config.PlaygroundEnabled,
 		GraphiQL:   s.config.GraphiQL,
+		ResultCallbackFn: func(_ context.Context, _ *graphql.Params, result *graphql.Result, _ []byte) {
+			s.served.Add(1)
+			if result.HasErrors() {
+				s.failed.Add(1)
+			}
+		},
 	})
 	return &GraphQLHandler{
 		Schema:  schema,
-		Handler: graphqlHandler,
+		Handler: s.withBatching(schema, s.withIncrementalDelivery(schema, graphqlHandler)),
 	}
 }
 
-// HandleSubscription handles GraphQL subscription requests using Server-Sent Events.
+// Results returns the number of query and mutation results served so far
+// and how many of them had errors. Subscriptions are not counted.
+func (s *GraphQLServer) Results() (served, failed uint64) {
+	return s.served.Load(), s.failed.Load()
+}
+
+// HandleSubscription handles GraphQL subscription requests and @live queries
+// using Server-Sent Events. Subscription events carry the resource version
+// the watch can be resumed from as event ID, which clients reconnecting after
+// a disconnect send back in the Last-Event-ID header.
 func (s *GraphQLServer) HandleSubscription(w http.ResponseWriter, r *http.Request, schema *graphql.Schema) {
 	logger := log.FromContext(r.Context())
 
@@ -70,12 +97,21 @@ func (s *GraphQLServer) HandleSubscription(w http.ResponseWriter, r *http.Reques
 		logger.V(4).Error(err, "Failed to close request body")
 	}
 
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	resumePoints := &utilscontext.ResumePoints{}
+	ctx = utilscontext.SetResumePoints(ctx, resumePoints)
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		ctx = utilscontext.SetResumeFrom(ctx, lastEventID)
+	}
+
 	subscriptionParams := graphql.Params{
 		Schema:         *schema,
 		RequestString:  params.Query,
 		VariableValues: params.Variables,
 		OperationName:  params.OperationName,
-		Context:        r.Context(),
+		Context:        ctx,
 	}
 
 	if err := flusher.Flush(); err != nil {
@@ -83,26 +119,129 @@ func (s *GraphQLServer) HandleSubscription(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	subscriptionChannel := graphql.Subscribe(subscriptionParams)
-	for res := range subscriptionChannel {
-		if res == nil {
-			continue
+	// write sends a chunk to the client. With a write timeout, clients not
+	// reading their events are disconnected instead of blocking the
+	// subscription forever.
+	write := func(chunk string) error {
+		if timeout := s.config.SSEWriteTimeout; timeout > 0 {
+			if err := flusher.SetWriteDeadline(time.Now().Add(timeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				return err
+			}
 		}
-
-		data, err := json.Marshal(res)
-		if err != nil {
-			logger.Error(err, "Error marshalling subscription response")
-			continue
+		if _, err := fmt.Fprint(w, chunk); err != nil {
+			return err
 		}
+		return flusher.Flush()
+	}
 
-		if _, err := fmt.Fprintf(w, "event: next\ndata: %s\n\n", data); err != nil {
-			logger.V(4).Error(err, "Failed to write SSE event")
-			return
+	writeEvent := func(event, id string, data []byte) bool {
+		var idField string
+		if id != "" {
+			idField = "id: " + id + "\n"
+		}
+		if err := write(fmt.Sprintf("event: %s\n%sdata: %s\n\n", event, idField, data)); err != nil {
+			logger.V(4).Error(err, "Failed to write SSE event", "event", event)
+			return false
 		}
+		return true
+	}
+
+	// Comments keep proxies from closing connections of quiet subscriptions
+	// as idle. Clients ignore them.
+	var keepaliveC <-chan time.Time
+	if interval := s.config.SSEKeepaliveInterval; interval > 0 {
+		keepalive := time.NewTicker(interval)
+		defer keepalive.Stop()
+		keepaliveC = keepalive.C
+	}
+
+	// With a maximum lifetime, the client gets an "expiring" event carrying the
+	// subscription ID shortly before the end. Renewing the subscription via
+	// SubscriptionRenewHeader restarts the lifetime and sends a "renewed" event.
+	var (
+		lifetime         *subscriptionLifetime
+		expiresAt        time.Time
+		warnC, expireC   <-chan time.Time
+		renewC           <-chan struct{}
+		warnTimer, timer *time.Timer
+	)
+	if maxLifetime := s.config.SubscriptionMaxLifetime; maxLifetime > 0 {
+		token, _ := utilscontext.GetTokenFromCtx(r.Context())
+		lifetime = s.lifetimes.add(token)
+		defer s.lifetimes.remove(lifetime.id)
 
-		if err := flusher.Flush(); err != nil {
-			logger.V(4).Error(err, "Failed to flush SSE response")
-			return
+		warnAfter := max(maxLifetime-s.config.SubscriptionExpiryWarning, 0)
+		expiresAt = time.Now().Add(maxLifetime)
+		warnTimer = time.NewTimer(warnAfter)
+		timer = time.NewTimer(maxLifetime)
+		defer warnTimer.Stop()
+		defer timer.Stop()
+
+		warnC, expireC, renewC = warnTimer.C, timer.C, lifetime.renew
+	}
+
+	subscriptionChannel, incrementalChannel := s.execute(ctx, schema, subscriptionParams)
+loop:
+	for {
+		select {
+		case res, ok := <-subscriptionChannel:
+			if !ok {
+				break loop
+			}
+			if res == nil {
+				continue
+			}
+			resolver.AddErrorExtensions(res.Errors)
+			id := resumePoints.Next()
+
+			data, err := json.Marshal(res)
+			if err != nil {
+				logger.Error(err, "Error marshalling subscription response")
+				continue
+			}
+
+			if !writeEvent("next", id, data) {
+				return
+			}
+		case payload, ok := <-incrementalChannel:
+			if !ok {
+				break loop
+			}
+
+			data, err := json.Marshal(payload)
+			if err != nil {
+				logger.Error(err, "Error marshalling incremental payload")
+				continue
+			}
+
+			if !writeEvent("next", "", data) {
+				return
+			}
+		case <-keepaliveC:
+			if err := write(":keepalive\n\n"); err != nil {
+				logger.V(4).Error(err, "Failed to write SSE keepalive")
+				return
+			}
+		case <-warnC:
+			data, _ := json.Marshal(lifetimeEvent{ID: lifetime.id, ExpiresAt: expiresAt})
+			if !writeEvent("expiring", "", data) {
+				return
+			}
+		case <-renewC:
+			maxLifetime := s.config.SubscriptionMaxLifetime
+			expiresAt = time.Now().Add(maxLifetime)
+			warnTimer.Reset(max(maxLifetime-s.config.SubscriptionExpiryWarning, 0))
+			timer.Reset(maxLifetime)
+
+			data, _ := json.Marshal(lifetimeEvent{ID: lifetime.id, ExpiresAt: expiresAt})
+			if !writeEvent("renewed", "", data) {
+				return
+			}
+		case <-expireC:
+			logger.V(4).Info("Subscription reached its maximum lifetime", "id", lifetime.id)
+			// Stop the resolvers and their upstream watches.
+			cancel()
+			break loop
 		}
 	}
 
@@ -110,8 +249,22 @@ func (s *GraphQLServer) HandleSubscription(w http.ResponseWriter, r *http.Reques
 	select {
 	case <-r.Context().Done():
 	default:
-		if _, err := fmt.Fprint(w, "event: complete\n\n"); err != nil {
+		if err := write("event: complete\n\n"); err != nil {
 			logger.V(4).Error(err, "Failed to write SSE complete event")
 		}
 	}
 }
+
+// execute starts the operation of params. Live queries are polled and queries
+// using @defer or @stream delivered incrementally; everything else, including
+// errors for documents that are neither, goes through graphql.Subscribe.
+// Exactly one of the returned channels is non-nil.
+func (s *GraphQLServer) execute(ctx context.Context, schema *graphql.Schema, params graphql.Params) (<-chan *graphql.Result, <-chan incrementalPayload) {
+	if isLiveQuery(params.RequestString, params.OperationName) {
+		return liveQuery(ctx, params, s.config.LiveQueryInterval), nil
+	}
+	if plan, ok := planIncremental(schema, params.RequestString, params.OperationName, params.VariableValues); ok {
+		return nil, executeIncremental(ctx, params, plan)
+	}
+	return graphql.Subscribe(params), nil
+}
@@ -0,0 +1,94 @@
+package graphql
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/config"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLServer_Results(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+			"ping": &graphql.Field{
+				Type:    graphql.String,
+				Resolve: func(graphql.ResolveParams) (any, error) { return "pong", nil },
+			},
+		}}),
+	})
+	require.NoError(t, err)
+
+	s := NewGraphQLServer(config.GraphQL{})
+	h := s.CreateHandler(&schema)
+
+	for _, query := range []string{`{ ping }`, `{ ping }`, `{ missing }`} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"query": "`+query+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		h.Handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	served, failed := s.Results()
+	assert.Equal(t, uint64(3), served)
+	assert.Equal(t, uint64(1), failed)
+}
+
+func TestHandleSubscription_EventIDs(t *testing.T) {
+	// The subscription sends the resource version it resumes from, then events
+	// resumable at "2" and "3" with one in between that is not resumable.
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+			"ping": &graphql.Field{Type: graphql.String},
+		}}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{Name: "Subscription", Fields: graphql.Fields{
+			"version": &graphql.Field{
+				Type: graphql.String,
+				Subscribe: func(p graphql.ResolveParams) (any, error) {
+					resumeFrom, _ := utilscontext.GetResumeFromCtx(p.Context)
+					ch := make(chan any, 4)
+					for _, v := range []string{"from:" + resumeFrom, "2", "", "3"} {
+						ch <- v
+					}
+					close(ch)
+					return ch, nil
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					v := p.Source.(string)
+					resumeAt := v
+					if strings.HasPrefix(v, "from:") {
+						resumeAt = ""
+					}
+					if points, ok := utilscontext.GetResumePointsFromCtx(p.Context); ok {
+						points.Add(resumeAt)
+					}
+					return v, nil
+				},
+			},
+		}}),
+	})
+	require.NoError(t, err)
+
+	s := NewGraphQLServer(config.GraphQL{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.HandleSubscription(w, r, &schema)
+	}))
+	t.Cleanup(server.Close)
+
+	events := subscribeWith(t, server, `{"query":"subscription { version }"}`, "Last-Event-ID", "1")
+
+	var ids, data []string
+	for range 4 {
+		ev := nextEvent(t, events)
+		require.Equal(t, "next", ev.name)
+		ids = append(ids, ev.id)
+		data = append(data, ev.data)
+	}
+	assert.Equal(t, []string{"", "2", "", "3"}, ids)
+	assert.JSONEq(t, `{"data":{"version":"from:1"}}`, data[0])
+	assert.Equal(t, "complete", nextEvent(t, events).name)
+}
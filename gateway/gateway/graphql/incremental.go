@@ -0,0 +1,522 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/graphql-go/graphql/language/visitor"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/recovery"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// streamChunkSize is the maximum number of items of a streamed list sent in
+// one payload.
+const streamChunkSize = 50
+
+// incrementalPlan is a query split for incremental delivery: the initial
+// query without the deferred fragments, which are executed as queries of
+// their own selecting only the path to the fragment, and the streamed lists
+// cut from the initial result.
+type incrementalPlan struct {
+	initial  string
+	deferred []deferredFragment
+	streams  []streamedField
+}
+
+// deferredFragment is a fragment marked with @defer.
+type deferredFragment struct {
+	label string
+	// keys are the response keys of the fields leading to the fragment.
+	keys  []string
+	query string
+}
+
+// streamedField is a list field marked with @stream.
+type streamedField struct {
+	label string
+	// keys are the response keys of the fields leading to the list,
+	// including its own.
+	keys         []string
+	initialCount int
+}
+
+// incrementalPayload is a payload of an incremental delivery response.
+type incrementalPayload struct {
+	Data        any                        `json:"data,omitempty"`
+	Errors      []gqlerrors.FormattedError `json:"errors,omitempty"`
+	Extensions  map[string]any             `json:"extensions,omitempty"`
+	Incremental []incrementalResult        `json:"incremental,omitempty"`
+	HasNext     bool                       `json:"hasNext"`
+}
+
+// incrementalResult is the data of a deferred fragment or the items of a
+// streamed list at path.
+type incrementalResult struct {
+	Data   any                        `json:"data,omitempty"`
+	Items  []any                      `json:"items,omitempty"`
+	Path   []any                      `json:"path"`
+	Label  string                     `json:"label,omitempty"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+// planIncremental splits a query using @defer or @stream for incremental
+// delivery. It reports false for documents that do not parse or validate,
+// operations other than queries, which would run twice, and queries without
+// active @defer or @stream; they are executed at once. @defer and @stream
+// within deferred fragments are delivered with the fragment.
+func planIncremental(schema *graphql.Schema, query, operationName string, variables map[string]any) (*incrementalPlan, bool) {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(query)})})
+	if err != nil {
+		return nil, false
+	}
+	operation := findOperation(doc, operationName)
+	if operation == nil || operation.Operation != ast.OperationTypeQuery {
+		return nil, false
+	}
+	addTypeConditions(schema, doc)
+	if !graphql.ValidateDocument(schema, doc, nil).IsValid {
+		return nil, false
+	}
+
+	p := &incrementalPlanner{
+		operation: operation,
+		fragments: map[string]*ast.FragmentDefinition{},
+		variables: variables,
+		plan:      &incrementalPlan{},
+	}
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			p.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	initial := p.rewrite(operation.SelectionSet, nil, nil, false)
+	if len(p.plan.deferred) == 0 && len(p.plan.streams) == 0 {
+		return nil, false
+	}
+	p.plan.initial = p.print(initial)
+	return p.plan, true
+}
+
+// addTypeConditions gives inline fragments without a type condition, such as
+// "... @defer { ... }", the type they are spread in. graphql-go takes a
+// list field's list type for their type and rejects them in lists.
+func addTypeConditions(schema *graphql.Schema, doc *ast.Document) {
+	typeInfo := graphql.NewTypeInfo(&graphql.TypeInfoConfig{Schema: schema})
+	visitor.Visit(doc, &visitor.VisitorOptions{
+		Enter: func(p visitor.VisitFuncParams) (string, any) {
+			if node, ok := p.Node.(ast.Node); ok {
+				if fragment, ok := node.(*ast.InlineFragment); ok && fragment.TypeCondition == nil && typeInfo.ParentType() != nil {
+					fragment.TypeCondition = ast.NewNamed(&ast.Named{Name: ast.NewName(&ast.Name{Value: typeInfo.ParentType().Name()})})
+				}
+				typeInfo.Enter(node)
+			}
+			return visitor.ActionNoChange, nil
+		},
+		Leave: func(p visitor.VisitFuncParams) (string, any) {
+			if node, ok := p.Node.(ast.Node); ok {
+				typeInfo.Leave(node)
+			}
+			return visitor.ActionNoChange, nil
+		},
+	}, nil)
+}
+
+type incrementalPlanner struct {
+	operation *ast.OperationDefinition
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+	plan      *incrementalPlan
+}
+
+// rewrite returns set without deferred fragments and with named fragments
+// inlined, recording the deferred fragments and streamed fields. chain holds
+// the fields and inline fragments leading to set, keys the response keys of
+// the fields. Within deferred fragments, the directives are only removed.
+func (p *incrementalPlanner) rewrite(set *ast.SelectionSet, chain []ast.Selection, keys []string, inDeferred bool) *ast.SelectionSet {
+	if set == nil {
+		return nil
+	}
+
+	selections := make([]ast.Selection, 0, len(set.Selections))
+	for _, selection := range set.Selections {
+		if spread, ok := selection.(*ast.FragmentSpread); ok {
+			fragment, ok := p.fragments[spread.Name.Value]
+			if !ok {
+				continue
+			}
+			selection = ast.NewInlineFragment(&ast.InlineFragment{
+				TypeCondition: fragment.TypeCondition,
+				Directives:    spread.Directives,
+				SelectionSet:  fragment.SelectionSet,
+			})
+		}
+
+		switch node := selection.(type) {
+		case *ast.Field:
+			key := node.Name.Value
+			if node.Alias != nil {
+				key = node.Alias.Value
+			}
+			fieldKeys := append(slices.Clone(keys), key)
+			if stream, ok := p.active(node.Directives, types.StreamDirective.Name); ok && !inDeferred {
+				p.plan.streams = append(p.plan.streams, streamedField{
+					label:        p.stringArg(stream, "label"),
+					keys:         fieldKeys,
+					initialCount: max(p.intArg(stream, "initialCount"), 0),
+				})
+			}
+			field := *node
+			field.Directives = withoutIncrementalDirectives(node.Directives)
+			field.SelectionSet = p.rewrite(node.SelectionSet, append(slices.Clone(chain), node), fieldKeys, inDeferred)
+			selections = append(selections, &field)
+
+		case *ast.InlineFragment:
+			fragment := *node
+			fragment.Directives = withoutIncrementalDirectives(node.Directives)
+			if deferDirective, ok := p.active(node.Directives, types.DeferDirective.Name); ok && !inDeferred {
+				fragment.SelectionSet = p.rewrite(node.SelectionSet, nil, nil, true)
+				p.plan.deferred = append(p.plan.deferred, deferredFragment{
+					label: p.stringArg(deferDirective, "label"),
+					keys:  keys,
+					query: p.print(nestSelection(chain, &fragment)),
+				})
+				continue
+			}
+			fragment.SelectionSet = p.rewrite(node.SelectionSet, append(slices.Clone(chain), node), keys, inDeferred)
+			selections = append(selections, &fragment)
+
+		default:
+			selections = append(selections, selection)
+		}
+	}
+
+	// Selection sets may not be empty.
+	if len(selections) == 0 {
+		selections = append(selections, ast.NewField(&ast.Field{Name: ast.NewName(&ast.Name{Value: "__typename"})}))
+	}
+	return ast.NewSelectionSet(&ast.SelectionSet{Selections: selections})
+}
+
+// nestSelection returns the selection set selecting leaf through chain.
+func nestSelection(chain []ast.Selection, leaf ast.Selection) *ast.SelectionSet {
+	selection := leaf
+	for i := len(chain) - 1; i >= 0; i-- {
+		set := ast.NewSelectionSet(&ast.SelectionSet{Selections: []ast.Selection{selection}})
+		switch node := chain[i].(type) {
+		case *ast.Field:
+			field := *node
+			field.Directives = withoutIncrementalDirectives(node.Directives)
+			field.SelectionSet = set
+			selection = &field
+		case *ast.InlineFragment:
+			fragment := *node
+			fragment.Directives = withoutIncrementalDirectives(node.Directives)
+			fragment.SelectionSet = set
+			selection = &fragment
+		}
+	}
+	return ast.NewSelectionSet(&ast.SelectionSet{Selections: []ast.Selection{selection}})
+}
+
+// print returns the query document of the operation with selection set
+// set, declaring only the variables it uses.
+func (p *incrementalPlanner) print(set *ast.SelectionSet) string {
+	used := map[string]bool{}
+	visitor.Visit(set, &visitor.VisitorOptions{
+		Enter: func(params visitor.VisitFuncParams) (string, any) {
+			if variable, ok := params.Node.(*ast.Variable); ok && variable.Name != nil {
+				used[variable.Name.Value] = true
+			}
+			return visitor.ActionNoChange, nil
+		},
+	}, nil)
+
+	operation := *p.operation
+	operation.SelectionSet = set
+	operation.VariableDefinitions = nil
+	for _, def := range p.operation.VariableDefinitions {
+		if used[def.Variable.Name.Value] {
+			operation.VariableDefinitions = append(operation.VariableDefinitions, def)
+		}
+	}
+	doc := ast.NewDocument(&ast.Document{Definitions: []ast.Node{&operation}})
+	printed, _ := printer.Print(doc).(string)
+	return printed
+}
+
+// active returns the directive called name if it is present and its if
+// argument is not false.
+func (p *incrementalPlanner) active(directives []*ast.Directive, name string) (*ast.Directive, bool) {
+	for _, directive := range directives {
+		if directive.Name == nil || directive.Name.Value != name {
+			continue
+		}
+		enabled, ok := p.value(directive, "if").(bool)
+		return directive, !ok || enabled
+	}
+	return nil, false
+}
+
+// value returns the value of a directive argument, nil if it is absent.
+func (p *incrementalPlanner) value(directive *ast.Directive, name string) any {
+	for _, arg := range directive.Arguments {
+		if arg.Name == nil || arg.Name.Value != name {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.Variable:
+			return p.variables[v.Name.Value]
+		case *ast.BooleanValue:
+			return v.Value
+		case *ast.StringValue:
+			return v.Value
+		case *ast.IntValue:
+			n, _ := strconv.Atoi(v.Value)
+			return n
+		}
+	}
+	return nil
+}
+
+func (p *incrementalPlanner) stringArg(directive *ast.Directive, name string) string {
+	s, _ := p.value(directive, name).(string)
+	return s
+}
+
+func (p *incrementalPlanner) intArg(directive *ast.Directive, name string) int {
+	switch n := p.value(directive, name).(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	}
+	return 0
+}
+
+// withoutIncrementalDirectives returns directives without @defer and @stream.
+func withoutIncrementalDirectives(directives []*ast.Directive) []*ast.Directive {
+	return slices.DeleteFunc(slices.Clone(directives), func(d *ast.Directive) bool {
+		return d.Name != nil && (d.Name.Value == types.DeferDirective.Name || d.Name.Value == types.StreamDirective.Name)
+	})
+}
+
+// executeIncremental executes a planned query and sends the initial result,
+// the remaining items of the streamed lists and then the deferred fragments
+// as they complete. The last payload has hasNext false.
+func executeIncremental(ctx context.Context, params graphql.Params, plan *incrementalPlan) <-chan incrementalPayload {
+	payloads := make(chan incrementalPayload)
+	send := func(payload incrementalPayload) bool {
+		select {
+		case payloads <- payload:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(payloads)
+		defer func() {
+			if err := recovery.Recovered(ctx, "executeIncremental", recover()); err != nil {
+				send(incrementalPayload{Errors: gqlerrors.FormatErrors(err)})
+			}
+		}()
+
+		initialParams := params
+		initialParams.RequestString = plan.initial
+		initialParams.Context = ctx
+		initial := graphql.Do(initialParams)
+
+		// Fragments below a failed query cannot be delivered.
+		var streamed []incrementalResult
+		deferred := plan.deferred
+		if initial.Data == nil {
+			deferred = nil
+		} else {
+			for _, stream := range plan.streams {
+				streamed = append(streamed, cutStream(initial.Data, stream)...)
+			}
+		}
+
+		pending := len(streamed) + len(deferred)
+		if !send(incrementalPayload{Data: initial.Data, Errors: initial.Errors, Extensions: initial.Extensions, HasNext: pending > 0}) {
+			return
+		}
+		for _, result := range streamed {
+			pending--
+			if !send(incrementalPayload{Incremental: []incrementalResult{result}, HasNext: pending > 0}) {
+				return
+			}
+		}
+
+		results := make(chan []incrementalResult, len(deferred))
+		for _, fragment := range deferred {
+			go func() {
+				fragmentParams := params
+				fragmentParams.RequestString = fragment.query
+				fragmentParams.Context = ctx
+				results <- fragmentResults(graphql.Do(fragmentParams), fragment)
+			}()
+		}
+		for range deferred {
+			var incremental []incrementalResult
+			select {
+			case incremental = <-results:
+			case <-ctx.Done():
+				return
+			}
+			pending--
+			if !send(incrementalPayload{Incremental: incremental, HasNext: pending > 0}) {
+				return
+			}
+		}
+	}()
+
+	return payloads
+}
+
+// fragmentResults returns the data of a deferred fragment for each object
+// the fragment applies to in the result of its query.
+func fragmentResults(result *graphql.Result, fragment deferredFragment) []incrementalResult {
+	var incremental []incrementalResult
+	walkPath(result.Data, fragment.keys, nil, func(path []any, data map[string]any) {
+		incremental = append(incremental, incrementalResult{Data: data, Path: path, Label: fragment.label})
+	})
+	if len(result.Errors) > 0 {
+		if len(incremental) == 0 {
+			path := make([]any, len(fragment.keys))
+			for i, key := range fragment.keys {
+				path[i] = key
+			}
+			incremental = append(incremental, incrementalResult{Path: path, Label: fragment.label})
+		}
+		incremental[0].Errors = result.Errors
+	}
+	return incremental
+}
+
+// cutStream cuts the items after initialCount from each list of the streamed
+// field in data and returns them in chunks.
+func cutStream(data any, stream streamedField) []incrementalResult {
+	var incremental []incrementalResult
+	parentKeys, key := stream.keys[:len(stream.keys)-1], stream.keys[len(stream.keys)-1]
+	walkPath(data, parentKeys, nil, func(path []any, parent map[string]any) {
+		items, ok := parent[key].([]any)
+		if !ok || len(items) <= stream.initialCount {
+			return
+		}
+		parent[key] = items[:stream.initialCount]
+		for start := stream.initialCount; start < len(items); start += streamChunkSize {
+			end := min(start+streamChunkSize, len(items))
+			incremental = append(incremental, incrementalResult{
+				Items: items[start:end],
+				Path:  append(slices.Clone(path), key, start),
+				Label: stream.label,
+			})
+		}
+	})
+	return incremental
+}
+
+// walkPath calls visit with each object reached by following keys from
+// value, descending into every item of the lists on the way.
+func walkPath(value any, keys []string, path []any, visit func(path []any, object map[string]any)) {
+	switch v := value.(type) {
+	case []any:
+		for i, item := range v {
+			walkPath(item, keys, append(slices.Clone(path), i), visit)
+		}
+	case map[string]any:
+		if len(keys) == 0 {
+			visit(path, v)
+			return
+		}
+		walkPath(v[keys[0]], keys[1:], append(slices.Clone(path), keys[0]), visit)
+	}
+}
+
+// multipartBoundary separates the payloads of multipart responses, as
+// expected by Apollo Client and graphql-http.
+const multipartBoundary = "-"
+
+// withIncrementalDelivery answers POST requests accepting multipart/mixed
+// whose query uses @defer or @stream with a multipart response of the
+// initial result followed by the incremental payloads. Other requests are
+// passed to next.
+func (s *GraphQLServer) withIncrementalDelivery(schema *graphql.Schema, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Body == nil || !strings.Contains(r.Header.Get("Accept"), "multipart/mixed") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Error reading request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var params batchedOperation
+		if err := json.Unmarshal(body, &params); err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		plan, ok := planIncremental(schema, params.Query, params.OperationName, params.Variables)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		logger := log.FromContext(r.Context())
+		w.Header().Set("Content-Type", `multipart/mixed; boundary="`+multipartBoundary+`"; deferSpec=20220824`)
+		w.WriteHeader(http.StatusOK)
+		flusher := http.NewResponseController(w)
+
+		failed := false
+		payloads := executeIncremental(r.Context(), graphql.Params{
+			Schema:         *schema,
+			VariableValues: params.Variables,
+			OperationName:  params.OperationName,
+		}, plan)
+		for payload := range payloads {
+			failed = failed || len(payload.Errors) > 0 || slices.ContainsFunc(payload.Incremental, func(r incrementalResult) bool { return len(r.Errors) > 0 })
+			data, err := json.Marshal(payload)
+			if err != nil {
+				logger.Error(err, "Error marshalling incremental payload")
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "\r\n--%s\r\nContent-Type: application/json; charset=utf-8\r\n\r\n%s", multipartBoundary, data); err != nil {
+				logger.V(4).Error(err, "Failed to write incremental payload")
+				return
+			}
+			if err := flusher.Flush(); err != nil {
+				logger.V(4).Error(err, "Failed to flush incremental payload")
+				return
+			}
+		}
+		if _, err := fmt.Fprintf(w, "\r\n--%s--\r\n", multipartBoundary); err != nil {
+			logger.V(4).Error(err, "Failed to write multipart end")
+		}
+
+		s.served.Add(1)
+		if failed {
+			s.failed.Add(1)
+		}
+	})
+}
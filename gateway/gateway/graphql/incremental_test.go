@@ -0,0 +1,236 @@
+package graphql
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/config"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/middleware"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIncrementalTestSchema returns a schema with a list of pods, each with a
+// name and an owner.
+func newIncrementalTestSchema(t *testing.T) *graphql.Schema {
+	t.Helper()
+	owner := graphql.NewObject(graphql.ObjectConfig{Name: "Owner", Fields: graphql.Fields{
+		"name": &graphql.Field{Type: graphql.String},
+	}})
+	pod := graphql.NewObject(graphql.ObjectConfig{Name: "Pod", Fields: graphql.Fields{
+		"name": &graphql.Field{Type: graphql.String},
+		"owner": &graphql.Field{
+			Type: owner,
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return map[string]any{"name": "owner-of-" + p.Source.(map[string]any)["name"].(string)}, nil
+			},
+		},
+	}})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+			"pods": &graphql.Field{
+				Type: graphql.NewList(pod),
+				Args: graphql.FieldConfigArgument{"prefix": &graphql.ArgumentConfig{Type: graphql.String}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					prefix, _ := p.Args["prefix"].(string)
+					var pods []any
+					for _, name := range []string{"a", "b", "c"} {
+						pods = append(pods, map[string]any{"name": prefix + name})
+					}
+					return pods, nil
+				},
+			},
+		}}),
+		Directives: types.Directives,
+	})
+	require.NoError(t, err)
+	return &schema
+}
+
+func TestPlanIncremental(t *testing.T) {
+	schema := newIncrementalTestSchema(t)
+
+	tests := []struct {
+		name          string
+		query         string
+		variables     map[string]any
+		wantOK        bool
+		wantInitial   string
+		wantDeferred  []string
+		wantStreamKey []string
+	}{
+		{name: "no directives", query: `{ pods { name } }`},
+		{name: "mutation", query: `mutation { pods { name } }`},
+		{name: "invalid", query: `{ pods { missing } }`},
+		{name: "disabled defer", query: `query ($d: Boolean) { pods { name ... @defer(if: $d) { owner { name } } } }`, variables: map[string]any{"d": false}},
+		{
+			name:         "inline fragment",
+			query:        `query ($p: String) { pods(prefix: $p) { name ... @defer(label: "owner") { owner { name } } } }`,
+			wantOK:       true,
+			wantInitial:  "query ($p: String) {\n  pods(prefix: $p) {\n    name\n  }\n}\n",
+			wantDeferred: []string{"query ($p: String) {\n  pods(prefix: $p) {\n    ... on Pod {\n      owner {\n        name\n      }\n    }\n  }\n}\n"},
+		},
+		{
+			name:         "named fragment with everything deferred",
+			query:        `{ pods { ...Owner @defer } } fragment Owner on Pod { owner { name } }`,
+			wantOK:       true,
+			wantInitial:  "{\n  pods {\n    __typename\n  }\n}\n",
+			wantDeferred: []string{"{\n  pods {\n    ... on Pod {\n      owner {\n        name\n      }\n    }\n  }\n}\n"},
+		},
+		{
+			name:          "stream",
+			query:         `{ list: pods @stream(initialCount: 1) { name } }`,
+			wantOK:        true,
+			wantInitial:   "{\n  list: pods {\n    name\n  }\n}\n",
+			wantStreamKey: []string{"list"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, ok := planIncremental(schema, tt.query, "", tt.variables)
+			require.Equal(t, tt.wantOK, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, tt.wantInitial, plan.initial)
+			var deferred []string
+			for _, fragment := range plan.deferred {
+				deferred = append(deferred, fragment.query)
+			}
+			assert.Equal(t, tt.wantDeferred, deferred)
+			if tt.wantStreamKey != nil {
+				require.Len(t, plan.streams, 1)
+				assert.Equal(t, tt.wantStreamKey, plan.streams[0].keys)
+			}
+		})
+	}
+}
+
+func TestWithIncrementalDelivery(t *testing.T) {
+	schema := newIncrementalTestSchema(t)
+	s := NewGraphQLServer(config.GraphQL{})
+	h := s.CreateHandler(schema)
+
+	body := `{"query": "{ pods @stream(initialCount: 2) { name } more: pods { name ... @defer(label: \"owner\") { owner { name } } } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Accept", "multipart/mixed")
+	rec := httptest.NewRecorder()
+	h.Handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	mediaType, params, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	var payloads []string
+	reader := multipart.NewReader(rec.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(part)
+		require.NoError(t, err)
+		payloads = append(payloads, string(data))
+	}
+
+	require.Len(t, payloads, 3)
+	assert.JSONEq(t, `{"data": {"pods": [{"name": "a"}, {"name": "b"}], "more": [{"name": "a"}, {"name": "b"}, {"name": "c"}]}, "hasNext": true}`, payloads[0])
+	assert.JSONEq(t, `{"incremental": [{"items": [{"name": "c"}], "path": ["pods", 2]}], "hasNext": true}`, payloads[1])
+	assert.JSONEq(t, `{"incremental": [
+		{"data": {"owner": {"name": "owner-of-a"}}, "path": ["more", 0], "label": "owner"},
+		{"data": {"owner": {"name": "owner-of-b"}}, "path": ["more", 1], "label": "owner"},
+		{"data": {"owner": {"name": "owner-of-c"}}, "path": ["more", 2], "label": "owner"}
+	], "hasNext": false}`, payloads[2])
+
+	served, failed := s.Results()
+	assert.Equal(t, uint64(1), served)
+	assert.Zero(t, failed)
+
+	t.Run("without multipart", func(t *testing.T) {
+		body := `{"query": "{ pods @stream(initialCount: 2) { name } more: pods { name ... on Pod @defer { owner { name } } } }"}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		h.Handler.ServeHTTP(rec, req)
+
+		var result struct {
+			Data map[string][]any `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+		assert.Len(t, result.Data["pods"], 3)
+		assert.Len(t, result.Data["more"], 3)
+	})
+}
+
+func TestWithIncrementalDelivery_Mirrored(t *testing.T) {
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body) //nolint:errcheck
+	}))
+	defer shadow.Close()
+
+	s := NewGraphQLServer(config.GraphQL{})
+	h := middleware.WithMirror(s.CreateHandler(newIncrementalTestSchema(t)).Handler, middleware.MirrorConfig{
+		TargetURL:  shadow.URL,
+		Percentage: 100,
+		Timeout:    time.Second,
+	})
+
+	body := `{"query": "{ pods @stream(initialCount: 1) { name } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Accept", "multipart/mixed")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	_, params, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	require.NoError(t, err)
+
+	var payloads []string
+	reader := multipart.NewReader(rec.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(part)
+		require.NoError(t, err)
+		payloads = append(payloads, string(data))
+	}
+
+	require.Len(t, payloads, 2, "flushing must reach the client through the mirror")
+	assert.JSONEq(t, `{"data": {"pods": [{"name": "a"}]}, "hasNext": true}`, payloads[0])
+	assert.JSONEq(t, `{"incremental": [{"items": [{"name": "b"}, {"name": "c"}], "path": ["pods", 1]}], "hasNext": false}`, payloads[1])
+}
+
+func TestHandleSubscription_Incremental(t *testing.T) {
+	schema := newIncrementalTestSchema(t)
+	s := NewGraphQLServer(config.GraphQL{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.HandleSubscription(w, r, schema)
+	}))
+	t.Cleanup(server.Close)
+
+	events := subscribeWith(t, server, `{"query":"{ pods { name ... @defer { owner { name } } } }"}`)
+	ev := nextEvent(t, events)
+	assert.Equal(t, "next", ev.name)
+	assert.JSONEq(t, `{"data": {"pods": [{"name": "a"}, {"name": "b"}, {"name": "c"}]}, "hasNext": true}`, ev.data)
+
+	ev = nextEvent(t, events)
+	assert.Equal(t, "next", ev.name)
+	assert.Contains(t, ev.data, `"hasNext":false`)
+
+	assert.Equal(t, "complete", nextEvent(t, events).name)
+}
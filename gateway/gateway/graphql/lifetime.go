@@ -0,0 +1,85 @@
+package graphql
+
+import (
+	"crypto/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+)
+
+// SubscriptionRenewHeader carries the ID of a subscription to renew. A request
+// with this header is answered with 204 No Content if the subscription was
+// renewed, or 404 Not Found if it does not exist (e.g. it already ended or is
+// served by another replica). Only the token that opened a subscription may
+// renew it.
+const SubscriptionRenewHeader = "X-Subscription-Renew"
+
+// subscriptionLifetime tracks the lifetime of one SSE subscription.
+type subscriptionLifetime struct {
+	id    string
+	token string
+	renew chan struct{}
+}
+
+// lifetimeRegistry holds the subscriptions that can currently be renewed.
+type lifetimeRegistry struct {
+	mu            sync.Mutex
+	subscriptions map[string]*subscriptionLifetime
+}
+
+func (l *lifetimeRegistry) add(token string) *subscriptionLifetime {
+	lt := &subscriptionLifetime{
+		id:    rand.Text(),
+		token: token,
+		renew: make(chan struct{}, 1),
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.subscriptions == nil {
+		l.subscriptions = map[string]*subscriptionLifetime{}
+	}
+	l.subscriptions[lt.id] = lt
+	return lt
+}
+
+func (l *lifetimeRegistry) remove(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.subscriptions, id)
+}
+
+// renew signals the subscription to extend its lifetime. It reports false if
+// no subscription with the ID was opened with the given token.
+func (l *lifetimeRegistry) renew(id, token string) bool {
+	l.mu.Lock()
+	lt, ok := l.subscriptions[id]
+	l.mu.Unlock()
+	if !ok || lt.token != token {
+		return false
+	}
+
+	select {
+	case lt.renew <- struct{}{}:
+	default: // a renewal is already pending
+	}
+	return true
+}
+
+// HandleRenewal renews the lifetime of the subscription with the given ID.
+func (s *GraphQLServer) HandleRenewal(w http.ResponseWriter, r *http.Request, id string) {
+	token, _ := utilscontext.GetTokenFromCtx(r.Context())
+	if !s.lifetimes.renew(id, token) {
+		http.Error(w, "subscription not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// lifetimeEvent is the payload of the "expiring" and "renewed" SSE events.
+type lifetimeEvent struct {
+	ID        string    `json:"id"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
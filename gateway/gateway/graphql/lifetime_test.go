@@ -0,0 +1,212 @@
+package graphql
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/config"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSchema(t *testing.T) *graphql.Schema {
+	t.Helper()
+	fields := graphql.Fields{
+		"ping": &graphql.Field{
+			Type:    graphql.String,
+			Resolve: func(graphql.ResolveParams) (any, error) { return "pong", nil },
+		},
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: fields}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{Name: "Subscription", Fields: graphql.Fields{
+			"ping": &graphql.Field{
+				Type: graphql.String,
+				Subscribe: func(p graphql.ResolveParams) (any, error) {
+					ch := make(chan any)
+					go func() {
+						<-p.Context.Done()
+						close(ch)
+					}()
+					return ch, nil
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) { return p.Source, nil },
+			},
+		}}),
+	})
+	require.NoError(t, err)
+	return &schema
+}
+
+type sseEvent struct {
+	name string
+	id   string
+	data string
+}
+
+// subscribe opens a subscription against server and streams its events.
+func subscribe(t *testing.T, server *httptest.Server) <-chan sseEvent {
+	t.Helper()
+	return subscribeWith(t, server, `{"query":"subscription { ping }"}`)
+}
+
+// subscribeWith sends body to server as a subscription request with the
+// given header names and values and streams the events.
+func subscribeWith(t *testing.T, server *httptest.Server, body string, headers ...string) <-chan sseEvent {
+	t.Helper()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, server.URL, strings.NewReader(body))
+	require.NoError(t, err)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	events := make(chan sseEvent)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close() //nolint:errcheck
+		scanner := bufio.NewScanner(resp.Body)
+		var ev sseEvent
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				ev.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "id: "):
+				ev.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				ev.data = strings.TrimPrefix(line, "data: ")
+			case line == "" && ev.name != "":
+				events <- ev
+				ev = sseEvent{}
+			}
+		}
+	}()
+	return events
+}
+
+func nextEvent(t *testing.T, events <-chan sseEvent) sseEvent {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		require.True(t, ok, "stream ended")
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return sseEvent{}
+	}
+}
+
+func newLifetimeServer(t *testing.T, cfg config.GraphQL) (*GraphQLServer, *httptest.Server) {
+	t.Helper()
+	s := NewGraphQLServer(cfg)
+	schema := newTestSchema(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.HandleSubscription(w, r.WithContext(utilscontext.SetToken(r.Context(), "token-a")), schema)
+	}))
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+func TestHandleSubscription_MaxLifetime(t *testing.T) {
+	_, server := newLifetimeServer(t, config.GraphQL{
+		SubscriptionMaxLifetime:   200 * time.Millisecond,
+		SubscriptionExpiryWarning: 100 * time.Millisecond,
+	})
+
+	events := subscribe(t, server)
+
+	ev := nextEvent(t, events)
+	require.Equal(t, "expiring", ev.name)
+	var payload lifetimeEvent
+	require.NoError(t, json.Unmarshal([]byte(ev.data), &payload))
+	assert.NotEmpty(t, payload.ID)
+	assert.False(t, payload.ExpiresAt.IsZero())
+
+	assert.Equal(t, "complete", nextEvent(t, events).name)
+}
+
+func TestHandleSubscription_Renewal(t *testing.T) {
+	s, server := newLifetimeServer(t, config.GraphQL{
+		SubscriptionMaxLifetime:   300 * time.Millisecond,
+		SubscriptionExpiryWarning: 200 * time.Millisecond,
+	})
+
+	events := subscribe(t, server)
+
+	ev := nextEvent(t, events)
+	require.Equal(t, "expiring", ev.name)
+	var expiring lifetimeEvent
+	require.NoError(t, json.Unmarshal([]byte(ev.data), &expiring))
+
+	assert.False(t, s.lifetimes.renew(expiring.ID, "token-b"), "renewal with another token must fail")
+	assert.False(t, s.lifetimes.renew("unknown", "token-a"))
+	require.True(t, s.lifetimes.renew(expiring.ID, "token-a"))
+
+	ev = nextEvent(t, events)
+	require.Equal(t, "renewed", ev.name)
+	var renewed lifetimeEvent
+	require.NoError(t, json.Unmarshal([]byte(ev.data), &renewed))
+	assert.Equal(t, expiring.ID, renewed.ID)
+	assert.True(t, renewed.ExpiresAt.After(expiring.ExpiresAt))
+
+	assert.Equal(t, "expiring", nextEvent(t, events).name)
+	assert.Equal(t, "complete", nextEvent(t, events).name)
+
+	assert.False(t, s.lifetimes.renew(expiring.ID, "token-a"), "ended subscriptions cannot be renewed")
+}
+
+func TestHandleRenewal(t *testing.T) {
+	s := NewGraphQLServer(config.GraphQL{})
+	lt := s.lifetimes.add("token-a")
+
+	tests := []struct {
+		name     string
+		id       string
+		token    string
+		wantCode int
+	}{
+		{name: "renewed", id: lt.id, token: "token-a", wantCode: http.StatusNoContent},
+		{name: "other token", id: lt.id, token: "token-b", wantCode: http.StatusNotFound},
+		{name: "unknown id", id: "unknown", token: "token-a", wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req = req.WithContext(utilscontext.SetToken(req.Context(), tt.token))
+			rec := httptest.NewRecorder()
+
+			s.HandleRenewal(rec, req, tt.id)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+}
+
+func TestHandleSubscription_Keepalive(t *testing.T) {
+	_, server := newLifetimeServer(t, config.GraphQL{
+		SSEKeepaliveInterval: 20 * time.Millisecond,
+		SSEWriteTimeout:      time.Second,
+	})
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, server.URL, strings.NewReader(`{"query":"subscription { ping }"}`))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck
+
+	scanner := bufio.NewScanner(resp.Body)
+	require.True(t, scanner.Scan())
+	assert.Equal(t, ":keepalive", scanner.Text())
+	require.True(t, scanner.Scan())
+	assert.Empty(t, scanner.Text())
+}
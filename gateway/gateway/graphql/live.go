@@ -0,0 +1,107 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultLiveQueryInterval is used when no interval is configured.
+const defaultLiveQueryInterval = 2 * time.Second
+
+// isLiveQuery reports whether the operation to execute is a query marked with
+// the @live directive. Documents that do not parse are not live queries; their
+// errors are reported by the regular execution.
+func isLiveQuery(query, operationName string) bool {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(query)})})
+	if err != nil {
+		return false
+	}
+
+	operation := findOperation(doc, operationName)
+	if operation == nil || operation.Operation != ast.OperationTypeQuery {
+		return false
+	}
+
+	for _, directive := range operation.Directives {
+		if directive.Name != nil && directive.Name.Value == types.LiveDirective.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// findOperation returns the operation of doc to execute, nil if there is
+// none or it is ambiguous without an operation name; execution reports it.
+func findOperation(doc *ast.Document, operationName string) *ast.OperationDefinition {
+	var operation *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName == "" || (op.Name != nil && op.Name.Value == operationName) {
+			if operation != nil && operationName == "" {
+				return nil
+			}
+			operation = op
+		}
+	}
+	return operation
+}
+
+// liveQuery executes params every interval until ctx is done and sends the
+// results that differ from the previously sent one. The first result is
+// always sent.
+func liveQuery(ctx context.Context, params graphql.Params, interval time.Duration) <-chan *graphql.Result {
+	if interval <= 0 {
+		interval = defaultLiveQueryInterval
+	}
+
+	results := make(chan *graphql.Result)
+	go func() {
+		defer close(results)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var last []byte
+		for {
+			params.Context = ctx
+			result := graphql.Do(params)
+			if ctx.Err() != nil {
+				return
+			}
+
+			current, err := json.Marshal(result)
+			if err != nil {
+				log.FromContext(ctx).Error(err, "Error marshalling live query result")
+			}
+			if err != nil || !bytes.Equal(current, last) {
+				last = current
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
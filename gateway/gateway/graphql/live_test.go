@@ -0,0 +1,69 @@
+package graphql
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/config"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsLiveQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		operationName string
+		want          bool
+	}{
+		{name: "live query", query: `query @live { ping }`, want: true},
+		{name: "plain query", query: `{ ping }`},
+		{name: "subscription", query: `subscription @live { ping }`},
+		{name: "selected live operation", query: `query A { ping } query B @live { ping }`, operationName: "B", want: true},
+		{name: "selected plain operation", query: `query A { ping } query B @live { ping }`, operationName: "A"},
+		{name: "ambiguous operation", query: `query A @live { ping } query B @live { ping }`},
+		{name: "invalid document", query: `query @live {`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isLiveQuery(tt.query, tt.operationName))
+		})
+	}
+}
+
+func TestHandleSubscription_LiveQuery(t *testing.T) {
+	// The value changes on every third execution, so unchanged results
+	// in between must be suppressed.
+	var executions atomic.Int64
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+			"counter": &graphql.Field{
+				Type: graphql.Int,
+				Resolve: func(graphql.ResolveParams) (any, error) {
+					return (executions.Add(1) - 1) / 3, nil
+				},
+			},
+		}}),
+		Directives: types.Directives,
+	})
+	require.NoError(t, err)
+
+	s := NewGraphQLServer(config.GraphQL{LiveQueryInterval: 10 * time.Millisecond})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.HandleSubscription(w, r, &schema)
+	}))
+	t.Cleanup(server.Close)
+
+	events := subscribeWith(t, server, `{"query":"query @live { counter }"}`)
+	for _, want := range []string{`{"data":{"counter":0}}`, `{"data":{"counter":1}}`, `{"data":{"counter":2}}`} {
+		ev := nextEvent(t, events)
+		assert.Equal(t, "next", ev.name)
+		assert.JSONEq(t, want, ev.data)
+	}
+}
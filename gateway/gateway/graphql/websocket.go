@@ -0,0 +1,417 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/websocket"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// WebSocketProtocol is the WebSocket subprotocol of GraphQL over WebSocket
+// spoken by Apollo and urql clients, see
+// https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md.
+const WebSocketProtocol = "graphql-transport-ws"
+
+// webSocketInitTimeout is how long a client has to send connection_init after
+// connecting.
+const webSocketInitTimeout = 10 * time.Second
+
+// webSocketWriteTimeout bounds writing a message to a client.
+const webSocketWriteTimeout = 10 * time.Second
+
+// Message types of the graphql-transport-ws protocol.
+const (
+	messageConnectionInit = "connection_init"
+	messageConnectionAck  = "connection_ack"
+	messagePing           = "ping"
+	messagePong           = "pong"
+	messageSubscribe      = "subscribe"
+	messageNext           = "next"
+	messageError          = "error"
+	messageComplete       = "complete"
+)
+
+// Close codes of the graphql-transport-ws protocol.
+const (
+	closeBadRequest               = 4400
+	closeUnauthorized             = 4401
+	closeForbidden                = 4403
+	closeSubprotocolNotAcceptable = 4406
+	closeInitTimeout              = 4408
+	closeSubscriberExists         = 4409
+	closeTooManyInitRequests      = 4429
+)
+
+// webSocketMessage is a message of the graphql-transport-ws protocol.
+type webSocketMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// subscribePayload is the payload of a subscribe message.
+type subscribePayload struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+	Extensions    map[string]any `json:"extensions"`
+}
+
+// WebSocketSession is what a WebSocket connection serves once its
+// connection_init message was accepted.
+type WebSocketSession struct {
+	// Context is the context operations are executed in. It carries the
+	// token of the connection.
+	Context context.Context
+	Schema  *graphql.Schema
+	// Prepare validates an operation and returns the context to execute it
+	// in. When nil, operations are executed in Context.
+	Prepare func(ctx context.Context, query string, extensions map[string]any) (context.Context, error)
+}
+
+// WebSocketAuthenticator authenticates the bearer token a WebSocket
+// connection was initialised with and returns the session to serve. The
+// token is empty if the client sent none. Errors close the connection as
+// forbidden.
+type WebSocketAuthenticator func(ctx context.Context, token string) (*WebSocketSession, error)
+
+var upgrader = websocket.Upgrader{
+	Subprotocols: []string{WebSocketProtocol},
+	// Connections are authenticated with the token of their connection_init
+	// message, not with cookies, so other origins cannot act on behalf of a
+	// user.
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// IsWebSocketRequest reports whether r asks to upgrade to a WebSocket
+// connection.
+func IsWebSocketRequest(r *http.Request) bool {
+	return websocket.IsWebSocketUpgrade(r)
+}
+
+// HandleWebSocket serves GraphQL subscriptions, @live queries, queries and
+// mutations over a WebSocket connection using the graphql-transport-ws
+// protocol. The connection is authenticated with the Authorization entry of
+// the connection_init payload, falling back to the token of the upgrade
+// request.
+func (s *GraphQLServer) HandleWebSocket(w http.ResponseWriter, r *http.Request, authenticate WebSocketAuthenticator) {
+	logger := log.FromContext(r.Context())
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already replied with an HTTP error.
+		logger.V(4).Error(err, "Failed to upgrade WebSocket connection")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	c := &webSocketConnection{
+		server:        s,
+		conn:          conn,
+		logger:        logger,
+		subscriptions: map[string]*webSocketOperation{},
+	}
+	defer func() {
+		cancel()
+		c.wg.Wait()
+		conn.Close() //nolint:errcheck
+	}()
+
+	if conn.Subprotocol() != WebSocketProtocol {
+		c.close(closeSubprotocolNotAcceptable, "Subprotocol not acceptable")
+		return
+	}
+
+	// The request context ends with the subscription timeout or on shutdown;
+	// closing the connection then ends the read loop below.
+	stop := context.AfterFunc(ctx, func() {
+		c.close(websocket.CloseGoingAway, "")
+		conn.Close() //nolint:errcheck
+	})
+	defer stop()
+
+	var initialised atomic.Bool
+	initTimer := time.AfterFunc(webSocketInitTimeout, func() {
+		if !initialised.Load() {
+			c.close(closeInitTimeout, "Connection initialisation timeout")
+			conn.Close() //nolint:errcheck
+		}
+	})
+	defer initTimer.Stop()
+
+	var session *WebSocketSession
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			logger.V(4).Info("WebSocket connection closed", "reason", err.Error())
+			return
+		}
+
+		var msg webSocketMessage
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Type == "" {
+			c.close(closeBadRequest, "Invalid message received")
+			return
+		}
+
+		switch msg.Type {
+		case messageConnectionInit:
+			if initialised.Swap(true) {
+				c.close(closeTooManyInitRequests, "Too many initialisation requests")
+				return
+			}
+			token := connectionToken(msg.Payload)
+			if token == "" {
+				token, _ = utilscontext.GetTokenFromCtx(ctx)
+			}
+			session, err = authenticate(ctx, token)
+			if err != nil {
+				logger.V(4).Info("Rejected WebSocket connection", "reason", err.Error())
+				c.close(closeForbidden, "Forbidden")
+				return
+			}
+			if !c.write(webSocketMessage{Type: messageConnectionAck}) {
+				return
+			}
+		case messagePing:
+			if !c.write(webSocketMessage{Type: messagePong}) {
+				return
+			}
+		case messagePong:
+		case messageSubscribe:
+			if session == nil {
+				c.close(closeUnauthorized, "Unauthorized")
+				return
+			}
+			var payload subscribePayload
+			if msg.ID == "" || json.Unmarshal(msg.Payload, &payload) != nil || payload.Query == "" {
+				c.close(closeBadRequest, "Invalid message received")
+				return
+			}
+			if !c.subscribe(msg.ID, session, payload) {
+				c.close(closeSubscriberExists, fmt.Sprintf("Subscriber for %s already exists", msg.ID))
+				return
+			}
+		case messageComplete:
+			c.unsubscribe(msg.ID)
+		default:
+			c.close(closeBadRequest, "Invalid message received")
+			return
+		}
+	}
+}
+
+// connectionToken returns the bearer token of the Authorization entry of a
+// connection_init payload, either at the top level or in its headers.
+func connectionToken(payload json.RawMessage) string {
+	var params map[string]any
+	if len(payload) == 0 || json.Unmarshal(payload, &params) != nil {
+		return ""
+	}
+	if headers, ok := params["headers"].(map[string]any); ok {
+		if token := bearerToken(headers); token != "" {
+			return token
+		}
+	}
+	return bearerToken(params)
+}
+
+// bearerToken returns the token of the Authorization entry of params,
+// matching the key case-insensitively like an HTTP header.
+func bearerToken(params map[string]any) string {
+	for key, value := range params {
+		if header, ok := value.(string); ok && strings.EqualFold(key, "Authorization") {
+			return strings.TrimPrefix(header, "Bearer ")
+		}
+	}
+	return ""
+}
+
+func mustMarshal(v any) []byte {
+	data, _ := json.Marshal(v)
+	return data
+}
+
+// webSocketConnection is the state of one graphql-transport-ws connection.
+type webSocketConnection struct {
+	server *GraphQLServer
+	conn   *websocket.Conn
+	logger logr.Logger
+
+	// writeMu serializes writes, as a connection supports one concurrent
+	// writer.
+	writeMu sync.Mutex
+
+	mu            sync.Mutex
+	subscriptions map[string]*webSocketOperation
+	wg            sync.WaitGroup
+}
+
+// webSocketOperation is an operation running on a connection.
+type webSocketOperation struct {
+	cancel context.CancelFunc
+}
+
+// write sends a message to the client and reports whether it succeeded.
+func (c *webSocketConnection) write(msg webSocketMessage) bool {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout)) //nolint:errcheck
+	if err := c.conn.WriteJSON(msg); err != nil {
+		c.logger.V(4).Error(err, "Failed to write WebSocket message", "type", msg.Type)
+		return false
+	}
+	return true
+}
+
+// close sends a close frame with the given code and reason.
+func (c *webSocketConnection) close(code int, reason string) {
+	message := websocket.FormatCloseMessage(code, reason)
+	if err := c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(webSocketWriteTimeout)); err != nil {
+		c.logger.V(4).Error(err, "Failed to close WebSocket connection", "code", code)
+	}
+}
+
+// subscribe starts the operation with the given ID, reporting false if an
+// operation with the ID is already running.
+func (c *webSocketConnection) subscribe(id string, session *WebSocketSession, payload subscribePayload) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.subscriptions[id]; ok {
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(session.Context)
+	op := &webSocketOperation{cancel: cancel}
+	c.subscriptions[id] = op
+	c.wg.Go(func() {
+		defer c.remove(id, op)
+		if c.run(ctx, id, session, payload) {
+			c.write(webSocketMessage{ID: id, Type: messageComplete})
+		}
+	})
+	return true
+}
+
+// unsubscribe stops the operation with the given ID, if it is running.
+func (c *webSocketConnection) unsubscribe(id string) {
+	c.mu.Lock()
+	op, ok := c.subscriptions[id]
+	c.mu.Unlock()
+	if ok {
+		c.remove(id, op)
+	}
+}
+
+// remove stops op and forgets it, unless its ID was already reused by
+// another operation.
+func (c *webSocketConnection) remove(id string, op *webSocketOperation) {
+	op.cancel()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.subscriptions[id] == op {
+		delete(c.subscriptions, id)
+	}
+}
+
+// run executes an operation and sends its results until it ends or ctx is
+// done. It reports whether the operation ended on its own and needs a
+// complete message; errors before execution are sent as an error message
+// instead.
+func (c *webSocketConnection) run(ctx context.Context, id string, session *WebSocketSession, payload subscribePayload) bool {
+	if session.Prepare != nil {
+		prepared, err := session.Prepare(ctx, payload.Query, payload.Extensions)
+		if err != nil {
+			c.write(webSocketMessage{ID: id, Type: messageError, Payload: mustMarshal(gqlerrors.FormatErrors(err))})
+			return false
+		}
+		ctx = prepared
+	}
+
+	params := graphql.Params{
+		Schema:         *session.Schema,
+		RequestString:  payload.Query,
+		VariableValues: payload.Variables,
+		OperationName:  payload.OperationName,
+		Context:        ctx,
+	}
+
+	// Queries and mutations have a single result; unlike over SSE, clients
+	// may send them over the same connection as their subscriptions.
+	if isSingleResult(session.Schema, payload) {
+		result := graphql.Do(params)
+		if ctx.Err() != nil {
+			return false
+		}
+		return c.write(webSocketMessage{ID: id, Type: messageNext, Payload: mustMarshal(result)})
+	}
+
+	results, incremental := c.server.execute(ctx, session.Schema, params)
+	for {
+		var (
+			data any
+			ok   bool
+		)
+		select {
+		case data, ok = <-results:
+		case data, ok = <-incremental:
+		case <-ctx.Done():
+			return false
+		}
+		if !ok {
+			return ctx.Err() == nil
+		}
+		if res, isResult := data.(*graphql.Result); isResult {
+			if res == nil {
+				continue
+			}
+			resolver.AddErrorExtensions(res.Errors)
+		}
+
+		next, err := json.Marshal(data)
+		if err != nil {
+			c.logger.Error(err, "Error marshalling subscription response")
+			continue
+		}
+		if !c.write(webSocketMessage{ID: id, Type: messageNext, Payload: next}) {
+			return false
+		}
+	}
+}
+
+// isSingleResult reports whether the operation to execute is a query or
+// mutation without @live, @defer or @stream. Documents that do not parse are
+// left to graphql.Subscribe to report.
+func isSingleResult(schema *graphql.Schema, payload subscribePayload) bool {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(payload.Query)})})
+	if err != nil {
+		return false
+	}
+
+	operation := findOperation(doc, payload.OperationName)
+	if operation == nil || operation.Operation == ast.OperationTypeSubscription {
+		return false
+	}
+	if isLiveQuery(payload.Query, payload.OperationName) {
+		return false
+	}
+	_, incremental := planIncremental(schema, payload.Query, payload.OperationName, payload.Variables)
+	return !incremental
+}
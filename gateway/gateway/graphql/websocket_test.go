@@ -0,0 +1,258 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/config"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWebSocketTestSchema(t *testing.T) *graphql.Schema {
+	t.Helper()
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+			"whoami": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					token, _ := utilscontext.GetTokenFromCtx(p.Context)
+					return token, nil
+				},
+			},
+		}}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{Name: "Subscription", Fields: graphql.Fields{
+			"count": &graphql.Field{
+				Type: graphql.Int,
+				Subscribe: func(p graphql.ResolveParams) (any, error) {
+					ch := make(chan any)
+					go func() {
+						defer close(ch)
+						for i := 1; i <= 3; i++ {
+							select {
+							case ch <- i:
+							case <-p.Context.Done():
+								return
+							}
+						}
+					}()
+					return ch, nil
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) { return p.Source, nil },
+			},
+			"forever": &graphql.Field{
+				Type: graphql.Int,
+				Subscribe: func(p graphql.ResolveParams) (any, error) {
+					ch := make(chan any)
+					go func() {
+						<-p.Context.Done()
+						close(ch)
+					}()
+					return ch, nil
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) { return p.Source, nil },
+			},
+		}}),
+	})
+	require.NoError(t, err)
+	return &schema
+}
+
+// dialWebSocket connects to a server handling WebSocket connections with
+// the test schema, accepting the token "valid" and rejecting queries
+// containing "forbidden".
+func dialWebSocket(t *testing.T, protocol string) *websocket.Conn {
+	t.Helper()
+	schema := newWebSocketTestSchema(t)
+	s := NewGraphQLServer(config.GraphQL{})
+	authenticate := func(ctx context.Context, token string) (*WebSocketSession, error) {
+		if token != "valid" {
+			return nil, errors.New("invalid token")
+		}
+		return &WebSocketSession{
+			Context: utilscontext.SetToken(ctx, token),
+			Schema:  schema,
+			Prepare: func(ctx context.Context, query string, _ map[string]any) (context.Context, error) {
+				if strings.Contains(query, "forbidden") {
+					return nil, errors.New("operation is not allowed")
+				}
+				return ctx, nil
+			},
+		}, nil
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.HandleWebSocket(w, r, authenticate)
+	}))
+	t.Cleanup(server.Close)
+
+	dialer := websocket.Dialer{Subprotocols: []string{protocol}}
+	conn, _, err := dialer.DialContext(t.Context(), "ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() }) //nolint:errcheck
+	return conn
+}
+
+func send(t *testing.T, conn *websocket.Conn, msg string) {
+	t.Helper()
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(msg)))
+}
+
+func receive(t *testing.T, conn *websocket.Conn) webSocketMessage {
+	t.Helper()
+	var msg webSocketMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+// closeCode reads from conn until it is closed and returns the close code.
+func closeCode(t *testing.T, conn *websocket.Conn) int {
+	t.Helper()
+	for {
+		_, _, err := conn.ReadMessage()
+		if err == nil {
+			continue
+		}
+		var closeErr *websocket.CloseError
+		require.ErrorAs(t, err, &closeErr)
+		return closeErr.Code
+	}
+}
+
+func TestHandleWebSocket_Subscription(t *testing.T) {
+	conn := dialWebSocket(t, WebSocketProtocol)
+
+	send(t, conn, `{"type":"connection_init","payload":{"Authorization":"Bearer valid"}}`)
+	assert.Equal(t, messageConnectionAck, receive(t, conn).Type)
+
+	send(t, conn, `{"type":"ping"}`)
+	assert.Equal(t, messagePong, receive(t, conn).Type)
+
+	send(t, conn, `{"id":"1","type":"subscribe","payload":{"query":"subscription { count }"}}`)
+	for i := 1; i <= 3; i++ {
+		msg := receive(t, conn)
+		assert.Equal(t, "1", msg.ID)
+		assert.Equal(t, messageNext, msg.Type)
+		assert.JSONEq(t, `{"data":{"count":`+strconv.Itoa(i)+`}}`, string(msg.Payload))
+	}
+	msg := receive(t, conn)
+	assert.Equal(t, "1", msg.ID)
+	assert.Equal(t, messageComplete, msg.Type)
+}
+
+func TestHandleWebSocket_Query(t *testing.T) {
+	conn := dialWebSocket(t, WebSocketProtocol)
+
+	send(t, conn, `{"type":"connection_init","payload":{"headers":{"authorization":"Bearer valid"}}}`)
+	assert.Equal(t, messageConnectionAck, receive(t, conn).Type)
+
+	send(t, conn, `{"id":"q","type":"subscribe","payload":{"query":"{ whoami }"}}`)
+	msg := receive(t, conn)
+	assert.Equal(t, messageNext, msg.Type)
+	assert.JSONEq(t, `{"data":{"whoami":"valid"}}`, string(msg.Payload))
+	assert.Equal(t, messageComplete, receive(t, conn).Type)
+
+	send(t, conn, `{"id":"f","type":"subscribe","payload":{"query":"{ forbidden }"}}`)
+	msg = receive(t, conn)
+	assert.Equal(t, "f", msg.ID)
+	assert.Equal(t, messageError, msg.Type)
+	var errs []map[string]any
+	require.NoError(t, json.Unmarshal(msg.Payload, &errs))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "operation is not allowed", errs[0]["message"])
+}
+
+func TestHandleWebSocket_CompleteByClient(t *testing.T) {
+	conn := dialWebSocket(t, WebSocketProtocol)
+
+	send(t, conn, `{"type":"connection_init","payload":{"Authorization":"Bearer valid"}}`)
+	assert.Equal(t, messageConnectionAck, receive(t, conn).Type)
+
+	// The server does not answer a complete sent by the client, so the next
+	// message is the pong.
+	send(t, conn, `{"id":"1","type":"subscribe","payload":{"query":"subscription { forever }"}}`)
+	send(t, conn, `{"id":"1","type":"complete"}`)
+	send(t, conn, `{"type":"ping"}`)
+	assert.Equal(t, messagePong, receive(t, conn).Type)
+
+	// Once completed, the ID may be reused.
+	send(t, conn, `{"id":"1","type":"subscribe","payload":{"query":"subscription { count }"}}`)
+	assert.Equal(t, messageNext, receive(t, conn).Type)
+}
+
+func TestHandleWebSocket_DuplicateID(t *testing.T) {
+	conn := dialWebSocket(t, WebSocketProtocol)
+
+	send(t, conn, `{"type":"connection_init","payload":{"Authorization":"Bearer valid"}}`)
+	assert.Equal(t, messageConnectionAck, receive(t, conn).Type)
+
+	send(t, conn, `{"id":"1","type":"subscribe","payload":{"query":"subscription { forever }"}}`)
+	send(t, conn, `{"id":"1","type":"subscribe","payload":{"query":"subscription { forever }"}}`)
+	assert.Equal(t, closeSubscriberExists, closeCode(t, conn))
+}
+
+func TestHandleWebSocket_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol string
+		messages []string
+		want     int
+	}{
+		{
+			name:     "unsupported subprotocol",
+			protocol: "graphql-ws",
+			want:     closeSubprotocolNotAcceptable,
+		},
+		{
+			name:     "subscribe before init",
+			protocol: WebSocketProtocol,
+			messages: []string{`{"id":"1","type":"subscribe","payload":{"query":"subscription { count }"}}`},
+			want:     closeUnauthorized,
+		},
+		{
+			name:     "rejected token",
+			protocol: WebSocketProtocol,
+			messages: []string{`{"type":"connection_init","payload":{"Authorization":"Bearer invalid"}}`},
+			want:     closeForbidden,
+		},
+		{
+			name:     "missing token",
+			protocol: WebSocketProtocol,
+			messages: []string{`{"type":"connection_init"}`},
+			want:     closeForbidden,
+		},
+		{
+			name:     "invalid message",
+			protocol: WebSocketProtocol,
+			messages: []string{`not json`},
+			want:     closeBadRequest,
+		},
+		{
+			name:     "repeated init",
+			protocol: WebSocketProtocol,
+			messages: []string{
+				`{"type":"connection_init","payload":{"Authorization":"Bearer valid"}}`,
+				`{"type":"connection_init","payload":{"Authorization":"Bearer valid"}}`,
+			},
+			want: closeTooManyInitRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := dialWebSocket(t, tt.protocol)
+			for _, msg := range tt.messages {
+				send(t, conn, msg)
+			}
+			assert.Equal(t, tt.want, closeCode(t, conn))
+		})
+	}
+}
@@ -0,0 +1,422 @@
+// Package kubectl translates the Kubernetes API requests of a GraphQL
+// operation into equivalent kubectl commands, so users can see what a query
+// or mutation does and reproduce it on the command line.
+//
+// Clients opt in per request with Header. Transport is installed in the
+// roundtripper chain of a cluster's client and records the API requests made
+// with a context of such a request. Extension adds the commands to the
+// response as extensions.kubectl.
+package kubectl
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Header asks for the kubectl equivalents of an operation when set to "true".
+const Header = "X-Kubectl-Equivalent"
+
+// ExtensionName is the key of the commands in the response extensions.
+const ExtensionName = "kubectl"
+
+type enabledKey struct{}
+
+type recorderKey struct{}
+
+// recorder gathers the distinct commands of one GraphQL operation in the
+// order their requests were sent.
+type recorder struct {
+	mu       sync.Mutex
+	commands []string
+}
+
+func (r *recorder) add(command string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !slices.Contains(r.commands, command) {
+		r.commands = append(r.commands, command)
+	}
+}
+
+func (r *recorder) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.commands)
+}
+
+// Middleware enables the translation for requests sending Header.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(Header) == "true" {
+			r = r.WithContext(context.WithValue(r.Context(), enabledKey{}, true))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Transport records the requests sent with the context of an operation
+// asking for kubectl equivalents and passes all requests on to the wrapped
+// transport.
+type Transport struct {
+	inner http.RoundTripper
+}
+
+// NewTransport returns a Transport sending requests with inner.
+func NewTransport(inner http.RoundTripper) *Transport {
+	return &Transport{inner: inner}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rec, ok := req.Context().Value(recorderKey{}).(*recorder); ok {
+		if command := Translate(req.Method, req.URL, req.Header.Get("Content-Type"), requestBody(req)); command != "" {
+			rec.add(command)
+		}
+	}
+	return t.inner.RoundTrip(req)
+}
+
+// requestBody returns a copy of the body of req, leaving the body itself
+// unread.
+func requestBody(req *http.Request) []byte {
+	if req.Body == nil || req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close() //nolint:errcheck
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// resourceRequest is the resource a request path refers to.
+type resourceRequest struct {
+	group, version, resource string
+	namespace, name          string
+	subresource              string
+}
+
+// ref returns the resource in the fully qualified form kubectl accepts,
+// e.g. deployments.v1.apps, so it is unambiguous across API groups.
+func (r resourceRequest) ref() string {
+	if r.group == "" {
+		return r.resource
+	}
+	return r.resource + "." + r.version + "." + r.group
+}
+
+// parsePath splits a resource path into its parts. Paths may carry a prefix,
+// e.g. of a path template, before /api or /apis.
+func parsePath(path string) (resourceRequest, bool) {
+	var req resourceRequest
+	var segments []string
+	if _, rest, ok := strings.Cut(path, "/apis/"); ok {
+		segments = strings.Split(strings.Trim(rest, "/"), "/")
+		if len(segments) < 3 {
+			return req, false
+		}
+		req.group, req.version, segments = segments[0], segments[1], segments[2:]
+	} else if _, rest, ok := strings.Cut(path, "/api/"); ok {
+		segments = strings.Split(strings.Trim(rest, "/"), "/")
+		if len(segments) < 2 {
+			return req, false
+		}
+		req.version, segments = segments[0], segments[1:]
+	} else {
+		return req, false
+	}
+
+	if segments[0] == "namespaces" && len(segments) >= 3 {
+		req.namespace, segments = segments[1], segments[2:]
+	}
+	req.resource = segments[0]
+	if len(segments) > 1 {
+		req.name = segments[1]
+	}
+	if len(segments) > 2 {
+		req.subresource = segments[2]
+	}
+	return req, true
+}
+
+// Translate returns the kubectl command equivalent to an API request, or an
+// empty string for requests that are not about resources, such as discovery.
+// Requests sending an object read it from a heredoc.
+func Translate(method string, u *url.URL, contentType string, body []byte) string {
+	req, ok := parsePath(u.Path)
+	if !ok {
+		return ""
+	}
+	query := u.Query()
+
+	var args []string
+	stdin := false
+	switch method {
+	case http.MethodGet:
+		switch {
+		case req.subresource == "log":
+			args = append(args, "logs", req.name)
+			args = appendFlag(args, "-c", query.Get("container"))
+			args = appendFlag(args, "--tail", query.Get("tailLines"))
+			if since := query.Get("sinceSeconds"); since != "" {
+				args = append(args, "--since="+since+"s")
+			}
+			if query.Get("follow") == "true" {
+				args = append(args, "-f")
+			}
+			args = appendNamespace(args, req)
+			return command(args, nil)
+		case req.name != "":
+			args = append(args, "get", req.ref(), req.name)
+			args = appendFlag(args, "--subresource", req.subresource)
+			args = appendNamespace(args, req)
+		default:
+			args = append(args, "get", req.ref())
+			args = appendSelectors(args, query)
+			if query.Get("watch") == "true" {
+				args = append(args, "--watch")
+			}
+			args = appendListNamespace(args, req)
+		}
+		args = append(args, "-o", "yaml")
+	case http.MethodPost:
+		if req.name != "" {
+			args = append(args, "create", "--raw", rawPath(req))
+		} else {
+			args = append(args, "create")
+			args = appendNamespace(args, req)
+		}
+		stdin = true
+	case http.MethodPut:
+		if req.subresource == "scale" {
+			if replicas := scaleReplicas(body); replicas != "" {
+				args = append(args, "scale", req.ref(), req.name, "--replicas="+replicas)
+				args = appendNamespace(args, req)
+				break
+			}
+		}
+		args = append(args, "replace")
+		args = appendFlag(args, "--subresource", req.subresource)
+		args = appendNamespace(args, req)
+		stdin = true
+	case http.MethodPatch:
+		if strings.HasPrefix(contentType, "application/apply-patch") {
+			args = append(args, "apply", "--server-side")
+			args = appendFlag(args, "--field-manager", query.Get("fieldManager"))
+			if query.Get("force") == "true" {
+				args = append(args, "--force-conflicts")
+			}
+			args = appendFlag(args, "--subresource", req.subresource)
+			args = appendNamespace(args, req)
+			stdin = true
+			break
+		}
+		args = append(args, "patch", req.ref(), req.name)
+		args = appendFlag(args, "--subresource", req.subresource)
+		args = append(args, "--type", patchType(contentType), "-p", string(body))
+		args = appendNamespace(args, req)
+	case http.MethodDelete:
+		args = append(args, "delete", req.ref())
+		switch {
+		case req.name != "":
+			args = append(args, req.name)
+			args = appendDeleteOptions(args, body)
+			args = appendNamespace(args, req)
+		case query.Get("labelSelector") == "" && query.Get("fieldSelector") == "":
+			args = append(args, "--all")
+			args = appendListNamespace(args, req)
+		default:
+			args = appendSelectors(args, query)
+			args = appendListNamespace(args, req)
+		}
+	default:
+		return ""
+	}
+
+	if query.Get("dryRun") == "All" {
+		args = append(args, "--dry-run=server")
+	}
+	if stdin {
+		args = append(args, "-f", "-")
+		return command(args, body)
+	}
+	return command(args, nil)
+}
+
+// appendDeleteOptions appends the flags of the DeleteOptions in the body of a
+// delete request. Preconditions have no flag and are left out.
+func appendDeleteOptions(args []string, body []byte) []string {
+	var opts struct {
+		PropagationPolicy  string `json:"propagationPolicy"`
+		GracePeriodSeconds *int64 `json:"gracePeriodSeconds"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &opts) != nil {
+		return args
+	}
+	if opts.PropagationPolicy != "" {
+		args = append(args, "--cascade="+strings.ToLower(opts.PropagationPolicy))
+	}
+	if opts.GracePeriodSeconds != nil {
+		args = append(args, "--grace-period="+strconv.FormatInt(*opts.GracePeriodSeconds, 10))
+	}
+	return args
+}
+
+func appendFlag(args []string, flag, value string) []string {
+	if value == "" {
+		return args
+	}
+	return append(args, flag, value)
+}
+
+func appendSelectors(args []string, query url.Values) []string {
+	args = appendFlag(args, "-l", query.Get("labelSelector"))
+	return appendFlag(args, "--field-selector", query.Get("fieldSelector"))
+}
+
+func appendNamespace(args []string, req resourceRequest) []string {
+	return appendFlag(args, "-n", req.namespace)
+}
+
+// appendListNamespace adds the namespace of a request for a collection.
+// Collections without a namespace span all namespaces, which kubectl only
+// does with -A; the flag is ignored for cluster-scoped resources.
+func appendListNamespace(args []string, req resourceRequest) []string {
+	if req.namespace == "" {
+		return append(args, "-A")
+	}
+	return appendNamespace(args, req)
+}
+
+// rawPath returns the API path of a subresource request, without any prefix.
+func rawPath(req resourceRequest) string {
+	path := "/api/" + req.version
+	if req.group != "" {
+		path = "/apis/" + req.group + "/" + req.version
+	}
+	if req.namespace != "" {
+		path += "/namespaces/" + req.namespace
+	}
+	path += "/" + req.resource + "/" + req.name
+	if req.subresource != "" {
+		path += "/" + req.subresource
+	}
+	return path
+}
+
+func patchType(contentType string) string {
+	switch {
+	case strings.HasPrefix(contentType, string(types.JSONPatchType)):
+		return "json"
+	case strings.HasPrefix(contentType, string(types.StrategicMergePatchType)):
+		return "strategic"
+	default:
+		return "merge"
+	}
+}
+
+var replicasPattern = regexp.MustCompile(`"replicas":\s*(\d+)`)
+
+// scaleReplicas returns the replicas of a Scale object, or an empty string
+// if body has none.
+func scaleReplicas(body []byte) string {
+	if m := replicasPattern.FindSubmatch(body); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// command joins args to a shell command line, quoting where needed, and
+// appends body as a heredoc.
+func command(args []string, body []byte) string {
+	quoted := make([]string, 0, len(args)+1)
+	quoted = append(quoted, "kubectl")
+	for _, arg := range args {
+		quoted = append(quoted, quote(arg))
+	}
+	line := strings.Join(quoted, " ")
+	if len(body) == 0 {
+		return line
+	}
+	return line + " <<'EOF'\n" + strings.TrimRight(string(body), "\n") + "\nEOF"
+}
+
+var safeArg = regexp.MustCompile(`^[a-zA-Z0-9._/:=,+@-]+$`)
+
+// quote returns arg as a single shell word.
+func quote(arg string) string {
+	if safeArg.MatchString(arg) {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// Extension is a graphql.Extension that adds the kubectl equivalents of an
+// operation to the response extensions if the request asked for them with
+// Header. Other responses are left unchanged.
+type Extension struct{}
+
+var _ graphql.Extension = Extension{}
+
+func (Extension) Init(ctx context.Context, _ *graphql.Params) context.Context {
+	if enabled, _ := ctx.Value(enabledKey{}).(bool); !enabled {
+		return ctx
+	}
+	return context.WithValue(ctx, recorderKey{}, &recorder{})
+}
+
+func (Extension) Name() string {
+	return ExtensionName
+}
+
+func (Extension) ParseDidStart(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+	return ctx, func(error) {}
+}
+
+func (Extension) ValidationDidStart(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+	return ctx, func([]gqlerrors.FormattedError) {}
+}
+
+func (Extension) ExecutionDidStart(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+	return ctx, func(result *graphql.Result) {
+		rec, ok := ctx.Value(recorderKey{}).(*recorder)
+		if !ok || result == nil {
+			return
+		}
+		if result.Extensions == nil {
+			result.Extensions = map[string]any{}
+		}
+		result.Extensions[ExtensionName] = rec.list()
+	}
+}
+
+func (Extension) ResolveFieldDidStart(ctx context.Context, _ *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+	return ctx, func(any, error) {}
+}
+
+// HasResult reports false; commands are added in ExecutionDidStart's finish
+// function so that responses not asking for them carry no extension entry.
+func (Extension) HasResult() bool {
+	return false
+}
+
+func (Extension) GetResult(context.Context) any {
+	return nil
+}
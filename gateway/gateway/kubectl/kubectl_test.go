@@ -0,0 +1,205 @@
+package kubectl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		contentType string
+		body        string
+		want        string
+	}{
+		{
+			name:   "get",
+			method: http.MethodGet,
+			url:    "/api/v1/namespaces/default/pods/web",
+			want:   "kubectl get pods web -n default -o yaml",
+		},
+		{
+			name:   "get with path prefix",
+			method: http.MethodGet,
+			url:    "/clusters/root/apis/apps/v1/namespaces/default/deployments/web",
+			want:   "kubectl get deployments.v1.apps web -n default -o yaml",
+		},
+		{
+			name:   "get cluster-scoped",
+			method: http.MethodGet,
+			url:    "/api/v1/namespaces/default",
+			want:   "kubectl get namespaces default -o yaml",
+		},
+		{
+			name:   "list with selectors",
+			method: http.MethodGet,
+			url:    "/api/v1/namespaces/default/pods?labelSelector=app%3Dweb%2Ctier+in+%28a%29&fieldSelector=status.phase%3DRunning&limit=50",
+			want:   "kubectl get pods -l 'app=web,tier in (a)' --field-selector status.phase=Running -n default -o yaml",
+		},
+		{
+			name:   "list in all namespaces",
+			method: http.MethodGet,
+			url:    "/apis/apps/v1/deployments",
+			want:   "kubectl get deployments.v1.apps -A -o yaml",
+		},
+		{
+			name:   "watch",
+			method: http.MethodGet,
+			url:    "/api/v1/namespaces/default/configmaps?watch=true",
+			want:   "kubectl get configmaps --watch -n default -o yaml",
+		},
+		{
+			name:   "logs",
+			method: http.MethodGet,
+			url:    "/api/v1/namespaces/default/pods/web/log?container=app&tailLines=10&sinceSeconds=60",
+			want:   "kubectl logs web -c app --tail 10 --since=60s -n default",
+		},
+		{
+			name:   "status subresource",
+			method: http.MethodGet,
+			url:    "/apis/apps/v1/namespaces/default/deployments/web/status",
+			want:   "kubectl get deployments.v1.apps web --subresource status -n default -o yaml",
+		},
+		{
+			name:   "create",
+			method: http.MethodPost,
+			url:    "/api/v1/namespaces/default/configmaps?dryRun=All",
+			body:   `{"kind":"ConfigMap"}`,
+			want:   "kubectl create -n default --dry-run=server -f - <<'EOF'\n{\"kind\":\"ConfigMap\"}\nEOF",
+		},
+		{
+			name:   "eviction",
+			method: http.MethodPost,
+			url:    "/api/v1/namespaces/default/pods/web/eviction",
+			body:   `{"kind":"Eviction"}`,
+			want:   "kubectl create --raw /api/v1/namespaces/default/pods/web/eviction -f - <<'EOF'\n{\"kind\":\"Eviction\"}\nEOF",
+		},
+		{
+			name:        "merge patch",
+			method:      http.MethodPatch,
+			url:         "/api/v1/namespaces/default/configmaps/settings",
+			contentType: "application/merge-patch+json",
+			body:        `{"data":{"key":"it's"}}`,
+			want:        `kubectl patch configmaps settings --type merge -p '{"data":{"key":"it'\''s"}}' -n default`,
+		},
+		{
+			name:        "server-side apply",
+			method:      http.MethodPatch,
+			url:         "/apis/apps/v1/namespaces/default/deployments/web?fieldManager=ci&force=true",
+			contentType: "application/apply-patch+yaml",
+			body:        `{"kind":"Deployment"}`,
+			want:        "kubectl apply --server-side --field-manager ci --force-conflicts -n default -f - <<'EOF'\n{\"kind\":\"Deployment\"}\nEOF",
+		},
+		{
+			name:   "scale",
+			method: http.MethodPut,
+			url:    "/apis/apps/v1/namespaces/default/deployments/web/scale",
+			body:   `{"kind":"Scale","spec":{"replicas": 3}}`,
+			want:   "kubectl scale deployments.v1.apps web --replicas=3 -n default",
+		},
+		{
+			name:   "delete",
+			method: http.MethodDelete,
+			url:    "/api/v1/namespaces/default/pods/web",
+			want:   "kubectl delete pods web -n default",
+		},
+		{
+			name:   "delete with options",
+			method: http.MethodDelete,
+			url:    "/apis/apps/v1/namespaces/default/deployments/web",
+			body:   `{"kind":"DeleteOptions","apiVersion":"v1","gracePeriodSeconds":0,"propagationPolicy":"Foreground","preconditions":{"uid":"1234"}}`,
+			want:   "kubectl delete deployments.v1.apps web --cascade=foreground --grace-period=0 -n default",
+		},
+		{
+			name:   "delete collection",
+			method: http.MethodDelete,
+			url:    "/api/v1/namespaces/default/pods?labelSelector=app%3Dweb",
+			want:   "kubectl delete pods -l app=web -n default",
+		},
+		{
+			name:   "delete everything",
+			method: http.MethodDelete,
+			url:    "/api/v1/namespaces/default/pods",
+			want:   "kubectl delete pods --all -n default",
+		},
+		{
+			name:   "discovery",
+			method: http.MethodGet,
+			url:    "/apis/apps/v1",
+		},
+		{
+			name:   "non-resource path",
+			method: http.MethodGet,
+			url:    "/version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, Translate(tt.method, u, tt.contentType, []byte(tt.body)))
+		})
+	}
+}
+
+func TestExtension(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(apiServer.Close)
+	httpClient := &http.Client{Transport: NewTransport(http.DefaultTransport)}
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+			"pods": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					req, err := http.NewRequestWithContext(p.Context, http.MethodGet, apiServer.URL+"/api/v1/namespaces/default/pods", nil)
+					if err != nil {
+						return nil, err
+					}
+					resp, err := httpClient.Do(req)
+					if err != nil {
+						return nil, err
+					}
+					return "ok", resp.Body.Close()
+				},
+			},
+		}}),
+		Extensions: []graphql.Extension{Extension{}},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		header string
+		want   any
+	}{
+		{name: "requested", header: "true", want: []string{"kubectl get pods -n default -o yaml"}},
+		{name: "not requested"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result *graphql.Result
+			handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				result = graphql.Do(graphql.Params{Schema: schema, RequestString: "{ pods }", Context: r.Context()})
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(""))
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.Empty(t, result.Errors)
+			assert.Equal(t, tt.want, result.Extensions[ExtensionName])
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Package maturity hides kinds tagged with a maturity, e.g. alpha, from the
+// schema, except for members of exempt groups. Like RBAC schema pruning, it
+// only changes what users see: the API server still authorizes every request.
+package maturity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	"golang.org/x/sync/singleflight"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxUsers bounds the number of tokens whose membership is cached.
+const maxUsers = 10000
+
+// Without returns the definitions without the kinds tagged with one of the
+// hidden maturities. definitions is returned as is if no kind is hidden.
+func Without(definitions map[string]*spec.Schema, hidden []string) map[string]*spec.Schema {
+	if len(hidden) == 0 {
+		return definitions
+	}
+
+	var without map[string]*spec.Schema
+	for key, definition := range definitions {
+		if !slices.Contains(hidden, extensions.Maturity(definition)) {
+			continue
+		}
+		if without == nil {
+			without = maps.Clone(definitions)
+		}
+		delete(without, key)
+	}
+	if without == nil {
+		return definitions
+	}
+	return without
+}
+
+// Groups tells whether users belong to one of a set of groups, as determined
+// by a SelfSubjectReview.
+type Groups struct {
+	client client.Client
+	groups []string
+
+	// members caches the membership by token hash. nil disables caching.
+	members   *ttlcache.Cache[string, Membership]
+	reviewing singleflight.Group
+}
+
+// Membership is the result of reviewing a user.
+type Membership struct {
+	// User is the name of the user.
+	User string
+	// Member tells whether the user belongs to one of the groups.
+	Member bool
+}
+
+// NewGroups returns Groups reviewing users with c, which must send requests
+// with the token of the request. Memberships are cached for ttl, or reviewed
+// on every request if it is not positive.
+func NewGroups(c client.Client, groups []string, ttl time.Duration) *Groups {
+	g := &Groups{client: c, groups: groups}
+	if ttl > 0 {
+		g.members = ttlcache.New(
+			ttlcache.WithTTL[string, Membership](ttl),
+			ttlcache.WithCapacity[string, Membership](maxUsers),
+		)
+	}
+	return g
+}
+
+// Member reports whether the user of ctx, authenticated with token, belongs
+// to one of the groups.
+func (g *Groups) Member(ctx context.Context, token string) (bool, error) {
+	membership, err := g.Review(ctx, token)
+	return membership.Member, err
+}
+
+// Review returns the name of the user of ctx, authenticated with token, and
+// whether they belong to one of the groups.
+func (g *Groups) Review(ctx context.Context, token string) (Membership, error) {
+	sum := sha256.Sum256([]byte(token))
+	tokenKey := hex.EncodeToString(sum[:])
+
+	if g.members != nil {
+		if item := g.members.Get(tokenKey); item != nil {
+			return item.Value(), nil
+		}
+	}
+
+	result, err, _ := g.reviewing.Do(tokenKey, func() (any, error) {
+		review := &authenticationv1.SelfSubjectReview{}
+		if err := g.client.Create(ctx, review); err != nil {
+			return Membership{}, fmt.Errorf("failed to review user: %w", err)
+		}
+		return Membership{
+			User: review.Status.UserInfo.Username,
+			Member: slices.ContainsFunc(review.Status.UserInfo.Groups, func(group string) bool {
+				return slices.Contains(g.groups, group)
+			}),
+		}, nil
+	})
+	if err != nil {
+		return Membership{}, err
+	}
+
+	membership := result.(Membership)
+	if g.members != nil {
+		g.members.Set(tokenKey, membership, ttlcache.DefaultTTL)
+	}
+	return membership, nil
+}
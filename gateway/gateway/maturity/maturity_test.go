@@ -0,0 +1,138 @@
+package maturity
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apis"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func tagged(maturity string) *spec.Schema {
+	s := &spec.Schema{}
+	s.AddExtension(extensions.MaturityKey, maturity)
+	return s
+}
+
+func TestWithout(t *testing.T) {
+	definitions := map[string]*spec.Schema{
+		"com.example.v1alpha1.Account": tagged(apis.MaturityAlpha),
+		"com.example.v1beta1.Widget":   tagged(apis.MaturityBeta),
+		"com.example.v1.Gadget":        tagged(apis.MaturityStable),
+		"io.k8s.api.core.v1.Pod":       {},
+	}
+
+	tests := []struct {
+		name   string
+		hidden []string
+		want   []string
+	}{
+		{
+			name: "nothing hidden",
+			want: []string{"com.example.v1alpha1.Account", "com.example.v1beta1.Widget", "com.example.v1.Gadget", "io.k8s.api.core.v1.Pod"},
+		},
+		{
+			name:   "alpha hidden",
+			hidden: []string{apis.MaturityAlpha},
+			want:   []string{"com.example.v1beta1.Widget", "com.example.v1.Gadget", "io.k8s.api.core.v1.Pod"},
+		},
+		{
+			name:   "alpha and beta hidden",
+			hidden: []string{apis.MaturityAlpha, apis.MaturityBeta},
+			want:   []string{"com.example.v1.Gadget", "io.k8s.api.core.v1.Pod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Without(definitions, tt.hidden)
+			assert.ElementsMatch(t, tt.want, slices.Collect(maps.Keys(got)))
+			assert.Len(t, definitions, 4, "definitions are not changed")
+		})
+	}
+}
+
+func TestGroupsMember(t *testing.T) {
+	userGroups := map[string][]string{
+		"admin": {"system:authenticated", "platform-admins"},
+		"user":  {"system:authenticated"},
+	}
+
+	var reviews atomic.Int32
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				reviews.Add(1)
+				token, _ := utilscontext.GetTokenFromCtx(ctx)
+				groups, ok := userGroups[token]
+				if !ok {
+					return errors.New("unauthorized")
+				}
+				obj.(*authenticationv1.SelfSubjectReview).Status.UserInfo.Username = token
+				obj.(*authenticationv1.SelfSubjectReview).Status.UserInfo.Groups = groups
+				return nil
+			},
+		}).
+		Build()
+
+	groups := NewGroups(cl, []string{"platform-admins"}, time.Minute)
+	member := func(token string) (bool, error) {
+		return groups.Member(utilscontext.SetToken(t.Context(), token), token)
+	}
+
+	for range 2 {
+		ok, err := member("admin")
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = member("user")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+	assert.Equal(t, int32(2), reviews.Load(), "memberships are cached per token")
+
+	membership, err := groups.Review(utilscontext.SetToken(t.Context(), "admin"), "admin")
+	require.NoError(t, err)
+	assert.Equal(t, Membership{User: "admin", Member: true}, membership)
+
+	ok, err := member("unknown")
+	assert.Error(t, err)
+	assert.False(t, ok)
+}
+
+func TestGroupsMemberWithoutCache(t *testing.T) {
+	var reviews atomic.Int32
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				reviews.Add(1)
+				return nil
+			},
+		}).
+		Build()
+
+	groups := NewGroups(cl, []string{"platform-admins"}, 0)
+	for range 2 {
+		ok, err := groups.Member(t.Context(), "token")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+	assert.Equal(t, int32(2), reviews.Load())
+}
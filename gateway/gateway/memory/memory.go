@@ -0,0 +1,128 @@
+// Package memory tunes the garbage collector of the gateway and reports its
+// memory use, as large schema sets make the heap grow with the number of
+// clusters.
+package memory
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/endpoint"
+)
+
+// Tuning configures the garbage collector. Zero values keep the settings of
+// the GOGC and GOMEMLIMIT environment variables.
+type Tuning struct {
+	// GCPercent sets the heap growth that triggers a collection, like GOGC.
+	// Negative values disable collections until MemoryLimit is reached.
+	GCPercent int
+	// MemoryLimit is the soft limit of the memory of the process in bytes,
+	// like GOMEMLIMIT.
+	MemoryLimit int64
+	// Ballast is the size in bytes of an allocation that is never used,
+	// delaying collections while the heap is small.
+	Ballast int64
+}
+
+// ballast keeps the ballast allocation alive. Its pages are never written,
+// so they take address space, not resident memory.
+var ballast []byte
+
+// Tune applies t to the garbage collector of the process.
+func Tune(t Tuning) {
+	if t.GCPercent != 0 {
+		debug.SetGCPercent(t.GCPercent)
+	}
+	if t.MemoryLimit > 0 {
+		debug.SetMemoryLimit(t.MemoryLimit)
+	}
+	if t.Ballast > 0 {
+		ballast = make([]byte, t.Ballast)
+	}
+}
+
+// Stats is the memory use reported on /debug/memstats.
+type Stats struct {
+	HeapAllocBytes   uint64    `json:"heapAllocBytes"`
+	HeapInuseBytes   uint64    `json:"heapInuseBytes"`
+	HeapObjects      uint64    `json:"heapObjects"`
+	SysBytes         uint64    `json:"sysBytes"`
+	NextGCBytes      uint64    `json:"nextGCBytes"`
+	NumGC            uint32    `json:"numGC"`
+	LastGC           time.Time `json:"lastGC,omitzero"`
+	GCPauseTotal     string    `json:"gcPauseTotal"`
+	Goroutines       int       `json:"goroutines"`
+	GCPercent        int64     `json:"gcPercent"`
+	MemoryLimitBytes uint64    `json:"memoryLimitBytes"`
+	BallastBytes     int       `json:"ballastBytes"`
+	// Clusters are the schema sizes of the served clusters, largest first.
+	Clusters []ClusterStats `json:"clusters"`
+}
+
+// ClusterStats is the size of the schema of a cluster.
+type ClusterStats struct {
+	Cluster string `json:"cluster"`
+	endpoint.SchemaSize
+}
+
+// Handler serves the memory use of the process and the schema sizes returned
+// by schemaSizes as JSON.
+type Handler struct {
+	schemaSizes func() map[string]endpoint.SchemaSize
+}
+
+// NewHandler returns a Handler reporting the schema sizes of schemaSizes,
+// e.g. those of the registry.
+func NewHandler(schemaSizes func() map[string]endpoint.SchemaSize) *Handler {
+	return &Handler{schemaSizes: schemaSizes}
+}
+
+// Stats reads the current memory use. It briefly stops the world.
+func (h *Handler) Stats() Stats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	samples := []metrics.Sample{{Name: "/gc/gogc:percent"}, {Name: "/gc/gomemlimit:bytes"}}
+	metrics.Read(samples)
+
+	stats := Stats{
+		HeapAllocBytes:   m.HeapAlloc,
+		HeapInuseBytes:   m.HeapInuse,
+		HeapObjects:      m.HeapObjects,
+		SysBytes:         m.Sys,
+		NextGCBytes:      m.NextGC,
+		NumGC:            m.NumGC,
+		GCPauseTotal:     time.Duration(m.PauseTotalNs).String(),
+		Goroutines:       runtime.NumGoroutine(),
+		GCPercent:        int64(samples[0].Value.Uint64()),
+		MemoryLimitBytes: samples[1].Value.Uint64(),
+		BallastBytes:     len(ballast),
+		Clusters:         []ClusterStats{},
+	}
+	if m.LastGC > 0 {
+		stats.LastGC = time.Unix(0, int64(m.LastGC))
+	}
+
+	for cluster, size := range h.schemaSizes() {
+		stats.Clusters = append(stats.Clusters, ClusterStats{Cluster: cluster, SchemaSize: size})
+	}
+	slices.SortFunc(stats.Clusters, func(a, b ClusterStats) int {
+		if a.Bytes != b.Bytes {
+			return b.Bytes - a.Bytes
+		}
+		return strings.Compare(a.Cluster, b.Cluster)
+	})
+	return stats
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(h.Stats()) //nolint:errcheck
+}
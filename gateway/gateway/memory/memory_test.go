@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+	"testing"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/endpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTune(t *testing.T) {
+	previousPercent := debug.SetGCPercent(100)
+	previousLimit := debug.SetMemoryLimit(-1)
+	t.Cleanup(func() {
+		debug.SetGCPercent(previousPercent)
+		debug.SetMemoryLimit(previousLimit)
+		ballast = nil
+	})
+
+	Tune(Tuning{GCPercent: 200, MemoryLimit: 1 << 30, Ballast: 1 << 20})
+
+	h := NewHandler(func() map[string]endpoint.SchemaSize { return nil })
+	stats := h.Stats()
+	assert.Equal(t, int64(200), stats.GCPercent)
+	assert.Equal(t, uint64(1<<30), stats.MemoryLimitBytes)
+	assert.Equal(t, 1<<20, stats.BallastBytes)
+
+	// Zero values keep the current settings.
+	Tune(Tuning{})
+	assert.Equal(t, int64(200), h.Stats().GCPercent)
+}
+
+func TestHandler(t *testing.T) {
+	h := NewHandler(func() map[string]endpoint.SchemaSize {
+		return map[string]endpoint.SchemaSize{
+			"small": {Bytes: 10, Types: 2},
+			"large": {Bytes: 1000, Types: 50},
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/memstats", nil))
+
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var got struct {
+		HeapAllocBytes uint64           `json:"heapAllocBytes"`
+		Clusters       []map[string]any `json:"clusters"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.NotZero(t, got.HeapAllocBytes)
+	assert.Equal(t, []map[string]any{
+		{"cluster": "large", "schemaBytes": float64(1000), "types": float64(50)},
+		{"cluster": "small", "schemaBytes": float64(10), "types": float64(2)},
+	}, got.Clusters)
+}
@@ -26,3 +26,42 @@ func NewSubscriptionMetrics(reg prometheus.Registerer) *SubscriptionMetrics {
 	reg.MustRegister(m.Active, m.Total, m.Rejected)
 	return m
 }
+
+// EventMetrics counts the subscription events merged into others to enforce
+// the event limits of their kinds.
+type EventMetrics struct {
+	Dropped *prometheus.CounterVec
+}
+
+func NewEventMetrics(reg prometheus.Registerer) *EventMetrics {
+	m := &EventMetrics{
+		Dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "graphql_subscription_events_dropped_total",
+			Help: "Total number of subscription events merged into later events of the same object by the event limit of their kind.",
+		}, []string{"group", "kind"}),
+	}
+	reg.MustRegister(m.Dropped)
+	return m
+}
+
+// RequestMetrics instruments GraphQL query and mutation requests.
+type RequestMetrics struct {
+	Total    *prometheus.CounterVec
+	Duration *prometheus.HistogramVec
+}
+
+func NewRequestMetrics(reg prometheus.Registerer) *RequestMetrics {
+	m := &RequestMetrics{
+		Total: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "graphql_requests_total",
+			Help: "Total number of GraphQL query and mutation requests by HTTP status code.",
+		}, []string{"code"}),
+		Duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "graphql_request_duration_seconds",
+			Help:    "Latency of GraphQL query and mutation requests by HTTP status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"code"}),
+	}
+	reg.MustRegister(m.Total, m.Duration)
+	return m
+}
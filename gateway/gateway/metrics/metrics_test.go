@@ -48,6 +48,38 @@ func TestSubscriptionMetricsIncDec(t *testing.T) {
 	assert.Equal(t, 1.0, gaugeValue(t, m.Active))
 }
 
+func TestNewRequestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewRequestMetrics(reg)
+
+	m.Total.WithLabelValues("200").Inc()
+	m.Duration.WithLabelValues("200").Observe(0.1)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	names := make(map[string]struct{})
+	for _, f := range families {
+		names[f.GetName()] = struct{}{}
+	}
+	assert.Contains(t, names, "graphql_requests_total")
+	assert.Contains(t, names, "graphql_request_duration_seconds")
+	assert.Equal(t, 1.0, counterValue(t, m.Total.WithLabelValues("200")))
+}
+
+func TestNewEventMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewEventMetrics(reg)
+
+	m.Dropped.WithLabelValues("", "Endpoints").Inc()
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	assert.Equal(t, "graphql_subscription_events_dropped_total", families[0].GetName())
+	assert.Equal(t, 1.0, counterValue(t, m.Dropped.WithLabelValues("", "Endpoints")))
+}
+
 func gaugeValue(t *testing.T, g prometheus.Gauge) float64 {
 	t.Helper()
 	var m dto.Metric
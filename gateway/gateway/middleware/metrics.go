@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// RequestMetrics provides optional instrumentation of served requests.
+type RequestMetrics struct {
+	// Total counts requests by status code.
+	Total *prometheus.CounterVec
+	// Duration observes request latencies by status code.
+	Duration prometheus.ObserverVec
+}
+
+// WithRequestMetrics returns a middleware counting requests and observing
+// their latency by status code. A nil metrics disables the instrumentation.
+func WithRequestMetrics(handler http.Handler, metrics *RequestMetrics) http.Handler {
+	if metrics == nil {
+		return handler
+	}
+	return promhttp.InstrumentHandlerDuration(metrics.Duration, promhttp.InstrumentHandlerCounter(metrics.Total, handler))
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRequestMetrics(t *testing.T) {
+	metrics := &RequestMetrics{
+		Total:    prometheus.NewCounterVec(prometheus.CounterOpts{Name: "requests_total"}, []string{"code"}),
+		Duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "request_duration_seconds"}, []string{"code"}),
+	}
+	handler := WithRequestMetrics(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("fail") {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}), metrics)
+
+	for _, target := range []string{"/", "/", "/?fail"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, target, nil))
+	}
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.Total.WithLabelValues("200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.Total.WithLabelValues("500")))
+	assert.Equal(t, 2, testutil.CollectAndCount(metrics.Duration.(prometheus.Collector)))
+}
+
+func TestWithRequestMetrics_Disabled(t *testing.T) {
+	called := false
+	handler := WithRequestMetrics(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }), nil)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.True(t, called)
+}
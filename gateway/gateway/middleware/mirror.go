@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// MirrorConfig configures shadow traffic mirroring.
+type MirrorConfig struct {
+	// TargetURL is the base URL of the shadow gateway (e.g. "http://gateway-canary:8080").
+	// The original request path is appended to it. Empty disables mirroring.
+	TargetURL string
+
+	// Percentage is the share of read-only requests (0-100) that are mirrored.
+	// 0 disables mirroring.
+	Percentage float64
+
+	// Timeout bounds a single mirrored request. 0 means no timeout.
+	Timeout time.Duration
+
+	// Client is used to send mirrored requests. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// WithMirror returns a middleware that replays a sample of read-only GraphQL
+// requests against a shadow gateway. Mirroring is fire-and-forget: the client
+// always receives the primary response, and the shadow response is only
+// compared against it and logged. Mutations and subscriptions are never mirrored.
+func WithMirror(handler http.Handler, cfg MirrorConfig) http.Handler {
+	if cfg.TargetURL == "" || cfg.Percentage <= 0 {
+		return handler
+	}
+
+	httpClient := cfg.Client
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	targetURL := strings.TrimRight(cfg.TargetURL, "/")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Body == nil || rand.Float64()*100 >= cfg.Percentage {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		if !isReadOnly(body) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &teeWriter{ResponseWriter: w, code: http.StatusOK}
+		handler.ServeHTTP(rec, r)
+
+		header := r.Header.Clone()
+		ctx := context.WithoutCancel(r.Context())
+		go sendMirror(ctx, httpClient, cfg.Timeout, targetURL+r.URL.RequestURI(), header, body, rec.code, rec.buf.Bytes())
+	})
+}
+
+// sendMirror replays the request against the shadow gateway and logs whether
+// its response matches the primary one.
+func sendMirror(ctx context.Context, httpClient *http.Client, timeout time.Duration, url string, header http.Header, body []byte, primaryCode int, primaryBody []byte) {
+	logger := log.FromContext(ctx).WithValues("operation", "mirror", "target", url)
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		logger.Error(err, "Failed to build mirrored request")
+		return
+	}
+	req.Header = header
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		logger.Error(err, "Mirrored request failed")
+		return
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	shadowBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.Error(err, "Failed to read mirrored response")
+		return
+	}
+
+	if resp.StatusCode != primaryCode || !responsesEqual(primaryBody, shadowBody) {
+		logger.Info("Mirrored response differs from primary",
+			"primaryStatus", primaryCode,
+			"shadowStatus", resp.StatusCode,
+			"primaryBytes", len(primaryBody),
+			"shadowBytes", len(shadowBody),
+		)
+		return
+	}
+
+	logger.V(4).Info("Mirrored response matches primary", "status", primaryCode)
+}
+
+// responsesEqual compares two response bodies semantically when both are
+// JSON, so that key ordering and whitespace do not count as differences.
+func responsesEqual(a, b []byte) bool {
+	var av, bv any
+	if json.Unmarshal(a, &av) != nil || json.Unmarshal(b, &bv) != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
+// isReadOnly reports whether every operation in the (possibly batched)
+// request body is a query.
+func isReadOnly(body []byte) bool {
+	var reqs []struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(body, &reqs); err != nil {
+		var single struct {
+			Query string `json:"query"`
+		}
+		if err := json.Unmarshal(body, &single); err != nil {
+			return false
+		}
+		reqs = append(reqs, single)
+	}
+
+	if len(reqs) == 0 {
+		return false
+	}
+
+	for _, req := range reqs {
+		doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query)})})
+		if err != nil {
+			return false
+		}
+		for _, def := range doc.Definitions {
+			if op, ok := def.(*ast.OperationDefinition); ok && op.Operation != ast.OperationTypeQuery {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// teeWriter writes the response through to the client while keeping a copy
+// for comparison with the mirrored response.
+type teeWriter struct {
+	http.ResponseWriter
+	code int
+	buf  bytes.Buffer
+}
+
+func (tw *teeWriter) WriteHeader(code int) {
+	tw.code = code
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+func (tw *teeWriter) Write(p []byte) (int, error) {
+	tw.buf.Write(p)
+	return tw.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController flush the underlying writer, e.g. for
+// the payloads of incremental delivery.
+func (tw *teeWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMirror(t *testing.T) {
+	type mirrored struct {
+		path string
+		auth string
+		body string
+	}
+
+	primary := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"data":{"pods":[]}}`)) //nolint:errcheck
+	})
+
+	tests := []struct {
+		name         string
+		percentage   float64
+		body         string
+		wantMirrored bool
+	}{
+		{
+			name:         "read-only query is mirrored",
+			percentage:   100,
+			body:         `{"query":"{ v1 { Pods { items { metadata { name } } } } }"}`,
+			wantMirrored: true,
+		},
+		{
+			name:         "batched read-only queries are mirrored",
+			percentage:   100,
+			body:         `[{"query":"{ a }"},{"query":"query Q { b }"}]`,
+			wantMirrored: true,
+		},
+		{
+			name:       "mutation is not mirrored",
+			percentage: 100,
+			body:       `{"query":"mutation { v1 { deletePod(name: \"x\") } }"}`,
+		},
+		{
+			name:       "batch containing a mutation is not mirrored",
+			percentage: 100,
+			body:       `[{"query":"{ a }"},{"query":"mutation { b }"}]`,
+		},
+		{
+			name:       "zero percentage disables mirroring",
+			percentage: 0,
+			body:       `{"query":"{ a }"}`,
+		},
+		{
+			name:       "unparsable body is not mirrored",
+			percentage: 100,
+			body:       `not json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := make(chan mirrored, 1)
+			shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				received <- mirrored{path: r.URL.Path, auth: r.Header.Get("Authorization"), body: string(b)}
+				w.Write([]byte(`{"data":{"pods":[]}}`)) //nolint:errcheck
+			}))
+			defer shadow.Close()
+
+			handler := WithMirror(primary, MirrorConfig{TargetURL: shadow.URL, Percentage: tt.percentage, Timeout: time.Second})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/clusters/test/graphql", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `{"data":{"pods":[]}}`, rec.Body.String())
+
+			select {
+			case got := <-received:
+				require.True(t, tt.wantMirrored, "unexpected mirrored request")
+				assert.Equal(t, "/api/clusters/test/graphql", got.path)
+				assert.Equal(t, "Bearer token", got.auth)
+				assert.Equal(t, tt.body, got.body)
+			case <-time.After(200 * time.Millisecond):
+				assert.False(t, tt.wantMirrored, "expected request to be mirrored")
+			}
+		})
+	}
+}
+
+func TestResponsesEqual(t *testing.T) {
+	assert.True(t, responsesEqual([]byte(`{"a":1,"b":2}`), []byte(`{ "b": 2, "a": 1 }`)))
+	assert.False(t, responsesEqual([]byte(`{"a":1}`), []byte(`{"a":2}`)))
+	assert.True(t, responsesEqual([]byte(`plain`), []byte(`plain`)))
+	assert.False(t, responsesEqual([]byte(`plain`), []byte(`{}`)))
+}
@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"k8s.io/utils/lru"
+)
+
+// Automatic persisted query error codes, as expected by Apollo clients.
+const (
+	PersistedQueryNotFoundCode     = "PERSISTED_QUERY_NOT_FOUND"
+	PersistedQueryNotSupportedCode = "PERSISTED_QUERY_NOT_SUPPORTED"
+	PersistedQueryHashMismatchCode = "BAD_REQUEST"
+)
+
+// persistedQueryVersion is the only version of the APQ protocol.
+const persistedQueryVersion = 1
+
+// PersistedQueryCache maps the SHA-256 hashes of queries registered by
+// clients to the query documents, evicting the least recently used ones.
+// It is shared by all clusters, as a hash identifies the same document
+// everywhere.
+type PersistedQueryCache struct {
+	queries *lru.Cache
+}
+
+// NewPersistedQueryCache returns a cache holding up to size queries.
+func NewPersistedQueryCache(size int) *PersistedQueryCache {
+	return &PersistedQueryCache{queries: lru.New(size)}
+}
+
+// Get returns the query registered under a hash.
+func (c *PersistedQueryCache) Get(hash string) (string, bool) {
+	query, ok := c.queries.Get(hash)
+	if !ok {
+		return "", false
+	}
+	return query.(string), true
+}
+
+// Add registers a query under its hash.
+func (c *PersistedQueryCache) Add(hash, query string) {
+	c.queries.Add(hash, query)
+}
+
+// persistedQueryError is a request the persisted query could not be resolved
+// for.
+type persistedQueryError struct {
+	status  int
+	code    string
+	message string
+}
+
+// persistedQueryExtension is the persistedQuery request extension.
+type persistedQueryExtension struct {
+	PersistedQuery *struct {
+		Version    int    `json:"version"`
+		Sha256Hash string `json:"sha256Hash"`
+	} `json:"persistedQuery"`
+}
+
+// WithPersistedQueries returns a middleware implementing Automatic Persisted
+// Queries: requests with a persistedQuery extension may send the SHA-256 hash
+// of the query instead of the document. Unknown hashes are answered with a
+// PersistedQueryNotFound error, upon which clients resend the request with
+// both, registering the query. The middleware fills in the query of
+// requests with known hashes, so later handlers see complete requests. A nil
+// cache disables it.
+func WithPersistedQueries(handler http.Handler, cache *PersistedQueryCache) http.Handler {
+	if cache == nil {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			values := r.URL.Query()
+			extensions := values.Get("extensions")
+			if extensions == "" {
+				break
+			}
+			query, resolved, apqErr := resolvePersistedQuery(cache, values.Get("query"), []byte(extensions))
+			if apqErr != nil {
+				writePersistedQueryError(w, apqErr)
+				return
+			}
+			if resolved {
+				values.Set("query", query)
+				r = r.Clone(r.Context())
+				r.URL.RawQuery = values.Encode()
+			}
+
+		case r.Method == http.MethodPost && r.Body != nil:
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			resolvedBody, apqErr := resolvePersistedQueries(cache, body)
+			if apqErr != nil {
+				writePersistedQueryError(w, apqErr)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(resolvedBody))
+			r.ContentLength = int64(len(resolvedBody))
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// resolvePersistedQueries fills in the queries of the (possibly batched)
+// requests in a body. Bodies without persisted queries and bodies that are
+// not JSON are returned unchanged.
+func resolvePersistedQueries(cache *PersistedQueryCache, body []byte) ([]byte, *persistedQueryError) {
+	var reqs []map[string]json.RawMessage
+	batched := json.Unmarshal(body, &reqs) == nil
+	if !batched {
+		var req map[string]json.RawMessage
+		if err := json.Unmarshal(body, &req); err != nil {
+			return body, nil
+		}
+		reqs = []map[string]json.RawMessage{req}
+	}
+
+	changed := false
+	for _, req := range reqs {
+		extensions, ok := req["extensions"]
+		if !ok {
+			continue
+		}
+		var query string
+		if raw, ok := req["query"]; ok {
+			_ = json.Unmarshal(raw, &query)
+		}
+		query, resolved, apqErr := resolvePersistedQuery(cache, query, extensions)
+		if apqErr != nil {
+			return nil, apqErr
+		}
+		if resolved {
+			req["query"], _ = json.Marshal(query)
+			changed = true
+		}
+	}
+	if !changed {
+		return body, nil
+	}
+
+	var resolved []byte
+	if batched {
+		resolved, _ = json.Marshal(reqs)
+	} else {
+		resolved, _ = json.Marshal(reqs[0])
+	}
+	return resolved, nil
+}
+
+// resolvePersistedQuery returns the query of a request with the given
+// extensions. It registers the query if the request sends it, and looks it
+// up otherwise. resolved is false for requests without a persisted query.
+func resolvePersistedQuery(cache *PersistedQueryCache, query string, extensions []byte) (string, bool, *persistedQueryError) {
+	var ext persistedQueryExtension
+	if err := json.Unmarshal(extensions, &ext); err != nil || ext.PersistedQuery == nil {
+		return query, false, nil
+	}
+	if ext.PersistedQuery.Version != persistedQueryVersion {
+		return "", false, &persistedQueryError{status: http.StatusBadRequest, code: PersistedQueryNotSupportedCode, message: "PersistedQueryNotSupported"}
+	}
+	hash := strings.ToLower(ext.PersistedQuery.Sha256Hash)
+
+	if query != "" {
+		sum := sha256.Sum256([]byte(query))
+		if hex.EncodeToString(sum[:]) != hash {
+			return "", false, &persistedQueryError{status: http.StatusBadRequest, code: PersistedQueryHashMismatchCode, message: "provided sha does not match query"}
+		}
+		cache.Add(hash, query)
+		return query, true, nil
+	}
+
+	query, ok := cache.Get(hash)
+	if !ok {
+		// Clients only retry with the query on a successful response.
+		return "", false, &persistedQueryError{status: http.StatusOK, code: PersistedQueryNotFoundCode, message: "PersistedQueryNotFound"}
+	}
+	return query, true, nil
+}
+
+func writePersistedQueryError(w http.ResponseWriter, e *persistedQueryError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.status)
+	json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+		"errors": []map[string]any{{
+			"message":    e.message,
+			"extensions": map[string]any{"code": e.code},
+		}},
+	})
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPersistedQueries(t *testing.T) {
+	const query = "{ v1 { Pods { items { metadata { name } } } } }"
+	sum := sha256.Sum256([]byte(query))
+	hash := hex.EncodeToString(sum[:])
+	extensions := `{"persistedQuery":{"version":1,"sha256Hash":"` + hash + `"}}`
+
+	// echo answers with the query the handler received.
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(r.URL.Query().Get("query"))) //nolint:errcheck
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		w.Write(b) //nolint:errcheck
+	})
+
+	post := func(body string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/api/clusters/test/graphql", strings.NewReader(body))
+	}
+	get := func(values url.Values) *http.Request {
+		return httptest.NewRequest(http.MethodGet, "/api/clusters/test/graphql?"+values.Encode(), nil)
+	}
+
+	tests := []struct {
+		name       string
+		registered bool
+		req        *http.Request
+		wantStatus int
+		wantCode   string
+		wantQuery  string
+		wantBody   string
+	}{
+		{
+			name:       "unknown hash is not found",
+			req:        post(`{"extensions":` + extensions + `}`),
+			wantStatus: http.StatusOK,
+			wantCode:   PersistedQueryNotFoundCode,
+		},
+		{
+			name:       "query with its hash is passed on",
+			req:        post(`{"query":"` + query + `","extensions":` + extensions + `}`),
+			wantStatus: http.StatusOK,
+			wantQuery:  query,
+		},
+		{
+			name:       "known hash is filled in",
+			registered: true,
+			req:        post(`{"extensions":` + extensions + `,"variables":{"a":1}}`),
+			wantStatus: http.StatusOK,
+			wantQuery:  query,
+		},
+		{
+			name:       "known hash is filled in for batched requests",
+			registered: true,
+			req:        post(`[{"extensions":` + extensions + `},{"query":"{ a }"}]`),
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"extensions":` + extensions + `,"query":"` + query + `"},{"query":"{ a }"}]`,
+		},
+		{
+			name:       "known hash is filled in for GET requests",
+			registered: true,
+			req:        get(url.Values{"extensions": {extensions}}),
+			wantStatus: http.StatusOK,
+			wantBody:   query,
+		},
+		{
+			name:       "hash not matching the query",
+			req:        post(`{"query":"{ a }","extensions":` + extensions + `}`),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   PersistedQueryHashMismatchCode,
+		},
+		{
+			name:       "unsupported version",
+			req:        post(`{"extensions":{"persistedQuery":{"version":2,"sha256Hash":"` + hash + `"}}}`),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   PersistedQueryNotSupportedCode,
+		},
+		{
+			name:       "request without persisted query is unchanged",
+			req:        post(`{"query":"{ a }","extensions":{"other":true}}`),
+			wantStatus: http.StatusOK,
+			wantBody:   `{"query":"{ a }","extensions":{"other":true}}`,
+		},
+		{
+			name:       "unparsable body is unchanged",
+			req:        post(`not json`),
+			wantStatus: http.StatusOK,
+			wantBody:   `not json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewPersistedQueryCache(10)
+			if tt.registered {
+				cache.Add(hash, query)
+			}
+
+			rec := httptest.NewRecorder()
+			WithPersistedQueries(echo, cache).ServeHTTP(rec, tt.req)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+
+			switch {
+			case tt.wantCode != "":
+				var resp struct {
+					Errors []struct {
+						Extensions map[string]any `json:"extensions"`
+					} `json:"errors"`
+				}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				require.Len(t, resp.Errors, 1)
+				assert.Equal(t, tt.wantCode, resp.Errors[0].Extensions["code"])
+			case tt.wantQuery != "":
+				var req struct {
+					Query string `json:"query"`
+				}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &req))
+				assert.Equal(t, tt.wantQuery, req.Query)
+			default:
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWithPersistedQueriesRegisters(t *testing.T) {
+	const query = "{ a }"
+	sum := sha256.Sum256([]byte(query))
+	extensions := `{"persistedQuery":{"version":1,"sha256Hash":"` + hex.EncodeToString(sum[:]) + `"}}`
+
+	cache := NewPersistedQueryCache(10)
+	handler := WithPersistedQueries(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), cache)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"query":"`+query+`","extensions":`+extensions+`}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	registered, ok := cache.Get(hex.EncodeToString(sum[:]))
+	require.True(t, ok)
+	assert.Equal(t, query, registered)
+}
+
+func TestWithPersistedQueriesDisabled(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	assert.NotNil(t, WithPersistedQueries(handler, nil))
+
+	rec := httptest.NewRecorder()
+	WithPersistedQueries(handler, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"abc"}}}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
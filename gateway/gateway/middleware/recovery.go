@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/recovery"
+)
+
+// WithRecovery returns a middleware answering requests whose handler panics
+// with a 500 and a GraphQL error carrying a correlation ID, instead of
+// leaving it to net/http, which does not cover handlers running in their
+// own goroutine, e.g. behind WithTimeout. Responses that were already
+// started, such as subscription streams, are ended.
+func WithRecovery(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &startedWriter{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			err := recovery.Recovered(r.Context(), r.Method+" "+r.URL.Path, v)
+			if err == nil || rw.started {
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Correlation-ID", err.CorrelationID)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"errors": []map[string]any{{
+					"message":    err.Error(),
+					"extensions": err.Extensions(),
+				}},
+			})
+		}()
+		handler.ServeHTTP(rw, r)
+	})
+}
+
+// startedWriter records whether a response was started.
+type startedWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *startedWriter) WriteHeader(code int) {
+	w.started = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *startedWriter) Write(p []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(p)
+}
+
+// Hijack marks the response as started, as the handler takes over the
+// connection.
+func (w *startedWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.started = true
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap lets http.ResponseController flush the underlying writer.
+func (w *startedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRecovery(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+		wantError  bool
+	}{
+		{
+			name: "handler without panic",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"data":{}}`)) //nolint:errcheck
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "panic before the response",
+			handler:    func(w http.ResponseWriter, r *http.Request) { panic("boom") },
+			wantStatus: http.StatusInternalServerError,
+			wantError:  true,
+		},
+		{
+			name: "panic after the response was started",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				panic("boom")
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			// Behind WithTimeout, a panic would otherwise crash the process.
+			WithTimeout(WithRecovery(tt.handler), time.Second).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+			assert.Equal(t, tt.wantStatus, rec.Code)
+
+			if !tt.wantError {
+				return
+			}
+			var resp struct {
+				Errors []struct {
+					Message    string         `json:"message"`
+					Extensions map[string]any `json:"extensions"`
+				} `json:"errors"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			require.Len(t, resp.Errors, 1)
+			assert.NotContains(t, resp.Errors[0].Message, "boom")
+			assert.Equal(t, rec.Header().Get("X-Correlation-ID"), resp.Errors[0].Extensions["correlationId"])
+		})
+	}
+}
+
+func TestWithRecoveryAbortHandler(t *testing.T) {
+	handler := WithRecovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { panic(http.ErrAbortHandler) }))
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
@@ -1,10 +1,12 @@
 package middleware
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"maps"
+	"net"
 	"net/http"
 	"sync"
 	"time"
@@ -38,8 +40,16 @@ func WithTimeout(handler http.Handler, timeout time.Duration) http.Handler {
 		case <-ctx.Done():
 			tw.mu.Lock()
 			tw.timedOut = true
+			hijacked := tw.hijacked
 			tw.mu.Unlock()
 
+			// A hijacked connection, e.g. a WebSocket, is owned by the handler,
+			// which ends it once the context is done.
+			if hijacked {
+				<-done
+				return
+			}
+
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusGatewayTimeout)
 			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
@@ -59,6 +69,7 @@ type timeoutWriter struct {
 	code     int
 	mu       sync.Mutex
 	timedOut bool
+	hijacked bool
 	flusher  http.Flusher // resolved once at construction; nil if unsupported
 }
 
@@ -96,6 +107,21 @@ func (tw *timeoutWriter) Flush() {
 	}
 }
 
+// Hijack implements http.Hijacker, letting handlers take over the
+// connection, e.g. to upgrade it to a WebSocket.
+func (tw *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return nil, nil, context.DeadlineExceeded
+	}
+	conn, rw, err := http.NewResponseController(tw.wrapped).Hijack()
+	if err == nil {
+		tw.hijacked = true
+	}
+	return conn, rw, err
+}
+
 // drain forwards buffered headers, status code, and body to the underlying writer.
 // Must be called with tw.mu held.
 func (tw *timeoutWriter) drain() {
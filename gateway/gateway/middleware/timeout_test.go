@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"encoding/json"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -55,6 +57,31 @@ func TestWithTimeout(t *testing.T) {
 		assert.Equal(t, "request timeout", resp.Errors[0].Message)
 	})
 
+	t.Run("hijacked connection is left to the handler on timeout", func(t *testing.T) {
+		inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, _, err := http.NewResponseController(w).Hijack()
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close() //nolint:errcheck
+			<-r.Context().Done()
+			conn.Write([]byte("bye")) //nolint:errcheck
+		})
+
+		server := httptest.NewServer(WithTimeout(inner, 10*time.Millisecond))
+		defer server.Close()
+
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close() //nolint:errcheck
+		_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\n\r\n"))
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(conn)
+		require.NoError(t, err)
+		assert.Equal(t, "bye", string(body))
+	})
+
 	t.Run("zero timeout disables middleware", func(t *testing.T) {
 		inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
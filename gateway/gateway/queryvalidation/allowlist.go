@@ -0,0 +1,104 @@
+package queryvalidation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// ErrOperationNotAllowed is returned for queries that are not in the
+// allowlist.
+var ErrOperationNotAllowed = errors.New("operation is not in the allowlist")
+
+// Allowlist holds the GraphQL documents that may be executed. Documents are
+// compared after normalizing whitespace, comments and formatting, so clients
+// may send them formatted differently than registered.
+type Allowlist struct {
+	documents map[string]struct{}
+}
+
+// NewAllowlist returns an allowlist of the given documents.
+func NewAllowlist(documents ...string) (*Allowlist, error) {
+	a := &Allowlist{documents: make(map[string]struct{}, len(documents))}
+	for i, document := range documents {
+		normalized, err := normalize(document)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		a.documents[normalized] = struct{}{}
+	}
+	return a, nil
+}
+
+// LoadAllowlist reads the documents of the .graphql and .gql files in a
+// directory. Hidden entries are skipped, so a mounted ConfigMap can be used
+// as the directory.
+func LoadAllowlist(dir string) (*Allowlist, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read operation allowlist: %w", err)
+	}
+
+	a := &Allowlist{documents: make(map[string]struct{})}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if strings.HasPrefix(entry.Name(), ".") || (ext != ".graphql" && ext != ".gql") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		// ConfigMap keys are symlinks, so the target is checked.
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read operation allowlist: %w", err)
+		}
+		normalized, err := normalize(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse operation allowlist %s: %w", path, err)
+		}
+		a.documents[normalized] = struct{}{}
+	}
+
+	if len(a.documents) == 0 {
+		return nil, fmt.Errorf("no .graphql or .gql documents found in operation allowlist %s", dir)
+	}
+	return a, nil
+}
+
+// Allows reports whether query is one of the registered documents.
+func (a *Allowlist) Allows(query string) bool {
+	normalized, err := normalize(query)
+	if err != nil {
+		return false
+	}
+	_, ok := a.documents[normalized]
+	return ok
+}
+
+// Len returns the number of registered documents.
+func (a *Allowlist) Len() int {
+	return len(a.documents)
+}
+
+// normalize parses a document and prints it in canonical form.
+func normalize(document string) (string, error) {
+	src := source.NewSource(&source.Source{Body: []byte(document)})
+	doc, err := parser.Parse(parser.ParseParams{Source: src})
+	if err != nil {
+		return "", err
+	}
+	printed, ok := printer.Print(doc).(string)
+	if !ok {
+		return "", errors.New("failed to print document")
+	}
+	return printed, nil
+}
@@ -0,0 +1,63 @@
+package queryvalidation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAllowlist(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pods.graphql"), []byte("# List pods\nquery Pods {\n  v1 { Pods { items { metadata { name } } } }\n}\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "nodes.gql"), []byte(`query Nodes { v1 { Nodes { items { metadata { name } } } } }`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a document"), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "..data"), 0o700))
+
+	allowlist, err := LoadAllowlist(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, allowlist.Len())
+
+	assert.True(t, allowlist.Allows(`query Pods { v1 { Pods { items { metadata { name } } } } }`), "formatting is ignored")
+	assert.True(t, allowlist.Allows("query Nodes {\n  v1 {\n    Nodes { items { metadata { name } } }\n  }\n}"))
+	assert.False(t, allowlist.Allows(`query Pods { v1 { Pods { items { metadata { name namespace } } } } }`))
+	assert.False(t, allowlist.Allows(`{ v1 { Pods { items { metadata { name } } } } }`), "operation name is part of the document")
+	assert.False(t, allowlist.Allows(`not a query`))
+}
+
+func TestLoadAllowlistErrors(t *testing.T) {
+	_, err := LoadAllowlist(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "failed to read operation allowlist")
+
+	_, err = LoadAllowlist(t.TempDir())
+	assert.ErrorContains(t, err, "no .graphql or .gql documents found")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.graphql"), []byte(`query {`), 0o600))
+	_, err = LoadAllowlist(dir)
+	assert.ErrorContains(t, err, "failed to parse operation allowlist")
+}
+
+func TestValidateAllowlist(t *testing.T) {
+	allowlist, err := NewAllowlist(`query Pods { pods { name } }`)
+	require.NoError(t, err)
+	cfg := Config{Allowlist: allowlist, MaxDepth: 1}
+
+	assert.ErrorIs(t, Validate(`query Other { pods { name } }`, cfg), ErrOperationNotAllowed)
+	assert.ErrorIs(t, Validate(`{ __schema { types { name } } }`, cfg), ErrOperationNotAllowed)
+	// Registered documents are still subject to the limits.
+	assert.ErrorContains(t, Validate("query Pods {\n  pods { name }\n}", cfg), "exceeds maximum allowed depth")
+
+	cfg.MaxDepth = 0
+	assert.NoError(t, Validate("query Pods {\n  pods { name }\n}", cfg))
+}
+
+func mustAllowlist(documents ...string) *Allowlist {
+	allowlist, err := NewAllowlist(documents...)
+	if err != nil {
+		panic(err)
+	}
+	return allowlist
+}
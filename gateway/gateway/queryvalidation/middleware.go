@@ -9,27 +9,29 @@ import (
 )
 
 // Middleware returns an http.Handler that validates incoming GraphQL queries
-// against depth and complexity limits before forwarding to the next handler.
-// Supports both single requests and batched query arrays.
-// If all limits are zero, the middleware is a no-op passthrough.
+// against the allowlist, depth and complexity limits and introspection before forwarding to
+// the next handler. Supports both single requests and batched query arrays,
+// and queries in the URL of GET requests.
+// If all limits are zero, introspection is allowed and there is no
+// allowlist, the middleware is a no-op passthrough.
 //
 // Expects the request parser middleware to have stored parsed requests in context.
 func Middleware(next http.Handler, cfg Config) http.Handler {
-	if cfg.MaxDepth <= 0 && cfg.MaxComplexity <= 0 && cfg.MaxBatchSize <= 0 {
+	if cfg.MaxDepth <= 0 && cfg.MaxComplexity <= 0 && cfg.MaxBatchSize <= 0 && !cfg.DisallowIntrospection && cfg.Allowlist == nil {
 		return next
 	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		reqs, ok := utilscontext.GetParsedRequestsFromCtx(r.Context())
-		if !ok {
-			next.ServeHTTP(w, r)
-			return
-		}
-
 		var queries []string
-		for _, req := range reqs {
-			if req.Query != "" {
-				queries = append(queries, req.Query)
+		if reqs, ok := utilscontext.GetParsedRequestsFromCtx(r.Context()); ok {
+			for _, req := range reqs {
+				if req.Query != "" {
+					queries = append(queries, req.Query)
+				}
+			}
+		} else if r.Method == http.MethodGet {
+			if query := r.URL.Query().Get("query"); query != "" {
+				queries = append(queries, query)
 			}
 		}
 
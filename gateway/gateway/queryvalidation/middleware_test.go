@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -24,6 +25,7 @@ func TestMiddleware(t *testing.T) {
 		cfg        Config
 		body       string
 		method     string
+		target     string
 		wantStatus int
 		wantError  string
 		wantPass   bool // whether request reaches the inner handler
@@ -52,6 +54,22 @@ func TestMiddleware(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantError:  "query complexity 4 exceeds maximum allowed complexity of 3",
 		},
+		{
+			name:       "allowlisted query passes through",
+			cfg:        Config{Allowlist: mustAllowlist(`query Pods { pods { name } }`)},
+			body:       `{"query":"query Pods {\n  pods { name }\n}"}`,
+			method:     http.MethodPost,
+			wantStatus: http.StatusOK,
+			wantPass:   true,
+		},
+		{
+			name:       "query not in allowlist rejected",
+			cfg:        Config{Allowlist: mustAllowlist(`query Pods { pods { name } }`)},
+			body:       `{"query":"{ secrets { data } }"}`,
+			method:     http.MethodPost,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "operation is not in the allowlist",
+		},
 		{
 			name:       "disabled config passes everything",
 			cfg:        Config{MaxDepth: 0, MaxComplexity: 0},
@@ -68,6 +86,30 @@ func TestMiddleware(t *testing.T) {
 			wantStatus: http.StatusOK,
 			wantPass:   true,
 		},
+		{
+			name:       "GET query rejected when exceeding depth",
+			cfg:        Config{MaxDepth: 1},
+			method:     http.MethodGet,
+			target:     "/graphql?query=" + url.QueryEscape("{ a { b } }"),
+			wantStatus: http.StatusBadRequest,
+			wantError:  "query depth 2 exceeds maximum allowed depth of 1",
+		},
+		{
+			name:       "GET introspection rejected",
+			cfg:        Config{DisallowIntrospection: true},
+			method:     http.MethodGet,
+			target:     "/graphql?query=" + url.QueryEscape("{ __schema { queryType { name } } }"),
+			wantStatus: http.StatusBadRequest,
+			wantError:  "introspection is not allowed",
+		},
+		{
+			name:       "introspection rejected",
+			cfg:        Config{DisallowIntrospection: true},
+			body:       `{"query":"{ __type(name: \"Pod\") { name } }"}`,
+			method:     http.MethodPost,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "introspection is not allowed",
+		},
 		{
 			name:       "empty query field passes through",
 			cfg:        Config{MaxDepth: 1},
@@ -161,7 +203,11 @@ func TestMiddleware(t *testing.T) {
 			if tt.body != "" {
 				body = strings.NewReader(tt.body)
 			}
-			req := httptest.NewRequest(tt.method, "/graphql", body)
+			target := "/graphql"
+			if tt.target != "" {
+				target = tt.target
+			}
+			req := httptest.NewRequest(tt.method, target, body)
 			if tt.method == http.MethodPost {
 				req.Header.Set("Content-Type", "application/json")
 			}
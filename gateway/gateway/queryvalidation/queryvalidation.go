@@ -1,6 +1,7 @@
 package queryvalidation
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/graphql-go/graphql/language/ast"
@@ -22,12 +23,27 @@ type Config struct {
 	// MaxBatchSize is the maximum number of queries allowed in a single batched request.
 	// 0 disables the limit.
 	MaxBatchSize int
+
+	// DisallowIntrospection rejects queries selecting __schema or __type.
+	DisallowIntrospection bool
+
+	// Allowlist rejects queries that are not registered in it.
+	// nil allows all queries.
+	Allowlist *Allowlist
 }
 
-// Validate parses a GraphQL query string and checks depth/complexity limits.
-// Returns a non-nil error if limits are exceeded.
+// ErrIntrospectionDisallowed is returned for introspection queries when
+// DisallowIntrospection is set.
+var ErrIntrospectionDisallowed = errors.New("introspection is not allowed")
+
+// Validate parses a GraphQL query string and checks the allowlist,
+// depth/complexity limits and introspection. Returns a non-nil error if the
+// query is not allowed.
 func Validate(query string, cfg Config) error {
-	if cfg.MaxDepth <= 0 && cfg.MaxComplexity <= 0 {
+	if cfg.Allowlist != nil && !cfg.Allowlist.Allows(query) {
+		return ErrOperationNotAllowed
+	}
+	if cfg.MaxDepth <= 0 && cfg.MaxComplexity <= 0 && !cfg.DisallowIntrospection {
 		return nil
 	}
 
@@ -44,6 +60,17 @@ func Validate(query string, cfg Config) error {
 		}
 	}
 
+	if cfg.DisallowIntrospection {
+		for _, def := range doc.Definitions {
+			if op, ok := def.(*ast.OperationDefinition); ok && op.SelectionSet != nil {
+				w.visiting = make(map[string]bool)
+				if w.selectsIntrospection(op.SelectionSet) {
+					return ErrIntrospectionDisallowed
+				}
+			}
+		}
+	}
+
 	var maxDepth, totalComplexity int
 	for _, def := range doc.Definitions {
 		if op, ok := def.(*ast.OperationDefinition); ok && op.SelectionSet != nil {
@@ -137,3 +164,40 @@ func (w *walker) walk(selSet *ast.SelectionSet, depth int) (int, int) {
 
 	return maxDepth, complexity
 }
+
+// introspectionFields are the meta fields exposing the schema. __typename
+// only names the type of an object and stays allowed.
+var introspectionFields = map[string]bool{"__schema": true, "__type": true}
+
+// selectsIntrospection reports whether a selection set selects an
+// introspection field, directly or through fragments.
+func (w *walker) selectsIntrospection(selSet *ast.SelectionSet) bool {
+	for _, sel := range selSet.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			if introspectionFields[s.Name.Value] {
+				return true
+			}
+			if s.SelectionSet != nil && w.selectsIntrospection(s.SelectionSet) {
+				return true
+			}
+		case *ast.InlineFragment:
+			if s.SelectionSet != nil && w.selectsIntrospection(s.SelectionSet) {
+				return true
+			}
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			frag, ok := w.fragments[name]
+			if !ok || w.visiting[name] || frag.SelectionSet == nil {
+				continue
+			}
+			w.visiting[name] = true
+			found := w.selectsIntrospection(frag.SelectionSet)
+			delete(w.visiting, name)
+			if found {
+				return true
+			}
+		}
+	}
+	return false
+}
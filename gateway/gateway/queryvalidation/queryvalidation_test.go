@@ -115,6 +115,28 @@ func TestValidate(t *testing.T) {
 			cfg:     Config{MaxComplexity: 5},
 			wantErr: "exceeds maximum allowed complexity of 5",
 		},
+		{
+			name:    "schema introspection rejected",
+			query:   `{ __schema { types { name } } }`,
+			cfg:     Config{DisallowIntrospection: true},
+			wantErr: "introspection is not allowed",
+		},
+		{
+			name:    "type introspection in fragment rejected",
+			query:   `query { ...F } fragment F on Query { __type(name: "Pod") { name } }`,
+			cfg:     Config{DisallowIntrospection: true},
+			wantErr: "introspection is not allowed",
+		},
+		{
+			name:  "typename allowed without introspection",
+			query: `{ pods { __typename name } }`,
+			cfg:   Config{DisallowIntrospection: true},
+		},
+		{
+			name:  "introspection allowed by default",
+			query: `{ __schema { queryType { name } } }`,
+			cfg:   Config{MaxDepth: 5},
+		},
 	}
 
 	for _, tt := range tests {
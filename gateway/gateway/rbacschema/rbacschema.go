@@ -0,0 +1,191 @@
+// Package rbacschema serves each user a schema without the kinds they may not
+// list, as determined by a SelfSubjectRulesReview. Pruning only changes what
+// users see: the API server still authorizes every request.
+package rbacschema
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	"golang.org/x/sync/singleflight"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/utils/lru"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// maxUsers bounds the number of tokens whose rules are cached.
+	maxUsers = 10000
+	// maxSchemas bounds the number of pruned schemas kept. Users with the
+	// same permissions share a schema.
+	maxSchemas = 64
+)
+
+// BuildFunc builds what is served for a set of definitions.
+type BuildFunc[T any] func(definitions map[string]*spec.Schema) (T, error)
+
+// Pruner returns what is built from the definitions of the kinds a user may
+// list.
+type Pruner[T any] struct {
+	client      client.Client
+	namespace   string
+	definitions map[string]*spec.Schema
+	full        T
+	build       BuildFunc[T]
+
+	// hidden caches the definition keys hidden from a token, by token hash.
+	// nil disables caching.
+	hidden    *ttlcache.Cache[string, []string]
+	reviewing singleflight.Group
+
+	// pruned holds the built schemas by the keys they hide.
+	pruned   *lru.Cache
+	building singleflight.Group
+}
+
+// New returns a pruner reviewing the rules of users in namespace with c,
+// which must send requests with the token of the request. full is served to
+// users that may list every kind. Rules are cached for ttl, or reviewed on
+// every request if it is not positive.
+func New[T any](c client.Client, namespace string, definitions map[string]*spec.Schema, full T, build BuildFunc[T], ttl time.Duration) *Pruner[T] {
+	p := &Pruner[T]{
+		client:      c,
+		namespace:   namespace,
+		definitions: definitions,
+		full:        full,
+		build:       build,
+		pruned:      lru.New(maxSchemas),
+	}
+	if ttl > 0 {
+		p.hidden = ttlcache.New(
+			ttlcache.WithTTL[string, []string](ttl),
+			ttlcache.WithCapacity[string, []string](maxUsers),
+		)
+	}
+	return p
+}
+
+// For returns what is served to the user of ctx. If the rules cannot be
+// reviewed or are incomplete, or the pruned schema fails to build, the full
+// schema is served. Incomplete rules are cached like complete ones.
+func (p *Pruner[T]) For(ctx context.Context, token string) T {
+	logger := log.FromContext(ctx)
+
+	sum := sha256.Sum256([]byte(token))
+	tokenKey := hex.EncodeToString(sum[:])
+
+	var hidden []string
+	if item := p.cached(tokenKey); item != nil {
+		hidden = item.Value()
+	} else {
+		result, err, _ := p.reviewing.Do(tokenKey, func() (any, error) {
+			return p.hiddenDefinitions(ctx)
+		})
+		if err != nil {
+			logger.Error(err, "Failed to review rules, serving the full schema")
+			return p.full
+		}
+		hidden = result.([]string)
+		if p.hidden != nil {
+			p.hidden.Set(tokenKey, hidden, ttlcache.DefaultTTL)
+		}
+	}
+
+	if len(hidden) == 0 {
+		return p.full
+	}
+
+	schemaKey := strings.Join(hidden, ",")
+	if pruned, ok := p.pruned.Get(schemaKey); ok {
+		return pruned.(T)
+	}
+
+	pruned, err, _ := p.building.Do(schemaKey, func() (any, error) {
+		definitions := maps.Clone(p.definitions)
+		for _, key := range hidden {
+			delete(definitions, key)
+		}
+		pruned, err := p.build(definitions)
+		if err != nil {
+			return nil, err
+		}
+		p.pruned.Add(schemaKey, pruned)
+		return pruned, nil
+	})
+	if err != nil {
+		logger.Error(err, "Failed to build pruned schema, serving the full schema", "hidden", len(hidden))
+		return p.full
+	}
+	return pruned.(T)
+}
+
+func (p *Pruner[T]) cached(tokenKey string) *ttlcache.Item[string, []string] {
+	if p.hidden == nil {
+		return nil
+	}
+	return p.hidden.Get(tokenKey)
+}
+
+// hiddenDefinitions returns the sorted keys of the definitions of kinds the
+// user of ctx may not list. Definitions that do not map to a resource, such
+// as meta types, are never hidden.
+func (p *Pruner[T]) hiddenDefinitions(ctx context.Context) ([]string, error) {
+	review := &authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: p.namespace},
+	}
+	if err := p.client.Create(ctx, review); err != nil {
+		return nil, fmt.Errorf("failed to review rules: %w", err)
+	}
+	if review.Status.Incomplete {
+		// Kinds allowed by an authorizer that cannot list its rules would be
+		// hidden, so nothing is.
+		log.FromContext(ctx).V(4).Info("Rules review is incomplete, serving the full schema", "error", review.Status.EvaluationError)
+		return nil, nil
+	}
+
+	var hidden []string
+	for key, definition := range p.definitions {
+		gvk, err := extensions.GVK(definition)
+		if err != nil || gvk == nil {
+			continue
+		}
+		mapping, err := p.client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			continue
+		}
+		if !canList(review.Status.ResourceRules, mapping.Resource.Group, mapping.Resource.Resource) {
+			hidden = append(hidden, key)
+		}
+	}
+	slices.Sort(hidden)
+	return hidden, nil
+}
+
+// canList reports whether the rules allow listing a resource. Rules limited
+// to resource names do not.
+func canList(rules []authorizationv1.ResourceRule, group, resource string) bool {
+	for _, rule := range rules {
+		if len(rule.ResourceNames) > 0 {
+			continue
+		}
+		if matches(rule.Verbs, "list") && matches(rule.APIGroups, group) && matches(rule.Resources, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(values []string, value string) bool {
+	return slices.Contains(values, value) || slices.Contains(values, "*")
+}
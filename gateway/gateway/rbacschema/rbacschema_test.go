@@ -0,0 +1,146 @@
+package rbacschema
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	"github.com/stretchr/testify/assert"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func definition(gvk schema.GroupVersionKind) *spec.Schema {
+	s := &spec.Schema{}
+	s.AddExtension(extensions.GVKKey, []any{map[string]any{"group": gvk.Group, "version": gvk.Version, "kind": gvk.Kind}})
+	return s
+}
+
+func TestPrunerFor(t *testing.T) {
+	pod := schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	secret := schema.GroupVersionKind{Version: "v1", Kind: "Secret"}
+	deployment := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, gvk := range []schema.GroupVersionKind{pod, secret, deployment} {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+
+	definitions := map[string]*spec.Schema{
+		"io.k8s.api.core.v1.Pod":             definition(pod),
+		"io.k8s.api.core.v1.Secret":          definition(secret),
+		"io.k8s.api.apps.v1.Deployment":      definition(deployment),
+		"io.k8s.api.core.v1.PodSpec":         {},
+		"io.k8s.apimachinery.pkg.apis.Other": definition(schema.GroupVersionKind{Version: "v1", Kind: "Unmapped"}),
+	}
+
+	tests := []struct {
+		name       string
+		rules      []authorizationv1.ResourceRule
+		incomplete bool
+		reviewErr  error
+		want       []string
+	}{
+		{
+			name:  "all kinds allowed",
+			rules: []authorizationv1.ResourceRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}},
+			want:  nil,
+		},
+		{
+			name: "kinds without list permission are hidden",
+			rules: []authorizationv1.ResourceRule{
+				{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+				{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}},
+				{Verbs: []string{"list"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}, ResourceNames: []string{"web"}},
+			},
+			want: []string{"io.k8s.api.apps.v1.Deployment", "io.k8s.api.core.v1.Secret"},
+		},
+		{
+			name:       "incomplete rules serve the full schema",
+			incomplete: true,
+			want:       nil,
+		},
+		{
+			name:      "failed review serves the full schema",
+			reviewErr: errors.New("unavailable"),
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reviews atomic.Int32
+			cl := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithRESTMapper(mapper).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						review := obj.(*authorizationv1.SelfSubjectRulesReview)
+						reviews.Add(1)
+						assert.Equal(t, "team-a", review.Spec.Namespace)
+						review.Status.ResourceRules = tt.rules
+						review.Status.Incomplete = tt.incomplete
+						return tt.reviewErr
+					},
+				}).
+				Build()
+
+			var builds atomic.Int32
+			// The built value lists the hidden definitions, nil for the full schema.
+			build := func(defs map[string]*spec.Schema) ([]string, error) {
+				builds.Add(1)
+				var hidden []string
+				for key := range definitions {
+					if _, ok := defs[key]; !ok {
+						hidden = append(hidden, key)
+					}
+				}
+				slices.Sort(hidden)
+				return hidden, nil
+			}
+
+			pruner := New(cl, "team-a", definitions, nil, build, time.Minute)
+			assert.Equal(t, tt.want, pruner.For(t.Context(), "token"))
+			assert.Equal(t, tt.want, pruner.For(t.Context(), "token"))
+			assert.Equal(t, tt.want, pruner.For(t.Context(), "other"))
+
+			if tt.reviewErr == nil {
+				assert.Equal(t, int32(2), reviews.Load(), "rules are cached per token")
+			}
+			if tt.want != nil {
+				assert.Equal(t, int32(1), builds.Load(), "users with the same permissions share a schema")
+			}
+			assert.Len(t, slices.Collect(maps.Keys(definitions)), 5, "definitions are not changed")
+		})
+	}
+}
+
+func TestPrunerForWithoutCache(t *testing.T) {
+	var reviews atomic.Int32
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				reviews.Add(1)
+				return nil
+			},
+		}).
+		Build()
+
+	pruner := New(cl, "default", map[string]*spec.Schema{}, "full", func(map[string]*spec.Schema) (string, error) { return "pruned", nil }, 0)
+	assert.Equal(t, "full", pruner.For(t.Context(), "token"))
+	assert.Equal(t, "full", pruner.For(t.Context(), "token"))
+	assert.Equal(t, int32(2), reviews.Load())
+}
@@ -0,0 +1,157 @@
+// Package registration generates the schemas of clusters registered at
+// runtime with the registerCluster mutation, which are served without a
+// schema from the listener.
+package registration
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/apischema/enricher"
+
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Metadata returns the metadata of a cluster reached at host with a bearer
+// token or a kubeconfig. host defaults to the server of the kubeconfig, and
+// ca is the PEM encoded certificate authority of a token's API server.
+// Kubeconfigs may only embed their credentials, see validateKubeconfig.
+func Metadata(host, token, kubeconfig, ca string) (v1alpha1.ClusterMetadata, error) {
+	metadata := v1alpha1.ClusterMetadata{Host: host}
+
+	switch {
+	case token != "" && kubeconfig != "":
+		return metadata, errors.New("either a token or a kubeconfig is required, not both")
+	case token != "":
+		if host == "" {
+			return metadata, errors.New("host is required with a token")
+		}
+		metadata.Auth = &v1alpha1.AuthMetadata{
+			Type:  v1alpha1.AuthTypeToken,
+			Token: base64.StdEncoding.EncodeToString([]byte(token)),
+		}
+	case kubeconfig != "":
+		if err := validateKubeconfig([]byte(kubeconfig)); err != nil {
+			return metadata, err
+		}
+		cfg, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfig))
+		if err != nil {
+			return metadata, fmt.Errorf("failed to parse kubeconfig: %w", err)
+		}
+		if metadata.Host == "" {
+			metadata.Host = cfg.Host
+		}
+		metadata.Auth = &v1alpha1.AuthMetadata{
+			Type:       v1alpha1.AuthTypeKubeconfig,
+			Kubeconfig: base64.StdEncoding.EncodeToString([]byte(kubeconfig)),
+		}
+	default:
+		return metadata, errors.New("a token or a kubeconfig is required")
+	}
+
+	if ca != "" {
+		metadata.CA = &v1alpha1.CAMetadata{Data: base64.StdEncoding.EncodeToString([]byte(ca))}
+	}
+	return metadata, nil
+}
+
+// validateKubeconfig fails if a kubeconfig makes the gateway run commands or
+// read files, which callers registering clusters must not be able to do: a
+// credential plugin would run in the gateway, and the files, e.g. the
+// gateway's service account token, would be sent to the caller's server.
+func validateKubeconfig(data []byte) error {
+	cfg, err := clientcmd.Load(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse kubeconfig: %w", err)
+	}
+
+	for name, authInfo := range cfg.AuthInfos {
+		var field string
+		switch {
+		case authInfo.Exec != nil:
+			field = "exec"
+		case authInfo.AuthProvider != nil:
+			field = "auth-provider"
+		case authInfo.TokenFile != "":
+			field = "tokenFile"
+		case authInfo.ClientCertificate != "":
+			field = "client-certificate"
+		case authInfo.ClientKey != "":
+			field = "client-key"
+		default:
+			continue
+		}
+		return fmt.Errorf("kubeconfig user %q must not use %s; embed the credentials with token or client-certificate-data and client-key-data", name, field)
+	}
+
+	for name, cluster := range cfg.Clusters {
+		if cluster.CertificateAuthority != "" {
+			return fmt.Errorf("kubeconfig cluster %q must not use certificate-authority; embed it with certificate-authority-data", name)
+		}
+	}
+	return nil
+}
+
+// GenerateSchema generates the schema of a cluster from its API server, like
+// the listener does for a ClusterAccess, and adds its metadata.
+func GenerateSchema(ctx context.Context, metadata v1alpha1.ClusterMetadata) ([]byte, error) {
+	logger := log.FromContext(ctx)
+
+	cfg, err := v1alpha1.BuildRestConfigFromMetadata(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config from metadata: %w", err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	httpClient, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+	mapper, err := apiutil.NewDynamicRESTMapper(cfg, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST mapper: %w", err)
+	}
+
+	apiextensions, err := apiextensionsclientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create apiextensions clientset: %w", err)
+	}
+	crds := apiextensions.ApiextensionsV1().CustomResourceDefinitions()
+
+	apiResources, err := discoveryClient.ServerPreferredResources()
+	if err != nil {
+		// Some resources may still be available
+		logger.V(2).Info("partial error getting server preferred resources", "error", err)
+		if apiResources == nil {
+			return nil, fmt.Errorf("failed to get server preferred resources: %w", err)
+		}
+	}
+
+	resolver := apischema.NewResolver(
+		enricher.NewDiscoveryStubs(apiResources),
+		enricher.NewScope(mapper),
+		enricher.NewCategories(apiResources),
+		enricher.NewPrinterColumns(crds),
+		enricher.NewMaturity(crds),
+	)
+	schemaJSON, err := resolver.Resolve(ctx, discoveryClient.OpenAPIV3())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve API schema: %w", err)
+	}
+
+	return extensions.WithClusterMetadata(schemaJSON, &metadata)
+}
@@ -0,0 +1,106 @@
+package registration
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com:6443
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: dev
+current-context: dev
+users:
+- name: dev
+  user:
+    token: secret
+`
+
+func TestMetadata(t *testing.T) {
+	encode := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name                        string
+		host, token, kubeconfig, ca string
+		want                        v1alpha1.ClusterMetadata
+		wantErr                     string
+	}{
+		{
+			name:  "token",
+			host:  "https://dev.example.com",
+			token: "secret",
+			ca:    "PEM",
+			want: v1alpha1.ClusterMetadata{
+				Host: "https://dev.example.com",
+				Auth: &v1alpha1.AuthMetadata{Type: v1alpha1.AuthTypeToken, Token: encode("secret")},
+				CA:   &v1alpha1.CAMetadata{Data: encode("PEM")},
+			},
+		},
+		{
+			name:       "host from kubeconfig",
+			kubeconfig: kubeconfig,
+			want: v1alpha1.ClusterMetadata{
+				Host: "https://dev.example.com:6443",
+				Auth: &v1alpha1.AuthMetadata{Type: v1alpha1.AuthTypeKubeconfig, Kubeconfig: encode(kubeconfig)},
+			},
+		},
+		{name: "token without host", token: "secret", wantErr: "host is required"},
+		{name: "token and kubeconfig", host: "https://dev.example.com", token: "secret", kubeconfig: kubeconfig, wantErr: "not both"},
+		{name: "no credentials", host: "https://dev.example.com", wantErr: "a token or a kubeconfig is required"},
+		{name: "invalid kubeconfig", kubeconfig: "{", wantErr: "failed to parse kubeconfig"},
+		{
+			name:       "exec credential plugin",
+			kubeconfig: strings.Replace(kubeconfig, "token: secret", "exec:\n      apiVersion: client.authentication.k8s.io/v1\n      command: sh", 1),
+			wantErr:    `kubeconfig user "dev" must not use exec`,
+		},
+		{
+			name:       "auth provider",
+			kubeconfig: strings.Replace(kubeconfig, "token: secret", "auth-provider:\n      name: oidc", 1),
+			wantErr:    "must not use auth-provider",
+		},
+		{
+			name:       "token file",
+			kubeconfig: strings.Replace(kubeconfig, "token: secret", "tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token", 1),
+			wantErr:    "must not use tokenFile",
+		},
+		{
+			name:       "client certificate file",
+			kubeconfig: strings.Replace(kubeconfig, "token: secret", "client-certificate: /etc/tls/tls.crt\n    client-key-data: a2V5", 1),
+			wantErr:    "must not use client-certificate",
+		},
+		{
+			name:       "client key file",
+			kubeconfig: strings.Replace(kubeconfig, "token: secret", "client-certificate-data: Y2VydA==\n    client-key: /etc/tls/tls.key", 1),
+			wantErr:    "must not use client-key",
+		},
+		{
+			name:       "certificate authority file",
+			kubeconfig: strings.Replace(kubeconfig, "server: https://dev.example.com:6443", "server: https://dev.example.com:6443\n    certificate-authority: /var/run/secrets/kubernetes.io/serviceaccount/ca.crt", 1),
+			wantErr:    `kubeconfig cluster "dev" must not use certificate-authority`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Metadata(tt.host, tt.token, tt.kubeconfig, tt.ca)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
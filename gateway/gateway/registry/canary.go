@@ -0,0 +1,142 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/endpoint"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// CanaryHeader picks the schema of a request while a canary runs: "true"
+// routes it to the canary, "false" to the current schema. Responses served
+// by a canary carry the header with the value "true".
+const CanaryHeader = "X-Schema-Canary"
+
+// minCanaryResults is the number of results a schema must have served for
+// its share of failed results to be meaningful. Canaries with less traffic
+// are promoted when their duration is over.
+const minCanaryResults = 20
+
+// canary is a new schema served next to the current one.
+type canary struct {
+	endpoint *endpoint.Endpoint
+	// smokeTestsPassed is set once the canary passed the smoke tests.
+	smokeTestsPassed bool
+	// currentServed and currentFailed are the results of the current
+	// endpoint when the canary started.
+	currentServed, currentFailed uint64
+}
+
+// startCanary serves ep as canary next to the current endpoint and promotes
+// or rolls it back after the configured duration. A running canary is
+// replaced. The caller must hold the lock.
+func (r *Registry) startCanary(ctx context.Context, clusterName string, current, ep *endpoint.Endpoint) {
+	logger := log.FromContext(ctx).WithValues("cluster", clusterName)
+
+	if previous, exists := r.canaries[clusterName]; exists {
+		previous.endpoint.Close()
+		logger.Info("Replaced canary schema with a newer one")
+	}
+
+	served, failed := current.Results()
+	c := &canary{endpoint: ep, currentServed: served, currentFailed: failed}
+	r.canaries[clusterName] = c
+	logger.Info("Serving new schema as canary", "duration", r.config.Canary.Duration, "percentage", r.config.Canary.Percentage)
+
+	go r.concludeCanary(ctx, clusterName, c)
+}
+
+// concludeCanary waits for the canary duration and then promotes the canary
+// if its share of failed results stayed close to that of the current
+// endpoint, or rolls it back otherwise.
+func (r *Registry) concludeCanary(ctx context.Context, clusterName string, c *canary) {
+	logger := log.FromContext(ctx).WithValues("cluster", clusterName)
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(r.config.Canary.Duration):
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The canary was replaced, rolled back or its cluster removed.
+	if r.canaries[clusterName] != c {
+		return
+	}
+	delete(r.canaries, clusterName)
+
+	current := r.endpoints[clusterName]
+	currentServed, currentFailed := current.Results()
+	canaryServed, canaryFailed := c.endpoint.Results()
+	if err := checkCanary(currentServed-c.currentServed, currentFailed-c.currentFailed, canaryServed, canaryFailed, r.config.Canary.MaxErrorIncrease); err != nil {
+		c.endpoint.Close()
+		logger.Error(err, "Rolled back canary schema")
+		return
+	}
+
+	current.Close()
+	r.endpoints[clusterName] = c.endpoint
+	r.loaded[clusterName] = time.Now()
+	if categories, exists := r.categories[clusterName]; exists {
+		categories.Publish(c.endpoint.Categories())
+	}
+	if c.smokeTestsPassed {
+		delete(r.degraded, clusterName)
+	}
+	logger.Info("Promoted canary schema", "served", canaryServed, "failed", canaryFailed)
+}
+
+// checkCanary returns an error if the share of failed results of the canary
+// exceeds that of the current endpoint by more than maxIncrease. The current
+// endpoint counts as error free if it served too few results to compare.
+func checkCanary(currentServed, currentFailed, canaryServed, canaryFailed uint64, maxIncrease float64) error {
+	if canaryServed < minCanaryResults {
+		return nil
+	}
+
+	var currentRatio float64
+	if currentServed >= minCanaryResults {
+		currentRatio = float64(currentFailed) / float64(currentServed)
+	}
+	canaryRatio := float64(canaryFailed) / float64(canaryServed)
+
+	if canaryRatio > currentRatio+maxIncrease {
+		return fmt.Errorf("%.1f%% of the %d results of the canary had errors, %.1f%% of those of the current schema", canaryRatio*100, canaryServed, currentRatio*100)
+	}
+	return nil
+}
+
+// Route returns the endpoint serving a request for a cluster and whether it
+// is a canary. While a canary runs, it serves the configured percentage of
+// requests and those asking for it with CanaryHeader.
+func (r *Registry) Route(name string, req *http.Request) (ep *endpoint.Endpoint, isCanary, exists bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	current, exists := r.endpoints[name]
+	if !exists {
+		return nil, false, false
+	}
+	c, running := r.canaries[name]
+	if !running {
+		return current, false, true
+	}
+
+	switch req.Header.Get(CanaryHeader) {
+	case "true":
+		return c.endpoint, true, true
+	case "false":
+		return current, false, true
+	}
+	if rand.Float64()*100 < r.config.Canary.Percentage {
+		return c.endpoint, true, true
+	}
+	return current, false, true
+}
@@ -0,0 +1,105 @@
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/config"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/endpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCanary(t *testing.T) {
+	tests := []struct {
+		name                         string
+		currentServed, currentFailed uint64
+		canaryServed, canaryFailed   uint64
+		wantErr                      bool
+	}{
+		{name: "flat error rate", currentServed: 100, currentFailed: 10, canaryServed: 50, canaryFailed: 6},
+		{name: "increased error rate", currentServed: 100, currentFailed: 10, canaryServed: 50, canaryFailed: 15, wantErr: true},
+		{name: "too few canary results", currentServed: 100, canaryServed: 10, canaryFailed: 10},
+		{name: "too few current results compare to zero", currentServed: 5, currentFailed: 5, canaryServed: 40, canaryFailed: 10, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCanary(tt.currentServed, tt.currentFailed, tt.canaryServed, tt.canaryFailed, 0.05)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRoute(t *testing.T) {
+	current, next := &endpoint.Endpoint{}, &endpoint.Endpoint{}
+
+	tests := []struct {
+		name       string
+		percentage float64
+		header     string
+		canary     bool
+		wantCanary bool
+	}{
+		{name: "no canary", percentage: 100},
+		{name: "percentage routes to canary", percentage: 100, canary: true, wantCanary: true},
+		{name: "percentage routes to current", percentage: 0, canary: true},
+		{name: "header picks canary", percentage: 0, header: "true", canary: true, wantCanary: true},
+		{name: "header picks current", percentage: 100, header: "false", canary: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(config.Gateway{Canary: config.Canary{Duration: time.Hour, Percentage: tt.percentage}})
+			r.endpoints["c1"] = current
+			if tt.canary {
+				r.canaries["c1"] = &canary{endpoint: next}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(CanaryHeader, tt.header)
+			}
+
+			ep, isCanary, exists := r.Route("c1", req)
+			require.True(t, exists)
+			assert.Equal(t, tt.wantCanary, isCanary)
+			if tt.wantCanary {
+				assert.Same(t, next, ep)
+			} else {
+				assert.Same(t, current, ep)
+			}
+		})
+	}
+
+	_, _, exists := New(config.Gateway{}).Route("missing", httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.False(t, exists)
+}
+
+func TestCanaryPromotion(t *testing.T) {
+	r := New(config.Gateway{Canary: config.Canary{Duration: time.Millisecond, Percentage: 10}})
+	current, next := &endpoint.Endpoint{}, &endpoint.Endpoint{}
+	r.endpoints["c1"] = current
+	r.degraded["c1"] = assert.AnError
+
+	r.mu.Lock()
+	r.startCanary(t.Context(), "c1", current, next)
+	r.canaries["c1"].smokeTestsPassed = true
+	r.mu.Unlock()
+
+	require.Eventually(t, func() bool {
+		ep, _ := r.GetEndpoint("c1")
+		return ep == next
+	}, time.Second, time.Millisecond)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	assert.Empty(t, r.canaries)
+	assert.NoError(t, r.degraded["c1"])
+}
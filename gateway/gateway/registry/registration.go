@@ -0,0 +1,209 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/endpoint"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/maturity"
+	registrationpkg "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/registration"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// registration is a cluster registered at runtime, removed when its timer
+// fires.
+type registration struct {
+	timer     *time.Timer
+	expiresAt time.Time
+	// owner is the user who registered the cluster, the only one who may
+	// renew or unregister it.
+	owner string
+}
+
+// homeCluster reviews the groups of users registering clusters with the
+// client of the home cluster's endpoint.
+type homeCluster struct {
+	endpoint *endpoint.Endpoint
+	groups   *maturity.Groups
+}
+
+// clusterRegistration returns the configuration of the cluster registration
+// mutations if cluster registration is enabled. They are only served on the
+// endpoints of clusters from the listener's schemas, as the users of
+// registered clusters are reviewed by the registrant's API server.
+func (r *Registry) clusterRegistration() *resolver.ClusterRegistration {
+	if r.config.GraphQL.ClusterRegistrationTTL <= 0 {
+		return nil
+	}
+	return &resolver.ClusterRegistration{
+		Registrar: r,
+		Member:    r.reviewRegistrant,
+		TTL:       r.config.GraphQL.ClusterRegistrationTTL,
+	}
+}
+
+// reviewRegistrant returns the name of the user authenticated with token and
+// whether they may register clusters, as reviewed by the API server of the
+// home cluster. The user must be known to it regardless of the endpoint the
+// request was sent to.
+func (r *Registry) reviewRegistrant(ctx context.Context, token string) (string, bool, error) {
+	home := r.config.GraphQL.ClusterRegistrationHomeCluster
+
+	r.mu.Lock()
+	ep, served := r.endpoints[home]
+	_, registered := r.registered[home]
+	if !served || registered {
+		r.mu.Unlock()
+		return "", false, fmt.Errorf("home cluster %s of cluster registration is not served", home)
+	}
+	if r.home.endpoint != ep {
+		r.home = homeCluster{
+			endpoint: ep,
+			groups:   maturity.NewGroups(ep.Client(), r.config.GraphQL.ClusterRegistrationGroups, r.config.TokenReviewCacheTTL),
+		}
+	}
+	groups := r.home.groups
+	r.mu.Unlock()
+
+	membership, err := groups.Review(ctx, token)
+	if err != nil {
+		return "", false, err
+	}
+	return membership.User, membership.Member, nil
+}
+
+// RegisterCluster implements resolver.ClusterRegistrar. Clusters served from
+// the listener's schemas and clusters registered by other users cannot be
+// registered. The name is reserved while the schema is generated, and
+// checked again before the cluster is served, so concurrent registrations
+// and schemas of the listener are not replaced.
+func (r *Registry) RegisterCluster(ctx context.Context, name, user string, credentials resolver.ClusterCredentials, ttl time.Duration) (*resolver.RegisteredCluster, error) {
+	if err := r.reserveRegistration(name, user); err != nil {
+		return nil, err
+	}
+	defer r.releaseRegistration(name)
+
+	metadata, err := registrationpkg.Metadata(credentials.Host, credentials.Token, credentials.Kubeconfig, credentials.CA)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := r.generateSchema(ctx, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate schema of cluster %s: %w", name, err)
+	}
+
+	// The endpoint outlives the request registering it.
+	ctx = context.WithoutCancel(ctx)
+	ep, categories, loadErr := r.newEndpoint(ctx, name, schema, nil)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkRegistrant(name, user); err != nil {
+		if ep != nil {
+			ep.Close()
+		}
+		return nil, err
+	}
+	previous, registered := r.registered[name]
+	if loadErr != nil {
+		if registered {
+			r.failed[name] = loadErr
+		}
+		return nil, fmt.Errorf("failed to load schema of cluster %s: %w", name, loadErr)
+	}
+	r.serveEndpoint(ctx, name, ep, categories)
+
+	if registered {
+		previous.timer.Stop()
+	}
+	reg := &registration{expiresAt: time.Now().Add(ttl), owner: user}
+	reg.timer = time.AfterFunc(ttl, func() { r.expire(ctx, name, reg) })
+	r.registered[name] = reg
+
+	return &resolver.RegisteredCluster{Name: name, ExpiresAt: reg.expiresAt}, nil
+}
+
+// reserveRegistration reserves the name of a cluster for user until
+// releaseRegistration is called.
+func (r *Registry) reserveRegistration(name, user string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkRegistrant(name, user); err != nil {
+		return err
+	}
+	if registrant, registering := r.registering[name]; registering {
+		if registrant != user {
+			return fmt.Errorf("cluster %s is being registered by another user", name)
+		}
+		return fmt.Errorf("cluster %s is already being registered", name)
+	}
+	r.registering[name] = user
+	return nil
+}
+
+func (r *Registry) releaseRegistration(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.registering, name)
+}
+
+// checkRegistrant returns an error if user may not register the cluster
+// name. The caller must hold r.mu.
+func (r *Registry) checkRegistrant(name, user string) error {
+	reg, registered := r.registered[name]
+	if _, served := r.endpoints[name]; served && !registered {
+		return fmt.Errorf("cluster %s is served from the listener's schemas", name)
+	}
+	if registered && reg.owner != user {
+		return fmt.Errorf("cluster %s was registered by another user", name)
+	}
+	return nil
+}
+
+// UnregisterCluster implements resolver.ClusterRegistrar. Clusters
+// registered by other users cannot be unregistered.
+func (r *Registry) UnregisterCluster(ctx context.Context, name, user string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reg, registered := r.registered[name]
+	if !registered {
+		return false, nil
+	}
+	if reg.owner != user {
+		return false, fmt.Errorf("cluster %s was registered by another user", name)
+	}
+
+	r.forgetRegistration(name)
+	r.deleteEndpoint(ctx, name)
+	return true, nil
+}
+
+// expire removes a registered cluster whose registration was not renewed.
+func (r *Registry) expire(ctx context.Context, name string, reg *registration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.registered[name] != reg {
+		return
+	}
+	delete(r.registered, name)
+
+	log.FromContext(ctx).Info("Registration expired, removing cluster", "cluster", name)
+	r.deleteEndpoint(ctx, name)
+}
+
+// forgetRegistration stops the expiry of a registered cluster. The caller
+// must hold r.mu.
+func (r *Registry) forgetRegistration(name string) {
+	if reg, exists := r.registered[name]; exists {
+		reg.timer.Stop()
+		delete(r.registered, name)
+	}
+}
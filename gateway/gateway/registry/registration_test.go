@@ -0,0 +1,238 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/authn"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/config"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{"components":{"schemas":{}},"x-cluster-metadata":{"host":"https://127.0.0.1:6443"}}`
+
+func newRegistrationRegistry(t *testing.T, generateErr error) *Registry {
+	r := New(config.Gateway{
+		GraphQL: config.GraphQL{
+			ClusterRegistrationTTL:         time.Hour,
+			ClusterRegistrationHomeCluster: "home",
+		},
+		Validator: authn.NoopValidator{},
+	})
+	r.generateSchema = func(_ context.Context, metadata v1alpha1.ClusterMetadata) ([]byte, error) {
+		assert.Equal(t, "https://dev.example.com", metadata.Host)
+		return []byte(testSchema), generateErr
+	}
+	return r
+}
+
+func TestRegisterCluster(t *testing.T) {
+	r := newRegistrationRegistry(t, nil)
+	credentials := resolver.ClusterCredentials{Host: "https://dev.example.com", Token: "secret"}
+
+	registered, err := r.RegisterCluster(t.Context(), "dev", "alice", credentials, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "dev", registered.Name)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), registered.ExpiresAt, time.Minute)
+	_, served := r.GetEndpoint("dev")
+	assert.True(t, served)
+
+	// Registering again renews the registration.
+	_, err = r.RegisterCluster(t.Context(), "dev", "alice", credentials, time.Hour)
+	require.NoError(t, err)
+
+	unregistered, err := r.UnregisterCluster(t.Context(), "dev", "alice")
+	require.NoError(t, err)
+	assert.True(t, unregistered)
+	_, served = r.GetEndpoint("dev")
+	assert.False(t, served)
+
+	unregistered, err = r.UnregisterCluster(t.Context(), "dev", "alice")
+	require.NoError(t, err)
+	assert.False(t, unregistered)
+}
+
+func TestRegisterCluster_Expiry(t *testing.T) {
+	r := newRegistrationRegistry(t, nil)
+
+	_, err := r.RegisterCluster(t.Context(), "dev", "alice", resolver.ClusterCredentials{Host: "https://dev.example.com", Token: "secret"}, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		_, served := r.GetEndpoint("dev")
+		return !served
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRegisterCluster_ListenerCluster(t *testing.T) {
+	r := newRegistrationRegistry(t, nil)
+	r.OnSchemaChanged(t.Context(), "prod", []byte(testSchema))
+
+	_, err := r.RegisterCluster(t.Context(), "prod", "alice", resolver.ClusterCredentials{Host: "https://dev.example.com", Token: "secret"}, time.Hour)
+	assert.ErrorContains(t, err, "served from the listener's schemas")
+
+	// Schemas from the listener take over registered clusters.
+	_, err = r.RegisterCluster(t.Context(), "dev", "alice", resolver.ClusterCredentials{Host: "https://dev.example.com", Token: "secret"}, time.Hour)
+	require.NoError(t, err)
+	r.OnSchemaChanged(t.Context(), "dev", []byte(testSchema))
+	unregistered, err := r.UnregisterCluster(t.Context(), "dev", "alice")
+	require.NoError(t, err)
+	assert.False(t, unregistered)
+}
+
+func TestRegisterCluster_GenerationFails(t *testing.T) {
+	r := newRegistrationRegistry(t, errors.New("connection refused"))
+
+	_, err := r.RegisterCluster(t.Context(), "dev", "alice", resolver.ClusterCredentials{Host: "https://dev.example.com", Token: "secret"}, time.Hour)
+	assert.ErrorContains(t, err, "connection refused")
+	assert.Nil(t, r.ClusterStatus(t.Context(), "dev"))
+}
+
+// mutationFields returns the names of the root mutation fields served by the
+// endpoint of a cluster.
+func mutationFields(t *testing.T, r *Registry, name string) []string {
+	t.Helper()
+	ep, served := r.GetEndpoint(name)
+	require.True(t, served)
+
+	req := httptest.NewRequest(http.MethodPost, "/"+name+"/graphql", strings.NewReader(`{"query": "{ __schema { mutationType { fields { name } } } }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(utilscontext.SetToken(req.Context(), "token"))
+	rec := httptest.NewRecorder()
+	ep.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var result struct {
+		Data struct {
+			Schema struct {
+				MutationType struct {
+					Fields []struct {
+						Name string `json:"name"`
+					} `json:"fields"`
+				} `json:"mutationType"`
+			} `json:"__schema"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	var names []string
+	for _, field := range result.Data.Schema.MutationType.Fields {
+		names = append(names, field.Name)
+	}
+	return names
+}
+
+func TestRegisterCluster_MutationsOnlyOnListenerClusters(t *testing.T) {
+	r := newRegistrationRegistry(t, nil)
+	r.OnSchemaChanged(t.Context(), "prod", []byte(testSchema))
+	assert.Contains(t, mutationFields(t, r, "prod"), "registerCluster")
+
+	_, err := r.RegisterCluster(t.Context(), "dev", "alice", resolver.ClusterCredentials{Host: "https://dev.example.com", Token: "secret"}, time.Hour)
+	require.NoError(t, err)
+	assert.NotContains(t, mutationFields(t, r, "dev"), "registerCluster", "registered clusters review their users themselves")
+	assert.NotContains(t, mutationFields(t, r, "dev"), "unregisterCluster")
+}
+
+func TestRegisterCluster_Owner(t *testing.T) {
+	r := newRegistrationRegistry(t, nil)
+	credentials := resolver.ClusterCredentials{Host: "https://dev.example.com", Token: "secret"}
+
+	_, err := r.RegisterCluster(t.Context(), "dev", "alice", credentials, time.Hour)
+	require.NoError(t, err)
+
+	_, err = r.RegisterCluster(t.Context(), "dev", "bob", credentials, time.Hour)
+	assert.ErrorContains(t, err, "registered by another user")
+
+	unregistered, err := r.UnregisterCluster(t.Context(), "dev", "bob")
+	assert.ErrorContains(t, err, "registered by another user")
+	assert.False(t, unregistered)
+	_, served := r.GetEndpoint("dev")
+	assert.True(t, served)
+
+	unregistered, err = r.UnregisterCluster(t.Context(), "dev", "alice")
+	require.NoError(t, err)
+	assert.True(t, unregistered)
+}
+
+func TestReviewRegistrant_HomeCluster(t *testing.T) {
+	r := newRegistrationRegistry(t, nil)
+
+	_, _, err := r.reviewRegistrant(t.Context(), "token")
+	assert.ErrorContains(t, err, "home cluster home of cluster registration is not served")
+
+	// A registered cluster cannot take the place of the home cluster.
+	_, err = r.RegisterCluster(t.Context(), "home", "alice", resolver.ClusterCredentials{Host: "https://dev.example.com", Token: "secret"}, time.Hour)
+	require.NoError(t, err)
+	_, _, err = r.reviewRegistrant(t.Context(), "token")
+	assert.ErrorContains(t, err, "is not served")
+}
+
+// blockGeneration makes the schema generation of r wait for the returned
+// channel to be closed, signalling generating once it started.
+func blockGeneration(r *Registry) (generating <-chan struct{}, release chan<- struct{}) {
+	started := make(chan struct{}, 1)
+	unblock := make(chan struct{})
+	r.generateSchema = func(context.Context, v1alpha1.ClusterMetadata) ([]byte, error) {
+		started <- struct{}{}
+		<-unblock
+		return []byte(testSchema), nil
+	}
+	return started, unblock
+}
+
+func TestRegisterCluster_Concurrent(t *testing.T) {
+	r := newRegistrationRegistry(t, nil)
+	generating, release := blockGeneration(r)
+	credentials := resolver.ClusterCredentials{Host: "https://dev.example.com", Token: "secret"}
+
+	done := make(chan error)
+	go func() {
+		_, err := r.RegisterCluster(t.Context(), "dev", "alice", credentials, time.Hour)
+		done <- err
+	}()
+	<-generating
+
+	// The name is reserved while alice's schema is generated.
+	_, err := r.RegisterCluster(t.Context(), "dev", "bob", credentials, time.Hour)
+	assert.ErrorContains(t, err, "being registered by another user")
+
+	close(release)
+	require.NoError(t, <-done)
+
+	_, err = r.RegisterCluster(t.Context(), "dev", "bob", credentials, time.Hour)
+	assert.ErrorContains(t, err, "registered by another user")
+	unregistered, err := r.UnregisterCluster(t.Context(), "dev", "alice")
+	require.NoError(t, err)
+	assert.True(t, unregistered)
+}
+
+func TestRegisterCluster_ListenerSchemaDuringGeneration(t *testing.T) {
+	r := newRegistrationRegistry(t, nil)
+	generating, release := blockGeneration(r)
+
+	done := make(chan error)
+	go func() {
+		_, err := r.RegisterCluster(t.Context(), "dev", "alice", resolver.ClusterCredentials{Host: "https://dev.example.com", Token: "secret"}, time.Hour)
+		done <- err
+	}()
+	<-generating
+
+	r.OnSchemaChanged(t.Context(), "dev", []byte(testSchema))
+	close(release)
+	assert.ErrorContains(t, <-done, "served from the listener's schemas")
+
+	// The listener's endpoint is still served.
+	assert.Contains(t, mutationFields(t, r, "dev"), "registerCluster")
+	unregistered, err := r.UnregisterCluster(t.Context(), "dev", "alice")
+	require.NoError(t, err)
+	assert.False(t, unregistered)
+}
@@ -5,9 +5,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/config"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/endpoint"
+	registrationpkg "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/registration"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/routing"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
 
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -15,20 +20,69 @@ import (
 type Registry struct {
 	mu        sync.RWMutex
 	endpoints map[string]*endpoint.Endpoint
-	config    config.Gateway
+	// degraded holds the smoke test failures of clusters whose last loaded
+	// schema failed them.
+	degraded map[string]error
+	// failed holds the errors of clusters whose last schema failed to load.
+	failed map[string]error
+	// canaries holds the new schemas served next to the current endpoints
+	// until they are promoted or rolled back.
+	canaries map[string]*canary
+	// categories holds the category feeds of the clusters, which outlive
+	// their endpoints so subscriptions learn about new schemas.
+	categories map[string]*resolver.CategoryFeed
+	// loaded holds when the served schemas of the clusters were loaded.
+	loaded map[string]time.Time
+	// registered holds the clusters registered at runtime.
+	registered map[string]*registration
+	// registering holds the users registering clusters by cluster name
+	// while their schemas are generated.
+	registering map[string]string
+	// home reviews the users registering clusters on the endpoint of the
+	// home cluster.
+	home homeCluster
+	// generateSchema generates the schemas of registered clusters.
+	generateSchema func(context.Context, v1alpha1.ClusterMetadata) ([]byte, error)
+	config         config.Gateway
 }
 
 // New creates a new endpoint registry.
 func New(cfg config.Gateway) *Registry {
 	return &Registry{
-		endpoints: make(map[string]*endpoint.Endpoint),
-		config:    cfg,
+		endpoints:   make(map[string]*endpoint.Endpoint),
+		degraded:    make(map[string]error),
+		failed:      make(map[string]error),
+		canaries:    make(map[string]*canary),
+		categories:  make(map[string]*resolver.CategoryFeed),
+		loaded:      make(map[string]time.Time),
+		registered:  make(map[string]*registration),
+		registering: make(map[string]string),
+
+		generateSchema: registrationpkg.GenerateSchema,
+		config:         cfg,
 	}
 }
 
 // OnSchemaChanged implements watcher.SchemaEventHandler.
-// It is called when a schema is created or updated.
+// It is called when a schema is created or updated. Schemas from the listener
+// replace those of registered clusters of the same name.
 func (r *Registry) OnSchemaChanged(ctx context.Context, clusterName string, schema []byte) {
+	ep, categories, err := r.newEndpoint(ctx, clusterName, schema, r.clusterRegistration())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.forgetRegistration(clusterName)
+	if err != nil {
+		r.failed[clusterName] = err
+		return
+	}
+	r.serveEndpoint(ctx, clusterName, ep, categories)
+}
+
+// newEndpoint creates the endpoint of a new schema of a cluster, with the
+// cluster registration mutations if registration is not nil.
+func (r *Registry) newEndpoint(ctx context.Context, clusterName string, schema []byte, registration *resolver.ClusterRegistration) (*endpoint.Endpoint, *resolver.CategoryFeed, error) {
 	logger := log.FromContext(ctx)
 	logger.V(4).Info("Loading endpoint", "cluster", clusterName)
 
@@ -38,6 +92,14 @@ func (r *Registry) OnSchemaChanged(ctx context.Context, clusterName string, sche
 	createCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	r.mu.Lock()
+	categories, exists := r.categories[clusterName]
+	if !exists {
+		categories = resolver.NewCategoryFeed()
+		r.categories[clusterName] = categories
+	}
+	r.mu.Unlock()
+
 	// Create endpoint outside the lock to avoid holding it during slow operations
 	ep, err := endpoint.New(
 		createCtx,
@@ -47,34 +109,99 @@ func (r *Registry) OnSchemaChanged(ctx context.Context, clusterName string, sche
 		r.config.Limits,
 		r.config.TokenReviewCacheTTL,
 		r.config.Validator,
+		r.config.ChangeFeed,
+		r.config.SavedQueries,
+		categories,
+		r.ClusterStatus,
+		registration,
 	)
 	if err != nil {
 		logger.Error(err, "Failed to create endpoint", "cluster", clusterName)
-		return
+		return nil, nil, err
+	}
+	return ep, categories, nil
+}
+
+// serveEndpoint serves ep for a cluster, replacing its current endpoint or
+// running it as canary. The caller must hold r.mu.
+func (r *Registry) serveEndpoint(ctx context.Context, clusterName string, ep *endpoint.Endpoint, categories *resolver.CategoryFeed) {
+	logger := log.FromContext(ctx)
+
+	delete(r.failed, clusterName)
+
+	old, exists := r.endpoints[clusterName]
+	if exists && r.config.Canary.Duration > 0 {
+		r.startCanary(ctx, clusterName, old, ep)
+	} else {
+		if exists {
+			old.Close()
+			logger.V(4).Info("Replaced existing endpoint", "cluster", clusterName)
+		}
+		r.endpoints[clusterName] = ep
+		r.loaded[clusterName] = time.Now()
+		categories.Publish(ep.Categories())
+		logger.Info("Successfully loaded endpoint", "cluster", clusterName)
 	}
 
+	if r.config.SmokeTests != nil {
+		go r.runSmokeTests(ctx, clusterName, ep, ep.AdminConfig())
+	}
+}
+
+// runSmokeTests runs the smoke tests against a newly loaded endpoint and
+// updates the cluster's degraded state, unless the endpoint was replaced in
+// the meantime. Canaries failing the smoke tests are rolled back.
+func (r *Registry) runSmokeTests(ctx context.Context, clusterName string, ep *endpoint.Endpoint, adminConfig *rest.Config) {
+	logger := log.FromContext(ctx).WithValues("cluster", clusterName)
+
+	err := r.config.SmokeTests.Run(ctx, clusterName, ep, adminConfig)
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if old, exists := r.endpoints[clusterName]; exists {
-		old.Close()
-		logger.V(4).Info("Replaced existing endpoint", "cluster", clusterName)
+	if c, running := r.canaries[clusterName]; running && c.endpoint == ep {
+		if err != nil {
+			delete(r.canaries, clusterName)
+			ep.Close()
+			logger.Error(err, "Smoke tests failed, rolled back canary schema")
+			return
+		}
+		c.smokeTestsPassed = true
+		return
+	}
+
+	if r.endpoints[clusterName] != ep {
+		return
+	}
+
+	_, wasDegraded := r.degraded[clusterName]
+	if err != nil {
+		r.degraded[clusterName] = err
+		logger.Error(err, "Smoke tests failed, cluster is degraded")
+		return
 	}
 
-	r.endpoints[clusterName] = ep
-	logger.Info("Successfully loaded endpoint", "cluster", clusterName)
+	delete(r.degraded, clusterName)
+	if wasDegraded {
+		logger.Info("Smoke tests passed, cluster is no longer degraded")
+	}
 }
 
 // OnSchemaDeleted implements watcher.SchemaEventHandler.
 // It is called when a schema is removed.
 func (r *Registry) OnSchemaDeleted(ctx context.Context, clusterName string) {
-	logger := log.FromContext(ctx)
-
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.deleteEndpoint(ctx, clusterName)
+}
+
+// deleteEndpoint stops serving a cluster. The caller must hold r.mu.
+func (r *Registry) deleteEndpoint(ctx context.Context, clusterName string) {
+	logger := log.FromContext(ctx)
 	logger.V(4).Info("Removing endpoint", "cluster", clusterName)
 
+	delete(r.failed, clusterName)
 	old, exists := r.endpoints[clusterName]
 	if !exists {
 		logger.V(2).Info("Attempted to remove non-existent endpoint", "cluster", clusterName)
@@ -83,6 +210,19 @@ func (r *Registry) OnSchemaDeleted(ctx context.Context, clusterName string) {
 
 	old.Close()
 	delete(r.endpoints, clusterName)
+	delete(r.loaded, clusterName)
+	delete(r.degraded, clusterName)
+	if categories, exists := r.categories[clusterName]; exists {
+		categories.Publish(nil)
+		delete(r.categories, clusterName)
+	}
+	if c, running := r.canaries[clusterName]; running {
+		c.endpoint.Close()
+		delete(r.canaries, clusterName)
+	}
+	if r.config.SmokeTests != nil {
+		r.config.SmokeTests.Forget(clusterName)
+	}
 	logger.Info("Successfully removed endpoint", "cluster", clusterName)
 }
 
@@ -93,3 +233,70 @@ func (r *Registry) GetEndpoint(name string) (*endpoint.Endpoint, bool) {
 	ep, exists := r.endpoints[name]
 	return ep, exists
 }
+
+// SchemaSizes returns the schema sizes of the served endpoints by cluster.
+// Canaries are not included.
+func (r *Registry) SchemaSizes() map[string]endpoint.SchemaSize {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sizes := make(map[string]endpoint.SchemaSize, len(r.endpoints))
+	for name, ep := range r.endpoints {
+		sizes[name] = ep.SchemaSize()
+	}
+	return sizes
+}
+
+// Degraded returns the smoke test failures of a cluster, nil if its last
+// loaded schema passed them or no smoke tests are configured.
+func (r *Registry) Degraded(name string) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.degraded[name]
+}
+
+// ClusterStatus implements resolver.ClusterStatusFunc. Clusters whose schema
+// failed to load are reported unserved; the health of their API server is
+// unknown.
+func (r *Registry) ClusterStatus(ctx context.Context, name string) *resolver.ClusterStatus {
+	r.mu.RLock()
+	ep, served := r.endpoints[name]
+	loaded := r.loaded[name]
+	failure, failed := r.failed[name]
+	r.mu.RUnlock()
+
+	if !served && !failed {
+		return nil
+	}
+
+	status := &resolver.ClusterStatus{Name: name, Served: served}
+	if failed {
+		status.SchemaError = failure.Error()
+	}
+	if !served {
+		return status
+	}
+
+	// Probe outside the lock, the API server may be slow to respond.
+	health := ep.Health(ctx)
+	status.Reachable = health.Reachable
+	status.CredentialsValid = health.CredentialsValid
+	status.ServerVersion = health.ServerVersion
+	status.Error = health.Error
+	if !health.LastSuccess.IsZero() {
+		status.LastSuccessfulCall = &health.LastSuccess
+	}
+	status.SchemaUpdatedAt = &loaded
+	return status
+}
+
+// RouteError returns why requests to a cluster without an endpoint cannot be
+// served: its schema failed to load, possibly because the cluster was
+// unreachable, or the gateway has no schema for it.
+func (r *Registry) RouteError(name string) *routing.Error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err, failed := r.failed[name]; failed {
+		return routing.SchemaLoadFailed(name, err)
+	}
+	return routing.ClusterNotFound(name)
+}
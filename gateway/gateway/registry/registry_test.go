@@ -0,0 +1,39 @@
+package registry
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/config"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/routing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteError(t *testing.T) {
+	r := New(config.Gateway{})
+
+	assert.Equal(t, routing.CodeClusterNotFound, r.RouteError("c1").Code)
+
+	r.OnSchemaChanged(t.Context(), "c1", []byte("not a schema"))
+	routeErr := r.RouteError("c1")
+	assert.Equal(t, routing.CodeSchemaFailed, routeErr.Code)
+	assert.Equal(t, http.StatusServiceUnavailable, routeErr.Status)
+
+	r.OnSchemaDeleted(t.Context(), "c1")
+	assert.Equal(t, routing.CodeClusterNotFound, r.RouteError("c1").Code)
+}
+
+func TestClusterStatus(t *testing.T) {
+	r := New(config.Gateway{})
+
+	assert.Nil(t, r.ClusterStatus(t.Context(), "c1"))
+
+	r.OnSchemaChanged(t.Context(), "c1", []byte("not a schema"))
+	status := r.ClusterStatus(t.Context(), "c1")
+	require.NotNil(t, status)
+	assert.Equal(t, "c1", status.Name)
+	assert.False(t, status.Served)
+	assert.Contains(t, status.SchemaError, "failed to parse schema")
+	assert.Nil(t, status.SchemaUpdatedAt)
+}
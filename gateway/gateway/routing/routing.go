@@ -0,0 +1,133 @@
+// Package routing defines the errors returned when a request cannot be routed
+// to the GraphQL endpoint of a cluster. They are written as GraphQL responses
+// whose error extensions carry a code, whether retrying can help, and the
+// status of the cluster, so clients can tell an unknown cluster from one that
+// is temporarily unavailable.
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+// Code identifies why a request was not served.
+type Code string
+
+const (
+	// CodeGatewayNotReady is returned before the gateway has started.
+	CodeGatewayNotReady Code = "GATEWAY_NOT_READY"
+	// CodeClusterRequired is returned for paths without a cluster name.
+	CodeClusterRequired Code = "CLUSTER_REQUIRED"
+	// CodeClusterNotFound is returned for clusters the gateway has no schema for.
+	CodeClusterNotFound Code = "CLUSTER_NOT_FOUND"
+	// CodeSchemaFailed is returned for clusters whose schema failed to load.
+	CodeSchemaFailed Code = "SCHEMA_FAILED"
+	// CodeClusterUnreachable is returned for clusters whose API server could
+	// not be reached while loading their schema.
+	CodeClusterUnreachable Code = "CLUSTER_UNREACHABLE"
+	// CodeUnauthenticated is returned for requests without a valid token.
+	CodeUnauthenticated Code = "UNAUTHENTICATED"
+	// CodeAuthenticationUnavailable is returned if the token could not be
+	// reviewed by the cluster.
+	CodeAuthenticationUnavailable Code = "AUTHENTICATION_UNAVAILABLE"
+)
+
+// ClusterStatus is the state of the cluster a request was addressed to.
+type ClusterStatus string
+
+const (
+	// ClusterUnknown is the status of clusters the gateway has no schema for.
+	ClusterUnknown ClusterStatus = "Unknown"
+	// ClusterReady is the status of clusters with a loaded schema.
+	ClusterReady ClusterStatus = "Ready"
+	// ClusterSchemaFailed is the status of clusters whose schema failed to load.
+	ClusterSchemaFailed ClusterStatus = "SchemaFailed"
+	// ClusterUnreachable is the status of clusters whose API server could not
+	// be reached.
+	ClusterUnreachable ClusterStatus = "Unreachable"
+)
+
+// Error is a request that could not be routed to a cluster's endpoint.
+type Error struct {
+	// Status is the HTTP status code of the response.
+	Status        int
+	Code          Code
+	Message       string
+	Retryable     bool
+	ClusterStatus ClusterStatus
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// GatewayNotReady returns the error for requests received before the gateway
+// has started.
+func GatewayNotReady() *Error {
+	return &Error{Status: http.StatusServiceUnavailable, Code: CodeGatewayNotReady, Message: "gateway not started", Retryable: true, ClusterStatus: ClusterUnknown}
+}
+
+// ClusterRequired returns the error for paths without a cluster name.
+func ClusterRequired() *Error {
+	return &Error{Status: http.StatusBadRequest, Code: CodeClusterRequired, Message: "cluster name is required in path: /api/clusters/{clusterName}", ClusterStatus: ClusterUnknown}
+}
+
+// ClusterNotFound returns the error for clusters the gateway has no schema for.
+func ClusterNotFound(cluster string) *Error {
+	return &Error{Status: http.StatusNotFound, Code: CodeClusterNotFound, Message: "cluster " + cluster + " not found", ClusterStatus: ClusterUnknown}
+}
+
+// SchemaLoadFailed returns the error for a cluster whose schema failed to
+// load with err. Failures to reach the cluster are retryable, other failures
+// last until the listener publishes a new schema.
+func SchemaLoadFailed(cluster string, err error) *Error {
+	if IsUnreachable(err) {
+		return &Error{Status: http.StatusServiceUnavailable, Code: CodeClusterUnreachable, Message: "cluster " + cluster + " is unreachable: " + err.Error(), Retryable: true, ClusterStatus: ClusterUnreachable}
+	}
+	return &Error{Status: http.StatusServiceUnavailable, Code: CodeSchemaFailed, Message: "schema of cluster " + cluster + " failed to load: " + err.Error(), ClusterStatus: ClusterSchemaFailed}
+}
+
+// Unauthenticated returns the error for requests without a valid token to a
+// cluster in the given status.
+func Unauthenticated(message string, status ClusterStatus) *Error {
+	return &Error{Status: http.StatusUnauthorized, Code: CodeUnauthenticated, Message: message, ClusterStatus: status}
+}
+
+// AuthenticationUnavailable returns the error for tokens the cluster could
+// not review.
+func AuthenticationUnavailable() *Error {
+	return &Error{Status: http.StatusServiceUnavailable, Code: CodeAuthenticationUnavailable, Message: "token could not be reviewed by the cluster", Retryable: true, ClusterStatus: ClusterUnreachable}
+}
+
+// IsUnreachable reports whether err is caused by a failure to connect to or
+// get an answer from a server, as opposed to a failure reported by it.
+func IsUnreachable(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		utilnet.IsConnectionRefused(err) ||
+		utilnet.IsConnectionReset(err) ||
+		utilnet.IsProbableEOF(err)
+}
+
+// Write writes err as a GraphQL response with the code, retryable flag and
+// cluster status in the extensions of its only error.
+func Write(w http.ResponseWriter, err *Error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(err.Status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"errors": []map[string]any{{
+			"message": err.Message,
+			"extensions": map[string]any{
+				"code":          err.Code,
+				"retryable":     err.Retryable,
+				"clusterStatus": err.ClusterStatus,
+			},
+		}},
+	})
+}
@@ -0,0 +1,76 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaLoadFailed(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantCode      Code
+		wantRetryable bool
+		wantStatus    ClusterStatus
+	}{
+		{
+			name:          "connection refused",
+			err:           fmt.Errorf("failed to create cluster: %w", &url.Error{Op: "Get", URL: "https://api", Err: syscall.ECONNREFUSED}),
+			wantCode:      CodeClusterUnreachable,
+			wantRetryable: true,
+			wantStatus:    ClusterUnreachable,
+		},
+		{
+			name:          "timeout",
+			err:           fmt.Errorf("failed to create endpoint: %w", context.DeadlineExceeded),
+			wantCode:      CodeClusterUnreachable,
+			wantRetryable: true,
+			wantStatus:    ClusterUnreachable,
+		},
+		{
+			name:       "invalid schema",
+			err:        errors.New("failed to unmarshal schema"),
+			wantCode:   CodeSchemaFailed,
+			wantStatus: ClusterSchemaFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SchemaLoadFailed("c1", tt.err)
+			assert.Equal(t, http.StatusServiceUnavailable, err.Status)
+			assert.Equal(t, tt.wantCode, err.Code)
+			assert.Equal(t, tt.wantRetryable, err.Retryable)
+			assert.Equal(t, tt.wantStatus, err.ClusterStatus)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Write(rec, ClusterNotFound("c1"))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var body struct {
+		Errors []struct {
+			Message    string         `json:"message"`
+			Extensions map[string]any `json:"extensions"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, "cluster c1 not found", body.Errors[0].Message)
+	assert.Equal(t, map[string]any{"code": "CLUSTER_NOT_FOUND", "retryable": false, "clusterStatus": "Unknown"}, body.Errors[0].Extensions)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/config"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/registry"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/routing"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/watcher"
 	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
 
@@ -75,7 +76,7 @@ func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	logger := log.FromContext(r.Context())
 
 	if !s.started {
-		http.Error(w, "Gateway not started", http.StatusServiceUnavailable)
+		routing.Write(w, routing.GatewayNotReady())
 		return
 	}
 
@@ -83,27 +84,34 @@ func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	clusterName, ok := utilscontext.GetClusterFromCtx(r.Context())
 	if !ok || clusterName == "" {
 		logger.Error(fmt.Errorf("cluster name not found in context"), "Missing cluster name", "path", r.URL.Path)
-		http.Error(w, "Cluster name is required in path: /api/clusters/{clusterName}", http.StatusBadRequest)
+		routing.Write(w, routing.ClusterRequired())
 		return
 	}
 
-	// Get endpoint for cluster
-	endpoint, exists := s.registry.GetEndpoint(clusterName)
+	// Get endpoint for cluster, or its canary
+	endpoint, isCanary, exists := s.registry.Route(clusterName, r)
 	if !exists {
-		logger.Error(fmt.Errorf("endpoint not found"), "Target endpoint not found",
+		routeErr := s.registry.RouteError(clusterName)
+		logger.Error(routeErr, "Target endpoint not found",
 			"cluster", clusterName,
 			"path", r.URL.Path,
+			"code", routeErr.Code,
 		)
-		http.NotFound(w, r)
+		routing.Write(w, routeErr)
 		return
 	}
 
 	logger.V(4).Info("Routing request to endpoint",
 		"cluster", clusterName,
+		"canary", isCanary,
 		"method", r.Method,
 		"path", r.URL.Path,
 	)
 
+	if isCanary {
+		w.Header().Set(registry.CanaryHeader, "true")
+	}
+
 	endpoint.ServeHTTP(w, r)
 }
 
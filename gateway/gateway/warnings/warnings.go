@@ -0,0 +1,138 @@
+// Package warnings propagates warnings returned by the Kubernetes API server
+// (deprecation notices, admission warnings) to GraphQL clients.
+//
+// Handler is installed as the rest.Config warning handler of a cluster's
+// client and records warnings in the collector carried by the request
+// context. Extension creates that collector for every GraphQL operation and
+// adds the collected warnings to the response as extensions.warnings.
+package warnings
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ExtensionName is the key of the warnings in the response extensions.
+const ExtensionName = "warnings"
+
+type collectorKey struct{}
+
+// collector gathers the distinct warnings of one GraphQL operation.
+type collector struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (c *collector) add(message string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !slices.Contains(c.messages, message) {
+		c.messages = append(c.messages, message)
+	}
+}
+
+func (c *collector) list() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.messages)
+}
+
+// WithCollector returns a context that collects API server warnings.
+func WithCollector(ctx context.Context) context.Context {
+	return context.WithValue(ctx, collectorKey{}, &collector{})
+}
+
+// FromContext returns the warnings collected in ctx so far.
+func FromContext(ctx context.Context) []string {
+	c, ok := ctx.Value(collectorKey{}).(*collector)
+	if !ok {
+		return nil
+	}
+	return c.list()
+}
+
+// Add records a warning of the gateway itself in the collector of ctx, to be
+// returned along with those of the API server. It is dropped if ctx has no
+// collector.
+func Add(ctx context.Context, message string) {
+	if c, ok := ctx.Value(collectorKey{}).(*collector); ok {
+		c.add(message)
+	}
+}
+
+// Handler records API server warnings in the collector of the request
+// context. Warnings of requests without a collector are logged.
+type Handler struct{}
+
+var _ rest.WarningHandlerWithContext = Handler{}
+
+// HandleWarningHeaderWithContext implements rest.WarningHandlerWithContext.
+func (Handler) HandleWarningHeaderWithContext(ctx context.Context, code int, _ string, message string) {
+	if code != 299 || message == "" {
+		return
+	}
+
+	if c, ok := ctx.Value(collectorKey{}).(*collector); ok {
+		c.add(message)
+		return
+	}
+
+	log.FromContext(ctx).Info("Kubernetes API warning", "warning", message)
+}
+
+// Extension is a graphql.Extension that adds the API server warnings of an
+// operation to the response extensions. Responses without warnings are left
+// unchanged.
+type Extension struct{}
+
+var _ graphql.Extension = Extension{}
+
+func (Extension) Init(ctx context.Context, _ *graphql.Params) context.Context {
+	return WithCollector(ctx)
+}
+
+func (Extension) Name() string {
+	return ExtensionName
+}
+
+func (Extension) ParseDidStart(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+	return ctx, func(error) {}
+}
+
+func (Extension) ValidationDidStart(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+	return ctx, func([]gqlerrors.FormattedError) {}
+}
+
+func (Extension) ExecutionDidStart(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+	return ctx, func(result *graphql.Result) {
+		messages := FromContext(ctx)
+		if result == nil || len(messages) == 0 {
+			return
+		}
+		if result.Extensions == nil {
+			result.Extensions = map[string]any{}
+		}
+		result.Extensions[ExtensionName] = messages
+	}
+}
+
+func (Extension) ResolveFieldDidStart(ctx context.Context, _ *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+	return ctx, func(any, error) {}
+}
+
+// HasResult reports false; warnings are added in ExecutionDidStart's finish
+// function so that responses without warnings carry no extension entry.
+func (Extension) HasResult() bool {
+	return false
+}
+
+func (Extension) GetResult(context.Context) any {
+	return nil
+}
@@ -0,0 +1,90 @@
+package warnings
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSchema(t *testing.T, warn func(p graphql.ResolveParams)) graphql.Schema {
+	t.Helper()
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"pods": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						warn(p)
+						return "ok", nil
+					},
+				},
+			},
+		}),
+		Extensions: []graphql.Extension{Extension{}},
+	})
+	require.NoError(t, err)
+	return schema
+}
+
+func TestExtension(t *testing.T) {
+	tests := []struct {
+		name         string
+		warn         func(p graphql.ResolveParams)
+		wantWarnings []string
+	}{
+		{
+			name: "no warnings",
+			warn: func(graphql.ResolveParams) {},
+		},
+		{
+			name: "warnings are deduplicated",
+			warn: func(p graphql.ResolveParams) {
+				Handler{}.HandleWarningHeaderWithContext(p.Context, 299, "-", "v1beta1 is deprecated")
+				Handler{}.HandleWarningHeaderWithContext(p.Context, 299, "-", "v1beta1 is deprecated")
+				Handler{}.HandleWarningHeaderWithContext(p.Context, 299, "-", "missing label")
+			},
+			wantWarnings: []string{"v1beta1 is deprecated", "missing label"},
+		},
+		{
+			name: "gateway warnings are added to those of the API server",
+			warn: func(p graphql.ResolveParams) {
+				Handler{}.HandleWarningHeaderWithContext(p.Context, 299, "-", "v1beta1 is deprecated")
+				Add(p.Context, "only the status is readable")
+			},
+			wantWarnings: []string{"v1beta1 is deprecated", "only the status is readable"},
+		},
+		{
+			name: "non-299 and empty warnings are ignored",
+			warn: func(p graphql.ResolveParams) {
+				Handler{}.HandleWarningHeaderWithContext(p.Context, 199, "-", "misc")
+				Handler{}.HandleWarningHeaderWithContext(p.Context, 299, "-", "")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := graphql.Do(graphql.Params{
+				Schema:        newSchema(t, tt.warn),
+				RequestString: "{ pods }",
+				Context:       t.Context(),
+			})
+			require.Empty(t, result.Errors)
+
+			if tt.wantWarnings == nil {
+				assert.NotContains(t, result.Extensions, ExtensionName)
+				return
+			}
+			assert.Equal(t, tt.wantWarnings, result.Extensions[ExtensionName])
+		})
+	}
+}
+
+func TestHandler_WithoutCollector(t *testing.T) {
+	// Must not panic when the request did not go through the extension.
+	Handler{}.HandleWarningHeaderWithContext(t.Context(), 299, "-", "deprecated")
+	assert.Nil(t, FromContext(t.Context()))
+}
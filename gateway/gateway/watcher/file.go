@@ -2,6 +2,7 @@ package watcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -9,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/changelog"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -20,10 +22,22 @@ type SchemaEventHandler interface {
 }
 
 // FileWatcher watches a directory for schema files and notifies the handler.
+// Changes appended to the changelogs of the schemas by the listener are
+// applied to the loaded schemas.
 type FileWatcher struct {
 	watcher   *fsnotify.Watcher
 	handler   SchemaEventHandler
 	watchPath string
+
+	// schemas are the loaded schemas by cluster name.
+	schemas map[string]loadedSchema
+}
+
+// loadedSchema is a schema with the changes of its changelog applied.
+type loadedSchema struct {
+	schema []byte
+	// hash is the changelog.Hash of schema, empty until it is needed.
+	hash string
 }
 
 // NewFileWatcher creates a new file watcher that will notify the given handler
@@ -37,6 +51,7 @@ func NewFileWatcher(handler SchemaEventHandler) (*FileWatcher, error) {
 	return &FileWatcher{
 		watcher: watcher,
 		handler: handler,
+		schemas: map[string]loadedSchema{},
 	}, nil
 }
 
@@ -145,6 +160,11 @@ func (fw *FileWatcher) onFileChanged(ctx context.Context, filePath string) {
 		return
 	}
 
+	if fw.isChangelog(filePath) {
+		fw.onChangelogChanged(ctx, filePath)
+		return
+	}
+
 	// Read the file content
 	data, err := os.ReadFile(filePath)
 	if err != nil {
@@ -152,19 +172,86 @@ func (fw *FileWatcher) onFileChanged(ctx context.Context, filePath string) {
 		return
 	}
 
+	// Apply the changes appended since the file was written
+	loaded := loadedSchema{schema: data}
+	if rel, err := filepath.Rel(fw.watchPath, filePath); err == nil {
+		loaded, err = fw.applyChangelog(loaded, filepath.Join(fw.watchPath, changelog.Dir, rel))
+		if err != nil {
+			logger.Error(err, "Failed to apply schema changelog", "path", filePath)
+			loaded = loadedSchema{schema: data}
+		}
+	}
+
 	// Extract cluster name from file path and notify handler
 	clusterName := extractClusterName(filePath)
-	fw.handler.OnSchemaChanged(ctx, clusterName, data)
+	fw.schemas[clusterName] = loaded
+	fw.handler.OnSchemaChanged(ctx, clusterName, loaded.schema)
 
 	logger.Info("Successfully processed schema file change", "path", filePath, "cluster", clusterName)
 }
 
+// onChangelogChanged applies the new entries of a changelog to the loaded
+// schema and notifies the schema handler if it changed.
+func (fw *FileWatcher) onChangelogChanged(ctx context.Context, filePath string) {
+	logger := log.FromContext(ctx)
+
+	clusterName := extractClusterName(filePath)
+	current, ok := fw.schemas[clusterName]
+	if !ok {
+		// The changelog is applied when the schema file is loaded.
+		return
+	}
+
+	loaded, err := fw.applyChangelog(current, filePath)
+	if err != nil {
+		logger.Error(err, "Failed to apply schema changelog", "path", filePath, "cluster", clusterName)
+		return
+	}
+	if loaded.hash == current.hash {
+		return
+	}
+
+	fw.schemas[clusterName] = loaded
+	fw.handler.OnSchemaChanged(ctx, clusterName, loaded.schema)
+
+	logger.Info("Successfully applied schema changelog", "path", filePath, "cluster", clusterName)
+}
+
+// applyChangelog applies the entries of the changelog at filePath following
+// the loaded schema. A missing changelog changes nothing.
+func (fw *FileWatcher) applyChangelog(loaded loadedSchema, filePath string) (loadedSchema, error) {
+	entries, err := os.ReadFile(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return loaded, nil
+	}
+	if err != nil {
+		return loaded, err
+	}
+
+	if loaded.hash == "" {
+		if loaded.hash, err = changelog.Hash(loaded.schema); err != nil {
+			return loaded, err
+		}
+	}
+	schema, hash, err := changelog.Replay(loaded.schema, loaded.hash, entries)
+	if err != nil {
+		return loaded, err
+	}
+	return loadedSchema{schema: schema, hash: hash}, nil
+}
+
 // onFileDeleted notifies the schema handler that a schema was deleted.
 func (fw *FileWatcher) onFileDeleted(ctx context.Context, filePath string) {
 	logger := log.FromContext(ctx)
 
+	// Changelogs are removed before their schema file is rewritten.
+	if fw.isChangelog(filePath) {
+		return
+	}
+
 	// Extract cluster name from file path and notify handler
 	clusterName := extractClusterName(filePath)
+	delete(fw.schemas, clusterName)
 	fw.handler.OnSchemaDeleted(ctx, clusterName)
 
 	logger.Info("Successfully processed schema file deletion", "path", filePath, "cluster", clusterName)
@@ -172,32 +259,35 @@ func (fw *FileWatcher) onFileDeleted(ctx context.Context, filePath string) {
 
 // loadAllFiles loads all files in the directory and subdirectories
 func (fw *FileWatcher) loadAllFiles(ctx context.Context, dir string) error {
-	logger := log.FromContext(ctx)
-
 	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		// Skip directories
+		// Skip directories; changelogs are applied with their schema files
 		if d.IsDir() {
+			if fw.isChangelog(path) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
-		// Read and process the file
-		data, err := os.ReadFile(path)
-		if err != nil {
-			logger.Error(err, "Failed to read schema file", "file", path)
-			return nil // Continue processing other files
-		}
-
-		clusterName := extractClusterName(path)
-		fw.handler.OnSchemaChanged(ctx, clusterName, data)
+		fw.onFileChanged(ctx, path)
 
 		return nil
 	})
 }
 
+// isChangelog reports whether a path is in the changelog directory.
+func (fw *FileWatcher) isChangelog(path string) bool {
+	rel, err := filepath.Rel(fw.watchPath, path)
+	if err != nil {
+		return false
+	}
+	first, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+	return first == changelog.Dir
+}
+
 // addWatchRecursively adds the directory and all subdirectories to the watcher
 func (fw *FileWatcher) addWatchRecursively(dir string) error {
 	if err := fw.watcher.Add(dir); err != nil {
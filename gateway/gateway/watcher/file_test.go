@@ -0,0 +1,59 @@
+package watcher_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/changelog"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/watcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileWatcher_AppliesChangelog(t *testing.T) {
+	dir := t.TempDir()
+	v1 := []byte(`{"definitions":{"a":{"type":"object"}}}`)
+	v2 := []byte(`{"definitions":{"a":{"type":"object"},"b":{"type":"object"}}}`)
+	v3 := []byte(`{"definitions":{"b":{"type":"object"}}}`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "root:a"), v1, 0o644))
+
+	entry, err := changelog.Diff(v1, v2)
+	require.NoError(t, err)
+	line, err := entry.Marshal()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, changelog.Dir), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, changelog.Dir, "root:a"), line, 0o644))
+
+	handler := newFakeHandler()
+	fw, err := watcher.NewFileWatcher(handler)
+	require.NoError(t, err)
+	go fw.Run(t.Context(), dir) //nolint:errcheck
+
+	waitForSchema := func(want []byte) {
+		t.Helper()
+		select {
+		case cluster := <-handler.changeCh:
+			assert.Equal(t, "root:a", cluster)
+			handler.mu.Lock()
+			defer handler.mu.Unlock()
+			assert.JSONEq(t, string(want), string(handler.changed[cluster]))
+		case <-time.After(5 * time.Second):
+			t.Fatal("schema not changed")
+		}
+	}
+
+	// The changelog is applied when the schema is loaded.
+	waitForSchema(v2)
+
+	entry, err = changelog.Diff(v2, v3)
+	require.NoError(t, err)
+	next, err := entry.Marshal()
+	require.NoError(t, err)
+	// The directories are watched after the schemas are loaded.
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, changelog.Dir, "root:a"), append(line, next...), 0o644))
+
+	waitForSchema(v3)
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// externalURLs builds the externally reachable URLs of the gateway for
+// responses linking to its endpoints, such as schema discovery documents.
+type externalURLs struct {
+	// publicURL is the base URL clients reach the gateway at, including any
+	// path prefix of an ingress or Gateway API route. nil derives it from
+	// the request.
+	publicURL *url.URL
+	// pathPrefix is the path prefix the gateway is served under.
+	pathPrefix string
+}
+
+// base returns the URL the gateway is reached at by the client of r, without
+// a trailing slash. Without a public URL, it is derived from the
+// X-Forwarded-Proto, X-Forwarded-Host and X-Forwarded-Prefix headers set by
+// ingress controllers and Gateway API implementations, falling back to the
+// request itself and the path prefix.
+func (u externalURLs) base(r *http.Request) string {
+	if u.publicURL != nil {
+		return strings.TrimSuffix(u.publicURL.String(), "/")
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := firstHeaderValue(r, "X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	host := r.Host
+	if forwarded := firstHeaderValue(r, "X-Forwarded-Host"); forwarded != "" {
+		host = forwarded
+	}
+	prefix := u.pathPrefix
+	if forwarded := firstHeaderValue(r, "X-Forwarded-Prefix"); forwarded != "" {
+		prefix = forwarded
+	}
+	return (&url.URL{Scheme: scheme, Host: host, Path: strings.TrimSuffix(prefix, "/")}).String()
+}
+
+// firstHeaderValue returns the first of the comma separated values of a
+// header, as proxies append to forwarded headers.
+func firstHeaderValue(r *http.Request, name string) string {
+	value, _, _ := strings.Cut(r.Header.Get(name), ",")
+	return strings.TrimSpace(value)
+}
+
+// withPathPrefix strips the path prefix from requests, for ingresses that
+// forward the path unchanged. Requests without it are served as they are, for
+// ingresses that rewrite the path.
+func withPathPrefix(next http.Handler, prefix string) http.Handler {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if path != prefix && !strings.HasPrefix(path, prefix+"/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = "/" + strings.TrimPrefix(strings.TrimPrefix(path, prefix), "/")
+		r2.URL.RawPath = ""
+		next.ServeHTTP(w, r2)
+	})
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalURLs_Base(t *testing.T) {
+	publicURL, err := url.Parse("https://api.example.com/k8s/")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		urls     externalURLs
+		headers  map[string]string
+		expected string
+	}{
+		{
+			name:     "request host",
+			expected: "http://gateway:8080",
+		},
+		{
+			name:     "path prefix",
+			urls:     externalURLs{pathPrefix: "/k8s/"},
+			expected: "http://gateway:8080/k8s",
+		},
+		{
+			name: "forwarded headers",
+			urls: externalURLs{pathPrefix: "/ignored"},
+			headers: map[string]string{
+				"X-Forwarded-Proto":  "https",
+				"X-Forwarded-Host":   "api.example.com, proxy.internal",
+				"X-Forwarded-Prefix": "/k8s",
+			},
+			expected: "https://api.example.com/k8s",
+		},
+		{
+			name:     "invalid forwarded proto",
+			headers:  map[string]string{"X-Forwarded-Proto": "javascript"},
+			expected: "http://gateway:8080",
+		},
+		{
+			name:     "public URL",
+			urls:     externalURLs{publicURL: publicURL},
+			headers:  map[string]string{"X-Forwarded-Host": "other.example.com"},
+			expected: "https://api.example.com/k8s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "http://gateway:8080/api/clusters/a/graphql", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			assert.Equal(t, tt.expected, tt.urls.base(r))
+		})
+	}
+}
+
+func TestWithPathPrefix(t *testing.T) {
+	var path string
+	handler := withPathPrefix(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+	}), "/k8s/")
+
+	for requested, expected := range map[string]string{
+		"/k8s/api/clusters/a/graphql": "/api/clusters/a/graphql",
+		"/api/clusters/a/graphql":     "/api/clusters/a/graphql",
+		"/k8s":                        "/",
+		"/k8sother/healthz":           "/k8sother/healthz",
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, requested, nil))
+		assert.Equal(t, expected, path, requested)
+	}
+}
@@ -5,16 +5,22 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/endpoint"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/graphql"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/middleware"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/routing"
 	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
 
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 type ServerConfig struct {
@@ -37,10 +43,37 @@ type ServerConfig struct {
 	ReadHeaderTimeout        time.Duration
 	IdleTimeout              time.Duration
 
+	// Mirror configures optional shadow traffic for read-only requests.
+	Mirror middleware.MirrorConfig
+
 	// SubscriptionMetrics provides optional Prometheus instrumentation for
 	// the subscription concurrency limiter. When nil, no metrics are recorded.
 	SubscriptionMetrics *middleware.InFlightMetrics
 
+	// RequestMetrics provides optional Prometheus instrumentation of query
+	// and mutation requests. When nil, no metrics are recorded.
+	RequestMetrics *middleware.RequestMetrics
+
+	// PersistedQueries holds the queries registered with Automatic Persisted
+	// Queries. When nil, persisted queries are not supported.
+	PersistedQueries *middleware.PersistedQueryCache
+
+	// Statusz serves the freshness of the schemas on /statusz. When nil, the
+	// endpoint is not registered.
+	Statusz http.Handler
+
+	// MemStats serves the memory use of the gateway on /debug/memstats.
+	// When nil, the endpoint is not registered.
+	MemStats http.Handler
+
+	// PublicURL is the base URL clients reach the gateway at, used for the
+	// URLs in discovery documents. When nil, it is derived from the
+	// forwarded headers of each request.
+	PublicURL *url.URL
+	// PathPrefix is the path prefix the gateway is served under behind an
+	// ingress, stripped from requests that still carry it.
+	PathPrefix string
+
 	Addr           string
 	EndpointSuffix string
 }
@@ -60,8 +93,15 @@ type Server struct {
 func NewServer(c ServerConfig) (*Server, error) {
 	s := http.NewServeMux()
 
-	queryHandler := middleware.WithMaxInFlightRequests(middleware.WithTimeout(c.Gateway, c.RequestTimeout), c.MaxInFlightRequests, nil)
-	subscriptionHandler := middleware.WithMaxInFlightRequests(middleware.WithTimeout(c.Gateway, c.SubscriptionTimeout), c.MaxInFlightSubscriptions, c.SubscriptionMetrics)
+	// Recovery runs inside the timeout, whose handler has a goroutine of its own.
+	gateway := middleware.WithRecovery(middleware.WithPersistedQueries(c.Gateway, c.PersistedQueries))
+	queryHandler := middleware.WithRequestMetrics(middleware.WithMaxInFlightRequests(middleware.WithTimeout(middleware.WithMirror(gateway, c.Mirror), c.RequestTimeout), c.MaxInFlightRequests, nil), c.RequestMetrics)
+	subscriptionHandler := middleware.WithMaxInFlightRequests(middleware.WithTimeout(gateway, c.SubscriptionTimeout), c.MaxInFlightSubscriptions, c.SubscriptionMetrics)
+
+	external := externalURLs{publicURL: c.PublicURL, pathPrefix: c.PathPrefix}
+	endpointURL := func(r *http.Request, clusterName string) string {
+		return external.base(r) + "/api/clusters/" + url.PathEscape(clusterName) + c.EndpointSuffix
+	}
 
 	s.Handle(fmt.Sprintf("/api/clusters/{clusterName}%s", c.EndpointSuffix), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if c.MaxRequestBodyBytes > 0 {
@@ -70,6 +110,17 @@ func NewServer(c ServerConfig) (*Server, error) {
 
 		clusterName := r.PathValue("clusterName")
 
+		// WebSocket connections are authenticated with their connection_init
+		// message, as browsers cannot set headers on them.
+		if graphql.IsWebSocketRequest(r) {
+			ctx := utilscontext.SetCluster(r.Context(), clusterName)
+			if authHeader := r.Header.Get("Authorization"); strings.HasPrefix(authHeader, "Bearer ") {
+				ctx = utilscontext.SetToken(ctx, strings.TrimPrefix(authHeader, "Bearer "))
+			}
+			subscriptionHandler.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
 		// Allow unauthenticated GET requests through to the playground handler.
 		if c.PlaygroundEnabled && r.Method == http.MethodGet {
 			ctx := utilscontext.SetCluster(r.Context(), clusterName)
@@ -77,18 +128,8 @@ func NewServer(c ServerConfig) (*Server, error) {
 			return
 		}
 
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Unauthorized: missing Authorization header", http.StatusUnauthorized)
-			return
-		}
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			http.Error(w, "Unauthorized: invalid Authorization header format", http.StatusUnauthorized)
-			return
-		}
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if token == "" {
-			http.Error(w, "Unauthorized: empty bearer token", http.StatusUnauthorized)
+		token, ok := bearerToken(w, r)
+		if !ok {
 			return
 		}
 
@@ -104,12 +145,35 @@ func NewServer(c ServerConfig) (*Server, error) {
 		}
 	}))
 
+	// Schema downloads are authenticated like queries, also with the
+	// playground enabled, as they list the resources of the cluster.
+	s.Handle(fmt.Sprintf("GET /api/clusters/{clusterName}%s/{%s}", c.EndpointSuffix, endpoint.SchemaFileParam), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(w, r)
+		if !ok {
+			return
+		}
+
+		ctx := utilscontext.SetToken(r.Context(), token)
+		ctx = utilscontext.SetCluster(ctx, r.PathValue("clusterName"))
+		ctx = utilscontext.SetEndpointURL(ctx, endpointURL(r, r.PathValue("clusterName")))
+		queryHandler.ServeHTTP(w, r.WithContext(ctx))
+	}))
+
 	// TODO: Add middleware for logging, metrics, tracing, etc.
 
 	// Health and metrics endpoints
 	s.Handle("/healthz", healthz.CheckHandler{Checker: healthz.Ping})
 	s.Handle("/readyz", healthz.CheckHandler{Checker: checkerOrPing(c.ReadyzCheck)})
-	s.Handle("/metrics", promhttp.Handler())
+	if c.Statusz != nil {
+		s.Handle("GET /statusz", c.Statusz)
+	}
+	if c.MemStats != nil {
+		s.Handle("GET /debug/memstats", c.MemStats)
+	}
+	// The client-go metrics of controller-runtime's registry count the
+	// requests sent to the API servers.
+	metricsHandler := promhttp.HandlerFor(prometheus.Gatherers{prometheus.DefaultGatherer, ctrlmetrics.Registry}, promhttp.HandlerOpts{})
+	s.Handle("/metrics", promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer, metricsHandler))
 
 	corsHandler := cors.New(cors.Options{
 		AllowedOrigins:   c.CORSConfig.AllowedOrigins,
@@ -119,7 +183,7 @@ func NewServer(c ServerConfig) (*Server, error) {
 
 	return &Server{
 		Server: &http.Server{
-			Handler:           corsHandler.Handler(s),
+			Handler:           corsHandler.Handler(withPathPrefix(s, c.PathPrefix)),
 			Addr:              c.Addr,
 			ReadHeaderTimeout: c.ReadHeaderTimeout,
 			IdleTimeout:       c.IdleTimeout,
@@ -127,6 +191,26 @@ func NewServer(c ServerConfig) (*Server, error) {
 	}, nil
 }
 
+// bearerToken returns the bearer token of the Authorization header, or
+// writes an UNAUTHENTICATED error if there is none.
+func bearerToken(w http.ResponseWriter, r *http.Request) (string, bool) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		routing.Write(w, routing.Unauthenticated("missing Authorization header", routing.ClusterUnknown))
+		return "", false
+	}
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		routing.Write(w, routing.Unauthenticated("invalid Authorization header format, expected a bearer token", routing.ClusterUnknown))
+		return "", false
+	}
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	if token == "" {
+		routing.Write(w, routing.Unauthenticated("empty bearer token", routing.ClusterUnknown))
+		return "", false
+	}
+	return token, true
+}
+
 // checkerOrPing returns the given checker if non-nil, otherwise healthz.Ping (always healthy).
 func checkerOrPing(c healthz.Checker) healthz.Checker {
 	if c != nil {
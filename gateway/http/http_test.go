@@ -79,6 +79,7 @@ func TestMissingAuthorizationHeader(t *testing.T) {
 
 	body, _ := io.ReadAll(resp.Body)
 	assert.Contains(t, string(body), "missing Authorization header")
+	assert.Contains(t, string(body), `"code":"UNAUTHENTICATED"`)
 }
 
 func TestInvalidAuthorizationFormat(t *testing.T) {
@@ -178,6 +179,47 @@ func TestPlaygroundDisabledRejectsUnauthenticatedGet(t *testing.T) {
 	assert.False(t, handler.called)
 }
 
+func TestSchemaDownloadRequiresToken(t *testing.T) {
+	handler := &captureHandler{}
+	srv, err := NewServer(ServerConfig{
+		Gateway:           handler,
+		Addr:              ":0",
+		EndpointSuffix:    testEndpointSuffix,
+		PlaygroundEnabled: true,
+		CORSConfig:        CORSConfig{},
+	})
+	require.NoError(t, err)
+	ts := httptest.NewServer(srv.Server.Handler)
+	defer ts.Close()
+
+	url := clusterURL(ts.URL, "my-cluster") + "/schema.graphql"
+
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "schema downloads are authenticated with the playground enabled")
+	assert.False(t, handler.called)
+
+	req, err := http.NewRequest("GET", url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer my-token")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, handler.called)
+	assert.Equal(t, "my-token", handler.token)
+	assert.Equal(t, "my-cluster", handler.clusterName)
+
+	req, err = http.NewRequest("POST", url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer my-token")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
 func TestHealthEndpointsReflectCheckerState(t *testing.T) {
 	failing := func(_ *http.Request) error { return fmt.Errorf("down") }
 
@@ -252,3 +294,24 @@ func TestMaxRequestBodyBytes(t *testing.T) {
 		})
 	}
 }
+
+func TestWebSocketUpgradeWithoutAuthorizationForwarded(t *testing.T) {
+	handler := &captureHandler{}
+	ts := newTestServer(t, handler)
+	defer ts.Close()
+
+	req, err := http.NewRequest("GET", clusterURL(ts.URL, "my-cluster"), nil)
+	require.NoError(t, err)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck
+
+	assert.True(t, handler.called)
+	assert.False(t, handler.tokenOK)
+	assert.Equal(t, "my-cluster", handler.clusterName)
+}
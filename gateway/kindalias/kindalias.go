@@ -0,0 +1,73 @@
+// Package kindalias loads the aliases serving renamed kinds under their
+// former names for a deprecation window.
+package kindalias
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// Kind is a group, version and kind in the aliases file.
+type Kind struct {
+	Group   string `json:"group,omitempty"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// GroupVersionKind returns the kind as a GroupVersionKind.
+func (k Kind) GroupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: k.Group, Version: k.Version, Kind: k.Kind}
+}
+
+// Alias serves the kind To under the former name From.
+type Alias struct {
+	From Kind `json:"from"`
+	To   Kind `json:"to"`
+	// Clusters limits the alias to these clusters. Empty applies it to all.
+	Clusters []string `json:"clusters,omitempty"`
+}
+
+// file is the format of the aliases file.
+type file struct {
+	Aliases []Alias `json:"aliases"`
+}
+
+// Load reads the aliases from a YAML file.
+func Load(path string) ([]Alias, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kind aliases: %w", err)
+	}
+
+	var f file
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse kind aliases %s: %w", path, err)
+	}
+
+	for i, alias := range f.Aliases {
+		if alias.From.Version == "" || alias.From.Kind == "" || alias.To.Version == "" || alias.To.Kind == "" {
+			return nil, fmt.Errorf("kind alias %d: from and to need a version and kind", i)
+		}
+		if alias.From == alias.To {
+			return nil, fmt.Errorf("kind alias %d: from and to are the same kind", i)
+		}
+	}
+	return f.Aliases, nil
+}
+
+// ForCluster returns the aliases applying to a cluster.
+func ForCluster(aliases []Alias, cluster string) []resolver.KindAlias {
+	var applied []resolver.KindAlias
+	for _, alias := range aliases {
+		if len(alias.Clusters) == 0 || slices.Contains(alias.Clusters, cluster) {
+			applied = append(applied, resolver.KindAlias{From: alias.From.GroupVersionKind(), To: alias.To.GroupVersionKind()})
+		}
+	}
+	return applied
+}
@@ -0,0 +1,98 @@
+package kindalias
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Alias
+		wantErr string
+	}{
+		{
+			name: "aliases",
+			content: `
+aliases:
+- from: {group: old.example.com, version: v1, kind: Widget}
+  to: {group: example.com, version: v1, kind: Gadget}
+- from: {version: v1, kind: Thing}
+  to: {group: example.com, version: v2, kind: Thing}
+  clusters: [prod]
+`,
+			want: []Alias{
+				{From: Kind{Group: "old.example.com", Version: "v1", Kind: "Widget"}, To: Kind{Group: "example.com", Version: "v1", Kind: "Gadget"}},
+				{From: Kind{Version: "v1", Kind: "Thing"}, To: Kind{Group: "example.com", Version: "v2", Kind: "Thing"}, Clusters: []string{"prod"}},
+			},
+		},
+		{
+			name: "missing kind",
+			content: `
+aliases:
+- from: {group: old.example.com, version: v1}
+  to: {group: example.com, version: v1, kind: Gadget}
+`,
+			wantErr: "kind alias 0: from and to need a version and kind",
+		},
+		{
+			name: "same kind",
+			content: `
+aliases:
+- from: {group: example.com, version: v1, kind: Gadget}
+  to: {group: example.com, version: v1, kind: Gadget}
+`,
+			wantErr: "kind alias 0: from and to are the same kind",
+		},
+		{
+			name: "unknown field",
+			content: `
+aliases:
+- form: {version: v1, kind: Widget}
+`,
+			wantErr: "failed to parse kind aliases",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "aliases.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			got, err := Load(path)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestForCluster(t *testing.T) {
+	aliases := []Alias{
+		{From: Kind{Version: "v1", Kind: "Widget"}, To: Kind{Group: "example.com", Version: "v1", Kind: "Widget"}},
+		{From: Kind{Version: "v1", Kind: "Thing"}, To: Kind{Group: "example.com", Version: "v1", Kind: "Thing"}, Clusters: []string{"prod"}},
+	}
+
+	widget := resolver.KindAlias{
+		From: schema.GroupVersionKind{Version: "v1", Kind: "Widget"},
+		To:   schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"},
+	}
+	thing := resolver.KindAlias{
+		From: schema.GroupVersionKind{Version: "v1", Kind: "Thing"},
+		To:   schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Thing"},
+	}
+	assert.Equal(t, []resolver.KindAlias{widget, thing}, ForCluster(aliases, "prod"))
+	assert.Equal(t, []resolver.KindAlias{widget}, ForCluster(aliases, "dev"))
+}
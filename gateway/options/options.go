@@ -2,8 +2,11 @@ package options
 
 import (
 	"errors"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apis"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/defaults"
 	"github.com/spf13/pflag"
 
@@ -20,6 +23,10 @@ type Options struct {
 type ExtraOptions struct {
 	// SchemasDir is the directory to store schema files (used with file watcher).
 	SchemasDir string
+	// SchemaStalenessThreshold is the age of schema files reported as stale. 0 disables the check.
+	SchemaStalenessThreshold time.Duration
+	// ListenerHealthURL is the health endpoint of the listener probed along with the schema files.
+	ListenerHealthURL string
 	// SchemaHandler specifies how to receive schema updates ("file" or "grpc").
 	SchemaHandler string
 	// GRPCListenerAddress is the address of the gRPC listener (used with grpc watcher).
@@ -42,6 +49,42 @@ type ExtraOptions struct {
 	RequestTimeout time.Duration
 	// SubscriptionTimeout is the maximum duration for a single SSE subscription.
 	SubscriptionTimeout time.Duration
+	// SubscriptionMaxLifetime is the renewable maximum lifetime of an SSE subscription.
+	SubscriptionMaxLifetime time.Duration
+	// SubscriptionExpiryWarning is how long before expiry a subscription receives an "expiring" event.
+	SubscriptionExpiryWarning time.Duration
+	// SSEKeepaliveInterval is how often a keepalive comment is sent to SSE clients.
+	SSEKeepaliveInterval time.Duration
+	// SSEWriteTimeout bounds writing an SSE event to a client.
+	SSEWriteTimeout time.Duration
+	// LiveQueryInterval is how often @live queries are re-executed.
+	LiveQueryInterval time.Duration
+	// SubscriptionEventLimits are the minimum intervals between events of an object, as Kind.group=interval.
+	SubscriptionEventLimits []string
+	// ExposeManagedFields indicates whether metadata.managedFields is part of the schema.
+	ExposeManagedFields bool
+	// EmptyValues selects how empty and absent list and map fields are returned: "preserve", "null" or "empty".
+	EmptyValues string
+	// FieldCasing selects how object fields are named: "preserve" or "camel".
+	FieldCasing string
+	// Federation indicates whether schemas are served as Apollo Federation subgraphs.
+	Federation bool
+	// KindAliasesFile is a YAML file with kinds served under their former names. Empty disables aliases.
+	KindAliasesFile string
+	// Introspection selects who may run introspection queries: "enabled", "authenticated" or "disabled".
+	Introspection string
+	// RBACSchemaPruning serves each user a schema without the kinds they may not list.
+	RBACSchemaPruning bool
+	// RBACSchemaPruningNamespace is the namespace the rules of users are reviewed in.
+	RBACSchemaPruningNamespace string
+	// HiddenMaturities are the maturities of kinds left out of the schema, e.g. alpha.
+	HiddenMaturities []string
+	// HiddenMaturitiesGroups are the groups whose members are served kinds of HiddenMaturities anyway.
+	HiddenMaturitiesGroups []string
+	// OperationAllowlistDir is a directory of GraphQL documents, the only ones that may be executed. Empty allows all queries.
+	OperationAllowlistDir string
+	// PersistedQueriesCacheSize is the number of Automatic Persisted Queries kept. 0 disables them.
+	PersistedQueriesCacheSize int
 	// MaxRequestBodyBytes is the maximum allowed request body size in bytes.
 	MaxRequestBodyBytes int64
 	// MaxInFlightRequests is the maximum number of concurrent in-flight requests.
@@ -54,12 +97,67 @@ type ExtraOptions struct {
 	MaxQueryComplexity int
 	// MaxQueryBatchSize is the maximum number of queries allowed in a single batched request.
 	MaxQueryBatchSize int
+	// QueryBatchConcurrency is how many operations of a batched request are executed at the same time.
+	QueryBatchConcurrency int
+	// MaxLogLines is the maximum number of lines returned by a single podLogsPage query.
+	MaxLogLines int
+	// MaxLogBytes is the maximum number of bytes of the lines of a page returned by a podLogsPage query.
+	MaxLogBytes int64
+	// MaxEvents is the maximum number of events returned by a single events field.
+	MaxEvents int
 	// ReadHeaderTimeout is the maximum duration for reading request headers.
 	ReadHeaderTimeout time.Duration
 	// IdleTimeout is the maximum duration an idle keep-alive connection remains open.
 	IdleTimeout time.Duration
 	// EndpointSuffix is the suffix appended to the cluster endpoint path (e.g. "/graphql").
 	EndpointSuffix string
+	// PublicURL is the base URL clients reach the gateway at, used in generated URLs. Empty derives it from forwarded headers.
+	PublicURL string
+	// PathPrefix is the path prefix the gateway is served under behind an ingress.
+	PathPrefix string
+	// MirrorURL is the base URL of a shadow gateway that receives mirrored read-only requests.
+	MirrorURL string
+	// MirrorPercentage is the percentage (0-100) of read-only requests mirrored to MirrorURL.
+	MirrorPercentage float64
+	// ChangeFeedSize is the number of recent mutations kept for the recentChanges query.
+	// 0 disables the change feed.
+	ChangeFeedSize int
+	// ChangeFeedFile is the file the change feed is persisted to. Empty keeps it in memory only.
+	ChangeFeedFile string
+	// ClusterRegistrationTTL is the maximum time clusters registered with the registerCluster
+	// mutation are served. 0 disables the mutation.
+	ClusterRegistrationTTL time.Duration
+	// ClusterRegistrationGroups are the groups whose members may register clusters.
+	ClusterRegistrationGroups []string
+	// ClusterRegistrationHomeCluster is the cluster served from the listener's schemas whose API server reviews the
+	// groups of users registering clusters.
+	ClusterRegistrationHomeCluster string
+	// SavedQueriesFile is the file the queries saved by users are stored in. Empty disables saved queries.
+	SavedQueriesFile string
+	// SmokeTestsFile is a YAML file with queries run against each cluster after its schema was loaded.
+	// Empty disables smoke tests.
+	SmokeTestsFile string
+	// SmokeTestServiceAccount is the namespace/name of the read-only ServiceAccount smoke tests run as.
+	SmokeTestServiceAccount string
+	// SmokeTestTimeout is the maximum duration of the smoke tests of one schema load.
+	SmokeTestTimeout time.Duration
+	// CanaryDuration is how long a new schema is served as a canary next to the current one
+	// before it is promoted. 0 replaces schemas immediately.
+	CanaryDuration time.Duration
+	// CanaryPercentage is the percentage (0-100) of requests routed to a canary schema.
+	CanaryPercentage float64
+	// CanaryMaxErrorIncrease is how much higher (0-1) the share of failed requests of a canary
+	// schema may be than that of the current schema for it to be promoted.
+	CanaryMaxErrorIncrease float64
+	// GCPercent sets GOGC. 0 keeps the GOGC environment variable, negative values disable
+	// collections until MemoryLimitBytes is reached.
+	GCPercent int
+	// MemoryLimitBytes sets GOMEMLIMIT. 0 keeps the GOMEMLIMIT environment variable.
+	MemoryLimitBytes int64
+	// MemoryBallastBytes is the size of a never used allocation delaying collections while the heap is small.
+	MemoryBallastBytes int64
+	// DebugMemStats serves the memory use and schema sizes of the clusters on /debug/memstats.
+	DebugMemStats bool
 }
 
 type completedOptions struct {
@@ -81,27 +179,62 @@ func NewOptions() *Options {
 		Logs: logs,
 
 		ExtraOptions: ExtraOptions{
-			SchemasDir:               "_output/schemas",
-			SchemaHandler:            "file",
-			GRPCListenerAddress:      "localhost:50051",
-			GRPCMaxRecvMsgSize:       defaults.DefaultGRPCMaxMsgSize,
-			ServerBindAddress:        "0.0.0.0",
-			ServerBindPort:           8080,
-			PlaygroundEnabled:        false,
-			CORSAllowedOrigins:       []string{},
-			CORSAllowedHeaders:       []string{},
-			TokenReviewCacheTTL:      30 * time.Second,
-			RequestTimeout:           60 * time.Second,
-			SubscriptionTimeout:      30 * time.Minute,
-			MaxRequestBodyBytes:      3 * 1024 * 1024,
-			MaxInFlightRequests:      400,
-			MaxInFlightSubscriptions: 50,
-			MaxQueryDepth:            10,
-			MaxQueryComplexity:       1000,
-			MaxQueryBatchSize:        10,
-			ReadHeaderTimeout:        32 * time.Second,
-			IdleTimeout:              90 * time.Second,
-			EndpointSuffix:           "/graphql",
+			SchemasDir:                 "_output/schemas",
+			SchemaStalenessThreshold:   24 * time.Hour,
+			ListenerHealthURL:          "",
+			SchemaHandler:              "file",
+			GRPCListenerAddress:        "localhost:50051",
+			GRPCMaxRecvMsgSize:         defaults.DefaultGRPCMaxMsgSize,
+			ServerBindAddress:          "0.0.0.0",
+			ServerBindPort:             8080,
+			PlaygroundEnabled:          false,
+			CORSAllowedOrigins:         []string{},
+			CORSAllowedHeaders:         []string{},
+			TokenReviewCacheTTL:        30 * time.Second,
+			RequestTimeout:             60 * time.Second,
+			SubscriptionTimeout:        30 * time.Minute,
+			SubscriptionMaxLifetime:    0,
+			SubscriptionExpiryWarning:  time.Minute,
+			SSEKeepaliveInterval:       30 * time.Second,
+			SSEWriteTimeout:            10 * time.Second,
+			LiveQueryInterval:          2 * time.Second,
+			ExposeManagedFields:        false,
+			EmptyValues:                "preserve",
+			FieldCasing:                "preserve",
+			Federation:                 false,
+			KindAliasesFile:            "",
+			Introspection:              "enabled",
+			RBACSchemaPruning:          false,
+			RBACSchemaPruningNamespace: "default",
+			OperationAllowlistDir:      "",
+			PersistedQueriesCacheSize:  1000,
+			MaxRequestBodyBytes:        3 * 1024 * 1024,
+			MaxInFlightRequests:        400,
+			MaxInFlightSubscriptions:   50,
+			MaxQueryDepth:              10,
+			MaxQueryComplexity:         1000,
+			MaxQueryBatchSize:          10,
+			QueryBatchConcurrency:      4,
+			MaxLogLines:                1000,
+			MaxLogBytes:                1024 * 1024,
+			MaxEvents:                  100,
+			ReadHeaderTimeout:          32 * time.Second,
+			IdleTimeout:                90 * time.Second,
+			EndpointSuffix:             "/graphql",
+			PublicURL:                  "",
+			PathPrefix:                 "",
+			MirrorURL:                  "",
+			MirrorPercentage:           0,
+			ChangeFeedSize:             0,
+			ChangeFeedFile:             "",
+			ClusterRegistrationTTL:     0,
+			SavedQueriesFile:           "",
+			SmokeTestsFile:             "",
+			SmokeTestServiceAccount:    "",
+			SmokeTestTimeout:           30 * time.Second,
+			CanaryDuration:             0,
+			CanaryPercentage:           10,
+			CanaryMaxErrorIncrease:     0.05,
 		},
 	}
 	return opts
@@ -111,6 +244,8 @@ func (options *Options) AddFlags(fs *pflag.FlagSet) {
 	logsv1.AddFlags(options.Logs, fs)
 
 	fs.StringVar(&options.SchemasDir, "schemas-dir", options.SchemasDir, "directory to watch for schema files (used with --schema-handler=file)")
+	fs.DurationVar(&options.SchemaStalenessThreshold, "schema-staleness-threshold", options.SchemaStalenessThreshold, "age of schema files reported as stale in the metrics and on /statusz, longer than the listener's resync period (used with --schema-handler=file, 0 to disable)")
+	fs.StringVar(&options.ListenerHealthURL, "listener-health-url", options.ListenerHealthURL, "health endpoint of the listener, e.g. http://listener:8081/healthz, probed to tell a stopped listener from a stuck one (empty to disable)")
 	fs.StringVar(&options.SchemaHandler, "schema-handler", options.SchemaHandler, "how to receive schema updates: 'file' or 'grpc'")
 	fs.StringVar(&options.GRPCListenerAddress, "grpc-listener-address", options.GRPCListenerAddress, "address of the gRPC listener (used with --schema-handler=grpc)")
 	fs.IntVar(&options.GRPCMaxRecvMsgSize, "grpc-max-recv-msg-size", options.GRPCMaxRecvMsgSize, "maximum gRPC receive message size in bytes (used with --schema-handler=grpc)")
@@ -122,15 +257,57 @@ func (options *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&options.TokenReviewCacheTTL, "token-review-cache-ttl", options.TokenReviewCacheTTL, "TTL for cached TokenReview results (0 to disable caching)")
 	fs.DurationVar(&options.RequestTimeout, "request-timeout", options.RequestTimeout, "maximum duration for non-streaming GraphQL requests (0 to disable)")
 	fs.DurationVar(&options.SubscriptionTimeout, "subscription-timeout", options.SubscriptionTimeout, "maximum duration for SSE subscription connections (0 to disable)")
+	fs.DurationVar(&options.SubscriptionMaxLifetime, "subscription-max-lifetime", options.SubscriptionMaxLifetime, "maximum lifetime of an SSE subscription unless renewed by the client (0 to disable)")
+	fs.DurationVar(&options.SubscriptionExpiryWarning, "subscription-expiry-warning", options.SubscriptionExpiryWarning, "how long before --subscription-max-lifetime ends a subscription an \"expiring\" event is sent")
+	fs.DurationVar(&options.SSEKeepaliveInterval, "sse-keepalive-interval", options.SSEKeepaliveInterval, "how often a keepalive comment is sent to SSE clients so proxies do not close quiet subscriptions as idle (0 to disable)")
+	fs.DurationVar(&options.SSEWriteTimeout, "sse-write-timeout", options.SSEWriteTimeout, "maximum duration of writing an SSE event to a client before it is disconnected (0 to disable)")
+	fs.DurationVar(&options.LiveQueryInterval, "live-query-interval", options.LiveQueryInterval, "how often queries marked with @live are re-executed over SSE")
+	fs.StringSliceVar(&options.SubscriptionEventLimits, "subscription-event-limits", options.SubscriptionEventLimits, "minimum intervals between subscription events of an object of a kind as Kind.group=interval, e.g. Endpoints=1s; events within it are merged")
+	fs.BoolVar(&options.ExposeManagedFields, "expose-managed-fields", options.ExposeManagedFields, "include metadata.managedFields in the GraphQL schema")
+	fs.StringVar(&options.EmptyValues, "empty-values", options.EmptyValues, "how empty and absent list and map fields are returned: 'preserve' as stored, 'null' for both or 'empty' for both")
+	fs.StringVar(&options.FieldCasing, "field-casing", options.FieldCasing, "how object fields are named: 'preserve' keeps property names, 'camel' converts snake_case and kebab-case property names to camelCase")
+	fs.BoolVar(&options.Federation, "federation", options.Federation, "serve each cluster as an Apollo Federation subgraph with _service and _entities queries and @key directives on metadata name and namespace")
+	fs.StringVar(&options.KindAliasesFile, "kind-aliases-file", options.KindAliasesFile, "YAML file with renamed kinds served under their former group, version and kind with deprecated fields (empty to disable)")
+	fs.StringVar(&options.Introspection, "introspection", options.Introspection, "who may query __schema and __type: 'enabled' for everyone, 'authenticated' for requests with a valid token or 'disabled' for nobody")
+	fs.BoolVar(&options.RBACSchemaPruning, "rbac-schema-pruning", options.RBACSchemaPruning, "serve each user a schema without the kinds they may not list, according to a SelfSubjectRulesReview")
+	fs.StringVar(&options.RBACSchemaPruningNamespace, "rbac-schema-pruning-namespace", options.RBACSchemaPruningNamespace, "namespace the rules of users are reviewed in for --rbac-schema-pruning; cluster-wide permissions apply in every namespace")
+	fs.StringSliceVar(&options.HiddenMaturities, "hidden-maturities", options.HiddenMaturities, "maturities of kinds left out of the schema, e.g. alpha, as tagged with the gateway.platform-mesh.io/maturity annotation on their CRD (empty to serve all kinds)")
+	fs.StringSliceVar(&options.HiddenMaturitiesGroups, "hidden-maturities-groups", options.HiddenMaturitiesGroups, "groups whose members are served kinds of --hidden-maturities anyway, according to a SelfSubjectReview")
+	fs.StringVar(&options.OperationAllowlistDir, "operation-allowlist-dir", options.OperationAllowlistDir, "directory of .graphql documents, e.g. a mounted ConfigMap; only these documents may be executed (empty to allow all queries)")
+	fs.IntVar(&options.PersistedQueriesCacheSize, "persisted-queries-cache-size", options.PersistedQueriesCacheSize, "number of Automatic Persisted Queries kept, least recently used first evicted (0 to disable)")
 	fs.Int64Var(&options.MaxRequestBodyBytes, "max-request-body-bytes", options.MaxRequestBodyBytes, "maximum allowed request body size in bytes (0 to disable)")
 	fs.IntVar(&options.MaxInFlightRequests, "max-inflight-requests", options.MaxInFlightRequests, "maximum number of concurrent in-flight requests (0 to disable)")
 	fs.IntVar(&options.MaxInFlightSubscriptions, "max-inflight-subscriptions", options.MaxInFlightSubscriptions, "maximum number of concurrent in-flight SSE subscriptions (0 to disable)")
 	fs.IntVar(&options.MaxQueryDepth, "max-query-depth", options.MaxQueryDepth, "maximum allowed nesting depth for GraphQL queries (0 to disable)")
 	fs.IntVar(&options.MaxQueryComplexity, "max-query-complexity", options.MaxQueryComplexity, "maximum allowed complexity score for GraphQL queries (0 to disable)")
 	fs.IntVar(&options.MaxQueryBatchSize, "max-query-batch-size", options.MaxQueryBatchSize, "maximum number of queries allowed in a single batched request (0 to disable)")
+	fs.IntVar(&options.QueryBatchConcurrency, "query-batch-concurrency", options.QueryBatchConcurrency, "number of operations of a batched request executed at the same time")
+	fs.IntVar(&options.MaxLogLines, "max-log-lines", options.MaxLogLines, "maximum number of lines returned by a single podLogsPage query (0 to disable)")
+	fs.Int64Var(&options.MaxLogBytes, "max-log-bytes", options.MaxLogBytes, "maximum number of bytes of the lines of a podLogsPage page, cutting longer lines (0 to disable)")
+	fs.IntVar(&options.MaxEvents, "max-events", options.MaxEvents, "maximum number of events returned by a single events field (0 to disable)")
 	fs.DurationVar(&options.ReadHeaderTimeout, "read-header-timeout", options.ReadHeaderTimeout, "maximum duration for reading request headers (0 to disable)")
 	fs.DurationVar(&options.IdleTimeout, "idle-timeout", options.IdleTimeout, "maximum duration an idle keep-alive connection remains open (0 to disable)")
 	fs.StringVar(&options.EndpointSuffix, "endpoint-suffix", options.EndpointSuffix, "suffix appended to the cluster endpoint path (default \"/graphql\")")
+	fs.StringVar(&options.PublicURL, "public-url", options.PublicURL, "base URL clients reach the gateway at, including the path prefix of an ingress or HTTPRoute, used in discovery documents (empty to derive it from X-Forwarded-* headers)")
+	fs.StringVar(&options.PathPrefix, "path-prefix", options.PathPrefix, "path prefix the gateway is served under behind an ingress, stripped from requests that still carry it (empty for none)")
+	fs.StringVar(&options.MirrorURL, "mirror-url", options.MirrorURL, "base URL of a shadow gateway that receives a copy of read-only requests (empty to disable)")
+	fs.Float64Var(&options.MirrorPercentage, "mirror-percentage", options.MirrorPercentage, "percentage (0-100) of read-only requests mirrored to --mirror-url")
+	fs.IntVar(&options.ChangeFeedSize, "change-feed-size", options.ChangeFeedSize, "number of recent mutations exposed by the recentChanges query (0 to disable)")
+	fs.StringVar(&options.ChangeFeedFile, "change-feed-file", options.ChangeFeedFile, "file the change feed is persisted to across restarts (empty to keep it in memory only)")
+	fs.DurationVar(&options.ClusterRegistrationTTL, "cluster-registration-ttl", options.ClusterRegistrationTTL, "maximum time clusters registered with the registerCluster mutation are served before they are removed (0 to disable the mutation)")
+	fs.StringSliceVar(&options.ClusterRegistrationGroups, "cluster-registration-groups", options.ClusterRegistrationGroups, "groups whose members may register clusters, according to a SelfSubjectReview (required with --cluster-registration-ttl)")
+	fs.StringVar(&options.ClusterRegistrationHomeCluster, "cluster-registration-home-cluster", options.ClusterRegistrationHomeCluster, "cluster served from the listener's schemas whose API server reviews the groups of users registering clusters (required with --cluster-registration-ttl)")
+	fs.StringVar(&options.SavedQueriesFile, "saved-queries-file", options.SavedQueriesFile, "file storing the named queries users save with the saveQuery mutation (empty to disable saved queries)")
+	fs.StringVar(&options.SmokeTestsFile, "smoke-tests-file", options.SmokeTestsFile, "YAML file with queries run against each cluster after its schema was loaded (empty to disable)")
+	fs.StringVar(&options.SmokeTestServiceAccount, "smoke-test-service-account", options.SmokeTestServiceAccount, "namespace/name of the read-only ServiceAccount smoke tests run as (required with --smoke-tests-file)")
+	fs.DurationVar(&options.SmokeTestTimeout, "smoke-test-timeout", options.SmokeTestTimeout, "maximum duration of the smoke tests run after a schema load")
+	fs.DurationVar(&options.CanaryDuration, "canary-duration", options.CanaryDuration, "how long a new schema is served as a canary next to the current one before it is promoted (0 replaces schemas immediately)")
+	fs.Float64Var(&options.CanaryPercentage, "canary-percentage", options.CanaryPercentage, "percentage (0-100) of requests routed to a canary schema")
+	fs.Float64Var(&options.CanaryMaxErrorIncrease, "canary-max-error-increase", options.CanaryMaxErrorIncrease, "how much higher (0-1) the share of failed requests of a canary schema may be than that of the current schema for it to be promoted")
+	fs.IntVar(&options.GCPercent, "gc-percent", options.GCPercent, "heap growth in percent triggering a garbage collection, like GOGC (0 to keep GOGC, negative to only collect at --memory-limit-bytes)")
+	fs.Int64Var(&options.MemoryLimitBytes, "memory-limit-bytes", options.MemoryLimitBytes, "soft memory limit of the gateway in bytes, like GOMEMLIMIT (0 to keep GOMEMLIMIT)")
+	fs.Int64Var(&options.MemoryBallastBytes, "memory-ballast-bytes", options.MemoryBallastBytes, "size in bytes of a never used allocation delaying garbage collections while the heap is small (0 to disable)")
+	fs.BoolVar(&options.DebugMemStats, "debug-memstats", options.DebugMemStats, "serve the memory use and the schema sizes of the clusters on /debug/memstats")
 }
 
 func (options *Options) Complete() (*CompletedOptions, error) {
@@ -157,6 +334,21 @@ func (options *CompletedOptions) Validate() error {
 		return errors.New("--schemas-dir must be set when --schema-handler=file")
 	}
 
+	if options.PublicURL != "" {
+		u, err := url.Parse(options.PublicURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("--public-url must be an absolute http or https URL")
+		}
+	}
+
+	if options.PathPrefix != "" && !strings.HasPrefix(options.PathPrefix, "/") {
+		return errors.New("--path-prefix must start with /")
+	}
+
+	if options.SchemaStalenessThreshold < 0 {
+		return errors.New("--schema-staleness-threshold must not be negative")
+	}
+
 	if options.TokenReviewCacheTTL < 0 {
 		return errors.New("--token-review-cache-ttl must not be negative")
 	}
@@ -169,6 +361,60 @@ func (options *CompletedOptions) Validate() error {
 		return errors.New("--subscription-timeout must not be negative")
 	}
 
+	if options.SubscriptionMaxLifetime < 0 {
+		return errors.New("--subscription-max-lifetime must not be negative")
+	}
+
+	if options.SubscriptionExpiryWarning < 0 {
+		return errors.New("--subscription-expiry-warning must not be negative")
+	}
+
+	if options.SubscriptionMaxLifetime > 0 && options.SubscriptionExpiryWarning >= options.SubscriptionMaxLifetime {
+		return errors.New("--subscription-expiry-warning must be shorter than --subscription-max-lifetime")
+	}
+
+	if options.SSEKeepaliveInterval < 0 {
+		return errors.New("--sse-keepalive-interval must not be negative")
+	}
+
+	if options.SSEWriteTimeout < 0 {
+		return errors.New("--sse-write-timeout must not be negative")
+	}
+
+	if options.LiveQueryInterval <= 0 {
+		return errors.New("--live-query-interval must be positive")
+	}
+
+	if options.EmptyValues != "preserve" && options.EmptyValues != "null" && options.EmptyValues != "empty" {
+		return errors.New("--empty-values must be 'preserve', 'null' or 'empty'")
+	}
+
+	if options.FieldCasing != "preserve" && options.FieldCasing != "camel" {
+		return errors.New("--field-casing must be 'preserve' or 'camel'")
+	}
+
+	if options.Introspection != "enabled" && options.Introspection != "authenticated" && options.Introspection != "disabled" {
+		return errors.New("--introspection must be 'enabled', 'authenticated' or 'disabled'")
+	}
+
+	if options.RBACSchemaPruning && options.RBACSchemaPruningNamespace == "" {
+		return errors.New("--rbac-schema-pruning-namespace must not be empty with --rbac-schema-pruning")
+	}
+
+	for _, maturity := range options.HiddenMaturities {
+		if maturity != apis.MaturityAlpha && maturity != apis.MaturityBeta && maturity != apis.MaturityStable {
+			return errors.New("--hidden-maturities must only contain 'alpha', 'beta' or 'stable'")
+		}
+	}
+
+	if len(options.HiddenMaturitiesGroups) > 0 && len(options.HiddenMaturities) == 0 {
+		return errors.New("--hidden-maturities-groups requires --hidden-maturities")
+	}
+
+	if options.PersistedQueriesCacheSize < 0 {
+		return errors.New("--persisted-queries-cache-size must not be negative")
+	}
+
 	if options.MaxRequestBodyBytes < 0 {
 		return errors.New("--max-request-body-bytes must not be negative")
 	}
@@ -193,6 +439,22 @@ func (options *CompletedOptions) Validate() error {
 		return errors.New("--max-query-batch-size must not be negative")
 	}
 
+	if options.QueryBatchConcurrency <= 0 {
+		return errors.New("--query-batch-concurrency must be positive")
+	}
+
+	if options.MaxLogLines < 0 {
+		return errors.New("--max-log-lines must not be negative")
+	}
+
+	if options.MaxLogBytes < 0 {
+		return errors.New("--max-log-bytes must not be negative")
+	}
+
+	if options.MaxEvents < 0 {
+		return errors.New("--max-events must not be negative")
+	}
+
 	if options.ReadHeaderTimeout < 0 {
 		return errors.New("--read-header-timeout must not be negative")
 	}
@@ -201,5 +463,69 @@ func (options *CompletedOptions) Validate() error {
 		return errors.New("--idle-timeout must not be negative")
 	}
 
+	if options.ChangeFeedSize < 0 {
+		return errors.New("--change-feed-size must not be negative")
+	}
+
+	if options.ChangeFeedFile != "" && options.ChangeFeedSize == 0 {
+		return errors.New("--change-feed-file requires --change-feed-size")
+	}
+
+	if options.ClusterRegistrationTTL < 0 {
+		return errors.New("--cluster-registration-ttl must not be negative")
+	}
+
+	if options.ClusterRegistrationTTL > 0 && len(options.ClusterRegistrationGroups) == 0 {
+		return errors.New("--cluster-registration-groups must be set when --cluster-registration-ttl is set")
+	}
+
+	if options.ClusterRegistrationTTL > 0 && options.ClusterRegistrationHomeCluster == "" {
+		return errors.New("--cluster-registration-home-cluster must be set when --cluster-registration-ttl is set")
+	}
+
+	if options.SmokeTestsFile != "" {
+		namespace, name, ok := strings.Cut(options.SmokeTestServiceAccount, "/")
+		if !ok || namespace == "" || name == "" {
+			return errors.New("--smoke-test-service-account must be set to namespace/name when --smoke-tests-file is set")
+		}
+		if options.SmokeTestTimeout <= 0 {
+			return errors.New("--smoke-test-timeout must be positive")
+		}
+	}
+
+	if options.CanaryDuration < 0 {
+		return errors.New("--canary-duration must not be negative")
+	}
+
+	if options.CanaryPercentage < 0 || options.CanaryPercentage > 100 {
+		return errors.New("--canary-percentage must be between 0 and 100")
+	}
+
+	if options.CanaryMaxErrorIncrease < 0 || options.CanaryMaxErrorIncrease > 1 {
+		return errors.New("--canary-max-error-increase must be between 0 and 1")
+	}
+
+	if options.GCPercent < 0 && options.MemoryLimitBytes == 0 {
+		return errors.New("a negative --gc-percent requires --memory-limit-bytes")
+	}
+
+	if options.MemoryLimitBytes < 0 {
+		return errors.New("--memory-limit-bytes must not be negative")
+	}
+
+	if options.MemoryBallastBytes < 0 {
+		return errors.New("--memory-ballast-bytes must not be negative")
+	}
+
+	if options.MirrorPercentage < 0 || options.MirrorPercentage > 100 {
+		return errors.New("--mirror-percentage must be between 0 and 100")
+	}
+
+	if options.MirrorURL != "" {
+		if u, err := url.Parse(options.MirrorURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("--mirror-url must be an absolute URL")
+		}
+	}
+
 	return nil
 }
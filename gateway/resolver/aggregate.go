@@ -0,0 +1,147 @@
+package resolver
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const GroupByArg = "groupBy"
+
+var errGroupByNotMetadata = errors.New("groupBy must be a metadata field such as metadata.namespace or metadata.labels.team")
+
+// AggregateArgs returns arguments for aggregate queries
+func AggregateArgs(scope v1.ResourceScope) graphql.FieldConfigArgument {
+	args := CountArgs(scope)
+	args[GroupByArg] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "The metadata field to group by, e.g. metadata.namespace, metadata.labels.team or metadata.annotations.owner; everything after metadata.labels. is the label key",
+	}
+	return args
+}
+
+// AggregateItems returns a resolver counting the objects of a kind matching
+// the selectors per value of a metadata field. It lists only their metadata.
+// Buckets are ordered by count, largest first, then by value, and objects
+// without the field are counted in a bucket with a null value.
+func (r *Service) AggregateItems(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, "AggregateItems", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		logger := log.FromContext(p.Context).WithValues("operation", "aggregate", "kind", gvk.Kind)
+
+		groupBy, err := GetArg[string](p.Args, GroupByArg, true)
+		if err != nil {
+			return nil, err
+		}
+		value, err := metadataValue(groupBy)
+		if err != nil {
+			return nil, err
+		}
+
+		opts, err := metadataListOptions(p.Args, scope)
+		if err != nil {
+			logger.Error(err, "Unable to parse given selectors")
+			return nil, err
+		}
+
+		counts := map[string]int{}
+		missing := 0
+		err = r.listMetadata(ctx, gvk, opts, func(list *metav1.PartialObjectMetadataList) bool {
+			for _, item := range list.Items {
+				if v, ok := value(item.ObjectMeta); ok {
+					counts[v]++
+				} else {
+					missing++
+				}
+			}
+			return true
+		})
+		if err != nil {
+			logger.Error(err, "Unable to list objects")
+			return nil, err
+		}
+
+		buckets := make([]map[string]any, 0, len(counts)+1)
+		for v, count := range counts {
+			buckets = append(buckets, map[string]any{"value": v, "count": count})
+		}
+		if missing > 0 {
+			buckets = append(buckets, map[string]any{"value": nil, "count": missing})
+		}
+		slices.SortFunc(buckets, func(a, b map[string]any) int {
+			if c := cmp.Compare(b["count"].(int), a["count"].(int)); c != 0 {
+				return c
+			}
+			// The bucket without a value comes last among equal counts.
+			aValue, aOK := a["value"].(string)
+			bValue, bOK := b["value"].(string)
+			if aOK != bOK {
+				if aOK {
+					return -1
+				}
+				return 1
+			}
+			return cmp.Compare(aValue, bValue)
+		})
+		return buckets, nil
+	}
+}
+
+// metadataValue returns a function reading the value of the metadata field
+// at path as a string. Labels and annotations are read by key, so keys may
+// contain dots, and other fields by their dot-separated path.
+func metadataValue(path string) (func(metav1.ObjectMeta) (string, bool), error) {
+	field, ok := strings.CutPrefix(path, "metadata.")
+	if !ok || field == "" {
+		return nil, errGroupByNotMetadata
+	}
+
+	if key, ok := strings.CutPrefix(field, "labels."); ok && key != "" {
+		return func(meta metav1.ObjectMeta) (string, bool) {
+			v, ok := meta.Labels[key]
+			return v, ok
+		}, nil
+	}
+	if key, ok := strings.CutPrefix(field, "annotations."); ok && key != "" {
+		return func(meta metav1.ObjectMeta) (string, bool) {
+			v, ok := meta.Annotations[key]
+			return v, ok
+		}, nil
+	}
+	if field == "labels" || field == "annotations" {
+		return nil, fmt.Errorf("groupBy %s requires a key, e.g. %s.team", path, path)
+	}
+
+	segments := strings.Split(field, ".")
+	return func(meta metav1.ObjectMeta) (string, bool) {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&meta)
+		if err != nil {
+			return "", false
+		}
+		v, found, err := unstructured.NestedFieldNoCopy(obj, segments...)
+		if err != nil || !found {
+			return "", false
+		}
+		switch v.(type) {
+		case map[string]any, []any:
+			return "", false
+		}
+		return fmt.Sprint(v), true
+	}, nil
+}
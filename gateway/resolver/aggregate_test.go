@@ -0,0 +1,90 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestAggregateItems(t *testing.T) {
+	object := func(namespace string, labels map[string]string) metav1.PartialObjectMetadata {
+		return metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Labels: labels}}
+	}
+	fc := &fakeClient{
+		listFn: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+			listOpts := &client.ListOptions{}
+			listOpts.ApplyOptions(opts)
+
+			metadataList := list.(*metav1.PartialObjectMetadataList)
+			if listOpts.Continue == "" {
+				metadataList.Items = []metav1.PartialObjectMetadata{
+					object("team-a", map[string]string{"team": "web", "app.kubernetes.io/name": "shop"}),
+					object("team-a", map[string]string{"team": "api"}),
+				}
+				metadataList.Continue = "next"
+				return nil
+			}
+			metadataList.Items = []metav1.PartialObjectMetadata{
+				object("team-b", map[string]string{"team": "web"}),
+				object("team-b", nil),
+			}
+			return nil
+		},
+	}
+	aggregate := (&Service{runtimeClient: fc}).AggregateItems(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, v1.NamespaceScoped)
+
+	tests := []struct {
+		name    string
+		groupBy string
+		want    []map[string]any
+		wantErr string
+	}{
+		{
+			name:    "label",
+			groupBy: "metadata.labels.team",
+			want: []map[string]any{
+				{"value": "web", "count": 2},
+				{"value": "api", "count": 1},
+				{"value": nil, "count": 1},
+			},
+		},
+		{
+			name:    "label key with dots",
+			groupBy: "metadata.labels.app.kubernetes.io/name",
+			want: []map[string]any{
+				{"value": nil, "count": 3},
+				{"value": "shop", "count": 1},
+			},
+		},
+		{
+			name:    "metadata field",
+			groupBy: "metadata.namespace",
+			want: []map[string]any{
+				{"value": "team-a", "count": 2},
+				{"value": "team-b", "count": 2},
+			},
+		},
+		{name: "not metadata", groupBy: "spec.replicas", wantErr: "groupBy must be a metadata field"},
+		{name: "labels without key", groupBy: "metadata.labels", wantErr: "requires a key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets, err := aggregate(graphql.ResolveParams{Context: t.Context(), Args: map[string]any{GroupByArg: tt.groupBy}})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, buckets)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package resolver
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// KindAlias serves a kind that was renamed or moved to another group or
+// version under its former name as well, so that existing queries keep
+// working while clients migrate.
+type KindAlias struct {
+	// From is the former group, version and kind the fields are named after.
+	From schema.GroupVersionKind
+	// To is the kind the fields read and write.
+	To schema.GroupVersionKind
+}
+
+// WithKindAliases sets the kinds served under a former name.
+func (r *Service) WithKindAliases(aliases []KindAlias) *Service {
+	r.kindAliases = aliases
+	return r
+}
+
+// KindAliases returns the kinds served under a former name.
+func (r *Service) KindAliases() []KindAlias {
+	return r.kindAliases
+}
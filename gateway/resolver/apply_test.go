@@ -0,0 +1,103 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestApplyItem(t *testing.T) {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	object := func() map[string]any {
+		return map[string]any{
+			"metadata": map[string]any{"name": "settings"},
+			"data":     map[string]any{"mode": "fast"},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		args             map[string]any
+		wantFieldManager string
+		wantForce        bool
+		wantDryRun       []string
+		wantErr          string
+	}{
+		{
+			name:             "default field manager",
+			args:             map[string]any{NamespaceArg: "default", ObjectArg: object()},
+			wantFieldManager: DefaultFieldManager,
+		},
+		{
+			name:             "forced apply of a named field manager",
+			args:             map[string]any{NamespaceArg: "default", ObjectArg: object(), FieldManagerArg: "argocd", ForceArg: true},
+			wantFieldManager: "argocd",
+			wantForce:        true,
+		},
+		{
+			name:             "dry run",
+			args:             map[string]any{NamespaceArg: "default", ObjectArg: object(), DryRunArg: true},
+			wantFieldManager: DefaultFieldManager,
+			wantDryRun:       []string{"All"},
+		},
+		{
+			name:    "missing name",
+			args:    map[string]any{NamespaceArg: "default", ObjectArg: map[string]any{"data": map[string]any{}}},
+			wantErr: "object metadata.name is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				calls   int
+				patched client.Object
+				data    string
+				opts    client.PatchOptions
+			)
+			cl := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, patch client.Patch, patchOpts ...client.PatchOption) error {
+					calls++
+					patched = obj
+					raw, err := patch.Data(obj)
+					require.NoError(t, err)
+					data = string(raw)
+					assert.Equal(t, types.ApplyPatchType, patch.Type())
+					opts.ApplyOptions(patchOpts)
+					return nil
+				},
+			}).Build()
+
+			_, err := New(cl).ApplyItem(gvk, v1.NamespaceScoped)(graphql.ResolveParams{Context: t.Context(), Args: tt.args})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Zero(t, calls)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, 1, calls)
+			assert.Equal(t, client.ObjectKey{Namespace: "default", Name: "settings"}, client.ObjectKeyFromObject(patched))
+			assert.JSONEq(t, `{
+				"apiVersion": "v1",
+				"kind": "ConfigMap",
+				"metadata": {"name": "settings", "namespace": "default"},
+				"data": {"mode": "fast"}
+			}`, data)
+			assert.Equal(t, tt.wantFieldManager, opts.FieldManager)
+			require.NotNil(t, opts.Force)
+			assert.Equal(t, tt.wantForce, *opts.Force)
+			assert.Equal(t, tt.wantDryRun, opts.DryRun)
+		})
+	}
+}
@@ -1,11 +1,15 @@
 package resolver
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 
 	"github.com/graphql-go/graphql"
+	schematypes "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -13,17 +17,33 @@ import (
 
 // Argument name constants
 const (
-	LabelSelectorArg   = "labelselector"
-	NameArg            = "name"
-	NamespaceArg       = "namespace"
-	ObjectArg          = "object"
-	SubscribeToAllArg  = "subscribeToAll"
-	SortByArg          = "sortBy"
-	DryRunArg          = "dryRun"
-	ResourceVersionArg = "resourceVersion"
-	LimitArg           = "limit"
-	ContinueArg        = "continue"
-	YamlArg            = "yaml"
+	LabelSelectorArg      = "labelselector"
+	LabelSelectorInputArg = "labelSelectorInput"
+	FieldSelectorArg      = "fieldSelector"
+	NameArg               = "name"
+	NamespaceArg          = "namespace"
+	ObjectArg             = "object"
+	SubscribeToAllArg     = "subscribeToAll"
+	CoalesceMsArg         = "coalesceMs"
+	BookmarksArg          = "bookmarks"
+	EmitInitialStateArg   = "emitInitialState"
+	SortByArg             = "sortBy"
+	DryRunArg             = "dryRun"
+	ResourceVersionArg    = "resourceVersion"
+	LimitArg              = "limit"
+	ContinueArg           = "continue"
+	YamlArg               = "yaml"
+	GroupByNamespaceArg   = "groupByNamespace"
+	ClusterArg            = "cluster"
+	KindsArg              = "kinds"
+	StatusArg             = "status"
+	ReplicasArg           = "replicas"
+	FieldManagerArg       = "fieldManager"
+	ForceArg              = "force"
+	GracePeriodSecondsArg = "gracePeriodSeconds"
+	AtResourceVersionArg  = "atResourceVersion"
+	APIVersionArg         = "apiVersion"
+	AllNamespacesArg      = "allNamespaces"
 )
 
 var (
@@ -42,6 +62,16 @@ var (
 		Description: "A label selector to filter the objects by",
 	}
 
+	LabelSelectorInputArgConfig = &graphql.ArgumentConfig{
+		Type:        LabelSelectorInput,
+		Description: "A structured label selector to filter the objects by, combined with labelselector if both are set",
+	}
+
+	FieldSelectorArgConfig = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "A field selector to filter the objects by (e.g. status.phase=Running)",
+	}
+
 	DryRunArgConfig = &graphql.ArgumentConfig{
 		Type:        graphql.Boolean,
 		Description: "If true, the operation will be performed in dry-run mode",
@@ -53,11 +83,32 @@ var (
 		Description:  "If true, events will be emitted on every field change",
 	}
 
+	CoalesceMsArgConfig = &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "If set, changes of an object within this many milliseconds are emitted once with its latest state, counting the left out events in suppressed",
+	}
+
+	BookmarksArgConfig = &graphql.ArgumentConfig{
+		Type:         graphql.Boolean,
+		DefaultValue: false,
+		Description:  "If true, BOOKMARK events carrying only the latest resourceVersion are emitted when the API server sends them, to resume from a recent point without changes",
+	}
+
+	EmitInitialStateArgConfig = &graphql.ArgumentConfig{
+		Type:        graphql.Boolean,
+		Description: "If true, the current objects are emitted as ADDED events before the changes, if false only changes are emitted. Defaults to true without resourceVersion",
+	}
+
 	ResourceVersionArgConfig = &graphql.ArgumentConfig{
 		Type:        graphql.String,
 		Description: "If set, subscription will stream changes starting from this resourceVersion. If omitted will return all",
 	}
 
+	AtResourceVersionArgConfig = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "If set, the objects are read as they were at exactly this resourceVersion (resourceVersionMatch=Exact). Fails once the API server has compacted it",
+	}
+
 	SortByArgConfig = &graphql.ArgumentConfig{
 		Type:         graphql.String,
 		Description:  "The field to sort the results by",
@@ -74,6 +125,24 @@ var (
 		Description: "Continue token from a previous list call to retrieve the next page",
 	}
 
+	GroupByNamespaceArgConfig = &graphql.ArgumentConfig{
+		Type:         graphql.Boolean,
+		DefaultValue: false,
+		Description:  "If true, the items are additionally returned grouped by namespace in byNamespace",
+	}
+
+	FieldManagerArgConfig = &graphql.ArgumentConfig{
+		Type:         graphql.NewNonNull(graphql.String),
+		DefaultValue: DefaultFieldManager,
+		Description:  "The manager that owns the applied fields",
+	}
+
+	ForceArgConfig = &graphql.ArgumentConfig{
+		Type:         graphql.Boolean,
+		DefaultValue: false,
+		Description:  "If true, fields owned by other managers are taken over instead of failing with a conflict",
+	}
+
 	YamlArgConfig = &graphql.ArgumentConfig{
 		Type:        graphql.NewNonNull(graphql.String),
 		Description: "YAML manifest to apply (single document only)",
@@ -94,10 +163,12 @@ func ItemArgs(scope apiextensionsv1.ResourceScope) graphql.FieldConfigArgument {
 // ListArgs returns arguments for list queries
 func ListArgs(scope apiextensionsv1.ResourceScope) graphql.FieldConfigArgument {
 	args := graphql.FieldConfigArgument{
-		LabelSelectorArg: LabelSelectorArgConfig,
-		SortByArg:        SortByArgConfig,
-		LimitArg:         LimitArgConfig,
-		ContinueArg:      ContinueArgConfig,
+		LabelSelectorArg:      LabelSelectorArgConfig,
+		LabelSelectorInputArg: LabelSelectorInputArgConfig,
+		FieldSelectorArg:      FieldSelectorArgConfig,
+		SortByArg:             SortByArgConfig,
+		LimitArg:              LimitArgConfig,
+		ContinueArg:           ContinueArgConfig,
 	}
 	if isResourceNamespaceScoped(scope) {
 		args[NamespaceArg] = NamespaceArgConfig
@@ -110,6 +181,9 @@ func SubscriptionItemArgs(scope apiextensionsv1.ResourceScope) graphql.FieldConf
 	args := ItemArgs(scope)
 	args[SubscribeToAllArg] = SubscribeToAllArgConfig
 	args[ResourceVersionArg] = ResourceVersionArgConfig
+	args[CoalesceMsArg] = CoalesceMsArgConfig
+	args[BookmarksArg] = BookmarksArgConfig
+	args[EmitInitialStateArg] = EmitInitialStateArgConfig
 	return args
 }
 
@@ -118,6 +192,9 @@ func SubscriptionListArgs(scope apiextensionsv1.ResourceScope) graphql.FieldConf
 	args := ListArgs(scope)
 	args[SubscribeToAllArg] = SubscribeToAllArgConfig
 	args[ResourceVersionArg] = ResourceVersionArgConfig
+	args[CoalesceMsArg] = CoalesceMsArgConfig
+	args[BookmarksArg] = BookmarksArgConfig
+	args[EmitInitialStateArg] = EmitInitialStateArgConfig
 	return args
 }
 
@@ -140,6 +217,38 @@ func CreateArgs(scope apiextensionsv1.ResourceScope, inputType *graphql.InputObj
 func UpdateArgs(scope apiextensionsv1.ResourceScope, inputType *graphql.InputObject) graphql.FieldConfigArgument {
 	args := CreateArgs(scope, inputType)
 	args[NameArg] = NameArgConfig
+	args[ResourceVersionArg] = UpdateResourceVersionArgConfig
+	return args
+}
+
+// UpdateStatusArgs returns arguments for status update mutations
+func UpdateStatusArgs(scope apiextensionsv1.ResourceScope, statusType graphql.Input) graphql.FieldConfigArgument {
+	args := ItemArgs(scope)
+	args[StatusArg] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(statusType),
+		Description: "The status fields to update",
+	}
+	args[DryRunArg] = DryRunArgConfig
+	return args
+}
+
+// ApplyArgs returns arguments for server-side apply mutations
+func ApplyArgs(scope apiextensionsv1.ResourceScope, inputType *graphql.InputObject) graphql.FieldConfigArgument {
+	args := CreateArgs(scope, inputType)
+	args[ObjectArg].Description = "The fully specified intent of the field manager"
+	args[FieldManagerArg] = FieldManagerArgConfig
+	args[ForceArg] = ForceArgConfig
+	return args
+}
+
+// ScaleArgs returns arguments for scale mutations
+func ScaleArgs(scope apiextensionsv1.ResourceScope) graphql.FieldConfigArgument {
+	args := ItemArgs(scope)
+	args[ReplicasArg] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(graphql.Int),
+		Description: "The desired number of replicas",
+	}
+	args[DryRunArg] = DryRunArgConfig
 	return args
 }
 
@@ -147,6 +256,38 @@ func UpdateArgs(scope apiextensionsv1.ResourceScope, inputType *graphql.InputObj
 func DeleteArgs(scope apiextensionsv1.ResourceScope) graphql.FieldConfigArgument {
 	args := ItemArgs(scope)
 	args[DryRunArg] = DryRunArgConfig
+	args[PropagationPolicyArg] = PropagationPolicyArgConfig
+	args[GracePeriodSecondsArg] = DeleteGracePeriodSecondsArgConfig
+	args[PreconditionsArg] = PreconditionsArgConfig
+	return args
+}
+
+// EvictArgs returns arguments for eviction mutations
+func EvictArgs(scope apiextensionsv1.ResourceScope) graphql.FieldConfigArgument {
+	args := ItemArgs(scope)
+	args[GracePeriodSecondsArg] = &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "Seconds the pod is given to terminate gracefully; defaults to the pod's terminationGracePeriodSeconds",
+	}
+	args[DryRunArg] = DryRunArgConfig
+	return args
+}
+
+// DeleteAllOfArgs returns arguments for deleteAll mutations. Collections are
+// deleted per namespace, so the namespace is required for namespaced resources.
+func DeleteAllOfArgs(scope apiextensionsv1.ResourceScope) graphql.FieldConfigArgument {
+	args := graphql.FieldConfigArgument{
+		LabelSelectorArg:      LabelSelectorArgConfig,
+		LabelSelectorInputArg: LabelSelectorInputArgConfig,
+		FieldSelectorArg:      FieldSelectorArgConfig,
+		DryRunArg:             DryRunArgConfig,
+	}
+	if isResourceNamespaceScoped(scope) {
+		args[NamespaceArg] = &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The namespace in which to delete the objects",
+		}
+	}
 	return args
 }
 
@@ -159,7 +300,7 @@ func ApplyYamlArgs() graphql.FieldConfigArgument {
 
 // Extractable defines types that can be extracted from GraphQL arguments
 type Extractable interface {
-	string | bool | int
+	string | bool | int | []any
 }
 
 // ListResult represents the response structure for list queries.
@@ -168,6 +309,14 @@ type ListResult struct {
 	Items              []map[string]any `json:"items"`
 	Continue           string           `json:"continue"`
 	RemainingItemCount *int64           `json:"remainingItemCount"`
+	ByNamespace        []NamespaceGroup `json:"byNamespace"`
+	AllNamespaces      bool             `json:"allNamespaces"`
+}
+
+// NamespaceGroup holds the items of a list result that belong to one namespace.
+type NamespaceGroup struct {
+	Namespace string           `json:"namespace"`
+	Items     []map[string]any `json:"items"`
 }
 
 // ListResultFields returns GraphQL field definitions for ListResult.
@@ -181,9 +330,17 @@ func ListResultFields(resourceType *graphql.Object) graphql.Fields {
 	}
 }
 
+// NamespaceGroupFields returns GraphQL field definitions for NamespaceGroup.
+func NamespaceGroupFields(resourceType *graphql.Object) graphql.Fields {
+	return graphql.Fields{
+		"namespace": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"items":     &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(resourceType)))},
+	}
+}
+
 // GetArg extracts a typed argument from the args map.
 // Returns the zero value if the argument is not present and not required.
-// Returns an error if required argument is missing or has wrong type.
+// Returns an error if required argument is missing or cannot be coerced to T.
 func GetArg[T Extractable](args map[string]any, key string, required bool) (T, error) {
 	var zero T
 
@@ -195,10 +352,11 @@ func GetArg[T Extractable](args map[string]any, key string, required bool) (T, e
 		return zero, nil
 	}
 
-	typedVal, ok := val.(T)
-	if !ok {
-		return zero, fmt.Errorf("invalid type for argument: %s", key)
+	coerced, err := coerceArg(val, zero)
+	if err != nil {
+		return zero, fmt.Errorf("invalid type for argument: %s: %w", key, err)
 	}
+	typedVal := coerced.(T)
 
 	// For strings, check empty value when required
 	if required {
@@ -210,6 +368,146 @@ func GetArg[T Extractable](args map[string]any, key string, required bool) (T, e
 	return typedVal, nil
 }
 
+// coerceArg converts an argument value to the type of target. Besides values
+// of the target type, booleans and integers are accepted as strings ("true",
+// "42") and integers as integral floats, the way JSON decoders deliver them.
+func coerceArg(val any, target any) (any, error) {
+	switch target.(type) {
+	case bool:
+		switch v := val.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("expected Boolean, got %q", v)
+			}
+			return b, nil
+		}
+		return nil, fmt.Errorf("expected Boolean, got %T", val)
+	case int:
+		switch v := val.(type) {
+		case int:
+			return v, nil
+		case int32:
+			return int(v), nil
+		case int64:
+			return intFromInt64(v)
+		case float64:
+			if v != math.Trunc(v) {
+				return nil, fmt.Errorf("expected Int, got %v", v)
+			}
+			if v < math.MinInt64 || v >= math.MaxInt64 {
+				return nil, fmt.Errorf("expected Int, %v is out of range", v)
+			}
+			return intFromInt64(int64(v))
+		case json.Number:
+			i, err := v.Int64()
+			if err != nil {
+				return nil, fmt.Errorf("expected Int, got %q", v)
+			}
+			return intFromInt64(i)
+		case string:
+			i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("expected Int, got %q", v)
+			}
+			return intFromInt64(i)
+		}
+		return nil, fmt.Errorf("expected Int, got %T", val)
+	case string:
+		if v, ok := val.(string); ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("expected String, got %T", val)
+	case []any:
+		if v, ok := val.([]any); ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("expected List, got %T", val)
+	}
+	return nil, fmt.Errorf("unsupported argument type %T", target)
+}
+
+func intFromInt64(v int64) (any, error) {
+	if v < math.MinInt || v > math.MaxInt {
+		return nil, fmt.Errorf("expected Int, %d is out of range", v)
+	}
+	return int(v), nil
+}
+
+// GetObjectArg extracts an input object argument from the args map.
+func GetObjectArg(args map[string]any, key string) (map[string]any, error) {
+	val, exists := args[key]
+	if !exists || val == nil {
+		return nil, fmt.Errorf("missing required argument: %s", key)
+	}
+	obj, ok := val.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for argument: %s: expected object, got %T", key, val)
+	}
+	return obj, nil
+}
+
+// getObjectInput extracts an input object argument of the field being
+// resolved, converted back to the object Kubernetes expects.
+func (r *Service) getObjectInput(p graphql.ResolveParams, key string) (map[string]any, error) {
+	obj, err := GetObjectArg(p.Args, key)
+	if err != nil {
+		return nil, err
+	}
+	converted, _ := schematypes.InputToObject(obj, argumentType(p, key), r.fieldNames).(map[string]any)
+	return converted, nil
+}
+
+// argumentType returns the type of an argument of the field being resolved,
+// or nil if it is not known.
+func argumentType(p graphql.ResolveParams, name string) graphql.Input {
+	parent, ok := p.Info.ParentType.(*graphql.Object)
+	if !ok {
+		return nil
+	}
+	field, ok := parent.Fields()[p.Info.FieldName]
+	if !ok {
+		return nil
+	}
+	for _, arg := range field.Args {
+		if arg.Name() == name {
+			return arg.Type
+		}
+	}
+	return nil
+}
+
+// GetStringListArg extracts a list of strings from the args map. A single
+// string is accepted as a list of one, as GraphQL input coercion does.
+func GetStringListArg(args map[string]any, key string) ([]string, error) {
+	val, exists := args[key]
+	if !exists || val == nil {
+		return nil, nil
+	}
+
+	var values []any
+	switch v := val.(type) {
+	case []any:
+		values = v
+	case []string:
+		return v, nil
+	default:
+		values = []any{v}
+	}
+
+	result := make([]string, 0, len(values))
+	for i, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid type for argument: %s[%d]: expected String, got %T", key, i, value)
+		}
+		result = append(result, str)
+	}
+	return result, nil
+}
+
 func isResourceNamespaceScoped(resourceScope apiextensionsv1.ResourceScope) bool {
 	return resourceScope == apiextensionsv1.NamespaceScoped
 }
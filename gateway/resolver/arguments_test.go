@@ -1,11 +1,13 @@
 package resolver_test
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetStrArg(t *testing.T) {
@@ -19,7 +21,7 @@ func TestGetStrArg(t *testing.T) {
 			args: map[string]any{
 				"arg1": false,
 			},
-			error: errors.New("invalid type for argument: arg1"),
+			error: errors.New("invalid type for argument: arg1: expected String, got bool"),
 		},
 		{
 			name: "empty_value_ERROR",
@@ -56,7 +58,7 @@ func TestGetBoolArg(t *testing.T) {
 			args: map[string]any{
 				"arg1": "MUST_BE_BOOL",
 			},
-			error: errors.New("invalid type for argument: arg1"),
+			error: errors.New(`invalid type for argument: arg1: expected Boolean, got "MUST_BE_BOOL"`),
 		},
 	}
 
@@ -69,3 +71,126 @@ func TestGetBoolArg(t *testing.T) {
 		})
 	}
 }
+
+func TestGetArg_Coercion(t *testing.T) {
+	t.Run("bool", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			value   any
+			want    bool
+			wantErr string
+		}{
+			{name: "bool", value: true, want: true},
+			{name: "string true", value: "true", want: true},
+			{name: "string False", value: "False", want: false},
+			{name: "string 1", value: "1", want: true},
+			{name: "padded string", value: " false ", want: false},
+			{name: "unparsable string", value: "yes", wantErr: `invalid type for argument: arg1: expected Boolean, got "yes"`},
+			{name: "number", value: 1, wantErr: "invalid type for argument: arg1: expected Boolean, got int"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := resolver.GetArg[bool](map[string]any{"arg1": tt.value}, "arg1", false)
+				if tt.wantErr != "" {
+					assert.EqualError(t, err, tt.wantErr)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			})
+		}
+	})
+
+	t.Run("int", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			value   any
+			want    int
+			wantErr string
+		}{
+			{name: "int", value: 5, want: 5},
+			{name: "int64", value: int64(5), want: 5},
+			{name: "integral float", value: float64(5), want: 5},
+			{name: "json number", value: json.Number("5"), want: 5},
+			{name: "string", value: "-5", want: -5},
+			{name: "fractional float", value: 5.5, wantErr: "invalid type for argument: arg1: expected Int, got 5.5"},
+			{name: "fractional json number", value: json.Number("5.5"), wantErr: `invalid type for argument: arg1: expected Int, got "5.5"`},
+			{name: "unparsable string", value: "five", wantErr: `invalid type for argument: arg1: expected Int, got "five"`},
+			{name: "bool", value: true, wantErr: "invalid type for argument: arg1: expected Int, got bool"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := resolver.GetArg[int](map[string]any{"arg1": tt.value}, "arg1", false)
+				if tt.wantErr != "" {
+					assert.EqualError(t, err, tt.wantErr)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			})
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		got, err := resolver.GetArg[[]any](map[string]any{"arg1": []any{"a", 1}}, "arg1", true)
+		require.NoError(t, err)
+		assert.Equal(t, []any{"a", 1}, got)
+
+		_, err = resolver.GetArg[[]any](map[string]any{"arg1": "a"}, "arg1", true)
+		assert.EqualError(t, err, "invalid type for argument: arg1: expected List, got string")
+	})
+
+	t.Run("missing optional argument", func(t *testing.T) {
+		got, err := resolver.GetArg[int](map[string]any{"arg1": nil}, "arg1", false)
+		require.NoError(t, err)
+		assert.Zero(t, got)
+	})
+}
+
+func TestGetObjectArg(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]any
+		wantErr string
+	}{
+		{name: "object", args: map[string]any{"object": map[string]any{"a": 1}}},
+		{name: "missing", args: map[string]any{}, wantErr: "missing required argument: object"},
+		{name: "not an object", args: map[string]any{"object": "{}"}, wantErr: "invalid type for argument: object: expected object, got string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.GetObjectArg(tt.args, "object")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.args["object"], got)
+		})
+	}
+}
+
+func TestGetStringListArg(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    []string
+		wantErr string
+	}{
+		{name: "list", value: []any{"a", "b"}, want: []string{"a", "b"}},
+		{name: "single value", value: "a", want: []string{"a"}},
+		{name: "missing", value: nil},
+		{name: "non-string element", value: []any{"a", 1}, wantErr: "invalid type for argument: kinds[1]: expected String, got int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.GetStringListArg(map[string]any{"kinds": tt.value}, "kinds")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package resolver
+
+import (
+	schematypes "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+)
+
+// WithFieldNames sets how the fields of object types are named. Input
+// objects are converted back to the property names recorded in names when
+// the schema is generated.
+func (r *Service) WithFieldNames(names *schematypes.FieldNames) *Service {
+	r.fieldNames = names
+	return r
+}
+
+// FieldNames returns how the fields of object types are named, nil if they
+// are named like their properties.
+func (r *Service) FieldNames() *schematypes.FieldNames {
+	return r.fieldNames
+}
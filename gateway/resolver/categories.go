@@ -0,0 +1,130 @@
+package resolver
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+)
+
+// CategoryFeed holds the kinds of each category of the schema served for a
+// cluster and notifies subscribers when a newly served schema changes them.
+// It outlives the endpoints of the cluster, so subscriptions on an endpoint
+// learn about the kinds of the schemas replacing it.
+type CategoryFeed struct {
+	mu          sync.Mutex
+	categories  map[string][]TypeByCategory
+	subscribers map[chan struct{}]struct{}
+}
+
+// NewCategoryFeed creates a feed without categories.
+func NewCategoryFeed() *CategoryFeed {
+	return &CategoryFeed{subscribers: map[chan struct{}]struct{}{}}
+}
+
+// Publish replaces the categories and notifies the subscribers.
+func (f *CategoryFeed) Publish(categories map[string][]TypeByCategory) {
+	sorted := make(map[string][]TypeByCategory, len(categories))
+	for name, types := range categories {
+		sorted[name] = slices.SortedFunc(slices.Values(types), compareTypes)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.categories = sorted
+	for ch := range f.subscribers {
+		// A pending notification already makes the subscriber read the
+		// latest categories.
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Types returns the kinds of a category, sorted by group, version and kind.
+func (f *CategoryFeed) Types(name string) []TypeByCategory {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.categories[name]
+}
+
+// subscribe returns a channel notified after each Publish and a function
+// ending the subscription.
+func (f *CategoryFeed) subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.subscribers, ch)
+	}
+}
+
+func compareTypes(a, b TypeByCategory) int {
+	return cmp.Or(
+		cmp.Compare(a.Group, b.Group),
+		cmp.Compare(a.Version, b.Version),
+		cmp.Compare(a.Kind, b.Kind),
+	)
+}
+
+// WithCategoryFeed serves the typeByCategory subscription from feed.
+func (r *Service) WithCategoryFeed(feed *CategoryFeed) *Service {
+	r.categories = feed
+	return r
+}
+
+// CategoryFeed returns the feed of the typeByCategory subscription, or nil if
+// disabled.
+func (r *Service) CategoryFeed() *CategoryFeed {
+	return r.categories
+}
+
+// SubscribeTypeByCategory returns a subscription resolver emitting the kinds
+// of a category, first the current ones and then whenever a newly served
+// schema changes them.
+func (r *Service) SubscribeTypeByCategory() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		name, err := GetArg[string](p.Args, NameArg, true)
+		if err != nil {
+			return nil, err
+		}
+
+		changed, unsubscribe := r.categories.subscribe()
+		resultChannel := make(chan any)
+		go func() {
+			defer close(resultChannel)
+			defer unsubscribe()
+
+			var sent []TypeByCategory
+			for first := true; ; first = false {
+				types := r.categories.Types(name)
+				if first || !slices.Equal(types, sent) {
+					// Never send nil, the field is a non-null list.
+					result := append([]TypeByCategory{}, types...)
+					select {
+					case <-p.Context.Done():
+						return
+					case resultChannel <- result:
+					}
+					sent = types
+				}
+
+				select {
+				case <-p.Context.Done():
+					return
+				case <-changed:
+				}
+			}
+		}()
+
+		return resultChannel, nil
+	}
+}
@@ -0,0 +1,72 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeTypeByCategory(t *testing.T) {
+	account := TypeByCategory{Group: "core.platform-mesh.io", Version: "v1alpha1", Kind: "Account", Scope: "Cluster"}
+	widget := TypeByCategory{Group: "example.com", Version: "v1", Kind: "Widget", Scope: "Namespaced"}
+	pod := TypeByCategory{Version: "v1", Kind: "Pod", Scope: "Namespaced"}
+
+	feed := NewCategoryFeed()
+	feed.Publish(map[string][]TypeByCategory{"ui": {account}, "all": {pod}})
+	r := New(nil).WithCategoryFeed(feed)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	result, err := r.SubscribeTypeByCategory()(graphql.ResolveParams{
+		Context: ctx,
+		Args:    map[string]any{NameArg: "ui"},
+	})
+	require.NoError(t, err)
+	ch := result.(chan any)
+
+	next := func() any {
+		t.Helper()
+		select {
+		case v := <-ch:
+			return v
+		case <-time.After(time.Second):
+			t.Fatal("no event")
+			return nil
+		}
+	}
+
+	assert.Equal(t, []TypeByCategory{account}, next())
+
+	// Schema updates leaving the category as it is emit nothing.
+	feed.Publish(map[string][]TypeByCategory{"ui": {account}, "all": {pod, account}})
+
+	feed.Publish(map[string][]TypeByCategory{"ui": {widget, account}})
+	assert.Equal(t, []TypeByCategory{account, widget}, next())
+
+	feed.Publish(nil)
+	assert.Equal(t, []TypeByCategory{}, next())
+
+	cancel()
+	select {
+	case _, open := <-ch:
+		assert.False(t, open)
+	case <-time.After(time.Second):
+		t.Fatal("subscription did not end")
+	}
+}
+
+func TestSubscribeTypeByCategory_UnknownCategory(t *testing.T) {
+	r := New(nil).WithCategoryFeed(NewCategoryFeed())
+
+	result, err := r.SubscribeTypeByCategory()(graphql.ResolveParams{
+		Context: t.Context(),
+		Args:    map[string]any{NameArg: "ui"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []TypeByCategory{}, <-result.(chan any))
+}
@@ -0,0 +1,112 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/changefeed"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const defaultRecentChangesLimit = 50
+
+// RecentChangesArgs returns the arguments of the recentChanges query.
+func RecentChangesArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		ClusterArg: &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Cluster to return changes for. Defaults to, and must match, the cluster of this endpoint",
+		},
+		KindsArg: &graphql.ArgumentConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "Only return changes of these kinds (case-insensitive)",
+		},
+		LimitArg: &graphql.ArgumentConfig{
+			Type:         graphql.Int,
+			DefaultValue: defaultRecentChangesLimit,
+			Description:  "Maximum number of changes to return, newest first",
+		},
+	}
+}
+
+// WithChangeFeed records mutations executed by the service in feed and
+// enables the recentChanges query.
+func (r *Service) WithChangeFeed(feed *changefeed.Feed) *Service {
+	r.changes = feed
+	return r
+}
+
+// ChangeFeed returns the feed mutations are recorded in, or nil if disabled.
+func (r *Service) ChangeFeed() *changefeed.Feed {
+	return r.changes
+}
+
+// RecentChanges returns a resolver listing the mutations recently executed
+// through this gateway instance on the request's cluster.
+func (r *Service) RecentChanges() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		if r.changes == nil {
+			return nil, errors.New("change feed is disabled")
+		}
+
+		current := changeCluster(p.Context)
+		cluster, err := GetArg[string](p.Args, ClusterArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if cluster != "" && cluster != current {
+			return nil, errors.New("changes of other clusters are not visible from this endpoint")
+		}
+
+		filter := changefeed.Filter{Cluster: current}
+		kinds, err := GetStringListArg(p.Args, KindsArg)
+		if err != nil {
+			return nil, err
+		}
+		filter.Kinds = kinds
+
+		limit, err := GetArg[int](p.Args, LimitArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if limit <= 0 || limit > r.changes.Size() {
+			limit = r.changes.Size()
+		}
+
+		return r.changes.Recent(filter, limit), nil
+	}
+}
+
+// recordChange adds a mutation to the change feed, if enabled.
+func (r *Service) recordChange(ctx context.Context, operation string, gvk schema.GroupVersionKind, obj *unstructured.Unstructured) {
+	if r.changes == nil {
+		return
+	}
+
+	r.changes.Record(ctx, changefeed.Change{
+		Time:            time.Now(),
+		Cluster:         changeCluster(ctx),
+		Operation:       operation,
+		Group:           gvk.Group,
+		Version:         gvk.Version,
+		Kind:            gvk.Kind,
+		Namespace:       obj.GetNamespace(),
+		Name:            obj.GetName(),
+		ResourceVersion: obj.GetResourceVersion(),
+	})
+}
+
+// changeCluster identifies the cluster of a request: the logical cluster
+// target if one was set, otherwise the endpoint's cluster.
+func changeCluster(ctx context.Context) string {
+	if target, ok := utilscontext.GetClusterTargetFromCtx(ctx); ok && target != "" {
+		return target
+	}
+	cluster, _ := utilscontext.GetClusterFromCtx(ctx)
+	return cluster
+}
@@ -0,0 +1,90 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/changefeed"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestRecentChanges(t *testing.T) {
+	feed, err := changefeed.New(10, "")
+	require.NoError(t, err)
+	r := New(nil).WithChangeFeed(feed)
+
+	ctx := utilscontext.SetCluster(t.Context(), "c1")
+	otherCtx := utilscontext.SetCluster(t.Context(), "c2")
+
+	obj := func(name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetNamespace("default")
+		u.SetName(name)
+		u.SetResourceVersion("42")
+		return u
+	}
+	pod := schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	deployment := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+	r.recordChange(ctx, changefeed.OperationCreate, pod, obj("p1"))
+	r.recordChange(ctx, changefeed.OperationUpdate, deployment, obj("d1"))
+	r.recordChange(otherCtx, changefeed.OperationDelete, pod, obj("p2"))
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "changes of the endpoint's cluster",
+			args: map[string]any{LimitArg: 50},
+			want: []string{"UPDATE d1", "CREATE p1"},
+		},
+		{
+			name: "filtered by kind",
+			args: map[string]any{KindsArg: []any{"pod"}, LimitArg: 50},
+			want: []string{"CREATE p1"},
+		},
+		{
+			name: "limited",
+			args: map[string]any{LimitArg: 1},
+			want: []string{"UPDATE d1"},
+		},
+		{
+			name:    "other cluster",
+			args:    map[string]any{ClusterArg: "c2", LimitArg: 50},
+			wantErr: "changes of other clusters are not visible from this endpoint",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := r.RecentChanges()(graphql.ResolveParams{Context: ctx, Args: tt.args})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []string
+			for _, c := range result.([]changefeed.Change) {
+				assert.Equal(t, "c1", c.Cluster)
+				assert.Equal(t, "default", c.Namespace)
+				assert.Equal(t, "42", c.ResourceVersion)
+				got = append(got, c.Operation+" "+c.Name)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRecentChanges_Disabled(t *testing.T) {
+	_, err := New(nil).RecentChanges()(graphql.ResolveParams{Context: t.Context(), Args: map[string]any{}})
+	assert.EqualError(t, err, "change feed is disabled")
+}
@@ -0,0 +1,196 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	HostArg       = "host"
+	TokenArg      = "token"
+	KubeconfigArg = "kubeconfig"
+	CAArg         = "certificateAuthority"
+	TTLSecondsArg = "ttlSeconds"
+)
+
+// ClusterCredentials are how the gateway reaches a registered cluster.
+type ClusterCredentials struct {
+	// Host is the URL of the API server, defaulting to the server of the
+	// kubeconfig.
+	Host string
+	// Token is a bearer token, alternatively to a kubeconfig.
+	Token string
+	// Kubeconfig is a kubeconfig with credentials, alternatively to a token.
+	Kubeconfig string
+	// CA is the PEM encoded certificate authority of the API server.
+	CA string
+}
+
+// RegisteredCluster is a cluster registered with the registerCluster
+// mutation.
+type RegisteredCluster struct {
+	Name      string    `json:"name"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ClusterRegistrar serves clusters registered at runtime.
+type ClusterRegistrar interface {
+	// RegisterCluster generates the schema of a cluster and serves it until
+	// ttl passed, replacing an earlier registration of the cluster by the
+	// same user.
+	RegisterCluster(ctx context.Context, name, user string, credentials ClusterCredentials, ttl time.Duration) (*RegisteredCluster, error)
+	// UnregisterCluster stops serving a cluster the user registered and
+	// reports whether it was registered.
+	UnregisterCluster(ctx context.Context, name, user string) (bool, error)
+}
+
+// ClusterRegistration configures the registerCluster and unregisterCluster
+// mutations.
+type ClusterRegistration struct {
+	Registrar ClusterRegistrar
+	// Member returns the name of the user authenticated with a token and
+	// whether they may register clusters.
+	Member func(ctx context.Context, token string) (user string, member bool, err error)
+	// TTL is the default and maximum time registered clusters are served.
+	TTL time.Duration
+}
+
+// WithClusterRegistration enables the registerCluster and unregisterCluster
+// mutations.
+func (r *Service) WithClusterRegistration(registration *ClusterRegistration) *Service {
+	r.clusterRegistration = registration
+	return r
+}
+
+// ClusterRegistration returns the configuration of the cluster registration
+// mutations, or nil if disabled.
+func (r *Service) ClusterRegistration() *ClusterRegistration {
+	return r.clusterRegistration
+}
+
+// RegisterClusterArgs returns the arguments of the registerCluster mutation.
+func RegisterClusterArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		NameArg: &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "Name the cluster is served under, a DNS subdomain",
+		},
+		HostArg: &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "URL of the API server; defaults to the server of the kubeconfig",
+		},
+		TokenArg: &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Bearer token the gateway uses for the cluster, alternatively to a kubeconfig",
+		},
+		KubeconfigArg: &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Kubeconfig the gateway uses for the cluster, alternatively to a token; credentials and certificate authority must be embedded, exec and auth-provider users are rejected",
+		},
+		CAArg: &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "PEM encoded certificate authority of the API server",
+		},
+		TTLSecondsArg: &graphql.ArgumentConfig{
+			Type:        graphql.Int,
+			Description: "Seconds the cluster is served before it is removed; defaults to and is capped at the gateway's maximum",
+		},
+	}
+}
+
+// RegisterCluster returns a resolver registering a cluster for members of
+// the groups allowed to.
+func (r *Service) RegisterCluster() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		user, err := r.authorizeClusterRegistration(p.Context)
+		if err != nil {
+			return nil, err
+		}
+
+		name, err := GetArg[string](p.Args, NameArg, true)
+		if err != nil {
+			return nil, err
+		}
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid cluster name %q: %s", name, strings.Join(errs, ", "))
+		}
+
+		var credentials ClusterCredentials
+		for arg, value := range map[string]*string{
+			HostArg:       &credentials.Host,
+			TokenArg:      &credentials.Token,
+			KubeconfigArg: &credentials.Kubeconfig,
+			CAArg:         &credentials.CA,
+		} {
+			if *value, err = GetArg[string](p.Args, arg, false); err != nil {
+				return nil, err
+			}
+		}
+
+		ttl := r.clusterRegistration.TTL
+		ttlSeconds, err := GetArg[int](p.Args, TTLSecondsArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if ttlSeconds < 0 {
+			return nil, fmt.Errorf("%s must not be negative", TTLSecondsArg)
+		}
+		if ttlSeconds > 0 {
+			ttl = min(ttl, time.Duration(ttlSeconds)*time.Second)
+		}
+
+		registered, err := r.clusterRegistration.Registrar.RegisterCluster(p.Context, name, user, credentials, ttl)
+		if err != nil {
+			return nil, err
+		}
+		log.FromContext(p.Context).Info("Registered cluster", "cluster", name, "user", user, "expiresAt", registered.ExpiresAt)
+		return registered, nil
+	}
+}
+
+// UnregisterCluster returns a resolver removing a cluster the user
+// registered.
+func (r *Service) UnregisterCluster() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		user, err := r.authorizeClusterRegistration(p.Context)
+		if err != nil {
+			return nil, err
+		}
+
+		name, err := GetArg[string](p.Args, NameArg, true)
+		if err != nil {
+			return nil, err
+		}
+		return r.clusterRegistration.Registrar.UnregisterCluster(p.Context, name, user)
+	}
+}
+
+// authorizeClusterRegistration returns the name of the user of ctx, failing
+// unless they may register clusters.
+func (r *Service) authorizeClusterRegistration(ctx context.Context) (string, error) {
+	if r.clusterRegistration == nil {
+		return "", errors.New("cluster registration is disabled")
+	}
+
+	token, ok := utilscontext.GetTokenFromCtx(ctx)
+	if !ok || token == "" {
+		return "", errors.New("registering clusters requires authentication")
+	}
+	user, member, err := r.clusterRegistration.Member(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	if !member || user == "" {
+		return "", errors.New("you are not allowed to register clusters")
+	}
+	return user, nil
+}
@@ -0,0 +1,111 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRegistrar struct {
+	name        string
+	user        string
+	credentials ClusterCredentials
+	ttl         time.Duration
+}
+
+func (f *fakeRegistrar) RegisterCluster(_ context.Context, name, user string, credentials ClusterCredentials, ttl time.Duration) (*RegisteredCluster, error) {
+	f.name, f.user, f.credentials, f.ttl = name, user, credentials, ttl
+	return &RegisteredCluster{Name: name}, nil
+}
+
+func (f *fakeRegistrar) UnregisterCluster(_ context.Context, name, user string) (bool, error) {
+	return name == f.name && user == f.user, nil
+}
+
+func TestRegisterCluster(t *testing.T) {
+	member := func(_ context.Context, token string) (string, bool, error) {
+		return token + "-user", token == "admin", nil
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		args    map[string]any
+		wantTTL time.Duration
+		wantErr string
+	}{
+		{
+			name:    "default TTL",
+			token:   "admin",
+			args:    map[string]any{NameArg: "dev", HostArg: "https://dev.example.com", TokenArg: "secret"},
+			wantTTL: time.Hour,
+		},
+		{
+			name:    "shorter TTL",
+			token:   "admin",
+			args:    map[string]any{NameArg: "dev", HostArg: "https://dev.example.com", TokenArg: "secret", TTLSecondsArg: 60},
+			wantTTL: time.Minute,
+		},
+		{
+			name:    "TTL capped at the maximum",
+			token:   "admin",
+			args:    map[string]any{NameArg: "dev", HostArg: "https://dev.example.com", TokenArg: "secret", TTLSecondsArg: 86400},
+			wantTTL: time.Hour,
+		},
+		{
+			name:    "invalid name",
+			token:   "admin",
+			args:    map[string]any{NameArg: "Dev/1", TokenArg: "secret"},
+			wantErr: "invalid cluster name",
+		},
+		{
+			name:    "not a member",
+			token:   "user",
+			args:    map[string]any{NameArg: "dev", TokenArg: "secret"},
+			wantErr: "not allowed to register clusters",
+		},
+		{
+			name:    "unauthenticated",
+			args:    map[string]any{NameArg: "dev", TokenArg: "secret"},
+			wantErr: "requires authentication",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registrar := &fakeRegistrar{}
+			r := New(nil).WithClusterRegistration(&ClusterRegistration{Registrar: registrar, Member: member, TTL: time.Hour})
+
+			ctx := t.Context()
+			if tt.token != "" {
+				ctx = utilscontext.SetToken(ctx, tt.token)
+			}
+			result, err := r.RegisterCluster()(graphql.ResolveParams{Context: ctx, Args: tt.args})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				assert.Empty(t, registrar.name)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &RegisteredCluster{Name: "dev"}, result)
+			assert.Equal(t, ClusterCredentials{Host: "https://dev.example.com", Token: "secret"}, registrar.credentials)
+			assert.Equal(t, tt.wantTTL, registrar.ttl)
+			assert.Equal(t, "admin-user", registrar.user, "the registrar learns who registered the cluster")
+
+			unregistered, err := r.UnregisterCluster()(graphql.ResolveParams{Context: ctx, Args: map[string]any{NameArg: "dev"}})
+			require.NoError(t, err)
+			assert.Equal(t, true, unregistered)
+		})
+	}
+}
+
+func TestClusterRegistrationDisabled(t *testing.T) {
+	ctx := utilscontext.SetToken(t.Context(), "admin")
+	_, err := New(nil).UnregisterCluster()(graphql.ResolveParams{Context: ctx, Args: map[string]any{NameArg: "dev"}})
+	assert.ErrorContains(t, err, "cluster registration is disabled")
+}
@@ -0,0 +1,71 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// ClusterStatus is the state of a cluster served by the gateway, so clients
+// can tell why its operations fail.
+type ClusterStatus struct {
+	Name string `json:"name"`
+	// Served reports whether the gateway serves a schema for the cluster.
+	Served bool `json:"served"`
+	// Reachable reports whether the API server responded to the last call.
+	Reachable bool `json:"reachable"`
+	// CredentialsValid reports whether the API server accepted the
+	// gateway's credentials for the cluster in the last response.
+	CredentialsValid bool `json:"credentialsValid"`
+	// LastSuccessfulCall is when a call with the gateway's credentials last
+	// succeeded.
+	LastSuccessfulCall *time.Time `json:"lastSuccessfulCall"`
+	// ServerVersion is the version of the API server.
+	ServerVersion string `json:"serverVersion"`
+	// SchemaUpdatedAt is when the served schema was loaded.
+	SchemaUpdatedAt *time.Time `json:"schemaUpdatedAt"`
+	// SchemaError is why the latest schema of the cluster failed to load,
+	// in which case a previous one may still be served.
+	SchemaError string `json:"schemaError"`
+	// Error is the error of the last call to the API server.
+	Error string `json:"error"`
+}
+
+// ClusterStatusFunc returns the status of a cluster, nil if the gateway does
+// not know it.
+type ClusterStatusFunc func(ctx context.Context, name string) *ClusterStatus
+
+// WithClusterStatus serves the clusterStatus query from status.
+func (r *Service) WithClusterStatus(status ClusterStatusFunc) *Service {
+	r.clusterStatus = status
+	return r
+}
+
+// ClusterStatus returns the function the clusterStatus query is served from,
+// or nil if disabled.
+func (r *Service) ClusterStatus() ClusterStatusFunc {
+	return r.clusterStatus
+}
+
+// GetClusterStatus returns a resolver returning the status of a cluster, or
+// null if the gateway does not know it.
+func (r *Service) GetClusterStatus() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		if r.clusterStatus == nil {
+			return nil, errors.New("cluster status is disabled")
+		}
+
+		name, err := GetArg[string](p.Args, NameArg, true)
+		if err != nil {
+			return nil, err
+		}
+
+		status := r.clusterStatus(p.Context, name)
+		if status == nil {
+			return nil, nil
+		}
+		return status, nil
+	}
+}
@@ -0,0 +1,30 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetClusterStatus(t *testing.T) {
+	r := New(nil).WithClusterStatus(func(_ context.Context, name string) *ClusterStatus {
+		if name != "c1" {
+			return nil
+		}
+		return &ClusterStatus{Name: name, Served: true, Reachable: true}
+	})
+
+	result, err := r.GetClusterStatus()(graphql.ResolveParams{Context: t.Context(), Args: map[string]any{NameArg: "c1"}})
+	require.NoError(t, err)
+	assert.Equal(t, &ClusterStatus{Name: "c1", Served: true, Reachable: true}, result)
+
+	result, err = r.GetClusterStatus()(graphql.ResolveParams{Context: t.Context(), Args: map[string]any{NameArg: "c2"}})
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	_, err = New(nil).GetClusterStatus()(graphql.ResolveParams{Context: t.Context(), Args: map[string]any{NameArg: "c1"}})
+	assert.Error(t, err)
+}
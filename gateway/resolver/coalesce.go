@@ -0,0 +1,142 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/recovery"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// maxCoalesceWindow bounds how long events of a subscription may be held back.
+const maxCoalesceWindow = time.Minute
+
+// coalesceWindow returns the window of the coalesceMs argument, zero if unset.
+func coalesceWindow(args map[string]any) (time.Duration, error) {
+	ms, err := GetArg[int](args, CoalesceMsArg, false)
+	if err != nil {
+		return 0, err
+	}
+	window := time.Duration(ms) * time.Millisecond
+	if window < 0 || window > maxCoalesceWindow {
+		return 0, errors.New("coalesceMs must be between 0 and 60000")
+	}
+	return window, nil
+}
+
+// coalesceEvents returns a channel emitting the events of in, where events of
+// the same object arriving within window of its first pending event are
+// merged into one carrying the latest object and the number of events left
+// out in Suppressed. Errors are passed on after the pending events. As merged
+// events are reordered, only the last event of a flush carries the resource
+// version to resume from, that of the latest event. merged, if not nil, is
+// called for each event merged into another. A zero window returns in
+// unchanged.
+func coalesceEvents(ctx context.Context, in chan any, window time.Duration, merged func()) chan any {
+	if window <= 0 {
+		return in
+	}
+
+	out := make(chan any)
+	go func() {
+		defer close(out)
+		defer func() {
+			if err := recovery.Recovered(ctx, "coalesceEvents", recover()); err != nil {
+				select {
+				case <-ctx.Done():
+				case out <- err:
+				}
+			}
+		}()
+
+		var (
+			pending = map[string]*SubscriptionEnvelope{}
+			order   []string
+			flush   <-chan time.Time
+			// resume is the resource version of the latest event.
+			resume string
+		)
+		send := func(v any) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- v:
+				return true
+			}
+		}
+		flushPending := func() bool {
+			for i, key := range order {
+				envelope := *pending[key]
+				envelope.ResourceVersion = ""
+				if i == len(order)-1 {
+					envelope.ResourceVersion = resume
+				}
+				if !send(envelope) {
+					return false
+				}
+			}
+			clear(pending)
+			order, flush = order[:0], nil
+			return true
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-flush:
+				if !flushPending() {
+					return
+				}
+			case v, ok := <-in:
+				if !ok {
+					flushPending()
+					return
+				}
+				envelope, isEnvelope := v.(SubscriptionEnvelope)
+				if !isEnvelope {
+					if !flushPending() || !send(v) {
+						return
+					}
+					continue
+				}
+
+				resume = envelope.ResourceVersion
+				key := envelopeKey(envelope)
+				if prev, ok := pending[key]; ok {
+					prev.Object = envelope.Object
+					prev.Suppressed += envelope.Suppressed + 1
+					// An object added within the window is still new to the client.
+					if prev.Type != EventTypeAdded || envelope.Type != EventTypeModified {
+						prev.Type = envelope.Type
+					}
+					if merged != nil {
+						merged()
+					}
+					continue
+				}
+				pending[key] = &envelope
+				order = append(order, key)
+				if flush == nil {
+					flush = time.After(window)
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// envelopeKey identifies the object of an event by namespace and name.
+func envelopeKey(envelope SubscriptionEnvelope) string {
+	switch obj := envelope.Object.(type) {
+	case map[string]any:
+		u := unstructured.Unstructured{Object: obj}
+		return u.GetNamespace() + "/" + u.GetName()
+	case SubscriptionObject:
+		return obj.Metadata.Namespace + "/" + obj.Metadata.Name
+	default:
+		return ""
+	}
+}
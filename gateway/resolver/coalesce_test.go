@@ -0,0 +1,135 @@
+package resolver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoalesceEvents(t *testing.T) {
+	event := func(eventType, name, rv string) SubscriptionEnvelope {
+		return SubscriptionEnvelope{Type: eventType, Object: makeUnstructuredObj(name, "default", rv).Object}
+	}
+	errWatch := errors.New("watch failed")
+
+	tests := []struct {
+		name   string
+		events []any
+		want   []SubscriptionEnvelope
+		err    bool
+	}{
+		{
+			name:   "changes of an object are merged into the latest",
+			events: []any{event(EventTypeModified, "a", "1"), event(EventTypeModified, "a", "2"), event(EventTypeModified, "a", "3")},
+			want:   []SubscriptionEnvelope{{Type: EventTypeModified, Object: event("", "a", "3").Object, Suppressed: 2}},
+		},
+		{
+			name:   "objects are kept apart in order",
+			events: []any{event(EventTypeModified, "a", "1"), event(EventTypeAdded, "b", "2"), event(EventTypeModified, "a", "3")},
+			want: []SubscriptionEnvelope{
+				{Type: EventTypeModified, Object: event("", "a", "3").Object, Suppressed: 1},
+				{Type: EventTypeAdded, Object: event("", "b", "2").Object},
+			},
+		},
+		{
+			name:   "modified after added stays added",
+			events: []any{event(EventTypeAdded, "a", "1"), event(EventTypeModified, "a", "2")},
+			want:   []SubscriptionEnvelope{{Type: EventTypeAdded, Object: event("", "a", "2").Object, Suppressed: 1}},
+		},
+		{
+			name:   "deleted is emitted as deleted",
+			events: []any{event(EventTypeModified, "a", "1"), event(EventTypeDeleted, "a", "2")},
+			want:   []SubscriptionEnvelope{{Type: EventTypeDeleted, Object: event("", "a", "2").Object, Suppressed: 1}},
+		},
+		{
+			name:   "errors are passed on after the pending events",
+			events: []any{event(EventTypeModified, "a", "1"), errWatch},
+			want:   []SubscriptionEnvelope{{Type: EventTypeModified, Object: event("", "a", "1").Object}},
+			err:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := make(chan any)
+			out := coalesceEvents(t.Context(), in, time.Hour, nil)
+			go func() {
+				defer close(in)
+				for _, e := range tt.events {
+					in <- e
+				}
+			}()
+
+			var got []SubscriptionEnvelope
+			var gotErr error
+			for v := range out {
+				switch v := v.(type) {
+				case SubscriptionEnvelope:
+					got = append(got, v)
+				case error:
+					gotErr = v
+				}
+			}
+			assert.Equal(t, tt.want, got)
+			if tt.err {
+				assert.ErrorIs(t, gotErr, errWatch)
+			}
+		})
+	}
+}
+
+func TestCoalesceEventsResumePoint(t *testing.T) {
+	event := func(name, rv string) SubscriptionEnvelope {
+		return SubscriptionEnvelope{Type: EventTypeModified, Object: makeUnstructuredObj(name, "default", rv).Object, ResourceVersion: rv}
+	}
+
+	in := make(chan any)
+	out := coalesceEvents(t.Context(), in, time.Hour, nil)
+	go func() {
+		defer close(in)
+		for _, e := range []SubscriptionEnvelope{event("a", "1"), event("b", "2"), event("a", "3")} {
+			in <- e
+		}
+	}()
+
+	// b was changed before the merged change of a, so resuming after a would
+	// lose it: only the last event carries the resume point.
+	var got []string
+	for v := range out {
+		got = append(got, v.(SubscriptionEnvelope).ResourceVersion)
+	}
+	assert.Equal(t, []string{"", "3"}, got)
+}
+
+func TestCoalesceEventsFlushesAfterWindow(t *testing.T) {
+	in := make(chan any)
+	defer close(in)
+	out := coalesceEvents(t.Context(), in, 10*time.Millisecond, nil)
+
+	in <- SubscriptionEnvelope{Type: EventTypeModified, Object: makeUnstructuredObj("a", "default", "1").Object}
+	select {
+	case v := <-out:
+		assert.Equal(t, EventTypeModified, v.(SubscriptionEnvelope).Type)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not emitted after the window")
+	}
+}
+
+func TestCoalesceWindow(t *testing.T) {
+	window, err := coalesceWindow(map[string]any{})
+	require.NoError(t, err)
+	assert.Zero(t, window)
+
+	window, err = coalesceWindow(map[string]any{CoalesceMsArg: 250})
+	require.NoError(t, err)
+	assert.Equal(t, 250*time.Millisecond, window)
+
+	_, err = coalesceWindow(map[string]any{CoalesceMsArg: -1})
+	assert.Error(t, err)
+
+	in := make(chan any)
+	assert.Equal(t, in, coalesceEvents(t.Context(), in, 0, nil))
+}
@@ -0,0 +1,90 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/graphql-go/graphql"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ConflictCode is the code of the GraphQL errors of conflicting writes.
+const ConflictCode = "CONFLICT"
+
+var UpdateResourceVersionArgConfig = &graphql.ArgumentConfig{
+	Type:        graphql.String,
+	Description: "If set, the update fails with a CONFLICT error unless the object still has this resourceVersion",
+}
+
+// ConflictError is returned when the API server rejects a write because the
+// object was changed since the resourceVersion or preconditions the client
+// passed.
+type ConflictError struct {
+	Kind string
+	Name string
+	// CurrentResourceVersion is the resourceVersion of the object after the
+	// conflict, empty if it could not be read.
+	CurrentResourceVersion string
+	Err                    error
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %s was modified concurrently, read it again and retry: %v", e.Kind, e.Name, e.Err)
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// Extensions implements gqlerrors.ExtendedError, so clients can tell
+// conflicts from other errors and retry with the current resourceVersion.
+func (e *ConflictError) Extensions() map[string]any {
+	extensions := ErrorExtensions(e.Err)
+	if extensions == nil {
+		extensions = map[string]any{}
+	}
+	extensions["code"] = ConflictCode
+	if e.CurrentResourceVersion != "" {
+		extensions["currentResourceVersion"] = e.CurrentResourceVersion
+	}
+	return extensions
+}
+
+// conflictError returns a ConflictError for err if the API server rejected
+// the write of obj with a conflict, reading the current resourceVersion of
+// obj, and err otherwise.
+func (r *Service) conflictError(ctx context.Context, obj *unstructured.Unstructured, err error) error {
+	if !apierrors.IsConflict(err) {
+		return err
+	}
+
+	conflict := &ConflictError{Kind: obj.GetKind(), Name: obj.GetName(), Err: err}
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(obj.GroupVersionKind())
+	if getErr := r.runtimeClient.Get(ctx, client.ObjectKeyFromObject(obj), current); getErr != nil {
+		log.FromContext(ctx).V(4).Info("Failed to read object after conflict", "error", getErr)
+		return conflict
+	}
+	conflict.CurrentResourceVersion = current.GetResourceVersion()
+	return conflict
+}
+
+// withResourceVersion returns a copy of an object input with
+// metadata.resourceVersion set, which makes the API server reject patches of
+// objects with another resourceVersion.
+func withResourceVersion(input map[string]any, resourceVersion string) map[string]any {
+	input = maps.Clone(input)
+	metadata, _ := input["metadata"].(map[string]any)
+	metadata = maps.Clone(metadata)
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadata["resourceVersion"] = resourceVersion
+	input["metadata"] = metadata
+	return input
+}
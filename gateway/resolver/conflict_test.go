@@ -0,0 +1,59 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestUpdateItem_ResourceVersion(t *testing.T) {
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+	}).Build()
+	update := New(cl).UpdateItem(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, v1.NamespaceScoped)
+
+	var current corev1.ConfigMap
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "settings"}, &current))
+
+	updateWith := func(resourceVersion, value string) (any, error) {
+		return update(graphql.ResolveParams{
+			Context: t.Context(),
+			Args: map[string]any{
+				NameArg:            "settings",
+				NamespaceArg:       "default",
+				ResourceVersionArg: resourceVersion,
+				ObjectArg:          map[string]any{"data": map[string]any{"mode": value}},
+			},
+		})
+	}
+
+	result, err := updateWith(current.ResourceVersion, "a")
+	require.NoError(t, err)
+	updatedVersion := result.(map[string]any)["metadata"].(map[string]any)["resourceVersion"]
+	assert.NotEqual(t, current.ResourceVersion, updatedVersion)
+
+	// The object changed since current was read.
+	_, err = updateWith(current.ResourceVersion, "b")
+	var conflict *ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, updatedVersion, conflict.CurrentResourceVersion)
+
+	formatted := gqlerrors.FormatError(gqlerrors.NewLocatedError(err, nil))
+	assert.Equal(t, ConflictCode, formatted.Extensions["code"])
+	assert.Equal(t, "Conflict", formatted.Extensions["reason"])
+	assert.Equal(t, updatedVersion, formatted.Extensions["currentResourceVersion"])
+
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "settings"}, &current))
+	assert.Equal(t, map[string]string{"mode": "a"}, current.Data)
+}
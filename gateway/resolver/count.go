@@ -0,0 +1,139 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// countPageSize is the number of objects count and aggregate queries list
+// per request.
+const countPageSize = 500
+
+// CountArgs returns arguments for count queries
+func CountArgs(scope v1.ResourceScope) graphql.FieldConfigArgument {
+	args := graphql.FieldConfigArgument{
+		LabelSelectorArg:      LabelSelectorArgConfig,
+		LabelSelectorInputArg: LabelSelectorInputArgConfig,
+		FieldSelectorArg:      FieldSelectorArgConfig,
+	}
+	if isResourceNamespaceScoped(scope) {
+		args[NamespaceArg] = &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "The namespace in which to count the objects, all namespaces if omitted",
+		}
+	}
+	return args
+}
+
+// CountItems returns a resolver counting the objects of a kind matching the
+// selectors. It lists only their metadata, in pages, and stops after the
+// first page if the API server reports the number of remaining objects.
+func (r *Service) CountItems(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, "CountItems", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		logger := log.FromContext(p.Context).WithValues("operation", "count", "kind", gvk.Kind)
+
+		opts, err := metadataListOptions(p.Args, scope)
+		if err != nil {
+			logger.Error(err, "Unable to parse given selectors")
+			return nil, err
+		}
+
+		count := 0
+		err = r.listMetadata(ctx, gvk, opts, func(list *metav1.PartialObjectMetadataList) bool {
+			count += len(list.Items)
+			if remaining := list.GetRemainingItemCount(); remaining != nil {
+				count += int(*remaining)
+				return false
+			}
+			return true
+		})
+		if err != nil {
+			logger.Error(err, "Unable to list objects")
+			return nil, err
+		}
+		return count, nil
+	}
+}
+
+// metadataListOptions returns the list options of the selector and
+// namespace arguments of count and aggregate queries.
+func metadataListOptions(args map[string]any, scope v1.ResourceScope) ([]client.ListOption, error) {
+	opts, err := selectorListOptions(args)
+	if err != nil {
+		return nil, err
+	}
+	if isResourceNamespaceScoped(scope) {
+		namespace, err := GetArg[string](args, NamespaceArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if namespace != "" {
+			opts = append(opts, client.InNamespace(namespace))
+		}
+	}
+	return opts, nil
+}
+
+// listMetadata lists the metadata of the objects of gvk in pages of
+// countPageSize objects and calls page for each page until it returns false.
+func (r *Service) listMetadata(ctx context.Context, gvk schema.GroupVersionKind, opts []client.ListOption, page func(list *metav1.PartialObjectMetadataList) bool) error {
+	opts = append(opts, client.Limit(countPageSize))
+	continueToken := ""
+	for {
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(gvk)
+		if err := r.runtimeClient.List(ctx, list, append(opts, client.Continue(continueToken))...); err != nil {
+			return fmt.Errorf("unable to list objects: %w", err)
+		}
+		if !page(list) {
+			return nil
+		}
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			return nil
+		}
+	}
+}
+
+// selectorListOptions returns the list options of the label and field
+// selector arguments.
+func selectorListOptions(args map[string]any) ([]client.ListOption, error) {
+	var opts []client.ListOption
+
+	labelSelector, err := labelSelectorFromArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if labelSelector != nil {
+		opts = append(opts, client.MatchingLabelsSelector{Selector: labelSelector})
+	}
+
+	fieldSelector, err := GetArg[string](args, FieldSelectorArg, false)
+	if err != nil {
+		return nil, err
+	}
+	if fieldSelector != "" {
+		selector, err := fields.ParseSelector(fieldSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", FieldSelectorArg, fieldSelector, err)
+		}
+		opts = append(opts, client.MatchingFieldsSelector{Selector: selector})
+	}
+
+	return opts, nil
+}
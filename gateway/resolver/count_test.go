@@ -0,0 +1,74 @@
+package resolver
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCountItems(t *testing.T) {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+
+	t.Run("pages through metadata", func(t *testing.T) {
+		var requests []*client.ListOptions
+		fc := &fakeClient{
+			listFn: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+				listOpts := &client.ListOptions{}
+				listOpts.ApplyOptions(opts)
+				requests = append(requests, listOpts)
+
+				metadataList, ok := list.(*metav1.PartialObjectMetadataList)
+				require.True(t, ok, "count must list metadata only")
+				assert.Equal(t, gvk, metadataList.GroupVersionKind())
+
+				page := len(requests)
+				metadataList.Items = make([]metav1.PartialObjectMetadata, 2)
+				if page < 3 {
+					metadataList.Continue = strconv.Itoa(page)
+				}
+				return nil
+			},
+		}
+
+		count, err := (&Service{runtimeClient: fc}).CountItems(gvk, v1.NamespaceScoped)(graphql.ResolveParams{
+			Context: t.Context(),
+			Args:    map[string]any{NamespaceArg: "team-a", LabelSelectorArg: "app=web"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 6, count)
+
+		require.Len(t, requests, 3)
+		assert.Equal(t, "team-a", requests[0].Namespace)
+		assert.Equal(t, "app=web", requests[0].LabelSelector.String())
+		assert.Equal(t, int64(countPageSize), requests[0].Limit)
+		assert.Empty(t, requests[0].Continue)
+		assert.Equal(t, "2", requests[2].Continue)
+	})
+
+	t.Run("remaining item count", func(t *testing.T) {
+		fc := &fakeClient{
+			listFn: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+				remaining := int64(1200)
+				metadataList := list.(*metav1.PartialObjectMetadataList)
+				metadataList.Items = make([]metav1.PartialObjectMetadata, countPageSize)
+				metadataList.Continue = "next"
+				metadataList.RemainingItemCount = &remaining
+				return nil
+			},
+		}
+
+		count, err := (&Service{runtimeClient: fc}).CountItems(gvk, v1.NamespaceScoped)(graphql.ResolveParams{Context: t.Context(), Args: map[string]any{}})
+		require.NoError(t, err)
+		assert.Equal(t, countPageSize+1200, count)
+		assert.EqualValues(t, 1, fc.listCalls)
+	})
+}
@@ -0,0 +1,86 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDeleteAllOf(t *testing.T) {
+	configMap := func(namespace, name, env string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    map[string]string{"env": env},
+		}}
+	}
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	tests := []struct {
+		name      string
+		args      map[string]any
+		wantNames []string
+		wantErr   string
+	}{
+		{
+			name:      "all objects of the namespace",
+			args:      map[string]any{NamespaceArg: "test"},
+			wantNames: []string{"other/keep"},
+		},
+		{
+			name:      "label selector",
+			args:      map[string]any{NamespaceArg: "test", LabelSelectorArg: "env=ci"},
+			wantNames: []string{"other/keep", "test/b"},
+		},
+		{
+			name:      "structured label selector",
+			args:      map[string]any{NamespaceArg: "test", LabelSelectorInputArg: map[string]any{"matchLabels": map[string]any{"env": "dev"}}},
+			wantNames: []string{"other/keep", "test/a"},
+		},
+		{
+			name:      "dry run",
+			args:      map[string]any{NamespaceArg: "test", DryRunArg: true},
+			wantNames: []string{"other/keep", "test/a", "test/b"},
+		},
+		{
+			name:    "namespace is required",
+			args:    map[string]any{},
+			wantErr: "missing required argument: namespace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(configMap("test", "a", "ci"), configMap("test", "b", "dev"), configMap("other", "keep", "ci")).
+				Build()
+
+			result, err := New(cl).DeleteAllOf(gvk, v1.NamespaceScoped)(graphql.ResolveParams{Context: t.Context(), Args: tt.args})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, true, result)
+
+			var remaining corev1.ConfigMapList
+			require.NoError(t, cl.List(t.Context(), &remaining))
+			var names []string
+			for _, cm := range remaining.Items {
+				names = append(names, client.ObjectKeyFromObject(&cm).String())
+			}
+			assert.ElementsMatch(t, tt.wantNames, names)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package resolver
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	PropagationPolicyArg = "propagationPolicy"
+	PreconditionsArg     = "preconditions"
+)
+
+// PropagationPolicyEnum mirrors metav1.DeletionPropagation.
+var PropagationPolicyEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "DeletionPropagation",
+	Description: "Whether and how the dependents of a deleted object are garbage collected",
+	Values: graphql.EnumValueConfigMap{
+		string(metav1.DeletePropagationOrphan): &graphql.EnumValueConfig{
+			Value:       string(metav1.DeletePropagationOrphan),
+			Description: "Dependents are kept and their owner references to the object removed",
+		},
+		string(metav1.DeletePropagationBackground): &graphql.EnumValueConfig{
+			Value:       string(metav1.DeletePropagationBackground),
+			Description: "The object is deleted right away and its dependents in the background",
+		},
+		string(metav1.DeletePropagationForeground): &graphql.EnumValueConfig{
+			Value:       string(metav1.DeletePropagationForeground),
+			Description: "The object is deleted after its dependents with blockOwnerDeletion",
+		},
+	},
+})
+
+var (
+	PropagationPolicyArgConfig = &graphql.ArgumentConfig{
+		Type:        PropagationPolicyEnum,
+		Description: "How the dependents of the object are garbage collected; defaults to the kind's policy, usually Background",
+	}
+
+	DeleteGracePeriodSecondsArgConfig = &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "Seconds the object is given to terminate gracefully, 0 to delete it immediately; defaults to the kind's grace period",
+	}
+
+	PreconditionsArgConfig = &graphql.ArgumentConfig{
+		Type:        PreconditionsInput,
+		Description: "Conditions the object must fulfill to be deleted",
+	}
+)
+
+// PreconditionsInput mirrors metav1.Preconditions.
+var PreconditionsInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "PreconditionsInput",
+	Description: "Conditions the object must fulfill to be deleted",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"uid": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The UID the object must have, so a recreated object of the same name is not deleted",
+		},
+		"resourceVersion": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The resourceVersion the object must have, so it is not deleted after a concurrent change",
+		},
+	},
+})
+
+// deleteOptions returns the options of a delete from the arguments of a
+// delete mutation.
+func deleteOptions(args map[string]any, dryRun []string) (*client.DeleteOptions, error) {
+	opts := &client.DeleteOptions{DryRun: dryRun}
+
+	policy, err := GetArg[string](args, PropagationPolicyArg, false)
+	if err != nil {
+		return nil, err
+	}
+	if policy != "" {
+		propagation := metav1.DeletionPropagation(policy)
+		opts.PropagationPolicy = &propagation
+	}
+
+	if args[GracePeriodSecondsArg] != nil {
+		gracePeriod, err := GetArg[int](args, GracePeriodSecondsArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if gracePeriod < 0 {
+			return nil, fmt.Errorf("%s must not be negative", GracePeriodSecondsArg)
+		}
+		seconds := int64(gracePeriod)
+		opts.GracePeriodSeconds = &seconds
+	}
+
+	if preconditions, ok := args[PreconditionsArg].(map[string]any); ok {
+		uid, err := GetArg[string](preconditions, "uid", false)
+		if err != nil {
+			return nil, err
+		}
+		resourceVersion, err := GetArg[string](preconditions, "resourceVersion", false)
+		if err != nil {
+			return nil, err
+		}
+		if uid != "" || resourceVersion != "" {
+			opts.Preconditions = &metav1.Preconditions{}
+		}
+		if uid != "" {
+			opts.Preconditions.UID = (*types.UID)(&uid)
+		}
+		if resourceVersion != "" {
+			opts.Preconditions.ResourceVersion = &resourceVersion
+		}
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,88 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestDeleteItem_Options(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    client.DeleteOptions
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			args: map[string]any{},
+			want: client.DeleteOptions{DryRun: []string{}},
+		},
+		{
+			name: "propagation policy and grace period",
+			args: map[string]any{PropagationPolicyArg: "Foreground", GracePeriodSecondsArg: 0},
+			want: client.DeleteOptions{
+				DryRun:             []string{},
+				PropagationPolicy:  ptr.To(metav1.DeletePropagationForeground),
+				GracePeriodSeconds: ptr.To(int64(0)),
+			},
+		},
+		{
+			name: "preconditions",
+			args: map[string]any{PreconditionsArg: map[string]any{"uid": "1234", "resourceVersion": "42"}, DryRunArg: true},
+			want: client.DeleteOptions{
+				DryRun:        []string{"All"},
+				Preconditions: &metav1.Preconditions{UID: ptr.To(types.UID("1234")), ResourceVersion: ptr.To("42")},
+			},
+		},
+		{
+			name:    "negative grace period",
+			args:    map[string]any{GracePeriodSecondsArg: -1},
+			wantErr: "gracePeriodSeconds must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				calls int
+				opts  client.DeleteOptions
+			)
+			cl := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(_ context.Context, _ client.WithWatch, _ client.Object, deleteOpts ...client.DeleteOption) error {
+					calls++
+					opts.ApplyOptions(deleteOpts)
+					return nil
+				},
+			}).Build()
+
+			args := map[string]any{NameArg: "web", NamespaceArg: "default"}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+			_, err := New(cl).DeleteItem(gvk, v1.NamespaceScoped)(graphql.ResolveParams{Context: t.Context(), Args: args})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				assert.Zero(t, calls)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 1, calls)
+			assert.Equal(t, tt.want, opts)
+		})
+	}
+}
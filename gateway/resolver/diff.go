@@ -0,0 +1,156 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// The types of a field change in a diff.
+const (
+	FieldAdded   = "ADDED"
+	FieldRemoved = "REMOVED"
+	FieldChanged = "CHANGED"
+)
+
+// diffIgnoredFields are the metadata fields the API server maintains itself,
+// which differ between the live object and a dry run without a change of the
+// submitted object.
+var diffIgnoredFields = []string{"creationTimestamp", "generation", "managedFields", "resourceVersion", "uid"}
+
+// DiffArgs returns arguments for diff queries
+func DiffArgs(scope v1.ResourceScope, inputType *graphql.InputObject) graphql.FieldConfigArgument {
+	args := ApplyArgs(scope, inputType)
+	delete(args, DryRunArg)
+	args[ObjectArg].Description = "The fully specified intent of the field manager, applied with dry run"
+	return args
+}
+
+// DiffItem applies the object with server-side apply in dry run mode and
+// returns the fields in which the result differs from the live object. All
+// fields of an object that does not exist yet are added.
+func (r *Service) DiffItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, "DiffItem", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		logger := log.FromContext(p.Context).WithValues("operation", "diff", "kind", gvk.Kind)
+
+		obj, _, opts, err := r.applyRequest(p, gvk, scope)
+		if err != nil {
+			return nil, err
+		}
+		opts.DryRun = []string{"All"}
+
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(gvk)
+		exists := true
+		if err := r.runtimeClient.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+			if !apierrors.IsNotFound(err) {
+				logger.Error(err, "Failed to get object")
+				return nil, err
+			}
+			exists = false
+			live.Object = map[string]any{}
+		}
+
+		patchData, err := json.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal object input: %w", err)
+		}
+
+		if err := r.runtimeClient.Patch(ctx, obj, client.RawPatch(types.ApplyPatchType, patchData), opts); err != nil {
+			logger.Error(err, "Failed to apply object in dry run")
+			return nil, err
+		}
+
+		return map[string]any{
+			"exists":  exists,
+			"changes": fieldChanges(live.Object, obj.Object),
+		}, nil
+	}
+}
+
+// fieldChanges returns the changes from before to after ordered by path, except
+// for the fields the API server maintains. Lists of the same length are
+// compared item by item, other lists are changed as a whole.
+func fieldChanges(before, after map[string]any) []map[string]any {
+	before, after = copyObject(before), copyObject(after)
+	for _, field := range diffIgnoredFields {
+		unstructured.RemoveNestedField(before, "metadata", field)
+		unstructured.RemoveNestedField(after, "metadata", field)
+	}
+
+	changes := []map[string]any{}
+	collectFieldChanges(before, after, "", &changes)
+	return changes
+}
+
+func collectFieldChanges(before, after any, path string, changes *[]map[string]any) {
+	beforeMap, beforeIsMap := before.(map[string]any)
+	afterMap, afterIsMap := after.(map[string]any)
+	if beforeIsMap && afterIsMap {
+		keys := slices.Collect(maps.Keys(beforeMap))
+		for key := range afterMap {
+			if _, found := beforeMap[key]; !found {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			oldValue, inBefore := beforeMap[key]
+			newValue, inAfter := afterMap[key]
+			switch {
+			case !inBefore:
+				*changes = append(*changes, fieldChange(fieldPath, FieldAdded, nil, newValue))
+			case !inAfter:
+				*changes = append(*changes, fieldChange(fieldPath, FieldRemoved, oldValue, nil))
+			default:
+				collectFieldChanges(oldValue, newValue, fieldPath, changes)
+			}
+		}
+		return
+	}
+
+	beforeList, beforeIsList := before.([]any)
+	afterList, afterIsList := after.([]any)
+	if beforeIsList && afterIsList && len(beforeList) == len(afterList) {
+		for i := range beforeList {
+			collectFieldChanges(beforeList[i], afterList[i], fmt.Sprintf("%s[%d]", path, i), changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, fieldChange(path, FieldChanged, before, after))
+	}
+}
+
+func fieldChange(path, changeType string, oldValue, newValue any) map[string]any {
+	return map[string]any{
+		"path":     path,
+		"type":     changeType,
+		"oldValue": oldValue,
+		"newValue": newValue,
+	}
+}
@@ -0,0 +1,116 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestDiffItem(t *testing.T) {
+	var dryRun []string
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default", Labels: map[string]string{"app": "web"}},
+		Data:       map[string]string{"mode": "a", "level": "1"},
+	}).WithInterceptorFuncs(interceptor.Funcs{
+		// The server-side apply result is the applied object, as if the
+		// field manager owned all fields.
+		Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			patchOpts := &client.PatchOptions{}
+			patchOpts.ApplyOptions(opts)
+			dryRun = patchOpts.DryRun
+
+			data, err := patch.Data(obj)
+			if err != nil {
+				return err
+			}
+			u := obj.(*unstructured.Unstructured)
+			if err := json.Unmarshal(data, &u.Object); err != nil {
+				return err
+			}
+			u.SetUID("uid")
+			u.SetResourceVersion("2")
+			return nil
+		},
+	}).Build()
+	diff := New(cl).DiffItem(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, v1.NamespaceScoped)
+
+	t.Run("existing object", func(t *testing.T) {
+		result, err := diff(graphql.ResolveParams{
+			Context: t.Context(),
+			Args: map[string]any{
+				NamespaceArg: "default",
+				ObjectArg: map[string]any{
+					"metadata": map[string]any{"name": "settings", "labels": map[string]any{"app": "web"}},
+					"data":     map[string]any{"mode": "b", "debug": "true"},
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"All"}, dryRun)
+		assert.Equal(t, map[string]any{
+			"exists": true,
+			"changes": []map[string]any{
+				{"path": "data.debug", "type": FieldAdded, "oldValue": nil, "newValue": "true"},
+				{"path": "data.level", "type": FieldRemoved, "oldValue": "1", "newValue": nil},
+				{"path": "data.mode", "type": FieldChanged, "oldValue": "a", "newValue": "b"},
+			},
+		}, result)
+
+		var cm corev1.ConfigMap
+		require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "settings"}, &cm))
+		assert.Equal(t, "a", cm.Data["mode"])
+	})
+
+	t.Run("new object", func(t *testing.T) {
+		result, err := diff(graphql.ResolveParams{
+			Context: t.Context(),
+			Args: map[string]any{
+				NamespaceArg: "default",
+				ObjectArg: map[string]any{
+					"metadata": map[string]any{"name": "other"},
+					"data":     map[string]any{"mode": "b"},
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"exists": false,
+			"changes": []map[string]any{
+				{"path": "apiVersion", "type": FieldAdded, "oldValue": nil, "newValue": "v1"},
+				{"path": "data", "type": FieldAdded, "oldValue": nil, "newValue": map[string]any{"mode": "b"}},
+				{"path": "kind", "type": FieldAdded, "oldValue": nil, "newValue": "ConfigMap"},
+				{"path": "metadata", "type": FieldAdded, "oldValue": nil, "newValue": map[string]any{"name": "other", "namespace": "default"}},
+			},
+		}, result)
+	})
+}
+
+func TestFieldChanges_Lists(t *testing.T) {
+	before := map[string]any{"spec": map[string]any{
+		"containers": []any{map[string]any{"name": "app", "image": "app:1"}},
+		"args":       []any{"a"},
+	}}
+	after := map[string]any{"spec": map[string]any{
+		"containers": []any{map[string]any{"name": "app", "image": "app:2"}},
+		"args":       []any{"a", "b"},
+	}}
+
+	assert.Equal(t, []map[string]any{
+		{"path": "spec.args", "type": FieldChanged, "oldValue": []any{"a"}, "newValue": []any{"a", "b"}},
+		{"path": "spec.containers[0].image", "type": FieldChanged, "oldValue": "app:1", "newValue": "app:2"},
+	}, fieldChanges(before, after))
+}
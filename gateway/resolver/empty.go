@@ -0,0 +1,73 @@
+package resolver
+
+import (
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+)
+
+// EmptyValues selects how list and map fields of objects are returned when
+// they are empty or absent. The API server omits most empty fields but keeps
+// some, so without normalization the same state can be returned as either.
+type EmptyValues string
+
+const (
+	// EmptyValuesPreserve returns fields as stored: absent fields are null
+	// and empty fields are empty.
+	EmptyValuesPreserve EmptyValues = "preserve"
+	// EmptyValuesNull returns empty lists and maps as null.
+	EmptyValuesNull EmptyValues = "null"
+	// EmptyValuesEmpty returns absent lists and maps as empty ones.
+	EmptyValuesEmpty EmptyValues = "empty"
+)
+
+// WithEmptyValues sets how empty and absent list and map fields are returned.
+func (r *Service) WithEmptyValues(mode EmptyValues) *Service {
+	r.emptyValues = mode
+	return r
+}
+
+// EmptyValues returns how empty and absent list and map fields are returned.
+func (r *Service) EmptyValues() EmptyValues {
+	if r.emptyValues == "" {
+		return EmptyValuesPreserve
+	}
+	return r.emptyValues
+}
+
+// NormalizeEmpty wraps the resolver of a list or map field so that empty and
+// absent values are returned according to the mode of the service. newEmpty
+// returns the value an absent field is replaced with in EmptyValuesEmpty mode.
+// A nil resolver is treated as the default resolver.
+func (r *Service) NormalizeEmpty(resolve graphql.FieldResolveFn, newEmpty func() any) graphql.FieldResolveFn {
+	if resolve == nil {
+		resolve = graphql.DefaultResolveFn
+	}
+
+	switch r.EmptyValues() {
+	case EmptyValuesNull:
+		return func(p graphql.ResolveParams) (any, error) {
+			value, err := resolve(p)
+			if err != nil || value == nil {
+				return value, err
+			}
+			if v := reflect.ValueOf(value); (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0 {
+				return nil, nil
+			}
+			return value, nil
+		}
+	case EmptyValuesEmpty:
+		return func(p graphql.ResolveParams) (any, error) {
+			value, err := resolve(p)
+			if err != nil {
+				return nil, err
+			}
+			if v := reflect.ValueOf(value); !v.IsValid() || ((v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.IsNil()) {
+				return newEmpty(), nil
+			}
+			return value, nil
+		}
+	default:
+		return resolve
+	}
+}
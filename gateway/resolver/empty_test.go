@@ -0,0 +1,42 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeEmpty(t *testing.T) {
+	newEmpty := func() any { return []any{} }
+
+	tests := []struct {
+		name  string
+		mode  EmptyValues
+		value any
+		want  any
+	}{
+		{name: "preserve keeps absent", mode: EmptyValuesPreserve, value: nil, want: nil},
+		{name: "preserve keeps empty", mode: EmptyValuesPreserve, value: []any{}, want: []any{}},
+		{name: "unset mode preserves", value: []any{}, want: []any{}},
+		{name: "null replaces empty list", mode: EmptyValuesNull, value: []any{}, want: nil},
+		{name: "null replaces empty map", mode: EmptyValuesNull, value: map[string]string{}, want: nil},
+		{name: "null keeps values", mode: EmptyValuesNull, value: []any{"a"}, want: []any{"a"}},
+		{name: "empty replaces absent", mode: EmptyValuesEmpty, value: nil, want: []any{}},
+		{name: "empty replaces nil slice", mode: EmptyValuesEmpty, value: []string(nil), want: []any{}},
+		{name: "empty keeps values", mode: EmptyValuesEmpty, value: []any{"a"}, want: []any{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolve := New(nil).WithEmptyValues(tt.mode).NormalizeEmpty(func(graphql.ResolveParams) (any, error) {
+				return tt.value, nil
+			}, newEmpty)
+
+			got, err := resolve(graphql.ResolveParams{Context: t.Context()})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrorsExtensionName is the name of ErrorsExtension.
+const ErrorsExtensionName = "apiErrors"
+
+// errorCodes are the codes of the GraphQL errors of API server errors by
+// their reason.
+var errorCodes = map[metav1.StatusReason]string{
+	metav1.StatusReasonNotFound:           "NOT_FOUND",
+	metav1.StatusReasonForbidden:          "FORBIDDEN",
+	metav1.StatusReasonUnauthorized:       "UNAUTHENTICATED",
+	metav1.StatusReasonConflict:           ConflictCode,
+	metav1.StatusReasonAlreadyExists:      "ALREADY_EXISTS",
+	metav1.StatusReasonInvalid:            "INVALID",
+	metav1.StatusReasonBadRequest:         "BAD_REQUEST",
+	metav1.StatusReasonMethodNotAllowed:   "METHOD_NOT_ALLOWED",
+	metav1.StatusReasonGone:               "GONE",
+	metav1.StatusReasonExpired:            "GONE",
+	metav1.StatusReasonTooManyRequests:    "TOO_MANY_REQUESTS",
+	metav1.StatusReasonTimeout:            "TIMEOUT",
+	metav1.StatusReasonServerTimeout:      "TIMEOUT",
+	metav1.StatusReasonServiceUnavailable: "SERVICE_UNAVAILABLE",
+	metav1.StatusReasonInternalError:      "INTERNAL_SERVER_ERROR",
+}
+
+// ErrorExtensions returns the GraphQL error extensions of an error returned
+// by the API server, or nil for other errors:
+//
+//	"extensions": {"code": "INVALID", "reason": "Invalid", "details": {"kind": "ConfigMap", "name": "settings",
+//	  "fieldErrors": [{"field": "metadata.name", "type": "FieldValueInvalid", "message": "..."}]}}
+//
+// code is one of a fixed set of codes derived from the reason, which is
+// passed on as the API server sent it.
+func ErrorExtensions(err error) map[string]any {
+	var apiStatus apierrors.APIStatus
+	if !errors.As(err, &apiStatus) {
+		return nil
+	}
+	status := apiStatus.Status()
+
+	code, ok := errorCodes[status.Reason]
+	if !ok {
+		code = "BAD_REQUEST"
+		if status.Code >= http.StatusInternalServerError {
+			code = "INTERNAL_SERVER_ERROR"
+		}
+	}
+	extensions := map[string]any{"code": code, "reason": string(status.Reason)}
+
+	if status.Details == nil {
+		return extensions
+	}
+	details := map[string]any{}
+	if status.Details.Kind != "" {
+		details["kind"] = status.Details.Kind
+	}
+	if status.Details.Name != "" {
+		details["name"] = status.Details.Name
+	}
+	if status.Details.RetryAfterSeconds > 0 {
+		details["retryAfterSeconds"] = status.Details.RetryAfterSeconds
+	}
+	var fieldErrors []map[string]any
+	for _, cause := range status.Details.Causes {
+		fieldErrors = append(fieldErrors, map[string]any{
+			"field":   cause.Field,
+			"type":    string(cause.Type),
+			"message": cause.Message,
+		})
+	}
+	if len(fieldErrors) > 0 {
+		details["fieldErrors"] = fieldErrors
+	}
+	if len(details) > 0 {
+		extensions["details"] = details
+	}
+	return extensions
+}
+
+// AddErrorExtensions sets the extensions of the errors of API server errors
+// that have none, e.g. because they were wrapped or are the errors of a
+// subscription.
+func AddErrorExtensions(errs []gqlerrors.FormattedError) {
+	for i := range errs {
+		if errs[i].Extensions != nil {
+			continue
+		}
+		err := errs[i].OriginalError()
+		for {
+			located, ok := err.(*gqlerrors.Error)
+			if !ok || located.OriginalError == nil {
+				break
+			}
+			err = located.OriginalError
+		}
+		errs[i].Extensions = ErrorExtensions(err)
+	}
+}
+
+// ErrorsExtension is a graphql.Extension that adds the ErrorExtensions of API
+// server errors to the errors of queries and mutations, so clients can tell
+// e.g. missing objects from denied requests without parsing messages.
+// Subscription results are passed to AddErrorExtensions by the transports.
+type ErrorsExtension struct{}
+
+var _ graphql.Extension = ErrorsExtension{}
+
+func (ErrorsExtension) Init(ctx context.Context, _ *graphql.Params) context.Context {
+	return ctx
+}
+
+func (ErrorsExtension) Name() string {
+	return ErrorsExtensionName
+}
+
+func (ErrorsExtension) ParseDidStart(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+	return ctx, func(error) {}
+}
+
+func (ErrorsExtension) ValidationDidStart(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+	return ctx, func([]gqlerrors.FormattedError) {}
+}
+
+func (ErrorsExtension) ExecutionDidStart(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+	return ctx, func(result *graphql.Result) {
+		if result != nil {
+			AddErrorExtensions(result.Errors)
+		}
+	}
+}
+
+func (ErrorsExtension) ResolveFieldDidStart(ctx context.Context, _ *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+	return ctx, func(any, error) {}
+}
+
+func (ErrorsExtension) HasResult() bool {
+	return false
+}
+
+func (ErrorsExtension) GetResult(context.Context) any {
+	return nil
+}
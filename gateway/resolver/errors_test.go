@@ -0,0 +1,109 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestErrorExtensions(t *testing.T) {
+	configMaps := schema.GroupResource{Resource: "configmaps"}
+
+	tests := []struct {
+		name string
+		err  error
+		want map[string]any
+	}{
+		{
+			name: "not found",
+			err:  apierrors.NewNotFound(configMaps, "settings"),
+			want: map[string]any{"code": "NOT_FOUND", "reason": "NotFound", "details": map[string]any{"kind": "configmaps", "name": "settings"}},
+		},
+		{
+			name: "forbidden",
+			err:  apierrors.NewForbidden(configMaps, "", errors.New("denied")),
+			want: map[string]any{"code": "FORBIDDEN", "reason": "Forbidden", "details": map[string]any{"kind": "configmaps"}},
+		},
+		{
+			name: "invalid with field errors",
+			err: apierrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, "Settings", field.ErrorList{
+				field.Invalid(field.NewPath("metadata", "name"), "Settings", "must be lowercase"),
+			}),
+			want: map[string]any{"code": "INVALID", "reason": "Invalid", "details": map[string]any{
+				"kind": "ConfigMap",
+				"name": "Settings",
+				"fieldErrors": []map[string]any{
+					{"field": "metadata.name", "type": "FieldValueInvalid", "message": `Invalid value: "Settings": must be lowercase`},
+				},
+			}},
+		},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("failed to list: %w", apierrors.NewTooManyRequests("slow down", 3)),
+			want: map[string]any{"code": "TOO_MANY_REQUESTS", "reason": "TooManyRequests", "details": map[string]any{"retryAfterSeconds": int32(3)}},
+		},
+		{
+			name: "unknown reason",
+			err:  &apierrors.StatusError{ErrStatus: metav1.Status{Status: metav1.StatusFailure, Code: 502}},
+			want: map[string]any{"code": "INTERNAL_SERVER_ERROR", "reason": ""},
+		},
+		{
+			name: "not an API server error",
+			err:  errors.New("name is required"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ErrorExtensions(tt.err))
+		})
+	}
+}
+
+func TestErrorsExtension(t *testing.T) {
+	s, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"missing": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(graphql.ResolveParams) (any, error) {
+						return nil, fmt.Errorf("failed to get: %w", apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "web"))
+					},
+				},
+				"invalidArgument": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(graphql.ResolveParams) (any, error) {
+						return nil, errors.New("name is required")
+					},
+				},
+			},
+		}),
+		Extensions: []graphql.Extension{ErrorsExtension{}},
+	})
+	require.NoError(t, err)
+
+	result := graphql.Do(graphql.Params{Schema: s, RequestString: "{ missing invalidArgument }", Context: t.Context()})
+	require.Len(t, result.Errors, 2)
+	byMessage := map[string]gqlerrors.FormattedError{}
+	for _, e := range result.Errors {
+		byMessage[e.Message] = e
+	}
+	assert.Equal(t, "NOT_FOUND", byMessage[`failed to get: pods "web" not found`].Extensions["code"])
+	assert.Nil(t, byMessage["name is required"].Extensions)
+
+	// Subscriptions report the errors of their resolvers unwrapped.
+	errs := gqlerrors.FormatErrors(apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("denied")))
+	AddErrorExtensions(errs)
+	assert.Equal(t, "FORBIDDEN", errs[0].Extensions["code"])
+}
@@ -0,0 +1,67 @@
+package resolver
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EventLimits is the minimum interval between two events of an object of a
+// kind sent to a subscription. Events of an object arriving within it are
+// merged like with coalesceMs.
+type EventLimits map[schema.GroupKind]time.Duration
+
+// DroppedEventsFunc is called for each event of a kind merged into another
+// to enforce its event limit.
+type DroppedEventsFunc func(gk schema.GroupKind)
+
+// ParseEventLimits parses limits of the form Kind.group=interval, e.g.
+// Endpoints=1s or EndpointSlice.discovery.k8s.io=500ms.
+func ParseEventLimits(specs []string) (EventLimits, error) {
+	limits := EventLimits{}
+	for _, spec := range specs {
+		kind, interval, ok := strings.Cut(spec, "=")
+		if !ok || kind == "" {
+			return nil, fmt.Errorf("event limit %q must have the form Kind.group=interval", spec)
+		}
+		d, err := time.ParseDuration(interval)
+		if err != nil {
+			return nil, fmt.Errorf("event limit %q: %w", spec, err)
+		}
+		if d <= 0 || d > maxCoalesceWindow {
+			return nil, fmt.Errorf("event limit %q must be positive and at most %s", spec, maxCoalesceWindow)
+		}
+		limits[schema.ParseGroupKind(kind)] = d
+	}
+	return limits, nil
+}
+
+// WithEventLimits limits the events sent to subscriptions per object of the
+// kinds in limits, reporting merged events to dropped if it is not nil.
+func (r *Service) WithEventLimits(limits EventLimits, dropped DroppedEventsFunc) *Service {
+	r.eventLimits = limits
+	r.eventsDropped = dropped
+	return r
+}
+
+// eventWindow returns how long the events of an object of gvk are held back:
+// the window of the coalesceMs argument or the event limit of the kind,
+// whichever is longer. If the limit applies, the returned function reports
+// merged events as dropped, otherwise it is nil.
+func (r *Service) eventWindow(args map[string]any, gvk schema.GroupVersionKind) (time.Duration, func(), error) {
+	window, err := coalesceWindow(args)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	limit, limited := r.eventLimits[gvk.GroupKind()]
+	if !limited || limit < window {
+		return window, nil, nil
+	}
+	if r.eventsDropped == nil {
+		return limit, nil, nil
+	}
+	return limit, func() { r.eventsDropped(gvk.GroupKind()) }, nil
+}
@@ -0,0 +1,74 @@
+package resolver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestParseEventLimits(t *testing.T) {
+	limits, err := ParseEventLimits([]string{"Endpoints=1s", "EndpointSlice.discovery.k8s.io=500ms"})
+	require.NoError(t, err)
+	assert.Equal(t, EventLimits{
+		{Kind: "Endpoints"}: time.Second,
+		{Group: "discovery.k8s.io", Kind: "EndpointSlice"}: 500 * time.Millisecond,
+	}, limits)
+
+	for _, spec := range []string{"Endpoints", "=1s", "Endpoints=fast", "Endpoints=0s", "Endpoints=2m"} {
+		_, err := ParseEventLimits([]string{spec})
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestEventWindow(t *testing.T) {
+	endpoints := schema.GroupVersionKind{Version: "v1", Kind: "Endpoints"}
+	pods := schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+
+	var dropped []schema.GroupKind
+	r := New(nil).WithEventLimits(EventLimits{endpoints.GroupKind(): time.Second}, func(gk schema.GroupKind) {
+		dropped = append(dropped, gk)
+	})
+
+	window, merged, err := r.eventWindow(map[string]any{}, pods)
+	require.NoError(t, err)
+	assert.Zero(t, window)
+	assert.Nil(t, merged)
+
+	window, merged, err = r.eventWindow(map[string]any{CoalesceMsArg: 250}, endpoints)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, window)
+	require.NotNil(t, merged)
+	merged()
+	assert.Equal(t, []schema.GroupKind{endpoints.GroupKind()}, dropped)
+
+	// A longer coalesceMs is the client's choice, its merges are not dropped.
+	window, merged, err = r.eventWindow(map[string]any{CoalesceMsArg: 5000}, endpoints)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, window)
+	assert.Nil(t, merged)
+}
+
+func TestCoalesceEventsReportsMerged(t *testing.T) {
+	in := make(chan any)
+	merged := 0
+	out := coalesceEvents(t.Context(), in, time.Hour, func() { merged++ })
+
+	go func() {
+		for _, rv := range []string{"1", "2", "3"} {
+			in <- SubscriptionEnvelope{Type: EventTypeModified, Object: makeUnstructuredObj("a", "default", rv).Object}
+		}
+		close(in)
+	}()
+
+	var got []SubscriptionEnvelope
+	for v := range out {
+		got = append(got, v.(SubscriptionEnvelope))
+	}
+	require.Len(t, got, 1)
+	assert.Equal(t, 2, got[0].Suppressed)
+	assert.Equal(t, 2, merged)
+}
@@ -0,0 +1,116 @@
+package resolver
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// EventGVK is the core/v1 Event. events.k8s.io/v1 serves the same objects.
+var EventGVK = schema.GroupVersionKind{Version: "v1", Kind: "Event"}
+
+// involvedObjectUIDField is the field selector matching events of an object.
+const involvedObjectUIDField = "involvedObject.uid"
+
+// DefaultEventsLimit is the number of events returned when no limit is given.
+const DefaultEventsLimit = 20
+
+// WithMaxEvents caps the number of events a single events field lists. 0
+// disables the cap.
+func (r *Service) WithMaxEvents(maxEvents int) *Service {
+	r.maxEvents = maxEvents
+	return r
+}
+
+// ObjectEvents returns a resolver listing a page of the events of the object
+// the field belongs to, with the token to continue with. The events of a
+// page are ordered newest first. Events of cluster-scoped objects are looked
+// up in all namespaces.
+func (r *Service) ObjectEvents(gvk schema.GroupVersionKind) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, "ObjectEvents", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		source, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, errors.New("events are only available on objects")
+		}
+
+		obj := &unstructured.Unstructured{Object: source}
+		if obj.GetUID() == "" {
+			// Objects from dry-run creates have no UID yet.
+			return map[string]any{"items": []map[string]any{}, "continue": ""}, nil
+		}
+
+		limit, err := GetArg[int](p.Args, LimitArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if limit <= 0 {
+			limit = DefaultEventsLimit
+		}
+		if r.maxEvents > 0 && limit > r.maxEvents {
+			limit = r.maxEvents
+		}
+
+		continueToken, err := GetArg[string](p.Args, ContinueArg, false)
+		if err != nil {
+			return nil, err
+		}
+
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(EventGVK.GroupVersion().WithKind(EventGVK.Kind + "List"))
+
+		opts := []client.ListOption{
+			client.MatchingFields{involvedObjectUIDField: string(obj.GetUID())},
+			client.Limit(int64(limit)),
+			client.Continue(continueToken),
+		}
+		if ns := obj.GetNamespace(); ns != "" {
+			opts = append(opts, client.InNamespace(ns))
+		}
+
+		if err := r.runtimeClient.List(ctx, list, opts...); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to list events", "kind", gvk.Kind, "name", obj.GetName())
+			return nil, err
+		}
+
+		slices.SortStableFunc(list.Items, func(a, b unstructured.Unstructured) int {
+			return eventTime(b).Compare(eventTime(a))
+		})
+
+		events := make([]map[string]any, len(list.Items))
+		for i, item := range list.Items {
+			events[i] = item.Object
+		}
+		return map[string]any{"items": events, "continue": list.GetContinue()}, nil
+	}
+}
+
+// eventTime returns when an event was last observed. Events recorded with the
+// events.k8s.io API only carry eventTime and series.lastObservedTime.
+func eventTime(event unstructured.Unstructured) time.Time {
+	for _, path := range [][]string{
+		{"series", "lastObservedTime"},
+		{"lastTimestamp"},
+		{"eventTime"},
+		{"firstTimestamp"},
+		{"metadata", "creationTimestamp"},
+	} {
+		value, _, _ := unstructured.NestedString(event.Object, path...)
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,141 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestObjectEvents(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	event := func(name string, uid types.UID, last time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: name},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "web", UID: uid},
+			Reason:         name,
+			LastTimestamp:  metav1.NewTime(last),
+		}
+	}
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			event("scheduled", "uid-web", now.Add(-time.Minute)),
+			event("pulled", "uid-web", now),
+			event("started", "uid-web", now.Add(-30*time.Second)),
+			event("other", "uid-other", now),
+		).
+		WithIndex(&corev1.Event{}, involvedObjectUIDField, func(obj client.Object) []string {
+			// The fake client applies the index to the unstructured objects it lists.
+			uid, _, _ := unstructured.NestedString(obj.(*unstructured.Unstructured).Object, "involvedObject", "uid")
+			return []string{uid}
+		}).
+		Build()
+
+	resolve := New(cl).ObjectEvents(schema.GroupVersionKind{Version: "v1", Kind: "Pod"})
+	source := map[string]any{"metadata": map[string]any{"name": "web", "namespace": "default", "uid": "uid-web"}}
+
+	tests := []struct {
+		name   string
+		source map[string]any
+		args   map[string]any
+		want   []string
+	}{
+		{
+			name:   "events of the object, newest first",
+			source: source,
+			args:   map[string]any{},
+			want:   []string{"pulled", "started", "scheduled"},
+		},
+		{
+			name:   "object without a UID",
+			source: map[string]any{"metadata": map[string]any{"name": "web", "namespace": "default"}},
+			args:   map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := resolve(graphql.ResolveParams{Context: t.Context(), Source: tt.source, Args: tt.args})
+			require.NoError(t, err)
+
+			var reasons []string
+			for _, e := range result.(map[string]any)["items"].([]map[string]any) {
+				reasons = append(reasons, e["reason"].(string))
+			}
+			assert.Equal(t, tt.want, reasons)
+		})
+	}
+}
+
+func TestObjectEvents_Pagination(t *testing.T) {
+	source := map[string]any{"metadata": map[string]any{"name": "web", "namespace": "default", "uid": "uid-web"}}
+
+	tests := []struct {
+		name          string
+		maxEvents     int
+		args          map[string]any
+		wantLimit     int64
+		wantContinue  string
+		returnedToken string
+	}{
+		{
+			name:      "default limit",
+			args:      map[string]any{},
+			wantLimit: DefaultEventsLimit,
+		},
+		{
+			name:          "next page",
+			args:          map[string]any{LimitArg: 5, ContinueArg: "page-2"},
+			wantLimit:     5,
+			wantContinue:  "page-2",
+			returnedToken: "page-3",
+		},
+		{
+			name:      "limit capped by the server",
+			maxEvents: 50,
+			args:      map[string]any{LimitArg: 1000},
+			wantLimit: 50,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *client.ListOptions
+			fc := &fakeClient{
+				listFn: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+					got = &client.ListOptions{}
+					got.ApplyOptions(opts)
+					list.(*unstructured.UnstructuredList).SetContinue(tt.returnedToken)
+					return nil
+				},
+			}
+
+			result, err := (&Service{runtimeClient: fc}).WithMaxEvents(tt.maxEvents).ObjectEvents(schema.GroupVersionKind{Version: "v1", Kind: "Pod"})(graphql.ResolveParams{
+				Context: t.Context(),
+				Source:  source,
+				Args:    tt.args,
+			})
+			require.NoError(t, err)
+
+			require.NotNil(t, got)
+			assert.Equal(t, tt.wantLimit, got.Limit)
+			assert.Equal(t, tt.wantContinue, got.Continue)
+			assert.Equal(t, "default", got.Namespace)
+			assert.Equal(t, tt.returnedToken, result.(map[string]any)["continue"])
+		})
+	}
+}
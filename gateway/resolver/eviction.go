@@ -0,0 +1,99 @@
+package resolver
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/changefeed"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const evictionSubresource = "eviction"
+
+// evictionGVK is the kind posted to the eviction subresource of pods.
+var evictionGVK = schema.GroupVersionKind{Group: "policy", Version: "v1", Kind: "Eviction"}
+
+// EvictItem creates an Eviction for an object, which deletes it unless that
+// would violate a PodDisruptionBudget.
+func (r *Service) EvictItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		logger := log.FromContext(p.Context)
+		ctx, span := otel.Tracer("").Start(p.Context, "EvictItem", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		logger = logger.WithValues("operation", "evict", "kind", gvk.Kind)
+
+		name, err := GetArg[string](p.Args, NameArg, true)
+		if err != nil {
+			return nil, err
+		}
+
+		parent := &unstructured.Unstructured{}
+		parent.SetGroupVersionKind(gvk)
+		parent.SetName(name)
+
+		if isResourceNamespaceScoped(scope) {
+			namespace, err := GetArg[string](p.Args, NamespaceArg, true)
+			if err != nil {
+				return nil, err
+			}
+			parent.SetNamespace(namespace)
+		}
+
+		dryRunBool, err := GetArg[bool](p.Args, DryRunArg, false)
+		if err != nil {
+			return nil, err
+		}
+
+		eviction := &unstructured.Unstructured{}
+		eviction.SetGroupVersionKind(evictionGVK)
+		eviction.SetNamespace(parent.GetNamespace())
+		eviction.SetName(name)
+
+		deleteOptions := map[string]any{}
+		if dryRunBool {
+			deleteOptions["dryRun"] = []any{"All"}
+		}
+		if _, ok := p.Args[GracePeriodSecondsArg]; ok {
+			gracePeriod, err := GetArg[int](p.Args, GracePeriodSecondsArg, false)
+			if err != nil {
+				return nil, err
+			}
+			if gracePeriod < 0 {
+				return nil, fmt.Errorf("%s must not be negative", GracePeriodSecondsArg)
+			}
+			deleteOptions["gracePeriodSeconds"] = int64(gracePeriod)
+		}
+		if len(deleteOptions) > 0 {
+			eviction.Object["deleteOptions"] = deleteOptions
+		}
+
+		var createOpts []client.SubResourceCreateOption
+		if dryRunBool {
+			createOpts = append(createOpts, client.DryRunAll)
+		}
+
+		if err := r.runtimeClient.SubResource(evictionSubresource).Create(ctx, parent, eviction, createOpts...); err != nil {
+			logger.Error(err, "Failed to evict object")
+			if apierrors.IsTooManyRequests(err) {
+				return nil, fmt.Errorf("eviction of %s %s is blocked by a disruption budget, retry later: %w", gvk.Kind, name, err)
+			}
+			return nil, err
+		}
+
+		if !dryRunBool {
+			r.recordChange(ctx, changefeed.OperationDelete, gvk, parent)
+		}
+
+		return true, nil
+	}
+}
@@ -0,0 +1,100 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestEvictItem(t *testing.T) {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	pdbErr := apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10)
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		createErr     error
+		wantOptions   map[string]any
+		wantDryRun    []string
+		wantErr       string
+		wantNoRequest bool
+	}{
+		{
+			name: "eviction is created",
+			args: map[string]any{NameArg: "web", NamespaceArg: "default"},
+		},
+		{
+			name:        "dry run with grace period",
+			args:        map[string]any{NameArg: "web", NamespaceArg: "default", DryRunArg: true, GracePeriodSecondsArg: 5},
+			wantOptions: map[string]any{"dryRun": []any{"All"}, "gracePeriodSeconds": int64(5)},
+			wantDryRun:  []string{"All"},
+		},
+		{
+			name:      "blocked by a disruption budget",
+			args:      map[string]any{NameArg: "web", NamespaceArg: "default"},
+			createErr: pdbErr,
+			wantErr:   "eviction of Pod web is blocked by a disruption budget, retry later: " + pdbErr.Error(),
+		},
+		{
+			name:          "negative grace period",
+			args:          map[string]any{NameArg: "web", NamespaceArg: "default", GracePeriodSecondsArg: -1},
+			wantErr:       "gracePeriodSeconds must not be negative",
+			wantNoRequest: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				calls    int
+				parent   client.Object
+				eviction *unstructured.Unstructured
+				opts     client.SubResourceCreateOptions
+			)
+			cl := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceCreate: func(_ context.Context, _ client.Client, subResource string, obj, body client.Object, createOpts ...client.SubResourceCreateOption) error {
+					calls++
+					assert.Equal(t, "eviction", subResource)
+					parent = obj
+					eviction = body.(*unstructured.Unstructured)
+					opts.ApplyOptions(createOpts)
+					return tt.createErr
+				},
+			}).Build()
+
+			result, err := New(cl).EvictItem(gvk, v1.NamespaceScoped)(graphql.ResolveParams{Context: t.Context(), Args: tt.args})
+			if tt.wantNoRequest {
+				assert.Zero(t, calls)
+			}
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, true, result)
+
+			require.Equal(t, 1, calls)
+			assert.Equal(t, client.ObjectKey{Namespace: "default", Name: "web"}, client.ObjectKeyFromObject(parent))
+			assert.Equal(t, evictionGVK, eviction.GroupVersionKind())
+			assert.Equal(t, client.ObjectKey{Namespace: "default", Name: "web"}, client.ObjectKeyFromObject(eviction))
+			deleteOptions, _, _ := unstructured.NestedFieldNoCopy(eviction.Object, "deleteOptions")
+			if tt.wantOptions == nil {
+				assert.Nil(t, deleteOptions)
+			} else {
+				assert.Equal(t, tt.wantOptions, deleteOptions)
+			}
+			assert.Equal(t, tt.wantDryRun, opts.DryRun)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package resolver
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// RepresentationsArg is the argument of the _entities query.
+const RepresentationsArg = "representations"
+
+// WithFederation sets whether schemas include the queries and types of an
+// Apollo Federation subgraph.
+func (r *Service) WithFederation(enabled bool) *Service {
+	r.federation = enabled
+	return r
+}
+
+// Federation reports whether schemas include the queries and types of an
+// Apollo Federation subgraph.
+func (r *Service) Federation() bool {
+	return r.federation
+}
+
+// Entities returns the resolver of the _entities query of Apollo Federation,
+// reading the object each representation refers to by its __typename and
+// metadata name and namespace. typeKinds maps the names of the resource
+// types to their kinds. Null is returned for objects that do not exist.
+func (r *Service) Entities(typeKinds map[string]schema.GroupVersionKind, kinds map[schema.GroupKind]ServedKind) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		representations, err := GetArg[[]any](p.Args, RepresentationsArg, true)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, span := otel.Tracer("").Start(p.Context, "Entities", trace.WithAttributes(
+			attribute.Int("representations", len(representations)),
+		))
+		defer span.End()
+
+		entities := make([]any, len(representations))
+		for i, representation := range representations {
+			rep, ok := representation.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("representation %d is not an object", i)
+			}
+			typeName, _ := rep["__typename"].(string)
+			gvk, ok := typeKinds[typeName]
+			if !ok {
+				return nil, fmt.Errorf("representation %d: type %q is not an entity of this schema", i, typeName)
+			}
+			served, ok := kinds[gvk.GroupKind()]
+			if !ok {
+				return nil, fmt.Errorf("representation %d: kind %s is not served by this schema", i, gvk.GroupKind())
+			}
+
+			name, _, _ := unstructured.NestedString(rep, "metadata", "name")
+			if name == "" {
+				return nil, fmt.Errorf("representation %d of %s has no metadata.name", i, typeName)
+			}
+			key := client.ObjectKey{Name: name}
+			if isResourceNamespaceScoped(served.Scope) {
+				key.Namespace, _, _ = unstructured.NestedString(rep, "metadata", "namespace")
+				if key.Namespace == "" {
+					return nil, fmt.Errorf("representation %d of %s %q has no metadata.namespace", i, typeName, name)
+				}
+			}
+
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(gvk)
+			if err := r.runtimeClient.Get(ctx, key, obj); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				log.FromContext(ctx).Error(err, "Failed to get entity", "kind", gvk.Kind, "name", name)
+				return nil, err
+			}
+			entities[i] = obj.Object
+		}
+		return entities, nil
+	}
+}
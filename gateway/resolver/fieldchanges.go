@@ -0,0 +1,129 @@
+package resolver
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	schematypes "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+)
+
+// requestedFields returns the fields of the objects selected by a
+// subscription as paths of property names, including the fields selected in
+// fragments. The fields selected in a list are paths below the list and
+// apply to each of its items.
+func (r *Service) requestedFields(info graphql.ResolveInfo) *fieldpath.Set {
+	set := &fieldpath.Set{}
+	envelope, ok := graphql.GetNamed(info.ReturnType).(*graphql.Object)
+	if !ok {
+		return set
+	}
+	objectField, ok := envelope.Fields()["object"]
+	if !ok {
+		return set
+	}
+
+	for _, fieldAST := range info.FieldASTs {
+		if fieldAST.SelectionSet == nil {
+			continue
+		}
+		for _, field := range selectedFields(fieldAST.SelectionSet, info.Fragments) {
+			if field.Name.Value == "object" {
+				r.addSelection(set, nil, field.SelectionSet, objectField.Type, info.Fragments)
+			}
+		}
+	}
+	return set
+}
+
+// addSelection adds the paths of the fields of selectionSet, selected on a
+// field of type parent at prefix, to set.
+func (r *Service) addSelection(set *fieldpath.Set, prefix fieldpath.Path, selectionSet *ast.SelectionSet, parent graphql.Type, fragments map[string]ast.Definition) {
+	object, _ := graphql.GetNamed(parent).(*graphql.Object)
+
+	for _, field := range selectedFields(selectionSet, fragments) {
+		name := field.Name.Value
+		if name == "__typename" {
+			continue
+		}
+
+		var fieldType graphql.Type
+		if object != nil {
+			if def, ok := object.Fields()[name]; ok {
+				fieldType = def.Type
+			}
+			name = r.fieldNames.Property(schematypes.InputTypeName(object.Name()), name)
+		}
+
+		path := append(append(fieldpath.Path{}, prefix...), fieldpath.PathElement{FieldName: &name})
+		// The entries of maps are listed in the order of their keys, so the
+		// whole map is compared.
+		if field.SelectionSet == nil || len(field.SelectionSet.Selections) == 0 || schematypes.IsMapEntries(fieldType) {
+			set.Insert(path)
+			continue
+		}
+		r.addSelection(set, path, field.SelectionSet, fieldType, fragments)
+	}
+}
+
+// selectedFields returns the fields of a selection set, with the fields of
+// inline fragments and fragment spreads.
+func selectedFields(selectionSet *ast.SelectionSet, fragments map[string]ast.Definition) []*ast.Field {
+	if selectionSet == nil {
+		return nil
+	}
+
+	var fields []*ast.Field
+	for _, selection := range selectionSet.Selections {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			fields = append(fields, sel)
+		case *ast.InlineFragment:
+			fields = append(fields, selectedFields(sel.SelectionSet, fragments)...)
+		case *ast.FragmentSpread:
+			if fragment, ok := fragments[sel.Name.Value].(*ast.FragmentDefinition); ok {
+				fields = append(fields, selectedFields(fragment.SelectionSet, fragments)...)
+			}
+		}
+	}
+	return fields
+}
+
+// fieldValues returns the values of the fields in set of a value of an
+// object. The fields below a list are selected in each of its items, and
+// values that are not objects where fields are selected are kept as a whole.
+func fieldValues(value any, set *fieldpath.Set) any {
+	switch v := value.(type) {
+	case map[string]any:
+		selected := map[string]any{}
+		for pe := range set.Members.All() {
+			if pe.FieldName == nil {
+				continue
+			}
+			if fieldValue, ok := v[*pe.FieldName]; ok {
+				selected[*pe.FieldName] = fieldValue
+			}
+		}
+		for pe := range set.Children.All() {
+			if pe.FieldName == nil {
+				continue
+			}
+			if _, ok := selected[*pe.FieldName]; ok {
+				continue
+			}
+			child, _ := set.Children.Get(pe)
+			if fieldValue, ok := v[*pe.FieldName]; ok {
+				selected[*pe.FieldName] = fieldValues(fieldValue, child)
+			}
+		}
+		return selected
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = fieldValues(item, set)
+		}
+		return items
+	default:
+		return value
+	}
+}
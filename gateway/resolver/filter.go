@@ -0,0 +1,172 @@
+package resolver
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	schematypes "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const FilterArg = "filter"
+
+// The operators of a filter.
+const (
+	FilterEqual       = "EQ"
+	FilterNotEqual    = "NE"
+	FilterGreaterThan = "GT"
+	FilterLessThan    = "LT"
+	FilterContains    = "CONTAINS"
+	FilterExists      = "EXISTS"
+)
+
+// FilterOperatorEnum lists the operators of the filter argument.
+var FilterOperatorEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "FilterOperator",
+	Description: "How a filter compares the values at its path with its value",
+	Values: graphql.EnumValueConfigMap{
+		FilterEqual:       &graphql.EnumValueConfig{Value: FilterEqual, Description: "A value equals value"},
+		FilterNotEqual:    &graphql.EnumValueConfig{Value: FilterNotEqual, Description: "No value equals value, also true if the path has no value"},
+		FilterGreaterThan: &graphql.EnumValueConfig{Value: FilterGreaterThan, Description: "A value is greater than value"},
+		FilterLessThan:    &graphql.EnumValueConfig{Value: FilterLessThan, Description: "A value is less than value"},
+		FilterContains:    &graphql.EnumValueConfig{Value: FilterContains, Description: "A string value contains value, or a list value has an item equal to value"},
+		FilterExists:      &graphql.EnumValueConfig{Value: FilterExists, Description: "The path has a value; value is ignored"},
+	},
+})
+
+// FilterInput is an expression of the filter argument.
+var FilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "FilterInput",
+	Description: "An expression objects must match",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"path": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: `A JSONPath such as .status.phase or .status.conditions[?(@.type=="Ready")].status, with Kubernetes field names; the leading dot is optional`,
+		},
+		"op": &graphql.InputObjectFieldConfig{
+			Type:         FilterOperatorEnum,
+			DefaultValue: FilterEqual,
+		},
+		"value": &graphql.InputObjectFieldConfig{
+			Type:        schematypes.JSONScalar,
+			Description: "The value to compare with, required except for EXISTS",
+		},
+	},
+})
+
+// FilterArgConfig filters lists by expressions evaluated by the gateway.
+var FilterArgConfig = &graphql.ArgumentConfig{
+	Type:        graphql.NewList(graphql.NewNonNull(FilterInput)),
+	Description: "Expressions the returned objects must all match, evaluated by the gateway on each page returned by the API server, so a page may have fewer than limit items",
+}
+
+// listFilter is a parsed expression of the filter argument.
+type listFilter struct {
+	path  *jsonpath.JSONPath
+	op    string
+	value any
+}
+
+// getFilters returns the expressions of the filter argument.
+func getFilters(args map[string]any) ([]listFilter, error) {
+	entries, _ := args[FilterArg].([]any)
+	filters := make([]listFilter, 0, len(entries))
+	for _, entry := range entries {
+		input, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s entry: %v", FilterArg, entry)
+		}
+
+		path, _ := input["path"].(string)
+		if path == "" {
+			return nil, fmt.Errorf("%s path is required", FilterArg)
+		}
+		expression := strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}")
+		if !strings.HasPrefix(expression, ".") {
+			expression = "." + expression
+		}
+		jp := jsonpath.New(FilterArg).AllowMissingKeys(true)
+		if err := jp.Parse("{" + expression + "}"); err != nil {
+			return nil, fmt.Errorf("invalid %s path %q: %w", FilterArg, path, err)
+		}
+
+		op, _ := input["op"].(string)
+		if op == "" {
+			op = FilterEqual
+		}
+		value, hasValue := input["value"]
+		if op != FilterExists && (!hasValue || value == nil) {
+			return nil, fmt.Errorf("%s %s on %q requires a value", FilterArg, op, path)
+		}
+
+		filters = append(filters, listFilter{path: jp, op: op, value: value})
+	}
+	return filters, nil
+}
+
+// filterItems returns the items matching all filters.
+func filterItems(items []unstructured.Unstructured, filters []listFilter) []unstructured.Unstructured {
+	if len(filters) == 0 {
+		return items
+	}
+	return slices.DeleteFunc(items, func(item unstructured.Unstructured) bool {
+		return slices.ContainsFunc(filters, func(f listFilter) bool { return !f.matches(item) })
+	})
+}
+
+// matches reports whether the values of obj at the path of f satisfy f.
+func (f listFilter) matches(obj unstructured.Unstructured) bool {
+	var values []any
+	if results, err := f.path.FindResults(obj.Object); err == nil {
+		for _, result := range results {
+			for _, value := range result {
+				values = append(values, value.Interface())
+			}
+		}
+	}
+
+	switch f.op {
+	case FilterExists:
+		return len(values) > 0
+	case FilterNotEqual:
+		return !slices.ContainsFunc(values, func(v any) bool { return filterValuesEqual(v, f.value) })
+	}
+	return slices.ContainsFunc(values, func(v any) bool {
+		switch f.op {
+		case FilterEqual:
+			return filterValuesEqual(v, f.value)
+		case FilterGreaterThan:
+			return compareValues(v, f.value) > 0
+		case FilterLessThan:
+			return compareValues(v, f.value) < 0
+		case FilterContains:
+			switch v := v.(type) {
+			case string:
+				s, ok := f.value.(string)
+				return ok && strings.Contains(v, s)
+			case []any:
+				return slices.ContainsFunc(v, func(item any) bool { return filterValuesEqual(item, f.value) })
+			}
+		}
+		return false
+	})
+}
+
+// filterValuesEqual compares numbers by value regardless of their type and
+// other values exactly.
+func filterValuesEqual(a, b any) bool {
+	switch a.(type) {
+	case int64, float64:
+		switch b.(type) {
+		case int64, float64:
+			return compareValues(a, b) == 0
+		}
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
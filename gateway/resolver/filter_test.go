@@ -0,0 +1,127 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestListItems_Filter(t *testing.T) {
+	pod := func(name, phase, ready string, restarts int64, finalizers ...any) unstructured.Unstructured {
+		obj := unstructured.Unstructured{Object: map[string]any{
+			"metadata": map[string]any{"name": name, "namespace": "default"},
+			"status": map[string]any{
+				"phase":        phase,
+				"restartCount": restarts,
+				"conditions":   []any{map[string]any{"type": "Ready", "status": ready}},
+			},
+		}}
+		if len(finalizers) > 0 {
+			obj.Object["metadata"].(map[string]any)["finalizers"] = finalizers
+		}
+		return obj
+	}
+
+	fc := &fakeClient{
+		listFn: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+			list.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{
+				pod("a", "Running", "True", 0),
+				pod("b", "Pending", "False", 3, "example.com/cleanup"),
+				pod("c", "Running", "False", 12),
+			}
+			return nil
+		},
+	}
+	svc := &Service{runtimeClient: fc}
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+
+	filter := func(path, op string, value any) any {
+		return map[string]any{"path": path, "op": op, "value": value}
+	}
+
+	tests := []struct {
+		name    string
+		filter  []any
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "equal",
+			filter: []any{filter("status.phase", FilterEqual, "Running")},
+			want:   []string{"a", "c"},
+		},
+		{
+			name:   "all expressions match",
+			filter: []any{filter("status.phase", FilterEqual, "Running"), filter(`.status.conditions[?(@.type=="Ready")].status`, FilterNotEqual, "True")},
+			want:   []string{"c"},
+		},
+		{
+			name:   "numbers of different types",
+			filter: []any{filter("{.status.restartCount}", FilterGreaterThan, float64(2))},
+			want:   []string{"b", "c"},
+		},
+		{
+			name:   "less than",
+			filter: []any{filter("status.restartCount", FilterLessThan, int64(10))},
+			want:   []string{"a", "b"},
+		},
+		{
+			name:   "contains a substring",
+			filter: []any{filter("status.phase", FilterContains, "end")},
+			want:   []string{"b"},
+		},
+		{
+			name:   "contains a list item",
+			filter: []any{filter("metadata.finalizers", FilterContains, "example.com/cleanup")},
+			want:   []string{"b"},
+		},
+		{
+			name:   "exists",
+			filter: []any{map[string]any{"path": "metadata.finalizers", "op": FilterExists}},
+			want:   []string{"b"},
+		},
+		{
+			name:   "not equal matches missing values",
+			filter: []any{filter("metadata.finalizers[*]", FilterNotEqual, "example.com/cleanup")},
+			want:   []string{"a", "c"},
+		},
+		{
+			name:    "missing value",
+			filter:  []any{map[string]any{"path": "status.phase", "op": FilterEqual}},
+			wantErr: `filter EQ on "status.phase" requires a value`,
+		},
+		{
+			name:    "invalid JSONPath",
+			filter:  []any{filter("status[?(", FilterEqual, "x")},
+			wantErr: `invalid filter path "status[?("`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := svc.ListItems(gvk, v1.NamespaceScoped, nil)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    map[string]any{FilterArg: tt.filter},
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var names []string
+			for _, item := range out.(*ListResult).Items {
+				names = append(names, item["metadata"].(map[string]any)["name"].(string))
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
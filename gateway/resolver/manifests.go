@@ -0,0 +1,270 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/changefeed"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"gopkg.in/yaml.v3"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// The operations reported for the documents of an applyManifests mutation.
+const (
+	ManifestCreated   = "CREATED"
+	ManifestUpdated   = "UPDATED"
+	ManifestUnchanged = "UNCHANGED"
+	ManifestFailed    = "FAILED"
+	ManifestSkipped   = "SKIPPED"
+)
+
+// manifestOperations maps the results of applyManifest to the operations
+// reported for a document.
+var manifestOperations = map[controllerutil.OperationResult]string{
+	controllerutil.OperationResultCreated: ManifestCreated,
+	controllerutil.OperationResultUpdated: ManifestUpdated,
+	controllerutil.OperationResultNone:    ManifestUnchanged,
+}
+
+// ApplyManifestsArgs returns arguments for the applyManifests mutation
+func ApplyManifestsArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		YamlArg: &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "YAML manifests to apply, one or more documents separated by ---",
+		},
+		NamespaceArg: &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "The namespace of namespaced objects that do not set metadata.namespace",
+		},
+		DryRunArg: DryRunArgConfig,
+	}
+}
+
+// manifest is a document of an applyManifests mutation and its result.
+type manifest struct {
+	obj *unstructured.Unstructured
+	err error
+}
+
+// ApplyManifests returns a resolver that applies the documents of a
+// multi-document YAML string like the deprecated applyYaml. All documents are decoded and
+// authorized first, and none is applied if one of them is invalid or may not
+// be created or updated by the user. The documents are then applied in order,
+// and a failed document does not stop the following ones. The result lists
+// the outcome of each document.
+func (r *Service) ApplyManifests() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, "ApplyManifests")
+		defer span.End()
+
+		logger := log.FromContext(ctx).WithValues("operation", "applyManifests")
+
+		yamlStr, err := GetArg[string](p.Args, YamlArg, true)
+		if err != nil {
+			return nil, err
+		}
+		namespace, err := GetArg[string](p.Args, NamespaceArg, false)
+		if err != nil {
+			return nil, err
+		}
+		dryRun, err := GetArg[bool](p.Args, DryRunArg, false)
+		if err != nil {
+			return nil, err
+		}
+
+		documents, err := parseYAMLDocuments(yamlStr)
+		if err != nil {
+			return nil, err
+		}
+		span.SetAttributes(attribute.Int("documents", len(documents)))
+
+		manifests := make([]manifest, len(documents))
+		authorized := true
+		for i, document := range documents {
+			manifests[i].obj = &unstructured.Unstructured{Object: document}
+			if err := validateManifest(document); err != nil {
+				manifests[i].err = err
+			} else {
+				manifests[i].err = r.authorizeManifest(ctx, manifests[i].obj, namespace)
+			}
+			authorized = authorized && manifests[i].err == nil
+		}
+
+		c := client.Client(r.runtimeClient)
+		if dryRun {
+			c = client.NewDryRunClient(c)
+		}
+
+		results := make([]map[string]any, len(manifests))
+		for i, m := range manifests {
+			result := map[string]any{
+				"index":      i,
+				"apiVersion": m.obj.GetAPIVersion(),
+				"kind":       m.obj.GetKind(),
+				"namespace":  m.obj.GetNamespace(),
+				"name":       m.obj.GetName(),
+			}
+			results[i] = result
+
+			if m.err != nil {
+				setManifestError(result, m.err)
+				continue
+			}
+			if !authorized {
+				result["operation"] = ManifestSkipped
+				continue
+			}
+
+			gvk := m.obj.GroupVersionKind()
+			applied, operation, err := applyManifest(ctx, c, m.obj)
+			if err != nil {
+				logger.Error(err, "Failed to apply manifest", "index", i, "kind", gvk.Kind, "name", m.obj.GetName())
+				setManifestError(result, err)
+				continue
+			}
+
+			result["operation"] = manifestOperations[operation]
+			result["name"] = applied.GetName()
+			result["object"] = applied.Object
+
+			if dryRun {
+				continue
+			}
+			switch operation {
+			case controllerutil.OperationResultCreated:
+				r.recordChange(ctx, changefeed.OperationCreate, gvk, applied)
+			case controllerutil.OperationResultUpdated:
+				r.recordChange(ctx, changefeed.OperationUpdate, gvk, applied)
+			}
+		}
+
+		return results, nil
+	}
+}
+
+func setManifestError(result map[string]any, err error) {
+	result["operation"] = ManifestFailed
+	result["error"] = err.Error()
+	if code, ok := ErrorExtensions(err)["code"]; ok {
+		result["code"] = code
+	}
+}
+
+// parseYAMLDocuments decodes the documents of a multi-document YAML string,
+// skipping empty documents.
+func parseYAMLDocuments(yamlStr string) ([]map[string]any, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(yamlStr)))
+
+	var documents []map[string]any
+	for {
+		var document map[string]any
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML in document %d: %w", len(documents), err)
+		}
+		if document != nil {
+			documents = append(documents, document)
+		}
+	}
+
+	if len(documents) == 0 {
+		return nil, errors.New("no YAML documents to apply")
+	}
+	return documents, nil
+}
+
+// authorizeManifest sets the namespace of a namespaced obj without one to
+// namespace and checks with a SelfSubjectAccessReview that the user of ctx
+// may create obj or, if it exists, update it.
+func (r *Service) authorizeManifest(ctx context.Context, obj *unstructured.Unstructured, namespace string) error {
+	gvk := obj.GroupVersionKind()
+	mapping, err := r.runtimeClient.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return fmt.Errorf("failed to find resource of %s: %w", gvk.GroupKind(), err)
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace && obj.GetNamespace() == "" {
+		if namespace == "" {
+			return fmt.Errorf("metadata.namespace is required for %s", gvk.Kind)
+		}
+		obj.SetNamespace(namespace)
+	}
+
+	verb := "create"
+	if obj.GetName() != "" {
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(gvk)
+		err := r.runtimeClient.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+		switch {
+		case err == nil:
+			verb = "update"
+		case !apierrors.IsNotFound(err):
+			return err
+		}
+	}
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:      verb,
+				Group:     gvk.Group,
+				Version:   gvk.Version,
+				Resource:  mapping.Resource.Resource,
+				Namespace: obj.GetNamespace(),
+				Name:      obj.GetName(),
+			},
+		},
+	}
+	if err := r.runtimeClient.Create(ctx, review); err != nil {
+		return fmt.Errorf("failed to check permission to %s %s: %w", verb, mapping.Resource.GroupResource(), err)
+	}
+	if !review.Status.Allowed {
+		return apierrors.NewForbidden(mapping.Resource.GroupResource(), obj.GetName(), fmt.Errorf("not allowed to %s it", verb))
+	}
+	return nil
+}
+
+// applyManifest creates obj if it has no name or does not exist yet, and
+// otherwise replaces the existing object with it.
+func applyManifest(ctx context.Context, c client.Client, obj *unstructured.Unstructured) (*unstructured.Unstructured, controllerutil.OperationResult, error) {
+	if obj.GetName() == "" {
+		if err := c.Create(ctx, obj); err != nil {
+			return nil, controllerutil.OperationResultNone, err
+		}
+		return obj, controllerutil.OperationResultCreated, nil
+	}
+
+	target := &unstructured.Unstructured{}
+	target.SetGroupVersionKind(obj.GroupVersionKind())
+	target.SetName(obj.GetName())
+	target.SetNamespace(obj.GetNamespace())
+
+	result, err := controllerutil.CreateOrUpdate(ctx, c, target, func() error {
+		rv := target.GetResourceVersion()
+		uid := target.GetUID()
+		target.Object = obj.Object
+		target.SetResourceVersion(rv)
+		target.SetUID(uid)
+		return nil
+	})
+	if err != nil {
+		return nil, controllerutil.OperationResultNone, err
+	}
+	return target, result, nil
+}
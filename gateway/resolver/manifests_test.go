@@ -0,0 +1,138 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const testManifests = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: existing
+  namespace: team-a
+data:
+  mode: b
+---
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: added
+data:
+  mode: a
+`
+
+func TestApplyManifests(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           map[string]any
+		denied         string
+		wantOperations []string
+		wantCodes      []any
+		wantCreated    bool
+		wantErr        string
+	}{
+		{
+			name:           "creates and updates objects",
+			args:           map[string]any{YamlArg: testManifests, NamespaceArg: "team-a"},
+			wantOperations: []string{ManifestUpdated, ManifestCreated},
+			wantCodes:      []any{nil, nil},
+			wantCreated:    true,
+		},
+		{
+			name:           "dry run",
+			args:           map[string]any{YamlArg: testManifests, NamespaceArg: "team-a", DryRunArg: true},
+			wantOperations: []string{ManifestUpdated, ManifestCreated},
+			wantCodes:      []any{nil, nil},
+		},
+		{
+			name:           "a denied document stops all documents",
+			args:           map[string]any{YamlArg: testManifests, NamespaceArg: "team-a"},
+			denied:         "create",
+			wantOperations: []string{ManifestSkipped, ManifestFailed},
+			wantCodes:      []any{nil, "FORBIDDEN"},
+		},
+		{
+			name:           "namespace required",
+			args:           map[string]any{YamlArg: testManifests},
+			wantOperations: []string{ManifestSkipped, ManifestFailed},
+			wantCodes:      []any{nil, nil},
+		},
+		{
+			name:    "invalid YAML",
+			args:    map[string]any{YamlArg: "a: [\n---\nb: c"},
+			wantErr: "invalid YAML in document 0",
+		},
+		{
+			name:    "no documents",
+			args:    map[string]any{YamlArg: "---\n"},
+			wantErr: "no YAML documents to apply",
+		},
+	}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithRESTMapper(mapper).
+				WithObjects(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "existing"},
+					Data:       map[string]string{"mode": "a"},
+				}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						if review, ok := obj.(*authorizationv1.SelfSubjectAccessReview); ok {
+							review.Status.Allowed = review.Spec.ResourceAttributes.Verb != tt.denied
+							return nil
+						}
+						return c.Create(ctx, obj, opts...)
+					},
+				}).
+				Build()
+
+			result, err := New(cl).ApplyManifests()(graphql.ResolveParams{Context: t.Context(), Args: tt.args})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			results := result.([]map[string]any)
+			require.Len(t, results, len(tt.wantOperations))
+			for i, r := range results {
+				assert.Equal(t, i, r["index"])
+				assert.Equal(t, tt.wantOperations[i], r["operation"], r["error"])
+				assert.Equal(t, tt.wantCodes[i], r["code"])
+			}
+
+			var existing corev1.ConfigMap
+			require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "team-a", Name: "existing"}, &existing))
+			err = cl.Get(t.Context(), client.ObjectKey{Namespace: "team-a", Name: "added"}, &corev1.ConfigMap{})
+			if tt.wantCreated {
+				assert.NoError(t, err)
+				assert.Equal(t, "b", existing.Data["mode"])
+			} else {
+				assert.True(t, apierrors.IsNotFound(err))
+				assert.Equal(t, "a", existing.Data["mode"])
+			}
+		})
+	}
+}
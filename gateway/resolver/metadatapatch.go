@@ -0,0 +1,179 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/changefeed"
+	schematypes "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	AddLabelsArg         = "addLabels"
+	RemoveLabelsArg      = "removeLabels"
+	AddAnnotationsArg    = "addAnnotations"
+	RemoveAnnotationsArg = "removeAnnotations"
+	AddFinalizersArg     = "addFinalizers"
+	RemoveFinalizersArg  = "removeFinalizers"
+)
+
+// PatchMetadataArgs returns arguments for metadata patch mutations.
+func PatchMetadataArgs(scope v1.ResourceScope) graphql.FieldConfigArgument {
+	args := ItemArgs(scope)
+	args[AddLabelsArg] = &graphql.ArgumentConfig{
+		Type:        schematypes.StringMapScalar,
+		Description: "Labels to set, replacing the values of existing keys",
+	}
+	args[RemoveLabelsArg] = &graphql.ArgumentConfig{
+		Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+		Description: "Keys of labels to remove",
+	}
+	args[AddAnnotationsArg] = &graphql.ArgumentConfig{
+		Type:        schematypes.StringMapScalar,
+		Description: "Annotations to set, replacing the values of existing keys",
+	}
+	args[RemoveAnnotationsArg] = &graphql.ArgumentConfig{
+		Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+		Description: "Keys of annotations to remove",
+	}
+	args[AddFinalizersArg] = &graphql.ArgumentConfig{
+		Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+		Description: "Finalizers to add unless the object has them",
+	}
+	args[RemoveFinalizersArg] = &graphql.ArgumentConfig{
+		Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+		Description: "Finalizers to remove",
+	}
+	args[DryRunArg] = DryRunArgConfig
+	return args
+}
+
+// PatchItemMetadata adds and removes labels, annotations and finalizers of an
+// object with a merge patch. As merge patches replace lists, finalizers are
+// changed on the list read before, and the patch fails with a conflict if the
+// object was changed since.
+func (r *Service) PatchItemMetadata(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		logger := log.FromContext(p.Context)
+		ctx, span := otel.Tracer("").Start(p.Context, "PatchItemMetadata", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		logger = logger.WithValues("operation", "patchMetadata", "kind", gvk.Kind)
+
+		name, err := GetArg[string](p.Args, NameArg, true)
+		if err != nil {
+			return nil, err
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		obj.SetName(name)
+
+		if isResourceNamespaceScoped(scope) {
+			namespace, err := GetArg[string](p.Args, NamespaceArg, true)
+			if err != nil {
+				return nil, err
+			}
+			obj.SetNamespace(namespace)
+		}
+
+		lists := map[string][]string{}
+		for _, key := range []string{RemoveLabelsArg, RemoveAnnotationsArg, AddFinalizersArg, RemoveFinalizersArg} {
+			if lists[key], err = GetStringListArg(p.Args, key); err != nil {
+				return nil, err
+			}
+		}
+
+		metadata := map[string]any{}
+		if labels := stringMapPatch(p.Args[AddLabelsArg], lists[RemoveLabelsArg]); len(labels) > 0 {
+			metadata["labels"] = labels
+		}
+		if annotations := stringMapPatch(p.Args[AddAnnotationsArg], lists[RemoveAnnotationsArg]); len(annotations) > 0 {
+			metadata["annotations"] = annotations
+		}
+
+		addFinalizers, removeFinalizers := lists[AddFinalizersArg], lists[RemoveFinalizersArg]
+		if len(addFinalizers) > 0 || len(removeFinalizers) > 0 {
+			current := &unstructured.Unstructured{}
+			current.SetGroupVersionKind(gvk)
+			if err := r.runtimeClient.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+				logger.Error(err, "Failed to get object")
+				return nil, err
+			}
+
+			finalizers := slices.DeleteFunc(current.GetFinalizers(), func(finalizer string) bool {
+				return slices.Contains(removeFinalizers, finalizer)
+			})
+			for _, finalizer := range addFinalizers {
+				if !slices.Contains(finalizers, finalizer) {
+					finalizers = append(finalizers, finalizer)
+				}
+			}
+			metadata["finalizers"] = finalizers
+			metadata["resourceVersion"] = current.GetResourceVersion()
+		}
+
+		if len(metadata) == 0 {
+			return nil, fmt.Errorf("at least one of %s, %s, %s, %s, %s or %s is required",
+				AddLabelsArg, RemoveLabelsArg, AddAnnotationsArg, RemoveAnnotationsArg, AddFinalizersArg, RemoveFinalizersArg)
+		}
+
+		patchData, err := json.Marshal(map[string]any{"metadata": metadata})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal metadata patch: %w", err)
+		}
+
+		dryRunBool, err := GetArg[bool](p.Args, DryRunArg, false)
+		if err != nil {
+			return nil, err
+		}
+		var dryRun []string
+		if dryRunBool {
+			dryRun = []string{"All"}
+		}
+
+		patch := client.RawPatch(types.MergePatchType, patchData)
+		if err := r.runtimeClient.Patch(ctx, obj, patch, &client.PatchOptions{DryRun: dryRun}); err != nil {
+			logger.Error(err, "Failed to patch object metadata")
+			return nil, r.conflictError(ctx, obj, err)
+		}
+
+		if !dryRunBool {
+			r.recordChange(ctx, changefeed.OperationUpdate, gvk, obj)
+		}
+
+		return obj.Object, nil
+	}
+}
+
+// stringMapPatch returns the merge patch of a string map setting the entries
+// of add and removing the keys of remove.
+func stringMapPatch(add any, remove []string) map[string]any {
+	patch := map[string]any{}
+	switch entries := add.(type) {
+	case map[string]string:
+		for key, value := range entries {
+			patch[key] = value
+		}
+	case map[string]any:
+		for key, value := range entries {
+			patch[key] = value
+		}
+	}
+	for _, key := range remove {
+		patch[key] = nil
+	}
+	return patch
+}
@@ -0,0 +1,59 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPatchItemMetadata(t *testing.T) {
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "settings",
+			Namespace:   "default",
+			Labels:      map[string]string{"app": "web", "tier": "frontend"},
+			Annotations: map[string]string{"owner": "team-a"},
+			Finalizers:  []string{"example.com/a", "example.com/b"},
+		},
+		Data: map[string]string{"mode": "a"},
+	}).Build()
+	patchMetadata := New(cl).PatchItemMetadata(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, v1.NamespaceScoped)
+	key := client.ObjectKey{Namespace: "default", Name: "settings"}
+
+	_, err := patchMetadata(graphql.ResolveParams{
+		Context: t.Context(),
+		Args: map[string]any{
+			NameArg:              "settings",
+			NamespaceArg:         "default",
+			AddLabelsArg:         map[string]any{"env": "prod", "app": "api"},
+			RemoveLabelsArg:      []any{"tier"},
+			RemoveAnnotationsArg: []any{"owner"},
+			AddFinalizersArg:     []any{"example.com/c", "example.com/a"},
+			RemoveFinalizersArg:  []any{"example.com/b"},
+		},
+	})
+	require.NoError(t, err)
+
+	var cm corev1.ConfigMap
+	require.NoError(t, cl.Get(t.Context(), key, &cm))
+	assert.Equal(t, map[string]string{"app": "api", "env": "prod"}, cm.Labels)
+	assert.Empty(t, cm.Annotations)
+	assert.Equal(t, []string{"example.com/a", "example.com/c"}, cm.Finalizers)
+	assert.Equal(t, map[string]string{"mode": "a"}, cm.Data)
+
+	_, err = patchMetadata(graphql.ResolveParams{
+		Context: t.Context(),
+		Args:    map[string]any{NameArg: "settings", NamespaceArg: "default"},
+	})
+	assert.ErrorContains(t, err, "at least one of")
+}
@@ -0,0 +1,167 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var (
+	crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+	// storageVersionMigrationListGVKs are the in-tree storage version
+	// migration API and that of the out-of-tree storage version migrator.
+	storageVersionMigrationListGVKs = []schema.GroupVersionKind{
+		{Group: "storagemigration.k8s.io", Version: "v1alpha1", Kind: "StorageVersionMigrationList"},
+		{Group: "migration.k8s.io", Version: "v1alpha1", Kind: "StorageVersionMigrationList"},
+	}
+)
+
+// MigrationStatus describes whether the stored objects of a custom resource
+// are on its storage version.
+type MigrationStatus struct {
+	// StorageVersion is the version new objects are stored in.
+	StorageVersion string `json:"storageVersion"`
+	// StoredVersions are the versions objects may still be stored in,
+	// as recorded in the CRD status.
+	StoredVersions []string `json:"storedVersions"`
+	// PendingVersions are the stored versions other than the storage version.
+	PendingVersions []string `json:"pendingVersions"`
+	// Migrated is true if all objects are stored in the storage version.
+	Migrated bool `json:"migrated"`
+	// Migration is the latest storage version migration of the resource, if
+	// the cluster runs the storage version migrator.
+	Migration *StorageVersionMigration `json:"migration"`
+}
+
+// StorageVersionMigration summarizes a StorageVersionMigration object.
+type StorageVersionMigration struct {
+	Name  string `json:"name"`
+	Phase string `json:"phase"`
+}
+
+// TypeMigrationStatus returns a resolver for the migration status of the
+// TypeByCategory the field belongs to. Built-in types have no CustomResourceDefinition
+// and resolve to null.
+func (r *Service) TypeMigrationStatus() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		t, ok := p.Source.(TypeByCategory)
+		if !ok {
+			return nil, errors.New("migrationStatus is only available on types")
+		}
+
+		ctx, span := otel.Tracer("").Start(p.Context, "TypeMigrationStatus", trace.WithAttributes(attribute.String("kind", t.Kind)))
+		defer span.End()
+
+		gk := schema.GroupKind{Group: t.Group, Kind: t.Kind}
+		mapping, err := r.runtimeClient.RESTMapper().RESTMapping(gk, t.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find resource of %s: %w", gk, err)
+		}
+
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(crdGVK)
+		crdName := mapping.Resource.Resource + "." + t.Group
+		if err := r.runtimeClient.Get(ctx, client.ObjectKey{Name: crdName}, crd); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get CustomResourceDefinition %s: %w", crdName, err)
+		}
+
+		status := migrationStatusFromCRD(crd)
+		status.Migration = r.latestStorageVersionMigration(p, mapping.Resource.GroupResource())
+		return status, nil
+	}
+}
+
+// migrationStatusFromCRD compares the stored versions of a CRD with its
+// storage version.
+func migrationStatusFromCRD(crd *unstructured.Unstructured) *MigrationStatus {
+	status := &MigrationStatus{}
+
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		version, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		if storage, _ := version["storage"].(bool); storage {
+			status.StorageVersion, _ = version["name"].(string)
+		}
+	}
+
+	status.StoredVersions, _, _ = unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+	for _, v := range status.StoredVersions {
+		if v != status.StorageVersion {
+			status.PendingVersions = append(status.PendingVersions, v)
+		}
+	}
+	status.Migrated = len(status.PendingVersions) == 0 && slices.Contains(status.StoredVersions, status.StorageVersion)
+
+	return status
+}
+
+// latestStorageVersionMigration returns the newest StorageVersionMigration of
+// a resource. Clusters without a storage version migration API, or callers
+// not allowed to list migrations, get nil.
+func (r *Service) latestStorageVersionMigration(p graphql.ResolveParams, gr schema.GroupResource) *StorageVersionMigration {
+	var latest *unstructured.Unstructured
+	for _, gvk := range storageVersionMigrationListGVKs {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+		if err := r.runtimeClient.List(p.Context, list); err != nil {
+			if !meta.IsNoMatchError(err) && !apierrors.IsNotFound(err) {
+				log.FromContext(p.Context).V(4).Info("Unable to list storage version migrations", "group", gvk.Group, "error", err.Error())
+			}
+			continue
+		}
+
+		for i := range list.Items {
+			item := &list.Items[i]
+			group, _, _ := unstructured.NestedString(item.Object, "spec", "resource", "group")
+			resource, _, _ := unstructured.NestedString(item.Object, "spec", "resource", "resource")
+			if group != gr.Group || resource != gr.Resource {
+				continue
+			}
+			if latest == nil || latest.GetCreationTimestamp().Time.Before(item.GetCreationTimestamp().Time) {
+				latest = item
+			}
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+
+	return &StorageVersionMigration{Name: latest.GetName(), Phase: migrationPhase(latest)}
+}
+
+// migrationPhase returns the type of the true condition of a migration,
+// e.g. Running, Succeeded or Failed, or Pending if none is true.
+func migrationPhase(migration *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(migration.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		if condition["status"] == string(metav1.ConditionTrue) {
+			if phase, ok := condition["type"].(string); ok {
+				return phase
+			}
+		}
+	}
+	return "Pending"
+}
@@ -0,0 +1,104 @@
+package resolver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTypeMigrationStatus(t *testing.T) {
+	crd := func(storedVersions ...any) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]any{
+			"spec": map[string]any{
+				"group": "example.com",
+				"versions": []any{
+					map[string]any{"name": "v1alpha1", "served": true, "storage": false},
+					map[string]any{"name": "v1", "served": true, "storage": true},
+				},
+			},
+			"status": map[string]any{"storedVersions": storedVersions},
+		}}
+		u.SetGroupVersionKind(crdGVK)
+		u.SetName("widgets.example.com")
+		return u
+	}
+	migration := func(name string, created time.Time, resource string, conditions ...any) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]any{
+			"spec":   map[string]any{"resource": map[string]any{"group": "example.com", "version": "v1", "resource": resource}},
+			"status": map[string]any{"conditions": conditions},
+		}}
+		u.SetGroupVersionKind(schema.GroupVersionKind{Group: "storagemigration.k8s.io", Version: "v1alpha1", Kind: "StorageVersionMigration"})
+		u.SetName(name)
+		u.SetCreationTimestamp(metav1.NewTime(created))
+		return u
+	}
+	running := map[string]any{"type": "Running", "status": "True"}
+	succeeded := map[string]any{"type": "Succeeded", "status": "True"}
+
+	widget := TypeByCategory{Group: "example.com", Version: "v1", Kind: "Widget", Scope: "Namespaced"}
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		source  TypeByCategory
+		objects []client.Object
+		want    *MigrationStatus
+	}{
+		{
+			name:    "migrated",
+			source:  widget,
+			objects: []client.Object{crd("v1")},
+			want:    &MigrationStatus{StorageVersion: "v1", StoredVersions: []string{"v1"}, Migrated: true},
+		},
+		{
+			name:   "pending with a running migration",
+			source: widget,
+			objects: []client.Object{
+				crd("v1alpha1", "v1"),
+				migration("old", now.Add(-time.Hour), "widgets", succeeded),
+				migration("new", now, "widgets", running),
+				migration("other", now, "gadgets", succeeded),
+			},
+			want: &MigrationStatus{
+				StorageVersion:  "v1",
+				StoredVersions:  []string{"v1alpha1", "v1"},
+				PendingVersions: []string{"v1alpha1"},
+				Migration:       &StorageVersionMigration{Name: "new", Phase: "Running"},
+			},
+		},
+		{
+			name:   "built-in type",
+			source: TypeByCategory{Version: "v1", Kind: "Pod", Scope: "Namespaced"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}, meta.RESTScopeNamespace)
+			mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+			mapper.Add(crdGVK, meta.RESTScopeRoot)
+			mapper.Add(schema.GroupVersionKind{Group: "storagemigration.k8s.io", Version: "v1alpha1", Kind: "StorageVersionMigration"}, meta.RESTScopeRoot)
+
+			cl := fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(tt.objects...).Build()
+
+			got, err := New(cl).TypeMigrationStatus()(graphql.ResolveParams{Context: t.Context(), Source: tt.source})
+			require.NoError(t, err)
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// AllNamespacesArgConfig is the argument listing a namespaced kind across all
+// namespaces.
+var AllNamespacesArgConfig = &graphql.ArgumentConfig{
+	Type:         graphql.Boolean,
+	DefaultValue: false,
+	Description:  "If true, the objects of all namespaces are listed, which requires permission to list them cluster-wide. Cannot be combined with namespace",
+}
+
+// errAllNamespacesWithNamespace is returned for lists that set both
+// allNamespaces and namespace.
+var errAllNamespacesWithNamespace = errors.New("allNamespaces cannot be combined with namespace")
+
+// authorizeAllNamespaces checks with a SelfSubjectAccessReview that the user
+// of the request may list the objects of gvk cluster-wide, so a missing
+// permission is reported as such instead of as a failed list.
+func (r *Service) authorizeAllNamespaces(ctx context.Context, gvk schema.GroupVersionKind) error {
+	mapping, err := r.runtimeClient.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return fmt.Errorf("failed to find resource of %s: %w", gvk.GroupKind(), err)
+	}
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:     "list",
+				Group:    gvk.Group,
+				Version:  gvk.Version,
+				Resource: mapping.Resource.Resource,
+			},
+		},
+	}
+	if err := r.runtimeClient.Create(ctx, review); err != nil {
+		return fmt.Errorf("failed to check permission to list %s in all namespaces: %w", mapping.Resource.GroupResource(), err)
+	}
+	if !review.Status.Allowed {
+		return fmt.Errorf("not allowed to list %s in all namespaces", mapping.Resource.GroupResource())
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestListItems_AllNamespaces(t *testing.T) {
+	tests := []struct {
+		name              string
+		args              map[string]any
+		allowed           bool
+		wantReview        bool
+		wantItems         int
+		wantAllNamespaces bool
+		wantErr           string
+	}{
+		{
+			name:              "without namespace",
+			args:              map[string]any{},
+			wantItems:         2,
+			wantAllNamespaces: true,
+		},
+		{
+			name:      "in a namespace",
+			args:      map[string]any{NamespaceArg: "team-a"},
+			wantItems: 1,
+		},
+		{
+			name:              "allNamespaces checks cluster-wide permission",
+			args:              map[string]any{AllNamespacesArg: true},
+			allowed:           true,
+			wantReview:        true,
+			wantItems:         2,
+			wantAllNamespaces: true,
+		},
+		{
+			name:       "allNamespaces without permission",
+			args:       map[string]any{AllNamespacesArg: true},
+			wantReview: true,
+			wantErr:    "not allowed to list configmaps in all namespaces",
+		},
+		{
+			name:    "allNamespaces with namespace",
+			args:    map[string]any{AllNamespacesArg: true, NamespaceArg: "team-a"},
+			wantErr: "allNamespaces cannot be combined with namespace",
+		},
+	}
+
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(gvk, meta.RESTScopeNamespace)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var review *authorizationv1.ResourceAttributes
+			cl := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithRESTMapper(mapper).
+				WithObjects(
+					&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "a"}},
+					&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "b"}},
+				).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						if sar, ok := obj.(*authorizationv1.SelfSubjectAccessReview); ok {
+							review = sar.Spec.ResourceAttributes
+							sar.Status.Allowed = tt.allowed
+							return nil
+						}
+						return c.Create(ctx, obj, opts...)
+					},
+				}).
+				Build()
+
+			out, err := New(cl).ListItems(gvk, v1.NamespaceScoped, nil)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    tt.args,
+			})
+
+			if tt.wantReview {
+				require.NotNil(t, review)
+				assert.Equal(t, authorizationv1.ResourceAttributes{Verb: "list", Version: "v1", Resource: "configmaps"}, *review)
+			} else {
+				assert.Nil(t, review)
+			}
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			result := out.(*ListResult)
+			assert.Len(t, result.Items, tt.wantItems)
+			assert.Equal(t, tt.wantAllNamespaces, result.AllNamespaces)
+		})
+	}
+}
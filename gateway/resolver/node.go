@@ -0,0 +1,118 @@
+package resolver
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// IDArg is the argument of the node query.
+const IDArg = "id"
+
+// NodeID identifies an object across clusters for the Node interface.
+type NodeID struct {
+	Cluster   string
+	GVK       schema.GroupVersionKind
+	Namespace string
+	Name      string
+}
+
+// String returns the opaque global ID: the cluster, group, version, kind,
+// namespace and name joined by slashes, which none of them contain, and
+// base64 encoded.
+func (id NodeID) String() string {
+	raw := strings.Join([]string{id.Cluster, id.GVK.Group, id.GVK.Version, id.GVK.Kind, id.Namespace, id.Name}, "/")
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseNodeID parses a global ID returned by NodeID.String.
+func ParseNodeID(id string) (NodeID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return NodeID{}, fmt.Errorf("invalid node id %q: %w", id, err)
+	}
+	parts := strings.Split(string(raw), "/")
+	if len(parts) != 6 || parts[2] == "" || parts[3] == "" || parts[5] == "" {
+		return NodeID{}, fmt.Errorf("invalid node id %q", id)
+	}
+	return NodeID{
+		Cluster:   parts[0],
+		GVK:       schema.GroupVersionKind{Group: parts[1], Version: parts[2], Kind: parts[3]},
+		Namespace: parts[4],
+		Name:      parts[5],
+	}, nil
+}
+
+// NodeIDResolver returns the resolver of the id field of objects of gvk.
+func NodeIDResolver(gvk schema.GroupVersionKind) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		obj, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, errors.New("id is only available on objects")
+		}
+		u := &unstructured.Unstructured{Object: obj}
+		return NodeID{
+			Cluster:   changeCluster(p.Context),
+			GVK:       gvk,
+			Namespace: u.GetNamespace(),
+			Name:      u.GetName(),
+		}.String(), nil
+	}
+}
+
+// Node returns the resolver of the node query, reading the object a global
+// ID points at. IDs of other clusters are rejected, as each endpoint only
+// reaches its own cluster. Null is returned for objects that do not exist.
+func (r *Service) Node(kinds map[schema.GroupKind]ServedKind) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		idArg, err := GetArg[string](p.Args, IDArg, true)
+		if err != nil {
+			return nil, err
+		}
+		id, err := ParseNodeID(idArg)
+		if err != nil {
+			return nil, err
+		}
+		if cluster := changeCluster(p.Context); id.Cluster != cluster {
+			return nil, fmt.Errorf("node %s belongs to cluster %q, not %q", idArg, id.Cluster, cluster)
+		}
+		served, ok := kinds[id.GVK.GroupKind()]
+		if !ok {
+			return nil, fmt.Errorf("kind %s of node %s is not served by this schema", id.GVK.GroupKind(), idArg)
+		}
+
+		ctx, span := otel.Tracer("").Start(p.Context, "Node", trace.WithAttributes(
+			attribute.String("kind", id.GVK.Kind),
+			attribute.String("name", id.Name),
+		))
+		defer span.End()
+
+		key := client.ObjectKey{Name: id.Name}
+		if isResourceNamespaceScoped(served.Scope) {
+			key.Namespace = id.Namespace
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(id.GVK)
+		if err := r.runtimeClient.Get(ctx, key, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			log.FromContext(ctx).Error(err, "Failed to get node", "kind", id.GVK.Kind, "name", id.Name)
+			return nil, err
+		}
+		return obj.Object, nil
+	}
+}
@@ -0,0 +1,83 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseNodeID(t *testing.T) {
+	id := NodeID{Cluster: "root:org", GVK: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, Namespace: "default", Name: "web"}
+
+	parsed, err := ParseNodeID(id.String())
+	require.NoError(t, err)
+	assert.Equal(t, id, parsed)
+
+	clusterScoped := NodeID{Cluster: "c1", GVK: schema.GroupVersionKind{Version: "v1", Kind: "Node"}, Name: "node-1"}
+	parsed, err = ParseNodeID(clusterScoped.String())
+	require.NoError(t, err)
+	assert.Equal(t, clusterScoped, parsed)
+
+	for _, invalid := range []string{"not base64!", "YS9i", NodeID{Cluster: "c1"}.String()} {
+		_, err := ParseNodeID(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestNode(t *testing.T) {
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "settings"}}).
+		Build()
+	kinds := map[schema.GroupKind]ServedKind{{Kind: "ConfigMap"}: {Version: "v1", Scope: v1.NamespaceScoped}}
+	resolve := New(cl).Node(kinds)
+	ctx := utilscontext.SetCluster(t.Context(), "c1")
+
+	configMap := func(cluster, name string) string {
+		return NodeID{Cluster: cluster, GVK: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, Namespace: "default", Name: name}.String()
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		wantName string
+		wantErr  bool
+	}{
+		{name: "existing object", id: configMap("c1", "settings"), wantName: "settings"},
+		{name: "missing object", id: configMap("c1", "missing")},
+		{name: "other cluster", id: configMap("c2", "settings"), wantErr: true},
+		{name: "unserved kind", id: NodeID{Cluster: "c1", GVK: schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, Namespace: "default", Name: "x"}.String(), wantErr: true},
+		{name: "invalid id", id: "invalid", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := resolve(graphql.ResolveParams{Context: ctx, Args: map[string]any{IDArg: tt.id}})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantName == "" {
+				assert.Nil(t, result)
+				return
+			}
+			assert.Equal(t, tt.wantName, (&unstructured.Unstructured{Object: result.(map[string]any)}).GetName())
+
+			id, err := NodeIDResolver(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"})(graphql.ResolveParams{Context: ctx, Source: result})
+			require.NoError(t, err)
+			assert.Equal(t, tt.id, id)
+		})
+	}
+}
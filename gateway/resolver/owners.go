@@ -0,0 +1,191 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// ControllerArg restricts the owners field to the managing controller.
+	ControllerArg = "controller"
+	// KindArg and GroupArg select the kind listed by the ownedObjects field.
+	KindArg  = "kind"
+	GroupArg = "group"
+)
+
+// ServedKind is the version and scope the schema serves a kind at.
+type ServedKind struct {
+	Version string
+	Scope   v1.ResourceScope
+}
+
+// Owners returns a resolver fetching the objects listed in the
+// ownerReferences of the object the field belongs to. Owners of kinds the
+// schema does not serve, owners that no longer exist and owners that were
+// recreated since the reference was set are left out.
+func (r *Service) Owners(kinds map[schema.GroupKind]ServedKind) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, "Owners")
+		defer span.End()
+
+		source, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, errors.New("owners are only available on objects")
+		}
+
+		controllerOnly, err := GetArg[bool](p.Args, ControllerArg, false)
+		if err != nil {
+			return nil, err
+		}
+
+		obj := &unstructured.Unstructured{Object: source}
+		owners := []map[string]any{}
+		for _, ref := range obj.GetOwnerReferences() {
+			if controllerOnly && (ref.Controller == nil || !*ref.Controller) {
+				continue
+			}
+
+			gv, err := schema.ParseGroupVersion(ref.APIVersion)
+			if err != nil {
+				continue
+			}
+			served, ok := kinds[schema.GroupKind{Group: gv.Group, Kind: ref.Kind}]
+			if !ok {
+				log.FromContext(ctx).V(4).Info("Skipping owner of a kind the schema does not serve", "apiVersion", ref.APIVersion, "kind", ref.Kind)
+				continue
+			}
+
+			owner := &unstructured.Unstructured{}
+			owner.SetGroupVersionKind(schema.GroupVersionKind{Group: gv.Group, Version: served.Version, Kind: ref.Kind})
+
+			// Namespaced objects can only be owned by objects in their own
+			// namespace or by cluster-scoped objects.
+			key := client.ObjectKey{Name: ref.Name}
+			if isResourceNamespaceScoped(served.Scope) {
+				key.Namespace = obj.GetNamespace()
+			}
+
+			span.AddEvent("GetOwner", trace.WithAttributes(attribute.String("kind", ref.Kind), attribute.String("name", ref.Name)))
+			if err := r.runtimeClient.Get(ctx, key, owner); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				log.FromContext(ctx).Error(err, "Failed to get owner", "kind", ref.Kind, "name", ref.Name)
+				return nil, err
+			}
+			if owner.GetUID() != ref.UID {
+				continue
+			}
+
+			owners = append(owners, owner.Object)
+		}
+		return owners, nil
+	}
+}
+
+// OwnedObjects returns a resolver listing the objects of a chosen kind whose
+// ownerReferences point at the object the field belongs to. Namespaced
+// objects are searched in the owner's namespace; children of cluster-scoped
+// objects are searched in all namespaces.
+func (r *Service) OwnedObjects(kinds map[schema.GroupKind]ServedKind) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		source, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, errors.New("owned objects are only available on objects")
+		}
+
+		kind, err := GetArg[string](p.Args, KindArg, true)
+		if err != nil {
+			return nil, err
+		}
+		group, err := GetArg[string](p.Args, GroupArg, false)
+		if err != nil {
+			return nil, err
+		}
+		gk, served, err := lookupServedKind(kinds, kind, group, p.Args[GroupArg] != nil)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, span := otel.Tracer("").Start(p.Context, "OwnedObjects", trace.WithAttributes(attribute.String("kind", gk.Kind)))
+		defer span.End()
+
+		owner := &unstructured.Unstructured{Object: source}
+		if owner.GetUID() == "" {
+			// Objects from dry-run creates have no UID yet.
+			return []map[string]any{}, nil
+		}
+
+		var opts []client.ListOption
+		if owner.GetNamespace() != "" {
+			if !isResourceNamespaceScoped(served.Scope) {
+				// Namespaced objects cannot own cluster-scoped objects.
+				return []map[string]any{}, nil
+			}
+			opts = append(opts, client.InNamespace(owner.GetNamespace()))
+		}
+
+		labelSelector, err := labelSelectorFromArgs(p.Args)
+		if err != nil {
+			return nil, err
+		}
+		if labelSelector != nil {
+			opts = append(opts, client.MatchingLabelsSelector{Selector: labelSelector})
+		}
+
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(schema.GroupVersionKind{Group: gk.Group, Version: served.Version, Kind: gk.Kind + "List"})
+		if err := r.runtimeClient.List(ctx, list, opts...); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to list owned objects", "kind", gk.Kind, "owner", owner.GetName())
+			return nil, err
+		}
+
+		owned := []map[string]any{}
+		for _, item := range list.Items {
+			if slices.ContainsFunc(item.GetOwnerReferences(), func(ref metav1.OwnerReference) bool { return ref.UID == owner.GetUID() }) {
+				owned = append(owned, item.Object)
+			}
+		}
+		return owned, nil
+	}
+}
+
+// lookupServedKind finds a kind by name, case-insensitively. The group is
+// required if several groups serve a kind of that name.
+func lookupServedKind(kinds map[schema.GroupKind]ServedKind, kind, group string, hasGroup bool) (schema.GroupKind, ServedKind, error) {
+	var matches []schema.GroupKind
+	for gk := range kinds {
+		if strings.EqualFold(gk.Kind, kind) && (!hasGroup || gk.Group == group) {
+			matches = append(matches, gk)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return schema.GroupKind{}, ServedKind{}, fmt.Errorf("kind %q is not served by this schema", kind)
+	case 1:
+		return matches[0], kinds[matches[0]], nil
+	default:
+		groups := make([]string, len(matches))
+		for i, gk := range matches {
+			groups[i] = gk.Group
+		}
+		slices.Sort(groups)
+		return schema.GroupKind{}, ServedKind{}, fmt.Errorf("kind %q is served by several groups (%s), set %s", kind, strings.Join(groups, ", "), GroupArg)
+	}
+}
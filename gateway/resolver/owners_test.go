@@ -0,0 +1,156 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestOwners(t *testing.T) {
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-abc", UID: "uid-rs"}},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "recreated", UID: "uid-new"}},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", UID: "uid-node"}},
+		).
+		Build()
+
+	kinds := map[schema.GroupKind]ServedKind{
+		{Group: "apps", Kind: "ReplicaSet"}: {Version: "v1", Scope: v1.NamespaceScoped},
+		{Kind: "Node"}:                      {Version: "v1", Scope: v1.ClusterScoped},
+	}
+	resolve := New(cl).Owners(kinds)
+
+	pod := &unstructured.Unstructured{}
+	pod.SetNamespace("default")
+	pod.SetName("web-abc-xyz")
+	pod.SetOwnerReferences([]metav1.OwnerReference{
+		{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-abc", UID: "uid-rs", Controller: ptr.To(true)},
+		{APIVersion: "v1", Kind: "Node", Name: "node-1", UID: "uid-node"},
+		{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "recreated", UID: "uid-old"},
+		{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "deleted", UID: "uid-deleted"},
+		{APIVersion: "example.com/v1", Kind: "Unserved", Name: "x", UID: "uid-x"},
+	})
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want []string
+	}{
+		{
+			name: "existing owners of served kinds",
+			args: map[string]any{},
+			want: []string{"ReplicaSet web-abc", "Node node-1"},
+		},
+		{
+			name: "controller only",
+			args: map[string]any{ControllerArg: true},
+			want: []string{"ReplicaSet web-abc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := resolve(graphql.ResolveParams{Context: t.Context(), Source: pod.Object, Args: tt.args})
+			require.NoError(t, err)
+
+			var got []string
+			for _, owner := range result.([]map[string]any) {
+				u := &unstructured.Unstructured{Object: owner}
+				got = append(got, u.GetKind()+" "+u.GetName())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOwnedObjects(t *testing.T) {
+	ownedBy := func(uid types.UID) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: uid}}
+	}
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-1", Labels: map[string]string{"rev": "1"}, OwnerReferences: ownedBy("uid-web")}},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-2", Labels: map[string]string{"rev": "2"}, OwnerReferences: ownedBy("uid-web")}},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other", OwnerReferences: ownedBy("uid-other")}},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "web-1", OwnerReferences: ownedBy("uid-web")}},
+		).
+		Build()
+
+	kinds := map[schema.GroupKind]ServedKind{
+		{Group: "apps", Kind: "ReplicaSet"}:          {Version: "v1", Scope: v1.NamespaceScoped},
+		{Kind: "Node"}:                               {Version: "v1", Scope: v1.ClusterScoped},
+		{Group: "example.com", Kind: "Gadget"}:       {Version: "v1", Scope: v1.NamespaceScoped},
+		{Group: "other.example.com", Kind: "Gadget"}: {Version: "v1", Scope: v1.NamespaceScoped},
+	}
+	resolve := New(cl).OwnedObjects(kinds)
+
+	deployment := &unstructured.Unstructured{}
+	deployment.SetNamespace("default")
+	deployment.SetName("web")
+	deployment.SetUID("uid-web")
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "owned objects in the owner's namespace",
+			args: map[string]any{KindArg: "replicaset"},
+			want: []string{"web-1", "web-2"},
+		},
+		{
+			name: "label selector",
+			args: map[string]any{KindArg: "ReplicaSet", LabelSelectorArg: "rev=2"},
+			want: []string{"web-2"},
+		},
+		{
+			name: "cluster-scoped kind",
+			args: map[string]any{KindArg: "Node"},
+		},
+		{
+			name:    "unserved kind",
+			args:    map[string]any{KindArg: "Pod"},
+			wantErr: `kind "Pod" is not served by this schema`,
+		},
+		{
+			name:    "ambiguous kind",
+			args:    map[string]any{KindArg: "Gadget"},
+			wantErr: `kind "Gadget" is served by several groups (example.com, other.example.com), set group`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := resolve(graphql.ResolveParams{Context: t.Context(), Source: deployment.Object, Args: tt.args})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []string
+			for _, obj := range result.([]map[string]any) {
+				got = append(got, (&unstructured.Unstructured{Object: obj}).GetName())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
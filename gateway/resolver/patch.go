@@ -0,0 +1,140 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/changefeed"
+	schematypes "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	PatchArg     = "patch"
+	PatchTypeArg = "type"
+)
+
+// PatchTypeEnum lists the patch formats of the patch mutations.
+var PatchTypeEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "PatchType",
+	Description: "The format of a patch",
+	Values: graphql.EnumValueConfigMap{
+		"JSON": &graphql.EnumValueConfig{
+			Value:       string(types.JSONPatchType),
+			Description: "A JSON Patch (RFC 6902): a list of operations",
+		},
+		"MERGE": &graphql.EnumValueConfig{
+			Value:       string(types.MergePatchType),
+			Description: "A JSON Merge Patch (RFC 7386): an object whose fields replace those of the object, null removes them",
+		},
+		"STRATEGIC_MERGE": &graphql.EnumValueConfig{
+			Value:       string(types.StrategicMergePatchType),
+			Description: "A strategic merge patch, merging lists by their keys; not supported for custom resources",
+		},
+	},
+})
+
+// PatchArgs returns arguments for patch mutations.
+func PatchArgs(scope v1.ResourceScope) graphql.FieldConfigArgument {
+	args := ItemArgs(scope)
+	args[PatchArg] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(schematypes.JSONScalar),
+		Description: "The patch, with the Kubernetes field names of the object",
+	}
+	args[PatchTypeArg] = &graphql.ArgumentConfig{
+		Type:         PatchTypeEnum,
+		DefaultValue: string(types.MergePatchType),
+		Description:  "The format of the patch",
+	}
+	args[DryRunArg] = DryRunArgConfig
+	return args
+}
+
+// PatchItem patches an object with a JSON patch, a JSON merge patch or a
+// strategic merge patch.
+func (r *Service) PatchItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		logger := log.FromContext(p.Context)
+		ctx, span := otel.Tracer("").Start(p.Context, "PatchItem", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		logger = logger.WithValues("operation", "patch", "kind", gvk.Kind)
+
+		name, err := GetArg[string](p.Args, NameArg, true)
+		if err != nil {
+			return nil, err
+		}
+
+		patchType, err := GetArg[string](p.Args, PatchTypeArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if patchType == "" {
+			patchType = string(types.MergePatchType)
+		}
+
+		patchValue, ok := p.Args[PatchArg]
+		if !ok || patchValue == nil {
+			return nil, fmt.Errorf("missing required argument: %s", PatchArg)
+		}
+		switch patchValue.(type) {
+		case []any:
+			if patchType != string(types.JSONPatchType) {
+				return nil, fmt.Errorf("%s must be an object for merge patches", PatchArg)
+			}
+		case map[string]any:
+			if patchType == string(types.JSONPatchType) {
+				return nil, fmt.Errorf("%s must be a list of operations for JSON patches", PatchArg)
+			}
+		default:
+			return nil, fmt.Errorf("%s must be an object or a list of operations", PatchArg)
+		}
+		patchData, err := json.Marshal(patchValue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal patch: %w", err)
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		obj.SetName(name)
+
+		if isResourceNamespaceScoped(scope) {
+			namespace, err := GetArg[string](p.Args, NamespaceArg, true)
+			if err != nil {
+				return nil, err
+			}
+			obj.SetNamespace(namespace)
+		}
+
+		dryRunBool, err := GetArg[bool](p.Args, DryRunArg, false)
+		if err != nil {
+			return nil, err
+		}
+		var dryRun []string
+		if dryRunBool {
+			dryRun = []string{"All"}
+		}
+
+		patch := client.RawPatch(types.PatchType(patchType), patchData)
+		if err := r.runtimeClient.Patch(ctx, obj, patch, &client.PatchOptions{DryRun: dryRun}); err != nil {
+			logger.Error(err, "Failed to patch object")
+			return nil, err
+		}
+
+		if !dryRunBool {
+			r.recordChange(ctx, changefeed.OperationUpdate, gvk, obj)
+		}
+
+		return obj.Object, nil
+	}
+}
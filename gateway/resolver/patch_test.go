@@ -0,0 +1,103 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestPatchItem(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	mergePatch := map[string]any{"spec": map[string]any{"replicas": 3}}
+	jsonPatch := []any{map[string]any{"op": "replace", "path": "/spec/replicas", "value": 3}}
+
+	tests := []struct {
+		name       string
+		args       map[string]any
+		wantType   types.PatchType
+		wantData   string
+		wantDryRun []string
+		wantErr    string
+	}{
+		{
+			name:     "merge patch by default",
+			args:     map[string]any{NameArg: "web", NamespaceArg: "default", PatchArg: mergePatch},
+			wantType: types.MergePatchType,
+			wantData: `{"spec":{"replicas":3}}`,
+		},
+		{
+			name:     "JSON patch",
+			args:     map[string]any{NameArg: "web", NamespaceArg: "default", PatchArg: jsonPatch, PatchTypeArg: string(types.JSONPatchType)},
+			wantType: types.JSONPatchType,
+			wantData: `[{"op":"replace","path":"/spec/replicas","value":3}]`,
+		},
+		{
+			name:       "dry-run strategic merge patch",
+			args:       map[string]any{NameArg: "web", NamespaceArg: "default", PatchArg: mergePatch, PatchTypeArg: string(types.StrategicMergePatchType), DryRunArg: true},
+			wantType:   types.StrategicMergePatchType,
+			wantData:   `{"spec":{"replicas":3}}`,
+			wantDryRun: []string{"All"},
+		},
+		{
+			name:    "JSON patch that is not a list",
+			args:    map[string]any{NameArg: "web", NamespaceArg: "default", PatchArg: mergePatch, PatchTypeArg: string(types.JSONPatchType)},
+			wantErr: "patch must be a list of operations for JSON patches",
+		},
+		{
+			name:    "merge patch that is not an object",
+			args:    map[string]any{NameArg: "web", NamespaceArg: "default", PatchArg: jsonPatch},
+			wantErr: "patch must be an object for merge patches",
+		},
+		{
+			name:    "missing namespace",
+			args:    map[string]any{NameArg: "web", PatchArg: mergePatch},
+			wantErr: "missing required argument: namespace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				calls   int
+				patched client.Object
+				patch   client.Patch
+				opts    client.PatchOptions
+			)
+			cl := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, p client.Patch, patchOpts ...client.PatchOption) error {
+					calls++
+					patched, patch = obj, p
+					opts.ApplyOptions(patchOpts)
+					return nil
+				},
+			}).Build()
+
+			_, err := New(cl).PatchItem(gvk, v1.NamespaceScoped)(graphql.ResolveParams{Context: t.Context(), Args: tt.args})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				assert.Zero(t, calls)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, 1, calls)
+			assert.Equal(t, client.ObjectKey{Namespace: "default", Name: "web"}, client.ObjectKeyFromObject(patched))
+			assert.Equal(t, gvk, patched.GetObjectKind().GroupVersionKind())
+			assert.Equal(t, tt.wantType, patch.Type())
+			data, err := patch.Data(patched)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.wantData, string(data))
+			assert.Equal(t, tt.wantDryRun, opts.DryRun)
+		})
+	}
+}
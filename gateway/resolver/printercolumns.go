@@ -0,0 +1,58 @@
+package resolver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// SortByArgConfigFor returns the sortBy argument of a kind, listing its
+// printer columns in the description if it has any.
+func SortByArgConfigFor(columns []v1.CustomResourceColumnDefinition) *graphql.ArgumentConfig {
+	if len(columns) == 0 {
+		return SortByArgConfig
+	}
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+	return &graphql.ArgumentConfig{
+		Type:         SortByArgConfig.Type,
+		Description:  SortByArgConfig.Description + ". Printer columns are sorted by their JSONPath like kubectl shows them: " + strings.Join(names, ", "),
+		DefaultValue: SortByArgConfig.DefaultValue,
+	}
+}
+
+// printerColumn returns the printer column named by sortBy, compared
+// case-insensitively as kubectl prints column names in upper case.
+func printerColumn(columns []v1.CustomResourceColumnDefinition, sortBy string) (v1.CustomResourceColumnDefinition, bool) {
+	for _, column := range columns {
+		if strings.EqualFold(column.Name, sortBy) {
+			return column, true
+		}
+	}
+	return v1.CustomResourceColumnDefinition{}, false
+}
+
+// columnValue returns the value of a printer column of an object. Columns
+// such as the status of a condition are often only set once the object is
+// reconciled, so objects may not have a value.
+func columnValue(column v1.CustomResourceColumnDefinition) (func(obj unstructured.Unstructured) (any, bool), error) {
+	jp := jsonpath.New(column.Name).AllowMissingKeys(true)
+	if err := jp.Parse("{" + column.JSONPath + "}"); err != nil {
+		return nil, fmt.Errorf("invalid JSONPath %q of printer column %s: %w", column.JSONPath, column.Name, err)
+	}
+
+	return func(obj unstructured.Unstructured) (any, bool) {
+		results, err := jp.FindResults(obj.Object)
+		if err != nil || len(results) == 0 || len(results[0]) == 0 {
+			return nil, false
+		}
+		return results[0][0].Interface(), true
+	}, nil
+}
@@ -0,0 +1,82 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestListItems_SortByPrinterColumn(t *testing.T) {
+	columns := []v1.CustomResourceColumnDefinition{
+		{Name: "Ready", Type: "string", JSONPath: `.status.conditions[?(@.type=="Ready")].status`},
+		{Name: "Replicas", Type: "integer", JSONPath: ".spec.replicas"},
+		{Name: "Broken", Type: "string", JSONPath: ".spec[?("},
+	}
+
+	account := func(name string, replicas int64, ready string) unstructured.Unstructured {
+		obj := unstructured.Unstructured{Object: map[string]any{
+			"metadata": map[string]any{"name": name, "namespace": "default"},
+			"spec":     map[string]any{"replicas": replicas},
+		}}
+		if ready != "" {
+			obj.Object["status"] = map[string]any{"conditions": []any{
+				map[string]any{"type": "Synced", "status": "True"},
+				map[string]any{"type": "Ready", "status": ready},
+			}}
+		}
+		return obj
+	}
+
+	fc := &fakeClient{
+		listFn: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+			list.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{
+				account("a", 3, "True"),
+				account("b", 1, ""),
+				account("c", 2, "False"),
+			}
+			return nil
+		},
+	}
+	svc := &Service{runtimeClient: fc}
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Account"}
+
+	tests := []struct {
+		name    string
+		sortBy  string
+		want    []string
+		wantErr bool
+	}{
+		{name: "column with filter", sortBy: "Ready", want: []string{"c", "a", "b"}},
+		{name: "column name is case-insensitive", sortBy: "REPLICAS", want: []string{"b", "c", "a"}},
+		{name: "field path", sortBy: "spec.replicas", want: []string{"b", "c", "a"}},
+		{name: "invalid JSONPath", sortBy: "Broken", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := svc.ListItems(gvk, v1.NamespaceScoped, columns)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    map[string]any{SortByArg: tt.sortBy},
+			})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var names []string
+			for _, item := range out.(*ListResult).Items {
+				names = append(names, item["metadata"].(map[string]any)["name"].(string))
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
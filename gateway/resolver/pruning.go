@@ -0,0 +1,264 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// PrunedFieldsExtensionName is the key of the pruned fields in the response extensions.
+const PrunedFieldsExtensionName = "prunedFields"
+
+// PrunedFieldsFieldName is the field of object types listing the fields
+// pruned from the object a mutation returned.
+const PrunedFieldsFieldName = "prunedFields"
+
+// prunedFieldsSourceKey holds the pruned fields in the objects returned by
+// mutations. GraphQL reserves names starting with two underscores, so no
+// field generated from the object's schema reads it.
+const prunedFieldsSourceKey = "__prunedFields"
+
+type prunedFieldsKey struct{}
+
+// prunedFieldsCollector gathers pruned field paths per mutation response key.
+type prunedFieldsCollector struct {
+	mu     sync.Mutex
+	fields map[string][]string
+}
+
+// PrunedFieldsExtension is a graphql.Extension that reports fields the API
+// server dropped from created or updated objects, e.g. unknown fields of a CRD
+// with pruning enabled. They are returned in the response extensions under
+// prunedFields, keyed by the mutation's response key:
+//
+//	"extensions": {"prunedFields": {"createFoo": ["spec.unknownField"]}}
+//
+// Responses without pruned fields are left unchanged.
+type PrunedFieldsExtension struct{}
+
+var _ graphql.Extension = PrunedFieldsExtension{}
+
+func (PrunedFieldsExtension) Init(ctx context.Context, _ *graphql.Params) context.Context {
+	return context.WithValue(ctx, prunedFieldsKey{}, &prunedFieldsCollector{fields: map[string][]string{}})
+}
+
+func (PrunedFieldsExtension) Name() string {
+	return PrunedFieldsExtensionName
+}
+
+func (PrunedFieldsExtension) ParseDidStart(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+	return ctx, func(error) {}
+}
+
+func (PrunedFieldsExtension) ValidationDidStart(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+	return ctx, func([]gqlerrors.FormattedError) {}
+}
+
+func (PrunedFieldsExtension) ExecutionDidStart(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+	return ctx, func(result *graphql.Result) {
+		c, ok := ctx.Value(prunedFieldsKey{}).(*prunedFieldsCollector)
+		if !ok || result == nil {
+			return
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if len(c.fields) == 0 {
+			return
+		}
+		if result.Extensions == nil {
+			result.Extensions = map[string]any{}
+		}
+		result.Extensions[PrunedFieldsExtensionName] = c.fields
+	}
+}
+
+func (PrunedFieldsExtension) ResolveFieldDidStart(ctx context.Context, _ *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+	return ctx, func(any, error) {}
+}
+
+// HasResult reports false; pruned fields are added in ExecutionDidStart's
+// finish function so that responses without them carry no extension entry.
+func (PrunedFieldsExtension) HasResult() bool {
+	return false
+}
+
+func (PrunedFieldsExtension) GetResult(context.Context) any {
+	return nil
+}
+
+// recordPrunedFields compares the submitted object with the one persisted by
+// the API server and records the fields that were dropped.
+func recordPrunedFields(p graphql.ResolveParams, submitted, persisted map[string]any) []string {
+	pruned := prunedFields(submitted, persisted)
+	if len(pruned) == 0 {
+		return nil
+	}
+
+	if c, ok := p.Context.Value(prunedFieldsKey{}).(*prunedFieldsCollector); ok {
+		key := p.Info.FieldName
+		if path := p.Info.Path; path != nil {
+			key = fmt.Sprint(path.Key)
+		}
+
+		c.mu.Lock()
+		c.fields[key] = pruned
+		c.mu.Unlock()
+	}
+	return pruned
+}
+
+// withPrunedFields returns obj with the pruned fields for the prunedFields
+// field of the mutation result. obj is copied so that the object recorded in
+// the change feed is left unchanged.
+func withPrunedFields(obj map[string]any, pruned []string) map[string]any {
+	if len(pruned) == 0 {
+		return obj
+	}
+	result := maps.Clone(obj)
+	result[prunedFieldsSourceKey] = pruned
+	return result
+}
+
+// PrunedFields returns the resolver of the prunedFields field of objects,
+// listing the fields the API server dropped from the object a mutation
+// returned. It is empty for objects read by queries.
+func PrunedFields(p graphql.ResolveParams) (any, error) {
+	source, ok := p.Source.(map[string]any)
+	if !ok {
+		return []string{}, nil
+	}
+	if pruned, ok := source[prunedFieldsSourceKey].([]string); ok {
+		return pruned, nil
+	}
+	return []string{}, nil
+}
+
+// serverMetadataFields are the fields of metadata the API server sets itself,
+// replacing or dropping submitted values.
+var serverMetadataFields = []string{
+	"creationTimestamp",
+	"deletionGracePeriodSeconds",
+	"deletionTimestamp",
+	"generation",
+	"managedFields",
+	"resourceVersion",
+	"selfLink",
+	"uid",
+}
+
+// WithStatusSubresources sets the kinds with a status subresource. The API
+// server only writes their status through it, so create, update, upsert and
+// apply mutations do not report a submitted status as pruned.
+func (r *Service) WithStatusSubresources(kinds map[schema.GroupVersionKind]bool) *Service {
+	r.statusSubresources = kinds
+	return r
+}
+
+// prunableFields returns the fields of an object submitted to a mutation of
+// gvk that the API server may prune, leaving out the status of kinds with a
+// status subresource.
+func (r *Service) prunableFields(gvk schema.GroupVersionKind, submitted map[string]any) map[string]any {
+	if !r.statusSubresources[gvk] {
+		return submitted
+	}
+	submitted = maps.Clone(submitted)
+	delete(submitted, "status")
+	return submitted
+}
+
+// prunedFields returns the sorted paths of the fields set in submitted that
+// are missing from persisted. Fields submitted as null request removal and
+// other zero values are dropped by omitempty on built-in types, so both are
+// ignored, as are the metadata fields the API server sets itself.
+func prunedFields(submitted, persisted map[string]any) []string {
+	if metadata, ok := submitted["metadata"].(map[string]any); ok {
+		metadata = maps.Clone(metadata)
+		for _, field := range serverMetadataFields {
+			delete(metadata, field)
+		}
+		submitted = maps.Clone(submitted)
+		submitted["metadata"] = metadata
+	}
+
+	var pruned []string
+	collectPrunedFields(submitted, persisted, "", &pruned)
+	sort.Strings(pruned)
+	return slices.Compact(pruned)
+}
+
+func collectPrunedFields(submitted, persisted any, path string, pruned *[]string) {
+	switch sub := submitted.(type) {
+	case map[string]any:
+		per, ok := persisted.(map[string]any)
+		if !ok {
+			return
+		}
+		for key, value := range sub {
+			if isZeroValue(value) {
+				continue
+			}
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			persistedValue, found := per[key]
+			if !found {
+				*pruned = append(*pruned, fieldPath)
+				continue
+			}
+			collectPrunedFields(value, persistedValue, fieldPath, pruned)
+		}
+	case []any:
+		per, ok := persisted.([]any)
+		if !ok || len(per) != len(sub) {
+			// Lists may be reordered or merged by the server; only compare
+			// element-wise when the shape is unchanged.
+			return
+		}
+		for i := range sub {
+			collectPrunedFields(sub[i], per[i], fmt.Sprintf("%s[%d]", path, i), pruned)
+		}
+	}
+}
+
+func isZeroValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// copyObject returns a deep copy of a JSON-compatible object, normalizing
+// values such as int or map[string]string from GraphQL input to their JSON
+// equivalents. Returns nil if obj cannot be encoded.
+func copyObject(obj map[string]any) map[string]any {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,165 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestPrunedFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		submitted map[string]any
+		persisted map[string]any
+		want      []string
+	}{
+		{
+			name:      "nothing pruned",
+			submitted: map[string]any{"spec": map[string]any{"replicas": 1}},
+			persisted: map[string]any{"spec": map[string]any{"replicas": int64(1)}, "status": map[string]any{}},
+		},
+		{
+			name:      "nested fields pruned",
+			submitted: map[string]any{"spec": map[string]any{"replicas": 1, "unknown": "x", "extra": map[string]any{"a": "b"}}},
+			persisted: map[string]any{"spec": map[string]any{"replicas": 1}},
+			want:      []string{"spec.extra", "spec.unknown"},
+		},
+		{
+			name:      "zero values ignored",
+			submitted: map[string]any{"spec": map[string]any{"removed": nil, "empty": "", "flag": false, "labels": map[string]any{}}},
+			persisted: map[string]any{"spec": map[string]any{}},
+		},
+		{
+			name: "list elements compared by index",
+			submitted: map[string]any{"spec": map[string]any{"containers": []any{
+				map[string]any{"name": "a"},
+				map[string]any{"name": "b", "unknown": "x"},
+			}}},
+			persisted: map[string]any{"spec": map[string]any{"containers": []any{
+				map[string]any{"name": "a"},
+				map[string]any{"name": "b"},
+			}}},
+			want: []string{"spec.containers[1].unknown"},
+		},
+		{
+			name: "server metadata ignored",
+			submitted: map[string]any{"metadata": map[string]any{
+				"name":              "foo",
+				"uid":               "submitted",
+				"deletionTimestamp": "2024-01-01T00:00:00Z",
+				"managedFields":     []any{map[string]any{"manager": "x"}},
+			}},
+			persisted: map[string]any{"metadata": map[string]any{"name": "foo", "uid": "assigned"}},
+		},
+		{
+			name:      "lists of different length skipped",
+			submitted: map[string]any{"items": []any{map[string]any{"unknown": "x"}}},
+			persisted: map[string]any{"items": []any{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, prunedFields(tt.submitted, tt.persisted))
+		})
+	}
+}
+
+func TestPrunedFieldsExtension(t *testing.T) {
+	persisted := map[string]any{"spec": map[string]any{"replicas": 1}}
+	mutation := func(p graphql.ResolveParams) (any, error) {
+		submitted := copyObject(map[string]any{"spec": map[string]any{"replicas": 1, "unknown": "x"}})
+		recordPrunedFields(p, submitted, persisted)
+		return "ok", nil
+	}
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+			"ping": &graphql.Field{Type: graphql.String, Resolve: func(graphql.ResolveParams) (any, error) { return "pong", nil }},
+		}}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{Name: "Mutation", Fields: graphql.Fields{
+			"createFoo": &graphql.Field{Type: graphql.String, Resolve: mutation},
+		}}),
+		Extensions: []graphql.Extension{PrunedFieldsExtension{}},
+	})
+	require.NoError(t, err)
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `mutation { created: createFoo }`,
+		Context:       t.Context(),
+	})
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string][]string{"created": {"spec.unknown"}}, result.Extensions[PrunedFieldsExtensionName])
+
+	result = graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ ping }`,
+		Context:       t.Context(),
+	})
+	require.Empty(t, result.Errors)
+	assert.NotContains(t, result.Extensions, PrunedFieldsExtensionName)
+}
+
+func TestPrunedFieldsField(t *testing.T) {
+	persisted := map[string]any{"metadata": map[string]any{"name": "foo"}, "spec": map[string]any{"replicas": 1}}
+	foo := graphql.NewObject(graphql.ObjectConfig{Name: "Foo", Fields: graphql.Fields{
+		"metadata": &graphql.Field{Type: graphql.NewObject(graphql.ObjectConfig{Name: "Metadata", Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String},
+		}})},
+		PrunedFieldsFieldName: &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Resolve: PrunedFields,
+		},
+	}})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+			"foo": &graphql.Field{Type: foo, Resolve: func(graphql.ResolveParams) (any, error) { return persisted, nil }},
+		}}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{Name: "Mutation", Fields: graphql.Fields{
+			"createFoo": &graphql.Field{Type: foo, Resolve: func(p graphql.ResolveParams) (any, error) {
+				submitted := copyObject(map[string]any{"spec": map[string]any{"replicas": 1, "unknown": "x"}})
+				return withPrunedFields(persisted, recordPrunedFields(p, submitted, persisted)), nil
+			}},
+		}}),
+	})
+	require.NoError(t, err)
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `mutation { createFoo { metadata { name } prunedFields } }`,
+		Context:       t.Context(),
+	})
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string]any{"createFoo": map[string]any{
+		"metadata":     map[string]any{"name": "foo"},
+		"prunedFields": []any{"spec.unknown"},
+	}}, result.Data)
+	assert.NotContains(t, persisted, prunedFieldsSourceKey, "the persisted object must not be changed")
+
+	result = graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ foo { prunedFields } }`,
+		Context:       t.Context(),
+	})
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string]any{"foo": map[string]any{"prunedFields": []any{}}}, result.Data)
+}
+
+func TestPrunableFields(t *testing.T) {
+	widget := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	gadget := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Gadget"}
+	r := New(nil).WithStatusSubresources(map[schema.GroupVersionKind]bool{widget: true})
+
+	submitted := map[string]any{"spec": map[string]any{"image": "nginx"}, "status": map[string]any{"phase": "Running"}}
+	persisted := map[string]any{"spec": map[string]any{"image": "nginx"}}
+
+	assert.Empty(t, prunedFields(r.prunableFields(widget, submitted), persisted), "the status subresource is written separately")
+	assert.Equal(t, []string{"status"}, prunedFields(r.prunableFields(gadget, submitted), persisted))
+	assert.Contains(t, submitted, "status", "the submitted object must not be changed")
+}
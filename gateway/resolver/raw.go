@@ -0,0 +1,100 @@
+package resolver
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// RawResourceArgs returns the arguments of the rawResource query.
+func RawResourceArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		APIVersionArg: &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The apiVersion of the object, e.g. example.com/v1",
+		},
+		KindArg: &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The kind of the object, e.g. Widget",
+		},
+		NameArg: NameArgConfig,
+		NamespaceArg: &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "The namespace of the object, required for namespaced kinds",
+		},
+	}
+}
+
+// RawResource returns a resolver reading any object by apiVersion and kind,
+// including kinds the schema does not have types for yet, such as custom
+// resources installed after the schema was generated. The kind is looked up
+// in the cluster's discovery information at query time.
+func (r *Service) RawResource() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		apiVersion, err := GetArg[string](p.Args, APIVersionArg, true)
+		if err != nil {
+			return nil, err
+		}
+		kind, err := GetArg[string](p.Args, KindArg, true)
+		if err != nil {
+			return nil, err
+		}
+		name, err := GetArg[string](p.Args, NameArg, true)
+		if err != nil {
+			return nil, err
+		}
+		namespace, err := GetArg[string](p.Args, NamespaceArg, false)
+		if err != nil {
+			return nil, err
+		}
+
+		gv, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", APIVersionArg, apiVersion, err)
+		}
+		gvk := gv.WithKind(kind)
+
+		ctx, span := otel.Tracer("").Start(p.Context, "RawResource", trace.WithAttributes(
+			attribute.String("apiVersion", apiVersion),
+			attribute.String("kind", kind),
+		))
+		defer span.End()
+
+		logger := log.FromContext(ctx).WithValues(
+			"operation", "get",
+			"group", gvk.Group,
+			"version", gvk.Version,
+			"kind", gvk.Kind,
+		)
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+
+		namespaced, err := r.runtimeClient.IsObjectNamespaced(obj)
+		if err != nil {
+			return nil, fmt.Errorf("kind %s is not served by the cluster: %w", gvk, err)
+		}
+
+		key := client.ObjectKey{Name: name}
+		if namespaced {
+			if namespace == "" {
+				return nil, fmt.Errorf("%s is required for namespaced kind %s", NamespaceArg, kind)
+			}
+			key.Namespace = namespace
+		}
+
+		if err := r.runtimeClient.Get(ctx, key, obj); err != nil {
+			logger.WithValues("name", name, "namespace", namespace).Error(err, "Unable to get object")
+			return nil, err
+		}
+		return obj.Object, nil
+	}
+}
@@ -0,0 +1,87 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRawResource(t *testing.T) {
+	widgetGVK := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	gadgetGVK := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Gadget"}
+
+	widget := &unstructured.Unstructured{}
+	widget.SetGroupVersionKind(widgetGVK)
+	widget.SetNamespace("default")
+	widget.SetName("w1")
+	require.NoError(t, unstructured.SetNestedField(widget.Object, int64(3), "spec", "size"))
+
+	gadget := &unstructured.Unstructured{}
+	gadget.SetGroupVersionKind(gadgetGVK)
+	gadget.SetName("g1")
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(widgetGVK, meta.RESTScopeNamespace)
+	mapper.Add(gadgetGVK, meta.RESTScopeRoot)
+	cl := fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(widget, gadget).Build()
+	resolve := New(cl).RawResource()
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    string
+		wantErr string
+	}{
+		{
+			name: "namespaced object",
+			args: map[string]any{APIVersionArg: "example.com/v1", KindArg: "Widget", NameArg: "w1", NamespaceArg: "default"},
+			want: "default/w1",
+		},
+		{
+			name: "cluster-scoped object ignores the namespace",
+			args: map[string]any{APIVersionArg: "example.com/v1", KindArg: "Gadget", NameArg: "g1", NamespaceArg: "default"},
+			want: "/g1",
+		},
+		{
+			name:    "namespaced object without namespace",
+			args:    map[string]any{APIVersionArg: "example.com/v1", KindArg: "Widget", NameArg: "w1"},
+			wantErr: "namespace is required",
+		},
+		{
+			name:    "unknown kind",
+			args:    map[string]any{APIVersionArg: "example.com/v1", KindArg: "Unknown", NameArg: "x"},
+			wantErr: "not served by the cluster",
+		},
+		{
+			name:    "invalid apiVersion",
+			args:    map[string]any{APIVersionArg: "a/b/c", KindArg: "Widget", NameArg: "w1"},
+			wantErr: "invalid apiVersion",
+		},
+		{
+			name:    "missing object",
+			args:    map[string]any{APIVersionArg: "example.com/v1", KindArg: "Widget", NameArg: "missing", NamespaceArg: "default"},
+			wantErr: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := resolve(graphql.ResolveParams{Context: t.Context(), Args: tt.args})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			u := &unstructured.Unstructured{Object: result.(map[string]any)}
+			assert.Equal(t, tt.want, u.GetNamespace()+"/"+u.GetName())
+		})
+	}
+}
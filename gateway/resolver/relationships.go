@@ -0,0 +1,157 @@
+package resolver
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// namespaceKey carries the namespace of the enclosing object into nested
+// objects, so references without a namespace can be resolved relative to it.
+// It is not a valid GraphQL name and cannot collide with a field.
+const namespaceKey = "$namespace"
+
+// InheritNamespace wraps the resolver of a field returning nested objects,
+// so that relationship fields below it know the namespace of the object they
+// belong to. A nil resolver is treated as the default resolver.
+func InheritNamespace(resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	if resolve == nil {
+		resolve = graphql.DefaultResolveFn
+	}
+	return func(p graphql.ResolveParams) (any, error) {
+		value, err := resolve(p)
+		if err != nil {
+			return nil, err
+		}
+		source, ok := p.Source.(map[string]any)
+		if !ok {
+			return value, nil
+		}
+		namespace := sourceNamespace(source)
+		if namespace == "" {
+			return value, nil
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			return withNamespace(v, namespace), nil
+		case []any:
+			items := make([]any, len(v))
+			for i, item := range v {
+				if m, ok := item.(map[string]any); ok {
+					items[i] = withNamespace(m, namespace)
+				} else {
+					items[i] = item
+				}
+			}
+			return items, nil
+		default:
+			return value, nil
+		}
+	}
+}
+
+// withNamespace returns a shallow copy of obj carrying the namespace.
+func withNamespace(obj map[string]any, namespace string) map[string]any {
+	copied := make(map[string]any, len(obj)+1)
+	for k, v := range obj {
+		copied[k] = v
+	}
+	copied[namespaceKey] = namespace
+	return copied
+}
+
+// sourceNamespace returns the namespace inherited by a nested object, or the
+// namespace of the object itself at the top level.
+func sourceNamespace(source map[string]any) string {
+	if namespace, ok := source[namespaceKey].(string); ok {
+		return namespace
+	}
+	namespace, _, _ := unstructured.NestedString(source, "metadata", "namespace")
+	return namespace
+}
+
+// Relationship returns a resolver fetching the object a reference field such
+// as accountRef points at. The reference's kind, apiGroup or apiVersion are
+// used if it has them, otherwise defaultKind. References without a namespace
+// resolve in the namespace of the object they belong to. Null is returned
+// for empty references and objects that do not exist.
+func (r *Service) Relationship(refField, defaultKind string, kinds map[schema.GroupKind]ServedKind) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		source, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		ref, ok := source[refField].(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		name, _ := ref["name"].(string)
+		if name == "" {
+			return nil, nil
+		}
+
+		kind := defaultKind
+		if refKind, ok := ref["kind"].(string); ok && refKind != "" {
+			kind = refKind
+		}
+		group, hasGroup := referenceGroup(ref)
+		gk, served, err := lookupServedKind(kinds, kind, group, hasGroup)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", refField, err)
+		}
+
+		ctx, span := otel.Tracer("").Start(p.Context, "Relationship", trace.WithAttributes(
+			attribute.String("field", refField),
+			attribute.String("kind", gk.Kind),
+			attribute.String("name", name),
+		))
+		defer span.End()
+
+		key := client.ObjectKey{Name: name}
+		if isResourceNamespaceScoped(served.Scope) {
+			key.Namespace, _ = ref["namespace"].(string)
+			if key.Namespace == "" {
+				key.Namespace = sourceNamespace(source)
+			}
+			if key.Namespace == "" {
+				return nil, fmt.Errorf("failed to resolve %s: %s %q is namespaced and the reference has no namespace", refField, gk.Kind, name)
+			}
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: gk.Group, Version: served.Version, Kind: gk.Kind})
+		if err := r.runtimeClient.Get(ctx, key, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			log.FromContext(ctx).Error(err, "Failed to get referenced object", "field", refField, "kind", gk.Kind, "name", name)
+			return nil, err
+		}
+		return obj.Object, nil
+	}
+}
+
+// referenceGroup returns the API group a reference names, either directly as
+// apiGroup (where null means the core group) or as part of apiVersion.
+func referenceGroup(ref map[string]any) (string, bool) {
+	if apiGroup, ok := ref["apiGroup"]; ok {
+		group, _ := apiGroup.(string)
+		return group, true
+	}
+	if apiVersion, ok := ref["apiVersion"].(string); ok && apiVersion != "" {
+		if gv, err := schema.ParseGroupVersion(apiVersion); err == nil {
+			return gv.Group, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,120 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRelationship(t *testing.T) {
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "creds"}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "creds"}},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		).
+		Build()
+
+	kinds := map[schema.GroupKind]ServedKind{
+		{Kind: "Secret"}: {Version: "v1", Scope: v1.NamespaceScoped},
+		{Kind: "Node"}:   {Version: "v1", Scope: v1.ClusterScoped},
+	}
+	svc := New(cl)
+
+	tests := []struct {
+		name    string
+		source  map[string]any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "kind from the field name in the inherited namespace",
+			source: map[string]any{namespaceKey: "default", "secretRef": map[string]any{"name": "creds"}},
+			want:   "default/creds",
+		},
+		{
+			name:   "namespace of the reference",
+			source: map[string]any{namespaceKey: "default", "secretRef": map[string]any{"name": "creds", "namespace": "other"}},
+			want:   "other/creds",
+		},
+		{
+			name:   "namespace of the object at the top level",
+			source: map[string]any{"metadata": map[string]any{"namespace": "other"}, "secretRef": map[string]any{"name": "creds"}},
+			want:   "other/creds",
+		},
+		{
+			name:   "kind and apiVersion of the reference",
+			source: map[string]any{namespaceKey: "default", "secretRef": map[string]any{"apiVersion": "v1", "kind": "Node", "name": "node-1"}},
+			want:   "/node-1",
+		},
+		{
+			name:   "missing object",
+			source: map[string]any{namespaceKey: "default", "secretRef": map[string]any{"name": "missing"}},
+		},
+		{
+			name:   "empty reference",
+			source: map[string]any{namespaceKey: "default"},
+		},
+		{
+			name:    "unserved kind",
+			source:  map[string]any{"secretRef": map[string]any{"kind": "Unserved", "name": "x"}},
+			wantErr: true,
+		},
+		{
+			name:    "namespaced kind without namespace",
+			source:  map[string]any{"secretRef": map[string]any{"name": "creds"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := svc.Relationship("secretRef", "Secret", kinds)(graphql.ResolveParams{Context: t.Context(), Source: tt.source})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.want == "" {
+				assert.Nil(t, result)
+				return
+			}
+			u := &unstructured.Unstructured{Object: result.(map[string]any)}
+			assert.Equal(t, tt.want, u.GetNamespace()+"/"+u.GetName())
+		})
+	}
+}
+
+func TestInheritNamespace(t *testing.T) {
+	source := map[string]any{
+		"metadata": map[string]any{"namespace": "default"},
+		"spec":     map[string]any{"size": 1},
+		"items":    []any{map[string]any{"size": 2}, "scalar"},
+	}
+	resolve := InheritNamespace(nil)
+
+	spec, err := resolve(graphql.ResolveParams{Source: source, Info: graphql.ResolveInfo{FieldName: "spec"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"size": 1, namespaceKey: "default"}, spec)
+	assert.NotContains(t, source["spec"], namespaceKey)
+
+	items, err := resolve(graphql.ResolveParams{Source: source, Info: graphql.ResolveInfo{FieldName: "items"}})
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{"size": 2, namespaceKey: "default"}, "scalar"}, items)
+
+	nested, err := resolve(graphql.ResolveParams{Source: spec, Info: graphql.ResolveInfo{FieldName: "size"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, nested)
+}
@@ -7,18 +7,24 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"slices"
 	"strings"
 
 	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/changefeed"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/warnings"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/savedqueries"
+	schematypes "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"gopkg.in/yaml.v3"
 
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -28,6 +34,22 @@ import (
 
 type Service struct {
 	runtimeClient client.WithWatch
+	changes       *changefeed.Feed
+	savedQueries  *savedqueries.Store
+	categories    *CategoryFeed
+	emptyValues   EmptyValues
+	federation    bool
+	kindAliases   []KindAlias
+	eventLimits   EventLimits
+	eventsDropped DroppedEventsFunc
+	maxEvents     int
+	fieldNames    *schematypes.FieldNames
+	clusterStatus ClusterStatusFunc
+	tables        *TableReader
+	// statusSubresources holds the kinds with a status subresource.
+	statusSubresources map[schema.GroupVersionKind]bool
+
+	clusterRegistration *ClusterRegistration
 }
 
 func New(runtimeClient client.WithWatch) *Service {
@@ -36,7 +58,10 @@ func New(runtimeClient client.WithWatch) *Service {
 	}
 }
 
-func (r *Service) ListItems(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+// ListItems returns a resolver listing the objects of a kind. sortBy and the
+// paths of orderBy are either field paths or names of the kind's printer
+// columns.
+func (r *Service) ListItems(gvk schema.GroupVersionKind, scope v1.ResourceScope, columns []v1.CustomResourceColumnDefinition) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
 		logger := log.FromContext(p.Context)
 		ctx, span := otel.Tracer("").Start(p.Context, "ListItems", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
@@ -53,29 +78,35 @@ func (r *Service) ListItems(gvk schema.GroupVersionKind, scope v1.ResourceScope)
 		list := &unstructured.UnstructuredList{}
 		list.SetGroupVersionKind(gvk)
 
-		var opts []client.ListOption
-
-		labelSelector, err := GetArg[string](p.Args, LabelSelectorArg, false)
+		opts, err := selectorListOptions(p.Args)
 		if err != nil {
+			logger.Error(err, "Unable to parse given selectors")
 			return nil, err
 		}
-		if labelSelector != "" {
-			selector, err := labels.Parse(labelSelector)
-			if err != nil {
-				logger.WithValues(LabelSelectorArg, labelSelector).Error(err, "Unable to parse given label selector")
-				return nil, err
-			}
-			opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
-		}
 
+		allNamespaces := false
 		if isResourceNamespaceScoped(scope) {
 			namespace, err := GetArg[string](p.Args, NamespaceArg, false)
 			if err != nil {
 				return nil, err
 			}
+			explicitAllNamespaces, err := GetArg[bool](p.Args, AllNamespacesArg, false)
+			if err != nil {
+				return nil, err
+			}
+			if explicitAllNamespaces && namespace != "" {
+				return nil, errAllNamespacesWithNamespace
+			}
+			if explicitAllNamespaces {
+				if err := r.authorizeAllNamespaces(ctx, gvk); err != nil {
+					logger.Error(err, "Unable to list objects in all namespaces")
+					return nil, err
+				}
+			}
 			if namespace != "" {
 				opts = append(opts, client.InNamespace(namespace))
 			}
+			allNamespaces = namespace == ""
 		}
 
 		limit, err := GetArg[int](p.Args, LimitArg, false)
@@ -94,35 +125,72 @@ func (r *Service) ListItems(gvk schema.GroupVersionKind, scope v1.ResourceScope)
 			opts = append(opts, client.Continue(continueToken))
 		}
 
+		atResourceVersion, err := GetArg[string](p.Args, AtResourceVersionArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if atResourceVersion != "" {
+			if continueToken != "" {
+				return nil, errAtResourceVersionWithContinue
+			}
+			opts = append(opts, exactResourceVersion(atResourceVersion))
+		}
+
+		filters, err := getFilters(p.Args)
+		if err != nil {
+			return nil, err
+		}
+
 		if err = r.runtimeClient.List(ctx, list, opts...); err != nil {
 			logger.Error(err, "Unable to list objects")
-			return nil, fmt.Errorf("unable to list objects: %w", err)
+			return nil, fmt.Errorf("unable to list objects: %w", resourceVersionError(atResourceVersion, err))
 		}
+		list.Items = filterItems(list.Items, filters)
 
 		sortBy, err := GetArg[string](p.Args, SortByArg, false)
 		if err != nil {
 			return nil, err
 		}
 
-		if sortBy != "" {
-			if err := validateSortBy(list.Items, sortBy); err != nil {
-				logger.WithValues(SortByArg, sortBy).Error(err, "Invalid sortBy field path")
+		orderBy, err := getOrderBy(p.Args, list.Items, columns)
+		if err != nil {
+			logger.Error(err, "Invalid orderBy")
+			return nil, err
+		}
+		if len(orderBy) == 0 && sortBy != "" {
+			key, err := newSortKey(list.Items, columns, sortBy, false)
+			if err != nil {
+				logger.WithValues(SortByArg, sortBy).Error(err, "Invalid sortBy")
 				return nil, err
 			}
-			slices.SortFunc(list.Items, compareUnstructured(sortBy))
+			orderBy = []sortKey{key}
 		}
+		sortItems(list.Items, orderBy)
 
 		items := make([]map[string]any, len(list.Items))
 		for i, item := range list.Items {
 			items[i] = item.Object
 		}
 
-		return &ListResult{
+		result := &ListResult{
 			ResourceVersion:    list.GetResourceVersion(),
 			Items:              items,
 			Continue:           list.GetContinue(),
 			RemainingItemCount: list.GetRemainingItemCount(),
-		}, nil
+			AllNamespaces:      allNamespaces,
+		}
+
+		if isResourceNamespaceScoped(scope) {
+			groupByNamespace, err := GetArg[bool](p.Args, GroupByNamespaceArg, false)
+			if err != nil {
+				return nil, err
+			}
+			if groupByNamespace {
+				result.ByNamespace = groupItemsByNamespace(list.Items)
+			}
+		}
+
+		return result, nil
 	}
 }
 
@@ -162,8 +230,23 @@ func (r *Service) GetItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) g
 			key.Namespace = namespace
 		}
 
-		// Get the object using the runtime client
-		if err = r.runtimeClient.Get(ctx, key, obj); err != nil {
+		atResourceVersion, err := GetArg[string](p.Args, AtResourceVersionArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if atResourceVersion != "" {
+			obj, err = r.getAtResourceVersion(ctx, gvk, key, atResourceVersion)
+		} else {
+			// Get the object using the runtime client
+			err = r.runtimeClient.Get(ctx, key, obj)
+			if apierrors.IsForbidden(err) {
+				if statusOnly, statusErr := r.getStatusOnly(ctx, gvk, key); statusErr == nil {
+					warnings.Add(ctx, fmt.Sprintf("only the status of %s %s is readable, its other fields are null", gvk.Kind, strings.TrimPrefix(key.String(), "/")))
+					return statusOnly.Object, nil
+				}
+			}
+		}
+		if err != nil {
 			logger.WithValues("name", name, "scope", string(scope)).Error(err, "Unable to get object")
 			return nil, err
 		}
@@ -191,6 +274,35 @@ func (r *Service) GetItemAsYAML(gvk schema.GroupVersionKind, scope v1.ResourceSc
 	}
 }
 
+// ListItemsAsYAML returns a resolver listing objects like ListItems and
+// encoding them as a multi-document YAML string, one document per object.
+// The YAML has no continue token, so the list is never paged.
+func (r *Service) ListItemsAsYAML(gvk schema.GroupVersionKind, scope v1.ResourceScope, columns []v1.CustomResourceColumnDefinition) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		_, span := otel.Tracer("").Start(p.Context, "ListItemsAsYAML", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		p.Args = maps.Clone(p.Args)
+		delete(p.Args, LimitArg)
+		delete(p.Args, ContinueArg)
+
+		out, err := r.ListItems(gvk, scope, columns)(p)
+		if err != nil {
+			return "", err
+		}
+
+		var returnYaml bytes.Buffer
+		encoder := yaml.NewEncoder(&returnYaml)
+		for _, item := range out.(*ListResult).Items {
+			if err = encoder.Encode(item); err != nil {
+				return "", err
+			}
+		}
+
+		return returnYaml.String(), nil
+	}
+}
+
 func (r *Service) CreateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
 		ctx, span := otel.Tracer("").Start(p.Context, "CreateItem", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
@@ -203,7 +315,10 @@ func (r *Service) CreateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope
 			"kind", gvk.Kind,
 		)
 
-		objectInput := p.Args["object"].(map[string]any)
+		objectInput, err := r.getObjectInput(p, ObjectArg)
+		if err != nil {
+			return nil, err
+		}
 
 		obj := &unstructured.Unstructured{
 			Object: objectInput,
@@ -231,12 +346,22 @@ func (r *Service) CreateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope
 			dryRun = []string{"All"}
 		}
 
+		submitted := copyObject(obj.Object)
 		if err := r.runtimeClient.Create(ctx, obj, &client.CreateOptions{DryRun: dryRun}); err != nil {
 			logger.Error(err, "Failed to create object")
 			return nil, err
 		}
 
-		return obj.Object, nil
+		pruned := recordPrunedFields(p, r.prunableFields(gvk, submitted), obj.Object)
+		if len(pruned) > 0 {
+			logger.V(4).Info("API server pruned submitted fields", "fields", pruned)
+		}
+
+		if !dryRunBool {
+			r.recordChange(ctx, changefeed.OperationCreate, gvk, obj)
+		}
+
+		return withPrunedFields(obj.Object, pruned), nil
 	}
 }
 
@@ -253,8 +378,19 @@ func (r *Service) UpdateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope
 			return nil, err
 		}
 
-		objectInput := p.Args[ObjectArg].(map[string]any)
-		patchData, err := json.Marshal(objectInput)
+		objectInput, err := r.getObjectInput(p, ObjectArg)
+		if err != nil {
+			return nil, err
+		}
+		resourceVersion, err := GetArg[string](p.Args, ResourceVersionArg, false)
+		if err != nil {
+			return nil, err
+		}
+		patchInput := objectInput
+		if resourceVersion != "" {
+			patchInput = withResourceVersion(objectInput, resourceVersion)
+		}
+		patchData, err := json.Marshal(patchInput)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal object input: %w", err)
 		}
@@ -283,10 +419,176 @@ func (r *Service) UpdateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope
 		patch := client.RawPatch(types.MergePatchType, patchData)
 		if err := r.runtimeClient.Patch(ctx, obj, patch, &client.PatchOptions{DryRun: dryRun}); err != nil {
 			logger.Error(err, "Failed to patch object")
+			return nil, r.conflictError(ctx, obj, err)
+		}
+
+		pruned := recordPrunedFields(p, r.prunableFields(gvk, copyObject(objectInput)), obj.Object)
+		if len(pruned) > 0 {
+			logger.V(4).Info("API server pruned submitted fields", "fields", pruned)
+		}
+
+		if !dryRunBool {
+			r.recordChange(ctx, changefeed.OperationUpdate, gvk, obj)
+		}
+
+		return withPrunedFields(obj.Object, pruned), nil
+	}
+}
+
+// DefaultFieldManager is the field manager of server-side applies that do not
+// name one.
+const DefaultFieldManager = "kubernetes-graphql-gateway"
+
+// ApplyItem server-side applies an object. The object is the complete intent
+// of the field manager: fields it applied before and omits now are removed,
+// and fields owned by other managers conflict unless force is set.
+func (r *Service) ApplyItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		logger := log.FromContext(p.Context)
+		ctx, span := otel.Tracer("").Start(p.Context, "ApplyItem", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		logger = logger.WithValues("operation", "apply", "kind", gvk.Kind)
+
+		obj, objectInput, opts, err := r.applyRequest(p, gvk, scope)
+		if err != nil {
 			return nil, err
 		}
 
-		return obj.Object, nil
+		dryRunBool, err := GetArg[bool](p.Args, DryRunArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if dryRunBool {
+			opts.DryRun = []string{"All"}
+		}
+
+		patchData, err := json.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal object input: %w", err)
+		}
+
+		patch := client.RawPatch(types.ApplyPatchType, patchData)
+		if err := r.runtimeClient.Patch(ctx, obj, patch, opts); err != nil {
+			logger.Error(err, "Failed to apply object")
+			return nil, err
+		}
+
+		pruned := recordPrunedFields(p, r.prunableFields(gvk, copyObject(objectInput)), obj.Object)
+		if len(pruned) > 0 {
+			logger.V(4).Info("API server pruned submitted fields", "fields", pruned)
+		}
+
+		if !dryRunBool {
+			r.recordChange(ctx, changefeed.OperationApply, gvk, obj)
+		}
+
+		return withPrunedFields(obj.Object, pruned), nil
+	}
+}
+
+// applyRequest returns the object and the options of a server-side apply
+// built from the arguments of an apply mutation, without dry run.
+func (r *Service) applyRequest(p graphql.ResolveParams, gvk schema.GroupVersionKind, scope v1.ResourceScope) (*unstructured.Unstructured, map[string]any, *client.PatchOptions, error) {
+	objectInput, err := r.getObjectInput(p, ObjectArg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	obj := &unstructured.Unstructured{Object: copyObject(objectInput)}
+	obj.SetGroupVersionKind(gvk)
+
+	if isResourceNamespaceScoped(scope) {
+		namespace, err := GetArg[string](p.Args, NamespaceArg, true)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		obj.SetNamespace(namespace)
+	}
+
+	if obj.GetName() == "" {
+		return nil, nil, nil, errors.New("object metadata.name is required")
+	}
+
+	fieldManager, err := GetArg[string](p.Args, FieldManagerArg, false)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if fieldManager == "" {
+		fieldManager = DefaultFieldManager
+	}
+
+	force, err := GetArg[bool](p.Args, ForceArg, false)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return obj, objectInput, &client.PatchOptions{
+		Force:        &force,
+		FieldManager: fieldManager,
+	}, nil
+}
+
+// UpdateItemStatus merge-patches the status subresource of an object. Writes
+// through the main resource ignore status changes for resources with a status
+// subresource.
+func (r *Service) UpdateItemStatus(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		logger := log.FromContext(p.Context)
+		ctx, span := otel.Tracer("").Start(p.Context, "UpdateItemStatus", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		logger = logger.WithValues("operation", "updateStatus", "kind", gvk.Kind)
+
+		name, err := GetArg[string](p.Args, NameArg, true)
+		if err != nil {
+			return nil, err
+		}
+
+		statusInput := map[string]any{"status": schematypes.InputToObject(p.Args[StatusArg], argumentType(p, StatusArg), r.fieldNames)}
+		patchData, err := json.Marshal(statusInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal status input: %w", err)
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		obj.SetName(name)
+
+		if isResourceNamespaceScoped(scope) {
+			namespace, err := GetArg[string](p.Args, NamespaceArg, true)
+			if err != nil {
+				return nil, err
+			}
+			obj.SetNamespace(namespace)
+		}
+
+		dryRunBool, err := GetArg[bool](p.Args, DryRunArg, false)
+		if err != nil {
+			return nil, err
+		}
+		var dryRun []string
+		if dryRunBool {
+			dryRun = []string{"All"}
+		}
+
+		patch := client.RawPatch(types.MergePatchType, patchData)
+		opts := &client.SubResourcePatchOptions{PatchOptions: client.PatchOptions{DryRun: dryRun}}
+		if err := r.runtimeClient.Status().Patch(ctx, obj, patch, opts); err != nil {
+			logger.Error(err, "Failed to patch object status")
+			return nil, err
+		}
+
+		pruned := recordPrunedFields(p, copyObject(statusInput), obj.Object)
+		if len(pruned) > 0 {
+			logger.V(4).Info("API server pruned submitted fields", "fields", pruned)
+		}
+
+		if !dryRunBool {
+			r.recordChange(ctx, changefeed.OperationUpdate, gvk, obj)
+		}
+
+		return withPrunedFields(obj.Object, pruned), nil
 	}
 }
 
@@ -324,8 +626,80 @@ func (r *Service) DeleteItem(gvk schema.GroupVersionKind, scope v1.ResourceScope
 			dryRun = []string{"All"}
 		}
 
-		if err := r.runtimeClient.Delete(ctx, obj, &client.DeleteOptions{DryRun: dryRun}); err != nil {
+		opts, err := deleteOptions(p.Args, dryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.runtimeClient.Delete(ctx, obj, opts); err != nil {
 			logger.Error(err, "Failed to delete object")
+			return nil, r.conflictError(ctx, obj, err)
+		}
+
+		if !dryRunBool {
+			r.recordChange(ctx, changefeed.OperationDelete, gvk, obj)
+		}
+
+		return true, nil
+	}
+}
+
+// DeleteAllOf deletes all objects of a kind matching the selectors with a
+// single deletecollection request. Without selectors, all objects in the
+// namespace are deleted.
+func (r *Service) DeleteAllOf(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		logger := log.FromContext(p.Context)
+		ctx, span := otel.Tracer("").Start(p.Context, "DeleteAllOf", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		logger = logger.WithValues("operation", "deleteAllOf", "kind", gvk.Kind)
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+
+		var opts []client.DeleteAllOfOption
+
+		if isResourceNamespaceScoped(scope) {
+			namespace, err := GetArg[string](p.Args, NamespaceArg, true)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, client.InNamespace(namespace))
+		}
+
+		labelSelector, err := labelSelectorFromArgs(p.Args)
+		if err != nil {
+			logger.Error(err, "Unable to parse given label selector")
+			return nil, err
+		}
+		if labelSelector != nil {
+			opts = append(opts, client.MatchingLabelsSelector{Selector: labelSelector})
+		}
+
+		fieldSelector, err := GetArg[string](p.Args, FieldSelectorArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if fieldSelector != "" {
+			selector, err := fields.ParseSelector(fieldSelector)
+			if err != nil {
+				logger.WithValues(FieldSelectorArg, fieldSelector).Error(err, "Unable to parse given field selector")
+				return nil, err
+			}
+			opts = append(opts, client.MatchingFieldsSelector{Selector: selector})
+		}
+
+		dryRunBool, err := GetArg[bool](p.Args, DryRunArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if dryRunBool {
+			opts = append(opts, client.DryRunAll)
+		}
+
+		if err := r.runtimeClient.DeleteAllOf(ctx, obj, opts...); err != nil {
+			logger.Error(err, "Failed to delete objects")
 			return nil, err
 		}
 
@@ -335,7 +709,8 @@ func (r *Service) DeleteItem(gvk schema.GroupVersionKind, scope v1.ResourceScope
 
 // ApplyYaml returns a resolver that applies a single YAML document to the
 // Kubernetes API server with create-or-update semantics: if the resource
-// exists it is updated, otherwise it is created.
+// exists it is updated, otherwise it is created. It is deprecated in favor of
+// ApplyManifests, which applies multi-document YAML.
 func (r *Service) ApplyYaml() graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
 		ctx, span := otel.Tracer("").Start(p.Context, "ApplyYaml")
@@ -372,31 +747,28 @@ func (r *Service) ApplyYaml() graphql.FieldResolveFn {
 			"namespace", namespace,
 		)
 
-		if name == "" {
-			if err := r.runtimeClient.Create(ctx, obj); err != nil {
-				logger.Error(err, "Failed to create object with generateName")
+		submitted := copyObject(parsed)
+
+		target, result, err := applyManifest(ctx, r.runtimeClient, obj)
+		if err != nil {
+			logger.Error(err, "Failed to apply YAML")
+			if name == "" {
 				return nil, fmt.Errorf("failed to create resource %s: %w", gvk.Kind, err)
 			}
-			return obj.Object, nil
-		}
-
-		target := &unstructured.Unstructured{}
-		target.SetGroupVersionKind(gvk)
-		target.SetName(name)
-		target.SetNamespace(namespace)
-
-		if _, err := controllerutil.CreateOrUpdate(ctx, r.runtimeClient, target, func() error {
-			rv := target.GetResourceVersion()
-			uid := target.GetUID()
-			target.Object = parsed
-			target.SetResourceVersion(rv)
-			target.SetUID(uid)
-			return nil
-		}); err != nil {
-			logger.Error(err, "Failed to apply YAML")
 			return nil, fmt.Errorf("failed to apply resource %s/%s: %w", gvk.Kind, name, err)
 		}
 
+		if pruned := recordPrunedFields(p, r.prunableFields(gvk, submitted), target.Object); len(pruned) > 0 {
+			logger.V(4).Info("API server pruned submitted fields", "fields", pruned)
+		}
+
+		switch result {
+		case controllerutil.OperationResultCreated:
+			r.recordChange(ctx, changefeed.OperationCreate, gvk, target)
+		case controllerutil.OperationResultUpdated:
+			r.recordChange(ctx, changefeed.OperationUpdate, gvk, target)
+		}
+
 		return target.Object, nil
 	}
 }
@@ -407,35 +779,64 @@ func (r *Service) CommonResolver() graphql.FieldResolveFn {
 	}
 }
 
-func compareUnstructured(fieldPath string) func(a, b unstructured.Unstructured) int {
-	return func(a, b unstructured.Unstructured) int {
-		segments := strings.Split(fieldPath, ".")
-
-		aVal, foundA, errA := unstructured.NestedFieldNoCopy(a.Object, segments...)
-		bVal, foundB, errB := unstructured.NestedFieldNoCopy(b.Object, segments...)
-		if errA != nil || errB != nil || !foundA || !foundB {
-			return 0
-		}
-
-		switch av := aVal.(type) {
-		case string:
-			return cmp.Compare(av, bVal.(string))
-		case float64:
-			return cmp.Compare(av, bVal.(float64))
-		case int64:
-			return cmp.Compare(av, bVal.(int64))
-		case bool:
-			// bool not in cmp.Ordered; false < true
-			if av == bVal.(bool) {
+// compareValues compares two values of an object. Integers and floats are
+// compared as numbers and strings with compareStrings; values of different
+// types compare equal.
+func compareValues(aVal, bVal any) int {
+	switch av := aVal.(type) {
+	case string:
+		if bv, ok := bVal.(string); ok {
+			return compareStrings(av, bv)
+		}
+	case int64:
+		if bv, ok := bVal.(int64); ok {
+			return cmp.Compare(av, bv)
+		}
+		if bv, ok := bVal.(float64); ok {
+			return cmp.Compare(float64(av), bv)
+		}
+	case float64:
+		if bv, ok := bVal.(float64); ok {
+			return cmp.Compare(av, bv)
+		}
+		if bv, ok := bVal.(int64); ok {
+			return cmp.Compare(av, float64(bv))
+		}
+	case bool:
+		// bool not in cmp.Ordered; false < true
+		if bv, ok := bVal.(bool); ok {
+			if av == bv {
 				return 0
-			} else if bVal.(bool) {
+			} else if bv {
 				return -1
 			}
 			return 1
 		}
+	}
+
+	return 0
+}
 
-		return 0
+// groupItemsByNamespace groups items by namespace. Groups are ordered by
+// namespace name; items keep their order within a group.
+func groupItemsByNamespace(items []unstructured.Unstructured) []NamespaceGroup {
+	index := map[string]int{}
+	groups := []NamespaceGroup{}
+	for _, item := range items {
+		ns := item.GetNamespace()
+		i, ok := index[ns]
+		if !ok {
+			i = len(groups)
+			index[ns] = i
+			groups = append(groups, NamespaceGroup{Namespace: ns, Items: []map[string]any{}})
+		}
+		groups[i].Items = append(groups[i].Items, item.Object)
 	}
+
+	slices.SortStableFunc(groups, func(a, b NamespaceGroup) int {
+		return cmp.Compare(a.Namespace, b.Namespace)
+	})
+	return groups
 }
 
 // parseAndValidateYAML decodes a YAML string, rejects multi-document input,
@@ -453,25 +854,34 @@ func parseAndValidateYAML(yamlStr string) (map[string]any, error) {
 		return nil, errors.New("multi-document YAML is not supported; provide a single document")
 	}
 
+	if err := validateManifest(parsed); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// validateManifest checks that a decoded YAML document is a Kubernetes
+// object with apiVersion, kind and a name or generateName.
+func validateManifest(parsed map[string]any) error {
 	apiVersion, ok := parsed["apiVersion"].(string)
 	if !ok || apiVersion == "" {
-		return nil, errors.New("apiVersion is required and must be a string")
+		return errors.New("apiVersion is required and must be a string")
 	}
 
 	kind, ok := parsed["kind"].(string)
 	if !ok || kind == "" {
-		return nil, errors.New("kind is required and must be a string")
+		return errors.New("kind is required and must be a string")
 	}
 
 	metadata, ok := parsed["metadata"].(map[string]any)
 	if !ok || metadata == nil {
-		return nil, errors.New("metadata is required")
+		return errors.New("metadata is required")
 	}
 	name, _ := metadata["name"].(string)
 	generateName, _ := metadata["generateName"].(string)
 	if name == "" && generateName == "" {
-		return nil, errors.New("metadata.name or metadata.generateName is required")
+		return errors.New("metadata.name or metadata.generateName is required")
 	}
 
-	return parsed, nil
+	return nil
 }
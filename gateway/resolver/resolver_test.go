@@ -0,0 +1,318 @@
+package resolver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestListItems_Pagination(t *testing.T) {
+	remaining := int64(7)
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		wantLimit     int64
+		wantContinue  string
+		wantRemaining *int64
+	}{
+		{
+			name: "no pagination arguments",
+			args: map[string]any{},
+		},
+		{
+			name:          "limit and continue are passed through",
+			args:          map[string]any{LimitArg: 2, ContinueArg: "token-1"},
+			wantLimit:     2,
+			wantContinue:  "token-1",
+			wantRemaining: &remaining,
+		},
+		{
+			name: "non-positive limit is ignored",
+			args: map[string]any{LimitArg: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotOpts client.ListOptions
+			fc := &fakeClient{
+				listFn: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+					gotOpts.ApplyOptions(opts)
+
+					ul := list.(*unstructured.UnstructuredList)
+					ul.SetResourceVersion("42")
+					ul.Items = []unstructured.Unstructured{*makeUnstructuredObj("a", "default", "1")}
+					if gotOpts.Limit > 0 {
+						ul.SetContinue("token-2")
+						ul.SetRemainingItemCount(&remaining)
+					}
+					return nil
+				},
+			}
+
+			svc := &Service{runtimeClient: fc}
+			out, err := svc.ListItems(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, v1.NamespaceScoped, nil)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    tt.args,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantLimit, gotOpts.Limit)
+			assert.Equal(t, tt.wantContinue, gotOpts.Continue)
+
+			result, ok := out.(*ListResult)
+			require.True(t, ok)
+			assert.Equal(t, "42", result.ResourceVersion)
+			assert.Len(t, result.Items, 1)
+			assert.Equal(t, tt.wantRemaining, result.RemainingItemCount)
+			if tt.wantLimit > 0 {
+				assert.Equal(t, "token-2", result.Continue)
+			} else {
+				assert.Empty(t, result.Continue)
+			}
+		})
+	}
+}
+
+func TestListItems_FieldSelector(t *testing.T) {
+	tests := []struct {
+		name          string
+		fieldSelector string
+		wantSelector  string
+		wantErr       bool
+	}{
+		{
+			name:          "single requirement",
+			fieldSelector: "status.phase=Running",
+			wantSelector:  "status.phase=Running",
+		},
+		{
+			name:          "multiple requirements",
+			fieldSelector: "metadata.name!=foo,status.phase=Running",
+			wantSelector:  "metadata.name!=foo,status.phase=Running",
+		},
+		{
+			name:          "invalid selector",
+			fieldSelector: "status.phase",
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotOpts client.ListOptions
+			fc := &fakeClient{
+				listFn: func(_ context.Context, _ client.ObjectList, opts ...client.ListOption) error {
+					gotOpts.ApplyOptions(opts)
+					return nil
+				},
+			}
+
+			svc := &Service{runtimeClient: fc}
+			_, err := svc.ListItems(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, v1.NamespaceScoped, nil)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    map[string]any{FieldSelectorArg: tt.fieldSelector},
+			})
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Equal(t, int32(0), fc.listCalls)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, gotOpts.FieldSelector)
+			assert.Equal(t, tt.wantSelector, gotOpts.FieldSelector.String())
+		})
+	}
+}
+
+func TestListItems_GroupByNamespace(t *testing.T) {
+	fc := &fakeClient{
+		listFn: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+			list.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{
+				*makeUnstructuredObj("b", "team-b", "1"),
+				*makeUnstructuredObj("a", "team-a", "1"),
+				*makeUnstructuredObj("c", "team-b", "1"),
+			}
+			return nil
+		},
+	}
+	svc := &Service{runtimeClient: fc}
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	tests := []struct {
+		name       string
+		scope      v1.ResourceScope
+		args       map[string]any
+		wantGroups []NamespaceGroup
+	}{
+		{
+			name:  "not requested",
+			scope: v1.NamespaceScoped,
+			args:  map[string]any{GroupByNamespaceArg: false},
+		},
+		{
+			name:  "grouped and ordered by namespace",
+			scope: v1.NamespaceScoped,
+			args:  map[string]any{GroupByNamespaceArg: true, SortByArg: ""},
+			wantGroups: []NamespaceGroup{
+				{Namespace: "team-a", Items: []map[string]any{makeUnstructuredObj("a", "team-a", "1").Object}},
+				{Namespace: "team-b", Items: []map[string]any{
+					makeUnstructuredObj("b", "team-b", "1").Object,
+					makeUnstructuredObj("c", "team-b", "1").Object,
+				}},
+			},
+		},
+		{
+			name:  "ignored for cluster-scoped resources",
+			scope: v1.ClusterScoped,
+			args:  map[string]any{GroupByNamespaceArg: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := svc.ListItems(gvk, tt.scope, nil)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    tt.args,
+			})
+			require.NoError(t, err)
+
+			result, ok := out.(*ListResult)
+			require.True(t, ok)
+			assert.Len(t, result.Items, 3)
+			assert.Equal(t, tt.wantGroups, result.ByNamespace)
+		})
+	}
+}
+
+func TestListItemsAsYAML(t *testing.T) {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	tests := []struct {
+		name  string
+		items []unstructured.Unstructured
+		want  string
+	}{
+		{
+			name: "one document per object",
+			items: []unstructured.Unstructured{
+				*makeUnstructuredObj("b", "team-a", "1"),
+				*makeUnstructuredObj("a", "team-a", "1"),
+			},
+			want: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n    name: a\n    namespace: team-a\n    resourceVersion: \"1\"\n" +
+				"---\n" +
+				"apiVersion: v1\nkind: ConfigMap\nmetadata:\n    name: b\n    namespace: team-a\n    resourceVersion: \"1\"\n",
+		},
+		{
+			name: "no objects",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fc := &fakeClient{
+				listFn: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+					listOpts := &client.ListOptions{}
+					listOpts.ApplyOptions(opts)
+					assert.Zero(t, listOpts.Limit, "the YAML must hold the whole list")
+					list.(*unstructured.UnstructuredList).Items = tt.items
+					return nil
+				},
+			}
+
+			out, err := (&Service{runtimeClient: fc}).ListItemsAsYAML(gvk, v1.NamespaceScoped, nil)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    map[string]any{SortByArg: "metadata.name", LimitArg: 1},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out)
+		})
+	}
+}
+
+func TestCreateAndUpdateItem_Metadata(t *testing.T) {
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	svc := New(cl)
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	key := client.ObjectKey{Namespace: "default", Name: "settings"}
+
+	_, err := svc.CreateItem(gvk, v1.NamespaceScoped)(graphql.ResolveParams{
+		Context: t.Context(),
+		Args: map[string]any{
+			NamespaceArg: "default",
+			ObjectArg: map[string]any{"metadata": map[string]any{
+				"name":        "settings",
+				"labels":      map[string]any{"app": "web"},
+				"annotations": map[string]any{"owner": "team-a"},
+				"finalizers":  []any{"example.com/cleanup"},
+			}},
+		},
+	})
+	require.NoError(t, err)
+
+	var created corev1.ConfigMap
+	require.NoError(t, cl.Get(t.Context(), key, &created))
+	assert.Equal(t, map[string]string{"app": "web"}, created.Labels)
+	assert.Equal(t, map[string]string{"owner": "team-a"}, created.Annotations)
+	assert.Equal(t, []string{"example.com/cleanup"}, created.Finalizers)
+
+	_, err = svc.UpdateItem(gvk, v1.NamespaceScoped)(graphql.ResolveParams{
+		Context: t.Context(),
+		Args: map[string]any{
+			NameArg:      "settings",
+			NamespaceArg: "default",
+			ObjectArg: map[string]any{"metadata": map[string]any{
+				"annotations": map[string]any{"reviewed": "true"},
+				"finalizers":  []any{},
+			}},
+		},
+	})
+	require.NoError(t, err)
+
+	var updated corev1.ConfigMap
+	require.NoError(t, cl.Get(t.Context(), key, &updated))
+	assert.Equal(t, map[string]string{"owner": "team-a", "reviewed": "true"}, updated.Annotations)
+	assert.Empty(t, updated.Finalizers)
+	assert.Equal(t, map[string]string{"app": "web"}, updated.Labels)
+}
+
+func TestCreateItem_GenerateName(t *testing.T) {
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	create := New(cl).CreateItem(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, v1.NamespaceScoped)
+
+	result, err := create(graphql.ResolveParams{
+		Context: t.Context(),
+		Args: map[string]any{
+			NamespaceArg: "default",
+			ObjectArg:    map[string]any{"metadata": map[string]any{"generateName": "settings-"}},
+		},
+	})
+	require.NoError(t, err)
+
+	name, _, _ := unstructured.NestedString(result.(map[string]any), "metadata", "name")
+	assert.True(t, strings.HasPrefix(name, "settings-") && len(name) > len("settings-"), "server-assigned name %q", name)
+	var created corev1.ConfigMap
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: name}, &created))
+
+	_, err = create(graphql.ResolveParams{
+		Context: t.Context(),
+		Args: map[string]any{
+			NamespaceArg: "default",
+			ObjectArg:    map[string]any{"metadata": map[string]any{"labels": map[string]any{"app": "web"}}},
+		},
+	})
+	assert.EqualError(t, err, "object metadata.name or metadata.generateName is required")
+}
@@ -0,0 +1,64 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// exactResourceVersion is a list option requesting the state at exactly a
+// resource version instead of the most recent one.
+type exactResourceVersion string
+
+func (rv exactResourceVersion) ApplyToList(opts *client.ListOptions) {
+	if opts.Raw == nil {
+		opts.Raw = &metav1.ListOptions{}
+	}
+	opts.Raw.ResourceVersion = string(rv)
+	opts.Raw.ResourceVersionMatch = metav1.ResourceVersionMatchExact
+}
+
+// getAtResourceVersion reads an object as it was at a resource version.
+// GET requests cannot match a resource version exactly, so the object is
+// listed by name instead.
+func (r *Service) getAtResourceVersion(ctx context.Context, gvk schema.GroupVersionKind, key client.ObjectKey, resourceVersion string) (*unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+	opts := []client.ListOption{
+		client.MatchingFieldsSelector{Selector: fields.OneTermEqualSelector("metadata.name", key.Name)},
+		exactResourceVersion(resourceVersion),
+	}
+	if key.Namespace != "" {
+		opts = append(opts, client.InNamespace(key.Namespace))
+	}
+
+	if err := r.runtimeClient.List(ctx, list, opts...); err != nil {
+		return nil, resourceVersionError(resourceVersion, err)
+	}
+	if len(list.Items) == 0 {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: strings.ToLower(gvk.Kind)}, key.Name)
+	}
+	return &list.Items[0], nil
+}
+
+// resourceVersionError explains errors of reads pinned to a resource version
+// that the API server no longer keeps.
+func resourceVersionError(resourceVersion string, err error) error {
+	if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+		return fmt.Errorf("resource version %s is no longer available, the API server has compacted it: %w", resourceVersion, err)
+	}
+	return err
+}
+
+// errAtResourceVersionWithContinue is returned for lists that set both
+// atResourceVersion and continue.
+var errAtResourceVersionWithContinue = errors.New("atResourceVersion cannot be combined with continue, continue tokens already pin the resource version of the first page")
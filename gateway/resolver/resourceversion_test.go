@@ -0,0 +1,102 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestListItems_AtResourceVersion(t *testing.T) {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		listErr error
+		wantErr string
+	}{
+		{
+			name: "exact resource version",
+			args: map[string]any{AtResourceVersionArg: "42"},
+		},
+		{
+			name:    "compacted resource version",
+			args:    map[string]any{AtResourceVersionArg: "42"},
+			listErr: apierrors.NewResourceExpired("too old resource version: 42 (100)"),
+			wantErr: "unable to list objects: resource version 42 is no longer available, the API server has compacted it: too old resource version: 42 (100)",
+		},
+		{
+			name:    "combined with continue",
+			args:    map[string]any{AtResourceVersionArg: "42", ContinueArg: "token"},
+			wantErr: "atResourceVersion cannot be combined with continue, continue tokens already pin the resource version of the first page",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotOpts client.ListOptions
+			fc := &fakeClient{
+				listFn: func(_ context.Context, _ client.ObjectList, opts ...client.ListOption) error {
+					gotOpts.ApplyOptions(opts)
+					return tt.listErr
+				},
+			}
+
+			_, err := (&Service{runtimeClient: fc}).ListItems(gvk, v1.NamespaceScoped, nil)(graphql.ResolveParams{Context: t.Context(), Args: tt.args})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			require.NotNil(t, gotOpts.Raw)
+			assert.Equal(t, "42", gotOpts.Raw.ResourceVersion)
+			assert.Equal(t, metav1.ResourceVersionMatchExact, gotOpts.Raw.ResourceVersionMatch)
+		})
+	}
+}
+
+func TestGetItem_AtResourceVersion(t *testing.T) {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	var gotOpts client.ListOptions
+	var gotKind string
+	items := []unstructured.Unstructured{*makeUnstructuredObj("cfg", "default", "41")}
+	fc := &fakeClient{
+		listFn: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+			gotOpts = client.ListOptions{}
+			gotOpts.ApplyOptions(opts)
+			ul := list.(*unstructured.UnstructuredList)
+			gotKind = ul.GetKind()
+			ul.Items = items
+			return nil
+		},
+	}
+	resolve := (&Service{runtimeClient: fc}).GetItem(gvk, v1.NamespaceScoped)
+	args := map[string]any{NameArg: "cfg", NamespaceArg: "default", AtResourceVersionArg: "42"}
+
+	out, err := resolve(graphql.ResolveParams{Context: t.Context(), Args: args})
+	require.NoError(t, err)
+
+	assert.Equal(t, "cfg", out.(map[string]any)["metadata"].(map[string]any)["name"])
+	assert.Equal(t, "ConfigMapList", gotKind)
+	assert.Equal(t, "default", gotOpts.Namespace)
+	assert.Equal(t, "metadata.name=cfg", gotOpts.FieldSelector.String())
+	require.NotNil(t, gotOpts.Raw)
+	assert.Equal(t, "42", gotOpts.Raw.ResourceVersion)
+	assert.Equal(t, metav1.ResourceVersionMatchExact, gotOpts.Raw.ResourceVersionMatch)
+
+	items = nil
+	_, err = resolve(graphql.ResolveParams{Context: t.Context(), Args: args})
+	assert.True(t, apierrors.IsNotFound(err), "object missing at the resource version: %v", err)
+}
@@ -0,0 +1,149 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/savedqueries"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+)
+
+// Arguments of the saved query operations.
+const (
+	DocumentArg      = "document"
+	DescriptionArg   = "description"
+	SharedArg        = "shared"
+	IncludeSharedArg = "includeShared"
+)
+
+// SavedQueryArgs returns the arguments of the saveQuery mutation.
+func SavedQueryArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		NameArg: &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "Name of the query, replacing your query of the same name",
+		},
+		DocumentArg: &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "GraphQL document of the query",
+		},
+		DescriptionArg: &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "What the query is for",
+		},
+		SharedArg: &graphql.ArgumentConfig{
+			Type:         graphql.Boolean,
+			DefaultValue: false,
+			Description:  "Lists the query for other users of the cluster too",
+		},
+	}
+}
+
+// WithSavedQueries enables the saved query operations, storing the queries
+// in store.
+func (r *Service) WithSavedQueries(store *savedqueries.Store) *Service {
+	r.savedQueries = store
+	return r
+}
+
+// SavedQueries returns the store of saved queries, or nil if disabled.
+func (r *Service) SavedQueries() *savedqueries.Store {
+	return r.savedQueries
+}
+
+// ListSavedQueries returns a resolver listing the queries the user saved for
+// the request's cluster and, unless includeShared is false, those shared by
+// other users.
+func (r *Service) ListSavedQueries() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		user, err := r.currentUser(p.Context)
+		if err != nil {
+			return nil, err
+		}
+		includeShared := true
+		if p.Args[IncludeSharedArg] != nil {
+			if includeShared, err = GetArg[bool](p.Args, IncludeSharedArg, false); err != nil {
+				return nil, err
+			}
+		}
+		return r.savedQueries.List(changeCluster(p.Context), user, includeShared), nil
+	}
+}
+
+// SaveQuery returns a resolver saving a query under the user's name. The
+// document must parse, it is validated against the schema when executed.
+func (r *Service) SaveQuery() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		user, err := r.currentUser(p.Context)
+		if err != nil {
+			return nil, err
+		}
+		name, err := GetArg[string](p.Args, NameArg, true)
+		if err != nil {
+			return nil, err
+		}
+		document, err := GetArg[string](p.Args, DocumentArg, true)
+		if err != nil {
+			return nil, err
+		}
+		description, err := GetArg[string](p.Args, DescriptionArg, false)
+		if err != nil {
+			return nil, err
+		}
+		shared, err := GetArg[bool](p.Args, SharedArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(document)})}); err != nil {
+			return nil, fmt.Errorf("invalid document: %w", err)
+		}
+
+		return r.savedQueries.Save(savedqueries.Query{
+			Cluster:     changeCluster(p.Context),
+			Owner:       user,
+			Name:        name,
+			Description: description,
+			Document:    document,
+			Shared:      shared,
+		})
+	}
+}
+
+// DeleteSavedQuery returns a resolver deleting one of the user's queries.
+func (r *Service) DeleteSavedQuery() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		user, err := r.currentUser(p.Context)
+		if err != nil {
+			return nil, err
+		}
+		name, err := GetArg[string](p.Args, NameArg, true)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.savedQueries.Delete(changeCluster(p.Context), user, name); err != nil {
+			return nil, err
+		}
+		return true, nil
+	}
+}
+
+// currentUser returns the name of the user of the request's token, as the
+// cluster sees it.
+func (r *Service) currentUser(ctx context.Context) (string, error) {
+	if r.savedQueries == nil {
+		return "", errors.New("saved queries are disabled")
+	}
+	review := &authenticationv1.SelfSubjectReview{}
+	if err := r.runtimeClient.Create(ctx, review); err != nil {
+		return "", fmt.Errorf("failed to identify user: %w", err)
+	}
+	if review.Status.UserInfo.Username == "" {
+		return "", errors.New("failed to identify user: cluster returned no user name")
+	}
+	return review.Status.UserInfo.Username, nil
+}
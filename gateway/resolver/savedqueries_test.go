@@ -0,0 +1,87 @@
+package resolver
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/savedqueries"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newUserClient returns a client the cluster identifies as user.
+func newUserClient(user string) client.WithWatch {
+	return fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if review, ok := obj.(*authenticationv1.SelfSubjectReview); ok {
+					review.Status.UserInfo.Username = user
+					return nil
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+}
+
+func TestSavedQueries(t *testing.T) {
+	store, err := savedqueries.New(filepath.Join(t.TempDir(), "queries.json"))
+	require.NoError(t, err)
+	alice := New(newUserClient("alice")).WithSavedQueries(store)
+	bob := New(newUserClient("bob")).WithSavedQueries(store)
+	ctx := utilscontext.SetCluster(t.Context(), "c1")
+
+	save := func(r *Service, args map[string]any) (any, error) {
+		return r.SaveQuery()(graphql.ResolveParams{Context: ctx, Args: args})
+	}
+	list := func(r *Service, args map[string]any) []savedqueries.Query {
+		result, err := r.ListSavedQueries()(graphql.ResolveParams{Context: ctx, Args: args})
+		require.NoError(t, err)
+		return result.([]savedqueries.Query)
+	}
+
+	saved, err := save(alice, map[string]any{NameArg: "pods", DocumentArg: "{ pods { name } }", SharedArg: true})
+	require.NoError(t, err)
+	assert.Equal(t, "c1", saved.(savedqueries.Query).Cluster)
+	assert.Equal(t, "alice", saved.(savedqueries.Query).Owner)
+
+	_, err = save(alice, map[string]any{NameArg: "broken", DocumentArg: "{ pods {"})
+	assert.ErrorContains(t, err, "invalid document")
+
+	_, err = save(bob, map[string]any{NameArg: "mine", DocumentArg: "{ a }"})
+	require.NoError(t, err)
+
+	assert.Len(t, list(bob, map[string]any{}), 2)
+	assert.Len(t, list(bob, map[string]any{IncludeSharedArg: false}), 1)
+	assert.Len(t, list(alice, map[string]any{}), 1)
+
+	_, err = bob.ListSavedQueries()(graphql.ResolveParams{Context: ctx, Args: map[string]any{IncludeSharedArg: 1}})
+	assert.ErrorContains(t, err, "invalid type for argument: includeShared")
+
+	_, err = bob.DeleteSavedQuery()(graphql.ResolveParams{Context: ctx, Args: map[string]any{NameArg: "pods"}})
+	assert.ErrorIs(t, err, savedqueries.ErrNotFound)
+
+	deleted, err := alice.DeleteSavedQuery()(graphql.ResolveParams{Context: ctx, Args: map[string]any{NameArg: "pods"}})
+	require.NoError(t, err)
+	assert.Equal(t, true, deleted)
+	assert.Len(t, list(bob, map[string]any{}), 1)
+}
+
+func TestSavedQueries_UnknownUser(t *testing.T) {
+	store, err := savedqueries.New(filepath.Join(t.TempDir(), "queries.json"))
+	require.NoError(t, err)
+	r := New(newUserClient("")).WithSavedQueries(store)
+
+	_, err = r.ListSavedQueries()(graphql.ResolveParams{Context: t.Context(), Args: map[string]any{}})
+	assert.EqualError(t, err, "failed to identify user: cluster returned no user name")
+}
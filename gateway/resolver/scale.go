@@ -0,0 +1,127 @@
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/changefeed"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const scaleSubresource = "scale"
+
+// scaleGVK is the kind served by the scale subresource of all resources.
+var scaleGVK = schema.GroupVersionKind{Group: "autoscaling", Version: "v1", Kind: "Scale"}
+
+// GetScale returns a resolver reading the scale subresource of the object
+// the field belongs to.
+func (r *Service) GetScale(gvk schema.GroupVersionKind) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, "GetScale", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		source, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, errors.New("scale is only available on objects")
+		}
+
+		obj := &unstructured.Unstructured{Object: source}
+		parent := &unstructured.Unstructured{}
+		parent.SetGroupVersionKind(gvk)
+		parent.SetNamespace(obj.GetNamespace())
+		parent.SetName(obj.GetName())
+
+		scale := newScale()
+		if err := r.runtimeClient.SubResource(scaleSubresource).Get(ctx, parent, scale); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to get scale", "kind", gvk.Kind, "name", parent.GetName())
+			return nil, err
+		}
+
+		return scale.Object, nil
+	}
+}
+
+// ScaleItem sets the replicas of an object through its scale subresource,
+// so other fields of the object are neither read nor written.
+func (r *Service) ScaleItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		logger := log.FromContext(p.Context)
+		ctx, span := otel.Tracer("").Start(p.Context, "ScaleItem", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		logger = logger.WithValues("operation", "scale", "kind", gvk.Kind)
+
+		name, err := GetArg[string](p.Args, NameArg, true)
+		if err != nil {
+			return nil, err
+		}
+
+		replicas, err := GetArg[int](p.Args, ReplicasArg, true)
+		if err != nil {
+			return nil, err
+		}
+		if replicas < 0 {
+			return nil, fmt.Errorf("%s must not be negative", ReplicasArg)
+		}
+
+		parent := &unstructured.Unstructured{}
+		parent.SetGroupVersionKind(gvk)
+		parent.SetName(name)
+
+		if isResourceNamespaceScoped(scope) {
+			namespace, err := GetArg[string](p.Args, NamespaceArg, true)
+			if err != nil {
+				return nil, err
+			}
+			parent.SetNamespace(namespace)
+		}
+
+		dryRunBool, err := GetArg[bool](p.Args, DryRunArg, false)
+		if err != nil {
+			return nil, err
+		}
+		var dryRun []string
+		if dryRunBool {
+			dryRun = []string{"All"}
+		}
+
+		patchData, err := json.Marshal(map[string]any{"spec": map[string]any{"replicas": replicas}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal scale patch: %w", err)
+		}
+
+		scale := newScale()
+		opts := &client.SubResourcePatchOptions{
+			PatchOptions:    client.PatchOptions{DryRun: dryRun},
+			SubResourceBody: scale,
+		}
+		patch := client.RawPatch(types.MergePatchType, patchData)
+		if err := r.runtimeClient.SubResource(scaleSubresource).Patch(ctx, parent, patch, opts); err != nil {
+			logger.Error(err, "Failed to patch scale")
+			return nil, err
+		}
+
+		if !dryRunBool {
+			r.recordChange(ctx, changefeed.OperationUpdate, gvk, scale)
+		}
+
+		return scale.Object, nil
+	}
+}
+
+func newScale() *unstructured.Unstructured {
+	scale := &unstructured.Unstructured{}
+	scale.SetGroupVersionKind(scaleGVK)
+	return scale
+}
@@ -0,0 +1,116 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// scaleCall records a request to the scale subresource.
+type scaleCall struct {
+	subResource string
+	parent      client.Object
+	patch       string
+	dryRun      []string
+}
+
+// scaleClient returns a client serving the scale subresource with replicas,
+// as the fake client does not serve it for unstructured objects.
+func scaleClient(t *testing.T, replicas int64, calls *[]scaleCall) client.WithWatch {
+	scale := func(body client.Object, replicas int64) {
+		u := body.(*unstructured.Unstructured)
+		require.NoError(t, unstructured.SetNestedField(u.Object, replicas, "spec", "replicas"))
+		require.NoError(t, unstructured.SetNestedField(u.Object, "app=web", "status", "selector"))
+	}
+
+	return fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		SubResourceGet: func(_ context.Context, _ client.Client, subResource string, obj, body client.Object, _ ...client.SubResourceGetOption) error {
+			*calls = append(*calls, scaleCall{subResource: subResource, parent: obj})
+			scale(body, replicas)
+			return nil
+		},
+		SubResourcePatch: func(_ context.Context, _ client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			patchOpts := &client.SubResourcePatchOptions{}
+			patchOpts.ApplyOptions(opts)
+			data, err := patch.Data(obj)
+			require.NoError(t, err)
+			*calls = append(*calls, scaleCall{subResource: subResource, parent: obj, patch: string(data), dryRun: patchOpts.DryRun})
+			scale(patchOpts.SubResourceBody, 5)
+			return nil
+		},
+	}).Build()
+}
+
+func TestGetScale(t *testing.T) {
+	var calls []scaleCall
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	resolve := New(scaleClient(t, 3, &calls)).GetScale(gvk)
+
+	source := map[string]any{"metadata": map[string]any{"name": "web", "namespace": "default"}}
+	result, err := resolve(graphql.ResolveParams{Context: t.Context(), Source: source})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(3), result.(map[string]any)["spec"].(map[string]any)["replicas"])
+	require.Len(t, calls, 1)
+	assert.Equal(t, "scale", calls[0].subResource)
+	assert.Equal(t, gvk, calls[0].parent.GetObjectKind().GroupVersionKind())
+	assert.Equal(t, client.ObjectKey{Namespace: "default", Name: "web"}, client.ObjectKeyFromObject(calls[0].parent))
+}
+
+func TestScaleItem(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+	tests := []struct {
+		name       string
+		args       map[string]any
+		wantDryRun []string
+		wantErr    string
+	}{
+		{
+			name: "replicas are patched through the scale subresource",
+			args: map[string]any{NameArg: "web", NamespaceArg: "default", ReplicasArg: 5},
+		},
+		{
+			name:       "dry run",
+			args:       map[string]any{NameArg: "web", NamespaceArg: "default", ReplicasArg: 5, DryRunArg: true},
+			wantDryRun: []string{"All"},
+		},
+		{
+			name:    "negative replicas",
+			args:    map[string]any{NameArg: "web", NamespaceArg: "default", ReplicasArg: -1},
+			wantErr: "replicas must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []scaleCall
+			resolve := New(scaleClient(t, 3, &calls)).ScaleItem(gvk, v1.NamespaceScoped)
+
+			result, err := resolve(graphql.ResolveParams{Context: t.Context(), Args: tt.args})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Empty(t, calls)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, int64(5), result.(map[string]any)["spec"].(map[string]any)["replicas"])
+			require.Len(t, calls, 1)
+			assert.Equal(t, "scale", calls[0].subResource)
+			assert.Equal(t, client.ObjectKey{Namespace: "default", Name: "web"}, client.ObjectKeyFromObject(calls[0].parent))
+			assert.JSONEq(t, `{"spec":{"replicas":5}}`, calls[0].patch)
+			assert.Equal(t, tt.wantDryRun, calls[0].dryRun)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package resolver
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	SearchTermArg = "term"
+	CategoriesArg = "categories"
+	NamespacesArg = "namespaces"
+
+	// DefaultSearchLimit is the default number of objects a search returns.
+	DefaultSearchLimit = 50
+	// searchConcurrency is the number of kinds a search lists at once.
+	searchConcurrency = 8
+)
+
+// SearchArgs returns arguments for the search query
+func SearchArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		SearchTermArg: &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "Text contained in the name or a label value of the objects, case-insensitive, or a label selector such as app=web",
+		},
+		CategoriesArg: &graphql.ArgumentConfig{
+			Type:         graphql.NewList(graphql.NewNonNull(graphql.String)),
+			DefaultValue: []any{"all"},
+			Description:  "The categories whose kinds are searched, as listed by typeByCategory",
+		},
+		NamespacesArg: &graphql.ArgumentConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "The namespaces to search; all namespaces and cluster-scoped kinds if omitted",
+		},
+		LimitArg: &graphql.ArgumentConfig{
+			Type:         graphql.Int,
+			DefaultValue: DefaultSearchLimit,
+			Description:  "Maximum number of objects to return",
+		},
+	}
+}
+
+// Search returns a resolver finding objects of the kinds of categories by
+// name or labels. It lists the metadata of each kind, several kinds at once,
+// and skips kinds the user may not list. The objects are returned with only
+// apiVersion, kind and metadata, ordered by group, kind, namespace and name.
+func (r *Service) Search(categories map[string][]TypeByCategory, kinds map[schema.GroupKind]ServedKind) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, "Search")
+		defer span.End()
+
+		logger := log.FromContext(p.Context).WithValues("operation", "search")
+
+		term, err := GetArg[string](p.Args, SearchTermArg, true)
+		if err != nil {
+			return nil, err
+		}
+		term = strings.TrimSpace(term)
+		if term == "" {
+			return nil, fmt.Errorf("%s must not be empty", SearchTermArg)
+		}
+		categoryNames, err := GetStringListArg(p.Args, CategoriesArg)
+		if err != nil {
+			return nil, err
+		}
+		if len(categoryNames) == 0 {
+			categoryNames = []string{"all"}
+		}
+		namespaces, err := GetStringListArg(p.Args, NamespacesArg)
+		if err != nil {
+			return nil, err
+		}
+		limit, err := GetArg[int](p.Args, LimitArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if limit <= 0 {
+			limit = DefaultSearchLimit
+		}
+
+		var opts []client.ListOption
+		match := nameOrLabelsContain(strings.ToLower(term))
+		if strings.Contains(term, "=") || strings.HasPrefix(term, "!") {
+			selector, err := labels.Parse(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid label selector %q: %w", term, err)
+			}
+			opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+			match = func(metav1.ObjectMeta) bool { return true }
+		}
+
+		searched := searchedKinds(categories, categoryNames, kinds)
+		span.SetAttributes(attribute.Int("kinds", len(searched)))
+
+		matches := make([][]metav1.PartialObjectMetadata, len(searched))
+		group, groupCtx := errgroup.WithContext(ctx)
+		group.SetLimit(searchConcurrency)
+		for i, gvk := range searched {
+			scopes := [][]client.ListOption{opts}
+			if len(namespaces) > 0 {
+				if !isResourceNamespaceScoped(kinds[gvk.GroupKind()].Scope) {
+					continue
+				}
+				scopes = scopes[:0]
+				for _, namespace := range namespaces {
+					scopes = append(scopes, append(slices.Clone(opts), client.InNamespace(namespace)))
+				}
+			}
+
+			group.Go(func() error {
+				for _, scopeOpts := range scopes {
+					err := r.listMetadata(groupCtx, gvk, scopeOpts, func(list *metav1.PartialObjectMetadataList) bool {
+						for _, item := range list.Items {
+							if match(item.ObjectMeta) {
+								matches[i] = append(matches[i], item)
+							}
+						}
+						return len(matches[i]) < limit
+					})
+					if err != nil {
+						if isSkippedSearchError(err) {
+							logger.V(4).Info("Skipping kind in search", "kind", gvk.Kind, "error", err.Error())
+							return nil
+						}
+						return err
+					}
+				}
+				return nil
+			})
+		}
+		if err := group.Wait(); err != nil {
+			logger.Error(err, "Failed to search objects")
+			return nil, err
+		}
+
+		results := []map[string]any{}
+		for i, items := range matches {
+			slices.SortFunc(items, func(a, b metav1.PartialObjectMetadata) int {
+				return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+			})
+			apiVersion, kind := searched[i].ToAPIVersionAndKind()
+			for _, item := range items {
+				if len(results) == limit {
+					return results, nil
+				}
+				metadata, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&item.ObjectMeta)
+				if err != nil {
+					return nil, err
+				}
+				results = append(results, map[string]any{
+					"apiVersion": apiVersion,
+					"kind":       kind,
+					"metadata":   metadata,
+				})
+			}
+		}
+		return results, nil
+	}
+}
+
+// searchedKinds returns the kinds of the named categories the schema serves,
+// at their served version, ordered by group and kind.
+func searchedKinds(categories map[string][]TypeByCategory, names []string, kinds map[schema.GroupKind]ServedKind) []schema.GroupVersionKind {
+	var searched []schema.GroupVersionKind
+	for _, name := range names {
+		for _, t := range categories[name] {
+			gk := schema.GroupKind{Group: t.Group, Kind: t.Kind}
+			served, ok := kinds[gk]
+			if !ok {
+				continue
+			}
+			gvk := gk.WithVersion(served.Version)
+			if !slices.Contains(searched, gvk) {
+				searched = append(searched, gvk)
+			}
+		}
+	}
+	slices.SortFunc(searched, func(a, b schema.GroupVersionKind) int {
+		return cmp.Or(cmp.Compare(a.Group, b.Group), cmp.Compare(a.Kind, b.Kind))
+	})
+	return searched
+}
+
+// nameOrLabelsContain matches objects whose name or a label value contains
+// term, which must be lower case.
+func nameOrLabelsContain(term string) func(metav1.ObjectMeta) bool {
+	return func(meta metav1.ObjectMeta) bool {
+		if strings.Contains(strings.ToLower(meta.Name), term) {
+			return true
+		}
+		for _, value := range meta.Labels {
+			if strings.Contains(strings.ToLower(value), term) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// isSkippedSearchError reports whether a search skips a kind whose list
+// failed with err, as the user may not list it or it is no longer served.
+func isSkippedSearchError(err error) bool {
+	return apierrors.IsForbidden(err) || apierrors.IsNotFound(err) || apierrors.IsMethodNotSupported(err)
+}
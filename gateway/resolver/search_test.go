@@ -0,0 +1,140 @@
+package resolver
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSearch(t *testing.T) {
+	object := func(namespace, name string, labels map[string]string) metav1.PartialObjectMetadata {
+		return metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+	}
+	objects := map[string][]metav1.PartialObjectMetadata{
+		"ConfigMap": {
+			object("team-b", "shop-settings", nil),
+			object("team-a", "shop-settings", nil),
+			object("team-a", "billing", map[string]string{"app": "Shop"}),
+			object("team-a", "other", nil),
+		},
+		"Namespace": {object("", "shop", nil), object("", "team-a", nil)},
+	}
+
+	var mu sync.Mutex
+	var listed []string
+	fc := &fakeClient{
+		listFn: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+			listOpts := &client.ListOptions{}
+			listOpts.ApplyOptions(opts)
+			metadataList := list.(*metav1.PartialObjectMetadataList)
+			kind := metadataList.GroupVersionKind().Kind
+
+			mu.Lock()
+			listed = append(listed, kind+"/"+listOpts.Namespace)
+			mu.Unlock()
+
+			if kind == "Secret" {
+				return apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "", nil)
+			}
+			for _, item := range objects[kind] {
+				if listOpts.Namespace != "" && item.Namespace != listOpts.Namespace {
+					continue
+				}
+				if listOpts.LabelSelector != nil && !listOpts.LabelSelector.Matches(labels.Set(item.Labels)) {
+					continue
+				}
+				metadataList.Items = append(metadataList.Items, item)
+			}
+			return nil
+		},
+	}
+
+	categories := map[string][]TypeByCategory{
+		"all": {
+			{Version: "v1", Kind: "ConfigMap"},
+			{Version: "v1", Kind: "Secret"},
+			{Version: "v1", Kind: "Namespace"},
+			{Group: "example.com", Version: "v1", Kind: "Unserved"},
+		},
+	}
+	kinds := map[schema.GroupKind]ServedKind{
+		{Kind: "ConfigMap"}: {Version: "v1", Scope: v1.NamespaceScoped},
+		{Kind: "Secret"}:    {Version: "v1", Scope: v1.NamespaceScoped},
+		{Kind: "Namespace"}: {Version: "v1", Scope: v1.ClusterScoped},
+	}
+	search := (&Service{runtimeClient: fc}).Search(categories, kinds)
+
+	tests := []struct {
+		name       string
+		args       map[string]any
+		want       []string
+		wantListed []string
+		wantErr    string
+	}{
+		{
+			name:       "name and label values",
+			args:       map[string]any{SearchTermArg: "SHOP"},
+			want:       []string{"ConfigMap team-a/billing", "ConfigMap team-a/shop-settings", "ConfigMap team-b/shop-settings", "Namespace shop"},
+			wantListed: []string{"ConfigMap/", "Namespace/", "Secret/"},
+		},
+		{
+			name:       "namespaces skip cluster-scoped kinds",
+			args:       map[string]any{SearchTermArg: "shop", NamespacesArg: []any{"team-b"}},
+			want:       []string{"ConfigMap team-b/shop-settings"},
+			wantListed: []string{"ConfigMap/team-b", "Secret/team-b"},
+		},
+		{
+			name: "label selector",
+			args: map[string]any{SearchTermArg: "app=Shop"},
+			want: []string{"ConfigMap team-a/billing"},
+		},
+		{
+			name: "limit",
+			args: map[string]any{SearchTermArg: "shop", LimitArg: 2},
+			want: []string{"ConfigMap team-a/billing", "ConfigMap team-a/shop-settings"},
+		},
+		{
+			name:    "empty term",
+			args:    map[string]any{SearchTermArg: " "},
+			wantErr: "term must not be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listed = nil
+			result, err := search(graphql.ResolveParams{Context: t.Context(), Args: tt.args})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []string
+			for _, obj := range result.([]map[string]any) {
+				metadata := obj["metadata"].(map[string]any)
+				name := metadata["name"].(string)
+				if namespace, ok := metadata["namespace"].(string); ok {
+					name = namespace + "/" + name
+				}
+				assert.Equal(t, "v1", obj["apiVersion"])
+				got = append(got, obj["kind"].(string)+" "+name)
+			}
+			assert.Equal(t, tt.want, got)
+			if tt.wantListed != nil {
+				assert.ElementsMatch(t, tt.wantListed, listed)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	schematypes "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// LabelSelectorOperatorEnum mirrors metav1.LabelSelectorOperator.
+var LabelSelectorOperatorEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "LabelSelectorOperator",
+	Description: "A label selector operator",
+	Values: graphql.EnumValueConfigMap{
+		string(metav1.LabelSelectorOpIn):           &graphql.EnumValueConfig{Value: string(metav1.LabelSelectorOpIn)},
+		string(metav1.LabelSelectorOpNotIn):        &graphql.EnumValueConfig{Value: string(metav1.LabelSelectorOpNotIn)},
+		string(metav1.LabelSelectorOpExists):       &graphql.EnumValueConfig{Value: string(metav1.LabelSelectorOpExists)},
+		string(metav1.LabelSelectorOpDoesNotExist): &graphql.EnumValueConfig{Value: string(metav1.LabelSelectorOpDoesNotExist)},
+	},
+})
+
+// LabelSelectorRequirementInput mirrors metav1.LabelSelectorRequirement.
+var LabelSelectorRequirementInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "LabelSelectorRequirementInput",
+	Description: "A label selector requirement: a key, an operator and, for In and NotIn, a set of values",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"key": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The label key that the selector applies to",
+		},
+		"operator": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(LabelSelectorOperatorEnum),
+			Description: "The relationship of the key to the values",
+		},
+		"values": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "Values for In and NotIn; must be empty for Exists and DoesNotExist",
+		},
+	},
+})
+
+// LabelSelectorInput mirrors metav1.LabelSelector.
+var LabelSelectorInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "LabelSelectorInput",
+	Description: "A structured label selector. All requirements are ANDed.",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"matchLabels": &graphql.InputObjectFieldConfig{
+			Type:        schematypes.StringMapScalar,
+			Description: "Labels that must match exactly",
+		},
+		"matchExpressions": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(LabelSelectorRequirementInput)),
+			Description: "Set-based label requirements",
+		},
+	},
+})
+
+// labelSelectorFromArgs builds a selector from the string labelselector
+// argument and the structured labelSelectorInput argument. Requirements of
+// both are combined. Returns nil if neither is set.
+func labelSelectorFromArgs(args map[string]any) (labels.Selector, error) {
+	labelSelector, err := GetArg[string](args, LabelSelectorArg, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var selector labels.Selector
+	if labelSelector != "" {
+		selector, err = labels.Parse(labelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector: %w", err)
+		}
+	}
+
+	raw, ok := args[LabelSelectorInputArg]
+	if !ok || raw == nil {
+		return selector, nil
+	}
+
+	input, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for argument: %s", LabelSelectorInputArg)
+	}
+
+	ls, err := toLabelSelector(input)
+	if err != nil {
+		return nil, err
+	}
+
+	structured, err := metav1.LabelSelectorAsSelector(ls)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", LabelSelectorInputArg, err)
+	}
+
+	if selector == nil {
+		return structured, nil
+	}
+
+	requirements, _ := structured.Requirements()
+	return selector.Add(requirements...), nil
+}
+
+// toLabelSelector converts a LabelSelectorInput argument value to metav1.LabelSelector.
+func toLabelSelector(input map[string]any) (*metav1.LabelSelector, error) {
+	ls := &metav1.LabelSelector{}
+
+	switch matchLabels := input["matchLabels"].(type) {
+	case nil:
+	case map[string]string:
+		ls.MatchLabels = matchLabels
+	case map[string]any:
+		ls.MatchLabels = make(map[string]string, len(matchLabels))
+		for k, v := range matchLabels {
+			str, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("matchLabels value for %q must be a string", k)
+			}
+			ls.MatchLabels[k] = str
+		}
+	default:
+		return nil, errors.New("matchLabels must be a map of strings")
+	}
+
+	expressions, _ := input["matchExpressions"].([]any)
+	for _, e := range expressions {
+		expr, ok := e.(map[string]any)
+		if !ok {
+			return nil, errors.New("invalid matchExpressions entry")
+		}
+
+		key, _ := expr["key"].(string)
+		operator, _ := expr["operator"].(string)
+
+		req := metav1.LabelSelectorRequirement{
+			Key:      key,
+			Operator: metav1.LabelSelectorOperator(operator),
+		}
+
+		values, _ := expr["values"].([]any)
+		for _, v := range values {
+			if str, ok := v.(string); ok {
+				req.Values = append(req.Values, str)
+			}
+		}
+
+		ls.MatchExpressions = append(ls.MatchExpressions, req)
+	}
+
+	return ls, nil
+}
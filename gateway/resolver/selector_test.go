@@ -0,0 +1,108 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelSelectorFromArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    string
+		wantNil bool
+		wantErr string
+	}{
+		{
+			name:    "no selector",
+			args:    map[string]any{},
+			wantNil: true,
+		},
+		{
+			name: "string selector only",
+			args: map[string]any{LabelSelectorArg: "app=nginx"},
+			want: "app=nginx",
+		},
+		{
+			name: "matchLabels from object literal",
+			args: map[string]any{LabelSelectorInputArg: map[string]any{
+				"matchLabels": map[string]any{"app": "nginx"},
+			}},
+			want: "app=nginx",
+		},
+		{
+			name: "matchLabels from variables",
+			args: map[string]any{LabelSelectorInputArg: map[string]any{
+				"matchLabels": map[string]string{"app": "nginx"},
+			}},
+			want: "app=nginx",
+		},
+		{
+			name: "matchExpressions with all operators",
+			args: map[string]any{LabelSelectorInputArg: map[string]any{
+				"matchExpressions": []any{
+					map[string]any{"key": "tier", "operator": "In", "values": []any{"frontend", "backend"}},
+					map[string]any{"key": "env", "operator": "NotIn", "values": []any{"dev"}},
+					map[string]any{"key": "team", "operator": "Exists"},
+					map[string]any{"key": "legacy", "operator": "DoesNotExist"},
+				},
+			}},
+			want: "env notin (dev),!legacy,team,tier in (backend,frontend)",
+		},
+		{
+			name: "string and structured selectors are combined",
+			args: map[string]any{
+				LabelSelectorArg: "app=nginx",
+				LabelSelectorInputArg: map[string]any{
+					"matchExpressions": []any{
+						map[string]any{"key": "tier", "operator": "In", "values": []any{"frontend"}},
+					},
+				},
+			},
+			want: "app=nginx,tier in (frontend)",
+		},
+		{
+			name:    "invalid string selector",
+			args:    map[string]any{LabelSelectorArg: "app in"},
+			wantErr: "invalid label selector",
+		},
+		{
+			name: "In without values",
+			args: map[string]any{LabelSelectorInputArg: map[string]any{
+				"matchExpressions": []any{
+					map[string]any{"key": "tier", "operator": "In"},
+				},
+			}},
+			wantErr: "invalid labelSelectorInput",
+		},
+		{
+			name: "Exists with values",
+			args: map[string]any{LabelSelectorInputArg: map[string]any{
+				"matchExpressions": []any{
+					map[string]any{"key": "tier", "operator": "Exists", "values": []any{"x"}},
+				},
+			}},
+			wantErr: "invalid labelSelectorInput",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := labelSelectorFromArgs(tt.args)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantNil {
+				assert.Nil(t, selector)
+				return
+			}
+			require.NotNil(t, selector)
+			assert.Equal(t, tt.want, selector.String())
+		})
+	}
+}
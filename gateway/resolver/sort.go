@@ -0,0 +1,154 @@
+package resolver
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	OrderByArg = "orderBy"
+
+	SortAscending  = "ASC"
+	SortDescending = "DESC"
+)
+
+// SortDirectionEnum is the direction of a sort key.
+var SortDirectionEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "SortDirection",
+	Values: graphql.EnumValueConfigMap{
+		SortAscending:  &graphql.EnumValueConfig{Value: SortAscending, Description: "Smallest values first"},
+		SortDescending: &graphql.EnumValueConfig{Value: SortDescending, Description: "Largest values first"},
+	},
+})
+
+// SortKeyInput is a key of the orderBy argument.
+var SortKeyInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "SortKeyInput",
+	Description: "A key to sort a list by",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"path": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "A dot-separated field path such as spec.replicas, or the name of a printer column",
+		},
+		"direction": &graphql.InputObjectFieldConfig{
+			Type:         SortDirectionEnum,
+			DefaultValue: SortAscending,
+		},
+	},
+})
+
+// OrderByArgConfig sorts lists by several keys.
+var OrderByArgConfig = &graphql.ArgumentConfig{
+	Type:        graphql.NewList(graphql.NewNonNull(SortKeyInput)),
+	Description: "Keys to sort the results by, each one ordering the items the previous keys consider equal. Replaces sortBy. Items without a value for a key come last",
+}
+
+// sortKey compares objects by a value, which objects may not have.
+type sortKey struct {
+	value      func(obj unstructured.Unstructured) (any, bool)
+	descending bool
+}
+
+// getOrderBy returns the sort keys of the orderBy argument, resolving the
+// names of printer columns.
+func getOrderBy(args map[string]any, items []unstructured.Unstructured, columns []v1.CustomResourceColumnDefinition) ([]sortKey, error) {
+	entries, _ := args[OrderByArg].([]any)
+	keys := make([]sortKey, 0, len(entries))
+	for _, entry := range entries {
+		input, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s entry: %v", OrderByArg, entry)
+		}
+		path, _ := input["path"].(string)
+		if path == "" {
+			return nil, fmt.Errorf("%s path is required", OrderByArg)
+		}
+		direction, _ := input["direction"].(string)
+
+		key, err := newSortKey(items, columns, path, direction == SortDescending)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s path %q: %w", OrderByArg, path, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// newSortKey returns the sort key of path, which is either the name of a
+// printer column or a field path.
+func newSortKey(items []unstructured.Unstructured, columns []v1.CustomResourceColumnDefinition, path string, descending bool) (sortKey, error) {
+	if column, ok := printerColumn(columns, path); ok {
+		value, err := columnValue(column)
+		if err != nil {
+			return sortKey{}, err
+		}
+		return sortKey{value: value, descending: descending}, nil
+	}
+	if err := validateSortBy(items, path); err != nil {
+		return sortKey{}, err
+	}
+	return sortKey{value: fieldValue(path), descending: descending}, nil
+}
+
+// sortItems sorts items stably by keys. Items without a value for a key
+// come last in both directions.
+func sortItems(items []unstructured.Unstructured, keys []sortKey) {
+	slices.SortStableFunc(items, func(a, b unstructured.Unstructured) int {
+		for _, key := range keys {
+			aVal, foundA := key.value(a)
+			bVal, foundB := key.value(b)
+			var c int
+			switch {
+			case !foundA && !foundB:
+				c = 0
+			case !foundA:
+				return 1
+			case !foundB:
+				return -1
+			case key.descending:
+				c = compareValues(bVal, aVal)
+			default:
+				c = compareValues(aVal, bVal)
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
+
+// fieldValue returns the value of the field at a dot-separated path.
+func fieldValue(fieldPath string) func(obj unstructured.Unstructured) (any, bool) {
+	segments := strings.Split(fieldPath, ".")
+	return func(obj unstructured.Unstructured) (any, bool) {
+		value, found, err := unstructured.NestedFieldNoCopy(obj.Object, segments...)
+		return value, found && err == nil
+	}
+}
+
+// compareStrings compares timestamps such as metadata.creationTimestamp by
+// time and quantities such as "500m" or "2Gi" by their value, and other
+// strings lexically.
+func compareStrings(a, b string) int {
+	if aTime, err := time.Parse(time.RFC3339, a); err == nil {
+		if bTime, err := time.Parse(time.RFC3339, b); err == nil {
+			return aTime.Compare(bTime)
+		}
+	}
+	if aQuantity, err := resource.ParseQuantity(a); err == nil {
+		if bQuantity, err := resource.ParseQuantity(b); err == nil {
+			return aQuantity.Cmp(bQuantity)
+		}
+	}
+	return cmp.Compare(a, b)
+}
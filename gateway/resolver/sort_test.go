@@ -0,0 +1,109 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestListItems_OrderBy(t *testing.T) {
+	columns := []v1.CustomResourceColumnDefinition{{Name: "Memory", Type: "string", JSONPath: ".spec.memory"}}
+
+	app := func(name, team string, replicas int64, created, memory string) unstructured.Unstructured {
+		spec := map[string]any{"team": team, "replicas": replicas}
+		if memory != "" {
+			spec["memory"] = memory
+		}
+		return unstructured.Unstructured{Object: map[string]any{
+			"metadata": map[string]any{"name": name, "namespace": "default", "creationTimestamp": created},
+			"spec":     spec,
+		}}
+	}
+
+	fc := &fakeClient{
+		listFn: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+			list.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{
+				app("a", "web", 1, "2024-01-01T12:00:00Z", "1Gi"),
+				app("b", "api", 3, "2024-01-01T13:30:00+02:00", ""),
+				app("c", "web", 3, "2024-01-01T10:00:00Z", "512Mi"),
+				app("d", "api", 2, "2024-01-01T11:00:00Z", "2Gi"),
+			}
+			return nil
+		},
+	}
+	svc := &Service{runtimeClient: fc}
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "App"}
+
+	key := func(path, direction string) any {
+		return map[string]any{"path": path, "direction": direction}
+	}
+
+	tests := []struct {
+		name    string
+		orderBy []any
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "multiple keys",
+			orderBy: []any{key("spec.team", SortAscending), key("spec.replicas", SortDescending)},
+			want:    []string{"b", "d", "c", "a"},
+		},
+		{
+			name:    "timestamps with offsets",
+			orderBy: []any{key("metadata.creationTimestamp", SortAscending)},
+			want:    []string{"c", "d", "b", "a"},
+		},
+		{
+			name:    "quantities in a printer column, missing values last",
+			orderBy: []any{key("memory", SortDescending)},
+			want:    []string{"d", "a", "c", "b"},
+		},
+		{
+			name:    "ties keep the list order",
+			orderBy: []any{key("spec.team", SortDescending)},
+			want:    []string{"a", "c", "b", "d"},
+		},
+		{
+			name:    "unknown field",
+			orderBy: []any{key("spec.missing", SortAscending)},
+			wantErr: `invalid orderBy path "spec.missing"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := svc.ListItems(gvk, v1.NamespaceScoped, columns)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    map[string]any{SortByArg: "metadata.name", OrderByArg: tt.orderBy},
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var names []string
+			for _, item := range out.(*ListResult).Items {
+				names = append(names, item["metadata"].(map[string]any)["name"].(string))
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestCompareStrings(t *testing.T) {
+	assert.Equal(t, -1, compareStrings("2024-01-01T12:00:00+02:00", "2024-01-01T11:00:00Z"))
+	assert.Equal(t, 1, compareStrings("10", "9"))
+	assert.Equal(t, -1, compareStrings("500m", "1"))
+	assert.Equal(t, 0, compareStrings("1Gi", "1024Mi"))
+	assert.Equal(t, -1, compareStrings("apple", "banana"))
+}
@@ -0,0 +1,38 @@
+package resolver
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const statusSubresource = "status"
+
+// getStatusOnly reads an object through its status subresource, for users
+// who may read the status but not the object itself. The subresource returns
+// the whole object, so all fields but the status and metadata are removed.
+// It fails if the kind has no status subresource or the user may not read it
+// either.
+func (r *Service) getStatusOnly(ctx context.Context, gvk schema.GroupVersionKind, key client.ObjectKey) (*unstructured.Unstructured, error) {
+	parent := &unstructured.Unstructured{}
+	parent.SetGroupVersionKind(gvk)
+	parent.SetName(key.Name)
+	parent.SetNamespace(key.Namespace)
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := r.runtimeClient.SubResource(statusSubresource).Get(ctx, parent, obj); err != nil {
+		return nil, err
+	}
+
+	for field := range obj.Object {
+		switch field {
+		case "apiVersion", "kind", "metadata", "status":
+		default:
+			delete(obj.Object, field)
+		}
+	}
+	return obj, nil
+}
@@ -0,0 +1,141 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/warnings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestUpdateItemStatus(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "node-1"},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(pod).
+		WithStatusSubresource(pod).
+		Build()
+
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	resolve := New(cl).UpdateItemStatus(gvk, v1.NamespaceScoped)
+
+	tests := []struct {
+		name      string
+		args      map[string]any
+		wantPhase corev1.PodPhase
+	}{
+		{
+			name: "dry run leaves status unchanged",
+			args: map[string]any{
+				NameArg:      "web",
+				NamespaceArg: "default",
+				StatusArg:    map[string]any{"phase": "Running"},
+				DryRunArg:    true,
+			},
+			wantPhase: corev1.PodPending,
+		},
+		{
+			name: "status is patched",
+			args: map[string]any{
+				NameArg:      "web",
+				NamespaceArg: "default",
+				StatusArg:    map[string]any{"phase": "Running"},
+			},
+			wantPhase: corev1.PodRunning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolve(graphql.ResolveParams{Context: t.Context(), Args: tt.args})
+			require.NoError(t, err)
+
+			var stored corev1.Pod
+			require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(pod), &stored))
+			assert.Equal(t, tt.wantPhase, stored.Status.Phase)
+			assert.Equal(t, "node-1", stored.Spec.NodeName)
+		})
+	}
+}
+
+func TestGetItemStatusOnly(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "web", errors.New("no get"))
+
+	tests := []struct {
+		name        string
+		statusErr   error
+		wantErr     bool
+		wantWarning string
+	}{
+		{
+			name:        "status is returned without the other fields",
+			wantWarning: "only the status of Deployment default/web is readable, its other fields are null",
+		},
+		{
+			name:      "forbidden status fails the read",
+			statusErr: apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments/status"}, "web", errors.New("no get")),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var subResource string
+			cl := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+					return forbidden
+				},
+				SubResourceGet: func(_ context.Context, _ client.Client, sub string, obj, body client.Object, _ ...client.SubResourceGetOption) error {
+					subResource = sub
+					if tt.statusErr != nil {
+						return tt.statusErr
+					}
+					u := body.(*unstructured.Unstructured)
+					u.SetName(obj.GetName())
+					u.SetNamespace(obj.GetNamespace())
+					u.Object["spec"] = map[string]any{"replicas": int64(3)}
+					u.Object["status"] = map[string]any{"readyReplicas": int64(2)}
+					return nil
+				},
+			}).Build()
+
+			ctx := warnings.WithCollector(t.Context())
+			result, err := New(cl).GetItem(gvk, v1.NamespaceScoped)(graphql.ResolveParams{
+				Context: ctx,
+				Args:    map[string]any{NameArg: "web", NamespaceArg: "default"},
+			})
+			assert.Equal(t, statusSubresource, subResource)
+
+			if tt.wantErr {
+				assert.True(t, apierrors.IsForbidden(err))
+				assert.Empty(t, warnings.FromContext(ctx))
+				return
+			}
+			require.NoError(t, err)
+			obj := result.(map[string]any)
+			assert.NotContains(t, obj, "spec")
+			assert.Equal(t, map[string]any{"readyReplicas": int64(2)}, obj["status"])
+			assert.Equal(t, "web", obj["metadata"].(map[string]any)["name"])
+			assert.Equal(t, []string{tt.wantWarning}, warnings.FromContext(ctx))
+		})
+	}
+}
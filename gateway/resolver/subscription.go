@@ -2,32 +2,37 @@ package resolver
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"reflect"
-	"strings"
 	"time"
 
 	"github.com/graphql-go/graphql"
-	"github.com/graphql-go/graphql/language/ast"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/recovery"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
 )
 
 var (
 	ErrFailedToCastEventObjectToUnstructured = fmt.Errorf("failed to cast event object to unstructured")
+
+	errEmitInitialStateWithResourceVersion = errors.New("emitInitialState cannot be combined with resourceVersion, the initial state is only known when the watch starts now")
 )
 
 // Event type constants used in subscription envelopes
@@ -35,6 +40,8 @@ const (
 	EventTypeAdded    = "ADDED"
 	EventTypeModified = "MODIFIED"
 	EventTypeDeleted  = "DELETED"
+	// EventTypeBookmark is only sent to subscriptions requesting bookmarks.
+	EventTypeBookmark = "BOOKMARK"
 )
 
 const (
@@ -50,6 +57,11 @@ const (
 type SubscriptionEnvelope struct {
 	Type   string `json:"type"`
 	Object any    `json:"object"`
+	// Suppressed counts the events of the object merged into this one by coalesceMs.
+	Suppressed int `json:"suppressed"`
+	// ResourceVersion is the resource version the watch can be resumed from
+	// after this event, empty while the initial list is sent.
+	ResourceVersion string `json:"resourceVersion,omitempty"`
 }
 
 // SubscriptionObject represents an object with only minimal metadata
@@ -64,19 +76,31 @@ type SubscriptionMetadata struct {
 	ResourceVersion string `json:"resourceVersion,omitempty"`
 }
 
+// SubscribeItem watches a single object, emitting an envelope per change.
 func (r *Service) SubscribeItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
+		window, dropped, err := r.eventWindow(p.Args, gvk)
+		if err != nil {
+			return nil, err
+		}
 		resultChannel := make(chan any)
 		go r.runWatch(p, gvk, resultChannel, true, scope)
-		return resultChannel, nil
+		return coalesceEvents(p.Context, resultChannel, window, dropped), nil
 	}
 }
 
+// SubscribeItems watches the objects of a kind. Like SubscribeItem, it emits
+// an envelope per changed object after the initial list, never the whole list,
+// so an event costs the same however many objects are watched.
 func (r *Service) SubscribeItems(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
+		window, dropped, err := r.eventWindow(p.Args, gvk)
+		if err != nil {
+			return nil, err
+		}
 		resultChannel := make(chan any)
 		go r.runWatch(p, gvk, resultChannel, false, scope)
-		return resultChannel, nil
+		return coalesceEvents(p.Context, resultChannel, window, dropped), nil
 	}
 }
 
@@ -88,6 +112,14 @@ func (r *Service) runWatch(
 	scope v1.ResourceScope,
 ) {
 	defer close(resultChannel)
+	defer func() {
+		if err := recovery.Recovered(p.Context, "watch "+gvk.Kind, recover()); err != nil {
+			select {
+			case <-p.Context.Done():
+			case resultChannel <- err:
+			}
+		}
+	}()
 
 	ctx, span := otel.Tracer("").Start(p.Context, "runWatch", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
 	defer span.End()
@@ -106,10 +138,17 @@ func (r *Service) runWatch(
 		}
 	}
 
-	labelSelector, err := GetArg[string](p.Args, LabelSelectorArg, false)
+	labelSelector, err := labelSelectorFromArgs(p.Args)
 	if err != nil {
-		logger.Error(err, "Failed to get label selector argument")
-		sendErr(fmt.Errorf("failed to get label selector argument: %w", err))
+		logger.Error(err, "Invalid label selector")
+		sendErr(err)
+		return
+	}
+
+	fieldSelector, err := GetArg[string](p.Args, FieldSelectorArg, false)
+	if err != nil {
+		logger.Error(err, "Failed to get field selector argument")
+		sendErr(fmt.Errorf("failed to get field selector argument: %w", err))
 		return
 	}
 
@@ -120,6 +159,13 @@ func (r *Service) runWatch(
 		return
 	}
 
+	bookmarks, err := GetArg[bool](p.Args, BookmarksArg, false)
+	if err != nil {
+		logger.Error(err, "Failed to get bookmarks argument")
+		sendErr(fmt.Errorf("failed to get bookmarks argument: %w", err))
+		return
+	}
+
 	// optional resourceVersion to continue subscription from
 	resourceVersion, err := GetArg[string](p.Args, ResourceVersionArg, false)
 	if err != nil {
@@ -127,8 +173,30 @@ func (r *Service) runWatch(
 		sendErr(fmt.Errorf("failed to get resourceVersion argument: %w", err))
 		return
 	}
+	emitInitialState, err := GetArg[bool](p.Args, EmitInitialStateArg, false)
+	if err != nil {
+		logger.Error(err, "Failed to get emitInitialState argument")
+		sendErr(fmt.Errorf("failed to get emitInitialState argument: %w", err))
+		return
+	}
+	if emitInitialState && resourceVersion != "" {
+		sendErr(errEmitInitialStateWithResourceVersion)
+		return
+	}
+	// Unless disabled, the objects listed first are emitted as ADDED. Lists
+	// after the watch expired are always emitted, as changes were missed.
+	_, initialStateSet := p.Args[EmitInitialStateArg]
+	emitList := !initialStateSet || emitInitialState
+
+	// A client reconnecting with Last-Event-ID resumes from its last event.
+	if resumeFrom, ok := utilscontext.GetResumeFromCtx(ctx); ok {
+		resourceVersion = resumeFrom
+	}
 
-	fieldsToWatch := extractRequestedFields(p.Info)
+	var requested *fieldpath.Set
+	if !subscribeToAll {
+		requested = r.requestedFields(p.Info)
+	}
 
 	list := &unstructured.UnstructuredList{}
 	list.SetGroupVersionKind(schema.GroupVersionKind{
@@ -151,14 +219,18 @@ func (r *Service) runWatch(
 		}
 	}
 
-	if labelSelector != "" {
-		selector, err := labels.Parse(labelSelector)
+	if labelSelector != nil {
+		opts = append(opts, client.MatchingLabelsSelector{Selector: labelSelector})
+	}
+
+	if fieldSelector != "" {
+		selector, err := fields.ParseSelector(fieldSelector)
 		if err != nil {
-			logger.WithValues(LabelSelectorArg, labelSelector).Error(err, "Invalid label selector")
-			sendErr(fmt.Errorf("invalid label selector: %w", err))
+			logger.WithValues(FieldSelectorArg, fieldSelector).Error(err, "Invalid field selector")
+			sendErr(fmt.Errorf("invalid field selector: %w", err))
 			return
 		}
-		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+		opts = append(opts, client.MatchingFieldsSelector{Selector: selector})
 	}
 
 	var name string
@@ -175,8 +247,14 @@ func (r *Service) runWatch(
 	// If no resourceVersion provided, perform an initial LIST to obtain current items and resourceVersion,
 	// If a resourceVersion is provided, start WATCH from that resourceVersion without initial listing.
 
-	// Track last-seen objects for change detection on MODIFIED
-	previousObjects := make(map[string]*unstructured.Unstructured)
+	// Track the last-seen requested fields of the objects for change
+	// detection on MODIFIED
+	previousFields := make(map[string]any)
+	track := func(obj *unstructured.Unstructured) {
+		if requested != nil {
+			previousFields[obj.GetNamespace()+"/"+obj.GetName()] = runtime.DeepCopyJSONValue(fieldValues(obj.Object, requested))
+		}
+	}
 
 	lastRV := resourceVersion
 
@@ -205,16 +283,23 @@ func (r *Service) runWatch(
 				return false, nil
 			}
 
-			previousObjects = make(map[string]*unstructured.Unstructured)
+			previousFields = make(map[string]any)
 			for i := range list.Items {
 				item := list.Items[i]
-				key := item.GetNamespace() + "/" + item.GetName()
-				previousObjects[key] = item.DeepCopy()
+				track(&item)
+				if !emitList {
+					continue
+				}
 
 				envelope := SubscriptionEnvelope{
 					Type:   EventTypeAdded,
 					Object: item.Object,
 				}
+				// Items are not ordered by resource version, so the watch
+				// can only be resumed once the whole list was sent.
+				if i == len(list.Items)-1 {
+					envelope.ResourceVersion = list.GetResourceVersion()
+				}
 				select {
 				case <-ctx.Done():
 					return true, nil
@@ -223,13 +308,14 @@ func (r *Service) runWatch(
 			}
 
 			lastRV = list.GetResourceVersion()
+			emitList = true
 		}
 
 		// --- WATCH phase ---
 		watchOpts := append([]client.ListOption{}, opts...)
-		if lastRV != "" {
+		if lastRV != "" || bookmarks {
 			watchOpts = append(watchOpts, &client.ListOptions{
-				Raw: &metav1.ListOptions{ResourceVersion: lastRV},
+				Raw: &metav1.ListOptions{ResourceVersion: lastRV, AllowWatchBookmarks: bookmarks},
 			})
 		}
 
@@ -295,31 +381,27 @@ func (r *Service) runWatch(
 				var eventType string
 				switch event.Type {
 				case watch.Added:
-					previousObjects[key] = obj.DeepCopy()
+					track(obj)
 					sendUpdate = true
 					eventType = EventTypeAdded
 				case watch.Modified:
-					oldObj := previousObjects[key]
-					if subscribeToAll {
-						sendUpdate = true
-					} else {
-						var changed bool
-						changed, err = determineFieldChanged(oldObj, obj, fieldsToWatch)
-						if err != nil {
-							logger.Error(err, "Failed to determine field changes")
-							sendErr(fmt.Errorf("failed to determine field changed: %w", err))
-							return true, nil
-						}
-						sendUpdate = changed
+					sendUpdate = true
+					if requested != nil {
+						previous, seen := previousFields[key]
+						track(obj)
+						sendUpdate = !seen || !equality.Semantic.DeepEqual(previous, previousFields[key])
 					}
-					previousObjects[key] = obj.DeepCopy()
 					if sendUpdate {
 						eventType = EventTypeModified
 					}
 				case watch.Deleted:
-					delete(previousObjects, key)
+					delete(previousFields, key)
 					sendUpdate = true
 					eventType = EventTypeDeleted
+				case watch.Bookmark:
+					// Bookmarks only advance the resource version.
+					sendUpdate = bookmarks
+					eventType = EventTypeBookmark
 				}
 
 				if sendUpdate {
@@ -336,8 +418,9 @@ func (r *Service) runWatch(
 					}
 
 					envelope := SubscriptionEnvelope{
-						Type:   eventType,
-						Object: payload,
+						Type:            eventType,
+						Object:          payload,
+						ResourceVersion: lastRV,
 					}
 
 					select {
@@ -353,118 +436,15 @@ func (r *Service) runWatch(
 	})
 }
 
-// extractRequestedFields uses p.Info to determine the fields requested by the client.
-// It returns a slice of strings representing the "paths" of requested fields.
-func extractRequestedFields(info graphql.ResolveInfo) []string {
-	var fields []string
-	for _, fieldAST := range info.FieldASTs {
-		if fieldAST.SelectionSet == nil {
-			continue
-		}
-		for _, sel := range fieldAST.SelectionSet.Selections {
-			if f, ok := sel.(*ast.Field); ok {
-				if f.Name.Value == "object" {
-					fields = append(fields, parseSelectionSet(f.SelectionSet, "")...)
-				}
-			}
-		}
-	}
-	return fields
-}
-
-// parseSelectionSet recursively extracts field paths from a selection set.
-// If `prefix` is non-empty, it prefixes subfields with `prefix + "."`.
-func parseSelectionSet(selectionSet *ast.SelectionSet, prefix string) []string {
-	var result []string
-	if selectionSet == nil {
-		return result
-	}
-
-	for _, selection := range selectionSet.Selections {
-		switch sel := selection.(type) {
-		case *ast.Field:
-			fieldName := sel.Name.Value
-			fullPath := fieldName
-			if prefix != "" {
-				fullPath = prefix + "." + fieldName
-			}
-
-			// If this field has a sub-selection set, recurse
-			if sel.SelectionSet != nil && len(sel.SelectionSet.Selections) > 0 {
-				subFields := parseSelectionSet(sel.SelectionSet, fullPath)
-				result = append(result, subFields...)
-			} else {
-				// Leaf field
-				result = append(result, fullPath)
-			}
-		}
-	}
-	return result
-}
-
-func determineFieldChanged(oldObj, newObj *unstructured.Unstructured, fields []string) (bool, error) {
-	if oldObj == nil {
-		// No previous object, so treat as changed
-		return true, nil
-	}
-
-	for _, fieldPath := range fields {
-		oldValue, foundOld, err := getFieldValue(oldObj, fieldPath)
-		if err != nil {
-			return false, err
-		}
-		newValue, foundNew, err := getFieldValue(newObj, fieldPath)
-		if err != nil {
-			return false, err
-		}
-		if !foundOld && !foundNew {
-			// Field not present in both, consider no change
-			continue
-		}
-		if !foundOld || !foundNew {
-			// Field present in one but not the other, so changed
-			return true, nil
-		}
-		if !reflect.DeepEqual(oldValue, newValue) {
-			// Field value has changed
-			return true, nil
-		}
-	}
-
-	return false, nil
-}
-
-// Helper function to get the value of a field from an unstructured object
-func getFieldValue(obj *unstructured.Unstructured, fieldPath string) (any, bool, error) {
-	fields := strings.Split(fieldPath, ".")
-	var current any = obj.Object
-
-	for i, field := range fields {
-		switch v := current.(type) {
-		case map[string]any:
-			value, found, err := unstructured.NestedFieldNoCopy(v, field)
-			if err != nil {
-				return nil, false, fmt.Errorf("error accessing field %s: %v", strings.Join(fields[:i+1], "."), err)
-			}
-			if !found {
-				return nil, false, nil
-			}
-			current = value
-		case []any:
-			// in case of slice, we return it, and that slice will be compared later using deep equal
-			return current, true, nil
-		default:
-			return nil, false, fmt.Errorf("unexpected type at field %s, expected map[string]interface{} or []interface{}, got %T", strings.Join(fields[:i+1], "."), current)
-		}
-	}
-
-	return current, true, nil
-}
-
 func CreateSubscriptionResolver() graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
 		source := p.Source
 
+		if points, ok := utilscontext.GetResumePointsFromCtx(p.Context); ok {
+			envelope, _ := source.(SubscriptionEnvelope)
+			points.Add(envelope.ResourceVersion)
+		}
+
 		if err, ok := source.(error); ok {
 			return nil, err
 		}
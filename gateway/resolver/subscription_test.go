@@ -3,137 +3,150 @@ package resolver
 import (
 	"testing"
 
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
 )
 
-func TestDetermineFieldChanged(t *testing.T) {
+func TestRequestedFields(t *testing.T) {
+	port := graphql.NewObject(graphql.ObjectConfig{Name: "Port", Fields: graphql.Fields{
+		"port": &graphql.Field{Type: graphql.Int},
+	}})
+	widget := graphql.NewObject(graphql.ObjectConfig{Name: "Widget", Fields: graphql.Fields{
+		"metadata": &graphql.Field{Type: graphql.NewObject(graphql.ObjectConfig{Name: "Metadata", Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String},
+		}})},
+		"spec": &graphql.Field{Type: graphql.NewObject(graphql.ObjectConfig{Name: "WidgetSpec", Fields: graphql.Fields{
+			"replicas": &graphql.Field{Type: graphql.Int},
+			"ports":    &graphql.Field{Type: graphql.NewList(port)},
+		}})},
+		"status": &graphql.Field{Type: graphql.NewObject(graphql.ObjectConfig{Name: "WidgetStatus", Fields: graphql.Fields{
+			"ready": &graphql.Field{Type: graphql.Boolean},
+		}})},
+	}})
+	envelope := graphql.NewObject(graphql.ObjectConfig{Name: "WidgetEvent", Fields: graphql.Fields{
+		"type":   &graphql.Field{Type: graphql.String},
+		"object": &graphql.Field{Type: widget},
+	}})
+
+	var requested *fieldpath.Set
+	r := New(nil)
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+		"widget": &graphql.Field{Type: envelope, Resolve: func(p graphql.ResolveParams) (any, error) {
+			requested = r.requestedFields(p.Info)
+			return nil, nil
+		}},
+	}})})
+	require.NoError(t, err)
+
+	result := graphql.Do(graphql.Params{Schema: schema, RequestString: `
+		query {
+			widget {
+				type
+				object {
+					__typename
+					...meta
+					spec { ports { port } }
+					... on Widget { status { ready } }
+					wanted: spec { replicas }
+				}
+			}
+		}
+		fragment meta on Widget { metadata { name } }
+	`})
+	require.Empty(t, result.Errors)
+
+	want := fieldpath.NewSet(
+		fieldpath.MakePathOrDie("metadata", "name"),
+		fieldpath.MakePathOrDie("spec", "ports", "port"),
+		fieldpath.MakePathOrDie("spec", "replicas"),
+		fieldpath.MakePathOrDie("status", "ready"),
+	)
+	assert.True(t, want.Equals(requested), "requested fields = %s", requested)
+}
+
+func TestFieldValues(t *testing.T) {
+	requested := fieldpath.NewSet(
+		fieldpath.MakePathOrDie("status", "ready"),
+		fieldpath.MakePathOrDie("status", "conditions", "status"),
+		fieldpath.MakePathOrDie("spec"),
+	)
+
 	tests := []struct {
-		name           string
-		oldObj         *unstructured.Unstructured
-		newObj         *unstructured.Unstructured
-		fields         []string
-		isFieldChanged bool
-		expectError    bool
+		name    string
+		old     map[string]any
+		new     map[string]any
+		changed bool
 	}{
 		{
-			name:           "oldObj_is_nil",
-			oldObj:         nil,
-			newObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"ready": true}}},
-			fields:         []string{"status.ready"},
-			isFieldChanged: true,
-			expectError:    false,
-		},
-		{
-			name:           "both_objects_are_empty",
-			oldObj:         &unstructured.Unstructured{Object: map[string]any{}},
-			newObj:         &unstructured.Unstructured{Object: map[string]any{}},
-			fields:         []string{"status.ready"},
-			isFieldChanged: false,
-			expectError:    false,
+			name:    "both_objects_are_empty",
+			old:     map[string]any{},
+			new:     map[string]any{},
+			changed: false,
 		},
 		{
-			name:           "field_missing_in_both",
-			oldObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"ready": true}}},
-			newObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"ready": true}}},
-			fields:         []string{"status.missing"},
-			isFieldChanged: false,
-			expectError:    false,
+			name:    "field_removed",
+			old:     map[string]any{"status": map[string]any{"ready": true}},
+			new:     map[string]any{"status": map[string]any{}},
+			changed: true,
 		},
 		{
-			name:           "field_present_in_oldObj_but_missing_in_newObj",
-			oldObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"ready": true}}},
-			newObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{}}},
-			fields:         []string{"status.ready"},
-			isFieldChanged: true,
-			expectError:    false,
+			name:    "field_added",
+			old:     map[string]any{"status": map[string]any{}},
+			new:     map[string]any{"status": map[string]any{"ready": true}},
+			changed: true,
 		},
 		{
-			name:           "field_present_in_newObj_but_missing_in_oldObj",
-			oldObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{}}},
-			newObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"ready": true}}},
-			fields:         []string{"status.ready"},
-			isFieldChanged: true,
-			expectError:    false,
+			name:    "field_value_changed",
+			old:     map[string]any{"status": map[string]any{"ready": false}},
+			new:     map[string]any{"status": map[string]any{"ready": true}},
+			changed: true,
 		},
 		{
-			name:           "field_value_changed",
-			oldObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"ready": false}}},
-			newObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"ready": true}}},
-			fields:         []string{"status.ready"},
-			isFieldChanged: true,
-			expectError:    false,
+			name:    "other_field_changed",
+			old:     map[string]any{"status": map[string]any{"ready": true, "healthy": true}},
+			new:     map[string]any{"status": map[string]any{"ready": true, "healthy": false}},
+			changed: false,
 		},
 		{
-			name: "field_value_changed",
-			oldObj: &unstructured.Unstructured{Object: map[string]any{
-				"status": map[string]any{"ready": true, "healthy": true},
-			}},
-			newObj: &unstructured.Unstructured{Object: map[string]any{
-				"status": map[string]any{"ready": true, "healthy": false},
-			}},
-			fields:         []string{"status.ready", "status.healthy"},
-			isFieldChanged: true,
-			expectError:    false,
+			name:    "list_item_field_changed",
+			old:     map[string]any{"status": map[string]any{"conditions": []any{map[string]any{"type": "Ready", "status": "True"}}}},
+			new:     map[string]any{"status": map[string]any{"conditions": []any{map[string]any{"type": "Ready", "status": "False"}}}},
+			changed: true,
 		},
 		{
-			name:           "field_value_unchanged",
-			oldObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"ready": true}}},
-			newObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"ready": true}}},
-			fields:         []string{"status.ready"},
-			isFieldChanged: false,
-			expectError:    false,
+			name:    "list_item_other_field_changed",
+			old:     map[string]any{"status": map[string]any{"conditions": []any{map[string]any{"type": "Ready", "status": "True", "reason": "A"}}}},
+			new:     map[string]any{"status": map[string]any{"conditions": []any{map[string]any{"type": "Ready", "status": "True", "reason": "B"}}}},
+			changed: false,
 		},
 		{
-			name: "nested_field_changed",
-			oldObj: &unstructured.Unstructured{
-				Object: map[string]any{
-					"conditions": []any{map[string]any{"type": "Ready", "status": "True"}},
-				},
-			},
-			newObj: &unstructured.Unstructured{
-				Object: map[string]any{
-					"conditions": []any{map[string]any{"type": "Ready", "status": "False"}},
-				},
-			},
-			fields:         []string{"conditions.0.status", "conditions.0.type"},
-			isFieldChanged: true,
-			expectError:    false,
+			name:    "list_item_added",
+			old:     map[string]any{"status": map[string]any{"conditions": []any{}}},
+			new:     map[string]any{"status": map[string]any{"conditions": []any{map[string]any{"type": "Ready"}}}},
+			changed: true,
 		},
 		{
-			name:           "nested_field_unchanged",
-			oldObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"conditions": []any{map[string]any{"type": "Ready", "status": "True"}}}}},
-			newObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"conditions": []any{map[string]any{"type": "Ready", "status": "True"}}}}},
-			fields:         []string{"status.conditions.0.status"},
-			isFieldChanged: false,
-			expectError:    false,
+			name:    "selected_object_changed",
+			old:     map[string]any{"spec": map[string]any{"replicas": int64(1)}},
+			new:     map[string]any{"spec": map[string]any{"replicas": int64(2)}},
+			changed: true,
 		},
 		{
-			name:           "invalid_field_path",
-			oldObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"ready": true}}},
-			newObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"ready": true}}},
-			fields:         []string{"invalid.path"},
-			isFieldChanged: false,
-			expectError:    false,
-		},
-		{
-			name:           "unexpected_type_in_field_path",
-			oldObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"ready": true}}},
-			newObj:         &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"ready": true}}},
-			fields:         []string{"status.ready.invalid"},
-			isFieldChanged: false,
-			expectError:    true,
+			name:    "scalar_where_object_is_selected",
+			old:     map[string]any{"status": "pending"},
+			new:     map[string]any{"status": "done"},
+			changed: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := determineFieldChanged(tt.oldObj, tt.newObj, tt.fields)
-			if tt.expectError {
-				require.NotNil(t, err)
-			}
-			require.Equal(t, tt.isFieldChanged, result)
+			changed := !equality.Semantic.DeepEqual(fieldValues(tt.old, requested), fieldValues(tt.new, requested))
+			assert.Equal(t, tt.changed, changed)
 		})
 	}
 }
@@ -3,6 +3,7 @@ package resolver
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -11,6 +12,8 @@ import (
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/language/ast"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/recovery"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -509,3 +512,273 @@ func TestRunWatch_410OnWatchCreation_ClearsRVAndRelists(t *testing.T) {
 	assert.GreaterOrEqual(t, addedCount, 2, "expected ADDED events from re-list after 410")
 	assert.GreaterOrEqual(t, atomic.LoadInt32(&listCalls), int32(2), "expected re-list after 410")
 }
+
+func TestRunWatch_FieldSelector_AppliedToListAndWatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var listOpts, watchOpts client.ListOptions
+	fc := &fakeClient{
+		listFn: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+			listOpts.ApplyOptions(opts)
+			list.(*unstructured.UnstructuredList).SetResourceVersion("1")
+			return nil
+		},
+		watchFn: func(_ context.Context, _ client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+			watchOpts.ApplyOptions(opts)
+			cancel()
+			return newFakeWatcher(), nil
+		},
+	}
+
+	svc := &Service{runtimeClient: fc}
+	resultChannel := make(chan any, 10)
+
+	p := makeResolveParams(ctx)
+	p.Args[FieldSelectorArg] = "status.phase=Running"
+
+	go svc.runWatch(p, schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, resultChannel, false, v1.ClusterScoped)
+	collectResults(resultChannel, 3*time.Second)
+
+	require.NotNil(t, listOpts.FieldSelector)
+	assert.Equal(t, "status.phase=Running", listOpts.FieldSelector.String())
+	require.NotNil(t, watchOpts.FieldSelector)
+	assert.Equal(t, "status.phase=Running", watchOpts.FieldSelector.String())
+}
+
+func TestRunWatch_InvalidFieldSelector_SendsError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	fc := &fakeClient{}
+	svc := &Service{runtimeClient: fc}
+	resultChannel := make(chan any, 10)
+
+	p := makeResolveParams(ctx)
+	p.Args[FieldSelectorArg] = "status.phase"
+
+	go svc.runWatch(p, schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, resultChannel, false, v1.ClusterScoped)
+	results := collectResults(resultChannel, 3*time.Second)
+
+	require.Len(t, results, 1)
+	err, ok := results[0].(error)
+	require.True(t, ok)
+	assert.Contains(t, err.Error(), "invalid field selector")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&fc.listCalls))
+}
+
+func TestRunWatch_Panic_SendsErrorAndCloses(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	fc := &fakeClient{
+		listFn: func(_ context.Context, _ client.ObjectList, _ ...client.ListOption) error {
+			panic("boom")
+		},
+	}
+
+	svc := &Service{runtimeClient: fc}
+	resultChannel := make(chan any, 10)
+
+	go svc.runWatch(makeResolveParams(ctx), schema.GroupVersionKind{Group: "", Version: "v1", Kind: "ConfigMap"}, resultChannel, false, v1.ClusterScoped)
+
+	results := collectResults(resultChannel, 3*time.Second)
+	require.Len(t, results, 1)
+	var recovered *recovery.Error
+	require.ErrorAs(t, results[0].(error), &recovered)
+	assert.NotEmpty(t, recovered.CorrelationID)
+}
+
+func TestRunWatch_ResumePoints(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	fc := &fakeClient{
+		listFn: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+			ul := list.(*unstructured.UnstructuredList)
+			ul.SetResourceVersion("100")
+			ul.Items = []unstructured.Unstructured{
+				*makeUnstructuredObj("obj2", "default", "90"),
+				*makeUnstructuredObj("obj1", "default", "80"),
+			}
+			return nil
+		},
+		watchFn: func(_ context.Context, _ client.ObjectList, _ ...client.ListOption) (watch.Interface, error) {
+			w := newFakeWatcher()
+			w.events <- watch.Event{Type: watch.Modified, Object: makeUnstructuredObj("obj1", "default", "101")}
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				cancel()
+			}()
+			return w, nil
+		},
+	}
+
+	svc := &Service{runtimeClient: fc}
+	resultChannel := make(chan any, 10)
+
+	go svc.runWatch(makeResolveParams(ctx), schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, resultChannel, false, v1.ClusterScoped)
+
+	var got []string
+	for _, r := range collectResults(resultChannel, 3*time.Second) {
+		got = append(got, r.(SubscriptionEnvelope).ResourceVersion)
+	}
+	// Listed items are only resumable after the last one, at the list's version.
+	assert.Equal(t, []string{"", "100", "101"}, got)
+}
+
+func TestRunWatch_ResumeFromLastEventID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var watchOpts client.ListOptions
+	fc := &fakeClient{
+		watchFn: func(_ context.Context, _ client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+			watchOpts.ApplyOptions(opts)
+			cancel()
+			return newFakeWatcher(), nil
+		},
+	}
+
+	svc := &Service{runtimeClient: fc}
+	resultChannel := make(chan any, 10)
+
+	p := makeResolveParams(utilscontext.SetResumeFrom(ctx, "42"))
+	p.Args[ResourceVersionArg] = "7"
+
+	go svc.runWatch(p, schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, resultChannel, false, v1.ClusterScoped)
+	collectResults(resultChannel, 3*time.Second)
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&fc.listCalls), "a resumed watch does not list")
+	require.NotNil(t, watchOpts.Raw)
+	assert.Equal(t, "42", watchOpts.Raw.ResourceVersion)
+}
+
+func TestRunWatch_Bookmarks(t *testing.T) {
+	bookmark := &unstructured.Unstructured{}
+	bookmark.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"})
+	bookmark.SetResourceVersion("150")
+
+	for _, requested := range []bool{false, true} {
+		t.Run(fmt.Sprintf("requested=%t", requested), func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			var watchOpts client.ListOptions
+			fc := &fakeClient{
+				listFn: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+					list.(*unstructured.UnstructuredList).SetResourceVersion("100")
+					return nil
+				},
+				watchFn: func(_ context.Context, _ client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+					watchOpts.ApplyOptions(opts)
+					w := newFakeWatcher()
+					w.events <- watch.Event{Type: watch.Bookmark, Object: bookmark}
+					w.events <- watch.Event{Type: watch.Added, Object: makeUnstructuredObj("obj1", "default", "151")}
+					go func() {
+						time.Sleep(100 * time.Millisecond)
+						cancel()
+					}()
+					return w, nil
+				},
+			}
+
+			svc := &Service{runtimeClient: fc}
+			resultChannel := make(chan any, 10)
+
+			p := makeResolveParams(ctx)
+			p.Args[BookmarksArg] = requested
+
+			go svc.runWatch(p, schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, resultChannel, false, v1.ClusterScoped)
+
+			var got []string
+			for _, r := range collectResults(resultChannel, 3*time.Second) {
+				env := r.(SubscriptionEnvelope)
+				got = append(got, env.Type+"@"+env.ResourceVersion)
+			}
+
+			require.NotNil(t, watchOpts.Raw)
+			assert.Equal(t, requested, watchOpts.Raw.AllowWatchBookmarks)
+			if requested {
+				assert.Equal(t, []string{EventTypeBookmark + "@150", EventTypeAdded + "@151"}, got)
+			} else {
+				assert.Equal(t, []string{EventTypeAdded + "@151"}, got)
+			}
+		})
+	}
+}
+
+func TestRunWatch_EmitInitialState(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]any
+		want []string
+		err  bool
+	}{
+		{
+			name: "listed objects are emitted by default",
+			want: []string{"ADDED obj1", "MODIFIED obj2"},
+		},
+		{
+			name: "listed objects are emitted when requested",
+			args: map[string]any{EmitInitialStateArg: true},
+			want: []string{"ADDED obj1", "MODIFIED obj2"},
+		},
+		{
+			name: "only changes are emitted when disabled",
+			args: map[string]any{EmitInitialStateArg: false},
+			want: []string{"MODIFIED obj2"},
+		},
+		{
+			name: "initial state cannot be combined with a resource version",
+			args: map[string]any{EmitInitialStateArg: true, ResourceVersionArg: "50"},
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			fc := &fakeClient{
+				listFn: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+					ul := list.(*unstructured.UnstructuredList)
+					ul.SetResourceVersion("100")
+					ul.Items = []unstructured.Unstructured{*makeUnstructuredObj("obj1", "default", "90")}
+					return nil
+				},
+				watchFn: func(_ context.Context, _ client.ObjectList, _ ...client.ListOption) (watch.Interface, error) {
+					w := newFakeWatcher()
+					w.events <- watch.Event{Type: watch.Modified, Object: makeUnstructuredObj("obj2", "default", "101")}
+					go func() {
+						time.Sleep(100 * time.Millisecond)
+						cancel()
+					}()
+					return w, nil
+				},
+			}
+
+			svc := &Service{runtimeClient: fc}
+			resultChannel := make(chan any, 10)
+
+			p := makeResolveParams(ctx)
+			maps.Copy(p.Args, tt.args)
+
+			go svc.runWatch(p, schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, resultChannel, false, v1.ClusterScoped)
+			results := collectResults(resultChannel, 3*time.Second)
+
+			if tt.err {
+				require.Len(t, results, 1)
+				assert.ErrorIs(t, results[0].(error), errEmitInitialStateWithResourceVersion)
+				return
+			}
+			var got []string
+			for _, r := range results {
+				env := r.(SubscriptionEnvelope)
+				got = append(got, env.Type+" "+env.Object.(map[string]any)["metadata"].(map[string]any)["name"].(string))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// tableAccept asks the API server for a Table, as kubectl get does, falling
+// back to the list for servers without table support.
+const tableAccept = "application/json;as=Table;v=v1;g=meta.k8s.io,application/json"
+
+// TableReader reads the server-side tables kubectl get prints.
+type TableReader struct {
+	client rest.Interface
+}
+
+// NewTableReader creates a reader sending requests with cfg.
+func NewTableReader(cfg *rest.Config) (*TableReader, error) {
+	config := rest.CopyConfig(cfg)
+	config.APIPath = "/"
+	config.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+	client, err := rest.UnversionedRESTClientFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create table client: %w", err)
+	}
+	return &TableReader{client: client}, nil
+}
+
+// List returns the table of the objects of resource in namespace, or in all
+// namespaces if namespace is empty.
+func (t *TableReader) List(ctx context.Context, resource schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*metav1.Table, error) {
+	prefix := []string{"apis", resource.Group, resource.Version}
+	if resource.Group == "" {
+		prefix = []string{"api", resource.Version}
+	}
+
+	body, err := t.client.Get().
+		AbsPath(prefix...).
+		Namespace(namespace).
+		Resource(resource.Resource).
+		VersionedParams(&opts, metav1.ParameterCodec).
+		SetHeader("Accept", tableAccept).
+		Do(ctx).
+		Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	table := &metav1.Table{}
+	if err := json.Unmarshal(body, table); err != nil {
+		return nil, fmt.Errorf("failed to decode table: %w", err)
+	}
+	if table.Kind != "Table" {
+		return nil, fmt.Errorf("the API server returned %s instead of a Table for %s", table.Kind, resource.GroupResource())
+	}
+	return table, nil
+}
+
+// WithTables serves the table queries from tables.
+func (r *Service) WithTables(tables *TableReader) *Service {
+	r.tables = tables
+	return r
+}
+
+// Tables returns the reader the table queries are served from, or nil if
+// disabled.
+func (r *Service) Tables() *TableReader {
+	return r.tables
+}
+
+// TableArgs returns arguments for table queries
+func TableArgs(scope v1.ResourceScope) graphql.FieldConfigArgument {
+	args := graphql.FieldConfigArgument{
+		LabelSelectorArg:      LabelSelectorArgConfig,
+		LabelSelectorInputArg: LabelSelectorInputArgConfig,
+		FieldSelectorArg:      FieldSelectorArgConfig,
+		LimitArg:              LimitArgConfig,
+		ContinueArg:           ContinueArgConfig,
+	}
+	if isResourceNamespaceScoped(scope) {
+		args[NamespaceArg] = &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "The namespace of the objects in the table, all namespaces if omitted",
+		}
+	}
+	return args
+}
+
+// TableItems returns a resolver reading the server-side Table of the objects
+// of a kind, with the columns kubectl get prints. Each row carries the name
+// and namespace of its object.
+func (r *Service) TableItems(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		if r.tables == nil {
+			return nil, errors.New("tables are disabled")
+		}
+
+		ctx, span := otel.Tracer("").Start(p.Context, "TableItems", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		logger := log.FromContext(p.Context).WithValues("operation", "table", "kind", gvk.Kind)
+
+		mapping, err := r.runtimeClient.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find resource of %s: %w", gvk.GroupKind(), err)
+		}
+
+		opts := metav1.ListOptions{}
+		labelSelector, err := labelSelectorFromArgs(p.Args)
+		if err != nil {
+			return nil, err
+		}
+		if labelSelector != nil {
+			opts.LabelSelector = labelSelector.String()
+		}
+		if opts.FieldSelector, err = GetArg[string](p.Args, FieldSelectorArg, false); err != nil {
+			return nil, err
+		}
+		limit, err := GetArg[int](p.Args, LimitArg, false)
+		if err != nil {
+			return nil, err
+		}
+		opts.Limit = int64(limit)
+		if opts.Continue, err = GetArg[string](p.Args, ContinueArg, false); err != nil {
+			return nil, err
+		}
+
+		namespace := ""
+		if isResourceNamespaceScoped(scope) && mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			if namespace, err = GetArg[string](p.Args, NamespaceArg, false); err != nil {
+				return nil, err
+			}
+		}
+
+		table, err := r.tables.List(ctx, mapping.Resource, namespace, opts)
+		if err != nil {
+			logger.Error(err, "Failed to read table")
+			return nil, err
+		}
+
+		columns := make([]map[string]any, len(table.ColumnDefinitions))
+		for i, column := range table.ColumnDefinitions {
+			columns[i] = map[string]any{
+				"name":        column.Name,
+				"type":        column.Type,
+				"format":      column.Format,
+				"description": column.Description,
+				"priority":    int(column.Priority),
+			}
+		}
+
+		rows := make([]map[string]any, len(table.Rows))
+		for i, row := range table.Rows {
+			var object metav1.PartialObjectMetadata
+			if len(row.Object.Raw) > 0 {
+				if err := json.Unmarshal(row.Object.Raw, &object); err != nil {
+					return nil, fmt.Errorf("failed to decode object of table row: %w", err)
+				}
+			}
+			rows[i] = map[string]any{
+				"cells":     row.Cells,
+				"name":      object.Name,
+				"namespace": object.Namespace,
+			}
+		}
+
+		return map[string]any{
+			"columns":         columns,
+			"rows":            rows,
+			"resourceVersion": table.ResourceVersion,
+			"continue":        table.Continue,
+		}, nil
+	}
+}
@@ -0,0 +1,112 @@
+package resolver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTableItems(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+	tests := []struct {
+		name     string
+		response any
+		args     map[string]any
+		wantPath string
+		wantErr  string
+	}{
+		{
+			name: "namespaced table",
+			response: metav1.Table{
+				TypeMeta: metav1.TypeMeta{APIVersion: "meta.k8s.io/v1", Kind: "Table"},
+				ListMeta: metav1.ListMeta{ResourceVersion: "42", Continue: "next"},
+				ColumnDefinitions: []metav1.TableColumnDefinition{
+					{Name: "Name", Type: "string", Format: "name", Description: "Name of the object"},
+					{Name: "Ready", Type: "string"},
+					{Name: "Images", Type: "string", Priority: 1},
+				},
+				Rows: []metav1.TableRow{{
+					Cells: []any{"web", "2/3", "nginx"},
+					Object: runtime.RawExtension{
+						Raw: []byte(`{"kind":"PartialObjectMetadata","apiVersion":"meta.k8s.io/v1","metadata":{"name":"web","namespace":"team-a"}}`),
+					},
+				}},
+			},
+			args:     map[string]any{NamespaceArg: "team-a", LabelSelectorArg: "app=web", LimitArg: 10},
+			wantPath: "/apis/apps/v1/namespaces/team-a/deployments",
+		},
+		{
+			name: "server without tables",
+			response: map[string]any{
+				"kind":       "DeploymentList",
+				"apiVersion": "apps/v1",
+				"items":      []any{},
+			},
+			wantPath: "/apis/apps/v1/deployments",
+			wantErr:  "returned DeploymentList instead of a Table",
+		},
+	}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(gvk, meta.RESTScopeNamespace)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.wantPath, r.URL.Path)
+				assert.Equal(t, tableAccept, r.Header.Get("Accept"))
+				if tt.args[LabelSelectorArg] != nil {
+					assert.Equal(t, "app=web", r.URL.Query().Get("labelSelector"))
+					assert.Equal(t, "10", r.URL.Query().Get("limit"))
+				}
+				w.Header().Set("Content-Type", "application/json")
+				require.NoError(t, json.NewEncoder(w).Encode(tt.response))
+			}))
+			defer server.Close()
+
+			tables, err := NewTableReader(&rest.Config{Host: server.URL})
+			require.NoError(t, err)
+
+			svc := New(fake.NewClientBuilder().WithRESTMapper(mapper).Build()).WithTables(tables)
+			got, err := svc.TableItems(gvk, v1.NamespaceScoped)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    tt.args,
+			})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			table := got.(map[string]any)
+			assert.Equal(t, "42", table["resourceVersion"])
+			assert.Equal(t, "next", table["continue"])
+
+			columns := table["columns"].([]map[string]any)
+			require.Len(t, columns, 3)
+			assert.Equal(t, "Name", columns[0]["name"])
+			assert.Equal(t, "name", columns[0]["format"])
+			assert.Equal(t, 1, columns[2]["priority"])
+
+			rows := table["rows"].([]map[string]any)
+			require.Len(t, rows, 1)
+			assert.Equal(t, []any{"web", "2/3", "nginx"}, rows[0]["cells"])
+			assert.Equal(t, "web", rows[0]["name"])
+			assert.Equal(t, "team-a", rows[0]["namespace"])
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/changefeed"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// UpsertArgs returns arguments for upsert mutations.
+func UpsertArgs(scope v1.ResourceScope, inputType *graphql.InputObject) graphql.FieldConfigArgument {
+	args := CreateArgs(scope, inputType)
+	args[ObjectArg].Description = "The object to create, or to merge into the object of the same name"
+	return args
+}
+
+// UpsertItem creates an object, or merges it into the existing object of the
+// same name like UpdateItem.
+func (r *Service) UpsertItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		logger := log.FromContext(p.Context)
+		ctx, span := otel.Tracer("").Start(p.Context, "UpsertItem", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		logger = logger.WithValues("operation", "upsert", "kind", gvk.Kind)
+
+		objectInput, err := r.getObjectInput(p, ObjectArg)
+		if err != nil {
+			return nil, err
+		}
+
+		obj := &unstructured.Unstructured{Object: objectInput}
+		obj.SetGroupVersionKind(gvk)
+		if isResourceNamespaceScoped(scope) {
+			namespace, err := GetArg[string](p.Args, NamespaceArg, true)
+			if err != nil {
+				return nil, err
+			}
+			obj.SetNamespace(namespace)
+		}
+		if obj.GetName() == "" {
+			return nil, errors.New("object metadata.name is required")
+		}
+
+		dryRunBool, err := GetArg[bool](p.Args, DryRunArg, false)
+		if err != nil {
+			return nil, err
+		}
+		dryRun := []string{}
+		if dryRunBool {
+			dryRun = []string{"All"}
+		}
+
+		submitted := copyObject(obj.Object)
+		patchData, err := json.Marshal(submitted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal object input: %w", err)
+		}
+
+		key := client.ObjectKeyFromObject(obj)
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(gvk)
+		err = r.runtimeClient.Get(ctx, key, existing)
+		if err != nil && !apierrors.IsNotFound(err) {
+			logger.Error(err, "Failed to get object")
+			return nil, err
+		}
+
+		created := false
+		if err != nil {
+			err := r.runtimeClient.Create(ctx, obj, &client.CreateOptions{DryRun: dryRun})
+			switch {
+			case err == nil:
+				created = true
+			case apierrors.IsAlreadyExists(err):
+				// The object was created since it was read, so it is updated.
+			default:
+				logger.Error(err, "Failed to create object")
+				return nil, err
+			}
+		}
+
+		operation := changefeed.OperationCreate
+		if !created {
+			operation = changefeed.OperationUpdate
+			obj = &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(gvk)
+			obj.SetName(key.Name)
+			obj.SetNamespace(key.Namespace)
+			patch := client.RawPatch(types.MergePatchType, patchData)
+			if err := r.runtimeClient.Patch(ctx, obj, patch, &client.PatchOptions{DryRun: dryRun}); err != nil {
+				logger.Error(err, "Failed to patch object")
+				return nil, err
+			}
+		}
+
+		if !dryRunBool {
+			r.recordChange(ctx, operation, gvk, obj)
+		}
+
+		pruned := recordPrunedFields(p, r.prunableFields(gvk, submitted), obj.Object)
+		if len(pruned) > 0 {
+			logger.V(4).Info("API server pruned submitted fields", "fields", pruned)
+		}
+
+		return withPrunedFields(obj.Object, pruned), nil
+	}
+}
@@ -0,0 +1,79 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestUpsertItem(t *testing.T) {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	key := client.ObjectKey{Namespace: "default", Name: "settings"}
+	upsertParams := func(ctx context.Context, data map[string]any, dryRun bool) graphql.ResolveParams {
+		return graphql.ResolveParams{
+			Context: ctx,
+			Args: map[string]any{
+				NamespaceArg: "default",
+				DryRunArg:    dryRun,
+				ObjectArg:    map[string]any{"metadata": map[string]any{"name": "settings"}, "data": data},
+			},
+		}
+	}
+
+	t.Run("creates and updates", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		upsert := New(cl).UpsertItem(gvk, v1.NamespaceScoped)
+
+		_, err := upsert(upsertParams(t.Context(), map[string]any{"mode": "a"}, true))
+		require.NoError(t, err)
+		require.True(t, apierrors.IsNotFound(cl.Get(t.Context(), key, &corev1.ConfigMap{})), "dry run must not create the object")
+
+		_, err = upsert(upsertParams(t.Context(), map[string]any{"mode": "a"}, false))
+		require.NoError(t, err)
+		result, err := upsert(upsertParams(t.Context(), map[string]any{"size": "1"}, false))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"mode": "a", "size": "1"}, result.(map[string]any)["data"])
+
+		var cm corev1.ConfigMap
+		require.NoError(t, cl.Get(t.Context(), key, &cm))
+		assert.Equal(t, map[string]string{"mode": "a", "size": "1"}, cm.Data)
+	})
+
+	t.Run("created concurrently", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+			// Another client creates the object between the read and the create.
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if err := c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}, Data: map[string]string{"mode": "b"}}); err != nil {
+					return err
+				}
+				return apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, key.Name)
+			},
+		}).Build()
+
+		result, err := New(cl).UpsertItem(gvk, v1.NamespaceScoped)(upsertParams(t.Context(), map[string]any{"size": "1"}, false))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"mode": "b", "size": "1"}, result.(map[string]any)["data"])
+	})
+
+	t.Run("name required", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		_, err := New(cl).UpsertItem(gvk, v1.NamespaceScoped)(graphql.ResolveParams{
+			Context: t.Context(),
+			Args:    map[string]any{NamespaceArg: "default", ObjectArg: map[string]any{"metadata": map[string]any{"generateName": "settings-"}}},
+		})
+		assert.ErrorContains(t, err, "metadata.name is required")
+	})
+}
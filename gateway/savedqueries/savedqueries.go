@@ -0,0 +1,191 @@
+// Package savedqueries stores named GraphQL operations saved by users, so
+// teams can share curated queries through the gateway.
+package savedqueries
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limits of saved queries, so the store cannot be used to fill the disk.
+const (
+	MaxDocumentBytes = 64 * 1024
+	MaxPerUser       = 100
+)
+
+// validName restricts saved query names to something usable in URLs and
+// file names.
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,62}$`)
+
+// ErrNotFound is returned when deleting a query that does not exist.
+var ErrNotFound = errors.New("saved query not found")
+
+// Query is a named GraphQL operation saved by a user for a cluster.
+type Query struct {
+	Cluster     string    `json:"cluster"`
+	Owner       string    `json:"owner"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Document    string    `json:"document"`
+	Shared      bool      `json:"shared"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type key struct {
+	cluster, owner, name string
+}
+
+// Store holds the saved queries in memory and in a JSON file, rewritten on
+// every change.
+type Store struct {
+	mu      sync.RWMutex
+	path    string
+	queries map[key]Query
+}
+
+// New returns a store persisting to path, loading the queries saved in it.
+func New(path string) (*Store, error) {
+	if path == "" {
+		return nil, errors.New("saved queries file must be set")
+	}
+	s := &Store{path: path, queries: map[key]Query{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved queries file: %w", err)
+	}
+	var queries []Query
+	if err := json.Unmarshal(data, &queries); err != nil {
+		return nil, fmt.Errorf("failed to parse saved queries file %s: %w", path, err)
+	}
+	for _, q := range queries {
+		s.queries[key{q.Cluster, q.Owner, q.Name}] = q
+	}
+	return s, nil
+}
+
+// Save stores q, replacing the owner's query of the same name.
+func (s *Store) Save(q Query) (Query, error) {
+	if !validName.MatchString(q.Name) {
+		return Query{}, fmt.Errorf("invalid name %q: must be 1-63 letters, digits, '.', '_' or '-', starting with a letter or digit", q.Name)
+	}
+	if strings.TrimSpace(q.Document) == "" {
+		return Query{}, errors.New("document must not be empty")
+	}
+	if len(q.Document) > MaxDocumentBytes {
+		return Query{}, fmt.Errorf("document exceeds %d bytes", MaxDocumentBytes)
+	}
+	q.UpdatedAt = time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key{q.Cluster, q.Owner, q.Name}
+	if _, exists := s.queries[k]; !exists && s.countLocked(q.Cluster, q.Owner) >= MaxPerUser {
+		return Query{}, fmt.Errorf("at most %d queries can be saved per user", MaxPerUser)
+	}
+	previous, existed := s.queries[k]
+	s.queries[k] = q
+	if err := s.persistLocked(); err != nil {
+		if existed {
+			s.queries[k] = previous
+		} else {
+			delete(s.queries, k)
+		}
+		return Query{}, err
+	}
+	return q, nil
+}
+
+// Delete removes the owner's query called name.
+func (s *Store) Delete(cluster, owner, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key{cluster, owner, name}
+	previous, ok := s.queries[k]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(s.queries, k)
+	if err := s.persistLocked(); err != nil {
+		s.queries[k] = previous
+		return err
+	}
+	return nil
+}
+
+// List returns the queries of the cluster saved by user and, with
+// includeShared, those shared by others, ordered by owner and name.
+func (s *Store) List(cluster, user string, includeShared bool) []Query {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := []Query{}
+	for k, q := range s.queries {
+		if k.cluster == cluster && (k.owner == user || (includeShared && q.Shared)) {
+			result = append(result, q)
+		}
+	}
+	slices.SortFunc(result, func(a, b Query) int {
+		if c := strings.Compare(a.Owner, b.Owner); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result
+}
+
+func (s *Store) countLocked(cluster, owner string) int {
+	count := 0
+	for k := range s.queries {
+		if k.cluster == cluster && k.owner == owner {
+			count++
+		}
+	}
+	return count
+}
+
+// persistLocked replaces the file with the current queries, so it is never
+// left half written.
+func (s *Store) persistLocked() error {
+	queries := make([]Query, 0, len(s.queries))
+	for _, q := range s.queries {
+		queries = append(queries, q)
+	}
+	slices.SortFunc(queries, func(a, b Query) int {
+		return strings.Compare(a.Cluster+"/"+a.Owner+"/"+a.Name, b.Cluster+"/"+b.Owner+"/"+b.Name)
+	})
+	data, err := json.MarshalIndent(queries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode saved queries: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write saved queries file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write saved queries file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write saved queries file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write saved queries file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package savedqueries_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/savedqueries"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func names(queries []savedqueries.Query) []string {
+	result := make([]string, 0, len(queries))
+	for _, q := range queries {
+		result = append(result, q.Owner+"/"+q.Name)
+	}
+	return result
+}
+
+func TestNew_RequiresPath(t *testing.T) {
+	_, err := savedqueries.New("")
+	assert.Error(t, err)
+}
+
+func TestStore_SaveAndList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queries.json")
+	store, err := savedqueries.New(path)
+	require.NoError(t, err)
+
+	for _, q := range []savedqueries.Query{
+		{Cluster: "c1", Owner: "alice", Name: "pods", Document: "{ pods { name } }"},
+		{Cluster: "c1", Owner: "bob", Name: "shared", Document: "{ a }", Shared: true},
+		{Cluster: "c1", Owner: "bob", Name: "private", Document: "{ b }"},
+		{Cluster: "c2", Owner: "alice", Name: "other", Document: "{ c }"},
+	} {
+		_, err := store.Save(q)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"alice/pods", "bob/shared"}, names(store.List("c1", "alice", true)))
+	assert.Equal(t, []string{"alice/pods"}, names(store.List("c1", "alice", false)))
+	assert.Equal(t, []string{"bob/private", "bob/shared"}, names(store.List("c1", "bob", true)))
+	assert.Equal(t, []string{"alice/other"}, names(store.List("c2", "alice", true)))
+
+	saved, err := store.Save(savedqueries.Query{Cluster: "c1", Owner: "alice", Name: "pods", Document: "{ pods { namespace } }"})
+	require.NoError(t, err)
+	assert.False(t, saved.UpdatedAt.IsZero())
+	assert.Len(t, store.List("c1", "alice", false), 1)
+
+	reloaded, err := savedqueries.New(path)
+	require.NoError(t, err)
+	queries := reloaded.List("c1", "alice", false)
+	require.Len(t, queries, 1)
+	assert.Equal(t, "{ pods { namespace } }", queries[0].Document)
+	assert.Equal(t, []string{"alice/pods", "bob/shared"}, names(reloaded.List("c1", "alice", true)))
+}
+
+func TestStore_SaveInvalid(t *testing.T) {
+	store, err := savedqueries.New(filepath.Join(t.TempDir(), "queries.json"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		query   savedqueries.Query
+		wantErr string
+	}{
+		{name: "empty name", query: savedqueries.Query{Document: "{ a }"}, wantErr: "invalid name"},
+		{name: "name with slash", query: savedqueries.Query{Name: "a/b", Document: "{ a }"}, wantErr: "invalid name"},
+		{name: "empty document", query: savedqueries.Query{Name: "a", Document: " "}, wantErr: "document must not be empty"},
+		{name: "large document", query: savedqueries.Query{Name: "a", Document: strings.Repeat("a", savedqueries.MaxDocumentBytes+1)}, wantErr: "document exceeds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := store.Save(tt.query)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestStore_MaxPerUser(t *testing.T) {
+	store, err := savedqueries.New(filepath.Join(t.TempDir(), "queries.json"))
+	require.NoError(t, err)
+
+	for i := range savedqueries.MaxPerUser {
+		_, err := store.Save(savedqueries.Query{Cluster: "c1", Owner: "alice", Name: fmt.Sprintf("q%d", i), Document: "{ a }"})
+		require.NoError(t, err)
+	}
+	_, err = store.Save(savedqueries.Query{Cluster: "c1", Owner: "alice", Name: "one-more", Document: "{ a }"})
+	assert.ErrorContains(t, err, "at most")
+
+	// Replacing a query and saving for another user still work.
+	_, err = store.Save(savedqueries.Query{Cluster: "c1", Owner: "alice", Name: "q0", Document: "{ b }"})
+	assert.NoError(t, err)
+	_, err = store.Save(savedqueries.Query{Cluster: "c1", Owner: "bob", Name: "q0", Document: "{ b }"})
+	assert.NoError(t, err)
+}
+
+func TestStore_Delete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queries.json")
+	store, err := savedqueries.New(path)
+	require.NoError(t, err)
+
+	_, err = store.Save(savedqueries.Query{Cluster: "c1", Owner: "alice", Name: "pods", Document: "{ a }", Shared: true})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, store.Delete("c1", "bob", "pods"), savedqueries.ErrNotFound)
+	require.NoError(t, store.Delete("c1", "alice", "pods"))
+	assert.Empty(t, store.List("c1", "alice", true))
+
+	reloaded, err := savedqueries.New(path)
+	require.NoError(t, err)
+	assert.Empty(t, reloaded.List("c1", "alice", true))
+}
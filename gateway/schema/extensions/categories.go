@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/platform-mesh/kubernetes-graphql-gateway/apis"
+	openapiext "github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -34,26 +34,11 @@ func (m *CategoryManager) Store(
 		return errors.New("no resource extensions")
 	}
 
-	categoriesRaw, ok := resourceSpec.Extensions[apis.CategoriesExtensionKey]
-	if !ok {
-		return fmt.Errorf("%s extension not found", apis.CategoriesExtensionKey)
+	if _, ok := resourceSpec.Extensions[openapiext.CategoriesKey]; !ok {
+		return fmt.Errorf("%s extension not found", openapiext.CategoriesKey)
 	}
 
-	categoriesRawArray, ok := categoriesRaw.([]any)
-	if !ok {
-		return fmt.Errorf("%s extension is not an array", apis.CategoriesExtensionKey)
-	}
-
-	categories := make([]string, len(categoriesRawArray))
-	for i, v := range categoriesRawArray {
-		if str, ok := v.(string); ok {
-			categories[i] = str
-		} else {
-			return fmt.Errorf("failed to convert %v to string", v)
-		}
-	}
-
-	for _, category := range categories {
+	for _, category := range openapiext.Categories(resourceSpec) {
 		m.typeByCategory[category] = append(m.typeByCategory[category], resolver.TypeByCategory{
 			Group:   gvk.Group,
 			Version: gvk.Version,
@@ -12,6 +12,9 @@ const (
 type CustomQueryGenerator struct {
 	resolver        *resolver.Service
 	categoryManager *CategoryManager
+
+	// typeByCategoryType is shared by the query and the subscription.
+	typeByCategoryType *graphql.Object
 }
 
 func NewCustomQueryGenerator(resolver *resolver.Service, categoryManager *CategoryManager) *CustomQueryGenerator {
@@ -22,25 +25,78 @@ func NewCustomQueryGenerator(resolver *resolver.Service, categoryManager *Catego
 }
 
 func (g *CustomQueryGenerator) AddTypeByCategoryQuery(rootQueryType *graphql.Object) {
-	resourceType := graphql.NewObject(graphql.ObjectConfig{
+	rootQueryType.AddFieldConfig(typeByCategoryFieldName, &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(g.resourceType()))),
+		Args: graphql.FieldConfigArgument{
+			resolver.NameArg: resolver.NameArgConfig,
+		},
+		Resolve: g.resolver.TypeByCategory(g.categoryManager.AllCategories()),
+	})
+}
+
+// AddTypeByCategorySubscription adds a subscription emitting the types in a
+// category whenever they change, e.g. after a CustomResourceDefinition of
+// the category was installed. It requires a category feed.
+func (g *CustomQueryGenerator) AddTypeByCategorySubscription(rootSubscription *graphql.Object) {
+	if g.resolver.CategoryFeed() == nil {
+		return
+	}
+
+	rootSubscription.AddFieldConfig(typeByCategoryFieldName, &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(g.resourceType()))),
+		Description: "Emits the types in a category on subscription and whenever a schema update changes them",
+		Args: graphql.FieldConfigArgument{
+			resolver.NameArg: resolver.NameArgConfig,
+		},
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return p.Source, nil
+		},
+		Subscribe: g.resolver.SubscribeTypeByCategory(),
+	})
+}
+
+func (g *CustomQueryGenerator) resourceType() *graphql.Object {
+	if g.typeByCategoryType != nil {
+		return g.typeByCategoryType
+	}
+
+	g.typeByCategoryType = graphql.NewObject(graphql.ObjectConfig{
 		Name: typeByCategoryFieldName + "Object",
 		Fields: graphql.Fields{
 			"kind":    graphqlStringField(),
 			"group":   graphqlStringField(),
 			"version": graphqlStringField(),
 			"scope":   graphqlStringField(),
+			"migrationStatus": &graphql.Field{
+				Type:        migrationStatusType,
+				Description: "Whether stored objects are on the storage version; null for types without a CustomResourceDefinition",
+				Resolve:     g.resolver.TypeMigrationStatus(),
+			},
 		},
 	})
-
-	rootQueryType.AddFieldConfig(typeByCategoryFieldName, &graphql.Field{
-		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(resourceType))),
-		Args: graphql.FieldConfigArgument{
-			resolver.NameArg: resolver.NameArgConfig,
-		},
-		Resolve: g.resolver.TypeByCategory(g.categoryManager.AllCategories()),
-	})
+	return g.typeByCategoryType
 }
 
+var storageVersionMigrationType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "StorageVersionMigration",
+	Fields: graphql.Fields{
+		"name":  graphqlStringField(),
+		"phase": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "Pending, Running, Succeeded or Failed"},
+	},
+})
+
+var migrationStatusType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "MigrationStatus",
+	Description: "Storage version migration status of a custom resource",
+	Fields: graphql.Fields{
+		"storageVersion":  &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "The version new objects are stored in"},
+		"storedVersions":  &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))), Description: "The versions objects may be stored in"},
+		"pendingVersions": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))), Description: "Stored versions other than the storage version"},
+		"migrated":        &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Description: "Whether all objects are stored in the storage version"},
+		"migration":       &graphql.Field{Type: storageVersionMigrationType, Description: "The latest StorageVersionMigration of the resource, if the cluster serves the API"},
+	},
+})
+
 func graphqlStringField() *graphql.Field {
 	return &graphql.Field{
 		Type: graphql.NewNonNull(graphql.String),
@@ -5,8 +5,9 @@ import (
 	"fmt"
 
 	"github.com/graphql-go/graphql"
-	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	openapiext "github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/recovery"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
@@ -30,14 +31,15 @@ type PodLogEntry struct {
 
 type CustomSubscriptionGenerator struct {
 	clientset kubernetes.Interface
+	logLimits LogLimits
 }
 
-func NewCustomSubscriptionGenerator(restCfg *rest.Config) (*CustomSubscriptionGenerator, error) {
+func NewCustomSubscriptionGenerator(restCfg *rest.Config, logLimits LogLimits) (*CustomSubscriptionGenerator, error) {
 	cs, err := kubernetes.NewForConfig(restCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
 	}
-	return &CustomSubscriptionGenerator{clientset: cs}, nil
+	return &CustomSubscriptionGenerator{clientset: cs, logLimits: logLimits}, nil
 }
 
 func (g *CustomSubscriptionGenerator) AddPodLogsSubscription(rootSubscription *graphql.Object, definitions map[string]*spec.Schema) {
@@ -149,6 +151,14 @@ func (g *CustomSubscriptionGenerator) subscribePodLogs() graphql.FieldResolveFn
 		go func() {
 			defer close(resultChannel)
 			defer func() { _ = stream.Close() }()
+			defer func() {
+				if err := recovery.Recovered(p.Context, "podLogs", recover()); err != nil {
+					select {
+					case <-p.Context.Done():
+					case resultChannel <- err:
+					}
+				}
+			}()
 
 			logger := log.FromContext(p.Context).WithValues(
 				"operation", "podLogs",
@@ -185,7 +195,7 @@ func (g *CustomSubscriptionGenerator) subscribePodLogs() graphql.FieldResolveFn
 
 func hasPodResource(definitions map[string]*spec.Schema) bool {
 	for _, def := range definitions {
-		gvk, err := apischema.ExtractGVK(def)
+		gvk, err := openapiext.GVK(def)
 		if err != nil || gvk == nil {
 			continue
 		}
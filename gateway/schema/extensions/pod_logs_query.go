@@ -0,0 +1,292 @@
+package extensions
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const (
+	podLogsPageFieldName = "podLogsPage"
+	cursorArg            = "cursor"
+	limitArg             = "limit"
+)
+
+// LogLimits bounds the amount of log data a single podLogsPage query returns.
+type LogLimits struct {
+	// MaxLines caps the number of lines per page. Requested limits above it
+	// are lowered to MaxLines. 0 disables the cap.
+	MaxLines int
+
+	// MaxBytes caps the number of bytes of the lines of a page. Longer lines
+	// are cut. 0 disables the cap.
+	MaxBytes int64
+}
+
+// PodLogPage is one page of a pod's log, addressed by a cursor.
+type PodLogPage struct {
+	Lines     []string `json:"lines"`
+	Container string   `json:"container"`
+	// NextCursor is the cursor of the next page, or nil if this is the last
+	// page.
+	NextCursor *string `json:"nextCursor"`
+	// Truncated is true if the page was cut short or a line was cut by the
+	// byte limit.
+	Truncated bool `json:"truncated"`
+}
+
+// AddPodLogsQuery adds a podLogsPage query that returns a pod's log in pages
+// of lines. Pages are independent of any parent query and addressed by a
+// cursor the API server resumes the log at, so clients can page through a
+// log without holding a stream open.
+func (g *CustomSubscriptionGenerator) AddPodLogsQuery(rootQuery *graphql.Object, definitions map[string]*spec.Schema) {
+	if !hasPodResource(definitions) {
+		return
+	}
+
+	podLogPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PodLogPage",
+		Fields: graphql.Fields{
+			"lines":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+			"container":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"nextCursor": &graphql.Field{Type: graphql.String, Description: "Cursor of the next page, null on the last page"},
+			"truncated":  &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Description: "Whether the page was cut short, or a line was cut, by the server's byte limit"},
+		},
+	})
+
+	rootQuery.AddFieldConfig(podLogsPageFieldName, &graphql.Field{
+		Type: graphql.NewNonNull(podLogPageType),
+		Args: graphql.FieldConfigArgument{
+			resolver.NameArg: resolver.NameArgConfig,
+			resolver.NamespaceArg: &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Namespace of the pod",
+			},
+			containerArg: &graphql.ArgumentConfig{
+				Type:        graphql.String,
+				Description: "Container name (required for multi-container pods)",
+			},
+			sinceSecondsArg: &graphql.ArgumentConfig{
+				Type:        graphql.Int,
+				Description: "Only return logs newer than this many seconds (ignored with a cursor)",
+			},
+			cursorArg: &graphql.ArgumentConfig{
+				Type:        graphql.String,
+				Description: "The nextCursor of the previous page",
+			},
+			limitArg: &graphql.ArgumentConfig{
+				Type:        graphql.Int,
+				Description: "Maximum number of lines to return (capped by the server)",
+			},
+		},
+		Resolve: g.resolvePodLogsPage(),
+	})
+}
+
+func (g *CustomSubscriptionGenerator) resolvePodLogsPage() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		name, err := resolver.GetArg[string](p.Args, resolver.NameArg, true)
+		if err != nil {
+			return nil, err
+		}
+
+		namespace, err := resolver.GetArg[string](p.Args, resolver.NamespaceArg, true)
+		if err != nil {
+			return nil, err
+		}
+
+		container, err := resolver.GetArg[string](p.Args, containerArg, false)
+		if err != nil {
+			return nil, err
+		}
+
+		sinceSeconds, err := resolver.GetArg[int](p.Args, sinceSecondsArg, false)
+		if err != nil {
+			return nil, err
+		}
+
+		cursor, err := resolver.GetArg[string](p.Args, cursorArg, false)
+		if err != nil {
+			return nil, err
+		}
+		var after *logCursor
+		if cursor != "" {
+			parsed, err := parseLogCursor(cursor)
+			if err != nil {
+				return nil, err
+			}
+			after = &parsed
+		}
+
+		limit, err := resolver.GetArg[int](p.Args, limitArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if limit < 0 {
+			return nil, fmt.Errorf("%s must not be negative", limitArg)
+		}
+		if g.logLimits.MaxLines > 0 && (limit == 0 || limit > g.logLimits.MaxLines) {
+			limit = g.logLimits.MaxLines
+		}
+
+		// Timestamps locate the lines for the cursor and are removed again.
+		opts := &corev1.PodLogOptions{Container: container, Timestamps: true}
+		if after != nil {
+			// The API server resumes at the second of the cursor; the lines
+			// of that second up to the cursor are skipped while reading.
+			sinceTime := metav1.NewTime(after.time)
+			opts.SinceTime = &sinceTime
+		} else if sinceSeconds > 0 {
+			ss := int64(sinceSeconds)
+			opts.SinceSeconds = &ss
+		}
+
+		stream, err := g.clientset.CoreV1().Pods(namespace).GetLogs(name, opts).Stream(p.Context)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod logs: %w", err)
+		}
+		defer func() { _ = stream.Close() }()
+
+		page, err := readLogPage(stream, after, limit, g.logLimits.MaxBytes)
+		if err != nil {
+			return nil, fmt.Errorf("error reading pod logs: %w", err)
+		}
+		page.Container = container
+		return page, nil
+	}
+}
+
+// logCursor is the position after a line of a log: the timestamp of the
+// line and the number of lines with that timestamp up to it, as several
+// lines can share one.
+type logCursor struct {
+	time time.Time
+	seen int
+}
+
+// String returns the opaque cursor: the timestamp and count separated by a
+// space and base64 encoded.
+func (c logCursor) String() string {
+	raw := c.time.Format(time.RFC3339Nano) + " " + strconv.Itoa(c.seen)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// parseLogCursor parses a cursor returned by logCursor.String.
+func parseLogCursor(cursor string) (logCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return logCursor{}, fmt.Errorf("invalid %s %q: %w", cursorArg, cursor, err)
+	}
+	timestamp, seen, _ := strings.Cut(string(raw), " ")
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return logCursor{}, fmt.Errorf("invalid %s %q: %w", cursorArg, cursor, err)
+	}
+	n, err := strconv.Atoi(seen)
+	if err != nil || n < 1 {
+		return logCursor{}, fmt.Errorf("invalid %s %q", cursorArg, cursor)
+	}
+	return logCursor{time: t, seen: n}, nil
+}
+
+// readLogPage reads the page of a log with timestamps following the cursor
+// after, or from its start if after is nil. The page holds up to limit lines
+// (all remaining lines if limit is 0) of up to maxBytes bytes in total
+// (unlimited if maxBytes is 0), and its next cursor is set if lines follow.
+func readLogPage(r io.Reader, after *logCursor, limit int, maxBytes int64) (*PodLogPage, error) {
+	page := &PodLogPage{Lines: []string{}}
+
+	splitter := &lineSplitter{max: math.MaxInt}
+	if maxBytes > 0 {
+		// Lines are read up to the byte limit of the page plus the longest
+		// timestamp, and their text is cut to the limit below.
+		splitter.max = int(maxBytes) + len(time.RFC3339Nano) + 1
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, min(bufio.MaxScanTokenSize, splitter.max)), splitter.max)
+	scanner.Split(splitter.split)
+
+	var position logCursor
+	var size int64
+	for scanner.Scan() {
+		line := logCursor{time: position.time, seen: 1}
+		text := scanner.Text()
+		// The API server prefixes every line with its timestamp; lines
+		// without one keep the time of the previous line.
+		if timestamp, rest, found := strings.Cut(text, " "); found {
+			if t, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+				line.time, text = t, rest
+			}
+		}
+		if line.time.Equal(position.time) {
+			line.seen = position.seen + 1
+		}
+		cut := splitter.cut
+		if maxBytes > 0 && int64(len(text)) > maxBytes {
+			// Cut lines may end within a character, which is dropped.
+			text, cut = strings.ToValidUTF8(text[:maxBytes], ""), true
+		}
+
+		if after != nil && (line.time.Before(after.time) || line.time.Equal(after.time) && line.seen <= after.seen) {
+			position = line
+			continue
+		}
+
+		full := maxBytes > 0 && len(page.Lines) > 0 && size+int64(len(text))+1 > maxBytes
+		if full || limit > 0 && len(page.Lines) == limit {
+			next := position.String()
+			page.NextCursor = &next
+			page.Truncated = page.Truncated || full
+			return page, nil
+		}
+
+		page.Lines = append(page.Lines, text)
+		page.Truncated = page.Truncated || cut
+		size += int64(len(text)) + 1
+		position = line
+	}
+	return page, scanner.Err()
+}
+
+// lineSplitter splits a log into lines like bufio.ScanLines, but cuts lines
+// longer than max bytes instead of failing and drops the rest of them.
+type lineSplitter struct {
+	max int
+	// cut is set if the last line was cut.
+	cut bool
+	// dropping is set while the rest of a cut line is dropped.
+	dropping bool
+}
+
+func (s *lineSplitter) split(data []byte, atEOF bool) (int, []byte, error) {
+	advance, token, err := bufio.ScanLines(data, atEOF)
+	switch {
+	case err != nil:
+		return 0, nil, err
+	case token != nil && s.dropping:
+		s.dropping = false
+		return advance, nil, nil
+	case token != nil:
+		s.cut = false
+		return advance, token, nil
+	case len(data) < s.max:
+		return advance, nil, nil
+	case s.dropping:
+		return len(data), nil, nil
+	default:
+		s.cut, s.dropping = true, true
+		return len(data), data[:s.max], nil
+	}
+}
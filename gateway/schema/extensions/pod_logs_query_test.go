@@ -0,0 +1,98 @@
+package extensions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readAllPages reads a log page by page, following the next cursors.
+func readAllPages(t *testing.T, log string, limit int, maxBytes int64) [][]string {
+	t.Helper()
+	var pages [][]string
+	var after *logCursor
+	for range 100 {
+		page, err := readLogPage(strings.NewReader(log), after, limit, maxBytes)
+		require.NoError(t, err)
+		pages = append(pages, page.Lines)
+		if page.NextCursor == nil {
+			return pages
+		}
+		cursor, err := parseLogCursor(*page.NextCursor)
+		require.NoError(t, err)
+		after = &cursor
+	}
+	t.Fatal("log has more than 100 pages")
+	return nil
+}
+
+func TestReadLogPage(t *testing.T) {
+	log := "2024-01-01T00:00:00.1Z l0\n" +
+		"2024-01-01T00:00:00.2Z l1\n" +
+		// Lines may share a timestamp.
+		"2024-01-01T00:00:00.2Z l2\n" +
+		"2024-01-01T00:00:00.2Z l3\n" +
+		"2024-01-01T00:00:01Z l4\n"
+
+	tests := []struct {
+		name      string
+		limit     int
+		maxBytes  int64
+		wantPages [][]string
+	}{
+		{name: "pages of lines", limit: 2, wantPages: [][]string{{"l0", "l1"}, {"l2", "l3"}, {"l4"}}},
+		{name: "exact fit", limit: 5, wantPages: [][]string{{"l0", "l1", "l2", "l3", "l4"}}},
+		{name: "no limit", wantPages: [][]string{{"l0", "l1", "l2", "l3", "l4"}}},
+		{name: "byte limit per page", maxBytes: 7, wantPages: [][]string{{"l0", "l1"}, {"l2", "l3"}, {"l4"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantPages, readAllPages(t, log, tt.limit, tt.maxBytes))
+		})
+	}
+}
+
+func TestReadLogPage_Truncated(t *testing.T) {
+	log := "2024-01-01T00:00:00Z short\n" +
+		"2024-01-01T00:00:01Z " + strings.Repeat("x", 100_000) + "\n" +
+		"2024-01-01T00:00:02Z after\n"
+
+	page, err := readLogPage(strings.NewReader(log), nil, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"short"}, page.Lines)
+	assert.True(t, page.Truncated)
+	require.NotNil(t, page.NextCursor)
+
+	// Lines longer than the byte limit are cut instead of failing the page.
+	after, err := parseLogCursor(*page.NextCursor)
+	require.NoError(t, err)
+	page, err = readLogPage(strings.NewReader(log), &after, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{strings.Repeat("x", 10)}, page.Lines)
+	assert.True(t, page.Truncated)
+
+	// Without a byte limit, long lines are read whole.
+	page, err = readLogPage(strings.NewReader(log), nil, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"short", strings.Repeat("x", 100_000), "after"}, page.Lines)
+	assert.False(t, page.Truncated)
+	assert.Nil(t, page.NextCursor)
+}
+
+func TestParseLogCursor(t *testing.T) {
+	page, err := readLogPage(strings.NewReader("2024-01-01T00:00:00.5Z a\n2024-01-01T00:00:00.5Z b\n"), nil, 1, 0)
+	require.NoError(t, err)
+	require.NotNil(t, page.NextCursor)
+	cursor, err := parseLogCursor(*page.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-01T00:00:00.5Z", cursor.time.Format("2006-01-02T15:04:05.999999999Z07:00"))
+	assert.Equal(t, 1, cursor.seen)
+
+	for _, invalid := range []string{"not base64!", "bm90IGEgY3Vyc29y", ""} {
+		_, err := parseLogCursor(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/fields"
+)
+
+// federationLink imports the federation directives used by subgraph SDL.
+const federationLink = `extend schema @link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@key"])` + "\n\n"
+
+// PrintSubgraphSDL renders a schema as the SDL of an Apollo Federation
+// subgraph: the members of the _Entity union are marked with a key on their
+// metadata name and namespace, and the types and queries federation adds
+// to every subgraph are left out.
+func PrintSubgraphSDL(s *graphql.Schema) string {
+	opts := sdlOptions{
+		header: federationLink,
+		omitTypes: map[string]bool{
+			fields.EntityUnionName: true,
+			fields.AnyScalarName:   true,
+			fields.ServiceTypeName: true,
+		},
+		omitFields:     map[string]bool{},
+		typeDirectives: map[string]string{},
+	}
+	if query := s.QueryType(); query != nil {
+		opts.omitFields[query.Name()+"."+fields.EntitiesQuery] = true
+		opts.omitFields[query.Name()+"."+fields.ServiceQuery] = true
+	}
+	if entities, ok := s.Type(fields.EntityUnionName).(*graphql.Union); ok {
+		for _, member := range entities.Types() {
+			opts.typeDirectives[member.Name()] = " @key(fields: " + strconv.Quote(fields.EntityKeyFields) + ")"
+		}
+	}
+	return printSDL(s, opts)
+}
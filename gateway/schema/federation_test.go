@@ -0,0 +1,42 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestPrintSubgraphSDL(t *testing.T) {
+	var def spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {"metadata": {"type": "object", "properties": {"name": {"type": "string"}, "namespace": {"type": "string"}}}},
+		"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Widget"}],
+		"x-kubernetes-scope": "Namespaced"
+	}`), &def))
+
+	provider, err := New(t.Context(), map[string]*spec.Schema{"com.example.v1.Widget": &def}, resolver.New(nil).WithFederation(true), nil)
+	require.NoError(t, err)
+
+	sdl := PrintSubgraphSDL(provider.GetSchema())
+	assert.Contains(t, sdl, `extend schema @link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@key"])`)
+	assert.Contains(t, sdl, `type ExampleComV1Widget implements Node @key(fields: "metadata { name namespace }") {`)
+	for _, omitted := range []string{"_Entity", "_Any", "_Service", "_entities", "_service"} {
+		assert.NotContains(t, sdl, omitted)
+	}
+	assert.NotContains(t, PrintSDL(provider.GetSchema()), "@key")
+
+	result := graphql.Do(graphql.Params{
+		Schema:        *provider.GetSchema(),
+		Context:       t.Context(),
+		RequestString: `{ _service { sdl } }`,
+	})
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string]any{"_service": map[string]any{"sdl": sdl}}, result.Data)
+}
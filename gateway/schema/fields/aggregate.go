@@ -0,0 +1,15 @@
+package fields
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// AggregateBucketType is a bucket of an aggregate query.
+var AggregateBucketType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AggregateBucket",
+	Description: "The number of objects with a value of the grouped field",
+	Fields: graphql.Fields{
+		"value": &graphql.Field{Type: graphql.String, Description: "The value of the field, null for objects without it"},
+		"count": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+	},
+})
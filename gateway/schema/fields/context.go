@@ -1,6 +1,8 @@
 package fields
 
 import (
+	"slices"
+
 	"github.com/graphql-go/graphql"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -13,11 +15,24 @@ type ResourceContext struct {
 	UniqueTypeName string
 	ResourceType   *graphql.Object
 	InputType      *graphql.InputObject
-	SingularName   string
-	PluralName     string
-	SanitizedGroup string
+	// CreateInputType is InputType with the required fields non-null and
+	// the OpenAPI defaults, as only create mutations must set all fields.
+	CreateInputType *graphql.InputObject
+	SingularName    string
+	PluralName      string
+	SanitizedGroup  string
+	// Subresources lists the subresources the API server serves for the
+	// resource, e.g. "status" or "scale".
+	Subresources []string
+	// PrinterColumns are the additional printer columns of a custom
+	// resource, which sortBy accepts by name.
+	PrinterColumns []apiextensionsv1.CustomResourceColumnDefinition
 }
 
 func (r *ResourceContext) IsNamespaceScoped() bool {
 	return r.Scope == apiextensionsv1.NamespaceScoped
 }
+
+func (r *ResourceContext) HasSubresource(name string) bool {
+	return slices.Contains(r.Subresources, name)
+}
@@ -0,0 +1,41 @@
+package fields
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+)
+
+// FieldChangeTypeEnum is the type of a change in a diff.
+var FieldChangeTypeEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "FieldChangeType",
+	Values: graphql.EnumValueConfigMap{
+		resolver.FieldAdded:   &graphql.EnumValueConfig{Value: resolver.FieldAdded, Description: "The field is only set in the new object"},
+		resolver.FieldRemoved: &graphql.EnumValueConfig{Value: resolver.FieldRemoved, Description: "The field is only set in the live object"},
+		resolver.FieldChanged: &graphql.EnumValueConfig{Value: resolver.FieldChanged, Description: "The field has a different value"},
+	},
+})
+
+// DiffType is the result of a diff query.
+var DiffType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ObjectDiff",
+	Description: "The changes applying an object would make to the live object",
+	Fields: graphql.Fields{
+		"exists": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "False if the object does not exist yet, so that all of its fields are added",
+		},
+		"changes": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.NewObject(graphql.ObjectConfig{
+				Name:        "FieldChange",
+				Description: "A changed field, with Kubernetes property names in its path",
+				Fields: graphql.Fields{
+					"path":     &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "The path of the field, e.g. spec.containers[0].image"},
+					"type":     &graphql.Field{Type: graphql.NewNonNull(FieldChangeTypeEnum)},
+					"oldValue": &graphql.Field{Type: types.JSONScalar, Description: "The value of the live object, null if the field is added"},
+					"newValue": &graphql.Field{Type: types.JSONScalar, Description: "The value after applying, null if the field is removed"},
+				},
+			})))),
+		},
+	},
+})
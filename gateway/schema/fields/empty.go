@@ -0,0 +1,84 @@
+package fields
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// NormalizeEmptyValues wraps the list and map fields of the resource types
+// and the types below them, so that empty and absent values are returned
+// according to the empty values mode of the resolver. Fields with arguments
+// are computed by their own resolvers and left as they are, and so are
+// required fields in EmptyValuesNull mode.
+func (g *QueryGenerator) NormalizeEmptyValues(resourceTypes map[schema.GroupVersionKind]*graphql.Object) {
+	if g.resolver.EmptyValues() == resolver.EmptyValuesPreserve {
+		return
+	}
+
+	visited := map[*graphql.Object]bool{}
+	sorted := slices.SortedFunc(maps.Values(resourceTypes), func(a, b *graphql.Object) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	for _, resourceType := range sorted {
+		g.normalizeEmptyValues(resourceType, visited)
+	}
+}
+
+func (g *QueryGenerator) normalizeEmptyValues(t *graphql.Object, visited map[*graphql.Object]bool) {
+	if visited[t] {
+		return
+	}
+	visited[t] = true
+
+	// Adding fields redefines the field map, so changes are collected first.
+	added := graphql.Fields{}
+	fieldMap := t.Fields()
+	for _, name := range slices.Sorted(maps.Keys(fieldMap)) {
+		def := fieldMap[name]
+		if len(def.Args) > 0 {
+			continue
+		}
+		if nested, ok := graphql.GetNamed(def.Type).(*graphql.Object); ok {
+			g.normalizeEmptyValues(nested, visited)
+		}
+
+		_, required := def.Type.(*graphql.NonNull)
+		if required && g.resolver.EmptyValues() == resolver.EmptyValuesNull {
+			continue
+		}
+		if newEmpty := emptyValue(def.Type); newEmpty != nil {
+			added[name] = &graphql.Field{
+				Type:              def.Type,
+				Description:       def.Description,
+				DeprecationReason: def.DeprecationReason,
+				Resolve:           g.resolver.NormalizeEmpty(def.Resolve, newEmpty),
+			}
+		}
+	}
+
+	for name, field := range added {
+		t.AddFieldConfig(name, field)
+	}
+}
+
+// emptyValue returns a constructor of the empty value of a list or map type,
+// or nil for other types.
+func emptyValue(t graphql.Output) func() any {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType
+	}
+	if _, ok := t.(*graphql.List); ok {
+		return func() any { return []any{} }
+	}
+	if t == types.StringMapScalar {
+		return func() any { return map[string]any{} }
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package fields
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EventType is a core/v1 Event as listed by the events field.
+var EventType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ObjectEvent",
+	Description: "An event recorded for an object",
+	Fields: graphql.Fields{
+		"name": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.String),
+			Resolve: nestedString("metadata", "name"),
+		},
+		"type":           &graphql.Field{Type: graphql.String, Description: "Normal or Warning"},
+		"reason":         &graphql.Field{Type: graphql.String},
+		"message":        &graphql.Field{Type: graphql.String},
+		"count":          &graphql.Field{Type: graphql.Int, Description: "How often the event occurred"},
+		"firstTimestamp": &graphql.Field{Type: graphql.String},
+		"lastTimestamp":  &graphql.Field{Type: graphql.String},
+		"eventTime":      &graphql.Field{Type: graphql.String},
+		"reportingComponent": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The component that recorded the event",
+			Resolve:     firstNestedString([]string{"reportingComponent"}, []string{"source", "component"}),
+		},
+	},
+})
+
+// EventListType is a page of the events of an object.
+var EventListType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ObjectEventList",
+	Description: "A page of the events recorded for an object",
+	Fields: graphql.Fields{
+		"items":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(EventType))), Description: "The events of the page, newest first"},
+		"continue": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "The token to read the next page, empty on the last page"},
+	},
+})
+
+// EventsField returns the events field added to all resources when the
+// cluster serves events.
+func (g *QueryGenerator) EventsField(gvk schema.GroupVersionKind) *graphql.Field {
+	return &graphql.Field{
+		Type:        graphql.NewNonNull(EventListType),
+		Description: "The events of the object, read with an additional request",
+		Args: graphql.FieldConfigArgument{
+			resolver.LimitArg: &graphql.ArgumentConfig{
+				Type:         graphql.Int,
+				DefaultValue: resolver.DefaultEventsLimit,
+				Description:  "Maximum number of events to return, capped by the gateway",
+			},
+			resolver.ContinueArg: &graphql.ArgumentConfig{
+				Type:        graphql.String,
+				Description: "The continue token of the previous page",
+			},
+		},
+		Resolve: g.resolver.ObjectEvents(gvk),
+	}
+}
+
+func nestedString(path ...string) graphql.FieldResolveFn {
+	return firstNestedString(path)
+}
+
+// firstNestedString resolves to the first non-empty string found at paths
+// in the source object.
+func firstNestedString(paths ...[]string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		for _, path := range paths {
+			var value any = p.Source
+			for _, key := range path {
+				m, ok := value.(map[string]any)
+				if !ok {
+					value = nil
+					break
+				}
+				value = m[key]
+			}
+			if s, ok := value.(string); ok && s != "" {
+				return s, nil
+			}
+		}
+		return nil, nil
+	}
+}
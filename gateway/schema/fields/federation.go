@@ -0,0 +1,85 @@
+package fields
+
+import (
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Names of the types and queries Apollo Federation adds to a subgraph.
+const (
+	EntityUnionName = "_Entity"
+	AnyScalarName   = "_Any"
+	ServiceTypeName = "_Service"
+	EntitiesQuery   = "_entities"
+	ServiceQuery    = "_service"
+	EntityKeyFields = "metadata { name namespace }"
+)
+
+// AnyScalar is the _Any scalar of the entity representations sent by the
+// federation router.
+var AnyScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        AnyScalarName,
+	Description: "A representation of an entity: its __typename and the fields of its key.",
+	Serialize: func(value any) any {
+		return value
+	},
+	ParseValue: func(value any) any {
+		return value
+	},
+	ParseLiteral: types.JSONScalar.ParseLiteral,
+})
+
+var serviceType = graphql.NewObject(graphql.ObjectConfig{
+	Name: ServiceTypeName,
+	Fields: graphql.Fields{
+		"sdl": &graphql.Field{Type: graphql.String, Description: "The schema of this subgraph with its federation directives"},
+	},
+})
+
+// NewEntityUnion returns the _Entity union of the resource types, resolved by
+// apiVersion and kind.
+func NewEntityUnion(resourceTypes map[schema.GroupVersionKind]*graphql.Object) *graphql.Union {
+	return newResourceUnion(EntityUnionName, "The resource types the federation router can resolve by key", resourceTypes)
+}
+
+// EntitiesField returns the _entities query resolving entity representations
+// to objects.
+func (g *QueryGenerator) EntitiesField(entityUnion *graphql.Union, resourceTypes map[schema.GroupVersionKind]*graphql.Object, kinds map[schema.GroupKind]resolver.ServedKind) *graphql.Field {
+	typeKinds := make(map[string]schema.GroupVersionKind, len(resourceTypes))
+	for gvk, resourceType := range resourceTypes {
+		typeKinds[resourceType.Name()] = gvk
+	}
+
+	return &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(entityUnion)),
+		Description: "Get objects by the representations of the federation router, in the same order. Null for objects that do not exist.",
+		Args: graphql.FieldConfigArgument{
+			resolver.RepresentationsArg: &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(AnyScalar))),
+			},
+		},
+		Resolve: g.resolver.Entities(typeKinds, kinds),
+	}
+}
+
+// ServiceField returns the _service query returning the SDL printSDL renders
+// from the schema, printed once on first use.
+func ServiceField(printSDL func(*graphql.Schema) string) *graphql.Field {
+	var (
+		once sync.Once
+		sdl  string
+	)
+	return &graphql.Field{
+		Type:        graphql.NewNonNull(serviceType),
+		Description: "The schema of this subgraph for the federation router",
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			once.Do(func() { sdl = printSDL(&p.Info.Schema) })
+			return map[string]any{"sdl": sdl}, nil
+		},
+	}
+}
@@ -16,7 +16,7 @@ func NewMutationGenerator(resolver *resolver.Service) *MutationGenerator {
 func (g *MutationGenerator) Generate(rc *ResourceContext, target *graphql.Object) {
 	target.AddFieldConfig("create"+rc.SingularName, &graphql.Field{
 		Type:    rc.ResourceType,
-		Args:    resolver.CreateArgs(rc.Scope, rc.InputType),
+		Args:    resolver.CreateArgs(rc.Scope, rc.CreateInputType),
 		Resolve: g.resolver.CreateItem(rc.GVK, rc.Scope),
 	})
 
@@ -26,9 +26,84 @@ func (g *MutationGenerator) Generate(rc *ResourceContext, target *graphql.Object
 		Resolve: g.resolver.UpdateItem(rc.GVK, rc.Scope),
 	})
 
+	target.AddFieldConfig("upsert"+rc.SingularName, &graphql.Field{
+		Type:        rc.ResourceType,
+		Description: "Creates the object, or merges it into the existing object of the same name",
+		Args:        resolver.UpsertArgs(rc.Scope, rc.InputType),
+		Resolve:     g.resolver.UpsertItem(rc.GVK, rc.Scope),
+	})
+
+	target.AddFieldConfig("patch"+rc.SingularName, &graphql.Field{
+		Type:        rc.ResourceType,
+		Description: "Patches the object with a JSON patch, a JSON merge patch or a strategic merge patch",
+		Args:        resolver.PatchArgs(rc.Scope),
+		Resolve:     g.resolver.PatchItem(rc.GVK, rc.Scope),
+	})
+
+	target.AddFieldConfig("patch"+rc.SingularName+"Metadata", &graphql.Field{
+		Type:        rc.ResourceType,
+		Description: "Adds and removes labels, annotations and finalizers of the object",
+		Args:        resolver.PatchMetadataArgs(rc.Scope),
+		Resolve:     g.resolver.PatchItemMetadata(rc.GVK, rc.Scope),
+	})
+
+	target.AddFieldConfig("apply"+rc.SingularName, &graphql.Field{
+		Type:    rc.ResourceType,
+		Args:    resolver.ApplyArgs(rc.Scope, rc.InputType),
+		Resolve: g.resolver.ApplyItem(rc.GVK, rc.Scope),
+	})
+
+	if statusType := statusInputType(rc); statusType != nil {
+		target.AddFieldConfig("update"+rc.SingularName+"Status", &graphql.Field{
+			Type:    rc.ResourceType,
+			Args:    resolver.UpdateStatusArgs(rc.Scope, statusType),
+			Resolve: g.resolver.UpdateItemStatus(rc.GVK, rc.Scope),
+		})
+	}
+
+	if rc.HasSubresource("scale") {
+		target.AddFieldConfig("scale"+rc.SingularName, &graphql.Field{
+			Type:    ScaleType,
+			Args:    resolver.ScaleArgs(rc.Scope),
+			Resolve: g.resolver.ScaleItem(rc.GVK, rc.Scope),
+		})
+	}
+
+	if rc.HasSubresource("eviction") {
+		target.AddFieldConfig("evict"+rc.SingularName, &graphql.Field{
+			Type:        graphql.Boolean,
+			Description: "Evicts the object, respecting PodDisruptionBudgets",
+			Args:        resolver.EvictArgs(rc.Scope),
+			Resolve:     g.resolver.EvictItem(rc.GVK, rc.Scope),
+		})
+	}
+
 	target.AddFieldConfig("delete"+rc.SingularName, &graphql.Field{
 		Type:    graphql.Boolean,
 		Args:    resolver.DeleteArgs(rc.Scope),
 		Resolve: g.resolver.DeleteItem(rc.GVK, rc.Scope),
 	})
+
+	target.AddFieldConfig("deleteAll"+rc.SingularName, &graphql.Field{
+		Type:    graphql.Boolean,
+		Args:    resolver.DeleteAllOfArgs(rc.Scope),
+		Resolve: g.resolver.DeleteAllOf(rc.GVK, rc.Scope),
+	})
+}
+
+// statusInputType returns the input type of the status field, or nil if the
+// resource has no status subresource or no status field.
+func statusInputType(rc *ResourceContext) graphql.Input {
+	if !rc.HasSubresource("status") {
+		return nil
+	}
+
+	field, ok := rc.InputType.Fields()["status"]
+	if !ok {
+		return nil
+	}
+	if nonNull, ok := field.Type.(*graphql.NonNull); ok {
+		return nonNull.OfType
+	}
+	return field.Type
 }
@@ -0,0 +1,56 @@
+package fields
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// NodeInterfaceName is the name of the Relay Node interface implemented by
+// the resource types.
+const NodeInterfaceName = "Node"
+
+// NewNodeInterface returns the Node interface, resolving objects to their
+// type by apiVersion and kind. resourceTypes may be filled after the call.
+func NewNodeInterface(resourceTypes map[schema.GroupVersionKind]*graphql.Object) *graphql.Interface {
+	return graphql.NewInterface(graphql.InterfaceConfig{
+		Name:        NodeInterfaceName,
+		Description: "An object with a globally unique ID",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+		},
+		ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+			obj, ok := p.Value.(map[string]any)
+			if !ok {
+				return nil
+			}
+			return resourceTypes[(&unstructured.Unstructured{Object: obj}).GroupVersionKind()]
+		},
+	})
+}
+
+// NodeIDField returns the id field of the resource type of gvk.
+func (g *QueryGenerator) NodeIDField(gvk schema.GroupVersionKind) *graphql.Field {
+	return &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.ID),
+		Description: "Globally unique ID of the object, encoding its cluster, group, version, kind, namespace and name. Read the object by ID with the node query.",
+		Resolve:     resolver.NodeIDResolver(gvk),
+	}
+}
+
+// NodeField returns the node query reading an object by global ID.
+func (g *QueryGenerator) NodeField(nodeInterface *graphql.Interface, kinds map[schema.GroupKind]resolver.ServedKind) *graphql.Field {
+	return &graphql.Field{
+		Type:        nodeInterface,
+		Description: "Get an object by the global ID of its id field. Null if the object does not exist.",
+		Args: graphql.FieldConfigArgument{
+			resolver.IDArg: &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.ID),
+				Description: "The global ID of the object",
+			},
+		},
+		Resolve: g.resolver.Node(kinds),
+	}
+}
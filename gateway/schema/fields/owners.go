@@ -0,0 +1,93 @@
+package fields
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ObjectUnionName is the name of the union of all resource types.
+const ObjectUnionName = "KubernetesObject"
+
+// NewObjectUnion returns the union of the resource types, resolving objects
+// to their type by apiVersion and kind.
+func NewObjectUnion(resourceTypes map[schema.GroupVersionKind]*graphql.Object) *graphql.Union {
+	return newResourceUnion(ObjectUnionName, "Any object of a resource served by this schema, resolved by apiVersion and kind", resourceTypes)
+}
+
+// newResourceUnion returns a union of the resource types resolving objects to
+// their type by apiVersion and kind.
+func newResourceUnion(name, description string, resourceTypes map[schema.GroupVersionKind]*graphql.Object) *graphql.Union {
+	members := slices.SortedFunc(maps.Values(resourceTypes), func(a, b *graphql.Object) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return graphql.NewUnion(graphql.UnionConfig{
+		Name:        name,
+		Description: description,
+		Types:       members,
+		ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+			obj, ok := p.Value.(map[string]any)
+			if !ok {
+				return nil
+			}
+			return resourceTypes[(&unstructured.Unstructured{Object: obj}).GroupVersionKind()]
+		},
+	})
+}
+
+// OwnersField returns the owners field resolving an object's ownerReferences
+// to the owning objects.
+func (g *QueryGenerator) OwnersField(objectUnion *graphql.Union, kinds map[schema.GroupKind]resolver.ServedKind) *graphql.Field {
+	return &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(objectUnion))),
+		Description: "The objects listed in metadata.ownerReferences, read with an additional request per owner. Owners of kinds not served by this schema and owners that no longer exist are left out.",
+		Args: graphql.FieldConfigArgument{
+			resolver.ControllerArg: &graphql.ArgumentConfig{
+				Type:         graphql.Boolean,
+				DefaultValue: false,
+				Description:  "Only return the managing controller",
+			},
+		},
+		Resolve: g.resolver.Owners(kinds),
+	}
+}
+
+// OwnedObjectsField returns the ownedObjects field listing the objects of a
+// chosen kind owned by an object.
+func (g *QueryGenerator) OwnedObjectsField(objectUnion *graphql.Union, kinds map[schema.GroupKind]resolver.ServedKind) *graphql.Field {
+	return &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(objectUnion))),
+		Description: "The objects of a kind whose metadata.ownerReferences point at this object, read with an additional list request",
+		Args: graphql.FieldConfigArgument{
+			resolver.KindArg: &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The kind of the owned objects, e.g. Pod",
+			},
+			resolver.GroupArg: &graphql.ArgumentConfig{
+				Type:        graphql.String,
+				Description: "The API group of the kind, required if several groups serve a kind of that name",
+			},
+			resolver.LabelSelectorArg:      resolver.LabelSelectorArgConfig,
+			resolver.LabelSelectorInputArg: resolver.LabelSelectorInputArgConfig,
+		},
+		Resolve: g.resolver.OwnedObjects(kinds),
+	}
+}
+
+// SearchField returns the search query finding objects of the kinds of
+// categories by name or labels.
+func (g *QueryGenerator) SearchField(objectUnion *graphql.Union, categories map[string][]resolver.TypeByCategory, kinds map[schema.GroupKind]resolver.ServedKind) *graphql.Field {
+	return &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(objectUnion))),
+		Description: "Objects of the kinds of categories whose name or labels match term, read with a metadata-only list per kind. Only apiVersion, kind and metadata of the objects are set; read them with node(id) for their other fields",
+		Args:        resolver.SearchArgs(),
+		Resolve:     g.resolver.Search(categories, kinds),
+	}
+}
@@ -1,6 +1,8 @@
 package fields
 
 import (
+	"maps"
+
 	"github.com/graphql-go/graphql"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
 )
@@ -15,19 +17,66 @@ func NewQueryGenerator(resolver *resolver.Service) *QueryGenerator {
 
 func (g *QueryGenerator) Generate(rc *ResourceContext, target *graphql.Object) {
 	listArgs := resolver.ListArgs(rc.Scope)
+	listArgs[resolver.SortByArg] = resolver.SortByArgConfigFor(rc.PrinterColumns)
+	listArgs[resolver.OrderByArg] = resolver.OrderByArgConfig
+	listArgs[resolver.FilterArg] = resolver.FilterArgConfig
+	listArgs[resolver.AtResourceVersionArg] = resolver.AtResourceVersionArgConfig
 	itemArgs := resolver.ItemArgs(rc.Scope)
+	itemArgs[resolver.AtResourceVersionArg] = resolver.AtResourceVersionArgConfig
+
+	listFields := resolver.ListResultFields(rc.ResourceType)
+	if rc.IsNamespaceScoped() {
+		listArgs[resolver.GroupByNamespaceArg] = resolver.GroupByNamespaceArgConfig
+		listArgs[resolver.AllNamespacesArg] = resolver.AllNamespacesArgConfig
+		listFields["allNamespaces"] = &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "True if the items were listed across all namespaces, either with allNamespaces or without namespace. Each item's namespace is in metadata.namespace",
+		}
+
+		namespaceGroupType := graphql.NewObject(graphql.ObjectConfig{
+			Name:   rc.UniqueTypeName + "NamespaceGroup",
+			Fields: resolver.NamespaceGroupFields(rc.ResourceType),
+		})
+		listFields["byNamespace"] = &graphql.Field{
+			Type:        graphql.NewList(graphql.NewNonNull(namespaceGroupType)),
+			Description: "Items grouped by namespace, set if groupByNamespace is true",
+		}
+	}
 
 	listWrapperType := graphql.NewObject(graphql.ObjectConfig{
 		Name:   rc.UniqueTypeName + "List",
-		Fields: resolver.ListResultFields(rc.ResourceType),
+		Fields: listFields,
 	})
 
 	target.AddFieldConfig(rc.PluralName, &graphql.Field{
 		Type:    graphql.NewNonNull(listWrapperType),
 		Args:    listArgs,
-		Resolve: g.resolver.ListItems(rc.GVK, rc.Scope),
+		Resolve: g.resolver.ListItems(rc.GVK, rc.Scope, rc.PrinterColumns),
+	})
+
+	target.AddFieldConfig("count"+rc.PluralName, &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.Int),
+		Description: "The number of objects matching the selectors, counted from a metadata-only list",
+		Args:        resolver.CountArgs(rc.Scope),
+		Resolve:     g.resolver.CountItems(rc.GVK, rc.Scope),
+	})
+
+	target.AddFieldConfig("aggregate"+rc.PluralName, &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(AggregateBucketType))),
+		Description: "The number of objects matching the selectors per value of a metadata field, largest first, counted from a metadata-only list",
+		Args:        resolver.AggregateArgs(rc.Scope),
+		Resolve:     g.resolver.AggregateItems(rc.GVK, rc.Scope),
 	})
 
+	if g.resolver.Tables() != nil {
+		target.AddFieldConfig("table"+rc.PluralName, &graphql.Field{
+			Type:        graphql.NewNonNull(TableType),
+			Description: "The objects matching the selectors as the table kubectl get prints, with the columns of the API server",
+			Args:        resolver.TableArgs(rc.Scope),
+			Resolve:     g.resolver.TableItems(rc.GVK, rc.Scope),
+		})
+	}
+
 	target.AddFieldConfig(rc.SingularName, &graphql.Field{
 		Type:    graphql.NewNonNull(rc.ResourceType),
 		Args:    itemArgs,
@@ -39,4 +88,23 @@ func (g *QueryGenerator) Generate(rc *ResourceContext, target *graphql.Object) {
 		Args:    itemArgs,
 		Resolve: g.resolver.GetItemAsYAML(rc.GVK, rc.Scope),
 	})
+
+	// The YAML has no continue token, so it always holds the whole list.
+	listYamlArgs := maps.Clone(listArgs)
+	delete(listYamlArgs, resolver.GroupByNamespaceArg)
+	delete(listYamlArgs, resolver.LimitArg)
+	delete(listYamlArgs, resolver.ContinueArg)
+	target.AddFieldConfig(rc.PluralName+"Yaml", &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "The objects matching the list arguments as YAML documents separated by ---",
+		Args:        listYamlArgs,
+		Resolve:     g.resolver.ListItemsAsYAML(rc.GVK, rc.Scope, rc.PrinterColumns),
+	})
+
+	target.AddFieldConfig("diff"+rc.SingularName, &graphql.Field{
+		Type:        graphql.NewNonNull(DiffType),
+		Description: "Previews the changes applying the object would make, using server-side apply with dry run",
+		Args:        resolver.DiffArgs(rc.Scope, rc.InputType),
+		Resolve:     g.resolver.DiffItem(rc.GVK, rc.Scope),
+	})
 }
@@ -0,0 +1,120 @@
+package fields
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gobuffalo/flect"
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// refSuffix marks fields holding a reference to another object, e.g. accountRef.
+const refSuffix = "Ref"
+
+// AddRelationshipFields adds a field resolving each reference reachable from
+// the resource types to the referenced object. A field such as accountRef
+// gets a sibling account of the object union if the reference has a name and
+// either names its kind or Account is a kind served by exactly one group.
+// Fields leading to references pass the namespace of the enclosing object
+// down, so references without a namespace resolve next to the object.
+func (g *QueryGenerator) AddRelationshipFields(resourceTypes map[schema.GroupVersionKind]*graphql.Object, objectUnion *graphql.Union, kinds map[schema.GroupKind]resolver.ServedKind) {
+	w := &relationshipWalker{
+		resolver:    g.resolver,
+		objectUnion: objectUnion,
+		kinds:       kinds,
+		hasRefs:     map[*graphql.Object]bool{},
+	}
+
+	sorted := slices.SortedFunc(maps.Values(resourceTypes), func(a, b *graphql.Object) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	for _, resourceType := range sorted {
+		w.walk(resourceType)
+	}
+}
+
+type relationshipWalker struct {
+	resolver    *resolver.Service
+	objectUnion *graphql.Union
+	kinds       map[schema.GroupKind]resolver.ServedKind
+
+	// hasRefs records the visited types and whether references are reachable
+	// from them.
+	hasRefs map[*graphql.Object]bool
+}
+
+// walk adds the relationship fields to t and the types below it and reports
+// whether any reference is reachable from t.
+func (w *relationshipWalker) walk(t *graphql.Object) bool {
+	if found, visited := w.hasRefs[t]; visited {
+		return found
+	}
+	// Cyclic types are not descended into twice.
+	w.hasRefs[t] = false
+
+	// Adding fields redefines the field map, so changes are collected first.
+	added := graphql.Fields{}
+	fieldMap := t.Fields()
+	for _, name := range slices.Sorted(maps.Keys(fieldMap)) {
+		def := fieldMap[name]
+		nested, ok := graphql.GetNamed(def.Type).(*graphql.Object)
+		if !ok || def.Resolve != nil {
+			continue
+		}
+
+		if field := w.relationshipField(name, nested); field != nil {
+			target := strings.TrimSuffix(name, refSuffix)
+			if _, exists := fieldMap[target]; !exists {
+				added[target] = field
+			}
+		}
+
+		if w.walk(nested) {
+			added[name] = &graphql.Field{
+				Type:              def.Type,
+				Description:       def.Description,
+				DeprecationReason: def.DeprecationReason,
+				Resolve:           resolver.InheritNamespace(def.Resolve),
+			}
+		}
+	}
+
+	for name, field := range added {
+		t.AddFieldConfig(name, field)
+	}
+	w.hasRefs[t] = len(added) > 0
+	return len(added) > 0
+}
+
+// relationshipField returns the field resolving the reference held by the
+// field name of type ref, or nil if it is not a resolvable reference.
+func (w *relationshipWalker) relationshipField(name string, ref *graphql.Object) *graphql.Field {
+	if len(name) <= len(refSuffix) || !strings.HasSuffix(name, refSuffix) {
+		return nil
+	}
+	refFields := ref.Fields()
+	if refFields["name"] == nil {
+		return nil
+	}
+
+	defaultKind := flect.Pascalize(strings.TrimSuffix(name, refSuffix))
+	servedBy := 0
+	for gk := range w.kinds {
+		if strings.EqualFold(gk.Kind, defaultKind) {
+			servedBy++
+		}
+	}
+	if refFields["kind"] == nil && servedBy != 1 {
+		return nil
+	}
+
+	return &graphql.Field{
+		Type:        w.objectUnion,
+		Description: "The object referenced by " + name + ", read with an additional request. Null if the reference is empty or the object does not exist.",
+		Resolve:     w.resolver.Relationship(name, defaultKind, w.kinds),
+	}
+}
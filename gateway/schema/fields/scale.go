@@ -0,0 +1,50 @@
+package fields
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ScaleType is the autoscaling/v1 Scale served by the scale subresource.
+var ScaleType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ScaleSubresource",
+	Description: "The scale subresource of a resource",
+	Fields: graphql.Fields{
+		"spec": &graphql.Field{
+			Type: graphql.NewObject(graphql.ObjectConfig{
+				Name: "ScaleSubresourceSpec",
+				Fields: graphql.Fields{
+					"replicas": &graphql.Field{
+						Type:        graphql.Int,
+						Description: "The desired number of replicas",
+					},
+				},
+			}),
+		},
+		"status": &graphql.Field{
+			Type: graphql.NewObject(graphql.ObjectConfig{
+				Name: "ScaleSubresourceStatus",
+				Fields: graphql.Fields{
+					"replicas": &graphql.Field{
+						Type:        graphql.Int,
+						Description: "The observed number of replicas",
+					},
+					"selector": &graphql.Field{
+						Type:        graphql.String,
+						Description: "The label selector of the pods counted as replicas",
+					},
+				},
+			}),
+		},
+	},
+})
+
+// ScaleField returns the scale field added to resources with a scale subresource.
+func (g *QueryGenerator) ScaleField(gvk schema.GroupVersionKind) *graphql.Field {
+	return &graphql.Field{
+		Type:        ScaleType,
+		Description: "The scale subresource, read with an additional request",
+		Resolve:     g.resolver.GetScale(gvk),
+	}
+}
@@ -14,6 +14,7 @@ var WatchEventTypeEnum = graphql.NewEnum(graphql.EnumConfig{
 		resolver.EventTypeAdded:    &graphql.EnumValueConfig{Value: resolver.EventTypeAdded},
 		resolver.EventTypeModified: &graphql.EnumValueConfig{Value: resolver.EventTypeModified},
 		resolver.EventTypeDeleted:  &graphql.EnumValueConfig{Value: resolver.EventTypeDeleted},
+		resolver.EventTypeBookmark: &graphql.EnumValueConfig{Value: resolver.EventTypeBookmark},
 	},
 })
 
@@ -31,6 +32,20 @@ func (g *SubscriptionGenerator) Generate(rc *ResourceContext, target *graphql.Ob
 		Fields: graphql.Fields{
 			"type":   &graphql.Field{Type: graphql.NewNonNull(WatchEventTypeEnum)},
 			"object": &graphql.Field{Type: rc.ResourceType},
+			"suppressed": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "The number of events of the object merged into this one because of coalesceMs",
+			},
+			"resourceVersion": &graphql.Field{
+				Type:        graphql.String,
+				Description: "The resourceVersion to resume the subscription from after this event, null until the initial list was sent",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					if envelope, ok := p.Source.(resolver.SubscriptionEnvelope); ok && envelope.ResourceVersion != "" {
+						return envelope.ResourceVersion, nil
+					}
+					return nil, nil
+				},
+			},
 		},
 	})
 
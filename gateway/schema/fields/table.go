@@ -0,0 +1,40 @@
+package fields
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+)
+
+// TableType is the result of a table query.
+var TableType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Table",
+	Description: "The server-side table kubectl get prints",
+	Fields: graphql.Fields{
+		"columns": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.NewObject(graphql.ObjectConfig{
+				Name:        "TableColumn",
+				Description: "A column of a table, as defined by the API server",
+				Fields: graphql.Fields{
+					"name":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+					"type":        &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "The OpenAPI type of the cells, e.g. string or integer"},
+					"format":      &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "The OpenAPI format of the cells, e.g. date or name"},
+					"description": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+					"priority":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "0 for the columns kubectl get prints by default, higher for those of -o wide"},
+				},
+			})))),
+		},
+		"rows": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.NewObject(graphql.ObjectConfig{
+				Name:        "TableRow",
+				Description: "A row of a table, with a cell per column",
+				Fields: graphql.Fields{
+					"cells":     &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(types.JSONScalar))},
+					"name":      &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "The name of the object of the row"},
+					"namespace": &graphql.Field{Type: graphql.String, Description: "The namespace of the object of the row, empty for cluster-scoped objects"},
+				},
+			})))),
+		},
+		"resourceVersion": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"continue":        &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "The token to read the next page, empty on the last page"},
+	},
+})
@@ -0,0 +1,156 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gobuffalo/flect"
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/fields"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// generatedResource is a resource the generator added fields for.
+type generatedResource struct {
+	rc *fields.ResourceContext
+	// watch is set if subscription fields were generated.
+	watch bool
+}
+
+// addKindAliases adds deprecated fields serving each aliased kind under its
+// former group, version and kind, with the arguments and results of the
+// kind it was renamed to. Aliases of kinds that are not served and of
+// former kinds that still are served are skipped.
+func (g *SchemaGenerator) addKindAliases(ctx context.Context, rootQuery, rootMutation, rootSubscription *graphql.Object) {
+	logger := log.FromContext(ctx)
+
+	for _, alias := range g.resolver.KindAliases() {
+		target, ok := g.generated[alias.To]
+		if !ok {
+			logger.Info("Skipping kind alias, the kind is not served", "from", alias.From, "to", alias.To)
+			continue
+		}
+		if _, served := g.resourceTypes[alias.From]; served {
+			logger.Info("Skipping kind alias, the former kind is still served", "from", alias.From, "to", alias.To)
+			continue
+		}
+
+		rc := *target.rc
+		rc.UniqueTypeName = g.typeRegistry.GetUniqueTypeName(&alias.From)
+		rc.SingularName = alias.From.Kind
+		rc.PluralName = flect.Pluralize(alias.From.Kind)
+		rc.SanitizedGroup = ""
+		if alias.From.Group != "" {
+			rc.SanitizedGroup = types.SanitizeGroupName(alias.From.Group)
+		}
+		reason := fmt.Sprintf("Renamed to %s %s", alias.To.Kind, alias.To.GroupVersion())
+
+		// The fields are generated on scratch types first and then copied
+		// with the deprecation, so they match those of the kind.
+		query := graphql.NewObject(graphql.ObjectConfig{Name: rc.UniqueTypeName + "Alias", Fields: graphql.Fields{}})
+		g.queryGen.Generate(&rc, query)
+		queryVersionType, existing := g.versionType(rootQuery, rc.SanitizedGroup, alias.From.Version, "Query")
+		addDeprecated(queryVersionType, existing, query, reason, nil)
+
+		mutation := graphql.NewObject(graphql.ObjectConfig{Name: rc.UniqueTypeName + "Alias", Fields: graphql.Fields{}})
+		g.mutationGen.Generate(&rc, mutation)
+		mutationVersionType, existing := g.versionType(rootMutation, rc.SanitizedGroup, alias.From.Version, "Mutation")
+		addDeprecated(mutationVersionType, existing, mutation, reason, nil)
+
+		if target.watch {
+			// Subscription fields are named after the version they watch,
+			// which is that of the kind.
+			prefix := ""
+			if rc.SanitizedGroup != "" {
+				prefix = strings.ToLower(rc.SanitizedGroup) + "_"
+			}
+			rename := func(name string) string {
+				return prefix + strings.ToLower(alias.From.Version) + strings.TrimPrefix(name, prefix+strings.ToLower(alias.To.Version))
+			}
+			subscription := graphql.NewObject(graphql.ObjectConfig{Name: rc.UniqueTypeName + "Alias", Fields: graphql.Fields{}})
+			g.subscriptionGen.Generate(&rc, subscription)
+			addDeprecated(rootSubscription, rootSubscription.Fields(), subscription, reason, rename)
+		}
+
+		logger.V(4).Info("Added kind alias", "from", alias.From, "to", alias.To)
+	}
+}
+
+// versionType returns the type of a version field below root, under the
+// field of the group unless it is the core group, adding the fields that do
+// not exist yet. It also returns the fields of the version type if it
+// existed; graphql-go fails types whose fields are read while empty.
+func (g *SchemaGenerator) versionType(root *graphql.Object, group, version, suffix string) (*graphql.Object, graphql.FieldDefinitionMap) {
+	parent := root
+	if group != "" {
+		groupType, existed := childType(parent, group)
+		if !existed {
+			groupType = createGroupType(group, suffix)
+			versionType := createVersionType(group, version, suffix)
+			groupType.AddFieldConfig(version, &graphql.Field{Type: versionType, Resolve: g.resolver.CommonResolver()})
+			root.AddFieldConfig(group, &graphql.Field{Type: groupType, Resolve: g.resolver.CommonResolver()})
+			return versionType, nil
+		}
+		parent = groupType
+	}
+
+	versionType, existed := childType(parent, version)
+	if !existed {
+		versionType = createVersionType(group, version, suffix)
+		parent.AddFieldConfig(version, &graphql.Field{Type: versionType, Resolve: g.resolver.CommonResolver()})
+		return versionType, nil
+	}
+	return versionType, versionType.Fields()
+}
+
+// childType returns the object type of the field name of parent and whether
+// it exists.
+func childType(parent *graphql.Object, name string) (*graphql.Object, bool) {
+	field, ok := parent.Fields()[name]
+	if !ok {
+		return nil, false
+	}
+	child, ok := field.Type.(*graphql.Object)
+	return child, ok
+}
+
+// addDeprecated copies the fields of from to target, renamed by rename if it
+// is set and deprecated with reason. The existing fields of target are kept.
+func addDeprecated(target *graphql.Object, existing graphql.FieldDefinitionMap, from *graphql.Object, reason string, rename func(string) string) {
+	added := graphql.Fields{}
+	for _, name := range slices.Sorted(maps.Keys(from.Fields())) {
+		def := from.Fields()[name]
+		if rename != nil {
+			name = rename(name)
+		}
+		if _, exists := existing[name]; exists {
+			continue
+		}
+
+		args := graphql.FieldConfigArgument{}
+		for _, arg := range def.Args {
+			args[arg.Name()] = &graphql.ArgumentConfig{
+				Type:         arg.Type,
+				DefaultValue: arg.DefaultValue,
+				Description:  arg.Description(),
+			}
+		}
+		added[name] = &graphql.Field{
+			Type:              def.Type,
+			Args:              args,
+			Resolve:           def.Resolve,
+			Subscribe:         def.Subscribe,
+			Description:       def.Description,
+			DeprecationReason: reason,
+		}
+	}
+
+	for name, field := range added {
+		target.AddFieldConfig(name, field)
+	}
+}
@@ -2,12 +2,13 @@ package generator
 
 import (
 	"context"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/gobuffalo/flect"
 	"github.com/graphql-go/graphql"
-	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	openapiext "github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/extensions"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/fields"
@@ -15,6 +16,7 @@ import (
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/kube-openapi/pkg/validation/spec"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -28,6 +30,14 @@ type Resource struct {
 	SingularName   string
 	PluralName     string
 	SanitizedGroup string
+	// DeniedVerbs lists the read verbs the listener found the cluster's
+	// credentials are not allowed to use.
+	DeniedVerbs []string
+	// Subresources lists the subresources the API server serves for the
+	// resource, e.g. "status".
+	Subresources []string
+	// PrinterColumns are the additional printer columns of a custom resource.
+	PrinterColumns []apiextensionsv1.CustomResourceColumnDefinition
 }
 
 // SchemaGenerator transforms Kubernetes OpenAPI definitions into a GraphQL schema.
@@ -45,27 +55,63 @@ type SchemaGenerator struct {
 	categoryManager *extensions.CategoryManager
 	customQueryGen  *extensions.CustomQueryGenerator
 	customSubGen    *extensions.CustomSubscriptionGenerator
+
+	// hasEvents is set if the cluster serves core/v1 events.
+	hasEvents bool
+
+	// resourceTypes and servedKinds collect the generated resource types to
+	// resolve owner references in both directions and object references.
+	resourceTypes map[schema.GroupVersionKind]*graphql.Object
+	servedKinds   map[schema.GroupKind]resolver.ServedKind
+
+	// statusKinds holds the generated kinds with a status subresource.
+	statusKinds map[schema.GroupVersionKind]bool
+
+	// generated holds the resource contexts of the generated resources to
+	// add kind aliases for them.
+	generated map[schema.GroupVersionKind]generatedResource
+
+	// nodeInterface is implemented by the resource types without an id field
+	// of their own.
+	nodeInterface *graphql.Interface
+
+	// printSubgraphSDL renders the SDL returned by the _service query of
+	// federation subgraphs.
+	printSubgraphSDL func(*graphql.Schema) string
 }
 
 // New creates a new schema generator.
 func New(definitions map[string]*spec.Schema, resolverProvider *resolver.Service, customSubGen *extensions.CustomSubscriptionGenerator) *SchemaGenerator {
 	registry := types.NewRegistry()
 	categoryManager := extensions.NewCategoryManager(definitions)
+	resourceTypes := map[schema.GroupVersionKind]*graphql.Object{}
 
 	return &SchemaGenerator{
 		definitions:     definitions,
 		resolver:        resolverProvider,
 		typeRegistry:    registry,
-		typeConverter:   types.NewConverter(registry),
+		typeConverter:   types.NewConverter(registry).WithFieldNames(resolverProvider.FieldNames()),
 		queryGen:        fields.NewQueryGenerator(resolverProvider),
 		mutationGen:     fields.NewMutationGenerator(resolverProvider),
 		subscriptionGen: fields.NewSubscriptionGenerator(resolverProvider),
 		categoryManager: categoryManager,
 		customQueryGen:  extensions.NewCustomQueryGenerator(resolverProvider, categoryManager),
 		customSubGen:    customSubGen,
+		resourceTypes:   resourceTypes,
+		servedKinds:     map[schema.GroupKind]resolver.ServedKind{},
+		statusKinds:     map[schema.GroupVersionKind]bool{},
+		generated:       map[schema.GroupVersionKind]generatedResource{},
+		nodeInterface:   fields.NewNodeInterface(resourceTypes),
 	}
 }
 
+// WithSubgraphSDL sets the printer of the SDL returned by the _service query
+// when the resolver enables federation.
+func (g *SchemaGenerator) WithSubgraphSDL(printSDL func(*graphql.Schema) string) *SchemaGenerator {
+	g.printSubgraphSDL = printSDL
+	return g
+}
+
 // Generate constructs the complete GraphQL schema.
 func (g *SchemaGenerator) Generate(ctx context.Context) (*graphql.Schema, error) {
 	logger := log.FromContext(ctx)
@@ -75,6 +121,7 @@ func (g *SchemaGenerator) Generate(ctx context.Context) (*graphql.Schema, error)
 	rootSubscription := graphql.NewObject(graphql.ObjectConfig{Name: "Subscription", Fields: graphql.Fields{}})
 
 	resources := g.parseResources()
+	g.hasEvents = slices.ContainsFunc(resources, func(r *Resource) bool { return r.GVK == resolver.EventGVK })
 	groups := groupByAPIGroup(resources)
 
 	sortedGroups := make([]string, 0, len(groups))
@@ -86,18 +133,44 @@ func (g *SchemaGenerator) Generate(ctx context.Context) (*graphql.Schema, error)
 	for _, group := range sortedGroups {
 		g.processGroup(ctx, group, groups[group], rootQuery, rootMutation, rootSubscription)
 	}
+	g.addKindAliases(ctx, rootQuery, rootMutation, rootSubscription)
+	g.resolver.WithStatusSubresources(g.statusKinds)
+	g.addObjectFields(rootQuery)
+	if len(g.resourceTypes) > 0 {
+		rootQuery.AddFieldConfig("node", g.queryGen.NodeField(g.nodeInterface, g.servedKinds))
+	}
 
 	g.customQueryGen.AddTypeByCategoryQuery(rootQuery)
+	g.customQueryGen.AddTypeByCategorySubscription(rootSubscription)
 	g.addApplyYamlMutation(rootMutation)
+	g.addApplyManifestsMutation(rootMutation)
+	g.addRawResourceQuery(rootQuery)
+	if g.resolver.ChangeFeed() != nil {
+		g.addRecentChangesQuery(rootQuery)
+	}
+	if g.resolver.SavedQueries() != nil {
+		g.addSavedQueries(rootQuery, rootMutation)
+	}
+	if g.resolver.ClusterStatus() != nil {
+		g.addClusterStatusQuery(rootQuery)
+	}
+	if g.resolver.ClusterRegistration() != nil {
+		g.addClusterRegistration(rootMutation)
+	}
+	if g.resolver.Federation() {
+		g.addFederationQueries(rootQuery)
+	}
 
 	if g.customSubGen != nil {
 		g.customSubGen.AddPodLogsSubscription(rootSubscription, g.definitions)
+		g.customSubGen.AddPodLogsQuery(rootQuery, g.definitions)
 	}
 
 	schema, err := graphql.NewSchema(graphql.SchemaConfig{
 		Query:        rootQuery,
 		Mutation:     rootMutation,
 		Subscription: rootSubscription,
+		Directives:   types.Directives,
 	})
 	if err != nil {
 		logger.Error(err, "Error creating GraphQL schema")
@@ -107,17 +180,22 @@ func (g *SchemaGenerator) Generate(ctx context.Context) (*graphql.Schema, error)
 	return &schema, nil
 }
 
+// Categories returns the types of each category of the generated schema.
+func (g *SchemaGenerator) Categories() map[string][]resolver.TypeByCategory {
+	return g.categoryManager.AllCategories()
+}
+
 // parseResources extracts and validates all resources from definitions.
 func (g *SchemaGenerator) parseResources() []*Resource {
 	var resources []*Resource
 
 	for key, def := range g.definitions {
-		gvk, err := apischema.ExtractGVK(def)
+		gvk, err := openapiext.GVK(def)
 		if err != nil || gvk == nil || gvk.Kind == "" {
 			continue
 		}
 
-		scope, err := apischema.ExtractScope(def)
+		scope, err := openapiext.Scope(def)
 		if err != nil {
 			continue
 		}
@@ -126,6 +204,12 @@ func (g *SchemaGenerator) parseResources() []*Resource {
 			continue
 		}
 
+		// Skip resources that every query would be forbidden on.
+		deniedVerbs := openapiext.DeniedVerbs(def)
+		if slices.Contains(deniedVerbs, "get") && slices.Contains(deniedVerbs, "list") {
+			continue
+		}
+
 		sanitizedGroup := ""
 		if gvk.Group != "" {
 			sanitizedGroup = types.SanitizeGroupName(gvk.Group)
@@ -139,6 +223,9 @@ func (g *SchemaGenerator) parseResources() []*Resource {
 			SingularName:   gvk.Kind,
 			PluralName:     flect.Pluralize(gvk.Kind),
 			SanitizedGroup: sanitizedGroup,
+			DeniedVerbs:    deniedVerbs,
+			Subresources:   openapiext.Subresources(def),
+			PrinterColumns: openapiext.PrinterColumns(def),
 		})
 	}
 
@@ -266,37 +353,290 @@ func (g *SchemaGenerator) processResource(
 		return
 	}
 
+	// Resources may define their own scale field; the subresource is
+	// still available through the scale mutation then.
+	if _, exists := gqlFields["scale"]; !exists && slices.Contains(r.Subresources, "scale") {
+		gqlFields["scale"] = g.queryGen.ScaleField(r.GVK)
+	}
+	if _, exists := gqlFields[resolver.PrunedFieldsFieldName]; !exists {
+		gqlFields[resolver.PrunedFieldsFieldName] = &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The fields the API server dropped from the object submitted to a create, update, upsert or apply mutation, e.g. unknown fields of a custom resource; empty for queried objects",
+			Resolve:     resolver.PrunedFields,
+		}
+	}
+	if _, exists := gqlFields["events"]; !exists && g.hasEvents {
+		gqlFields["events"] = g.queryGen.EventsField(r.GVK)
+	}
+
+	var interfaces []*graphql.Interface
+	if _, exists := gqlFields["id"]; !exists {
+		gqlFields["id"] = g.queryGen.NodeIDField(r.GVK)
+		interfaces = append(interfaces, g.nodeInterface)
+	}
+
 	resourceType := graphql.NewObject(graphql.ObjectConfig{
-		Name:   uniqueTypeName,
-		Fields: gqlFields,
+		Name:       uniqueTypeName,
+		Fields:     gqlFields,
+		Interfaces: interfaces,
 	})
 
 	inputType := graphql.NewInputObject(graphql.InputObjectConfig{
-		Name:   uniqueTypeName + "_Input",
+		Name:   types.InputTypeName(uniqueTypeName),
 		Fields: inputFields,
 	})
 
+	g.resourceTypes[r.GVK] = resourceType
+	if slices.Contains(r.Subresources, "status") {
+		g.statusKinds[r.GVK] = true
+	}
+	// Owner references are resolved at the kind's preferred version.
+	if served, ok := g.servedKinds[r.GVK.GroupKind()]; !ok || version.CompareKubeAwareVersionStrings(r.GVK.Version, served.Version) > 0 {
+		g.servedKinds[r.GVK.GroupKind()] = resolver.ServedKind{Version: r.GVK.Version, Scope: r.Scope}
+	}
+
 	rc := &fields.ResourceContext{
-		GVK:            r.GVK,
-		Scope:          r.Scope,
-		UniqueTypeName: uniqueTypeName,
-		ResourceType:   resourceType,
-		InputType:      inputType,
-		SingularName:   r.SingularName,
-		PluralName:     r.PluralName,
-		SanitizedGroup: r.SanitizedGroup,
+		GVK:             r.GVK,
+		Scope:           r.Scope,
+		UniqueTypeName:  uniqueTypeName,
+		ResourceType:    resourceType,
+		InputType:       inputType,
+		CreateInputType: g.typeConverter.CreateInputType(inputType),
+		SingularName:    r.SingularName,
+		PluralName:      r.PluralName,
+		SanitizedGroup:  r.SanitizedGroup,
+		Subresources:    r.Subresources,
+		PrinterColumns:  r.PrinterColumns,
 	}
 
+	watch := !slices.Contains(r.DeniedVerbs, "watch")
 	g.queryGen.Generate(rc, queryVersionType)
 	g.mutationGen.Generate(rc, mutationVersionType)
-	g.subscriptionGen.Generate(rc, rootSubscription)
+	if watch {
+		g.subscriptionGen.Generate(rc, rootSubscription)
+	}
+	g.generated[r.GVK] = generatedResource{rc: rc, watch: watch}
+}
+
+// addObjectFields adds the fields returning objects of the union of all
+// resource types: the search query, owners and ownedObjects on every
+// resource type and a relationship field next to every resolvable reference.
+// Empty values are normalized last, so the fields wrapped for relationships
+// are covered.
+func (g *SchemaGenerator) addObjectFields(rootQuery *graphql.Object) {
+	if len(g.resourceTypes) == 0 {
+		return
+	}
+
+	objectUnion := fields.NewObjectUnion(g.resourceTypes)
+	rootQuery.AddFieldConfig("search", g.queryGen.SearchField(objectUnion, g.categoryManager.AllCategories(), g.servedKinds))
+	for _, resourceType := range g.resourceTypes {
+		existing := resourceType.Fields()
+		if _, exists := existing["owners"]; !exists {
+			resourceType.AddFieldConfig("owners", g.queryGen.OwnersField(objectUnion, g.servedKinds))
+		}
+		if _, exists := existing["ownedObjects"]; !exists {
+			resourceType.AddFieldConfig("ownedObjects", g.queryGen.OwnedObjectsField(objectUnion, g.servedKinds))
+		}
+	}
+	g.queryGen.AddRelationshipFields(g.resourceTypes, objectUnion, g.servedKinds)
+	g.queryGen.NormalizeEmptyValues(g.resourceTypes)
 }
 
 func (g *SchemaGenerator) addApplyYamlMutation(rootMutation *graphql.Object) {
 	rootMutation.AddFieldConfig("applyYaml", &graphql.Field{
-		Type:    types.JSONStringScalar,
-		Args:    resolver.ApplyYamlArgs(),
-		Resolve: g.resolver.ApplyYaml(),
+		Type:              types.JSONStringScalar,
+		Args:              resolver.ApplyYamlArgs(),
+		Resolve:           g.resolver.ApplyYaml(),
+		DeprecationReason: "Use applyManifests, which applies one or more documents after authorizing all of them and returns a result per document",
+	})
+}
+
+// addApplyManifestsMutation adds the applyManifests mutation applying
+// multi-document YAML.
+func (g *SchemaGenerator) addApplyManifestsMutation(rootMutation *graphql.Object) {
+	resultType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ManifestResult",
+		Description: "The outcome of applying a document of a YAML manifest",
+		Fields: graphql.Fields{
+			"index":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "Position of the document in the manifest, starting at 0"},
+			"apiVersion": &graphql.Field{Type: graphql.String},
+			"kind":       &graphql.Field{Type: graphql.String},
+			"namespace":  &graphql.Field{Type: graphql.String},
+			"name":       &graphql.Field{Type: graphql.String, Description: "Name of the object, generated by the API server for documents with metadata.generateName"},
+			"operation":  &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "CREATED, UPDATED, UNCHANGED, FAILED, or SKIPPED if another document failed validation or authorization"},
+			"object":     &graphql.Field{Type: types.JSONScalar, Description: "The applied object"},
+			"error":      &graphql.Field{Type: graphql.String, Description: "Why the document failed"},
+			"code":       &graphql.Field{Type: graphql.String, Description: "Error code of a Kubernetes API error, e.g. FORBIDDEN"},
+		},
+	})
+
+	rootMutation.AddFieldConfig("applyManifests", &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(resultType))),
+		Description: "Creates or updates the objects of one or more YAML documents, after checking that all of them are valid and allowed",
+		Args:        resolver.ApplyManifestsArgs(),
+		Resolve:     g.resolver.ApplyManifests(),
+	})
+}
+
+// addRawResourceQuery adds the rawResource query reading objects of kinds
+// without generated types.
+func (g *SchemaGenerator) addRawResourceQuery(rootQuery *graphql.Object) {
+	rootQuery.AddFieldConfig("rawResource", &graphql.Field{
+		Type:        types.JSONScalar,
+		Description: "Get any object by apiVersion and kind as JSON, including kinds this schema has no types for yet, e.g. custom resources installed since the schema was generated",
+		Args:        resolver.RawResourceArgs(),
+		Resolve:     g.resolver.RawResource(),
+	})
+}
+
+// addFederationQueries adds the _service query and, if there are resource
+// types with metadata, the _entities query of an Apollo Federation subgraph.
+func (g *SchemaGenerator) addFederationQueries(rootQuery *graphql.Object) {
+	if g.printSubgraphSDL != nil {
+		rootQuery.AddFieldConfig(fields.ServiceQuery, fields.ServiceField(g.printSubgraphSDL))
+	}
+
+	entityTypes := map[schema.GroupVersionKind]*graphql.Object{}
+	for gvk, resourceType := range g.resourceTypes {
+		if _, ok := resourceType.Fields()["metadata"]; ok {
+			entityTypes[gvk] = resourceType
+		}
+	}
+	if len(entityTypes) == 0 {
+		return
+	}
+	entityUnion := fields.NewEntityUnion(entityTypes)
+	rootQuery.AddFieldConfig(fields.EntitiesQuery, g.queryGen.EntitiesField(entityUnion, entityTypes, g.servedKinds))
+}
+
+// addRecentChangesQuery adds the recentChanges query listing mutations
+// recently executed through this gateway instance.
+func (g *SchemaGenerator) addRecentChangesQuery(rootQuery *graphql.Object) {
+	changeType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "RecentChange",
+		Description: "A mutation executed through this gateway instance",
+		Fields: graphql.Fields{
+			"time":            &graphql.Field{Type: graphql.NewNonNull(types.TimeScalar)},
+			"cluster":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"operation":       &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "CREATE, UPDATE or DELETE"},
+			"group":           &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"version":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"kind":            &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"namespace":       &graphql.Field{Type: graphql.String},
+			"name":            &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"resourceVersion": &graphql.Field{Type: graphql.String, Description: "Resource version after the change"},
+		},
+	})
+
+	rootQuery.AddFieldConfig("recentChanges", &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(changeType))),
+		Description: "Mutations recently executed through this gateway instance, newest first",
+		Args:        resolver.RecentChangesArgs(),
+		Resolve:     g.resolver.RecentChanges(),
+	})
+}
+
+// addClusterStatusQuery adds the clusterStatus query reporting whether the
+// gateway can reach a cluster with valid credentials and serves a current
+// schema for it.
+func (g *SchemaGenerator) addClusterStatusQuery(rootQuery *graphql.Object) {
+	statusType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ClusterStatus",
+		Description: "The state of a cluster served by the gateway",
+		Fields: graphql.Fields{
+			"name":               &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"served":             &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Description: "Whether the gateway serves a schema for the cluster"},
+			"reachable":          &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Description: "Whether the API server responded to the gateway's last call"},
+			"credentialsValid":   &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Description: "Whether the API server accepted the gateway's credentials for the cluster in its last response"},
+			"lastSuccessfulCall": &graphql.Field{Type: types.TimeScalar, Description: "When a call with the gateway's credentials last succeeded"},
+			"serverVersion":      &graphql.Field{Type: graphql.String, Description: "Version of the API server"},
+			"schemaUpdatedAt":    &graphql.Field{Type: types.TimeScalar, Description: "When the served schema was loaded"},
+			"schemaError":        &graphql.Field{Type: graphql.String, Description: "Why the latest schema failed to load; a previous one may still be served"},
+			"error":              &graphql.Field{Type: graphql.String, Description: "Error of the gateway's last call to the API server"},
+		},
+	})
+
+	rootQuery.AddFieldConfig("clusterStatus", &graphql.Field{
+		Type:        statusType,
+		Description: "Reachability, credential validity and schema freshness of a cluster served by this gateway instance, null if unknown",
+		Args: graphql.FieldConfigArgument{
+			resolver.NameArg: &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String), Description: "Name of the cluster"},
+		},
+		Resolve: g.resolver.GetClusterStatus(),
+	})
+}
+
+// addClusterRegistration adds the registerCluster and unregisterCluster
+// mutations serving clusters without a schema from the listener.
+func (g *SchemaGenerator) addClusterRegistration(rootMutation *graphql.Object) {
+	registeredType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "RegisteredCluster",
+		Description: "A cluster registered with this gateway instance",
+		Fields: graphql.Fields{
+			"name":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"expiresAt": &graphql.Field{Type: graphql.NewNonNull(types.TimeScalar), Description: "When the cluster is removed unless registered again"},
+		},
+	})
+
+	rootMutation.AddFieldConfig("registerCluster", &graphql.Field{
+		Type:        graphql.NewNonNull(registeredType),
+		Description: "Generates the schema of a cluster and serves it on this gateway instance for a limited time",
+		Args:        resolver.RegisterClusterArgs(),
+		Resolve:     g.resolver.RegisterCluster(),
+	})
+	rootMutation.AddFieldConfig("unregisterCluster", &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.Boolean),
+		Description: "Stops serving a registered cluster; false if it was not registered",
+		Args: graphql.FieldConfigArgument{
+			resolver.NameArg: &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String), Description: "Name of the cluster"},
+		},
+		Resolve: g.resolver.UnregisterCluster(),
+	})
+}
+
+// addSavedQueries adds the savedQueries query and the saveQuery and
+// deleteSavedQuery mutations managing the named queries of the user.
+func (g *SchemaGenerator) addSavedQueries(rootQuery, rootMutation *graphql.Object) {
+	savedQueryType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "SavedQuery",
+		Description: "A named GraphQL operation saved by a user",
+		Fields: graphql.Fields{
+			"cluster":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"owner":       &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "User who saved the query"},
+			"name":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"description": &graphql.Field{Type: graphql.String},
+			"document":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"shared":      &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Description: "Whether other users see the query"},
+			"updatedAt":   &graphql.Field{Type: graphql.NewNonNull(types.TimeScalar)},
+		},
+	})
+
+	rootQuery.AddFieldConfig("savedQueries", &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(savedQueryType))),
+		Description: "Queries you saved for this cluster and those shared by others, ordered by owner and name",
+		Args: graphql.FieldConfigArgument{
+			resolver.IncludeSharedArg: &graphql.ArgumentConfig{
+				Type:         graphql.Boolean,
+				DefaultValue: true,
+				Description:  "Also list the queries shared by other users",
+			},
+		},
+		Resolve: g.resolver.ListSavedQueries(),
+	})
+	rootMutation.AddFieldConfig("saveQuery", &graphql.Field{
+		Type:        graphql.NewNonNull(savedQueryType),
+		Description: "Saves a named query for this cluster under your user name",
+		Args:        resolver.SavedQueryArgs(),
+		Resolve:     g.resolver.SaveQuery(),
+	})
+	rootMutation.AddFieldConfig("deleteSavedQuery", &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.Boolean),
+		Description: "Deletes one of your saved queries",
+		Args: graphql.FieldConfigArgument{
+			resolver.NameArg: &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+		},
+		Resolve: g.resolver.DeleteSavedQuery(),
 	})
 }
 
@@ -1,15 +1,26 @@
 package generator
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"testing"
 
-	"github.com/platform-mesh/kubernetes-graphql-gateway/apis"
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/resolver"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 func TestGroupByAPIGroup(t *testing.T) {
@@ -155,6 +166,7 @@ type expectedResource struct {
 	singularName   string
 	pluralName     string
 	sanitizedGroup string
+	deniedVerbs    []string
 }
 
 func TestParseResources(t *testing.T) {
@@ -189,6 +201,28 @@ func TestParseResources(t *testing.T) {
 			},
 			want: nil,
 		},
+		{
+			name: "resource denied get and list is skipped",
+			definitions: map[string]*spec.Schema{
+				"io.k8s.api.core.v1.Secret": withDeniedVerbs(schemaWithGVKAndScope("", "v1", "Secret", apiextensionsv1.NamespaceScoped), "get", "list", "watch"),
+			},
+			want: nil,
+		},
+		{
+			name: "resource denied watch only is kept",
+			definitions: map[string]*spec.Schema{
+				"io.k8s.api.core.v1.Secret": withDeniedVerbs(schemaWithGVKAndScope("", "v1", "Secret", apiextensionsv1.NamespaceScoped), "watch"),
+			},
+			want: []expectedResource{{
+				key:            "io.k8s.api.core.v1.Secret",
+				gvk:            schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Secret"},
+				scope:          apiextensionsv1.NamespaceScoped,
+				singularName:   "Secret",
+				pluralName:     "Secrets",
+				sanitizedGroup: "",
+				deniedVerbs:    []string{"watch"},
+			}},
+		},
 		{
 			name: "core API resource (empty group)",
 			definitions: map[string]*spec.Schema{
@@ -274,6 +308,7 @@ func TestParseResources(t *testing.T) {
 				assert.Equal(t, want.singularName, got[i].SingularName)
 				assert.Equal(t, want.pluralName, got[i].PluralName)
 				assert.Equal(t, want.sanitizedGroup, got[i].SanitizedGroup)
+				assert.Equal(t, want.deniedVerbs, got[i].DeniedVerbs)
 			}
 		})
 	}
@@ -284,7 +319,7 @@ func schemaWithGVK(group, version, kind string) *spec.Schema {
 	return &spec.Schema{
 		VendorExtensible: spec.VendorExtensible{
 			Extensions: spec.Extensions{
-				apis.GVKExtensionKey: []any{
+				extensions.GVKKey: []any{
 					map[string]any{"group": group, "version": version, "kind": kind},
 				},
 			},
@@ -292,14 +327,783 @@ func schemaWithGVK(group, version, kind string) *spec.Schema {
 	}
 }
 
+// withDeniedVerbs records verbs as denied on the schema, as the listener's
+// access enricher does.
+func withDeniedVerbs(s *spec.Schema, verbs ...string) *spec.Schema {
+	verbList := make([]any, 0, len(verbs))
+	for _, verb := range verbs {
+		verbList = append(verbList, verb)
+	}
+	s.Extensions[extensions.DeniedVerbsKey] = verbList
+	return s
+}
+
 // schemaWithGVKAndScope creates a schema with both GVK and scope extensions.
 func schemaWithGVKAndScope(group, version, kind string, scope apiextensionsv1.ResourceScope) *spec.Schema {
 	return &spec.Schema{
 		VendorExtensible: spec.VendorExtensible{
 			Extensions: spec.Extensions{
-				apis.GVKExtensionKey:   []any{map[string]any{"group": group, "version": version, "kind": kind}},
-				apis.ScopeExtensionKey: string(scope),
+				extensions.GVKKey:   []any{map[string]any{"group": group, "version": version, "kind": kind}},
+				extensions.ScopeKey: string(scope),
+			},
+		},
+	}
+}
+
+// TestGenerate_DiscoveryStub verifies that a discovery-only stub, as written
+// by the listener for aggregated APIs without OpenAPI details, yields a
+// queryable type with spec and status as JSON.
+func TestGenerate_DiscoveryStub(t *testing.T) {
+	stub := `{
+		"type": "object",
+		"properties": {
+			"apiVersion": {"type": "string"},
+			"kind": {"type": "string"},
+			"metadata": {"type": "object", "x-kubernetes-preserve-unknown-fields": true},
+			"spec": {"type": "object", "x-kubernetes-preserve-unknown-fields": true},
+			"status": {"type": "object", "x-kubernetes-preserve-unknown-fields": true}
+		},
+		"x-kubernetes-group-version-kind": [{"group": "project.openshift.io", "version": "v1", "kind": "Project"}],
+		"x-kubernetes-scope": "Cluster"
+	}`
+	var def spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(stub), &def))
+
+	g := New(map[string]*spec.Schema{"project.openshift.io.v1.Project": &def}, resolver.New(nil), nil)
+	gqlSchema, err := g.Generate(t.Context())
+	require.NoError(t, err)
+
+	project, ok := gqlSchema.Type("ProjectOpenshiftIoV1Project").(*graphql.Object)
+	require.True(t, ok, "Project type should be generated")
+	assert.Equal(t, "JSON", project.Fields()["spec"].Type.Name())
+	assert.Equal(t, "JSON", project.Fields()["status"].Type.Name())
+
+	result := graphql.Do(graphql.Params{
+		Schema:        *gqlSchema,
+		RequestString: `{ __type(name: "ProjectOpenshiftIoV1Query") { fields { name } } }`,
+		Context:       t.Context(),
+	})
+	require.Empty(t, result.Errors)
+	assert.Contains(t, fmt.Sprint(result.Data), "Project")
+}
+
+// TestGenerate_ApplyYamlDeprecated verifies that applyYaml is deprecated in
+// favor of applyManifests.
+func TestGenerate_ApplyYamlDeprecated(t *testing.T) {
+	var def spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {"metadata": {"type": "object", "properties": {"name": {"type": "string"}}}},
+		"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Widget"}],
+		"x-kubernetes-scope": "Namespaced"
+	}`), &def))
+	gqlSchema, err := New(map[string]*spec.Schema{"com.example.v1.Widget": &def}, resolver.New(nil), nil).Generate(t.Context())
+	require.NoError(t, err)
+
+	fields := gqlSchema.MutationType().Fields()
+	require.Contains(t, fields, "applyYaml")
+	assert.Contains(t, fields["applyYaml"].DeprecationReason, "applyManifests")
+	require.Contains(t, fields, "applyManifests")
+	assert.Empty(t, fields["applyManifests"].DeprecationReason)
+}
+
+// TestGenerate_StatusMutation verifies that update{Kind}Status is generated
+// only for resources the listener found a status subresource for.
+func TestGenerate_StatusMutation(t *testing.T) {
+	definition := func(kind string, subresources ...string) *spec.Schema {
+		def := `{
+			"type": "object",
+			"properties": {
+				"spec": {"type": "object", "properties": {"replicas": {"type": "integer"}}},
+				"status": {"type": "object", "properties": {"phase": {"type": "string"}}}
+			},
+			"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "` + kind + `"}],
+			"x-kubernetes-scope": "Namespaced"
+		}`
+		var s spec.Schema
+		require.NoError(t, json.Unmarshal([]byte(def), &s))
+		if len(subresources) > 0 {
+			s.AddExtension(extensions.SubresourcesKey, subresources)
+		}
+		return &s
+	}
+
+	g := New(map[string]*spec.Schema{
+		"com.example.v1.Widget": definition("Widget", "status"),
+		"com.example.v1.Gadget": definition("Gadget"),
+	}, resolver.New(nil), nil)
+	gqlSchema, err := g.Generate(t.Context())
+	require.NoError(t, err)
+
+	mutation, ok := gqlSchema.Type("ExampleComV1Mutation").(*graphql.Object)
+	require.True(t, ok)
+
+	field, ok := mutation.Fields()["updateWidgetStatus"]
+	require.True(t, ok, "updateWidgetStatus should be generated")
+	var statusArg *graphql.Argument
+	for _, arg := range field.Args {
+		if arg.Name() == resolver.StatusArg {
+			statusArg = arg
+		}
+	}
+	require.NotNil(t, statusArg)
+	statusType, ok := statusArg.Type.(*graphql.NonNull).OfType.(*graphql.InputObject)
+	require.True(t, ok)
+	assert.Contains(t, statusType.Fields(), "phase")
+
+	assert.NotContains(t, mutation.Fields(), "updateGadgetStatus")
+}
+
+// TestGenerate_PrunedStatus verifies that the status submitted to a create
+// mutation of a kind with a status subresource, which the API server drops,
+// is not reported as pruned.
+func TestGenerate_PrunedStatus(t *testing.T) {
+	var def spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"metadata": {"type": "object", "properties": {"name": {"type": "string"}, "namespace": {"type": "string"}}},
+			"spec": {"type": "object", "properties": {"image": {"type": "string"}}},
+			"status": {"type": "object", "properties": {"phase": {"type": "string"}}}
+		},
+		"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Widget"}],
+		"x-kubernetes-scope": "Namespaced"
+	}`), &def))
+	def.AddExtension(extensions.SubresourcesKey, []string{"status"})
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}, meta.RESTScopeNamespace)
+	cl := fake.NewClientBuilder().WithRESTMapper(mapper).WithInterceptorFuncs(interceptor.Funcs{
+		// The API server drops the status of kinds with a status subresource
+		// on create, unlike the fake client.
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			unstructured.RemoveNestedField(obj.(*unstructured.Unstructured).Object, "status")
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+
+	gqlSchema, err := New(map[string]*spec.Schema{"com.example.v1.Widget": &def}, resolver.New(cl), nil).Generate(t.Context())
+	require.NoError(t, err)
+
+	result := graphql.Do(graphql.Params{
+		Schema:  *gqlSchema,
+		Context: t.Context(),
+		RequestString: `mutation { example_com { v1 { createWidget(namespace: "default", object: {
+			metadata: { name: "w1" }
+			spec: { image: "nginx" }
+			status: { phase: "Running" }
+		}) { status { phase } prunedFields } } } }`,
+	})
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string]any{"example_com": map[string]any{"v1": map[string]any{"createWidget": map[string]any{
+		"status":       nil,
+		"prunedFields": []any{},
+	}}}}, result.Data)
+}
+
+// TestGenerate_ScaleSubresource verifies that resources with a scale
+// subresource get a scale field and a scale{Kind} mutation.
+func TestGenerate_ScaleSubresource(t *testing.T) {
+	definition := func(kind string, properties string, subresources ...string) *spec.Schema {
+		def := `{
+			"type": "object",
+			"properties": ` + properties + `,
+			"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "` + kind + `"}],
+			"x-kubernetes-scope": "Namespaced"
+		}`
+		var s spec.Schema
+		require.NoError(t, json.Unmarshal([]byte(def), &s))
+		if len(subresources) > 0 {
+			s.AddExtension(extensions.SubresourcesKey, subresources)
+		}
+		return &s
+	}
+
+	g := New(map[string]*spec.Schema{
+		"com.example.v1.Widget": definition("Widget", `{"spec": {"type": "object", "properties": {"replicas": {"type": "integer"}}}}`, "scale"),
+		"com.example.v1.Gauge":  definition("Gauge", `{"scale": {"type": "string"}}`, "scale"),
+		"com.example.v1.Gadget": definition("Gadget", `{"spec": {"type": "object", "properties": {"size": {"type": "string"}}}}`),
+	}, resolver.New(nil), nil)
+	gqlSchema, err := g.Generate(t.Context())
+	require.NoError(t, err)
+
+	mutation, ok := gqlSchema.Type("ExampleComV1Mutation").(*graphql.Object)
+	require.True(t, ok)
+	assert.Contains(t, mutation.Fields(), "scaleWidget")
+	assert.Contains(t, mutation.Fields(), "scaleGauge")
+	assert.NotContains(t, mutation.Fields(), "scaleGadget")
+
+	widget, ok := gqlSchema.Type("ExampleComV1Widget").(*graphql.Object)
+	require.True(t, ok)
+	assert.Equal(t, "ScaleSubresource", widget.Fields()["scale"].Type.Name())
+
+	gauge, ok := gqlSchema.Type("ExampleComV1Gauge").(*graphql.Object)
+	require.True(t, ok)
+	assert.Equal(t, "String", gauge.Fields()["scale"].Type.Name(), "a scale field of the resource takes precedence")
+
+	gadget, ok := gqlSchema.Type("ExampleComV1Gadget").(*graphql.Object)
+	require.True(t, ok)
+	assert.NotContains(t, gadget.Fields(), "scale")
+}
+
+// TestGenerate_EvictionMutation verifies that evict{Kind} is generated only
+// for resources with an eviction subresource, i.e. pods.
+func TestGenerate_EvictionMutation(t *testing.T) {
+	definition := func(kind string, subresources ...string) *spec.Schema {
+		def := `{
+			"type": "object",
+			"properties": {"spec": {"type": "object", "properties": {"nodeName": {"type": "string"}}}},
+			"x-kubernetes-group-version-kind": [{"group": "", "version": "v1", "kind": "` + kind + `"}],
+			"x-kubernetes-scope": "Namespaced"
+		}`
+		var s spec.Schema
+		require.NoError(t, json.Unmarshal([]byte(def), &s))
+		if len(subresources) > 0 {
+			s.AddExtension(extensions.SubresourcesKey, subresources)
+		}
+		return &s
+	}
+
+	g := New(map[string]*spec.Schema{
+		"io.k8s.api.core.v1.Pod":       definition("Pod", "binding", "eviction", "status"),
+		"io.k8s.api.core.v1.ConfigMap": definition("ConfigMap"),
+	}, resolver.New(nil), nil)
+	gqlSchema, err := g.Generate(t.Context())
+	require.NoError(t, err)
+
+	mutation, ok := gqlSchema.Type("V1Mutation").(*graphql.Object)
+	require.True(t, ok)
+	assert.Contains(t, mutation.Fields(), "evictPod")
+	assert.NotContains(t, mutation.Fields(), "evictConfigMap")
+}
+
+// TestGenerate_EventsField verifies that resources get an events field only
+// if the cluster serves core/v1 events.
+func TestGenerate_EventsField(t *testing.T) {
+	definition := func(group, kind string) *spec.Schema {
+		def := `{
+			"type": "object",
+			"properties": {"message": {"type": "string"}},
+			"x-kubernetes-group-version-kind": [{"group": "` + group + `", "version": "v1", "kind": "` + kind + `"}],
+			"x-kubernetes-scope": "Namespaced"
+		}`
+		var s spec.Schema
+		require.NoError(t, json.Unmarshal([]byte(def), &s))
+		return &s
+	}
+
+	tests := []struct {
+		name        string
+		definitions map[string]*spec.Schema
+		wantEvents  bool
+	}{
+		{
+			name: "cluster serves events",
+			definitions: map[string]*spec.Schema{
+				"com.example.v1.Widget":    definition("example.com", "Widget"),
+				"io.k8s.api.core.v1.Event": definition("", "Event"),
+			},
+			wantEvents: true,
+		},
+		{
+			name: "cluster without events",
+			definitions: map[string]*spec.Schema{
+				"com.example.v1.Widget": definition("example.com", "Widget"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gqlSchema, err := New(tt.definitions, resolver.New(nil), nil).Generate(t.Context())
+			require.NoError(t, err)
+
+			widget, ok := gqlSchema.Type("ExampleComV1Widget").(*graphql.Object)
+			require.True(t, ok)
+			_, ok = widget.Fields()["events"]
+			assert.Equal(t, tt.wantEvents, ok)
+		})
+	}
+}
+
+// TestGenerate_OwnershipFields verifies that owner references resolve in
+// both directions to resource types through the object union.
+func TestGenerate_OwnershipFields(t *testing.T) {
+	definition := func(kind, scope string) *spec.Schema {
+		def := `{
+			"type": "object",
+			"properties": {
+				"metadata": {"type": "object", "properties": {"name": {"type": "string"}}},
+				"spec": {"type": "object", "properties": {"size": {"type": "integer"}}}
 			},
+			"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "` + kind + `"}],
+			"x-kubernetes-scope": "` + scope + `"
+		}`
+		var s spec.Schema
+		require.NoError(t, json.Unmarshal([]byte(def), &s))
+		return &s
+	}
+
+	gadget := &unstructured.Unstructured{}
+	gadget.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Gadget"})
+	gadget.SetName("g1")
+	gadget.SetUID("uid-g1")
+	require.NoError(t, unstructured.SetNestedField(gadget.Object, int64(3), "spec", "size"))
+
+	widget := &unstructured.Unstructured{}
+	widget.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"})
+	widget.SetNamespace("default")
+	widget.SetName("w1")
+	widget.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "example.com/v1", Kind: "Gadget", Name: "g1", UID: "uid-g1"}})
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(gadget.GroupVersionKind(), meta.RESTScopeRoot)
+	mapper.Add(widget.GroupVersionKind(), meta.RESTScopeNamespace)
+	cl := fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(gadget, widget).Build()
+
+	gqlSchema, err := New(map[string]*spec.Schema{
+		"com.example.v1.Widget": definition("Widget", "Namespaced"),
+		"com.example.v1.Gadget": definition("Gadget", "Cluster"),
+	}, resolver.New(cl), nil).Generate(t.Context())
+	require.NoError(t, err)
+
+	result := graphql.Do(graphql.Params{
+		Schema:  *gqlSchema,
+		Context: t.Context(),
+		RequestString: `{ example_com { v1 { Widget(name: "w1", namespace: "default") {
+			owners { __typename ... on ExampleComV1Gadget { metadata { name } spec { size } } }
+		} } } }`,
+	})
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string]any{"example_com": map[string]any{"v1": map[string]any{"Widget": map[string]any{
+		"owners": []any{map[string]any{
+			"__typename": "ExampleComV1Gadget",
+			"metadata":   map[string]any{"name": "g1"},
+			"spec":       map[string]any{"size": 3},
+		}},
+	}}}}, result.Data)
+
+	result = graphql.Do(graphql.Params{
+		Schema:  *gqlSchema,
+		Context: t.Context(),
+		RequestString: `{ example_com { v1 { Gadget(name: "g1") {
+			ownedObjects(kind: "Widget") { ... on ExampleComV1Widget { metadata { name } } }
+		} } } }`,
+	})
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string]any{"example_com": map[string]any{"v1": map[string]any{"Gadget": map[string]any{
+		"ownedObjects": []any{map[string]any{"metadata": map[string]any{"name": "w1"}}},
+	}}}}, result.Data)
+}
+
+func TestGenerate_RelationshipFields(t *testing.T) {
+	parse := func(def string) *spec.Schema {
+		var s spec.Schema
+		require.NoError(t, json.Unmarshal([]byte(def), &s))
+		return &s
+	}
+
+	account := &unstructured.Unstructured{}
+	account.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Account"})
+	account.SetNamespace("default")
+	account.SetName("a1")
+
+	widget := &unstructured.Unstructured{}
+	widget.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"})
+	widget.SetNamespace("default")
+	widget.SetName("w1")
+	require.NoError(t, unstructured.SetNestedField(widget.Object, "a1", "spec", "accountRef", "name"))
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(account.GroupVersionKind(), meta.RESTScopeNamespace)
+	mapper.Add(widget.GroupVersionKind(), meta.RESTScopeNamespace)
+	cl := fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(account, widget).Build()
+
+	gqlSchema, err := New(map[string]*spec.Schema{
+		"com.example.v1.Account": parse(`{
+			"type": "object",
+			"properties": {"metadata": {"type": "object", "properties": {"name": {"type": "string"}}}},
+			"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Account"}],
+			"x-kubernetes-scope": "Namespaced"
+		}`),
+		"com.example.v1.Widget": parse(`{
+			"type": "object",
+			"properties": {
+				"metadata": {"type": "object", "properties": {"name": {"type": "string"}, "namespace": {"type": "string"}}},
+				"spec": {"type": "object", "properties": {
+					"accountRef": {"type": "object", "properties": {"name": {"type": "string"}}},
+					"colorRef": {"type": "object", "properties": {"name": {"type": "string"}}}
+				}}
+			},
+			"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Widget"}],
+			"x-kubernetes-scope": "Namespaced"
+		}`),
+	}, resolver.New(cl), nil).Generate(t.Context())
+	require.NoError(t, err)
+
+	specType := gqlSchema.Type("ExampleComV1WidgetSpec").(*graphql.Object)
+	assert.Contains(t, specType.Fields(), "account")
+	assert.NotContains(t, specType.Fields(), "color", "Color is not a served kind")
+
+	result := graphql.Do(graphql.Params{
+		Schema:  *gqlSchema,
+		Context: t.Context(),
+		RequestString: `{ example_com { v1 { Widget(name: "w1", namespace: "default") {
+			spec { accountRef { name } account { ... on ExampleComV1Account { metadata { name } } } }
+		} } } }`,
+	})
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string]any{"example_com": map[string]any{"v1": map[string]any{"Widget": map[string]any{
+		"spec": map[string]any{
+			"accountRef": map[string]any{"name": "a1"},
+			"account":    map[string]any{"metadata": map[string]any{"name": "a1"}},
 		},
+	}}}}, result.Data)
+}
+
+func TestGenerate_EmptyValues(t *testing.T) {
+	var def spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"metadata": {"type": "object", "properties": {
+				"name": {"type": "string"},
+				"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+				"finalizers": {"type": "array", "items": {"type": "string"}}
+			}},
+			"spec": {"type": "object", "properties": {"tags": {"type": "array", "items": {"type": "string"}}}}
+		},
+		"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Widget"}],
+		"x-kubernetes-scope": "Namespaced"
+	}`), &def))
+
+	widget := &unstructured.Unstructured{}
+	widget.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"})
+	widget.SetNamespace("default")
+	widget.SetName("w1")
+	require.NoError(t, unstructured.SetNestedField(widget.Object, map[string]any{}, "metadata", "labels"))
+	require.NoError(t, unstructured.SetNestedField(widget.Object, []any{}, "spec", "tags"))
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(widget.GroupVersionKind(), meta.RESTScopeNamespace)
+	cl := fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(widget).Build()
+
+	tests := []struct {
+		mode           resolver.EmptyValues
+		wantLabels     any
+		wantFinalizers any
+		wantTags       any
+	}{
+		{mode: resolver.EmptyValuesPreserve, wantLabels: map[string]any{}, wantTags: []any{}},
+		{mode: resolver.EmptyValuesNull},
+		{mode: resolver.EmptyValuesEmpty, wantLabels: map[string]any{}, wantFinalizers: []any{}, wantTags: []any{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			gqlSchema, err := New(map[string]*spec.Schema{"com.example.v1.Widget": &def}, resolver.New(cl).WithEmptyValues(tt.mode), nil).Generate(t.Context())
+			require.NoError(t, err)
+
+			result := graphql.Do(graphql.Params{
+				Schema:        *gqlSchema,
+				Context:       t.Context(),
+				RequestString: `{ example_com { v1 { Widget(name: "w1", namespace: "default") { metadata { labels finalizers } spec { tags } } } } }`,
+			})
+			require.Empty(t, result.Errors)
+			assert.Equal(t, map[string]any{"example_com": map[string]any{"v1": map[string]any{"Widget": map[string]any{
+				"metadata": map[string]any{"labels": tt.wantLabels, "finalizers": tt.wantFinalizers},
+				"spec":     map[string]any{"tags": tt.wantTags},
+			}}}}, result.Data)
+		})
 	}
 }
+
+func TestGenerate_ObjectMaps(t *testing.T) {
+	var def spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"metadata": {"type": "object", "properties": {"name": {"type": "string"}, "namespace": {"type": "string"}}},
+			"spec": {"type": "object", "properties": {
+				"ports": {"type": "object", "additionalProperties": {"type": "object", "properties": {"protocol": {"type": "string"}}}}
+			}}
+		},
+		"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Widget"}],
+		"x-kubernetes-scope": "Namespaced"
+	}`), &def))
+
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(gvk, meta.RESTScopeNamespace)
+	cl := fake.NewClientBuilder().WithRESTMapper(mapper).Build()
+
+	gqlSchema, err := New(map[string]*spec.Schema{"com.example.v1.Widget": &def}, resolver.New(cl), nil).Generate(t.Context())
+	require.NoError(t, err)
+
+	created := graphql.Do(graphql.Params{
+		Schema:  *gqlSchema,
+		Context: t.Context(),
+		RequestString: `mutation { example_com { v1 { createWidget(namespace: "default", object: {
+			metadata: { name: "w1" }
+			spec: { ports: [{ key: "https", value: { protocol: "TLS" } }, { key: "http", value: { protocol: "TCP" } }] }
+		}) { spec { ports { key value { protocol } } } } } } }`,
+	})
+	require.Empty(t, created.Errors)
+	assert.Equal(t, map[string]any{"example_com": map[string]any{"v1": map[string]any{"createWidget": map[string]any{
+		"spec": map[string]any{"ports": []any{
+			map[string]any{"key": "http", "value": map[string]any{"protocol": "TCP"}},
+			map[string]any{"key": "https", "value": map[string]any{"protocol": "TLS"}},
+		}},
+	}}}}, created.Data)
+
+	stored := &unstructured.Unstructured{}
+	stored.SetGroupVersionKind(gvk)
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "w1"}, stored))
+	ports, _, err := unstructured.NestedMap(stored.Object, "spec", "ports")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"http": map[string]any{"protocol": "TCP"}, "https": map[string]any{"protocol": "TLS"}}, ports)
+}
+
+func TestGenerate_RequiredFields(t *testing.T) {
+	var def spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"required": ["spec"],
+		"properties": {
+			"metadata": {"type": "object", "properties": {"name": {"type": "string"}, "namespace": {"type": "string"}}},
+			"spec": {"type": "object", "required": ["image"], "properties": {
+				"image": {"type": "string"},
+				"replicas": {"type": "integer"}
+			}}
+		},
+		"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Widget"}],
+		"x-kubernetes-scope": "Namespaced"
+	}`), &def))
+
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(gvk, meta.RESTScopeNamespace)
+	cl := fake.NewClientBuilder().WithRESTMapper(mapper).Build()
+
+	gqlSchema, err := New(map[string]*spec.Schema{"com.example.v1.Widget": &def}, resolver.New(cl), nil).Generate(t.Context())
+	require.NoError(t, err)
+
+	missing := graphql.Do(graphql.Params{
+		Schema:  *gqlSchema,
+		Context: t.Context(),
+		RequestString: `mutation { example_com { v1 { createWidget(namespace: "default", object: {
+			metadata: { name: "w1" }
+			spec: { replicas: 1 }
+		}) { metadata { name } } } } }`,
+	})
+	require.Len(t, missing.Errors, 1)
+	assert.Contains(t, missing.Errors[0].Message, `In field "image": Expected "String!", found null`)
+
+	created := graphql.Do(graphql.Params{
+		Schema:  *gqlSchema,
+		Context: t.Context(),
+		RequestString: `mutation { example_com { v1 { createWidget(namespace: "default", object: {
+			metadata: { name: "w1" }
+			spec: { image: "nginx" }
+		}) { metadata { name } } } } }`,
+	})
+	require.Empty(t, created.Errors)
+
+	// Merge patches leave out the fields they do not change, required or not.
+	updated := graphql.Do(graphql.Params{
+		Schema:  *gqlSchema,
+		Context: t.Context(),
+		RequestString: `mutation { example_com { v1 { updateWidget(namespace: "default", name: "w1", object: {
+			spec: { replicas: 5 }
+		}) { spec { image replicas } } } } }`,
+	})
+	require.Empty(t, updated.Errors)
+	assert.Equal(t, map[string]any{"example_com": map[string]any{"v1": map[string]any{"updateWidget": map[string]any{
+		"spec": map[string]any{"image": "nginx", "replicas": 5},
+	}}}}, updated.Data)
+}
+
+func TestGenerate_InputDefaults(t *testing.T) {
+	var def spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"metadata": {"type": "object", "properties": {"name": {"type": "string"}, "namespace": {"type": "string"}}},
+			"spec": {"type": "object", "properties": {
+				"image": {"type": "string"},
+				"mode": {"type": "string", "default": "Auto"}
+			}}
+		},
+		"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Widget"}],
+		"x-kubernetes-scope": "Namespaced"
+	}`), &def))
+
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(gvk, meta.RESTScopeNamespace)
+	cl := fake.NewClientBuilder().WithRESTMapper(mapper).Build()
+
+	gqlSchema, err := New(map[string]*spec.Schema{"com.example.v1.Widget": &def}, resolver.New(cl), nil).Generate(t.Context())
+	require.NoError(t, err)
+
+	do := func(mutation string) map[string]any {
+		t.Helper()
+		result := graphql.Do(graphql.Params{Schema: *gqlSchema, Context: t.Context(), RequestString: mutation})
+		require.Empty(t, result.Errors)
+		return result.Data.(map[string]any)["example_com"].(map[string]any)["v1"].(map[string]any)
+	}
+
+	created := do(`mutation { example_com { v1 { createWidget(namespace: "default", object: {
+		metadata: { name: "w1" }
+		spec: { image: "nginx" }
+	}) { spec { mode } } } } }`)
+	assert.Equal(t, map[string]any{"spec": map[string]any{"mode": "Auto"}}, created["createWidget"])
+
+	do(`mutation { example_com { v1 { updateWidget(namespace: "default", name: "w1", object: { spec: { mode: "Manual" } }) { spec { mode } } } } }`)
+
+	// The merge patch of an update only holds the fields that were set, so
+	// defaulted fields keep their values.
+	updated := do(`mutation { example_com { v1 { updateWidget(namespace: "default", name: "w1", object: { spec: { image: "httpd" } }) { spec { image mode } } } } }`)
+	assert.Equal(t, map[string]any{"spec": map[string]any{"image": "httpd", "mode": "Manual"}}, updated["updateWidget"])
+}
+
+func TestGenerate_NodeInterface(t *testing.T) {
+	parse := func(def string) *spec.Schema {
+		var s spec.Schema
+		require.NoError(t, json.Unmarshal([]byte(def), &s))
+		return &s
+	}
+
+	gqlSchema, err := New(map[string]*spec.Schema{
+		"com.example.v1.Widget": parse(`{
+			"type": "object",
+			"properties": {"metadata": {"type": "object", "properties": {"name": {"type": "string"}}}},
+			"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Widget"}],
+			"x-kubernetes-scope": "Namespaced"
+		}`),
+		"com.example.v1.Gadget": parse(`{
+			"type": "object",
+			"properties": {"id": {"type": "integer"}},
+			"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Gadget"}],
+			"x-kubernetes-scope": "Namespaced"
+		}`),
+	}, resolver.New(nil), nil).Generate(t.Context())
+	require.NoError(t, err)
+
+	node, ok := gqlSchema.Type("Node").(*graphql.Interface)
+	require.True(t, ok)
+	assert.Contains(t, gqlSchema.QueryType().Fields(), "node")
+
+	widget := gqlSchema.Type("ExampleComV1Widget").(*graphql.Object)
+	assert.Contains(t, widget.Interfaces(), node)
+	assert.Equal(t, "ID!", widget.Fields()["id"].Type.String())
+
+	gadget := gqlSchema.Type("ExampleComV1Gadget").(*graphql.Object)
+	assert.Empty(t, gadget.Interfaces(), "types with an id field of their own do not implement Node")
+	assert.Equal(t, "Int", gadget.Fields()["id"].Type.String())
+}
+
+func TestGenerate_Federation(t *testing.T) {
+	var def spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {"metadata": {"type": "object", "properties": {"name": {"type": "string"}, "namespace": {"type": "string"}}}},
+		"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Widget"}],
+		"x-kubernetes-scope": "Namespaced"
+	}`), &def))
+	definitions := map[string]*spec.Schema{"com.example.v1.Widget": &def}
+
+	widget := &unstructured.Unstructured{}
+	widget.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"})
+	widget.SetNamespace("default")
+	widget.SetName("w1")
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(widget.GroupVersionKind(), meta.RESTScopeNamespace)
+	cl := fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(widget).Build()
+
+	t.Run("disabled", func(t *testing.T) {
+		gqlSchema, err := New(definitions, resolver.New(cl), nil).Generate(t.Context())
+		require.NoError(t, err)
+		assert.NotContains(t, gqlSchema.QueryType().Fields(), "_entities")
+		assert.NotContains(t, gqlSchema.QueryType().Fields(), "_service")
+	})
+
+	gqlSchema, err := New(definitions, resolver.New(cl).WithFederation(true), nil).
+		WithSubgraphSDL(func(s *graphql.Schema) string { return "sdl of " + s.QueryType().Name() }).
+		Generate(t.Context())
+	require.NoError(t, err)
+
+	result := graphql.Do(graphql.Params{
+		Schema:  *gqlSchema,
+		Context: t.Context(),
+		RequestString: `{
+			_service { sdl }
+			_entities(representations: [
+				{__typename: "ExampleComV1Widget", metadata: {name: "w1", namespace: "default"}},
+				{__typename: "ExampleComV1Widget", metadata: {name: "missing", namespace: "default"}}
+			]) { ... on ExampleComV1Widget { metadata { name } } }
+		}`,
+	})
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string]any{
+		"_service":  map[string]any{"sdl": "sdl of Query"},
+		"_entities": []any{map[string]any{"metadata": map[string]any{"name": "w1"}}, nil},
+	}, result.Data)
+
+	result = graphql.Do(graphql.Params{
+		Schema:        *gqlSchema,
+		Context:       t.Context(),
+		RequestString: `{ _entities(representations: [{__typename: "Unknown", metadata: {name: "w1"}}]) { __typename } }`,
+	})
+	require.NotEmpty(t, result.Errors)
+	assert.Contains(t, result.Errors[0].Message, `type "Unknown" is not an entity`)
+}
+
+func TestGenerate_KindAliases(t *testing.T) {
+	var def spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {"metadata": {"type": "object", "properties": {"name": {"type": "string"}, "namespace": {"type": "string"}}}},
+		"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v2", "kind": "Gadget"}],
+		"x-kubernetes-scope": "Namespaced"
+	}`), &def))
+
+	gadget := &unstructured.Unstructured{}
+	gadget.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v2", Kind: "Gadget"})
+	gadget.SetNamespace("default")
+	gadget.SetName("g1")
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(gadget.GroupVersionKind(), meta.RESTScopeNamespace)
+	cl := fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(gadget).Build()
+
+	gqlSchema, err := New(map[string]*spec.Schema{"com.example.v2.Gadget": &def}, resolver.New(cl).WithKindAliases([]resolver.KindAlias{
+		{From: schema.GroupVersionKind{Group: "old.example.com", Version: "v1", Kind: "Widget"}, To: gadget.GroupVersionKind()},
+		{From: schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Gadget"}, To: gadget.GroupVersionKind()},
+		{From: schema.GroupVersionKind{Version: "v1", Kind: "Missing"}, To: schema.GroupVersionKind{Version: "v1", Kind: "Unserved"}},
+	}), nil).Generate(t.Context())
+	require.NoError(t, err)
+
+	result := graphql.Do(graphql.Params{
+		Schema:  *gqlSchema,
+		Context: t.Context(),
+		RequestString: `{
+			old_example_com { v1 { Widget(name: "g1", namespace: "default") { metadata { name } } } }
+			example_com { v1 { Gadgets(namespace: "default") { items { metadata { name } } } } }
+		}`,
+	})
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string]any{
+		"old_example_com": map[string]any{"v1": map[string]any{"Widget": map[string]any{"metadata": map[string]any{"name": "g1"}}}},
+		"example_com":     map[string]any{"v1": map[string]any{"Gadgets": map[string]any{"items": []any{map[string]any{"metadata": map[string]any{"name": "g1"}}}}}},
+	}, result.Data)
+
+	oldVersion := gqlSchema.Type("OldExampleComV1Query").(*graphql.Object)
+	assert.Equal(t, "Renamed to Gadget example.com/v2", oldVersion.Fields()["Widgets"].DeprecationReason)
+	assert.Contains(t, gqlSchema.Type("OldExampleComV1Mutation").(*graphql.Object).Fields(), "createWidget")
+	assert.Contains(t, gqlSchema.SubscriptionType().Fields(), "old_example_com_v1_widgets")
+	assert.Contains(t, gqlSchema.SubscriptionType().Fields(), "example_com_v1_gadget")
+	assert.Empty(t, gqlSchema.Type("ExampleComV2Query").(*graphql.Object).Fields()["Gadget"].DeprecationReason)
+	assert.Nil(t, gqlSchema.Type("V1Query"), "aliases of unserved kinds are skipped")
+}
@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+)
+
+// introspectionQuery reads the complete schema, as GraphiQL and code
+// generators do.
+const introspectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives {
+      name
+      description
+      locations
+      args { ...InputValue }
+    }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType {
+              kind
+              name
+              ofType { kind name }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// IntrospectionJSON returns the result of the introspection query against a
+// schema as JSON, {"data": {"__schema": ...}}, which code generators read
+// like a response of the endpoint.
+func IntrospectionJSON(ctx context.Context, s *graphql.Schema) ([]byte, error) {
+	result := graphql.Do(graphql.Params{
+		Schema:        *s,
+		RequestString: introspectionQuery,
+		Context:       ctx,
+	})
+	if result.HasErrors() {
+		return nil, fmt.Errorf("failed to introspect schema: %v", result.Errors)
+	}
+	return json.MarshalIndent(map[string]any{"data": result.Data}, "", "  ")
+}
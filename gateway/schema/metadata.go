@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"maps"
+	"slices"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// objectMetaKey is the definition of the metadata every resource shares.
+const objectMetaKey = "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"
+
+// managedFieldsProperty is the ObjectMeta property recording server-side
+// apply field ownership. It is large and rarely useful outside of tooling.
+const managedFieldsProperty = "managedFields"
+
+// WithoutManagedFields returns the definitions with metadata.managedFields
+// removed from ObjectMeta. The definitions passed in are not modified.
+func WithoutManagedFields(definitions map[string]*spec.Schema) map[string]*spec.Schema {
+	objectMeta, ok := definitions[objectMetaKey]
+	if !ok || objectMeta == nil {
+		return definitions
+	}
+	if _, ok := objectMeta.Properties[managedFieldsProperty]; !ok {
+		return definitions
+	}
+
+	trimmed := *objectMeta
+	trimmed.Properties = maps.Clone(objectMeta.Properties)
+	delete(trimmed.Properties, managedFieldsProperty)
+	trimmed.Required = slices.DeleteFunc(slices.Clone(objectMeta.Required), func(name string) bool {
+		return name == managedFieldsProperty
+	})
+
+	result := maps.Clone(definitions)
+	result[objectMetaKey] = &trimmed
+	return result
+}
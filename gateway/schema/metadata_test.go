@@ -0,0 +1,35 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestWithoutManagedFields(t *testing.T) {
+	objectMeta := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"name":          *spec.StringProperty(),
+			"uid":           *spec.StringProperty(),
+			"managedFields": *spec.ArrayProperty(spec.MapProperty(nil)),
+		},
+	}}
+	definitions := map[string]*spec.Schema{
+		objectMetaKey: objectMeta,
+		"v1.Pod":      {},
+	}
+
+	trimmed := WithoutManagedFields(definitions)
+
+	assert.NotContains(t, trimmed[objectMetaKey].Properties, "managedFields")
+	assert.Contains(t, trimmed[objectMetaKey].Properties, "uid")
+	assert.Same(t, definitions["v1.Pod"], trimmed["v1.Pod"])
+	assert.Contains(t, objectMeta.Properties, "managedFields", "the original definitions must not be modified")
+	assert.Same(t, objectMeta, definitions[objectMetaKey])
+
+	withoutObjectMeta := map[string]*spec.Schema{"v1.Pod": {}}
+	assert.Equal(t, withoutObjectMeta, WithoutManagedFields(withoutObjectMeta))
+}
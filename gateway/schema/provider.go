@@ -14,20 +14,28 @@ import (
 // Provider provides access to the generated GraphQL schema.
 // It acts as a thin facade over the generator package.
 type Provider struct {
-	schema *graphql.Schema
+	schema     *graphql.Schema
+	categories map[string][]resolver.TypeByCategory
 }
 
 // New creates a new Provider with a GraphQL schema built from OpenAPI definitions.
 func New(ctx context.Context, definitions map[string]*spec.Schema, resolverProvider *resolver.Service, customSubGen *extensions.CustomSubscriptionGenerator) (*Provider, error) {
-	schema, err := generator.New(definitions, resolverProvider, customSubGen).Generate(ctx)
+	g := generator.New(definitions, resolverProvider, customSubGen).
+		WithSubgraphSDL(PrintSubgraphSDL)
+	schema, err := g.Generate(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Provider{schema: schema}, nil
+	return &Provider{schema: schema, categories: g.Categories()}, nil
 }
 
 // GetSchema returns the generated GraphQL schema.
 func (p *Provider) GetSchema() *graphql.Schema {
 	return p.schema
 }
+
+// Categories returns the types of each category of the schema.
+func (p *Provider) Categories() map[string][]resolver.TypeByCategory {
+	return p.categories
+}
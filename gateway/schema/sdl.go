@@ -0,0 +1,246 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// builtinScalars are the scalars every GraphQL schema has; SDL omits them.
+var builtinScalars = map[string]bool{
+	"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true,
+}
+
+// PrintSDL renders a schema in the GraphQL schema definition language, with
+// types, fields, arguments and enum values sorted by name so the output is
+// stable across generations. Built-in scalars, introspection types and the
+// specified directives are omitted.
+func PrintSDL(s *graphql.Schema) string {
+	return printSDL(s, sdlOptions{})
+}
+
+// sdlOptions adjust the printed schema, e.g. for a federation subgraph.
+type sdlOptions struct {
+	// header is printed before the definitions.
+	header string
+	// omitTypes and omitFields list the types and the fields, as
+	// Type.field, left out.
+	omitTypes  map[string]bool
+	omitFields map[string]bool
+	// typeDirectives are appended to the definitions of the named types.
+	typeDirectives map[string]string
+}
+
+func printSDL(s *graphql.Schema, opts sdlOptions) string {
+	var b strings.Builder
+
+	b.WriteString(opts.header)
+	printSchemaDefinition(&b, s)
+
+	for _, d := range s.Directives() {
+		if slices.Contains(graphql.SpecifiedDirectives, d) {
+			continue
+		}
+		printDescription(&b, d.Description, "")
+		fmt.Fprintf(&b, "directive @%s%s on %s\n\n", d.Name, printArgs(d.Args), strings.Join(d.Locations, " | "))
+	}
+
+	typeMap := s.TypeMap()
+	names := make([]string, 0, len(typeMap))
+	for name := range typeMap {
+		if strings.HasPrefix(name, "__") || builtinScalars[name] || opts.omitTypes[name] {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		printType(&b, typeMap[name], opts)
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// printSchemaDefinition prints the schema definition unless the root types
+// have their default names.
+func printSchemaDefinition(b *strings.Builder, s *graphql.Schema) {
+	roots := []struct {
+		operation string
+		object    *graphql.Object
+	}{
+		{"query", s.QueryType()},
+		{"mutation", s.MutationType()},
+		{"subscription", s.SubscriptionType()},
+	}
+
+	conventional := true
+	for _, r := range roots {
+		if r.object != nil && !strings.EqualFold(r.object.Name(), r.operation) {
+			conventional = false
+		}
+	}
+	if conventional {
+		return
+	}
+
+	b.WriteString("schema {\n")
+	for _, r := range roots {
+		if r.object != nil {
+			fmt.Fprintf(b, "  %s: %s\n", r.operation, r.object.Name())
+		}
+	}
+	b.WriteString("}\n\n")
+}
+
+func printType(b *strings.Builder, t graphql.Type, opts sdlOptions) {
+	printDescription(b, t.Description(), "")
+
+	switch t := t.(type) {
+	case *graphql.Scalar:
+		fmt.Fprintf(b, "scalar %s\n\n", t.Name())
+	case *graphql.Object:
+		fmt.Fprintf(b, "type %s%s%s {\n", t.Name(), printInterfaces(t.Interfaces()), opts.typeDirectives[t.Name()])
+		fieldMap := graphql.FieldDefinitionMap{}
+		for name, f := range t.Fields() {
+			if !opts.omitFields[t.Name()+"."+name] {
+				fieldMap[name] = f
+			}
+		}
+		printFields(b, fieldMap)
+		b.WriteString("}\n\n")
+	case *graphql.Interface:
+		fmt.Fprintf(b, "interface %s {\n", t.Name())
+		printFields(b, t.Fields())
+		b.WriteString("}\n\n")
+	case *graphql.Union:
+		members := make([]string, 0, len(t.Types()))
+		for _, member := range t.Types() {
+			members = append(members, member.Name())
+		}
+		fmt.Fprintf(b, "union %s = %s\n\n", t.Name(), strings.Join(members, " | "))
+	case *graphql.Enum:
+		fmt.Fprintf(b, "enum %s {\n", t.Name())
+		values := slices.Clone(t.Values())
+		slices.SortFunc(values, func(a, b *graphql.EnumValueDefinition) int { return strings.Compare(a.Name, b.Name) })
+		for _, v := range values {
+			printDescription(b, v.Description, "  ")
+			fmt.Fprintf(b, "  %s%s\n", v.Name, printDeprecated(v.DeprecationReason))
+		}
+		b.WriteString("}\n\n")
+	case *graphql.InputObject:
+		fmt.Fprintf(b, "input %s {\n", t.Name())
+		fieldMap := t.Fields()
+		for _, name := range sortedKeys(fieldMap) {
+			f := fieldMap[name]
+			printDescription(b, f.Description(), "  ")
+			fmt.Fprintf(b, "  %s: %s%s\n", name, f.Type, printDefault(f.DefaultValue))
+		}
+		b.WriteString("}\n\n")
+	}
+}
+
+func printInterfaces(interfaces []*graphql.Interface) string {
+	if len(interfaces) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(interfaces))
+	for _, i := range interfaces {
+		names = append(names, i.Name())
+	}
+	return " implements " + strings.Join(names, " & ")
+}
+
+func printFields(b *strings.Builder, fieldMap graphql.FieldDefinitionMap) {
+	for _, name := range sortedKeys(fieldMap) {
+		f := fieldMap[name]
+		printDescription(b, f.Description, "  ")
+		fmt.Fprintf(b, "  %s%s: %s%s\n", name, printArgs(f.Args), f.Type, printDeprecated(f.DeprecationReason))
+	}
+}
+
+func printArgs(args []*graphql.Argument) string {
+	if len(args) == 0 {
+		return ""
+	}
+	// graphql-go keeps arguments in a map, so their order is not stable.
+	args = slices.Clone(args)
+	slices.SortFunc(args, func(a, b *graphql.Argument) int { return strings.Compare(a.Name(), b.Name()) })
+
+	printed := make([]string, 0, len(args))
+	for _, a := range args {
+		printed = append(printed, fmt.Sprintf("%s: %s%s", a.Name(), a.Type, printDefault(a.DefaultValue)))
+	}
+	return "(" + strings.Join(printed, ", ") + ")"
+}
+
+func printDefault(value any) string {
+	if value == nil {
+		return ""
+	}
+	return " = " + printValue(value)
+}
+
+func printDeprecated(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return " @deprecated(reason: " + strconv.Quote(reason) + ")"
+}
+
+func printDescription(b *strings.Builder, description, indent string) {
+	if description == "" {
+		return
+	}
+	if !strings.Contains(description, "\n") {
+		fmt.Fprintf(b, "%s%s\n", indent, strconv.Quote(description))
+		return
+	}
+	escaped := strings.ReplaceAll(description, `"""`, `\"""`)
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+	for line := range strings.SplitSeq(escaped, "\n") {
+		fmt.Fprintf(b, "%s%s\n", indent, line)
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+}
+
+// printValue renders a default value as a GraphQL literal.
+func printValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, printValue(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case []string:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, strconv.Quote(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]any:
+		fields := make([]string, 0, len(v))
+		for _, key := range sortedKeys(v) {
+			fields = append(fields, key+": "+printValue(v[key]))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
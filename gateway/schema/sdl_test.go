@@ -0,0 +1,73 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintSDL(t *testing.T) {
+	phase := graphql.NewEnum(graphql.EnumConfig{
+		Name: "Phase",
+		Values: graphql.EnumValueConfigMap{
+			"Running": &graphql.EnumValueConfig{Value: "Running"},
+			"Pending": &graphql.EnumValueConfig{Value: "Pending", DeprecationReason: "use Running"},
+		},
+	})
+	filter := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "Filter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"labels": &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.String), DefaultValue: []any{"app"}},
+		},
+	})
+	pod := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Pod",
+		Description: "A pod.\nRuns containers.",
+		Fields: graphql.Fields{
+			"phase": &graphql.Field{Type: phase},
+			"name":  &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "The name."},
+		},
+	})
+
+	s, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"pods": &graphql.Field{
+					Type: graphql.NewList(pod),
+					Args: graphql.FieldConfigArgument{
+						"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+						"filter": &graphql.ArgumentConfig{Type: filter},
+					},
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `input Filter {
+  labels: [String] = ["app"]
+}
+
+enum Phase {
+  Pending @deprecated(reason: "use Running")
+  Running
+}
+
+"""
+A pod.
+Runs containers.
+"""
+type Pod {
+  "The name."
+  name: String!
+  phase: Phase
+}
+
+type Query {
+  pods(filter: Filter, limit: Int = 10): [Pod]
+}
+`, PrintSDL(&s)+"\n")
+}
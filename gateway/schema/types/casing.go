@@ -0,0 +1,117 @@
+package types
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// FieldCasing is how the GraphQL names of object fields are derived from the
+// names of their properties.
+type FieldCasing string
+
+const (
+	// FieldCasingPreserve keeps the property names, replacing characters
+	// GraphQL does not allow with underscores.
+	FieldCasingPreserve FieldCasing = "preserve"
+	// FieldCasingCamel converts snake_case and kebab-case property names to
+	// camelCase, e.g. max_replicas and max-replicas to maxReplicas.
+	FieldCasingCamel FieldCasing = "camel"
+)
+
+// wordSeparatorRegex matches the separators of the words of a property name.
+var wordSeparatorRegex = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// FieldName returns the GraphQL name of the field of a property.
+func (c FieldCasing) FieldName(property string) string {
+	if c != FieldCasingCamel {
+		return SanitizeFieldName(property)
+	}
+
+	words := wordSeparatorRegex.Split(property, -1)
+	var b strings.Builder
+	for _, word := range words {
+		if b.Len() == 0 {
+			b.WriteString(word)
+			continue
+		}
+		b.WriteString(capitalize(word))
+	}
+	return SanitizeFieldName(b.String())
+}
+
+// FieldNames names the fields of the object types of a schema and records
+// the input object fields named differently than their properties, so
+// arguments can be converted back to objects. A nil FieldNames preserves
+// property names and records nothing.
+type FieldNames struct {
+	casing FieldCasing
+
+	mu sync.RWMutex
+	// properties maps input object type names to the property names of
+	// their renamed fields.
+	properties map[string]map[string]string
+}
+
+// NewFieldNames creates FieldNames for casing.
+func NewFieldNames(casing FieldCasing) *FieldNames {
+	return &FieldNames{
+		casing:     casing,
+		properties: map[string]map[string]string{},
+	}
+}
+
+// Casing returns the casing of the field names.
+func (n *FieldNames) Casing() FieldCasing {
+	if n == nil {
+		return FieldCasingPreserve
+	}
+	return n.casing
+}
+
+// Property returns the name of the property of a field of an input object
+// type.
+func (n *FieldNames) Property(inputType, field string) string {
+	if n == nil {
+		return field
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if property, ok := n.properties[inputType][field]; ok {
+		return property
+	}
+	return field
+}
+
+// record records the property names of the renamed fields of an input object
+// type.
+func (n *FieldNames) record(inputType string, properties map[string]string) {
+	if n == nil || len(properties) == 0 {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.properties[inputType] = properties
+}
+
+// alias records the renamed fields of the input object type inputType for
+// the input object type name derived from it.
+func (n *FieldNames) alias(name, inputType string) {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if properties, ok := n.properties[inputType]; ok {
+		n.properties[name] = properties
+	}
+}
+
+// InputTypeName returns the name of the input object type of the object type
+// typeName.
+func InputTypeName(typeName string) string {
+	return SanitizeFieldName(typeName) + "_Input"
+}
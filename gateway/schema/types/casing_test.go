@@ -0,0 +1,99 @@
+package types_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestFieldCasing_FieldName(t *testing.T) {
+	tests := []struct {
+		casing   types.FieldCasing
+		property string
+		expected string
+	}{
+		{types.FieldCasingPreserve, "max_replicas", "max_replicas"},
+		{types.FieldCasingPreserve, "max-replicas", "max_replicas"},
+		{types.FieldCasingCamel, "max_replicas", "maxReplicas"},
+		{types.FieldCasingCamel, "max-replicas", "maxReplicas"},
+		{types.FieldCasingCamel, "apiVersion", "apiVersion"},
+		{types.FieldCasingCamel, "kubernetes.io/ingress-class", "kubernetesIoIngressClass"},
+		{types.FieldCasingCamel, "_private", "private"},
+		{types.FieldCasingCamel, "1st_value", "_1stValue"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.casing)+"/"+tt.property, func(t *testing.T) {
+			if got := tt.casing.FieldName(tt.property); got != tt.expected {
+				t.Errorf("FieldName(%q) = %q, want %q", tt.property, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestConvert_CamelCaseFields verifies that renamed fields read their
+// properties on output and are converted back to them on input.
+func TestConvert_CamelCaseFields(t *testing.T) {
+	names := types.NewFieldNames(types.FieldCasingCamel)
+	converter := types.NewConverter(types.NewRegistry()).WithFieldNames(names)
+
+	str := spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"string"}}}
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{
+				"spec": {SchemaProps: spec.SchemaProps{
+					Type:       []string{"object"},
+					Properties: map[string]spec.Schema{"storage_class": str, "node-name": str},
+				}},
+			},
+		},
+	}
+
+	fields, inputFields, err := converter.ConvertFields(schema, map[string]*spec.Schema{}, "TestType")
+	if err != nil {
+		t.Fatalf("ConvertFields() error = %v", err)
+	}
+
+	specType := fields["spec"].Type.(*graphql.Object)
+	storageClass, ok := specType.Fields()["storageClass"]
+	if !ok {
+		t.Fatalf("spec fields = %v, want storageClass", specType.Fields())
+	}
+	got, err := storageClass.Resolve(graphql.ResolveParams{
+		Source: map[string]any{"storage_class": "fast"},
+		Info:   graphql.ResolveInfo{FieldName: "storageClass"},
+	})
+	if err != nil || got != "fast" {
+		t.Errorf("Resolve() = %v, %v, want fast", got, err)
+	}
+
+	inputType := graphql.NewInputObject(graphql.InputObjectConfig{Name: types.InputTypeName("TestType"), Fields: inputFields})
+	input := map[string]any{"spec": map[string]any{"storageClass": "fast", "nodeName": "node-1"}}
+	want := map[string]any{"spec": map[string]any{"storage_class": "fast", "node-name": "node-1"}}
+	if got := types.InputToObject(input, inputType, names); !reflect.DeepEqual(got, want) {
+		t.Errorf("InputToObject() = %v, want %v", got, want)
+	}
+}
+
+func TestConvert_FieldNameCollision(t *testing.T) {
+	str := spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"string"}}}
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{"max_replicas": str, "maxReplicas": str},
+		},
+	}
+
+	converter := types.NewConverter(types.NewRegistry()).WithFieldNames(types.NewFieldNames(types.FieldCasingCamel))
+	if _, _, err := converter.ConvertFields(schema, map[string]*spec.Schema{}, "TestType"); err == nil {
+		t.Error("ConvertFields() error = nil, want a collision of max_replicas and maxReplicas")
+	}
+
+	converter = types.NewConverter(types.NewRegistry())
+	if _, _, err := converter.ConvertFields(schema, map[string]*spec.Schema{}, "TestType"); err != nil {
+		t.Errorf("ConvertFields() error = %v with preserved names", err)
+	}
+}
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// inputDescription returns the description of an input field followed by
+// the validation constraints of its schema, so they show up in GraphiQL
+// until the schema can express them as directives. Enum values are only
+// listed if the field is not a GraphQL enum already.
+func inputDescription(fieldSpec spec.Schema, inputType graphql.Input) string {
+	constraints := validationConstraints(fieldSpec)
+	if _, isEnum := graphql.GetNamed(inputType).(*graphql.Enum); !isEnum && len(fieldSpec.Enum) > 0 {
+		values := make([]string, 0, len(fieldSpec.Enum))
+		for _, v := range fieldSpec.Enum {
+			values = append(values, printConstraintValue(v))
+		}
+		constraints = append(constraints, "one of "+strings.Join(values, ", "))
+	}
+
+	if len(constraints) == 0 {
+		return fieldSpec.Description
+	}
+	summary := "Constraints: " + strings.Join(constraints, ", ") + "."
+	if fieldSpec.Description == "" {
+		return summary
+	}
+	return fieldSpec.Description + "\n\n" + summary
+}
+
+// validationConstraints lists the bounds, length limits and pattern of a
+// schema in the order of the OpenAPI specification.
+func validationConstraints(s spec.Schema) []string {
+	var constraints []string
+	if s.Minimum != nil {
+		if s.ExclusiveMinimum {
+			constraints = append(constraints, "greater than "+formatNumber(*s.Minimum))
+		} else {
+			constraints = append(constraints, "minimum "+formatNumber(*s.Minimum))
+		}
+	}
+	if s.Maximum != nil {
+		if s.ExclusiveMaximum {
+			constraints = append(constraints, "less than "+formatNumber(*s.Maximum))
+		} else {
+			constraints = append(constraints, "maximum "+formatNumber(*s.Maximum))
+		}
+	}
+	if s.MultipleOf != nil {
+		constraints = append(constraints, "multiple of "+formatNumber(*s.MultipleOf))
+	}
+	if s.MinLength != nil {
+		constraints = append(constraints, fmt.Sprintf("min length %d", *s.MinLength))
+	}
+	if s.MaxLength != nil {
+		constraints = append(constraints, fmt.Sprintf("max length %d", *s.MaxLength))
+	}
+	if s.Pattern != "" {
+		constraints = append(constraints, "pattern `"+s.Pattern+"`")
+	}
+	if s.MinItems != nil {
+		constraints = append(constraints, fmt.Sprintf("min items %d", *s.MinItems))
+	}
+	if s.MaxItems != nil {
+		constraints = append(constraints, fmt.Sprintf("max items %d", *s.MaxItems))
+	}
+	if s.UniqueItems {
+		constraints = append(constraints, "unique items")
+	}
+	return constraints
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func printConstraintValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return formatNumber(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
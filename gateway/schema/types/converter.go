@@ -1,13 +1,24 @@
 package types
 
 import (
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+
 	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
 
 	"k8s.io/kube-openapi/pkg/validation/spec"
 )
 
+// enumValueNameRegex matches values that are valid GraphQL enum value names.
+var enumValueNameRegex = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*$`)
+
 type Converter struct {
 	registry *Registry
+	names    *FieldNames
 }
 
 func NewConverter(registry *Registry) *Converter {
@@ -16,6 +27,13 @@ func NewConverter(registry *Registry) *Converter {
 	}
 }
 
+// WithFieldNames names the fields of the converted types with names,
+// recording the renamed input object fields in it.
+func (c *Converter) WithFieldNames(names *FieldNames) *Converter {
+	c.names = names
+	return c
+}
+
 func (c *Converter) ConvertFields(resourceScheme *spec.Schema, definitions map[string]*spec.Schema, typePrefix string) (graphql.Fields, graphql.InputObjectConfigFieldMap, error) {
 	return c.convertFields(resourceScheme, definitions, typePrefix, []string{})
 }
@@ -23,9 +41,22 @@ func (c *Converter) ConvertFields(resourceScheme *spec.Schema, definitions map[s
 func (c *Converter) convertFields(resourceScheme *spec.Schema, definitions map[string]*spec.Schema, typePrefix string, fieldPath []string) (graphql.Fields, graphql.InputObjectConfigFieldMap, error) {
 	fields := graphql.Fields{}
 	inputFields := graphql.InputObjectConfigFieldMap{}
+	createFields := map[string]createField{}
+	casing := c.names.Casing()
+
+	// Names are assigned in the order of the properties, so collisions are
+	// reported the same way every time.
+	properties := make(map[string]string, len(resourceScheme.Properties))
+	for _, property := range slices.Sorted(maps.Keys(resourceScheme.Properties)) {
+		name := casing.FieldName(property)
+		if other, exists := properties[name]; exists {
+			return nil, nil, fmt.Errorf("properties %q and %q of %s are both named %s", other, property, GenerateTypeName(typePrefix, fieldPath), name)
+		}
+		properties[name] = property
+	}
 
-	for fieldName, fieldSpec := range resourceScheme.Properties {
-		sanitizedFieldName := SanitizeFieldName(fieldName)
+	for name, fieldName := range properties {
+		fieldSpec := resourceScheme.Properties[fieldName]
 		currentFieldPath := append(fieldPath, fieldName)
 
 		fieldType, inputFieldType, err := c.convert(fieldSpec, definitions, typePrefix, currentFieldPath)
@@ -33,27 +64,53 @@ func (c *Converter) convertFields(resourceScheme *spec.Schema, definitions map[s
 			return nil, nil, err
 		}
 
-		fields[sanitizedFieldName] = &graphql.Field{
+		fields[name] = &graphql.Field{
 			Type:        fieldType,
 			Description: fieldSpec.Description,
+			Resolve:     resolveProperty(fieldName, name, fieldType),
+		}
+
+		// Required fields must be set on create only, as merge patches and
+		// apply intents may leave them out; outputs stay nullable so objects
+		// that violate the schema can still be read.
+		// Defaults are left out of their merge patches and apply intents as
+		// well, which would otherwise reset the fields to them.
+		createField := createField{
+			required:     slices.Contains(resourceScheme.Required, fieldName),
+			defaultValue: inputDefault(fieldSpec.Default, inputFieldType),
+		}
+		if createField.required || createField.defaultValue != nil {
+			createFields[name] = createField
 		}
 
-		inputFields[sanitizedFieldName] = &graphql.InputObjectFieldConfig{
+		inputFields[name] = &graphql.InputObjectFieldConfig{
 			Type:        inputFieldType,
-			Description: fieldSpec.Description,
+			Description: inputDescription(fieldSpec, inputFieldType),
 		}
 	}
 
+	maps.DeleteFunc(properties, func(name, property string) bool { return name == property })
+	inputTypeName := InputTypeName(GenerateTypeName(typePrefix, fieldPath))
+	c.names.record(inputTypeName, properties)
+	c.registry.registerCreateFields(inputTypeName, createFields)
+
 	return fields, inputFields, nil
 }
 
 func (c *Converter) convert(schema spec.Schema, definitions map[string]*spec.Schema, typePrefix string, fieldPath []string) (graphql.Output, graphql.Input, error) {
+	if isIntOrString(schema) {
+		return IntOrStringScalar, IntOrStringScalar, nil
+	}
+
 	if len(schema.Type) == 0 {
 		return c.handleRefType(schema, definitions, fieldPath)
 	}
 
 	switch schema.Type[0] {
 	case "string":
+		if enum := c.enumType(schema, typePrefix, fieldPath); enum != nil {
+			return enum, enum, nil
+		}
 		return graphql.String, graphql.String, nil
 	case "integer":
 		return graphql.Int, graphql.Int, nil
@@ -76,6 +133,9 @@ func (c *Converter) handleRefType(schema spec.Schema, definitions map[string]*sp
 	}
 
 	refKey := schema.AllOf[0].Ref.String()
+	if scalar, ok := refScalars[refKey]; ok {
+		return scalar, scalar, nil
+	}
 
 	if c.registry.IsProcessing(refKey) {
 		if output, input := c.registry.Get(refKey); output != nil {
@@ -116,6 +176,11 @@ func (c *Converter) handleArrayType(schema spec.Schema, definitions map[string]*
 	if err != nil {
 		return nil, nil, err
 	}
+	// Map entries are listed by the resolver of the field, which does not
+	// reach into lists, so lists of maps stay JSON.
+	if IsMapEntries(itemType) {
+		return graphql.NewList(JSONScalar), graphql.NewList(JSONScalar), nil
+	}
 	return graphql.NewList(itemType), graphql.NewList(inputItemType), nil
 }
 
@@ -123,7 +188,7 @@ func (c *Converter) handleObjectType(fieldSpec spec.Schema, definitions map[stri
 	// NOTE: When a schema has both Properties and x-kubernetes-preserve-unknown-fields: true,
 	// only the declared properties are exposed in the GraphQL type. Any additional undeclared
 	// fields are silently dropped from responses. Supporting the full preserve-unknown-fields
-	// semantics would require a catch-all JSON field or falling back to JSONStringScalar.
+	// semantics would require a catch-all JSON field or falling back to JSONScalar.
 	if len(fieldSpec.Properties) > 0 {
 		return c.handleNestedObject(fieldSpec, definitions, typePrefix, fieldPath)
 	}
@@ -132,9 +197,15 @@ func (c *Converter) handleObjectType(fieldSpec spec.Schema, definitions map[stri
 		if len(fieldSpec.AdditionalProperties.Schema.Type) == 1 && fieldSpec.AdditionalProperties.Schema.Type[0] == "string" {
 			return StringMapScalar, StringMapScalar, nil
 		}
+		output, input, err := c.handleObjectMap(*fieldSpec.AdditionalProperties.Schema, definitions, typePrefix, fieldPath)
+		if err != nil || output != nil {
+			return output, input, err
+		}
 	}
 
-	return JSONStringScalar, JSONStringScalar, nil
+	// Open-ended objects (no declared properties, e.g. x-kubernetes-preserve-unknown-fields)
+	// are passed through as structured JSON.
+	return JSONScalar, JSONScalar, nil
 }
 
 func (c *Converter) handleNestedObject(fieldSpec spec.Schema, definitions map[string]*spec.Schema, typePrefix string, fieldPath []string) (graphql.Output, graphql.Input, error) {
@@ -166,7 +237,7 @@ func (c *Converter) handleNestedObject(fieldSpec spec.Schema, definitions map[st
 	})
 
 	newInputType := graphql.NewInputObject(graphql.InputObjectConfig{
-		Name:        SanitizeFieldName(typeName) + "_Input",
+		Name:        InputTypeName(typeName),
 		Description: fieldSpec.Description,
 		Fields:      nestedInputFields,
 	})
@@ -175,3 +246,70 @@ func (c *Converter) handleNestedObject(fieldSpec spec.Schema, definitions map[st
 
 	return newType, newInputType, nil
 }
+
+// enumType returns a GraphQL enum for a string schema that declares enum
+// values, so invalid values are rejected during query validation. It returns
+// nil if the schema has no enum or any value is not a valid GraphQL enum value
+// name (e.g. "" or "kubernetes.io/foo"), in which case the field stays a String.
+func (c *Converter) enumType(schema spec.Schema, typePrefix string, fieldPath []string) *graphql.Enum {
+	if len(schema.Enum) == 0 {
+		return nil
+	}
+
+	values := graphql.EnumValueConfigMap{}
+	for _, v := range schema.Enum {
+		value, ok := v.(string)
+		if !ok || !enumValueNameRegex.MatchString(value) || value == "true" || value == "false" || value == "null" {
+			return nil
+		}
+		values[value] = &graphql.EnumValueConfig{Value: value}
+	}
+
+	typeName := SanitizeFieldName(GenerateTypeName(typePrefix, fieldPath) + "Enum")
+	if enum := c.registry.GetEnum(typeName); enum != nil {
+		return enum
+	}
+
+	enum := graphql.NewEnum(graphql.EnumConfig{
+		Name:        typeName,
+		Description: schema.Description,
+		Values:      values,
+	})
+	c.registry.RegisterEnum(typeName, enum)
+	return enum
+}
+
+// inputDefault converts an OpenAPI default to a GraphQL input default so the
+// field can be omitted in create mutations. Built-in types declare "" and {} defaults
+// for every non-pointer string and struct; these carry no values and would
+// make required fields optional, so they are dropped.
+func inputDefault(value any, inputType graphql.Input) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		if v == "" {
+			return nil
+		}
+	case map[string]any:
+		if len(v) == 0 {
+			return nil
+		}
+	case float64:
+		// JSON decodes all numbers as float64; Int fields need an int.
+		if named := graphql.GetNamed(inputType); named == graphql.Int && v == math.Trunc(v) {
+			return int(v)
+		}
+	}
+	return value
+}
+
+// isIntOrString reports whether a schema accepts both integers and strings,
+// either via the x-kubernetes-int-or-string extension used by CRDs or the
+// int-or-string format used by built-in types.
+func isIntOrString(schema spec.Schema) bool {
+	if extensions.IntOrString(&schema) {
+		return true
+	}
+	return schema.Format == "int-or-string"
+}
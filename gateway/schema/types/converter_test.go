@@ -1,9 +1,11 @@
 package types_test
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/graphql-go/graphql"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
 
 	"k8s.io/kube-openapi/pkg/validation/spec"
@@ -192,3 +194,478 @@ func TestConvert_FieldNamedInputNoCollision(t *testing.T) {
 		t.Errorf("output type for 'input' field (%q) must not equal parent input type (%q)", inputFieldInTemplates.Type.Name(), inputItemType.Name())
 	}
 }
+
+// TestConvert_QuantityRefUsesQuantityScalar verifies that fields referencing
+// resource.Quantity are mapped to the Quantity scalar instead of JSONString.
+func TestConvert_QuantityRefUsesQuantityScalar(t *testing.T) {
+	converter := types.NewConverter(types.NewRegistry())
+
+	quantityRef := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			AllOf: []spec.Schema{{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef(types.QuantityRef)}}},
+		},
+	}
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{
+				"capacity": quantityRef,
+				"steps": {
+					SchemaProps: spec.SchemaProps{
+						Type:  []string{"array"},
+						Items: &spec.SchemaOrArray{Schema: &quantityRef},
+					},
+				},
+			},
+		},
+	}
+	definitions := map[string]*spec.Schema{
+		types.QuantityRef: {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+	}
+
+	fields, inputFields, err := converter.ConvertFields(schema, definitions, "TestType")
+	if err != nil {
+		t.Fatalf("ConvertFields() error = %v", err)
+	}
+
+	if got := fields["capacity"].Type.Name(); got != "Quantity" {
+		t.Errorf("output type = %q, want %q", got, "Quantity")
+	}
+	if got := inputFields["capacity"].Type.Name(); got != "Quantity" {
+		t.Errorf("input type = %q, want %q", got, "Quantity")
+	}
+
+	listType, ok := fields["steps"].Type.(*graphql.List)
+	if !ok {
+		t.Fatal("expected steps to be a list type")
+	}
+	if got := listType.OfType.Name(); got != "Quantity" {
+		t.Errorf("list item type = %q, want %q", got, "Quantity")
+	}
+}
+
+// TestConvert_TimeRefsUseTimeScalar verifies that metav1.Time and
+// metav1.MicroTime references are mapped to the Time scalar.
+func TestConvert_TimeRefsUseTimeScalar(t *testing.T) {
+	converter := types.NewConverter(types.NewRegistry())
+
+	refTo := func(ref string) spec.Schema {
+		return spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				AllOf: []spec.Schema{{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef(ref)}}},
+			},
+		}
+	}
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{
+				"creationTimestamp": refTo(types.TimeRef),
+				"eventTime":         refTo(types.MicroTimeRef),
+			},
+		},
+	}
+	timeDef := &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"string"}, Format: "date-time"}}
+	definitions := map[string]*spec.Schema{
+		types.TimeRef:      timeDef,
+		types.MicroTimeRef: timeDef,
+	}
+
+	fields, inputFields, err := converter.ConvertFields(schema, definitions, "TestType")
+	if err != nil {
+		t.Fatalf("ConvertFields() error = %v", err)
+	}
+
+	for _, name := range []string{"creationTimestamp", "eventTime"} {
+		if got := fields[name].Type.Name(); got != "Time" {
+			t.Errorf("%s output type = %q, want %q", name, got, "Time")
+		}
+		if got := inputFields[name].Type.Name(); got != "Time" {
+			t.Errorf("%s input type = %q, want %q", name, got, "Time")
+		}
+	}
+}
+
+// TestConvert_IntOrString verifies that int-or-string fields, whether marked
+// with the CRD extension, the built-in format or a reference to
+// intstr.IntOrString, are mapped to the IntOrString scalar.
+func TestConvert_IntOrString(t *testing.T) {
+	converter := types.NewConverter(types.NewRegistry())
+
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{
+				"maxSurge": {
+					VendorExtensible: spec.VendorExtensible{
+						Extensions: spec.Extensions{extensions.IntOrStringKey: true},
+					},
+					SchemaProps: spec.SchemaProps{
+						AnyOf: []spec.Schema{
+							{SchemaProps: spec.SchemaProps{Type: []string{"integer"}}},
+							{SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+						},
+					},
+				},
+				"targetPort": {
+					SchemaProps: spec.SchemaProps{Type: []string{"string"}, Format: "int-or-string"},
+				},
+				"port": {
+					SchemaProps: spec.SchemaProps{
+						AllOf: []spec.Schema{{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef(types.IntOrStringRef)}}},
+					},
+				},
+			},
+		},
+	}
+	definitions := map[string]*spec.Schema{
+		types.IntOrStringRef: {SchemaProps: spec.SchemaProps{Type: []string{"string"}, Format: "int-or-string"}},
+	}
+
+	fields, inputFields, err := converter.ConvertFields(schema, definitions, "TestType")
+	if err != nil {
+		t.Fatalf("ConvertFields() error = %v", err)
+	}
+
+	for _, name := range []string{"maxSurge", "targetPort", "port"} {
+		if got := fields[name].Type.Name(); got != "IntOrString" {
+			t.Errorf("%s output type = %q, want %q", name, got, "IntOrString")
+		}
+		if got := inputFields[name].Type.Name(); got != "IntOrString" {
+			t.Errorf("%s input type = %q, want %q", name, got, "IntOrString")
+		}
+	}
+}
+
+// TestConvert_OpenEndedObjectUsesJSONScalar verifies that objects without
+// declared properties, such as those marked x-kubernetes-preserve-unknown-fields,
+// are mapped to the JSON scalar so their content round-trips as structured data.
+func TestConvert_OpenEndedObjectUsesJSONScalar(t *testing.T) {
+	converter := types.NewConverter(types.NewRegistry())
+
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{
+				"values": {
+					VendorExtensible: spec.VendorExtensible{
+						Extensions: spec.Extensions{"x-kubernetes-preserve-unknown-fields": true},
+					},
+					SchemaProps: spec.SchemaProps{Type: []string{"object"}},
+				},
+				"labels": {
+					SchemaProps: spec.SchemaProps{
+						Type: []string{"object"},
+						AdditionalProperties: &spec.SchemaOrBool{
+							Schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	fields, inputFields, err := converter.ConvertFields(schema, map[string]*spec.Schema{}, "TestType")
+	if err != nil {
+		t.Fatalf("ConvertFields() error = %v", err)
+	}
+
+	if got := fields["values"].Type.Name(); got != "JSON" {
+		t.Errorf("output type = %q, want %q", got, "JSON")
+	}
+	if got := inputFields["values"].Type.Name(); got != "JSON" {
+		t.Errorf("input type = %q, want %q", got, "JSON")
+	}
+	if got := fields["labels"].Type.Name(); got != "StringMap_Input" {
+		t.Errorf("string map output type = %q, want %q", got, "StringMap_Input")
+	}
+}
+
+// TestConvert_EnumValues verifies that string fields declaring enum values are
+// mapped to a GraphQL enum shared by output and input types, and that enums
+// with values that are not valid GraphQL names stay plain strings.
+func TestConvert_EnumValues(t *testing.T) {
+	converter := types.NewConverter(types.NewRegistry())
+
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{
+				"phase": {
+					SchemaProps: spec.SchemaProps{Type: []string{"string"}, Enum: []any{"Pending", "Running", "Failed"}},
+				},
+				"policy": {
+					SchemaProps: spec.SchemaProps{Type: []string{"string"}, Enum: []any{"", "example.com/policy"}},
+				},
+			},
+		},
+	}
+
+	fields, inputFields, err := converter.ConvertFields(schema, map[string]*spec.Schema{}, "TestType")
+	if err != nil {
+		t.Fatalf("ConvertFields() error = %v", err)
+	}
+
+	enum, ok := fields["phase"].Type.(*graphql.Enum)
+	if !ok {
+		t.Fatalf("output type = %T, want *graphql.Enum", fields["phase"].Type)
+	}
+	if got := enum.Name(); got != "TestTypePhaseEnum" {
+		t.Errorf("enum name = %q, want %q", got, "TestTypePhaseEnum")
+	}
+	if inputFields["phase"].Type != enum {
+		t.Errorf("input type = %v, want the output enum", inputFields["phase"].Type)
+	}
+	var values []string
+	for _, v := range enum.Values() {
+		values = append(values, v.Name)
+	}
+	if len(values) != 3 {
+		t.Errorf("enum values = %v, want Pending, Running and Failed", values)
+	}
+	if got := enum.Serialize("Running"); got != "Running" {
+		t.Errorf("Serialize(Running) = %v, want Running", got)
+	}
+
+	if got := fields["policy"].Type.Name(); got != "String" {
+		t.Errorf("invalid enum output type = %q, want %q", got, "String")
+	}
+}
+
+// TestConvert_RequiredInputFields verifies that fields listed in the schema's
+// required list are non-null in the input types of create mutations only,
+// while the shared input types and the output types stay nullable.
+func TestConvert_RequiredInputFields(t *testing.T) {
+	converter := types.NewConverter(types.NewRegistry())
+
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Required: []string{"spec"},
+			Properties: map[string]spec.Schema{
+				"spec": {
+					SchemaProps: spec.SchemaProps{
+						Type:     []string{"object"},
+						Required: []string{"image"},
+						Properties: map[string]spec.Schema{
+							"image":    {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+							"replicas": {SchemaProps: spec.SchemaProps{Type: []string{"integer"}}},
+						},
+					},
+				},
+				"status": {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+			},
+		},
+	}
+
+	fields, inputFields, err := converter.ConvertFields(schema, map[string]*spec.Schema{}, "TestType")
+	if err != nil {
+		t.Fatalf("ConvertFields() error = %v", err)
+	}
+
+	if _, ok := fields["spec"].Type.(*graphql.NonNull); ok {
+		t.Errorf("output field type = %v, want nullable", fields["spec"].Type)
+	}
+	for name, field := range inputFields {
+		if _, ok := field.Type.(*graphql.NonNull); ok {
+			t.Errorf("shared input field %s type = %v, want nullable", name, field.Type)
+		}
+	}
+	specInput := inputFields["spec"].Type.(*graphql.InputObject)
+	if _, ok := specInput.Fields()["image"].Type.(*graphql.NonNull); ok {
+		t.Errorf("nested shared input field type = %v, want nullable", specInput.Fields()["image"].Type)
+	}
+
+	inputType := graphql.NewInputObject(graphql.InputObjectConfig{Name: types.InputTypeName("TestType"), Fields: inputFields})
+	createFields := converter.CreateInputType(inputType).Fields()
+	if got := createFields["spec"].Type.String(); got != "TestTypeSpec_CreateInput!" {
+		t.Errorf("required create input field type = %q, want %q", got, "TestTypeSpec_CreateInput!")
+	}
+	if got := createFields["status"].Type.String(); got != "String" {
+		t.Errorf("optional create input field type = %q, want %q", got, "String")
+	}
+
+	nested := createFields["spec"].Type.(*graphql.NonNull).OfType.(*graphql.InputObject).Fields()
+	if got := nested["image"].Type.String(); got != "String!" {
+		t.Errorf("nested required create input field type = %q, want %q", got, "String!")
+	}
+	if got := nested["replicas"].Type.String(); got != "Int" {
+		t.Errorf("nested optional create input field type = %q, want %q", got, "Int")
+	}
+}
+
+// TestConvert_CreateInputTypeUnchanged verifies that input types without
+// required fields are used by create mutations as is.
+func TestConvert_CreateInputTypeUnchanged(t *testing.T) {
+	converter := types.NewConverter(types.NewRegistry())
+
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{
+				"spec": {SchemaProps: spec.SchemaProps{
+					Type:       []string{"object"},
+					Properties: map[string]spec.Schema{"image": {SchemaProps: spec.SchemaProps{Type: []string{"string"}}}},
+				}},
+			},
+		},
+	}
+
+	_, inputFields, err := converter.ConvertFields(schema, map[string]*spec.Schema{}, "TestType")
+	if err != nil {
+		t.Fatalf("ConvertFields() error = %v", err)
+	}
+
+	inputType := graphql.NewInputObject(graphql.InputObjectConfig{Name: types.InputTypeName("TestType"), Fields: inputFields})
+	if got := converter.CreateInputType(inputType); got != inputType {
+		t.Errorf("CreateInputType() = %v, want the shared input type", got)
+	}
+}
+
+// TestConvert_InputDefaults verifies that OpenAPI defaults become defaults of
+// the input fields of create mutations only, with integer defaults converted from their JSON float form and
+// empty string and object defaults dropped.
+func TestConvert_InputDefaults(t *testing.T) {
+	converter := types.NewConverter(types.NewRegistry())
+
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{
+				"replicas": {SchemaProps: spec.SchemaProps{Type: []string{"integer"}, Default: float64(1)}},
+				"ratio":    {SchemaProps: spec.SchemaProps{Type: []string{"number"}, Default: 0.5}},
+				"mode":     {SchemaProps: spec.SchemaProps{Type: []string{"string"}, Default: "Auto"}},
+				"paused":   {SchemaProps: spec.SchemaProps{Type: []string{"boolean"}, Default: false}},
+				"template": {SchemaProps: spec.SchemaProps{Type: []string{"object"}, Default: map[string]any{}}},
+				"name":     {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+				"kind":     {SchemaProps: spec.SchemaProps{Type: []string{"string"}, Default: ""}},
+			},
+		},
+	}
+
+	_, inputFields, err := converter.ConvertFields(schema, map[string]*spec.Schema{}, "TestType")
+	if err != nil {
+		t.Fatalf("ConvertFields() error = %v", err)
+	}
+	for name, field := range inputFields {
+		if field.DefaultValue != nil {
+			t.Errorf("shared input field %s default = %#v, want none", name, field.DefaultValue)
+		}
+	}
+
+	inputType := graphql.NewInputObject(graphql.InputObjectConfig{Name: types.InputTypeName("TestType"), Fields: inputFields})
+	createFields := converter.CreateInputType(inputType).Fields()
+	want := map[string]any{
+		"replicas": 1,
+		"ratio":    0.5,
+		"mode":     "Auto",
+		"paused":   false,
+		"template": nil,
+		"name":     nil,
+		"kind":     nil,
+	}
+	for name, wantDefault := range want {
+		if got := createFields[name].DefaultValue; got != wantDefault {
+			t.Errorf("%s default = %#v, want %#v", name, got, wantDefault)
+		}
+	}
+}
+
+// TestConvert_InputConstraints verifies that validation constraints are
+// appended to the descriptions of input fields and left out of output fields.
+func TestConvert_InputConstraints(t *testing.T) {
+	converter := types.NewConverter(types.NewRegistry())
+
+	minReplicas, maxReplicas := float64(1), float64(10)
+	maxLength := int64(63)
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{
+				"replicas": {SchemaProps: spec.SchemaProps{Type: []string{"integer"}, Description: "Number of replicas.", Minimum: &minReplicas, Maximum: &maxReplicas}},
+				"name":     {SchemaProps: spec.SchemaProps{Type: []string{"string"}, MaxLength: &maxLength, Pattern: "^[a-z]+$"}},
+				"policy":   {SchemaProps: spec.SchemaProps{Type: []string{"string"}, Enum: []any{"Always", "IfNotPresent"}}},
+				"path":     {SchemaProps: spec.SchemaProps{Type: []string{"string"}, Enum: []any{"/", "/tmp"}}},
+				"plain":    {SchemaProps: spec.SchemaProps{Type: []string{"string"}, Description: "No constraints."}},
+			},
+		},
+	}
+
+	fields, inputFields, err := converter.ConvertFields(schema, map[string]*spec.Schema{}, "TestType")
+	if err != nil {
+		t.Fatalf("ConvertFields() error = %v", err)
+	}
+
+	want := map[string]string{
+		"replicas": "Number of replicas.\n\nConstraints: minimum 1, maximum 10.",
+		"name":     "Constraints: max length 63, pattern `^[a-z]+$`.",
+		"policy":   "",
+		"path":     `Constraints: one of "/", "/tmp".`,
+		"plain":    "No constraints.",
+	}
+	for name, wantDescription := range want {
+		if got := inputFields[name].Description; got != wantDescription {
+			t.Errorf("%s input description = %q, want %q", name, got, wantDescription)
+		}
+	}
+	if got := fields["replicas"].Description; got != "Number of replicas." {
+		t.Errorf("replicas output description = %q, want %q", got, "Number of replicas.")
+	}
+}
+
+// TestConvert_ObjectMapUsesEntries verifies that maps with object values are
+// listed as key and value entries for output and input, while maps of other
+// values stay JSON.
+func TestConvert_ObjectMapUsesEntries(t *testing.T) {
+	converter := types.NewConverter(types.NewRegistry())
+
+	port := spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:       []string{"object"},
+		Properties: map[string]spec.Schema{"port": {SchemaProps: spec.SchemaProps{Type: []string{"integer"}}}},
+	}}
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{
+				"ports": {SchemaProps: spec.SchemaProps{
+					Type:                 []string{"object"},
+					AdditionalProperties: &spec.SchemaOrBool{Schema: &port},
+				}},
+				"counts": {SchemaProps: spec.SchemaProps{
+					Type:                 []string{"object"},
+					AdditionalProperties: &spec.SchemaOrBool{Schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"integer"}}}},
+				}},
+			},
+		},
+	}
+
+	fields, inputFields, err := converter.ConvertFields(schema, map[string]*spec.Schema{}, "TestType")
+	if err != nil {
+		t.Fatalf("ConvertFields() error = %v", err)
+	}
+
+	if got := fields["ports"].Type.String(); got != "[TestTypePortsMapEntry!]" {
+		t.Errorf("output type = %q, want %q", got, "[TestTypePortsMapEntry!]")
+	}
+	if got := inputFields["ports"].Type.String(); got != "[TestTypePortsMapEntry_Input!]" {
+		t.Errorf("input type = %q, want %q", got, "[TestTypePortsMapEntry_Input!]")
+	}
+	if !types.IsMapEntries(fields["ports"].Type) || !types.IsMapEntries(inputFields["ports"].Type) {
+		t.Error("ports is not recognized as map entries")
+	}
+	if got := fields["counts"].Type.Name(); got != "JSON" {
+		t.Errorf("map of integers output type = %q, want %q", got, "JSON")
+	}
+
+	entries, err := fields["ports"].Resolve(graphql.ResolveParams{
+		Source: map[string]any{"ports": map[string]any{"https": map[string]any{"port": 443}, "http": map[string]any{"port": 80}}},
+		Info:   graphql.ResolveInfo{FieldName: "ports"},
+	})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	want := []map[string]any{
+		{"key": "http", "value": map[string]any{"port": 80}},
+		{"key": "https", "value": map[string]any{"port": 443}},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries = %v, want %v", entries, want)
+	}
+
+	inputType := graphql.NewInputObject(graphql.InputObjectConfig{Name: "TestType_Input", Fields: inputFields})
+	input := map[string]any{"ports": []any{map[string]any{"key": "http", "value": map[string]any{"port": 80}}}, "other": "kept"}
+	wantInput := map[string]any{"ports": map[string]any{"http": map[string]any{"port": 80}}, "other": "kept"}
+	if got := types.InputToObject(input, inputType, nil); !reflect.DeepEqual(got, wantInput) {
+		t.Errorf("InputToObject() = %v, want %v", got, wantInput)
+	}
+}
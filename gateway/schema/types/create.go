@@ -0,0 +1,87 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// createInputSuffix names the input object types of create mutations.
+const createInputSuffix = "_CreateInput"
+
+// createField is what the field of an input object type of create mutations
+// adds to the field of the input object type the other mutations share.
+type createField struct {
+	required     bool
+	defaultValue any
+}
+
+// CreateInputType returns the input type of create mutations for an input
+// object type: its required fields are non-null and its fields with an
+// OpenAPI default default to it. Update, upsert and apply keep input, so
+// their merge patches and apply intents only hold the fields that were set.
+// Input object types without required or defaulted fields, nested ones
+// included, are returned as is.
+func (c *Converter) CreateInputType(input *graphql.InputObject) *graphql.InputObject {
+	createInput, _ := c.createInput(input).(*graphql.InputObject)
+	return createInput
+}
+
+func (c *Converter) createInput(t graphql.Input) graphql.Input {
+	switch t := t.(type) {
+	case *graphql.NonNull:
+		if ofType := c.createInput(t.OfType); ofType != t.OfType {
+			return graphql.NewNonNull(ofType)
+		}
+	case *graphql.List:
+		if ofType := c.createInput(t.OfType); ofType != t.OfType {
+			return graphql.NewList(ofType)
+		}
+	case *graphql.InputObject:
+		return c.createInputObject(t)
+	}
+	return t
+}
+
+// createInputObject derives the input object type of create mutations of
+// input. Input types are never recursive, as the converter falls back to
+// JSON for circular references, so the fields are derived first.
+func (c *Converter) createInputObject(input *graphql.InputObject) *graphql.InputObject {
+	if createInput, ok := c.registry.getCreateInput(input.Name()); ok {
+		return createInput
+	}
+
+	createFields := c.registry.getCreateFields(input.Name())
+	fields := make(graphql.InputObjectConfigFieldMap, len(input.Fields()))
+	changed := false
+	for name, field := range input.Fields() {
+		fieldType := c.createInput(field.Type)
+		if _, nonNull := fieldType.(*graphql.NonNull); createFields[name].required && !nonNull {
+			fieldType = graphql.NewNonNull(fieldType)
+		}
+		defaultValue := field.DefaultValue
+		if createFields[name].defaultValue != nil {
+			defaultValue = createFields[name].defaultValue
+			changed = true
+		}
+		changed = changed || fieldType != field.Type
+		fields[name] = &graphql.InputObjectFieldConfig{
+			Type:         fieldType,
+			Description:  field.Description(),
+			DefaultValue: defaultValue,
+		}
+	}
+
+	createInput := input
+	if changed {
+		name := strings.TrimSuffix(input.Name(), "_Input") + createInputSuffix
+		createInput = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name:        name,
+			Description: input.Description(),
+			Fields:      fields,
+		})
+		c.names.alias(name, input.Name())
+	}
+	c.registry.registerCreateInput(input.Name(), createInput)
+	return createInput
+}
@@ -0,0 +1,66 @@
+package types
+
+import (
+	"slices"
+
+	"github.com/graphql-go/graphql"
+)
+
+// LiveDirective marks a query as a live query. Sent as a subscription request
+// (Accept: text/event-stream), the gateway re-executes the query periodically
+// and sends a result whenever it differs from the previous one. Other requests
+// execute the query once.
+var LiveDirective = graphql.NewDirective(graphql.DirectiveConfig{
+	Name:        "live",
+	Description: "Re-executes the query over Server-Sent Events and sends the result whenever it changes.",
+	Locations:   []string{graphql.DirectiveLocationQuery},
+})
+
+// DeferDirective marks a fragment whose fields may be delivered after the
+// rest of the query result. Sent with "Accept: multipart/mixed" or as a
+// subscription request, the gateway answers with the initial result first
+// and the fragment later. Other requests execute the query at once.
+var DeferDirective = graphql.NewDirective(graphql.DirectiveConfig{
+	Name:        "defer",
+	Description: "Delivers the fragment after the rest of the result with incremental delivery.",
+	Locations:   []string{graphql.DirectiveLocationFragmentSpread, graphql.DirectiveLocationInlineFragment},
+	Args: graphql.FieldConfigArgument{
+		"if": &graphql.ArgumentConfig{
+			Type:         graphql.Boolean,
+			DefaultValue: true,
+			Description:  "Defers the fragment if true.",
+		},
+		"label": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Identifies the delivered fragment in the response.",
+		},
+	},
+})
+
+// StreamDirective marks a list field whose items after the first
+// initialCount may be delivered after the rest of the query result, like
+// deferred fragments.
+var StreamDirective = graphql.NewDirective(graphql.DirectiveConfig{
+	Name:        "stream",
+	Description: "Delivers the items of the list after the first initialCount after the rest of the result with incremental delivery.",
+	Locations:   []string{graphql.DirectiveLocationField},
+	Args: graphql.FieldConfigArgument{
+		"if": &graphql.ArgumentConfig{
+			Type:         graphql.Boolean,
+			DefaultValue: true,
+			Description:  "Streams the list if true.",
+		},
+		"label": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Identifies the delivered items in the response.",
+		},
+		"initialCount": &graphql.ArgumentConfig{
+			Type:         graphql.Int,
+			DefaultValue: 0,
+			Description:  "Number of items delivered with the initial result.",
+		},
+	},
+})
+
+// Directives are the directives of generated schemas.
+var Directives = append(slices.Clone(graphql.SpecifiedDirectives), LiveDirective, DeferDirective, StreamDirective)
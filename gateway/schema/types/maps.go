@@ -0,0 +1,184 @@
+package types
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// mapEntrySuffix names the entry types of maps with object values.
+const mapEntrySuffix = "MapEntry"
+
+// handleObjectMap converts a map with structured values, such as
+// map[string]ContainerStatus, to a list of { key value } entries sorted by
+// key. Maps of other values return nil types and stay JSON.
+func (c *Converter) handleObjectMap(valueSchema spec.Schema, definitions map[string]*spec.Schema, typePrefix string, fieldPath []string) (graphql.Output, graphql.Input, error) {
+	typeName := SanitizeFieldName(GenerateTypeName(typePrefix, fieldPath) + mapEntrySuffix)
+	if output, input := c.registry.Get(typeName); output != nil {
+		return graphql.NewList(graphql.NewNonNull(output)), graphql.NewList(graphql.NewNonNull(input)), nil
+	}
+
+	valueType, valueInputType, err := c.convert(valueSchema, definitions, typePrefix, append(slices.Clone(fieldPath), "value"))
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := valueType.(*graphql.Object); !ok && !IsMapEntries(valueType) {
+		return nil, nil, nil
+	}
+
+	output := graphql.NewObject(graphql.ObjectConfig{
+		Name:        typeName,
+		Description: "An entry of a map, listed in the order of its keys.",
+		Fields: graphql.Fields{
+			"key":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"value": &graphql.Field{Type: valueType, Description: valueSchema.Description, Resolve: resolveMap(valueType)},
+		},
+	})
+	input := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        typeName + "_Input",
+		Description: "An entry of a map. Entries with the same key overwrite each other.",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"key":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"value": &graphql.InputObjectFieldConfig{Type: valueInputType, Description: valueSchema.Description},
+		},
+	})
+	c.registry.Register(typeName, output, input)
+
+	return graphql.NewList(graphql.NewNonNull(output)), graphql.NewList(graphql.NewNonNull(input)), nil
+}
+
+// IsMapEntries reports whether t is the list of entries a map with object
+// values is converted to, either as output or as input type.
+func IsMapEntries(t graphql.Type) bool {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType
+	}
+	list, ok := t.(*graphql.List)
+	if !ok {
+		return false
+	}
+
+	var fieldNames []string
+	switch entry := graphql.GetNamed(list.OfType).(type) {
+	case *graphql.Object:
+		if !strings.HasSuffix(entry.Name(), mapEntrySuffix) {
+			return false
+		}
+		fieldNames = slices.Sorted(maps.Keys(entry.Fields()))
+	case *graphql.InputObject:
+		if !strings.HasSuffix(entry.Name(), mapEntrySuffix+"_Input") && !strings.HasSuffix(entry.Name(), mapEntrySuffix+createInputSuffix) {
+			return false
+		}
+		fieldNames = slices.Sorted(maps.Keys(entry.Fields()))
+	default:
+		return false
+	}
+	return slices.Equal(fieldNames, []string{"key", "value"})
+}
+
+// resolveMap returns the resolver of a field of type t, which lists the
+// entries of a map for map entry types and is nil otherwise.
+func resolveMap(t graphql.Output) graphql.FieldResolveFn {
+	if !IsMapEntries(t) {
+		return nil
+	}
+	return func(p graphql.ResolveParams) (any, error) {
+		value, err := graphql.DefaultResolveFn(p)
+		if err != nil {
+			return nil, err
+		}
+		return mapEntries(value), nil
+	}
+}
+
+// resolveProperty returns the resolver of the field name of type t for a
+// property. It is resolveMap(t) if the field is named like the property, and
+// reads the property from the object otherwise.
+func resolveProperty(property, name string, t graphql.Output) graphql.FieldResolveFn {
+	if property == name {
+		return resolveMap(t)
+	}
+	entries := IsMapEntries(t)
+	return func(p graphql.ResolveParams) (any, error) {
+		obj, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		if entries {
+			return mapEntries(obj[property]), nil
+		}
+		return obj[property], nil
+	}
+}
+
+// mapEntries lists the entries of a map sorted by key. Other values are
+// returned as they are.
+func mapEntries(value any) any {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+	entries := make([]map[string]any, 0, len(m))
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		entries = append(entries, map[string]any{"key": key, "value": m[key]})
+	}
+	return entries
+}
+
+// InputToObject converts the value of an argument of type t back to the
+// object Kubernetes expects: map entries become maps again and fields named
+// differently than their properties, as recorded in names, are renamed.
+func InputToObject(value any, t graphql.Input, names *FieldNames) any {
+	if value == nil || t == nil {
+		return value
+	}
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType
+	}
+
+	switch t := t.(type) {
+	case *graphql.List:
+		items, ok := value.([]any)
+		if !ok {
+			return value
+		}
+		if IsMapEntries(t) {
+			entryType := graphql.GetNamed(t.OfType).(*graphql.InputObject)
+			m := make(map[string]any, len(items))
+			for _, item := range items {
+				entry, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+				key, _ := entry["key"].(string)
+				m[key] = InputToObject(entry["value"], entryType.Fields()["value"].Type, names)
+			}
+			return m
+		}
+		converted := make([]any, len(items))
+		for i, item := range items {
+			converted[i] = InputToObject(item, t.OfType, names)
+		}
+		return converted
+	case *graphql.InputObject:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		fields := t.Fields()
+		converted := make(map[string]any, len(obj))
+		for name, fieldValue := range obj {
+			if field, ok := fields[name]; ok {
+				fieldValue = InputToObject(fieldValue, field.Type, names)
+			}
+			converted[names.Property(t.Name(), name)] = fieldValue
+		}
+		return converted
+	default:
+		return value
+	}
+}
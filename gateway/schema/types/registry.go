@@ -32,11 +32,19 @@ type TypeEntry struct {
 type Registry struct {
 	mu    sync.RWMutex
 	types map[string]*TypeEntry
+	enums map[string]*graphql.Enum
+	// createFields and createInputs are keyed by the names of the input
+	// object types the other mutations share.
+	createFields map[string]map[string]createField
+	createInputs map[string]*graphql.InputObject
 }
 
 func NewRegistry() *Registry {
 	return &Registry{
-		types: make(map[string]*TypeEntry),
+		types:        make(map[string]*TypeEntry),
+		enums:        make(map[string]*graphql.Enum),
+		createFields: make(map[string]map[string]createField),
+		createInputs: make(map[string]*graphql.InputObject),
 	}
 }
 
@@ -61,6 +69,59 @@ func (r *Registry) Get(key string) (*graphql.Object, *graphql.InputObject) {
 	return entry.Output, entry.Input
 }
 
+// RegisterEnum stores an enum type under key. Enums are used for both output
+// and input, so a single type is kept per key.
+func (r *Registry) RegisterEnum(key string, enum *graphql.Enum) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.enums[key] = enum
+}
+
+func (r *Registry) GetEnum(key string) *graphql.Enum {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.enums[key]
+}
+
+// registerCreateFields stores the fields of the input object type inputType
+// that differ in create mutations.
+func (r *Registry) registerCreateFields(inputType string, fields map[string]createField) {
+	if len(fields) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.createFields[inputType] = fields
+}
+
+func (r *Registry) getCreateFields(inputType string) map[string]createField {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.createFields[inputType]
+}
+
+// registerCreateInput stores the input object type of create mutations
+// derived from the input object type inputType.
+func (r *Registry) registerCreateInput(inputType string, createInput *graphql.InputObject) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.createInputs[inputType] = createInput
+}
+
+func (r *Registry) getCreateInput(inputType string) (*graphql.InputObject, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	createInput, ok := r.createInputs[inputType]
+	return createInput, ok
+}
+
 func (r *Registry) IsProcessing(key string) bool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
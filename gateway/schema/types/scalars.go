@@ -2,9 +2,14 @@ package types
 
 import (
 	"encoding/json"
+	"math"
+	"strconv"
+	"time"
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/language/ast"
+
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // JSONStringScalar is a GraphQL scalar for JSON-serialized string representation of any object.
@@ -44,6 +49,56 @@ var JSONStringScalar = graphql.NewScalar(graphql.ScalarConfig{
 	},
 })
 
+// JSONScalar is a GraphQL scalar for arbitrary JSON values. Unlike
+// JSONStringScalar, values are returned and accepted as structured JSON
+// rather than as strings, so objects round-trip unchanged.
+var JSONScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "JSON",
+	Description: "An arbitrary JSON value.",
+	Serialize: func(value any) any {
+		return value
+	},
+	ParseValue: func(value any) any {
+		return value
+	},
+	ParseLiteral: parseJSONLiteral,
+})
+
+// parseJSONLiteral converts an inline GraphQL value to its JSON equivalent.
+func parseJSONLiteral(valueAST ast.Value) any {
+	switch value := valueAST.(type) {
+	case *ast.ObjectValue:
+		result := make(map[string]any, len(value.Fields))
+		for _, field := range value.Fields {
+			result[field.Name.Value] = parseJSONLiteral(field.Value)
+		}
+		return result
+	case *ast.ListValue:
+		result := make([]any, 0, len(value.Values))
+		for _, item := range value.Values {
+			result = append(result, parseJSONLiteral(item))
+		}
+		return result
+	case *ast.IntValue:
+		if i, err := strconv.ParseInt(value.Value, 10, 64); err == nil {
+			return i
+		}
+		f, _ := strconv.ParseFloat(value.Value, 64)
+		return f
+	case *ast.FloatValue:
+		f, _ := strconv.ParseFloat(value.Value, 64)
+		return f
+	case *ast.StringValue:
+		return value.Value
+	case *ast.BooleanValue:
+		return value.Value
+	case *ast.EnumValue:
+		return value.Value
+	default:
+		return nil
+	}
+}
+
 // StringMapScalar is a GraphQL scalar for map[string]string input types.
 var StringMapScalar = graphql.NewScalar(graphql.ScalarConfig{
 	Name:        "StringMap_Input",
@@ -114,3 +169,177 @@ var StringMapScalar = graphql.NewScalar(graphql.ScalarConfig{
 		}
 	},
 })
+
+// OpenAPI definition names of types that are mapped to custom scalars.
+const (
+	QuantityRef    = "io.k8s.apimachinery.pkg.api.resource.Quantity"
+	TimeRef        = "io.k8s.apimachinery.pkg.apis.meta.v1.Time"
+	MicroTimeRef   = "io.k8s.apimachinery.pkg.apis.meta.v1.MicroTime"
+	IntOrStringRef = "io.k8s.apimachinery.pkg.util.intstr.IntOrString"
+)
+
+// refScalars maps OpenAPI definition names to the scalars that replace them.
+var refScalars = map[string]*graphql.Scalar{
+	QuantityRef:    QuantityScalar,
+	TimeRef:        TimeScalar,
+	MicroTimeRef:   TimeScalar,
+	IntOrStringRef: IntOrStringScalar,
+}
+
+// QuantityScalar is a GraphQL scalar for resource.Quantity values such as "100m" or "1Gi".
+// Input values are validated with resource.ParseQuantity; numbers are accepted and
+// converted to their canonical string form.
+var QuantityScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Quantity",
+	Description: "A Kubernetes resource quantity, e.g. \"100m\", \"1.5\" or \"1Gi\".",
+	Serialize: func(value any) any {
+		switch v := value.(type) {
+		case string:
+			return v
+		case resource.Quantity:
+			return v.String()
+		case *resource.Quantity:
+			return v.String()
+		default:
+			if q, ok := parseQuantity(v); ok {
+				return q
+			}
+			return nil
+		}
+	},
+	ParseValue: func(value any) any {
+		if q, ok := parseQuantity(value); ok {
+			return q
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) any {
+		switch value := valueAST.(type) {
+		case *ast.StringValue:
+			return parseQuantityOrNil(value.Value)
+		case *ast.IntValue:
+			return parseQuantityOrNil(value.Value)
+		case *ast.FloatValue:
+			return parseQuantityOrNil(value.Value)
+		default:
+			return nil
+		}
+	},
+})
+
+// parseQuantity validates a quantity given as a string or number. Strings are
+// returned unchanged so the user's notation is preserved; numbers are returned
+// in canonical form.
+func parseQuantity(value any) (string, bool) {
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case int:
+		str = strconv.Itoa(v)
+	case int64:
+		str = strconv.FormatInt(v, 10)
+	case float64:
+		str = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return "", false
+	}
+
+	q, err := resource.ParseQuantity(str)
+	if err != nil {
+		return "", false
+	}
+	if _, isString := value.(string); isString {
+		return str, true
+	}
+	return q.String(), true
+}
+
+func parseQuantityOrNil(str string) any {
+	if _, err := resource.ParseQuantity(str); err != nil {
+		return nil
+	}
+	return str
+}
+
+// TimeScalar is a GraphQL scalar for metav1.Time and metav1.MicroTime values,
+// serialized as RFC3339 strings, e.g. "2024-05-01T12:00:00Z".
+var TimeScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Time",
+	Description: "An RFC3339 timestamp, e.g. \"2024-05-01T12:00:00Z\".",
+	Serialize: func(value any) any {
+		switch v := value.(type) {
+		case string:
+			return v
+		case time.Time:
+			return v.UTC().Format(time.RFC3339Nano)
+		case *time.Time:
+			if v == nil {
+				return nil
+			}
+			return v.UTC().Format(time.RFC3339Nano)
+		default:
+			return nil
+		}
+	},
+	ParseValue: func(value any) any {
+		if str, ok := value.(string); ok {
+			return parseTimeOrNil(str)
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) any {
+		if value, ok := valueAST.(*ast.StringValue); ok {
+			return parseTimeOrNil(value.Value)
+		}
+		return nil
+	},
+})
+
+// parseTimeOrNil returns str if it is a valid RFC3339 timestamp.
+func parseTimeOrNil(str string) any {
+	if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+		return nil
+	}
+	return str
+}
+
+// IntOrStringScalar is a GraphQL scalar for intstr.IntOrString values and
+// fields marked with x-kubernetes-int-or-string, such as a service's
+// targetPort. Both integers and strings are accepted and returned unchanged.
+var IntOrStringScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "IntOrString",
+	Description: "An integer or a string, e.g. 8080, \"http\" or \"25%\".",
+	Serialize:   parseIntOrString,
+	ParseValue:  parseIntOrString,
+	ParseLiteral: func(valueAST ast.Value) any {
+		switch value := valueAST.(type) {
+		case *ast.StringValue:
+			return value.Value
+		case *ast.IntValue:
+			i, err := strconv.ParseInt(value.Value, 10, 64)
+			if err != nil {
+				return nil
+			}
+			return i
+		default:
+			return nil
+		}
+	},
+})
+
+// parseIntOrString returns strings and integers unchanged. Floats without a
+// fractional part, as produced by decoding JSON variables, become integers.
+func parseIntOrString(value any) any {
+	switch v := value.(type) {
+	case string, int, int32, int64:
+		return v
+	case float64:
+		if v != math.Trunc(v) {
+			return nil
+		}
+		return int64(v)
+	default:
+		return nil
+	}
+}
@@ -2,7 +2,9 @@ package types_test
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/graphql-go/graphql/language/ast"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/schema/types"
@@ -167,3 +169,149 @@ func TestJSONStringScalar_ProperSerialization(t *testing.T) {
 		t.Errorf("Result is in Go map format, not JSON: %s", resultStr)
 	}
 }
+
+func TestQuantityScalar(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected any
+	}{
+		{name: "milli cpu", input: "100m", expected: "100m"},
+		{name: "binary suffix", input: "1Gi", expected: "1Gi"},
+		{name: "decimal string is preserved", input: "0.5", expected: "0.5"},
+		{name: "integer", input: 2, expected: "2"},
+		{name: "float", input: 0.5, expected: "500m"},
+		{name: "invalid string", input: "lots", expected: nil},
+		{name: "unsupported type", input: true, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := types.QuantityScalar.ParseValue(tt.input); got != tt.expected {
+				t.Errorf("ParseValue(%v) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	literals := []struct {
+		input    ast.Value
+		expected any
+	}{
+		{input: &ast.StringValue{Value: "250Mi"}, expected: "250Mi"},
+		{input: &ast.IntValue{Value: "3"}, expected: "3"},
+		{input: &ast.FloatValue{Value: "1.5"}, expected: "1.5"},
+		{input: &ast.StringValue{Value: "1.5.5"}, expected: nil},
+		{input: &ast.BooleanValue{Value: true}, expected: nil},
+	}
+	for _, tt := range literals {
+		if got := types.QuantityScalar.ParseLiteral(tt.input); got != tt.expected {
+			t.Errorf("ParseLiteral(%v) = %v, want %v", tt.input.GetValue(), got, tt.expected)
+		}
+	}
+
+	if got := types.QuantityScalar.Serialize(int64(4)); got != "4" {
+		t.Errorf("Serialize(4) = %v, want %q", got, "4")
+	}
+}
+
+func TestTimeScalar(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected any
+	}{
+		{name: "seconds precision", input: "2024-05-01T12:00:00Z", expected: "2024-05-01T12:00:00Z"},
+		{name: "micro precision with offset", input: "2024-05-01T12:00:00.123456+02:00", expected: "2024-05-01T12:00:00.123456+02:00"},
+		{name: "date only", input: "2024-05-01", expected: nil},
+		{name: "not a string", input: 1714564800, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := types.TimeScalar.ParseValue(tt.input); got != tt.expected {
+				t.Errorf("ParseValue(%v) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	if got := types.TimeScalar.ParseLiteral(&ast.StringValue{Value: "2024-05-01T12:00:00Z"}); got != "2024-05-01T12:00:00Z" {
+		t.Errorf("ParseLiteral() = %v, want %q", got, "2024-05-01T12:00:00Z")
+	}
+	if got := types.TimeScalar.ParseLiteral(&ast.IntValue{Value: "1"}); got != nil {
+		t.Errorf("ParseLiteral(int) = %v, want nil", got)
+	}
+
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if got := types.TimeScalar.Serialize(ts); got != "2024-05-01T12:00:00Z" {
+		t.Errorf("Serialize(time.Time) = %v, want %q", got, "2024-05-01T12:00:00Z")
+	}
+}
+
+func TestIntOrStringScalar(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected any
+	}{
+		{name: "string", input: "http", expected: "http"},
+		{name: "percentage", input: "25%", expected: "25%"},
+		{name: "int", input: 8080, expected: 8080},
+		{name: "int64", input: int64(8080), expected: int64(8080)},
+		{name: "whole float from JSON variables", input: float64(8080), expected: int64(8080)},
+		{name: "fractional float", input: 1.5, expected: nil},
+		{name: "bool", input: true, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := types.IntOrStringScalar.ParseValue(tt.input); got != tt.expected {
+				t.Errorf("ParseValue(%v) = %v (%T), want %v (%T)", tt.input, got, got, tt.expected, tt.expected)
+			}
+			if got := types.IntOrStringScalar.Serialize(tt.input); got != tt.expected {
+				t.Errorf("Serialize(%v) = %v (%T), want %v (%T)", tt.input, got, got, tt.expected, tt.expected)
+			}
+		})
+	}
+
+	if got := types.IntOrStringScalar.ParseLiteral(&ast.IntValue{Value: "443"}); got != int64(443) {
+		t.Errorf("ParseLiteral(int) = %v (%T), want 443", got, got)
+	}
+	if got := types.IntOrStringScalar.ParseLiteral(&ast.StringValue{Value: "https"}); got != "https" {
+		t.Errorf("ParseLiteral(string) = %v, want %q", got, "https")
+	}
+	if got := types.IntOrStringScalar.ParseLiteral(&ast.FloatValue{Value: "1.5"}); got != nil {
+		t.Errorf("ParseLiteral(float) = %v, want nil", got)
+	}
+}
+
+func TestJSONScalar_RoundTrip(t *testing.T) {
+	value := map[string]any{
+		"replicas": int64(3),
+		"nested":   map[string]any{"enabled": true, "tags": []any{"a", "b"}},
+	}
+
+	if got := types.JSONScalar.Serialize(value); !reflect.DeepEqual(got, value) {
+		t.Errorf("Serialize() = %v, want %v", got, value)
+	}
+	if got := types.JSONScalar.ParseValue(value); !reflect.DeepEqual(got, value) {
+		t.Errorf("ParseValue() = %v, want %v", got, value)
+	}
+
+	literal := &ast.ObjectValue{
+		Fields: []*ast.ObjectField{
+			{Name: &ast.Name{Value: "replicas"}, Value: &ast.IntValue{Value: "3"}},
+			{Name: &ast.Name{Value: "nested"}, Value: &ast.ObjectValue{
+				Fields: []*ast.ObjectField{
+					{Name: &ast.Name{Value: "enabled"}, Value: &ast.BooleanValue{Value: true}},
+					{Name: &ast.Name{Value: "tags"}, Value: &ast.ListValue{Values: []ast.Value{
+						&ast.StringValue{Value: "a"},
+						&ast.StringValue{Value: "b"},
+					}}},
+				},
+			}},
+		},
+	}
+	if got := types.JSONScalar.ParseLiteral(literal); !reflect.DeepEqual(got, value) {
+		t.Errorf("ParseLiteral() = %v, want %v", got, value)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/gateway/freshness"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/gateway/http"
 
 	"k8s.io/klog/v2"
@@ -13,12 +14,14 @@ import (
 type Server struct {
 	HTTPServer *http.Server
 	Gateway    *gateway.Service
+	Freshness  *freshness.Watchdog
 }
 
 func NewServer(c *Config) (Server, error) {
 	return Server{
 		HTTPServer: c.HTTPServer,
 		Gateway:    c.Gateway,
+		Freshness:  c.Freshness,
 	}, nil
 }
 
@@ -39,6 +42,12 @@ func (s *Server) Run(ctx context.Context) error {
 		}
 	})
 
+	if s.Freshness != nil {
+		wg.Go(func() {
+			s.Freshness.Run(ctx)
+		})
+	}
+
 	wg.Wait()
 	return nil
 }
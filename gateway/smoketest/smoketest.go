@@ -0,0 +1,259 @@
+// Package smoketest runs configured GraphQL operations against an endpoint
+// after its schema was (re)loaded, so that broken schema generations are
+// detected before users run into them.
+package smoketest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/prometheus/client_golang/prometheus"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+// tokenExpirationSeconds is the lifetime requested for the tokens smoke tests
+// run with. The API server may enforce a longer minimum.
+const tokenExpirationSeconds = 600
+
+// Operation is a GraphQL query executed after each schema load.
+type Operation struct {
+	// Name identifies the operation in logs and metrics.
+	Name string `json:"name"`
+	// Clusters limits the operation to these clusters. Empty runs it on all.
+	Clusters []string `json:"clusters,omitempty"`
+	// Query is the GraphQL document. Only query operations are allowed.
+	Query string `json:"query"`
+	// Variables are the operation's variables.
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// appliesTo reports whether the operation runs on a cluster.
+func (o Operation) appliesTo(cluster string) bool {
+	return len(o.Clusters) == 0 || slices.Contains(o.Clusters, cluster)
+}
+
+// file is the format of the smoke test file.
+type file struct {
+	Operations []Operation `json:"operations"`
+}
+
+// Load reads the operations from a YAML file and rejects operations that are
+// not read-only queries.
+func Load(path string) ([]Operation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read smoke tests: %w", err)
+	}
+
+	var f file
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse smoke tests %s: %w", path, err)
+	}
+
+	names := map[string]bool{}
+	for _, op := range f.Operations {
+		if op.Name == "" {
+			return nil, errors.New("smoke test without name")
+		}
+		if names[op.Name] {
+			return nil, fmt.Errorf("duplicate smoke test %q", op.Name)
+		}
+		names[op.Name] = true
+
+		if err := validateQuery(op.Query); err != nil {
+			return nil, fmt.Errorf("smoke test %q: %w", op.Name, err)
+		}
+	}
+	return f.Operations, nil
+}
+
+// validateQuery checks that a document parses and contains only queries, so
+// that smoke tests cannot change the clusters.
+func validateQuery(query string) error {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+
+	operations := 0
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if op.Operation != ast.OperationTypeQuery {
+			return fmt.Errorf("only queries are allowed, got %s", op.Operation)
+		}
+		operations++
+	}
+	if operations != 1 {
+		return fmt.Errorf("query must contain exactly one operation, got %d", operations)
+	}
+	return nil
+}
+
+// TokenFunc returns the bearer token smoke tests authenticate with, given the
+// cluster's admin config.
+type TokenFunc func(ctx context.Context, adminConfig *rest.Config) (string, error)
+
+// ServiceAccountToken requests short-lived tokens for a ServiceAccount with
+// the TokenRequest API. The ServiceAccount must exist in every cluster and
+// should only be bound to read-only roles.
+func ServiceAccountToken(namespace, name string) TokenFunc {
+	return func(ctx context.Context, adminConfig *rest.Config) (string, error) {
+		clientset, err := kubernetes.NewForConfig(adminConfig)
+		if err != nil {
+			return "", fmt.Errorf("failed to create clientset: %w", err)
+		}
+
+		tr, err := clientset.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, name, &authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: ptr.To[int64](tokenExpirationSeconds)},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to request token for service account %s/%s: %w", namespace, name, err)
+		}
+		return tr.Status.Token, nil
+	}
+}
+
+// Runner executes smoke tests and records their results.
+type Runner struct {
+	operations []Operation
+	token      TokenFunc
+	timeout    time.Duration
+
+	failed *prometheus.GaugeVec
+	runs   *prometheus.CounterVec
+}
+
+// NewRunner creates a Runner and registers its metrics with reg.
+func NewRunner(operations []Operation, token TokenFunc, timeout time.Duration, reg prometheus.Registerer) *Runner {
+	r := &Runner{
+		operations: operations,
+		token:      token,
+		timeout:    timeout,
+		failed: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "graphql_smoke_tests_failed",
+			Help: "Number of smoke tests that failed after the cluster's last schema load. Non-zero marks the cluster degraded.",
+		}, []string{"cluster"}),
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "graphql_smoke_test_runs_total",
+			Help: "Total number of smoke test executions by cluster, operation and result.",
+		}, []string{"cluster", "operation", "result"}),
+	}
+	reg.MustRegister(r.failed, r.runs)
+	return r
+}
+
+// Run executes the operations applying to a cluster against its endpoint
+// handler and returns an error listing the failed operations, nil if all
+// passed.
+func (r *Runner) Run(ctx context.Context, cluster string, handler http.Handler, adminConfig *rest.Config) error {
+	logger := log.FromContext(ctx).WithValues("cluster", cluster)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var operations []Operation
+	for _, op := range r.operations {
+		if op.appliesTo(cluster) {
+			operations = append(operations, op)
+		}
+	}
+	if len(operations) == 0 {
+		return nil
+	}
+
+	token, err := r.token(ctx, adminConfig)
+	if err != nil {
+		for _, op := range operations {
+			r.runs.WithLabelValues(cluster, op.Name, "error").Inc()
+		}
+		r.failed.WithLabelValues(cluster).Set(float64(len(operations)))
+		return err
+	}
+
+	var errs []error
+	for _, op := range operations {
+		if err := execute(ctx, cluster, handler, token, op); err != nil {
+			r.runs.WithLabelValues(cluster, op.Name, "failure").Inc()
+			errs = append(errs, fmt.Errorf("smoke test %q: %w", op.Name, err))
+			continue
+		}
+		r.runs.WithLabelValues(cluster, op.Name, "success").Inc()
+		logger.V(4).Info("Smoke test passed", "operation", op.Name)
+	}
+
+	r.failed.WithLabelValues(cluster).Set(float64(len(errs)))
+	return errors.Join(errs...)
+}
+
+// Forget removes the metrics of a cluster that is no longer served.
+func (r *Runner) Forget(cluster string) {
+	r.failed.DeleteLabelValues(cluster)
+	r.runs.DeletePartialMatch(prometheus.Labels{"cluster": cluster})
+}
+
+// response is the part of a GraphQL response smoke tests check.
+type response struct {
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// execute sends an operation through the endpoint handler the way the HTTP
+// server does for user requests.
+func execute(ctx context.Context, cluster string, handler http.Handler, token string, op Operation) error {
+	body, err := json.Marshal(map[string]any{"query": op.Query, "variables": op.Variables})
+	if err != nil {
+		return err
+	}
+
+	ctx = utilscontext.SetToken(ctx, token)
+	ctx = utilscontext.SetCluster(ctx, cluster)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/graphql", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		return fmt.Errorf("status %d: %s", rec.Code, strings.TrimSpace(rec.Body.String()))
+	}
+
+	var resp response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		messages := make([]string, 0, len(resp.Errors))
+		for _, e := range resp.Errors {
+			messages = append(messages, e.Message)
+		}
+		return errors.New(strings.Join(messages, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package smoketest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	utilscontext "github.com/platform-mesh/kubernetes-graphql-gateway/gateway/utils/context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Operation
+		wantErr string
+	}{
+		{
+			name: "queries",
+			content: `
+operations:
+- name: namespaces
+  query: "{ v1 { Namespaces { items { metadata { name } } } } }"
+- name: widgets
+  clusters: [prod]
+  query: "query($ns: String) { example_com { v1 { Widgets(namespace: $ns) { items { metadata { name } } } } } }"
+  variables:
+    ns: default
+`,
+			want: []Operation{
+				{Name: "namespaces", Query: "{ v1 { Namespaces { items { metadata { name } } } } }"},
+				{
+					Name:      "widgets",
+					Clusters:  []string{"prod"},
+					Query:     "query($ns: String) { example_com { v1 { Widgets(namespace: $ns) { items { metadata { name } } } } } }",
+					Variables: map[string]any{"ns": "default"},
+				},
+			},
+		},
+		{
+			name: "mutation",
+			content: `
+operations:
+- name: delete
+  query: "mutation { v1 { deleteNamespace(name: \"default\") } }"
+`,
+			wantErr: `smoke test "delete": only queries are allowed, got mutation`,
+		},
+		{
+			name: "several operations",
+			content: `
+operations:
+- name: two
+  query: "query a { __typename } query b { __typename }"
+`,
+			wantErr: `smoke test "two": query must contain exactly one operation, got 2`,
+		},
+		{
+			name: "duplicate name",
+			content: `
+operations:
+- name: same
+  query: "{ __typename }"
+- name: same
+  query: "{ __typename }"
+`,
+			wantErr: `duplicate smoke test "same"`,
+		},
+		{
+			name: "unknown field",
+			content: `
+operations:
+- name: typo
+  qeury: "{ __typename }"
+`,
+			wantErr: "failed to parse smoke tests",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "smoketests.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			got, err := Load(path)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	operations := []Operation{
+		{Name: "ok", Query: "{ ok }"},
+		{Name: "broken", Query: "{ broken }"},
+		{Name: "other-cluster", Clusters: []string{"other"}, Query: "{ other }"},
+	}
+
+	var queries []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := utilscontext.GetTokenFromCtx(r.Context())
+		assert.Equal(t, "smoke-token", token)
+		assert.Equal(t, "Bearer smoke-token", r.Header.Get("Authorization"))
+
+		var body struct {
+			Query string `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		queries = append(queries, body.Query)
+
+		if body.Query == "{ broken }" {
+			_, _ = w.Write([]byte(`{"errors":[{"message":"Cannot query field \"broken\" on type \"Query\"."}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"ok":true}}`))
+	})
+	token := func(context.Context, *rest.Config) (string, error) { return "smoke-token", nil }
+
+	reg := prometheus.NewRegistry()
+	runner := NewRunner(operations, token, time.Minute, reg)
+
+	err := runner.Run(t.Context(), "prod", handler, &rest.Config{})
+	assert.EqualError(t, err, `smoke test "broken": Cannot query field "broken" on type "Query".`)
+	assert.Equal(t, []string{"{ ok }", "{ broken }"}, queries)
+	assert.Equal(t, 1.0, testutil.ToFloat64(runner.failed.WithLabelValues("prod")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(runner.runs.WithLabelValues("prod", "ok", "success")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(runner.runs.WithLabelValues("prod", "broken", "failure")))
+
+	runner.Forget("prod")
+	assert.Equal(t, 0, testutil.CollectAndCount(runner.failed))
+	assert.Equal(t, 0, testutil.CollectAndCount(runner.runs))
+}
+
+func TestRunner_Run_TokenError(t *testing.T) {
+	token := func(context.Context, *rest.Config) (string, error) {
+		return "", errors.New("service account not found")
+	}
+	handler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Fatal("no operation must run without a token")
+	})
+
+	runner := NewRunner([]Operation{{Name: "ok", Query: "{ ok }"}}, token, time.Minute, prometheus.NewRegistry())
+
+	err := runner.Run(t.Context(), "prod", handler, &rest.Config{})
+	assert.EqualError(t, err, "service account not found")
+	assert.Equal(t, 1.0, testutil.ToFloat64(runner.failed.WithLabelValues("prod")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(runner.runs.WithLabelValues("prod", "ok", "error")))
+}
@@ -22,6 +22,10 @@ const clusterTargetKey contextKey = "cluster-target-key"
 // Set once by the request parser middleware; consumed by downstream middlewares.
 const parsedRequestsKey contextKey = "parsed-requests-key"
 
+// endpointURLKey is the context key for the externally reachable URL of the
+// requested cluster endpoint.
+const endpointURLKey contextKey = "endpoint-url-key"
+
 // SetCluster sets cluster to the request context
 func SetCluster(ctx context.Context, cluster string) context.Context {
 	return context.WithValue(ctx, clusterKey, cluster)
@@ -46,6 +50,20 @@ func GetTokenFromCtx(ctx context.Context) (string, bool) {
 	return v, ok
 }
 
+// SetEndpointURL sets the externally reachable URL of the requested cluster
+// endpoint to the request context.
+func SetEndpointURL(ctx context.Context, endpointURL string) context.Context {
+	return context.WithValue(ctx, endpointURLKey, endpointURL)
+}
+
+// GetEndpointURLFromCtx retrieves the externally reachable URL of the
+// requested cluster endpoint from the request context.
+// Returns the URL and true if found, or empty string and false otherwise.
+func GetEndpointURLFromCtx(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(endpointURLKey).(string)
+	return v, ok
+}
+
 // SetClusterTarget sets the logical cluster target in the request context.
 func SetClusterTarget(ctx context.Context, target string) context.Context {
 	return context.WithValue(ctx, clusterTargetKey, target)
@@ -0,0 +1,69 @@
+package context
+
+import (
+	"context"
+	"sync"
+)
+
+// resumeFromKey is the context key for the resource version a subscription
+// resumes its watch from, taken from the Last-Event-ID header.
+const resumeFromKey contextKey = "resume-from-key"
+
+// resumePointsKey is the context key for the ResumePoints of a subscription.
+const resumePointsKey contextKey = "resume-points-key"
+
+// SetResumeFrom sets the resource version a subscription resumes from to the
+// request context.
+func SetResumeFrom(ctx context.Context, resourceVersion string) context.Context {
+	return context.WithValue(ctx, resumeFromKey, resourceVersion)
+}
+
+// GetResumeFromCtx retrieves the resource version a subscription resumes
+// from. Returns the resource version and true if found, or empty string and
+// false otherwise.
+func GetResumeFromCtx(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(resumeFromKey).(string)
+	return v, ok && v != ""
+}
+
+// ResumePoints passes the resource versions a subscription can be resumed
+// from after each of its events from the resolver to the transport, which
+// only sees the results. The resolver adds one per resolved event, in the
+// order of the results; empty if the subscription cannot be resumed there.
+type ResumePoints struct {
+	mu       sync.Mutex
+	versions []string
+}
+
+// Add records the resume point of the next result.
+func (p *ResumePoints) Add(resourceVersion string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.versions = append(p.versions, resourceVersion)
+}
+
+// Next returns the resume point of the oldest result not returned yet, empty
+// if there is none.
+func (p *ResumePoints) Next() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.versions) == 0 {
+		return ""
+	}
+	next := p.versions[0]
+	p.versions = p.versions[1:]
+	return next
+}
+
+// SetResumePoints sets the ResumePoints of a subscription to the request
+// context.
+func SetResumePoints(ctx context.Context, points *ResumePoints) context.Context {
+	return context.WithValue(ctx, resumePointsKey, points)
+}
+
+// GetResumePointsFromCtx retrieves the ResumePoints of a subscription.
+// Returns the points and true if found, or nil and false otherwise.
+func GetResumePointsFromCtx(ctx context.Context) (*ResumePoints, bool) {
+	v, ok := ctx.Value(resumePointsKey).(*ResumePoints)
+	return v, ok
+}
@@ -0,0 +1,155 @@
+// Package recovery turns panics in resolvers, handlers and the goroutines
+// serving subscriptions into errors carrying a correlation ID. Recovered
+// panics are logged with their stack, recorded on the active trace span and
+// passed to the registered reporters.
+package recovery
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Panic is a recovered panic.
+type Panic struct {
+	// CorrelationID is returned to the client and logged, so the two can be
+	// matched.
+	CorrelationID string
+	// Where names the resolver, handler or goroutine that panicked.
+	Where string
+	Value any
+	Stack []byte
+}
+
+// Reporter receives recovered panics, e.g. to forward them to an error
+// tracker such as Sentry.
+type Reporter interface {
+	ReportPanic(ctx context.Context, p Panic)
+}
+
+var (
+	reportersMu sync.RWMutex
+	reporters   []Reporter
+)
+
+// RegisterReporter adds a reporter for all panics recovered afterwards.
+func RegisterReporter(r Reporter) {
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+	reporters = append(reporters, r)
+}
+
+// Error is returned in place of a recovered panic. Its message does not
+// reveal the panic value, which is only logged.
+type Error struct {
+	CorrelationID string
+}
+
+func (e *Error) Error() string {
+	return "internal error, correlation ID " + e.CorrelationID
+}
+
+// Extensions implements gqlerrors.ExtendedError, adding the code and
+// correlation ID to the GraphQL error.
+func (e *Error) Extensions() map[string]any {
+	return map[string]any{"code": "INTERNAL_SERVER_ERROR", "correlationId": e.CorrelationID}
+}
+
+// Recovered handles the value returned by recover() and returns the error to
+// send to the client, or nil if there was no panic. It must be called in a
+// deferred function:
+//
+//	defer func() {
+//		if err := recovery.Recovered(ctx, "watch", recover()); err != nil {
+//			...
+//		}
+//	}()
+func Recovered(ctx context.Context, where string, v any) *Error {
+	if v == nil {
+		return nil
+	}
+
+	p := Panic{CorrelationID: newCorrelationID(), Where: where, Value: v, Stack: debug.Stack()}
+	err := fmt.Errorf("panic: %v", v)
+
+	log.FromContext(ctx).Error(err, "Recovered from panic", "where", where, "correlationId", p.CorrelationID, "stack", string(p.Stack))
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err, trace.WithStackTrace(true))
+	span.SetStatus(codes.Error, "panic in "+where)
+
+	reportersMu.RLock()
+	defer reportersMu.RUnlock()
+	for _, r := range reporters {
+		report(ctx, r, p)
+	}
+
+	return &Error{CorrelationID: p.CorrelationID}
+}
+
+// report calls a reporter, ignoring its own panics.
+func report(ctx context.Context, r Reporter, p Panic) {
+	defer func() {
+		if v := recover(); v != nil {
+			log.FromContext(ctx).Error(fmt.Errorf("panic: %v", v), "Panic reporter panicked", "correlationId", p.CorrelationID)
+		}
+	}()
+	r.ReportPanic(ctx, p)
+}
+
+func newCorrelationID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WrapResolvers makes the resolvers of all object fields of a schema return
+// an Error instead of panicking. graphql-go recovers resolver panics itself,
+// but returns the panic value to the client and reports it nowhere.
+// Resolvers that are already wrapped, e.g. of types shared by several
+// schemas, are not wrapped again.
+func WrapResolvers(schema *graphql.Schema) {
+	for _, t := range schema.TypeMap() {
+		object, ok := t.(*graphql.Object)
+		if !ok {
+			continue
+		}
+		for _, field := range object.Fields() {
+			where := object.Name() + "." + field.Name
+			if field.Resolve != nil && !isWrapped(field.Resolve) {
+				field.Resolve = wrap(field.Resolve, where)
+			}
+			if field.Subscribe != nil && !isWrapped(field.Subscribe) {
+				field.Subscribe = wrap(field.Subscribe, where)
+			}
+		}
+	}
+}
+
+// wrappedPC is the code pointer shared by all resolvers returned by wrap.
+var wrappedPC = reflect.ValueOf(wrap(nil, "")).Pointer()
+
+func isWrapped(resolve graphql.FieldResolveFn) bool {
+	return reflect.ValueOf(resolve).Pointer() == wrappedPC
+}
+
+func wrap(resolve graphql.FieldResolveFn, where string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (result any, err error) {
+		defer func() {
+			if recovered := Recovered(p.Context, where, recover()); recovered != nil {
+				result, err = nil, recovered
+			}
+		}()
+		return resolve(p)
+	}
+}
@@ -0,0 +1,74 @@
+package recovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingReporter struct {
+	panics []Panic
+}
+
+func (r *recordingReporter) ReportPanic(_ context.Context, p Panic) {
+	r.panics = append(r.panics, p)
+}
+
+func TestRecovered(t *testing.T) {
+	reporter := &recordingReporter{}
+	RegisterReporter(reporter)
+
+	assert.Nil(t, Recovered(t.Context(), "test", nil))
+
+	err := func() (err *Error) {
+		defer func() {
+			err = Recovered(t.Context(), "test", recover())
+		}()
+		panic("boom")
+	}()
+	require.NotNil(t, err)
+	assert.NotContains(t, err.Error(), "boom")
+	assert.Equal(t, map[string]any{"code": "INTERNAL_SERVER_ERROR", "correlationId": err.CorrelationID}, err.Extensions())
+
+	require.Len(t, reporter.panics, 1)
+	assert.Equal(t, err.CorrelationID, reporter.panics[0].CorrelationID)
+	assert.Equal(t, "test", reporter.panics[0].Where)
+	assert.Equal(t, "boom", reporter.panics[0].Value)
+	assert.NotEmpty(t, reporter.panics[0].Stack)
+}
+
+func TestWrapResolvers(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"ok": &graphql.Field{
+					Type:    graphql.String,
+					Resolve: func(graphql.ResolveParams) (any, error) { return "ok", nil },
+				},
+				"broken": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(graphql.ResolveParams) (any, error) {
+						var m map[string]string
+						m["secret"] = "value"
+						return nil, nil
+					},
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	WrapResolvers(&schema)
+	assert.True(t, isWrapped(schema.QueryType().Fields()["ok"].Resolve))
+
+	result := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ ok broken }`, Context: t.Context()})
+	assert.Equal(t, map[string]any{"ok": "ok", "broken": nil}, result.Data)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Message, "internal error, correlation ID")
+	assert.Equal(t, "INTERNAL_SERVER_ERROR", result.Errors[0].Extensions["code"])
+	assert.NotEmpty(t, result.Errors[0].Extensions["correlationId"])
+}
@@ -4,12 +4,14 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net"
+	"net/http"
 	"slices"
 	"strings"
 
 	gatewayv1alpha1 "github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/options"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/schemahandler"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/status"
 	kcpprovider "github.com/platform-mesh/kubernetes-graphql-gateway/providers/kcp"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/sdk"
 	"github.com/rs/zerolog/log"
@@ -57,6 +59,10 @@ type Config struct {
 
 	SchemaHandler schemahandler.Handler
 
+	// Status tracks the reconcile results of the workspaces, served on
+	// status.Path of the metrics server with --status-endpoint.
+	Status *status.Tracker
+
 	// ResourceReconcilerClusterMetadataFunc allows to provide cluster metadata for a given cluster name
 	// when reconciling anchor namespaces.
 	ResourceReconcilerClusterMetadataFunc func(clusterName string) (*gatewayv1alpha1.ClusterMetadata, error)
@@ -84,6 +90,7 @@ func (c *Config) GracefulStop() {
 func NewConfig(options *options.CompletedOptions) (*Config, error) {
 	config := &Config{
 		Options: options,
+		Status:  status.NewTracker(),
 	}
 
 	rules := clientcmd.NewDefaultClientConfigLoadingRules()
@@ -238,6 +245,13 @@ func NewConfig(options *options.CompletedOptions) (*Config, error) {
 	if options.Common.Metrics.Secure {
 		opts.Metrics.FilterProvider = filters.WithAuthenticationAndAuthorization
 	}
+	// The metrics server authenticates and authorizes status requests like
+	// metrics requests, which Validate ensures.
+	if options.StatusEndpoint {
+		opts.Metrics.ExtraHandlers = map[string]http.Handler{
+			status.Path: status.NewHandler(config.Status, options.Effective()),
+		}
+	}
 
 	manager, err := mcmanager.New(config.ClientConfig, config.Provider, opts)
 	if err != nil {
@@ -256,10 +270,11 @@ func NewConfig(options *options.CompletedOptions) (*Config, error) {
 
 	switch options.SchemaHandler {
 	case "file":
-		config.SchemaHandler, err = schemahandler.NewFileHandler(options.SchemasDir)
+		handler, err := schemahandler.NewFileHandler(options.SchemasDir)
 		if err != nil {
 			return nil, fmt.Errorf("error creating file handler: %w", err)
 		}
+		config.SchemaHandler = handler.WithChangelog(options.SchemaChangelogSize)
 	case "grpc":
 
 		lis, err := net.Listen("tcp", options.GRPCListenAddr)
@@ -3,21 +3,24 @@ package clusteraccess
 import (
 	"context"
 	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/controllers/reconciler"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/apischema"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/apischema/enricher"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/schemahandler"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/status"
 
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,6 +39,14 @@ const (
 	// ConditionTypeReady indicates whether the ClusterAccess schema was
 	// successfully generated and written.
 	ConditionTypeReady = "Ready"
+
+	// ConditionTypeRBACVerified indicates whether the ClusterAccess credentials
+	// can get, list and watch all resources in the schema. It is only set when
+	// RBAC verification is enabled.
+	ConditionTypeRBACVerified = "RBACVerified"
+
+	// maxDeniedInMessage bounds the resources listed in the RBACVerified message.
+	maxDeniedInMessage = 10
 )
 
 var (
@@ -45,9 +56,11 @@ var (
 
 // ClusterAccessReconciler reconciles ClusterAccess resources and generates schemas
 type ClusterAccessReconciler struct {
-	manager   mcmanager.Manager
-	opts      controller.TypedOptions[mcreconcile.Request]
-	ioHandler schemahandler.Handler
+	manager    mcmanager.Manager
+	opts       controller.TypedOptions[mcreconcile.Request]
+	ioHandler  schemahandler.Handler
+	verifyRBAC bool
+	status     *status.Tracker
 }
 
 // NewClusterAccessReconciler returns a new ClusterAccessReconciler
@@ -56,11 +69,15 @@ func NewClusterAccessReconciler(
 	mgr mcmanager.Manager,
 	opts controller.TypedOptions[mcreconcile.Request],
 	ioHandler schemahandler.Handler,
+	verifyRBAC bool,
+	tracker *status.Tracker,
 ) (*ClusterAccessReconciler, error) {
 	r := &ClusterAccessReconciler{
-		manager:   mgr,
-		opts:      opts,
-		ioHandler: ioHandler,
+		manager:    mgr,
+		opts:       opts,
+		ioHandler:  ioHandler,
+		verifyRBAC: verifyRBAC,
+		status:     tracker,
 	}
 
 	return r, nil
@@ -96,12 +113,14 @@ func (r *ClusterAccessReconciler) Reconcile(ctx context.Context, req mcreconcile
 			if err := r.ioHandler.Delete(ctx, name); err != nil {
 				logger.Error(err, "Failed to cleanup schema")
 			}
+			r.status.Forget(controllerName, clusterName, req.Name)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, fmt.Errorf("failed to get ClusterAccess: %w", err)
 	}
 
 	result, reconcileErr := r.reconcileClusterAccess(ctx, ca, c, cl.GetConfig(), clusterName)
+	r.status.Record(controllerName, clusterName, req.Name, reconcileErr)
 
 	// Update the Ready status condition based on the reconciliation outcome
 	if err := r.setReadyCondition(ctx, ca, c, reconcileErr); err != nil {
@@ -151,7 +170,7 @@ func (r *ClusterAccessReconciler) reconcileClusterAccess(
 		return ctrl.Result{}, err
 	}
 
-	// Get preferred resources for categories enricher
+	// Get preferred resources for the discovery stubs and categories enrichers
 	apiResources, err := targetDiscovery.ServerPreferredResources()
 	if err != nil {
 		// Log but don't fail - some resources may still be available
@@ -161,11 +180,33 @@ func (r *ClusterAccessReconciler) reconcileClusterAccess(
 		}
 	}
 
+	targetExtensions, err := apiextensionsclientset.NewForConfig(targetConfig)
+	if err != nil {
+		logger.Error(err, "Failed to create apiextensions clientset", "clusterAccess", ca.Name)
+		return ctrl.Result{}, err
+	}
+
 	// Create resolver with enrichers configured for this cluster
-	resolver := apischema.NewResolver(
+	enrichers := []apischema.Enricher{
+		enricher.NewDiscoveryStubs(apiResources),
 		enricher.NewScope(targetRM),
 		enricher.NewCategories(apiResources),
-	)
+		enricher.NewPrinterColumns(targetExtensions.ApiextensionsV1().CustomResourceDefinitions()),
+		enricher.NewMaturity(targetExtensions.ApiextensionsV1().CustomResourceDefinitions()),
+	}
+
+	var access *enricher.Access
+	if r.verifyRBAC {
+		targetClientset, err := kubernetes.NewForConfig(targetConfig)
+		if err != nil {
+			logger.Error(err, "Failed to create clientset", "clusterAccess", ca.Name)
+			return ctrl.Result{}, err
+		}
+		access = enricher.NewAccess(targetClientset.AuthorizationV1().SelfSubjectAccessReviews(), targetRM)
+		enrichers = append(enrichers, access)
+	}
+
+	resolver := apischema.NewResolver(enrichers...)
 
 	// Resolve schema from target cluster
 	schemaJSON, err := resolver.Resolve(ctx, targetDiscovery.OpenAPIV3())
@@ -174,6 +215,8 @@ func (r *ClusterAccessReconciler) reconcileClusterAccess(
 		return ctrl.Result{}, err
 	}
 
+	setRBACVerifiedCondition(ca, access)
+
 	// Inject cluster metadata into the schema
 	schemaWithMetadata, err := injectClusterMetadata(ctx, schemaJSON, *ca, c, currentConfig)
 	if err != nil {
@@ -260,14 +303,7 @@ func injectClusterMetadata(ctx context.Context, schemaData []byte, clusterAccess
 		}
 	}
 
-	var schemaJSON map[string]any
-	if err := json.Unmarshal(schemaData, &schemaJSON); err != nil {
-		return nil, fmt.Errorf("failed to parse schema JSON: %w", err)
-	}
-
-	schemaJSON["x-cluster-metadata"] = metadata
-
-	return json.Marshal(schemaJSON)
+	return extensions.WithClusterMetadata(schemaData, metadata)
 }
 
 // restMapperFromConfig creates a REST mapper from a config
@@ -284,6 +320,32 @@ func (r *ClusterAccessReconciler) restMapperFromConfig(cfg *rest.Config) (meta.R
 	return rm, nil
 }
 
+// setRBACVerifiedCondition records the result of RBAC verification on the
+// ClusterAccess status. The condition is removed if verification is disabled.
+// It is persisted together with the Ready condition.
+func setRBACVerifiedCondition(ca *v1alpha1.ClusterAccess, access *enricher.Access) {
+	if access == nil {
+		meta.RemoveStatusCondition(&ca.Status.Conditions, ConditionTypeRBACVerified)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionTypeRBACVerified,
+		ObservedGeneration: ca.Generation,
+		Status:             metav1.ConditionTrue,
+		Reason:             "AccessVerified",
+		Message:            "Credentials can get, list and watch all resources",
+	}
+
+	if denied := access.Denied(); len(denied) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "AccessDenied"
+		condition.Message = fmt.Sprintf("Credentials are denied on %d resources: %s", len(denied), enricher.FormatDenied(denied, maxDeniedInMessage))
+	}
+
+	meta.SetStatusCondition(&ca.Status.Conditions, condition)
+}
+
 // setReadyCondition updates the Ready status condition on the ClusterAccess resource.
 // On success (reconcileErr == nil) it sets Ready=True; on failure it sets Ready=False
 // with the error message. This is best-effort: failures to update are returned but
@@ -100,6 +100,8 @@ func (suite *ClusterAccessControllerTestSuite) SetupSuite() {
 		listenerConfig.Manager,
 		controller.TypedOptions[mcreconcile.Request]{},
 		listenerConfig.SchemaHandler,
+		false,
+		listenerConfig.Status,
 	)
 	suite.Require().NoError(err, "failed to create clusteraccess reconciler")
 
@@ -0,0 +1,72 @@
+package reconciler
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/contract"
+	apischemaMocks "github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/apischema/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/openapi"
+)
+
+var update = flag.Bool("update", false, "update the contract's golden schema document")
+
+// TestContract verifies that the listener writes the schema document the
+// gateway is tested against in apischema/contract.
+func TestContract(t *testing.T) {
+	gv := apischemaMocks.NewMockGroupVersion(t)
+	gv.EXPECT().Schema(mock.Anything).Return(contract.OpenAPIV3, nil)
+
+	openAPIClient := apischemaMocks.NewMockClient(t)
+	openAPIClient.EXPECT().Paths().Return(map[string]openapi.GroupVersion{contract.OpenAPIV3Path: gv}, nil)
+
+	discoveryClient := apischemaMocks.NewMockDiscoveryInterface(t)
+	discoveryClient.EXPECT().ServerPreferredResources().Return(contract.Discovery, nil)
+	discoveryClient.EXPECT().OpenAPIV3().Return(openAPIClient)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, list := range contract.Discovery {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		require.NoError(t, err)
+		for _, res := range list.APIResources {
+			scope := meta.RESTScopeRoot
+			if res.Namespaced {
+				scope = meta.RESTScopeNamespace
+			}
+			mapper.Add(gv.WithKind(res.Kind), scope)
+		}
+	}
+
+	metadata := contract.Metadata
+	got, err := generateSchemaWithMetadata(t.Context(), schemaGenerationParams{
+		ClusterPath:     "root",
+		DiscoveryClient: discoveryClient,
+		RESTMapper:      mapper,
+		CRDs:            apiextensionsfake.NewClientset().ApiextensionsV1().CustomResourceDefinitions(),
+	}, &metadata)
+	require.NoError(t, err)
+
+	contract.AssertDocument(t, got)
+
+	var indented bytes.Buffer
+	require.NoError(t, json.Indent(&indented, got, "", "  "))
+	indented.WriteByte('\n')
+
+	if *update {
+		goldenPath := filepath.Join("..", "..", "..", "apischema", "contract", contract.GoldenFile)
+		require.NoError(t, os.WriteFile(goldenPath, indented.Bytes(), 0o644))
+		return
+	}
+	assert.JSONEq(t, string(contract.Golden), indented.String(), "schema document differs from %s, run with -update if the change is intended", contract.GoldenFile)
+}
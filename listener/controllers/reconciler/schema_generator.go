@@ -2,13 +2,14 @@ package reconciler
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/apischema"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/apischema/enricher"
 
+	apiextensionsv1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/discovery"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -19,6 +20,7 @@ type schemaGenerationParams struct {
 	ClusterPath     string
 	DiscoveryClient discovery.DiscoveryInterface
 	RESTMapper      meta.RESTMapper
+	CRDs            apiextensionsv1client.CustomResourceDefinitionInterface
 	HostOverride    string // Optional: for virtual workspaces with custom URLs
 }
 
@@ -33,7 +35,7 @@ func generateSchemaWithMetadata(
 
 	logger.V(4).WithValues("clusterPath", params.ClusterPath).Info("starting API schema resolution")
 
-	// Get preferred resources for categories enricher
+	// Get preferred resources for the discovery stubs and categories enrichers
 	apiResources, err := params.DiscoveryClient.ServerPreferredResources()
 	if err != nil {
 		// Log but don't fail - some resources may still be available
@@ -45,8 +47,11 @@ func generateSchemaWithMetadata(
 
 	// Create resolver with enrichers configured for this cluster
 	resolver := apischema.NewResolver(
+		enricher.NewDiscoveryStubs(apiResources),
 		enricher.NewScope(params.RESTMapper),
 		enricher.NewCategories(apiResources),
+		enricher.NewPrinterColumns(params.CRDs),
+		enricher.NewMaturity(params.CRDs),
 	)
 
 	// Resolve current schema from API server
@@ -58,27 +63,9 @@ func generateSchemaWithMetadata(
 
 	logger.V(4).WithValues("clusterPath", params.ClusterPath, "schemaSize", len(rawSchema)).Info("API schema resolved")
 
-	// Parse the existing schema JSON and inject cluster metadata if provided
+	// Inject the cluster metadata if provided
 	if metadata != nil {
-		// TODO: This is ugly! Improve in future.
-		var schemaJSON map[string]any
-		if err := json.Unmarshal(rawSchema, &schemaJSON); err != nil {
-			return nil, fmt.Errorf("failed to parse schema JSON: %w", err)
-		}
-
-		data, err := json.Marshal(metadata)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal cluster metadata: %w", err)
-		}
-		// marshal metadata into map[string]any
-		var metadataMap map[string]any
-		if err := json.Unmarshal(data, &metadataMap); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal cluster metadata: %w", err)
-		}
-
-		// Inject the metadata into the schema
-		schemaJSON["x-cluster-metadata"] = metadataMap
-		return json.Marshal(schemaJSON)
+		return extensions.WithClusterMetadata(rawSchema, metadata)
 	}
 
 	return rawSchema, nil
@@ -9,6 +9,7 @@ import (
 	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/schemahandler"
 
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
@@ -52,6 +53,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, schemaPaths []string, cfg *r
 		return fmt.Errorf("failed to create REST mapper: %w", err)
 	}
 
+	extensionsClient, err := apiextensionsclientset.NewForConfig(cfg)
+	if err != nil {
+		logger.Error(err, "Failed to create apiextensions clientset")
+		return fmt.Errorf("failed to create apiextensions clientset: %w", err)
+	}
+
 	// We store both representation and schema files for each cluster paths.
 	for _, schemaPath := range schemaPaths {
 		logger.Info("Generating schema", "path", schemaPath)
@@ -59,6 +66,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, schemaPaths []string, cfg *r
 			ClusterPath:     schemaPath,
 			DiscoveryClient: discoveryClient,
 			RESTMapper:      restMapper,
+			CRDs:            extensionsClient.ApiextensionsV1().CustomResourceDefinitions(),
 		}
 
 		currentSchema, err := generateSchemaWithMetadata(ctx, params, metadata)
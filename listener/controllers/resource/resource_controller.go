@@ -8,6 +8,7 @@ import (
 	"github.com/platform-mesh/kubernetes-graphql-gateway/apis/v1alpha1"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/controllers/reconciler"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/schemahandler"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/status"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -38,6 +39,7 @@ type Reconciler struct {
 	anchorResource              string
 	resourceGVK                 schema.GroupVersionKind
 	additionalPathAnnotationKey string
+	status                      *status.Tracker
 
 	// Provider specific functions
 	clusterMetadataFunc    v1alpha1.ClusterMetadataFunc
@@ -55,6 +57,7 @@ func New(
 	additionalPathAnnotationKey string,
 	clusterMetadataFunc v1alpha1.ClusterMetadataFunc,
 	clusterURLResolverFunc v1alpha1.ClusterURLResolver,
+	tracker *status.Tracker,
 ) (*Reconciler, error) {
 	r := &Reconciler{
 		manager:                     mgr,
@@ -62,6 +65,7 @@ func New(
 		reconciler:                  reconciler.NewReconciler(schemaHandler),
 		anchorResource:              anchorResource,
 		additionalPathAnnotationKey: additionalPathAnnotationKey,
+		status:                      tracker,
 
 		clusterMetadataFunc:    clusterMetadataFunc,
 		clusterURLResolverFunc: clusterURLResolverFunc,
@@ -86,20 +90,27 @@ func New(
 }
 
 // Reconcile handles the namespace reconciliation
-func (r *Reconciler) Reconcile(ctx context.Context, req mcreconcile.Request) (ctrl.Result, error) {
+func (r *Reconciler) Reconcile(ctx context.Context, req mcreconcile.Request) (_ ctrl.Result, err error) {
 	logger := log.FromContext(ctx)
 
 	logger.Info("Reconciling anchor resource", "resourceName", req.Name, "cluster", req.ClusterName)
 
+	// Strip multi-provider prefix (e.g. "kcp#workspace1" → "workspace1") for
+	// downstream use in URLs, schema paths, and metadata lookups.
+	clusterName := reconciler.ClusterName(req.ClusterName)
+
+	removed := false
+	defer func() {
+		if !removed {
+			r.status.Record(controllerName, clusterName, req.Name, err)
+		}
+	}()
+
 	cl, err := r.manager.GetCluster(ctx, req.ClusterName)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to get client for cluster %q: %w", req.ClusterName, err)
 	}
 
-	// Strip multi-provider prefix (e.g. "kcp#workspace1" → "workspace1") for
-	// downstream use in URLs, schema paths, and metadata lookups.
-	clusterName := reconciler.ClusterName(req.ClusterName)
-
 	c := cl.GetClient()
 	config := rest.CopyConfig(cl.GetConfig())
 
@@ -125,6 +136,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req mcreconcile.Request) (ct
 			if err := r.reconciler.Cleanup(ctx, paths); err != nil {
 				logger.Error(err, "Failed to cleanup schema")
 			}
+			r.status.Forget(controllerName, clusterName, req.Name)
+			removed = true
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, fmt.Errorf("failed to get resource: %w", err)
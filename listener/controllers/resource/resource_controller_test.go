@@ -80,6 +80,7 @@ func (suite *ResourceControllerTestSuite) SetupSuite() {
 		listenerConfig.Options.AdditonalPathAnnotationKey,
 		listenerConfig.Options.ClusterMetadataFunc,
 		listenerConfig.Options.ClusterURLResolverFunc,
+		listenerConfig.Status,
 	)
 	suite.Require().NoError(err, "failed to create resource reconciler")
 
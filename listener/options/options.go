@@ -40,6 +40,9 @@ type ExtraOptions struct {
 	ClusterAccessControllerProviders string
 	// SchemasDir is the directory to store schema files. Only required if using file schema handler
 	SchemasDir string
+	// SchemaChangelogSize is how many changes of a schema are appended to its changelog before
+	// the schema file is rewritten. 0 rewrites the schema file on every change. Only used with the file schema handler
+	SchemaChangelogSize int
 	// ResourceGVR is the GroupVersionResource which the reconciler will be watching
 	ResourceGVR string
 	// AnchorResource is the resource to watch for kubernetes provider
@@ -67,6 +70,14 @@ type ExtraOptions struct {
 	EnableResourceController bool
 	// EnableClusterAccessController enables the ClusterAccess controller.
 	EnableClusterAccessController bool
+	// VerifyClusterAccessRBAC checks that ClusterAccess credentials can get, list
+	// and watch each resource before publishing the schema. Denied resources are
+	// reported in the RBACVerified condition and left out by the gateway.
+	VerifyClusterAccessRBAC bool
+
+	// StatusEndpoint serves the effective configuration and the reconcile
+	// results of the workspaces on /status of the metrics server.
+	StatusEndpoint bool
 }
 
 type completedOptions struct {
@@ -134,6 +145,7 @@ func (options *Options) AddFlags(fs *pflag.FlagSet) {
 
 	fs.StringVar(&options.SchemaHandler, "schema-handler", options.SchemaHandler, "The type of schema handler to use (e.g., 'file', 'grpc')")
 	fs.StringVar(&options.SchemasDir, "schemas-dir", options.SchemasDir, "SchemasDir is the directory to store schema files. Only required if using file schema handler")
+	fs.IntVar(&options.SchemaChangelogSize, "schema-changelog-size", options.SchemaChangelogSize, "number of schema changes appended to a schema's changelog as JSON Patches before the schema file is rewritten; 0 rewrites the schema file on every change (used with --schema-handler=file)")
 	fs.StringVar(&options.GRPCListenAddr, "grpc-listen-addr", options.GRPCListenAddr, "The gRPC server listener address (only used if SchemaHandler is 'grpc')")
 	fs.IntVar(&options.GRPCMaxSendMsgSize, "grpc-max-send-msg-size", options.GRPCMaxSendMsgSize, "maximum gRPC send message size in bytes (used with --schema-handler=grpc)")
 
@@ -146,6 +158,8 @@ func (options *Options) AddFlags(fs *pflag.FlagSet) {
 
 	fs.BoolVar(&options.EnableResourceController, "enable-resource-controller", options.EnableResourceController, "Enable the resource controller for watching the configured anchor resource and generating schemas")
 	fs.BoolVar(&options.EnableClusterAccessController, "enable-clusteraccess-controller", options.EnableClusterAccessController, "Enable the ClusterAccess controller for managing remote cluster schemas")
+	fs.BoolVar(&options.StatusEndpoint, "status-endpoint", options.StatusEndpoint, "Serve the effective configuration and the last reconcile result of each workspace as JSON on /status of the metrics server; requires --metrics-secure, as it is only served to authenticated and authorized users")
+	fs.BoolVar(&options.VerifyClusterAccessRBAC, "verify-clusteraccess-rbac", options.VerifyClusterAccessRBAC, "Verify that ClusterAccess credentials can get, list and watch each resource before publishing the schema; denied kinds are reported in the RBACVerified condition and not served by the gateway")
 }
 
 func (options *Options) Complete() (*CompletedOptions, error) {
@@ -235,6 +249,13 @@ func (options *CompletedOptions) Validate() error {
 			return fmt.Errorf("schemas-dir must be specified when schema-handler is 'file'")
 		}
 	}
+	if options.SchemaChangelogSize < 0 {
+		return fmt.Errorf("--schema-changelog-size must not be negative")
+	}
+
+	if options.VerifyClusterAccessRBAC && !options.EnableClusterAccessController {
+		return fmt.Errorf("--verify-clusteraccess-rbac requires --enable-clusteraccess-controller")
+	}
 
 	for _, ns := range options.CacheNamespaces {
 		if strings.TrimSpace(ns) == "" {
@@ -242,9 +263,68 @@ func (options *CompletedOptions) Validate() error {
 		}
 	}
 
+	if options.StatusEndpoint {
+		if !options.Common.Metrics.Secure {
+			return fmt.Errorf("--status-endpoint requires --metrics-secure")
+		}
+		if options.Common.Metrics.BindAddress == "0" {
+			return fmt.Errorf("--status-endpoint requires the metrics server, which --metrics-bind-address=0 disables")
+		}
+	}
+
 	return nil
 }
 
+// EffectiveConfig is the configuration reported by the status endpoint.
+type EffectiveConfig struct {
+	Provider                         string   `json:"provider"`
+	ResourceControllerProviders      string   `json:"resourceControllerProviders,omitempty"`
+	ClusterAccessControllerProviders string   `json:"clusterAccessControllerProviders,omitempty"`
+	SchemaHandler                    string   `json:"schemaHandler"`
+	SchemasDir                       string   `json:"schemasDir,omitempty"`
+	SchemaChangelogSize              int      `json:"schemaChangelogSize,omitempty"`
+	GRPCListenAddr                   string   `json:"grpcListenAddr,omitempty"`
+	EnableResourceController         bool     `json:"enableResourceController"`
+	EnableClusterAccessController    bool     `json:"enableClusterAccessController"`
+	VerifyClusterAccessRBAC          bool     `json:"verifyClusterAccessRBAC"`
+	AnchorResource                   string   `json:"anchorResource,omitempty"`
+	ResourceGVR                      string   `json:"resourceGVR,omitempty"`
+	AdditionalPathAnnotationKey      string   `json:"additionalPathAnnotationKey,omitempty"`
+	CacheNamespaces                  []string `json:"cacheNamespaces,omitempty"`
+	MaxConcurrentReconciles          int      `json:"maxConcurrentReconciles"`
+	LeaderElection                   bool     `json:"leaderElection"`
+}
+
+// Effective returns the configuration reported by the status endpoint. It
+// leaves out file paths of credentials such as kubeconfigs.
+func (options *CompletedOptions) Effective() EffectiveConfig {
+	c := EffectiveConfig{
+		Provider:                         options.Provider,
+		ResourceControllerProviders:      options.ResourceControllerProviders,
+		ClusterAccessControllerProviders: options.ClusterAccessControllerProviders,
+		SchemaHandler:                    options.SchemaHandler,
+		EnableResourceController:         options.EnableResourceController,
+		EnableClusterAccessController:    options.EnableClusterAccessController,
+		VerifyClusterAccessRBAC:          options.VerifyClusterAccessRBAC,
+		CacheNamespaces:                  options.CacheNamespaces,
+		MaxConcurrentReconciles:          options.Common.MaxConcurrentReconciles,
+		LeaderElection:                   options.Common.LeaderElectionEnabled,
+	}
+	switch options.SchemaHandler {
+	case "file":
+		c.SchemasDir = options.SchemasDir
+		c.SchemaChangelogSize = options.SchemaChangelogSize
+	case "grpc":
+		c.GRPCListenAddr = options.GRPCListenAddr
+	}
+	if options.EnableResourceController {
+		c.AnchorResource = options.AnchorResource
+		c.ResourceGVR = options.ResourceGVR
+		c.AdditionalPathAnnotationKey = options.AdditonalPathAnnotationKey
+	}
+	return c
+}
+
 // validateProviderNames checks that a comma-separated string contains only valid provider names.
 func validateProviderNames(names string, flagName string) error {
 	for name := range strings.SplitSeq(names, ",") {
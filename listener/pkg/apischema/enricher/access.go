@@ -0,0 +1,160 @@
+package enricher
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	"golang.org/x/sync/errgroup"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// readVerbs are the verbs the gateway needs to serve queries and subscriptions.
+var readVerbs = []string{"get", "list", "watch"}
+
+// accessCheckConcurrency bounds the number of parallel access reviews.
+const accessCheckConcurrency = 10
+
+// Access verifies that the credentials used to load the schema can read each
+// resource and records denied verbs in the x-kubernetes-denied-verbs
+// extension. The gateway uses it to leave out kinds every query would be
+// forbidden on. Reviews that fail are logged and treated as allowed, so an
+// unavailable authorization API does not hide resources.
+type Access struct {
+	reviews authorizationv1client.SelfSubjectAccessReviewInterface
+	mapper  meta.RESTMapper
+
+	mu     sync.Mutex
+	denied map[schema.GroupVersionKind][]string
+}
+
+// NewAccess creates a new Access enricher.
+func NewAccess(reviews authorizationv1client.SelfSubjectAccessReviewInterface, mapper meta.RESTMapper) *Access {
+	return &Access{reviews: reviews, mapper: mapper}
+}
+
+// Name returns the enricher name for logging.
+func (e *Access) Name() string {
+	return "access"
+}
+
+// Enrich reviews read access to all resources with GVK and scope.
+func (e *Access) Enrich(ctx context.Context, schemas *apischema.SchemaSet) error {
+	logger := log.FromContext(ctx)
+
+	denied := map[schema.GroupVersionKind][]string{}
+	var mu sync.Mutex
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(accessCheckConcurrency)
+
+	for _, entry := range schemas.All() {
+		if entry.GVK == nil || strings.HasSuffix(entry.GVK.Kind, "List") {
+			continue
+		}
+		if _, err := extensions.Scope(entry.Schema); err != nil {
+			continue
+		}
+
+		mapping, err := e.mapper.RESTMapping(entry.GVK.GroupKind(), entry.GVK.Version)
+		if err != nil {
+			logger.V(4).WithValues(
+				"gvk", entry.GVK,
+				"error", err,
+			).Info("failed to map resource for access review")
+			continue
+		}
+
+		g.Go(func() error {
+			verbs := e.deniedVerbs(gctx, mapping.Resource)
+			if len(verbs) == 0 {
+				return nil
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			denied[*entry.GVK] = verbs
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	for gvk, verbs := range denied {
+		if entry, ok := schemas.GetByGVK(gvk); ok {
+			extensions.SetDeniedVerbs(entry.Schema, verbs)
+		}
+	}
+
+	e.mu.Lock()
+	e.denied = denied
+	e.mu.Unlock()
+
+	logger.V(4).Info("verified resource access", "deniedCount", len(denied))
+	return nil
+}
+
+// Denied returns the verbs denied per resource by the last Enrich call.
+func (e *Access) Denied() map[schema.GroupVersionKind][]string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.denied
+}
+
+func (e *Access) deniedVerbs(ctx context.Context, gvr schema.GroupVersionResource) []string {
+	logger := log.FromContext(ctx)
+
+	var denied []string
+	for _, verb := range readVerbs {
+		review, err := e.reviews.Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Group:    gvr.Group,
+					Version:  gvr.Version,
+					Resource: gvr.Resource,
+					Verb:     verb,
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			logger.V(4).WithValues(
+				"resource", gvr,
+				"verb", verb,
+				"error", err,
+			).Info("access review failed")
+			continue
+		}
+
+		if !review.Status.Allowed {
+			denied = append(denied, verb)
+		}
+	}
+	return denied
+}
+
+// FormatDenied summarizes denied verbs per resource for status messages, e.g.
+// "apps/v1 Deployment: watch". At most limit resources are listed.
+func FormatDenied(denied map[schema.GroupVersionKind][]string, limit int) string {
+	entries := make([]string, 0, len(denied))
+	for gvk, verbs := range denied {
+		entries = append(entries, fmt.Sprintf("%s %s: %s", gvk.GroupVersion(), gvk.Kind, strings.Join(verbs, ",")))
+	}
+	slices.Sort(entries)
+
+	if len(entries) > limit {
+		return strings.Join(entries[:limit], "; ") + fmt.Sprintf("; and %d more", len(entries)-limit)
+	}
+	return strings.Join(entries, "; ")
+}
@@ -0,0 +1,101 @@
+package enricher_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/apischema/enricher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func resourceSchema(group, version, kind string) *spec.Schema {
+	return &spec.Schema{
+		VendorExtensible: spec.VendorExtensible{
+			Extensions: map[string]any{
+				extensions.GVKKey:   []map[string]any{{"group": group, "version": version, "kind": kind}},
+				extensions.ScopeKey: "Namespaced",
+			},
+		},
+	}
+}
+
+func TestAccessEnricher(t *testing.T) {
+	deploymentGVK := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	secretGVK := schema.GroupVersionKind{Version: "v1", Kind: "Secret"}
+	configMapGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	podGVK := schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(deploymentGVK, meta.RESTScopeNamespace)
+	mapper.Add(secretGVK, meta.RESTScopeNamespace)
+	mapper.Add(configMapGVK, meta.RESTScopeNamespace)
+	mapper.Add(podGVK, meta.RESTScopeNamespace)
+
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		switch {
+		case attrs.Resource == "pods":
+			return true, nil, errors.New("authorization API unavailable")
+		case attrs.Resource == "secrets", attrs.Resource == "deployments" && attrs.Verb == "watch":
+			review.Status.Allowed = false
+		default:
+			review.Status.Allowed = true
+		}
+		return true, review, nil
+	})
+
+	schemas := apischema.NewSchemaSetFromMap(map[string]*spec.Schema{
+		"io.k8s.api.apps.v1.Deployment": resourceSchema("apps", "v1", "Deployment"),
+		"io.k8s.api.core.v1.Secret":     resourceSchema("", "v1", "Secret"),
+		"io.k8s.api.core.v1.ConfigMap":  resourceSchema("", "v1", "ConfigMap"),
+		"io.k8s.api.core.v1.Pod":        resourceSchema("", "v1", "Pod"),
+	})
+
+	e := enricher.NewAccess(clientset.AuthorizationV1().SelfSubjectAccessReviews(), mapper)
+	require.NoError(t, e.Enrich(t.Context(), schemas))
+
+	want := map[schema.GroupVersionKind][]string{
+		deploymentGVK: {"watch"},
+		secretGVK:     {"get", "list", "watch"},
+	}
+	assert.Equal(t, want, e.Denied())
+
+	for gvk, verbs := range want {
+		entry, ok := schemas.GetByGVK(gvk)
+		require.True(t, ok)
+		assert.Equal(t, verbs, extensions.DeniedVerbs(entry.Schema), gvk.Kind)
+	}
+	for _, gvk := range []schema.GroupVersionKind{configMapGVK, podGVK} {
+		entry, ok := schemas.GetByGVK(gvk)
+		require.True(t, ok)
+		assert.Nil(t, extensions.DeniedVerbs(entry.Schema), "%s should not be marked denied", gvk.Kind)
+	}
+}
+
+func TestFormatDenied(t *testing.T) {
+	denied := map[schema.GroupVersionKind][]string{
+		{Version: "v1", Kind: "Secret"}:                                   {"get", "list", "watch"},
+		{Group: "apps", Version: "v1", Kind: "Deployment"}:                {"watch"},
+		{Group: "batch", Version: "v1", Kind: "CronJob"}:                  {"list"},
+		{Group: "policy", Version: "v1", Kind: "Eviction"}:                {"get"},
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"}: {"get"},
+	}
+
+	assert.Equal(t,
+		"apps/v1 Deployment: watch; batch/v1 CronJob: list; and 3 more",
+		enricher.FormatDenied(denied, 2),
+	)
+}
@@ -3,8 +3,8 @@ package enricher
 import (
 	"context"
 
-	"github.com/platform-mesh/kubernetes-graphql-gateway/apis"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -57,7 +57,7 @@ func (e *Categories) Enrich(ctx context.Context, schemas *apischema.SchemaSet) e
 				continue
 			}
 
-			entry.Schema.AddExtension(apis.CategoriesExtensionKey, res.Categories)
+			extensions.SetCategories(entry.Schema, res.Categories)
 		}
 	}
 
@@ -3,8 +3,8 @@ package enricher_test
 import (
 	"testing"
 
-	"github.com/platform-mesh/kubernetes-graphql-gateway/apis"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/apischema/enricher"
 	"github.com/stretchr/testify/assert"
 
@@ -16,7 +16,7 @@ func TestCategoriesEnricher(t *testing.T) {
 	podSchema := &spec.Schema{
 		VendorExtensible: spec.VendorExtensible{
 			Extensions: map[string]any{
-				apis.GVKExtensionKey: []map[string]any{
+				extensions.GVKKey: []map[string]any{
 					{"group": "", "version": "v1", "kind": "Pod"},
 				},
 			},
@@ -47,7 +47,7 @@ func TestCategoriesEnricher(t *testing.T) {
 	assert.NoError(t, err)
 
 	podEntry, _ := schemas.Get("v1.Pod")
-	categories := podEntry.Schema.Extensions[apis.CategoriesExtensionKey]
+	categories := podEntry.Schema.Extensions[extensions.CategoriesKey]
 	assert.Equal(t, []string{"all"}, categories)
 }
 
@@ -55,7 +55,7 @@ func TestCategoriesEnricher_NoCategories(t *testing.T) {
 	podSchema := &spec.Schema{
 		VendorExtensible: spec.VendorExtensible{
 			Extensions: map[string]any{
-				apis.GVKExtensionKey: []map[string]any{
+				extensions.GVKKey: []map[string]any{
 					{"group": "", "version": "v1", "kind": "Pod"},
 				},
 			},
@@ -87,7 +87,7 @@ func TestCategoriesEnricher_NoCategories(t *testing.T) {
 	assert.NoError(t, err)
 
 	podEntry, _ := schemas.Get("v1.Pod")
-	_, hasCategories := podEntry.Schema.Extensions[apis.CategoriesExtensionKey]
+	_, hasCategories := podEntry.Schema.Extensions[extensions.CategoriesKey]
 	assert.False(t, hasCategories, "should not have categories extension")
 }
 
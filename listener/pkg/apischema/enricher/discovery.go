@@ -0,0 +1,134 @@
+package enricher
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// objectMetaKey is the schema key of metav1.ObjectMeta after ref normalization.
+const objectMetaKey = "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"
+
+// DiscoveryStubs adds stub schemas for resources that are served by the API
+// server but missing from its OpenAPI document. This happens for aggregated
+// APIs such as project.openshift.io, whose servers do not always publish
+// complete OpenAPI details. Stubs expose metadata and carry spec and status
+// as open-ended objects, so the kinds are queryable instead of missing.
+type DiscoveryStubs struct {
+	resources []*metav1.APIResourceList
+}
+
+// NewDiscoveryStubs creates a new DiscoveryStubs enricher.
+func NewDiscoveryStubs(resources []*metav1.APIResourceList) *DiscoveryStubs {
+	return &DiscoveryStubs{resources: resources}
+}
+
+// Name returns the enricher name for logging.
+func (e *DiscoveryStubs) Name() string {
+	return "discoveryStubs"
+}
+
+// Enrich adds a stub schema for every discovered resource without a schema.
+func (e *DiscoveryStubs) Enrich(ctx context.Context, schemas *apischema.SchemaSet) error {
+	logger := log.FromContext(ctx)
+
+	_, hasObjectMeta := schemas.Get(objectMetaKey)
+
+	for _, apiResList := range e.resources {
+		gv, err := schema.ParseGroupVersion(apiResList.GroupVersion)
+		if err != nil {
+			logger.V(4).
+				WithValues(
+					"groupVersion", apiResList.GroupVersion,
+					"error", err,
+				).
+				Info("failed to parse group version")
+			continue
+		}
+
+		for _, res := range apiResList.APIResources {
+			// Subresources such as pods/log share the parent's kind.
+			if strings.Contains(res.Name, "/") || !slices.Contains(res.Verbs, "get") {
+				continue
+			}
+
+			gvk := gv.WithKind(res.Kind)
+			if _, ok := schemas.GetByGVK(gvk); ok {
+				continue
+			}
+
+			entry := &apischema.SchemaEntry{
+				Key:    stubKey(gvk),
+				Schema: stubSchema(gvk, res.Namespaced, hasObjectMeta),
+				GVK:    &gvk,
+			}
+			if schemas.Add(entry) {
+				logger.V(4).Info("added discovery-only schema stub", "gvk", gvk)
+			}
+		}
+	}
+
+	return nil
+}
+
+// stubKey returns the schema key of a stub, e.g. "project.openshift.io.v1.Project".
+func stubKey(gvk schema.GroupVersionKind) string {
+	if gvk.Group == "" {
+		return gvk.Version + "." + gvk.Kind
+	}
+	return gvk.Group + "." + gvk.Version + "." + gvk.Kind
+}
+
+func stubSchema(gvk schema.GroupVersionKind, namespaced, hasObjectMeta bool) *spec.Schema {
+	openObject := func(description string) spec.Schema {
+		s := spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type:        []string{"object"},
+				Description: description,
+			},
+		}
+		s.AddExtension("x-kubernetes-preserve-unknown-fields", true)
+		return s
+	}
+
+	metadata := openObject("Standard object's metadata.")
+	if hasObjectMeta {
+		metadata = spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Standard object's metadata.",
+				AllOf:       []spec.Schema{{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef(objectMetaKey)}}},
+			},
+		}
+	}
+
+	scope := apiextensionsv1.ClusterScoped
+	if namespaced {
+		scope = apiextensionsv1.NamespaceScoped
+	}
+
+	s := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:        []string{"object"},
+			Description: gvk.Kind + " is served by the API server without an OpenAPI schema; spec and status are untyped.",
+			Properties: map[string]spec.Schema{
+				"apiVersion": {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+				"kind":       {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+				"metadata":   metadata,
+				"spec":       openObject("Desired state, untyped because the API server publishes no schema."),
+				"status":     openObject("Observed state, untyped because the API server publishes no schema."),
+			},
+		},
+	}
+	extensions.SetGVK(s, gvk)
+	extensions.SetScope(s, scope)
+	return s
+}
@@ -0,0 +1,102 @@
+package enricher_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/apischema/enricher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// openShiftResources mimics discovery of the aggregated project.openshift.io
+// API, whose kinds are missing from the OpenAPI document.
+var openShiftResources = []*metav1.APIResourceList{
+	{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{
+			{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: []string{"get", "list"}},
+			{Name: "pods/log", Kind: "Pod", Namespaced: true, Verbs: []string{"get"}},
+		},
+	},
+	{
+		GroupVersion: "project.openshift.io/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "projects", Kind: "Project", Namespaced: false, Verbs: []string{"get", "list", "watch"}},
+			{Name: "projectrequests", Kind: "ProjectRequest", Namespaced: false, Verbs: []string{"create", "list"}},
+		},
+	},
+}
+
+func TestDiscoveryStubsEnricher(t *testing.T) {
+	podSchema := &spec.Schema{
+		VendorExtensible: spec.VendorExtensible{
+			Extensions: map[string]any{
+				extensions.GVKKey: []map[string]any{
+					{"group": "", "version": "v1", "kind": "Pod"},
+				},
+			},
+		},
+	}
+
+	schemas := apischema.NewSchemaSetFromMap(map[string]*spec.Schema{
+		"io.k8s.api.core.v1.Pod":                          podSchema,
+		"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {},
+	})
+
+	e := enricher.NewDiscoveryStubs(openShiftResources)
+	require.NoError(t, e.Enrich(t.Context(), schemas))
+
+	assert.Equal(t, 3, schemas.Size(), "only Project should be stubbed")
+	_, ok := schemas.GetByGVK(schema.GroupVersionKind{Group: "project.openshift.io", Version: "v1", Kind: "ProjectRequest"})
+	assert.False(t, ok, "resources without get must not be stubbed")
+
+	pod, ok := schemas.GetByGVK(schema.GroupVersionKind{Version: "v1", Kind: "Pod"})
+	require.True(t, ok)
+	assert.Same(t, podSchema, pod.Schema, "existing schemas must not be replaced")
+
+	project, ok := schemas.Get("project.openshift.io.v1.Project")
+	require.True(t, ok)
+	assert.Equal(t, &schema.GroupVersionKind{Group: "project.openshift.io", Version: "v1", Kind: "Project"}, project.GVK)
+	assert.Equal(t, "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta", project.Schema.Properties["metadata"].AllOf[0].Ref.String())
+	for _, name := range []string{"spec", "status"} {
+		prop := project.Schema.Properties[name]
+		assert.Equal(t, spec.StringOrArray{"object"}, prop.Type, name)
+		assert.Empty(t, prop.Properties, name)
+		assert.Equal(t, true, prop.Extensions["x-kubernetes-preserve-unknown-fields"], name)
+	}
+}
+
+func TestDiscoveryStubsEnricher_RoundTrip(t *testing.T) {
+	schemas := apischema.NewSchemaSetFromMap(map[string]*spec.Schema{})
+
+	require.NoError(t, enricher.NewDiscoveryStubs(openShiftResources).Enrich(t.Context(), schemas))
+
+	data, err := schemas.Marshal()
+	require.NoError(t, err)
+
+	var doc spec3.OpenAPI
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	project := doc.Components.Schemas["project.openshift.io.v1.Project"]
+	require.NotNil(t, project)
+
+	gvk, err := extensions.GVK(project)
+	require.NoError(t, err)
+	assert.Equal(t, &schema.GroupVersionKind{Group: "project.openshift.io", Version: "v1", Kind: "Project"}, gvk)
+
+	scope, err := extensions.Scope(project)
+	require.NoError(t, err)
+	assert.Equal(t, apiextensionsv1.ClusterScoped, scope)
+
+	// Without an ObjectMeta schema, metadata falls back to an open-ended object.
+	assert.Equal(t, spec.StringOrArray{"object"}, project.Properties["metadata"].Type)
+}
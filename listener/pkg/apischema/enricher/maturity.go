@@ -0,0 +1,73 @@
+package enricher
+
+import (
+	"context"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apis"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+
+	apiextensionsv1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Maturity adds x-kubernetes-maturity extension to the schemas of custom
+// resources whose CRD is tagged with the maturity annotation, so the gateway
+// can hide alpha or beta kinds. CRDs that cannot be listed are logged and
+// skipped, leaving all kinds untagged.
+type Maturity struct {
+	crds apiextensionsv1client.CustomResourceDefinitionInterface
+}
+
+// NewMaturity creates a new Maturity enricher.
+func NewMaturity(crds apiextensionsv1client.CustomResourceDefinitionInterface) *Maturity {
+	return &Maturity{crds: crds}
+}
+
+// Name returns the enricher name for logging.
+func (e *Maturity) Name() string {
+	return "maturity"
+}
+
+// Enrich tags the schemas of all served versions of a CRD with its maturity.
+func (e *Maturity) Enrich(ctx context.Context, schemas *apischema.SchemaSet) error {
+	logger := log.FromContext(ctx)
+
+	crds, err := e.crds.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		logger.V(4).WithValues("error", err).Info("failed to list custom resource definitions")
+		return nil
+	}
+
+	for _, crd := range crds.Items {
+		maturity, ok := crd.Annotations[apis.MaturityAnnotation]
+		if !ok {
+			continue
+		}
+		if maturity != apis.MaturityAlpha && maturity != apis.MaturityBeta && maturity != apis.MaturityStable {
+			logger.Info("ignoring invalid maturity annotation", "crd", crd.Name, "maturity", maturity)
+			continue
+		}
+
+		for _, version := range crd.Spec.Versions {
+			if !version.Served {
+				continue
+			}
+
+			entry, ok := schemas.GetByGVK(schema.GroupVersionKind{
+				Group:   crd.Spec.Group,
+				Version: version.Name,
+				Kind:    crd.Spec.Names.Kind,
+			})
+			if !ok {
+				continue
+			}
+
+			extensions.SetMaturity(entry.Schema, maturity)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package enricher_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apis"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/apischema/enricher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestMaturityEnricher(t *testing.T) {
+	crd := func(name, kind, maturity string, versions ...apiextensionsv1.CustomResourceDefinitionVersion) *apiextensionsv1.CustomResourceDefinition {
+		c := &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group:    "example.com",
+				Names:    apiextensionsv1.CustomResourceDefinitionNames{Kind: kind},
+				Versions: versions,
+			},
+		}
+		if maturity != "" {
+			c.Annotations = map[string]string{apis.MaturityAnnotation: maturity}
+		}
+		return c
+	}
+
+	clientset := fake.NewClientset(
+		crd("accounts.example.com", "Account", apis.MaturityAlpha,
+			apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true},
+			apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha2", Served: true},
+		),
+		crd("widgets.example.com", "Widget", "experimental",
+			apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true},
+		),
+		crd("gadgets.example.com", "Gadget", "",
+			apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true},
+		),
+	)
+
+	schemas := apischema.NewSchemaSetFromMap(map[string]*spec.Schema{
+		"com.example.v1alpha1.Account": resourceSchema("example.com", "v1alpha1", "Account"),
+		"com.example.v1alpha2.Account": resourceSchema("example.com", "v1alpha2", "Account"),
+		"com.example.v1.Widget":        resourceSchema("example.com", "v1", "Widget"),
+		"com.example.v1.Gadget":        resourceSchema("example.com", "v1", "Gadget"),
+	})
+
+	e := enricher.NewMaturity(clientset.ApiextensionsV1().CustomResourceDefinitions())
+	require.NoError(t, e.Enrich(t.Context(), schemas))
+
+	want := map[string]string{
+		"com.example.v1alpha1.Account": apis.MaturityAlpha,
+		"com.example.v1alpha2.Account": apis.MaturityAlpha,
+		"com.example.v1.Widget":        "",
+		"com.example.v1.Gadget":        "",
+	}
+	for key, maturity := range want {
+		entry, ok := schemas.Get(key)
+		require.True(t, ok)
+		assert.Equal(t, maturity, extensions.Maturity(entry.Schema), key)
+	}
+
+	// The gateway reads the maturity from the schema file.
+	entry, _ := schemas.Get("com.example.v1alpha1.Account")
+	data, err := json.Marshal(entry.Schema)
+	require.NoError(t, err)
+	var decoded spec.Schema
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, apis.MaturityAlpha, extensions.Maturity(&decoded))
+}
@@ -0,0 +1,63 @@
+package enricher
+
+import (
+	"context"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+
+	apiextensionsv1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// PrinterColumns adds x-kubernetes-print-columns extension to the schemas of
+// custom resources with additional printer columns, so the gateway can sort
+// by column name like kubectl shows them. CRDs that cannot be listed are
+// logged and skipped, as the columns are not required to serve a schema.
+type PrinterColumns struct {
+	crds apiextensionsv1client.CustomResourceDefinitionInterface
+}
+
+// NewPrinterColumns creates a new PrinterColumns enricher.
+func NewPrinterColumns(crds apiextensionsv1client.CustomResourceDefinitionInterface) *PrinterColumns {
+	return &PrinterColumns{crds: crds}
+}
+
+// Name returns the enricher name for logging.
+func (e *PrinterColumns) Name() string {
+	return "printer-columns"
+}
+
+// Enrich adds the printer columns of each served CRD version to its schema.
+func (e *PrinterColumns) Enrich(ctx context.Context, schemas *apischema.SchemaSet) error {
+	logger := log.FromContext(ctx)
+
+	crds, err := e.crds.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		logger.V(4).WithValues("error", err).Info("failed to list custom resource definitions")
+		return nil
+	}
+
+	for _, crd := range crds.Items {
+		for _, version := range crd.Spec.Versions {
+			if !version.Served || len(version.AdditionalPrinterColumns) == 0 {
+				continue
+			}
+
+			entry, ok := schemas.GetByGVK(schema.GroupVersionKind{
+				Group:   crd.Spec.Group,
+				Version: version.Name,
+				Kind:    crd.Spec.Names.Kind,
+			})
+			if !ok {
+				continue
+			}
+
+			extensions.SetPrinterColumns(entry.Schema, version.AdditionalPrinterColumns)
+		}
+	}
+
+	return nil
+}
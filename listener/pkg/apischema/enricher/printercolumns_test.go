@@ -0,0 +1,79 @@
+package enricher_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/apischema/enricher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestPrinterColumnsEnricher(t *testing.T) {
+	columns := []apiextensionsv1.CustomResourceColumnDefinition{
+		{Name: "Ready", Type: "string", JSONPath: `.status.conditions[?(@.type=="Ready")].status`},
+		{Name: "Replicas", Type: "integer", JSONPath: ".spec.replicas", Priority: 1},
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "accounts.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Account"},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true, AdditionalPrinterColumns: columns},
+				{Name: "v1beta1", Served: true},
+			},
+		},
+	}
+
+	schemas := apischema.NewSchemaSetFromMap(map[string]*spec.Schema{
+		"com.example.v1.Account":      resourceSchema("example.com", "v1", "Account"),
+		"com.example.v1beta1.Account": resourceSchema("example.com", "v1beta1", "Account"),
+	})
+
+	e := enricher.NewPrinterColumns(fake.NewClientset(crd).ApiextensionsV1().CustomResourceDefinitions())
+	require.NoError(t, e.Enrich(t.Context(), schemas))
+
+	entry, ok := schemas.GetByGVK(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Account"})
+	require.True(t, ok)
+	assert.Equal(t, columns, extensions.PrinterColumns(entry.Schema))
+
+	// The gateway reads the columns from the schema file.
+	data, err := json.Marshal(entry.Schema)
+	require.NoError(t, err)
+	var decoded spec.Schema
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, columns, extensions.PrinterColumns(&decoded))
+
+	entry, ok = schemas.GetByGVK(schema.GroupVersionKind{Group: "example.com", Version: "v1beta1", Kind: "Account"})
+	require.True(t, ok)
+	assert.Nil(t, extensions.PrinterColumns(entry.Schema))
+}
+
+func TestPrinterColumnsEnricher_ListFails(t *testing.T) {
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("list", "customresourcedefinitions", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("forbidden")
+	})
+
+	schemas := apischema.NewSchemaSetFromMap(map[string]*spec.Schema{
+		"com.example.v1.Account": resourceSchema("example.com", "v1", "Account"),
+	})
+
+	e := enricher.NewPrinterColumns(clientset.ApiextensionsV1().CustomResourceDefinitions())
+	require.NoError(t, e.Enrich(t.Context(), schemas))
+
+	entry, _ := schemas.Get("com.example.v1.Account")
+	assert.NotContains(t, entry.Schema.Extensions, extensions.PrinterColumnsKey)
+}
@@ -3,8 +3,8 @@ package enricher
 import (
 	"context"
 
-	"github.com/platform-mesh/kubernetes-graphql-gateway/apis"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -47,9 +47,9 @@ func (e *Scope) Enrich(ctx context.Context, schemas *apischema.SchemaSet) error
 		}
 
 		if namespaced {
-			entry.Schema.AddExtension(apis.ScopeExtensionKey, apiextensionsv1.NamespaceScoped)
+			extensions.SetScope(entry.Schema, apiextensionsv1.NamespaceScoped)
 		} else {
-			entry.Schema.AddExtension(apis.ScopeExtensionKey, apiextensionsv1.ClusterScoped)
+			extensions.SetScope(entry.Schema, apiextensionsv1.ClusterScoped)
 		}
 	}
 
@@ -3,8 +3,8 @@ package enricher_test
 import (
 	"testing"
 
-	"github.com/platform-mesh/kubernetes-graphql-gateway/apis"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/apischema/enricher"
 	"github.com/stretchr/testify/assert"
 
@@ -18,7 +18,7 @@ func TestScopeEnricher(t *testing.T) {
 	podSchema := &spec.Schema{
 		VendorExtensible: spec.VendorExtensible{
 			Extensions: map[string]any{
-				apis.GVKExtensionKey: []map[string]any{
+				extensions.GVKKey: []map[string]any{
 					{"group": "", "version": "v1", "kind": "Pod"},
 				},
 			},
@@ -27,7 +27,7 @@ func TestScopeEnricher(t *testing.T) {
 	nodeSchema := &spec.Schema{
 		VendorExtensible: spec.VendorExtensible{
 			Extensions: map[string]any{
-				apis.GVKExtensionKey: []map[string]any{
+				extensions.GVKKey: []map[string]any{
 					{"group": "", "version": "v1", "kind": "Node"},
 				},
 			},
@@ -52,12 +52,12 @@ func TestScopeEnricher(t *testing.T) {
 	// Check Pod is namespaced
 	podEntry, ok := schemas.Get("v1.Pod")
 	assert.True(t, ok, "expected v1.Pod to exist in schema set")
-	assert.Equal(t, apiextensionsv1.NamespaceScoped, podEntry.Schema.Extensions[apis.ScopeExtensionKey])
+	assert.Equal(t, apiextensionsv1.NamespaceScoped, podEntry.Schema.Extensions[extensions.ScopeKey])
 
 	// Check Node is cluster-scoped
 	nodeEntry, ok := schemas.Get("v1.Node")
 	assert.True(t, ok, "expected v1.Node to exist in schema set")
-	assert.Equal(t, apiextensionsv1.ClusterScoped, nodeEntry.Schema.Extensions[apis.ScopeExtensionKey])
+	assert.Equal(t, apiextensionsv1.ClusterScoped, nodeEntry.Schema.Extensions[extensions.ScopeKey])
 }
 
 func TestScopeEnricherName(t *testing.T) {
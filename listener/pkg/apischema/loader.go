@@ -5,9 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"maps"
+	"slices"
+	"strings"
 
 	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/openapi"
 	"k8s.io/kube-openapi/pkg/schemamutation"
@@ -40,10 +44,11 @@ func (l *SchemaLoader) Load(ctx context.Context, oc openapi.Client) (*apischema.
 	}
 
 	entries := make(map[string]*apischema.SchemaEntry)
+	subresources := make(map[schema.GroupVersionKind][]string)
 	walker := createRefWalker()
 
 	for pathKey, path := range paths {
-		pathEntries, errs := l.loadPath(ctx, path, walker)
+		pathEntries, pathSubresources, errs := l.loadPath(ctx, path, walker)
 		for _, e := range errs {
 			logger.V(4).Info("error loading schema path",
 				"path", pathKey,
@@ -51,6 +56,16 @@ func (l *SchemaLoader) Load(ctx context.Context, oc openapi.Client) (*apischema.
 		}
 
 		maps.Copy(entries, pathEntries)
+		maps.Copy(subresources, pathSubresources)
+	}
+
+	for _, entry := range entries {
+		if entry.GVK == nil {
+			continue
+		}
+		if names, ok := subresources[*entry.GVK]; ok {
+			extensions.SetSubresources(entry.Schema, names)
+		}
 	}
 
 	logger.Info("loaded schemas", "count", len(entries))
@@ -62,7 +77,7 @@ func (l *SchemaLoader) loadPath(
 	ctx context.Context,
 	path openapi.GroupVersion,
 	walker schemamutation.Walker,
-) (map[string]*apischema.SchemaEntry, []error) {
+) (map[string]*apischema.SchemaEntry, map[schema.GroupVersionKind][]string, []error) {
 	logger := log.FromContext(ctx)
 	entries := make(map[string]*apischema.SchemaEntry)
 	var errs []error
@@ -70,24 +85,26 @@ func (l *SchemaLoader) loadPath(
 	schemaBytes, err := path.Schema(discovery.AcceptV2)
 	if err != nil {
 		errs = append(errs, err)
-		return entries, errs
+		return entries, nil, errs
 	}
 
 	var openAPISpec spec3.OpenAPI
 	if err := json.Unmarshal(schemaBytes, &openAPISpec); err != nil {
 		errs = append(errs, err)
-		return entries, errs
+		return entries, nil, errs
 	}
 
+	subresources := pathSubresources(openAPISpec.Paths)
+
 	if openAPISpec.Components == nil {
-		return entries, errs
+		return entries, subresources, errs
 	}
 
 	for key, schema := range openAPISpec.Components.Schemas {
 		// Walk and normalize refs
 		walked := walker.WalkSchema(schema)
 
-		gvk, err := apischema.ExtractGVK(walked)
+		gvk, err := extensions.GVK(walked)
 		if err != nil {
 			logger.V(4).Info("failed to extract GVK",
 				"key", key,
@@ -103,7 +120,51 @@ func (l *SchemaLoader) loadPath(
 		}
 	}
 
-	return entries, errs
+	return entries, subresources, errs
+}
+
+// pathSubresources returns the subresources served per kind, derived from
+// paths such as /apis/apps/v1/namespaces/{namespace}/deployments/{name}/status.
+// The kind is taken from the parent resource's path, because subresources
+// like scale are typed differently than their parent.
+func pathSubresources(paths *spec3.Paths) map[schema.GroupVersionKind][]string {
+	subresources := make(map[schema.GroupVersionKind][]string)
+	if paths == nil {
+		return subresources
+	}
+
+	for pathKey := range paths.Paths {
+		parent, name, ok := strings.Cut(pathKey, "/{name}/")
+		if !ok || name == "" || strings.Contains(name, "/") {
+			continue
+		}
+
+		parentPath, ok := paths.Paths[parent+"/{name}"]
+		if !ok || parentPath.Get == nil {
+			continue
+		}
+
+		gvkMap, ok := parentPath.Get.Extensions[extensions.GVKKey].(map[string]any)
+		if !ok {
+			continue
+		}
+		group, _ := gvkMap["group"].(string)
+		version, _ := gvkMap["version"].(string)
+		kind, _ := gvkMap["kind"].(string)
+		if kind == "" {
+			continue
+		}
+
+		gvk := schema.GroupVersionKind{Group: group, Version: version, Kind: kind}
+		if !slices.Contains(subresources[gvk], name) {
+			subresources[gvk] = append(subresources[gvk], name)
+		}
+	}
+
+	for _, names := range subresources {
+		slices.Sort(names)
+	}
+	return subresources
 }
 
 // createRefWalker creates a schema walker that normalizes $ref pointers.
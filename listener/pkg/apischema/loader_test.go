@@ -3,11 +3,15 @@ package apischema_test
 import (
 	"testing"
 
-	"github.com/platform-mesh/kubernetes-graphql-gateway/apis"
-	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
+	listenerapischema "github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/apischema"
+	apischemaMocks "github.com/platform-mesh/kubernetes-graphql-gateway/listener/pkg/apischema/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/openapi"
 	"k8s.io/kube-openapi/pkg/validation/spec"
 )
 
@@ -23,7 +27,7 @@ func TestExtractGVK(t *testing.T) {
 			schema: &spec.Schema{
 				VendorExtensible: spec.VendorExtensible{
 					Extensions: map[string]any{
-						apis.GVKExtensionKey: []any{
+						extensions.GVKKey: []any{
 							map[string]any{
 								"group":   "apps",
 								"version": "v1",
@@ -45,7 +49,7 @@ func TestExtractGVK(t *testing.T) {
 			schema: &spec.Schema{
 				VendorExtensible: spec.VendorExtensible{
 					Extensions: map[string]any{
-						apis.GVKExtensionKey: []any{
+						extensions.GVKKey: []any{
 							map[string]any{
 								"group":   "",
 								"version": "v1",
@@ -85,7 +89,7 @@ func TestExtractGVK(t *testing.T) {
 			schema: &spec.Schema{
 				VendorExtensible: spec.VendorExtensible{
 					Extensions: map[string]any{
-						apis.GVKExtensionKey: []any{
+						extensions.GVKKey: []any{
 							map[string]any{"group": "a", "version": "v1", "kind": "A"},
 							map[string]any{"group": "b", "version": "v1", "kind": "B"},
 						},
@@ -100,7 +104,7 @@ func TestExtractGVK(t *testing.T) {
 			schema: &spec.Schema{
 				VendorExtensible: spec.VendorExtensible{
 					Extensions: map[string]any{
-						apis.GVKExtensionKey: []any{},
+						extensions.GVKKey: []any{},
 					},
 				},
 			},
@@ -111,7 +115,7 @@ func TestExtractGVK(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			gvk, err := apischema.ExtractGVK(tc.schema)
+			gvk, err := extensions.GVK(tc.schema)
 
 			if tc.wantErr {
 				assert.Error(t, err)
@@ -123,3 +127,57 @@ func TestExtractGVK(t *testing.T) {
 		})
 	}
 }
+
+// appsV1Document is a trimmed apps/v1 OpenAPI document with the paths of
+// Deployment and its subresources.
+const appsV1Document = `{
+  "openapi": "3.0.0",
+  "paths": {
+    "/apis/apps/v1/namespaces/{namespace}/deployments": {
+      "get": {"x-kubernetes-group-version-kind": {"group": "apps", "version": "v1", "kind": "Deployment"}}
+    },
+    "/apis/apps/v1/namespaces/{namespace}/deployments/{name}": {
+      "get": {"x-kubernetes-group-version-kind": {"group": "apps", "version": "v1", "kind": "Deployment"}}
+    },
+    "/apis/apps/v1/namespaces/{namespace}/deployments/{name}/status": {
+      "get": {"x-kubernetes-group-version-kind": {"group": "apps", "version": "v1", "kind": "Deployment"}}
+    },
+    "/apis/apps/v1/namespaces/{namespace}/deployments/{name}/scale": {
+      "get": {"x-kubernetes-group-version-kind": {"group": "autoscaling", "version": "v1", "kind": "Scale"}}
+    },
+    "/apis/apps/v1/namespaces/{namespace}/controllerrevisions/{name}": {
+      "get": {"x-kubernetes-group-version-kind": {"group": "apps", "version": "v1", "kind": "ControllerRevision"}}
+    }
+  },
+  "components": {
+    "schemas": {
+      "io.k8s.api.apps.v1.Deployment": {
+        "type": "object",
+        "x-kubernetes-group-version-kind": [{"group": "apps", "version": "v1", "kind": "Deployment"}]
+      },
+      "io.k8s.api.apps.v1.ControllerRevision": {
+        "type": "object",
+        "x-kubernetes-group-version-kind": [{"group": "apps", "version": "v1", "kind": "ControllerRevision"}]
+      }
+    }
+  }
+}`
+
+func TestSchemaLoader_Subresources(t *testing.T) {
+	gv := apischemaMocks.NewMockGroupVersion(t)
+	gv.EXPECT().Schema(mock.Anything).Return([]byte(appsV1Document), nil)
+
+	client := apischemaMocks.NewMockClient(t)
+	client.EXPECT().Paths().Return(map[string]openapi.GroupVersion{"apis/apps/v1": gv}, nil)
+
+	schemas, err := listenerapischema.NewSchemaLoader().Load(t.Context(), client)
+	require.NoError(t, err)
+
+	deployment, ok := schemas.Get("io.k8s.api.apps.v1.Deployment")
+	require.True(t, ok)
+	assert.Equal(t, []string{"scale", "status"}, extensions.Subresources(deployment.Schema))
+
+	revision, ok := schemas.Get("io.k8s.api.apps.v1.ControllerRevision")
+	require.True(t, ok)
+	assert.Nil(t, extensions.Subresources(revision.Schema))
+}
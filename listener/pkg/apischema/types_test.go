@@ -3,8 +3,8 @@ package apischema_test
 import (
 	"testing"
 
-	"github.com/platform-mesh/kubernetes-graphql-gateway/apis"
 	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/extensions"
 	"github.com/stretchr/testify/assert"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -16,7 +16,7 @@ func TestSchemaSet_O1_Lookups(t *testing.T) {
 	podSchema := &spec.Schema{
 		VendorExtensible: spec.VendorExtensible{
 			Extensions: map[string]any{
-				apis.GVKExtensionKey: []map[string]any{
+				extensions.GVKKey: []map[string]any{
 					{"group": "", "version": "v1", "kind": "Pod"},
 				},
 			},
@@ -25,7 +25,7 @@ func TestSchemaSet_O1_Lookups(t *testing.T) {
 	deploymentSchema := &spec.Schema{
 		VendorExtensible: spec.VendorExtensible{
 			Extensions: map[string]any{
-				apis.GVKExtensionKey: []map[string]any{
+				extensions.GVKKey: []map[string]any{
 					{"group": "apps", "version": "v1", "kind": "Deployment"},
 				},
 			},
@@ -35,7 +35,7 @@ func TestSchemaSet_O1_Lookups(t *testing.T) {
 	customPodSchema := &spec.Schema{
 		VendorExtensible: spec.VendorExtensible{
 			Extensions: map[string]any{
-				apis.GVKExtensionKey: []map[string]any{
+				extensions.GVKKey: []map[string]any{
 					{"group": "custom.io", "version": "v1", "kind": "Pod"},
 				},
 			},
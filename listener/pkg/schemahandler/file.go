@@ -1,11 +1,15 @@
 package schemahandler
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
+
+	"github.com/platform-mesh/kubernetes-graphql-gateway/apischema/changelog"
 )
 
 var (
@@ -18,6 +22,9 @@ var (
 type FileHandler struct {
 	// schemasDir is the base directory where schema files are stored.
 	schemasDir string
+	// changelogSize is how many changes are appended to the changelog of a
+	// schema before its file is rewritten. 0 rewrites it on every change.
+	changelogSize int
 }
 
 // NewFileHandler constructs a concrete FileHandler that stores files under schemasDir.
@@ -28,18 +35,56 @@ func NewFileHandler(schemasDir string) (*FileHandler, error) {
 	return &FileHandler{schemasDir: schemasDir}, nil
 }
 
-// Read reads the schema file for the given cluster name (relative path) from the schemasDir.
+// WithChangelog appends up to size changes of a schema to its changelog as
+// JSON Patches before the schema file is rewritten.
+func (h *FileHandler) WithChangelog(size int) *FileHandler {
+	h.changelogSize = size
+	return h
+}
+
+// Read reads the schema file for the given cluster name (relative path) from the schemasDir,
+// with the changes in its changelog applied.
 func (h *FileHandler) Read(_ context.Context, clusterName string) ([]byte, error) {
 	fileName := path.Join(h.schemasDir, clusterName)
 	JSON, err := os.ReadFile(fileName)
 	if err != nil {
 		return nil, errors.Join(ErrNotExist, err)
 	}
-	return JSON, nil
+
+	log, err := os.ReadFile(h.changelogFile(clusterName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return JSON, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	hash, err := changelog.Hash(JSON)
+	if err != nil {
+		return nil, err
+	}
+	JSON, _, err = changelog.Replay(JSON, hash, log)
+	return JSON, err
 }
 
 // Write writes the given JSON bytes under the clusterName path, creating subdirectories as needed.
-func (h *FileHandler) Write(_ context.Context, JSON []byte, clusterName string) error {
+// With a changelog, the change is appended to it instead while it has room.
+func (h *FileHandler) Write(ctx context.Context, JSON []byte, clusterName string) error {
+	if h.changelogSize > 0 {
+		appended, err := h.appendChange(ctx, JSON, clusterName)
+		if err != nil {
+			return errors.Join(ErrWriteJSONFile, err)
+		}
+		if appended {
+			return nil
+		}
+	}
+
+	// The changelog is removed first, so its entries are not applied to the
+	// new schema file.
+	if err := os.Remove(h.changelogFile(clusterName)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return errors.Join(ErrWriteJSONFile, err)
+	}
+
 	fileName := path.Join(h.schemasDir, clusterName)
 	// Create intermediate directories if they don't exist
 	dir := filepath.Dir(fileName)
@@ -52,11 +97,66 @@ func (h *FileHandler) Write(_ context.Context, JSON []byte, clusterName string)
 	return nil
 }
 
-// Delete removes the schema file for the given cluster name.
+// appendChange appends the change from the current schema of clusterName to
+// JSON to its changelog. It returns false if the schema file has to be
+// written instead, because it does not exist or the changelog is full.
+func (h *FileHandler) appendChange(ctx context.Context, JSON []byte, clusterName string) (bool, error) {
+	log, err := os.ReadFile(h.changelogFile(clusterName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+	if bytes.Count(log, []byte("\n")) >= h.changelogSize {
+		return false, nil
+	}
+
+	current, err := h.Read(ctx, clusterName)
+	if errors.Is(err, ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	entry, err := changelog.Diff(current, JSON)
+	if err != nil {
+		return false, err
+	}
+	if entry.Empty() {
+		return true, nil
+	}
+	line, err := entry.Marshal()
+	if err != nil {
+		return false, err
+	}
+
+	fileName := h.changelogFile(clusterName)
+	if err := os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+		return false, err
+	}
+	f, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return false, err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close() //nolint:errcheck
+		return false, err
+	}
+	return true, f.Close()
+}
+
+// Delete removes the schema file and changelog for the given cluster name.
 func (h *FileHandler) Delete(_ context.Context, clusterName string) error {
+	if err := os.Remove(h.changelogFile(clusterName)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
 	fileName := path.Join(h.schemasDir, clusterName)
 	if err := os.Remove(fileName); err != nil {
 		return errors.Join(ErrNotExist, err)
 	}
 	return nil
 }
+
+// changelogFile returns the path of the changelog of the schema of clusterName.
+func (h *FileHandler) changelogFile(clusterName string) string {
+	return path.Join(h.schemasDir, changelog.Dir, clusterName)
+}
@@ -135,3 +135,38 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteChangelog(t *testing.T) {
+	tempDir := t.TempDir()
+	handler, err := schemahandler.NewFileHandler(tempDir)
+	assert.NoError(t, err)
+	handler = handler.WithChangelog(2)
+
+	cluster := "root:orgs:default"
+	schemaFile := filepath.Join(tempDir, cluster)
+	changelogFile := filepath.Join(tempDir, ".changelog", cluster)
+
+	versions := []string{`{"key":"v1"}`, `{"key":"v2"}`, `{"key":"v3"}`, `{"key":"v4"}`}
+	for _, version := range versions {
+		assert.NoError(t, handler.Write(t.Context(), []byte(version), cluster))
+
+		read, err := handler.Read(t.Context(), cluster)
+		assert.NoError(t, err)
+		assert.JSONEq(t, version, string(read))
+	}
+
+	// v2 and v3 were appended to the changelog, which was full for v4.
+	written, err := os.ReadFile(schemaFile)
+	assert.NoError(t, err)
+	assert.Equal(t, versions[3], string(written))
+	_, err = os.Stat(changelogFile)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, handler.Write(t.Context(), []byte(`{"key":"v5"}`), cluster))
+	_, err = os.Stat(changelogFile)
+	assert.NoError(t, err)
+
+	assert.NoError(t, handler.Delete(t.Context(), cluster))
+	_, err = os.Stat(changelogFile)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,149 @@
+// Package status tracks the reconcile results of the listener's workspaces
+// and serves them with the effective configuration as JSON, so dashboards do
+// not have to scrape logs.
+package status
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Path is where the status is served on the metrics server.
+const Path = "/status"
+
+// Workspace is the reconcile state of an object the listener generates a
+// schema for, e.g. the anchor namespace of a kcp workspace or a
+// ClusterAccess.
+type Workspace struct {
+	// Controller is the controller reconciling the object.
+	Controller string `json:"controller"`
+	// Cluster is the cluster of the object, empty for the single provider.
+	Cluster string `json:"cluster,omitempty"`
+	// Name is the name of the object.
+	Name string `json:"name"`
+	// LastReconcile is when the object was last reconciled.
+	LastReconcile time.Time `json:"lastReconcile"`
+	// LastSuccess is when its schema was last reconciled without error.
+	LastSuccess time.Time `json:"lastSuccess,omitzero"`
+	// Error is the error of the last reconcile, empty if it succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+type key struct {
+	controller, cluster, name string
+}
+
+// Tracker records the reconcile results of the workspaces. A nil Tracker
+// records nothing.
+type Tracker struct {
+	mu         sync.Mutex
+	workspaces map[key]*Workspace
+	now        func() time.Time
+}
+
+// NewTracker creates a Tracker without workspaces.
+func NewTracker() *Tracker {
+	return &Tracker{workspaces: map[key]*Workspace{}, now: time.Now}
+}
+
+// Record records the result of a reconcile of an object.
+func (t *Tracker) Record(controller, cluster, name string, err error) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	k := key{controller: controller, cluster: cluster, name: name}
+	w, ok := t.workspaces[k]
+	if !ok {
+		w = &Workspace{Controller: controller, Cluster: cluster, Name: name}
+		t.workspaces[k] = w
+	}
+
+	w.LastReconcile = t.now()
+	w.Error = ""
+	if err != nil {
+		w.Error = err.Error()
+		return
+	}
+	w.LastSuccess = w.LastReconcile
+}
+
+// Forget removes an object whose schema was removed.
+func (t *Tracker) Forget(controller, cluster, name string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.workspaces, key{controller: controller, cluster: cluster, name: name})
+}
+
+// Workspaces returns the tracked workspaces ordered by controller, cluster
+// and name.
+func (t *Tracker) Workspaces() []Workspace {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	workspaces := make([]Workspace, 0, len(t.workspaces))
+	for _, w := range t.workspaces {
+		workspaces = append(workspaces, *w)
+	}
+	slices.SortFunc(workspaces, func(a, b Workspace) int {
+		return cmp.Or(
+			cmp.Compare(a.Controller, b.Controller),
+			cmp.Compare(a.Cluster, b.Cluster),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	return workspaces
+}
+
+// Status is the document served by Handler.
+type Status struct {
+	// Config is the effective configuration of the listener.
+	Config any `json:"config"`
+	// Workspaces are the tracked workspaces.
+	Workspaces []Workspace `json:"workspaces"`
+	// Failing is the number of workspaces whose last reconcile failed.
+	Failing int `json:"failing"`
+}
+
+// Handler serves the status of the workspaces of a Tracker and config as
+// JSON. It does not authenticate requests; it is meant to be served by the
+// metrics server, which does.
+type Handler struct {
+	tracker *Tracker
+	config  any
+}
+
+// NewHandler returns a Handler for tracker, reporting config as effective
+// configuration.
+func NewHandler(tracker *Tracker, config any) *Handler {
+	return &Handler{tracker: tracker, config: config}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := Status{Config: h.config, Workspaces: h.tracker.Workspaces()}
+	for _, w := range status.Workspaces {
+		if w.Error != "" {
+			status.Failing++
+		}
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(status) //nolint:errcheck
+}
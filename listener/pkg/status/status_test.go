@@ -0,0 +1,72 @@
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker()
+	tracker.now = func() time.Time { return now }
+
+	tracker.Record("resource", "root:b", "default", nil)
+	tracker.Record("resource", "root:a", "default", nil)
+	tracker.Record("clusteraccess", "", "remote", errors.New("connection refused"))
+
+	now = now.Add(time.Minute)
+	tracker.Record("resource", "root:a", "default", errors.New("discovery failed"))
+
+	assert.Equal(t, []Workspace{
+		{Controller: "clusteraccess", Name: "remote", LastReconcile: now.Add(-time.Minute), Error: "connection refused"},
+		{Controller: "resource", Cluster: "root:a", Name: "default", LastReconcile: now, LastSuccess: now.Add(-time.Minute), Error: "discovery failed"},
+		{Controller: "resource", Cluster: "root:b", Name: "default", LastReconcile: now.Add(-time.Minute), LastSuccess: now.Add(-time.Minute)},
+	}, tracker.Workspaces())
+
+	tracker.Forget("resource", "root:a", "default")
+	tracker.Forget("clusteraccess", "", "remote")
+	assert.Len(t, tracker.Workspaces(), 1)
+
+	// A nil tracker records nothing.
+	var disabled *Tracker
+	disabled.Record("resource", "root:a", "default", nil)
+	disabled.Forget("resource", "root:a", "default")
+}
+
+func TestHandler(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Record("resource", "root:a", "default", nil)
+	tracker.Record("resource", "root:b", "default", errors.New("discovery failed"))
+
+	h := NewHandler(tracker, map[string]string{"provider": "kcp"})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var got struct {
+		Config     map[string]string `json:"config"`
+		Workspaces []map[string]any  `json:"workspaces"`
+		Failing    int               `json:"failing"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, map[string]string{"provider": "kcp"}, got.Config)
+	assert.Equal(t, 1, got.Failing)
+	require.Len(t, got.Workspaces, 2)
+	assert.Equal(t, "root:a", got.Workspaces[0]["cluster"])
+	assert.NotContains(t, got.Workspaces[0], "error")
+	assert.Equal(t, "discovery failed", got.Workspaces[1]["error"])
+	assert.NotContains(t, got.Workspaces[1], "lastSuccess")
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -49,6 +49,7 @@ func NewServer(ctx context.Context, c *Config) (*Server, error) {
 			c.Options.AdditonalPathAnnotationKey,
 			c.Options.ClusterMetadataFunc,
 			c.Options.ClusterURLResolverFunc,
+			c.Status,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error setting up Namespace Controller: %w", err)
@@ -65,6 +66,8 @@ func NewServer(ctx context.Context, c *Config) (*Server, error) {
 			s.Config.Manager,
 			opts,
 			s.Config.SchemaHandler,
+			c.Options.VerifyClusterAccessRBAC,
+			c.Status,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error setting up ClusterAccess controller: %w", err)
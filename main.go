@@ -3,9 +3,7 @@ package main
 import (
 	"os"
 
-	"github.com/platform-mesh/kubernetes-graphql-gateway/cmd/gateway"
-	"github.com/platform-mesh/kubernetes-graphql-gateway/cmd/listener"
-	"github.com/spf13/cobra"
+	"github.com/platform-mesh/kubernetes-graphql-gateway/cmd/cli"
 
 	"k8s.io/klog/v2"
 
@@ -13,15 +11,7 @@ import (
 )
 
 func main() {
-	rootCmd := &cobra.Command{
-		Use:   "kubernetes-graphql-gateway",
-		Short: "Kubernetes GraphQL Gateway",
-	}
-
-	rootCmd.AddCommand(listener.NewCommand())
-	rootCmd.AddCommand(gateway.NewCommand())
-
-	if err := rootCmd.Execute(); err != nil {
+	if err := cli.NewRootCommand().Execute(); err != nil {
 		klog.Flush()
 		os.Exit(1)
 	}
@@ -0,0 +1,253 @@
+package workloadidentity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// awsTokenPrefix marks EKS tokens, which are presigned STS
+	// GetCallerIdentity URLs.
+	awsTokenPrefix = "k8s-aws-v1."
+	// awsClusterIDHeader binds a token to the EKS cluster it was issued for.
+	awsClusterIDHeader = "x-k8s-aws-id"
+	// awsPresignExpiry is how long the presigned URL is valid. EKS accepts
+	// tokens for 15 minutes after signing regardless of this value.
+	awsPresignExpiry = 60 * time.Second
+	// awsTokenLifetime is how long a token is used before a new one is signed.
+	awsTokenLifetime = 14 * time.Minute
+
+	awsSTSVersion   = "2011-06-15"
+	awsSTSService   = "sts"
+	awsSignatureAlg = "AWS4-HMAC-SHA256"
+)
+
+// awsCredentials are temporary credentials of the pod's IAM role.
+type awsCredentials struct {
+	AccessKeyID     string `xml:"AccessKeyId"`
+	SecretAccessKey string `xml:"SecretAccessKey"`
+	SessionToken    string `xml:"SessionToken"`
+}
+
+// awsTokenSource creates EKS tokens with the IAM role the pod is annotated
+// with (IRSA): the projected service account token is exchanged for role
+// credentials, which sign an STS GetCallerIdentity request EKS verifies.
+type awsTokenSource struct {
+	httpClient  *http.Client
+	clusterName string
+	region      string
+	endpoint    string
+	roleARN     string
+	tokenFile   string
+	now         func() time.Time
+}
+
+// newAWSTokenSource configures the source from the environment variables the
+// EKS pod identity webhook injects into the pod.
+func newAWSTokenSource(httpClient *http.Client, clusterName, region string) (*awsTokenSource, error) {
+	if clusterName == "" {
+		return nil, errors.New("aws workload identity requires a cluster name")
+	}
+
+	roleARN := os.Getenv("AWS_ROLE_ARN")
+	tokenFile := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+	if roleARN == "" || tokenFile == "" {
+		return nil, errors.New("aws workload identity requires AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE to be set")
+	}
+
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		return nil, errors.New("aws workload identity requires a region")
+	}
+
+	return &awsTokenSource{
+		httpClient:  httpClient,
+		clusterName: clusterName,
+		region:      region,
+		endpoint:    awsSTSEndpoint(region),
+		roleARN:     roleARN,
+		tokenFile:   tokenFile,
+		now:         time.Now,
+	}, nil
+}
+
+// awsSTSEndpoint returns the regional STS endpoint.
+func awsSTSEndpoint(region string) string {
+	domain := "amazonaws.com"
+	if strings.HasPrefix(region, "cn-") {
+		domain = "amazonaws.com.cn"
+	}
+	return "https://sts." + region + "." + domain
+}
+
+// Token implements oauth2.TokenSource.
+func (s *awsTokenSource) Token() (*oauth2.Token, error) {
+	creds, err := s.assumeRole()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	presigned, err := s.presignGetCallerIdentity(creds, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Token{
+		AccessToken: awsTokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(presigned)),
+		TokenType:   "Bearer",
+		Expiry:      now.Add(awsTokenLifetime),
+	}, nil
+}
+
+// assumeRole exchanges the projected service account token for temporary
+// credentials of the role. AssumeRoleWithWebIdentity is not signed.
+func (s *awsTokenSource) assumeRole() (*awsCredentials, error) {
+	webIdentityToken, err := os.ReadFile(s.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read web identity token: %w", err)
+	}
+
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {awsSTSVersion},
+		"RoleArn":          {s.roleARN},
+		"RoleSessionName":  {fmt.Sprintf("kubernetes-graphql-gateway-%d", s.now().UnixNano())},
+		"WebIdentityToken": {strings.TrimSpace(string(webIdentityToken))},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.endpoint+"/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("assume role request failed: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read assume role response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("assume role request failed with status %d: %s", resp.StatusCode, body)
+	}
+
+	var result struct {
+		Credentials awsCredentials `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode assume role response: %w", err)
+	}
+	if result.Credentials.AccessKeyID == "" {
+		return nil, errors.New("assume role response contains no credentials")
+	}
+
+	return &result.Credentials, nil
+}
+
+// presignGetCallerIdentity returns an STS GetCallerIdentity URL presigned
+// with AWS Signature Version 4, including the cluster ID header.
+func (s *awsTokenSource) presignGetCallerIdentity(creds *awsCredentials, now time.Time) (string, error) {
+	endpoint, err := url.Parse(s.endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid STS endpoint: %w", err)
+	}
+
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	credentialScope := date + "/" + s.region + "/" + awsSTSService + "/aws4_request"
+	signedHeaders := "host;" + awsClusterIDHeader
+
+	query := map[string]string{
+		"Action":               "GetCallerIdentity",
+		"Version":              awsSTSVersion,
+		"X-Amz-Algorithm":      awsSignatureAlg,
+		"X-Amz-Credential":     creds.AccessKeyID + "/" + credentialScope,
+		"X-Amz-Date":           amzDate,
+		"X-Amz-Expires":        fmt.Sprintf("%d", int(awsPresignExpiry.Seconds())),
+		"X-Amz-SignedHeaders":  signedHeaders,
+		"X-Amz-Security-Token": creds.SessionToken,
+	}
+	canonicalQuery := awsCanonicalQuery(query)
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		"/",
+		canonicalQuery,
+		"host:" + endpoint.Host + "\n" + awsClusterIDHeader + ":" + s.clusterName + "\n",
+		signedHeaders,
+		hexSHA256(""),
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		awsSignatureAlg,
+		amzDate,
+		credentialScope,
+		hexSHA256(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, awsSTSService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	return s.endpoint + "/?" + canonicalQuery + "&X-Amz-Signature=" + signature, nil
+}
+
+// awsCanonicalQuery encodes query parameters sorted by name, escaping all
+// but the unreserved characters as SigV4 requires.
+func awsCanonicalQuery(query map[string]string) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, awsEscape(name)+"="+awsEscape(query[name]))
+	}
+	return strings.Join(pairs, "&")
+}
+
+func awsEscape(s string) string {
+	// url.QueryEscape escapes all but unreserved characters, except that it
+	// encodes spaces as "+" and leaves "~" unescaped; SigV4 wants "%20".
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hexSHA256(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,77 @@
+package workloadidentity
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// azureKubernetesServerID is the application ID of the Azure Kubernetes
+	// Service AAD server, the audience of tokens AKS accepts.
+	azureKubernetesServerID = "6dae42f8-4368-4678-94ff-3960e28e3630"
+
+	azureDefaultAuthorityHost = "https://login.microsoftonline.com/"
+)
+
+// azureTokenSource exchanges the federated service account token projected
+// by the Azure Workload Identity webhook for a Microsoft Entra access token.
+type azureTokenSource struct {
+	httpClient *http.Client
+	tokenURL   string
+	clientID   string
+	tokenFile  string
+}
+
+// newAzureTokenSource configures the source from the environment variables
+// the Azure Workload Identity webhook injects into the pod.
+func newAzureTokenSource(httpClient *http.Client) (*azureTokenSource, error) {
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	tokenFile := os.Getenv("AZURE_FEDERATED_TOKEN_FILE")
+	if clientID == "" || tenantID == "" || tokenFile == "" {
+		return nil, errors.New("azure workload identity requires AZURE_CLIENT_ID, AZURE_TENANT_ID and AZURE_FEDERATED_TOKEN_FILE to be set")
+	}
+
+	authorityHost := os.Getenv("AZURE_AUTHORITY_HOST")
+	if authorityHost == "" {
+		authorityHost = azureDefaultAuthorityHost
+	}
+
+	return &azureTokenSource{
+		httpClient: httpClient,
+		tokenURL:   strings.TrimSuffix(authorityHost, "/") + "/" + url.PathEscape(tenantID) + "/oauth2/v2.0/token",
+		clientID:   clientID,
+		tokenFile:  tokenFile,
+	}, nil
+}
+
+// Token implements oauth2.TokenSource.
+func (s *azureTokenSource) Token() (*oauth2.Token, error) {
+	// The projected token is rotated by the kubelet, so read it every time.
+	assertion, err := os.ReadFile(s.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read federated token: %w", err)
+	}
+
+	form := url.Values{
+		"client_id":             {s.clientID},
+		"scope":                 {azureKubernetesServerID + "/.default"},
+		"grant_type":            {"client_credentials"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {strings.TrimSpace(string(assertion))},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return doTokenRequest(s.httpClient, req)
+}
@@ -0,0 +1,44 @@
+package workloadidentity
+
+import (
+	"net/http"
+	"os"
+
+	"golang.org/x/oauth2"
+)
+
+// gcpMetadataHost is the GCP metadata server. On GKE with Workload Identity
+// Federation, the GKE metadata server answers for the pod's Kubernetes
+// service account.
+const gcpMetadataHost = "metadata.google.internal"
+
+// gcpTokenSource gets access tokens of the pod's Google service account from
+// the metadata server. GKE accepts them as bearer tokens.
+type gcpTokenSource struct {
+	httpClient *http.Client
+	tokenURL   string
+}
+
+func newGCPTokenSource(httpClient *http.Client) *gcpTokenSource {
+	// GCE_METADATA_HOST is honored by all Google client libraries.
+	host := os.Getenv("GCE_METADATA_HOST")
+	if host == "" {
+		host = gcpMetadataHost
+	}
+
+	return &gcpTokenSource{
+		httpClient: httpClient,
+		tokenURL:   "http://" + host + "/computeMetadata/v1/instance/service-accounts/default/token",
+	}
+}
+
+// Token implements oauth2.TokenSource.
+func (s *gcpTokenSource) Token() (*oauth2.Token, error) {
+	req, err := http.NewRequest(http.MethodGet, s.tokenURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	return doTokenRequest(s.httpClient, req)
+}
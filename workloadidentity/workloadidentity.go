@@ -0,0 +1,110 @@
+// Package workloadidentity obtains Kubernetes API credentials from the cloud
+// workload identity of the pod the listener or gateway runs in: GKE Workload
+// Identity Federation, EKS IAM roles for service accounts (IRSA) and Azure
+// Workload Identity. No secrets are involved; the cloud's pod identity
+// webhook or metadata server provides the identity.
+package workloadidentity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// Provider is a cloud workload identity mechanism.
+type Provider string
+
+const (
+	ProviderGCP   Provider = "GCP"
+	ProviderAWS   Provider = "AWS"
+	ProviderAzure Provider = "Azure"
+)
+
+// Config selects the workload identity to obtain tokens from.
+type Config struct {
+	Provider Provider
+	// ClusterName is the EKS cluster the tokens are issued for. Required for AWS.
+	ClusterName string
+	// Region is the AWS STS region. Defaults to the AWS_REGION of the pod.
+	Region string
+}
+
+// requestTimeout bounds each token request. oauth2.TokenSource has no
+// context, so the timeout is set on the HTTP client.
+const requestTimeout = 30 * time.Second
+
+// NewTokenSource returns a token source for the configured provider. Tokens
+// are cached until shortly before they expire.
+func NewTokenSource(cfg Config) (oauth2.TokenSource, error) {
+	httpClient := &http.Client{Timeout: requestTimeout}
+
+	var ts oauth2.TokenSource
+	var err error
+	switch cfg.Provider {
+	case ProviderGCP:
+		ts = newGCPTokenSource(httpClient)
+	case ProviderAWS:
+		ts, err = newAWSTokenSource(httpClient, cfg.ClusterName, cfg.Region)
+	case ProviderAzure:
+		ts, err = newAzureTokenSource(httpClient)
+	default:
+		return nil, fmt.Errorf("unsupported workload identity provider %q", cfg.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return oauth2.ReuseTokenSource(nil, ts), nil
+}
+
+// WrapTransport returns a function for rest.Config.Wrap that authenticates
+// requests with tokens from ts.
+func WrapTransport(ts oauth2.TokenSource) func(http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &oauth2.Transport{Source: ts, Base: rt}
+	}
+}
+
+// tokenResponse is the OAuth2 token response of the GCP metadata server and
+// the Microsoft identity platform.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// doTokenRequest sends req and decodes an OAuth2 token response.
+func doTokenRequest(httpClient *http.Client, req *http.Request) (*oauth2.Token, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, body)
+	}
+
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tr.AccessToken == "" {
+		return nil, errors.New("token response contains no access token")
+	}
+
+	return &oauth2.Token{
+		AccessToken: tr.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second),
+	}, nil
+}
@@ -0,0 +1,197 @@
+package workloadidentity
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// writeTokenFile writes a projected service account token and returns its path.
+func writeTokenFile(t *testing.T, token string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte(token+"\n"), 0o600))
+	return path
+}
+
+func TestNewTokenSource(t *testing.T) {
+	t.Setenv("AZURE_CLIENT_ID", "")
+	t.Setenv("AWS_ROLE_ARN", "")
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name: "gcp",
+			cfg:  Config{Provider: ProviderGCP},
+		},
+		{
+			name:    "aws without cluster name",
+			cfg:     Config{Provider: ProviderAWS},
+			wantErr: "aws workload identity requires a cluster name",
+		},
+		{
+			name:    "aws outside of an IRSA pod",
+			cfg:     Config{Provider: ProviderAWS, ClusterName: "prod"},
+			wantErr: "aws workload identity requires AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE to be set",
+		},
+		{
+			name:    "azure outside of a workload identity pod",
+			cfg:     Config{Provider: ProviderAzure},
+			wantErr: "azure workload identity requires AZURE_CLIENT_ID, AZURE_TENANT_ID and AZURE_FEDERATED_TOKEN_FILE to be set",
+		},
+		{
+			name:    "unsupported provider",
+			cfg:     Config{Provider: "IBM"},
+			wantErr: `unsupported workload identity provider "IBM"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, err := NewTokenSource(tt.cfg)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, ts)
+		})
+	}
+}
+
+func TestGCPTokenSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+		assert.Equal(t, "/computeMetadata/v1/instance/service-accounts/default/token", r.URL.Path)
+		fmt.Fprint(w, `{"access_token":"gcp-token","token_type":"Bearer","expires_in":3600}`) //nolint:errcheck
+	}))
+	defer server.Close()
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(server.URL, "http://"))
+
+	token, err := newGCPTokenSource(server.Client()).Token()
+	require.NoError(t, err)
+	assert.Equal(t, "gcp-token", token.AccessToken)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), token.Expiry, time.Minute)
+}
+
+func TestAzureTokenSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tenant/oauth2/v2.0/token", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client", r.PostForm.Get("client_id"))
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "federated-token", r.PostForm.Get("client_assertion"))
+		assert.Equal(t, azureKubernetesServerID+"/.default", r.PostForm.Get("scope"))
+		fmt.Fprint(w, `{"access_token":"azure-token","token_type":"Bearer","expires_in":3600}`) //nolint:errcheck
+	}))
+	defer server.Close()
+	t.Setenv("AZURE_CLIENT_ID", "client")
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", writeTokenFile(t, "federated-token"))
+	t.Setenv("AZURE_AUTHORITY_HOST", server.URL+"/")
+
+	ts, err := newAzureTokenSource(server.Client())
+	require.NoError(t, err)
+	token, err := ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "azure-token", token.AccessToken)
+}
+
+func TestAzureTokenSource_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "invalid_client", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	t.Setenv("AZURE_CLIENT_ID", "client")
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", writeTokenFile(t, "federated-token"))
+	t.Setenv("AZURE_AUTHORITY_HOST", server.URL)
+
+	ts, err := newAzureTokenSource(server.Client())
+	require.NoError(t, err)
+	_, err = ts.Token()
+	assert.EqualError(t, err, "token request failed with status 401: invalid_client\n")
+}
+
+func TestAWSTokenSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "AssumeRoleWithWebIdentity", r.PostForm.Get("Action"))
+		assert.Equal(t, "arn:aws:iam::123456789012:role/gateway", r.PostForm.Get("RoleArn"))
+		assert.Equal(t, "web-identity-token", r.PostForm.Get("WebIdentityToken"))
+		fmt.Fprint(w, `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>AKIDEXAMPLE</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>session/token+1</SessionToken>
+      <Expiration>2026-01-01T01:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`) //nolint:errcheck
+	}))
+	defer server.Close()
+	t.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/gateway")
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", writeTokenFile(t, "web-identity-token"))
+	t.Setenv("AWS_REGION", "eu-central-1")
+
+	ts, err := newAWSTokenSource(server.Client(), "prod", "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://sts.eu-central-1.amazonaws.com", ts.endpoint)
+	ts.endpoint = server.URL
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts.now = func() time.Time { return now }
+
+	token, err := ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(awsTokenLifetime), token.Expiry)
+
+	require.True(t, strings.HasPrefix(token.AccessToken, awsTokenPrefix))
+	presigned, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token.AccessToken, awsTokenPrefix))
+	require.NoError(t, err)
+	u, err := url.Parse(string(presigned))
+	require.NoError(t, err)
+
+	query := u.Query()
+	assert.Equal(t, "GetCallerIdentity", query.Get("Action"))
+	assert.Equal(t, "AKIDEXAMPLE/20260101/eu-central-1/sts/aws4_request", query.Get("X-Amz-Credential"))
+	assert.Equal(t, "20260101T000000Z", query.Get("X-Amz-Date"))
+	assert.Equal(t, "60", query.Get("X-Amz-Expires"))
+	assert.Equal(t, "host;x-k8s-aws-id", query.Get("X-Amz-SignedHeaders"))
+	assert.Equal(t, "session/token+1", query.Get("X-Amz-Security-Token"))
+	assert.Len(t, query.Get("X-Amz-Signature"), 64)
+	assert.Contains(t, string(presigned), "X-Amz-Security-Token=session%2Ftoken%2B1")
+}
+
+func TestAWSCanonicalQuery(t *testing.T) {
+	got := awsCanonicalQuery(map[string]string{"b": "a b", "a": "x~y/z"})
+	assert.Equal(t, "a=x~y%2Fz&b=a%20b", got)
+}
+
+func TestWrapTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer static", r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "static"})
+	httpClient := &http.Client{Transport: WrapTransport(ts)(http.DefaultTransport)}
+	resp, err := httpClient.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close() //nolint:errcheck
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}